@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// runExportProcessed writes the whole processed index to a local file (backups, migrating to
+// another bucket, project or backend with import-processed)
+func runExportProcessed(args []string) int {
+	fs := flag.NewFlagSet("export-processed", flag.ContinueOnError)
+	out := fs.String("out", "", `file to write ("-" writes stdout)`)
+	format := fs.String("format", "", "json or csv (default by the -out extension: .csv is CSV, anything else JSON)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *out == "" {
+		log.Printf("❌ Error: -out is required")
+		fs.Usage()
+		return exitUsage
+	}
+	if *format == "" {
+		*format = repository.IndexFormatFor(*out)
+	}
+	if err := repository.ValidateIndexFormat(*format); err != nil {
+		log.Printf("❌ Error: -format: %v", err)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	processedRepo, err := repository.NewProcessedArticleRepository()
+	if err != nil {
+		log.Printf("❌ Error creating processed article repository: %v", err)
+		return exitFailed
+	}
+	defer processedRepo.Close()
+
+	entries, err := processedRepo.ExportEntries(ctx)
+	if err != nil {
+		log.Printf("❌ Reading the processed index failed: %v", err)
+		return exitFailed
+	}
+
+	var output io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Printf("❌ Error creating %s: %v", *out, err)
+			return exitFailed
+		}
+		defer f.Close()
+		output = f
+	}
+	if err := repository.WriteIndexEntries(output, entries, *format); err != nil {
+		log.Printf("❌ Writing the export failed: %v", err)
+		return exitFailed
+	}
+
+	log.Printf("✅ Exported %d entries as %s", len(entries), *format)
+	return exitOK
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
+)
+
+// runImportProcessed restores a file written by export-processed (or a copied index object) into
+// the processed index, keeping the entries' processed dates
+func runImportProcessed(args []string) int {
+	fs := flag.NewFlagSet("import-processed", flag.ContinueOnError)
+	file := fs.String("file", "", `file written by export-processed ("-" reads stdin)`)
+	format := fs.String("format", "", "json or csv (default by the -file extension: .csv is CSV, anything else JSON)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *file == "" {
+		log.Printf("❌ Error: -file is required")
+		fs.Usage()
+		return exitUsage
+	}
+	if *format == "" {
+		*format = repository.IndexFormatFor(*file)
+	}
+	if err := repository.ValidateIndexFormat(*format); err != nil {
+		log.Printf("❌ Error: -format: %v", err)
+		return exitUsage
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Printf("❌ Error opening %s: %v", *file, err)
+			return exitUsage
+		}
+		defer f.Close()
+		input = f
+	}
+	entries, err := repository.ReadIndexEntries(input, *format)
+	if err != nil {
+		log.Printf("❌ Error reading %s: %v", *file, err)
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	processedRepo, err := repository.NewProcessedArticleRepository()
+	if err != nil {
+		log.Printf("❌ Error creating processed article repository: %v", err)
+		return exitFailed
+	}
+	defer processedRepo.Close()
+	auditRepo, err := repository.NewAuditRepository()
+	if err != nil {
+		log.Printf("❌ Error creating audit repository: %v", err)
+		return exitFailed
+	}
+	defer auditRepo.Close()
+
+	// Same audit action as mark-processed
+	if err := auditRepo.Record(ctx, repository.AuditEntry{
+		Time:         time.Now(),
+		Action:       handler.AuditActionProcessedImport,
+		ActorTokenID: "cli",
+		Params:       map[string]string{"source": "export", "count": strconv.Itoa(len(entries))},
+	}); err != nil {
+		log.Printf("❌ Error recording audit entry: %v", err)
+		return exitFailed
+	}
+
+	added, err := processedRepo.ImportEntries(ctx, entries)
+	if err != nil {
+		log.Printf("❌ Importing the processed index failed after %d entries: %v", added, err)
+		return exitFailed
+	}
+
+	log.Printf("✅ Imported %d of %d entries (the rest were already in the index)", added, len(entries))
+	return exitOK
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+// Exit codes, so that cron wrappers and CI jobs can tell a partial run from a total failure
+const (
+	exitOK      = 0
+	exitUsage   = 1 // Invalid command or flags; nothing ran
+	exitPartial = 2 // Some articles (or feeds) failed
+	exitFailed  = 3 // Nothing succeeded
+)
+
+// cli runs one-off operations with the same wiring (config, repositories) as the server
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	var code int
+	switch os.Args[1] {
+	case "sitemap":
+		code = runSitemap(os.Args[2:])
+	case "mark-processed":
+		code = runMarkProcessed(os.Args[2:])
+	case "prune-processed":
+		code = runPruneProcessed(os.Args[2:])
+	case "export-processed":
+		code = runExportProcessed(os.Args[2:])
+	case "import-processed":
+		code = runImportProcessed(os.Args[2:])
+	case "migrate-cache":
+		code = runMigrateCache(os.Args[2:])
+	case "regenerate-summaries":
+		code = runRegenerateSummaries(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		log.Printf("❌ Error: unknown command %q", os.Args[1])
+		usage()
+		code = exitUsage
+	}
+
+	os.Exit(code)
+}
+
+// exitCode maps a run report's status to the exit code
+func exitCode(report *article.RunReport) int {
+	switch report.Status() {
+	case article.RunStatusOK:
+		return exitOK
+	case article.RunStatusPartial:
+		return exitPartial
+	default:
+		return exitFailed
+	}
+}
+
+// asOfFlag registers -as-of, which replays a command as of an earlier time (see withAsOf)
+func asOfFlag(fs *flag.FlagSet) *string {
+	return fs.String("as-of", "", "run as if it were this time: YYYY-MM-DD (local midnight) or RFC 3339 (default now)")
+}
+
+// withAsOf returns a context whose clock starts at the -as-of time, so that age filters and
+// retention cutoffs are computed as of then (the real clock when value is empty)
+func withAsOf(ctx context.Context, value string) (context.Context, error) {
+	if value == "" {
+		return ctx, nil
+	}
+	asOf, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		if asOf, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, fmt.Errorf("-as-of must be YYYY-MM-DD or RFC 3339: %q", value)
+		}
+	}
+	log.Printf("🕰️ Running as of %s", asOf.Format(time.RFC3339))
+	return repository.WithClock(ctx, repository.AsOfClock(asOf)), nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: cli <command> [flags]
+
+Commands:
+  sitemap          Summarize recent URLs from a site's sitemap.xml as a one-off batch
+  mark-processed   Bulk-mark URLs as processed (e.g. history imported from a previous deployment)
+  prune-processed  Remove processed index entries older than a retention window
+  export-processed Write the whole processed index to a JSON or CSV file (backups, migrations)
+  import-processed Restore an exported processed index, keeping the entries' processed dates
+  migrate-cache    Copy the processed index between backends (-from gcs -to firestore) before switching CACHE_TYPE
+  regenerate-summaries
+                   Re-summarize archived articles of the last -days made with older prompt templates (not posted unless -post)
+
+Run "cli <command> -h" for command flags. sitemap, mark-processed and prune-processed accept
+-as-of to run as if it were an earlier time (e.g. -as-of 2024-06-04 replays what a sitemap run would have selected that day).
+sitemap -dry-run (like DRY_RUN=true) logs the summaries it would post and does not mark the URLs as processed.
+sitemap -reprocess summarizes already processed URLs again (e.g. after a prompt fix) and overwrites their index entries.
+
+Exit codes: 0 all ok, 1 invalid usage, 2 partial failure, 3 total failure.`)
+}
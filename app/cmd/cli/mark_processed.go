@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
+)
+
+// runMarkProcessed bulk-marks URLs as processed so a migration does not replay old articles into Slack
+func runMarkProcessed(args []string) int {
+	fs := flag.NewFlagSet("mark-processed", flag.ContinueOnError)
+	file := fs.String("file", "", `file with one URL per line ("-" reads stdin; blank lines and # comments are skipped)`)
+	source := fs.String("source", handler.DefaultImportSource, "source recorded on the imported entries")
+	asOf := asOfFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *file == "" {
+		log.Printf("❌ Error: -file is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Printf("❌ Error opening %s: %v", *file, err)
+			return exitUsage
+		}
+		defer f.Close()
+		input = f
+	}
+
+	var items []repository.Item
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		items = append(items, repository.Item{Link: line, Source: *source})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("❌ Error reading URLs: %v", err)
+		return exitUsage
+	}
+	if len(items) == 0 {
+		log.Printf("❌ Error: no URLs in %s", *file)
+		return exitUsage
+	}
+
+	// -as-of backdates the processed date of the imported entries (e.g. to when they were posted)
+	ctx, err := withAsOf(context.Background(), *asOf)
+	if err != nil {
+		log.Printf("❌ Error: %v", err)
+		return exitUsage
+	}
+	processedRepo, err := repository.NewProcessedArticleRepository()
+	if err != nil {
+		log.Printf("❌ Error creating processed article repository: %v", err)
+		return exitFailed
+	}
+	defer processedRepo.Close()
+	auditRepo, err := repository.NewAuditRepository()
+	if err != nil {
+		log.Printf("❌ Error creating audit repository: %v", err)
+		return exitFailed
+	}
+	defer auditRepo.Close()
+
+	// Same audit trail as POST /admin/processed
+	if err := auditRepo.Record(ctx, repository.AuditEntry{
+		Time:         time.Now(),
+		Action:       handler.AuditActionProcessedImport,
+		ActorTokenID: "cli",
+		Params:       map[string]string{"source": *source, "count": strconv.Itoa(len(items))},
+	}); err != nil {
+		log.Printf("❌ Error recording audit entry: %v", err)
+		return exitFailed
+	}
+
+	added, err := processedRepo.MarkManyAsProcessed(ctx, items)
+	if err != nil {
+		log.Printf("❌ Marking URLs as processed failed: %v", err)
+		return exitFailed
+	}
+
+	log.Printf("✅ Marked %d of %d URLs as processed (the rest were already in the index)", added, len(items))
+	return exitOK
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
+)
+
+// runMigrateCache copies the processed index from one backend to another before switching
+// CACHE_TYPE, so that the new backend does not notify the history again
+func runMigrateCache(args []string) int {
+	fs := flag.NewFlagSet("migrate-cache", flag.ContinueOnError)
+	from := fs.String("from", "", "source backend: gcs (storage), firestore, sqlite, postgres or dynamodb")
+	to := fs.String("to", "", "target backend: gcs (storage), firestore, sqlite, postgres or dynamodb")
+	batch := fs.Int("batch", 500, "entries copied (and reported) at a time")
+	dryRun := fs.Bool("dry-run", false, "only count the entries that would be copied")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	fromType, toType := cacheTypeOf(*from), cacheTypeOf(*to)
+	if *from == "" || *to == "" {
+		log.Printf("❌ Error: -from and -to are required")
+		fs.Usage()
+		return exitUsage
+	}
+	if fromType == toType {
+		log.Printf("❌ Error: -from and -to are the same backend")
+		return exitUsage
+	}
+	if *batch <= 0 {
+		log.Printf("❌ Error: -batch must be positive")
+		return exitUsage
+	}
+
+	ctx := context.Background()
+	source, err := repository.NewProcessedArticleRepositoryFor(fromType)
+	if err != nil {
+		log.Printf("❌ Error opening source backend %s: %v", *from, err)
+		return exitUsage
+	}
+	defer source.Close()
+	target, err := repository.NewProcessedArticleRepositoryFor(toType)
+	if err != nil {
+		log.Printf("❌ Error opening target backend %s: %v", *to, err)
+		return exitUsage
+	}
+	defer target.Close()
+
+	if !*dryRun {
+		auditRepo, err := repository.NewAuditRepository()
+		if err != nil {
+			log.Printf("❌ Error creating audit repository: %v", err)
+			return exitFailed
+		}
+		defer auditRepo.Close()
+		// Same audit action as mark-processed and import-processed
+		if err := auditRepo.Record(ctx, repository.AuditEntry{
+			Time:         time.Now(),
+			Action:       handler.AuditActionProcessedImport,
+			ActorTokenID: "cli",
+			Params:       map[string]string{"source": fromType, "target": toType},
+		}); err != nil {
+			log.Printf("❌ Error recording audit entry: %v", err)
+			return exitFailed
+		}
+	}
+
+	migration := &repository.CacheMigration{
+		From:      source,
+		To:        target,
+		BatchSize: *batch,
+		DryRun:    *dryRun,
+		Progress: func(p repository.CacheMigrationProgress) {
+			log.Printf("⏳ %d/%d entries (%d%%), %d new", p.Done, p.Total, p.Done*100/p.Total, p.Copied)
+		},
+	}
+	progress, err := migration.Run(ctx)
+	if err != nil {
+		log.Printf("❌ Migration failed after %d/%d entries (%d copied; run it again to resume): %v",
+			progress.Done, progress.Total, progress.Copied, err)
+		if progress.Copied > 0 {
+			return exitPartial
+		}
+		return exitFailed
+	}
+
+	if *dryRun {
+		log.Printf("✅ Dry run: %d of %d entries would be copied from %s to %s", progress.Copied, progress.Total, fromType, toType)
+		return exitOK
+	}
+	log.Printf("✅ Copied %d of %d entries from %s to %s (the rest were already there)", progress.Copied, progress.Total, fromType, toType)
+	return exitOK
+}
+
+// cacheTypeOf maps a -from/-to backend to its CACHE_TYPE; gcs names the storage backend (which
+// also covers local storage)
+func cacheTypeOf(backend string) string {
+	switch backend {
+	case "", "gcs":
+		return repository.CacheTypeStorage
+	}
+	return backend
+}
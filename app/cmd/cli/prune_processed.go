@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
+)
+
+// runPruneProcessed drops processed index entries older than the retention window
+func runPruneProcessed(args []string) int {
+	fs := flag.NewFlagSet("prune-processed", flag.ContinueOnError)
+	defaultDays, _ := strconv.Atoi(os.Getenv("PROCESSED_RETENTION_DAYS"))
+	days := fs.Int("days", defaultDays, "remove entries processed more than this many days ago (default PROCESSED_RETENTION_DAYS)")
+	asOf := asOfFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *days <= 0 {
+		log.Printf("❌ Error: -days must be a positive number of days")
+		fs.Usage()
+		return exitUsage
+	}
+
+	ctx, err := withAsOf(context.Background(), *asOf)
+	if err != nil {
+		log.Printf("❌ Error: %v", err)
+		return exitUsage
+	}
+	processedRepo, err := repository.NewProcessedArticleRepository()
+	if err != nil {
+		log.Printf("❌ Error creating processed article repository: %v", err)
+		return exitFailed
+	}
+	defer processedRepo.Close()
+	auditRepo, err := repository.NewAuditRepository()
+	if err != nil {
+		log.Printf("❌ Error creating audit repository: %v", err)
+		return exitFailed
+	}
+	defer auditRepo.Close()
+
+	// Same audit trail as POST /admin/processed/prune
+	if err := auditRepo.Record(ctx, repository.AuditEntry{
+		Time:         time.Now(),
+		Action:       handler.AuditActionProcessedPrune,
+		ActorTokenID: "cli",
+		Params:       map[string]string{"older_than_days": strconv.Itoa(*days)},
+	}); err != nil {
+		log.Printf("❌ Error recording audit entry: %v", err)
+		return exitFailed
+	}
+
+	cutoff := repository.Now(ctx).AddDate(0, 0, -*days)
+	removed, err := processedRepo.Prune(ctx, cutoff)
+	if err != nil {
+		log.Printf("❌ Pruning the processed index failed: %v", err)
+		return exitFailed
+	}
+
+	log.Printf("✅ Removed %d entries processed before %s", removed, cutoff.Format(time.DateOnly))
+	return exitOK
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/pep299/article-summarizer-v3/internal/application"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+// runRegenerateSummaries re-summarizes recently archived articles with the current prompt templates
+func runRegenerateSummaries(args []string) int {
+	fs := flag.NewFlagSet("regenerate-summaries", flag.ContinueOnError)
+	days := fs.Int("days", 7, "regenerate summaries archived within this many days")
+	all := fs.Bool("all", false, "also regenerate summaries already made with the current prompt templates")
+	limit := fs.Int("limit", 0, "maximum number of summaries to regenerate (0 means no limit)")
+	post := fs.Bool("post", false, "post the regenerated summaries to their feed's notifier (default: only archive them)")
+	dryRun := fs.Bool("dry-run", false, "only list the summaries that would be regenerated")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *days <= 0 {
+		log.Printf("❌ Error: -days must be a positive number of days")
+		fs.Usage()
+		return exitUsage
+	}
+
+	app, err := application.New()
+	if err != nil {
+		log.Printf("❌ Error creating application: %v", err)
+		return exitFailed
+	}
+	defer app.Close()
+	if app.SummaryRegenerator == nil {
+		log.Printf("❌ Error: the summary archive is disabled (set SUMMARY_ARCHIVE_ENABLED=true)")
+		return exitUsage
+	}
+
+	result, err := app.SummaryRegenerator.Run(context.Background(), service.RegenerateOptions{
+		Days:   *days,
+		All:    *all,
+		Limit:  *limit,
+		Post:   *post,
+		DryRun: *dryRun,
+	})
+	if *dryRun && err == nil {
+		log.Printf("✅ Dry run: %d of %d archived summaries would be regenerated", result.Outdated, result.Checked)
+		return exitOK
+	}
+	if err != nil {
+		log.Printf("❌ Regeneration failed for %d of %d summaries: %v", result.Failed, result.Outdated, err)
+		if result.Regenerated > 0 {
+			return exitPartial
+		}
+		return exitFailed
+	}
+
+	log.Printf("✅ Regenerated %d of %d archived summaries (%d posted)", result.Regenerated, result.Checked, result.Posted)
+	return exitOK
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/application"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+// runSitemap onboards a site without a feed: enumerate sitemap URLs, filter, summarize and post
+func runSitemap(args []string) int {
+	fs := flag.NewFlagSet("sitemap", flag.ContinueOnError)
+	sitemapURL := fs.String("url", "", "sitemap.xml URL (sitemap index is followed one level)")
+	pattern := fs.String("pattern", "", "regular expression URLs must match (e.g. /blog/)")
+	since := fs.Duration("since", 30*24*time.Hour, "only URLs whose lastmod is within this duration (0 disables)")
+	limit := fs.Int("limit", 20, "maximum number of URLs to process (0 means no limit)")
+	markdown := fs.String("markdown", "", "write Markdown notes to this directory or gs:// / s3:// prefix instead of NOTIFIER_SITEMAP")
+	jsonOutput := fs.Bool("json", false, "print the run report (status and per-feed counts) as JSON to stdout")
+	dryRun := fs.Bool("dry-run", false, "summarize, but log the notifications instead of posting them and do not mark URLs as processed (DRY_RUN=true)")
+	reprocess := fs.Bool("reprocess", false, "summarize and post URLs again even if already processed, overwriting their index entries (narrow with -pattern)")
+	asOf := asOfFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if *sitemapURL == "" {
+		log.Printf("❌ Error: -url is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	ctx, err := withAsOf(context.Background(), *asOf)
+	if err != nil {
+		log.Printf("❌ Error: %v", err)
+		return exitUsage
+	}
+
+	opts := article.SitemapOptions{
+		SitemapURL: *sitemapURL,
+		Limit:      *limit,
+	}
+	if *pattern != "" {
+		re, err := regexp.Compile(*pattern)
+		if err != nil {
+			log.Printf("❌ Error: invalid -pattern: %v", err)
+			return exitUsage
+		}
+		opts.Pattern = re
+	}
+	if *since > 0 {
+		opts.Since = repository.Now(ctx).Add(-*since)
+	}
+
+	cfg, err := application.LoadWith(func(cfg *application.Config) {
+		if *markdown != "" {
+			// Route the sitemap feed to the Markdown notifier
+			cfg.Notifiers["sitemap"] = "markdown"
+			cfg.MarkdownOutput = *markdown
+		}
+		if *dryRun {
+			cfg.DryRun = true
+		}
+	})
+	if err != nil {
+		log.Printf("❌ Error loading config: %v", err)
+		return exitFailed
+	}
+	app, err := application.NewWithConfig(cfg)
+	if err != nil {
+		log.Printf("❌ Error creating application: %v", err)
+		return exitFailed
+	}
+	defer app.Close()
+
+	if *reprocess {
+		ctx = article.WithReprocess(ctx)
+	}
+	ctx, report := article.WithRunReport(ctx)
+	var processed int
+	err = article.RunFeed(ctx, "sitemap", func(ctx context.Context) error {
+		var err error
+		processed, err = app.SitemapProcessor.Process(ctx, opts)
+		return err
+	})
+
+	if *jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			log.Printf("❌ Error writing run report: %v", err)
+		}
+	}
+	if err != nil {
+		log.Printf("❌ Sitemap processing failed after %d articles: %v", processed, err)
+		return exitCode(report)
+	}
+
+	log.Printf("✅ Sitemap processing completed: %d articles", processed)
+	return exitOK
+}
@@ -1,21 +1,92 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
 
 	_ "github.com/pep299/article-summarizer-v3" // cloud_function.goのinit()を実行したいので
+	"github.com/pep299/article-summarizer-v3/internal/transport/server"
 )
 
+// shutdownTimeout bounds how long in-flight runs get to finish, and flush what they buffered, on SIGTERM
+const shutdownTimeout = 25 * time.Second
+
 func main() {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	// セルフホスト時はTLSを終端し、クライアント証明書を検証する (AUTH_MODE=mtls)
+	if certFile := os.Getenv("TLS_CERT_FILE"); certFile != "" {
+		if err := serveTLS(port, certFile, os.Getenv("TLS_KEY_FILE"), os.Getenv("TLS_CLIENT_CA_FILE")); err != nil {
+			log.Fatalf("serveTLS: %v\n", err)
+		}
+		return
+	}
+
 	if err := funcframework.Start(port); err != nil {
 		log.Fatalf("funcframework.Start: %v\n", err)
 	}
 }
+
+// serveTLS serves the application over TLS. Client certificates signed by the CA are verified when presented;
+// they are optional at the handshake so health checks work, and AUTH_MODE=mtls rejects requests without one.
+func serveTLS(port, certFile, keyFile, clientCAFile string) error {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return fmt.Errorf("reading client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	srv := &http.Server{
+		Addr:      ":" + port,
+		Handler:   http.HandlerFunc(server.HandleRequest),
+		TLSConfig: tlsConfig,
+	}
+	log.Printf("Serving TLS on :%s (client CA: %t)", port, clientCAFile != "")
+
+	// Stop accepting requests on SIGTERM/SIGINT and let in-flight runs finish, so their processed
+	// marks and digests are flushed instead of being left to the write-ahead log
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServeTLS(certFile, keyFile)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	log.Printf("Shutting down, waiting up to %s for in-flight requests", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -1,10 +1,16 @@
 package application
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
 
 	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
 	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
 	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
 	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
 )
@@ -13,11 +19,44 @@ import (
 type Application struct {
 	Config             *Config
 	WebhookHandler     *handler.Webhook
+	WebhookStream      *handler.WebhookStream
+	SimpleHook         *handler.SimpleHook
 	XHandler           *handler.X
 	XQuoteChainHandler *handler.XQuoteChain
 	HatenaHandler      *handler.HatenaHandler
 	RedditHandler      *handler.RedditHandler
 	LobstersHandler    *handler.LobstersHandler
+	HackerNewsHandler  *handler.HackerNewsHandler
+	ArxivHandler       *handler.ArxivHandler
+	YouTubeHandler     *handler.YouTubeHandler // nil unless YOUTUBE_CHANNEL_IDS is set
+	DevToHandler       *handler.CommunityHandler
+	QiitaHandler       *handler.CommunityHandler
+	ZennHandler        *handler.CommunityHandler
+	ProductHuntHandler *handler.CommunityHandler
+	XAccountsHandler   *handler.XAccountsHandler // nil unless X_ACCOUNTS is set
+	PodcastHandler     *handler.PodcastHandler   // nil unless PODCAST_FEEDS is set
+	OPMLHandler        *handler.OPMLHandler      // nil unless OPML_SOURCE is set
+	FeedsHandler       *handler.FeedsHandler     // nil unless GENERIC_FEEDS is set
+	BacklogHandler     *handler.BacklogHandler
+	HistoryHandler     *handler.History
+	ProvidersHandler   *handler.Providers  // nil on read-only instances, which never summarize
+	FeedHealthHandler  *handler.FeedHealth // nil on read-only and simulation instances
+	AdminProcessed     *handler.AdminProcessed
+	AdminImport        *handler.AdminProcessedImport
+	AdminPrune         *handler.AdminProcessedPrune
+	AdminAudit         *handler.AdminAudit
+	AdminUsage         *handler.AdminUsage
+	AdminStats         *handler.AdminProcessedStats
+	SlackCommand       *handler.SlackCommand     // nil unless SLACK_SIGNING_SECRET is set
+	SlackInteraction   *handler.SlackInteraction // nil unless SLACK_ACTIONS_ENABLED is set
+	SummaryFeedHandler *handler.SummaryFeed
+	SummaryExport      *handler.SummaryExport
+	SummaryArchive     *handler.SummaryArchive   // nil unless SUMMARY_ARCHIVE_ENABLED is set
+	SitemapProcessor   *article.SitemapProcessor // One-off onboarding batches (CLI)
+	// Re-summarizes archived articles after prompt upgrades (CLI); nil unless SUMMARY_ARCHIVE_ENABLED is set
+	SummaryRegenerator *service.SummaryRegenerator
+	leader             *service.LeaderElector // nil unless LEADER_ELECTION_ENABLED is set
+	fastLane           *limiter.FastLane      // nil unless BREAKING_FEEDS or BREAKING_KEYWORDS is set
 	cleanup            func() error
 }
 
@@ -28,36 +67,398 @@ func New() (*Application, error) {
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
+	return NewWithConfig(cfg)
+}
+
+// NewWithConfig creates an application instance from a loaded configuration (see LoadWith)
+func NewWithConfig(cfg *Config) (*Application, error) {
+	switch cfg.ServiceMode {
+	case ServiceModeReadOnly:
+		return newReadOnly(cfg)
+	case ServiceModeSimulation:
+		return newSimulation(cfg)
+	}
 
 	// Create repositories (now with direct implementations)
 	rssRepo := repository.NewRSSRepository()
-	geminiRepo := repository.NewGeminiRepository(cfg.GeminiAPIKey, cfg.GeminiModel, cfg.GeminiBaseURL)
+	extractionRules, err := repository.ParseExtractionRules(cfg.ExtractionRules)
+	if err != nil {
+		return nil, fmt.Errorf("parsing extraction rules: %w", err)
+	}
+	geminiRepo := repository.NewGeminiRepositoryWithExperiment(cfg.GeminiAPIKey, cfg.GeminiModel, cfg.GeminiBaseURL, nil, extractionRules)
+	redditGeminiRepo, err := newFeedGeminiRepository(cfg, cfg.PromptVariantReddit, geminiRepo, extractionRules)
+	if err != nil {
+		return nil, fmt.Errorf("creating reddit gemini repository: %w", err)
+	}
+	hatenaGeminiRepo, err := newFeedGeminiRepository(cfg, cfg.PromptVariantHatena, geminiRepo, extractionRules)
+	if err != nil {
+		return nil, fmt.Errorf("creating hatena gemini repository: %w", err)
+	}
+	lobstersGeminiRepo, err := newFeedGeminiRepository(cfg, cfg.PromptVariantLobsters, geminiRepo, extractionRules)
+	if err != nil {
+		return nil, fmt.Errorf("creating lobsters gemini repository: %w", err)
+	}
+	hackerNewsGeminiRepo, err := newFeedGeminiRepository(cfg, cfg.PromptVariantHackerNews, geminiRepo, extractionRules)
+	if err != nil {
+		return nil, fmt.Errorf("creating hackernews gemini repository: %w", err)
+	}
 	processedRepo, err := repository.NewProcessedArticleRepository()
 	if err != nil {
 		return nil, fmt.Errorf("creating processed article repository: %w", err)
 	}
-	redditSlackRepo := repository.NewSlackRepository(cfg.SlackBotToken, cfg.SlackChannelReddit, cfg.SlackBaseURL)
-	hatenaSlackRepo := repository.NewSlackRepository(cfg.SlackBotToken, cfg.SlackChannelHatena, cfg.SlackBaseURL)
-	lobstersSlackRepo := repository.NewSlackRepository(cfg.SlackBotToken, cfg.SlackChannelLobsters, cfg.SlackBaseURL)
-	webhookSlackRepo := repository.NewSlackRepository(cfg.SlackBotToken, cfg.WebhookSlackChannel, cfg.SlackBaseURL)
+	backlogRepo, err := repository.NewBacklogRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating backlog repository: %w", err)
+	}
+	auditRepo, err := repository.NewAuditRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating audit repository: %w", err)
+	}
+	usageRepo, err := repository.NewUsageRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating usage repository: %w", err)
+	}
+	feedStatsRepo, err := repository.NewFeedStatsRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating feed stats repository: %w", err)
+	}
+	// Plain feeds defined in GENERIC_FEEDS (validated in Config), registered by name below
+	genericFeeds, err := rss.ParseGenericFeedSpecs(cfg.GenericFeeds)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generic feeds: %w", err)
+	}
+	// Persistently failing feeds are escalated to their owners in the ops channel
+	feedEscalation := article.NewFeedEscalation(feedStatsRepo, cfg.feedOwners(genericFeeds), repository.NewSlackOpsThread(cfg.SlackBotToken, cfg.OpsThreadChannel, cfg.SlackBaseURL), cfg.FeedEscalationRuns)
+	feedStatsRepo = feedEscalation
+	// A dry run reads the production state but leaves it as it was (notifiers are replaced in newFeedNotifier)
+	if cfg.DryRun {
+		processedRepo = repository.NewDryRunProcessedRepository(processedRepo)
+		backlogRepo = repository.NewDryRunBacklogRepository(backlogRepo)
+		feedStatsRepo = repository.NewDryRunFeedStatsRepository(feedStatsRepo)
+	}
+	// Feeds with a dedup TTL summarize articles again once their processed mark is old enough
+	if ttls := cfg.dedupTTLs(genericFeeds); len(ttls) > 0 {
+		processedRepo = repository.NewDedupTTLRepository(processedRepo, ttls)
+	}
+	// Email digests record their queued notifications in a write-ahead log when WAL_ENABLED is set
+	var outbox repository.Storage
+	if cfg.usesNotifier("email") {
+		if outbox, err = repository.NewWALStorage(); err != nil {
+			return nil, fmt.Errorf("creating email outbox storage: %w", err)
+		}
+	}
+	// Shared notifiers serve every feed that selects their kind:
+	// a combined email digest, and the Markdown vault (one directory for all feeds)
+	shared := make(map[string]repository.Notifier)
+	if cfg.EmailDigestMode == "combined" {
+		shared["email"] = repository.NewEmailRepositoryWithOutbox(emailConfig(cfg), "", repository.NewWriteAheadLog(outbox, "email"))
+	}
+	if cfg.usesNotifier("markdown") {
+		markdownNotifier, err := repository.NewMarkdownRepository(cfg.MarkdownOutput)
+		if err != nil {
+			return nil, fmt.Errorf("creating markdown notifier: %w", err)
+		}
+		shared["markdown"] = markdownNotifier
+	}
+	summaryFeedRepo, err := repository.NewSummaryFeedRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating summary feed repository: %w", err)
+	}
+	// Slack channels get an hourly posting budget when SLACK_CHANNEL_MAX_POSTS_PER_HOUR is set
+	var budgets *repository.ChannelBudgets
+	if cfg.SlackMaxPostsPerHour > 0 {
+		budgetStore, err := repository.NewStorage()
+		if err != nil {
+			return nil, fmt.Errorf("creating channel budget storage: %w", err)
+		}
+		budgets = repository.NewChannelBudgets(budgetStore, cfg.SlackMaxPostsPerHour, cfg.SlackBudgetOverflow)
+	}
+	// Mirrors receive every feed's summaries in addition to the feed's own notifier
+	var mirrors []repository.Notifier
+	if cfg.SummaryFeedEnabled {
+		mirrors = append(mirrors, summaryFeedRepo)
+	}
+	var summaryArchiveRepo repository.SummaryArchiveRepository
+	if cfg.SummaryArchiveEnabled {
+		if summaryArchiveRepo, err = repository.NewSummaryArchiveRepository(); err != nil {
+			return nil, fmt.Errorf("creating summary archive repository: %w", err)
+		}
+		mirrors = append(mirrors, summaryArchiveRepo)
+	}
+	if cfg.NotionDatabaseID != "" {
+		mirrors = append(mirrors, repository.NewNotionRepository(cfg.NotionToken, cfg.NotionDatabaseID, cfg.NotionBaseURL))
+	}
+	redditNotifier := newFeedNotifier(cfg, "reddit", cfg.SlackChannelReddit, shared, outbox, budgets, mirrors)
+	hatenaNotifier := newFeedNotifier(cfg, "hatena", cfg.SlackChannelHatena, shared, outbox, budgets, mirrors)
+	lobstersNotifier := newFeedNotifier(cfg, "lobsters", cfg.SlackChannelLobsters, shared, outbox, budgets, mirrors)
+	hackerNewsNotifier := newFeedNotifier(cfg, "hackernews", cfg.SlackChannelHackerNews, shared, outbox, budgets, mirrors)
+	arxivNotifier := newFeedNotifier(cfg, "arxiv", cfg.SlackChannelArxiv, shared, outbox, budgets, mirrors)
+	youtubeNotifier := newFeedNotifier(cfg, "youtube", cfg.SlackChannelYouTube, shared, outbox, budgets, mirrors)
+	devToNotifier := newFeedNotifier(cfg, "devto", cfg.SlackChannelDevTo, shared, outbox, budgets, mirrors)
+	qiitaNotifier := newFeedNotifier(cfg, "qiita", cfg.SlackChannelQiita, shared, outbox, budgets, mirrors)
+	zennNotifier := newFeedNotifier(cfg, "zenn", cfg.SlackChannelZenn, shared, outbox, budgets, mirrors)
+	productHuntNotifier := newFeedNotifier(cfg, "producthunt", cfg.SlackChannelProductHunt, shared, outbox, budgets, mirrors)
+	xNotifier := newFeedNotifier(cfg, "x", cfg.SlackChannelX, shared, outbox, budgets, mirrors)
+	podcastNotifier := newFeedNotifier(cfg, "podcast", cfg.SlackChannelPodcast, shared, outbox, budgets, mirrors)
+	webhookNotifier := newFeedNotifier(cfg, repository.SourceOnDemand, cfg.WebhookSlackChannel, shared, outbox, budgets, mirrors)
+	sitemapNotifier := newFeedNotifier(cfg, "sitemap", cfg.SlackChannel, shared, outbox, budgets, mirrors)
+	opmlNotifier := newFeedNotifier(cfg, "opml", cfg.SlackChannel, shared, outbox, budgets, mirrors)
+	// Summaries are delivered with a translation per SUMMARY_TRANSLATION_<FEED> (inside the headline rewrite, which reads the original summary)
+	redditNotifier = withSummaryTranslation(cfg, "reddit", redditNotifier, geminiRepo)
+	hatenaNotifier = withSummaryTranslation(cfg, "hatena", hatenaNotifier, geminiRepo)
+	lobstersNotifier = withSummaryTranslation(cfg, "lobsters", lobstersNotifier, geminiRepo)
+	hackerNewsNotifier = withSummaryTranslation(cfg, "hackernews", hackerNewsNotifier, geminiRepo)
+	arxivNotifier = withSummaryTranslation(cfg, "arxiv", arxivNotifier, geminiRepo)
+	youtubeNotifier = withSummaryTranslation(cfg, "youtube", youtubeNotifier, geminiRepo)
+	devToNotifier = withSummaryTranslation(cfg, "devto", devToNotifier, geminiRepo)
+	qiitaNotifier = withSummaryTranslation(cfg, "qiita", qiitaNotifier, geminiRepo)
+	zennNotifier = withSummaryTranslation(cfg, "zenn", zennNotifier, geminiRepo)
+	productHuntNotifier = withSummaryTranslation(cfg, "producthunt", productHuntNotifier, geminiRepo)
+	xNotifier = withSummaryTranslation(cfg, "x", xNotifier, geminiRepo)
+	podcastNotifier = withSummaryTranslation(cfg, "podcast", podcastNotifier, geminiRepo)
+	webhookNotifier = withSummaryTranslation(cfg, repository.SourceOnDemand, webhookNotifier, geminiRepo)
+	sitemapNotifier = withSummaryTranslation(cfg, "sitemap", sitemapNotifier, geminiRepo)
+	opmlNotifier = withSummaryTranslation(cfg, "opml", opmlNotifier, geminiRepo)
+	// Clickbait-prone feeds get LLM-rewritten headlines
+	for _, source := range cfg.HeadlineRewriteSources {
+		switch source {
+		case "reddit":
+			redditNotifier = article.NewHeadlineRewriter(redditNotifier, geminiRepo)
+		case "hatena":
+			hatenaNotifier = article.NewHeadlineRewriter(hatenaNotifier, geminiRepo)
+		case "lobsters":
+			lobstersNotifier = article.NewHeadlineRewriter(lobstersNotifier, geminiRepo)
+		case "hackernews":
+			hackerNewsNotifier = article.NewHeadlineRewriter(hackerNewsNotifier, geminiRepo)
+		case "sitemap":
+			sitemapNotifier = article.NewHeadlineRewriter(sitemapNotifier, geminiRepo)
+		}
+	}
+	// Feeds being tuned get a per-run ops thread (outermost so processors can find it); not in a dry run, which posts nothing
+	for _, feed := range cfg.OpsThreadFeeds {
+		if cfg.DryRun {
+			break
+		}
+		opsThread := repository.NewSlackOpsThread(cfg.SlackBotToken, cfg.OpsThreadChannel, cfg.SlackBaseURL)
+		switch feed {
+		case "reddit":
+			redditNotifier = article.NewOpsNotifier(redditNotifier, opsThread)
+		case "hatena":
+			hatenaNotifier = article.NewOpsNotifier(hatenaNotifier, opsThread)
+		case "lobsters":
+			lobstersNotifier = article.NewOpsNotifier(lobstersNotifier, opsThread)
+		case "hackernews":
+			hackerNewsNotifier = article.NewOpsNotifier(hackerNewsNotifier, opsThread)
+		}
+	}
 
 	// Create services (business logic) - use production limiter by default
-	articleLimiter := limiter.NewProductionArticleLimiter()
-	urlService := service.NewURL(geminiRepo, webhookSlackRepo)
+	var articleLimiter limiter.ArticleLimiter = limiter.NewProductionArticleLimiter()
+	// Bursts of new items are split into shards run one SHARD_INTERVAL apart
+	var shardScheduler *service.InternalShardScheduler
+	if cfg.ShardSize > 0 {
+		var scheduler limiter.ShardScheduler
+		if cfg.CloudTasksQueue != "" {
+			scheduler = repository.NewCloudTasksScheduler(cfg.CloudTasksQueue, cfg.shardTargetURL, cfg.WebhookAuthToken)
+		} else {
+			shardScheduler = service.NewInternalShardScheduler()
+			scheduler = shardScheduler
+		}
+		articleLimiter = limiter.NewSharder(articleLimiter, cfg.ShardSize, cfg.ShardInterval, scheduler)
+	}
+	// Breaking news jumps the queue, the active windows and email batching
+	var fastLane *limiter.FastLane
+	if len(cfg.BreakingFeeds) > 0 || len(cfg.BreakingKeywords) > 0 {
+		fastLane = limiter.NewFastLane(articleLimiter, cfg.BreakingFeeds, cfg.BreakingKeywords, cfg.BreakingMaxAge)
+		articleLimiter = fastLane
+	}
+	// Shared across feeds since they draw on the same Gemini quota
+	articleConcurrency := limiter.NewConcurrencyController(cfg.ArticleConcurrencyMin, cfg.ArticleConcurrencyMax, cfg.ArticleLatencyTarget)
+	urlService := service.NewURL(geminiRepo, webhookNotifier)
+	usageService := service.NewUsage(usageRepo, cfg.OnDemandDailyQuota)
 
 	// Create X repository
 	xRepo := repository.NewXClient()
 
 	// Create handlers (HTTP layer)
-	webhookHandler := handler.NewWebhook(urlService)
+	webhookHandler := handler.NewWebhook(urlService, usageService, handler.WebhookFields{URL: cfg.WebhookURLField, User: cfg.WebhookUserField})
+	webhookStream := handler.NewWebhookStream(service.NewOnDemandQueue(urlService, cfg.OnDemandStreamWorkers), usageService)
+	simpleHook := handler.NewSimpleHook(urlService, usageService)
+	historyHandler := handler.NewHistory(service.NewHistory(processedRepo))
+	summaryFeedHandler := handler.NewSummaryFeed(summaryFeedRepo)
+	summaryExport := handler.NewSummaryExport(service.NewExport(summaryFeedRepo))
+	var summaryArchive *handler.SummaryArchive
+	if summaryArchiveRepo != nil {
+		summaryArchive = handler.NewSummaryArchive(summaryArchiveRepo)
+	}
+	adminProcessedHandler := handler.NewAdminProcessed(processedRepo, auditRepo)
+	adminImportHandler := handler.NewAdminProcessedImport(processedRepo, auditRepo)
+	adminPruneHandler := handler.NewAdminProcessedPrune(processedRepo, auditRepo, cfg.ProcessedRetentionDays)
+	adminAuditHandler := handler.NewAdminAudit(auditRepo)
+	adminUsageHandler := handler.NewAdminUsage(usageService)
+	var slackCommandHandler *handler.SlackCommand
+	if cfg.SlackSigningSecret != "" {
+		slackCommandHandler = handler.NewSlackCommand(cfg.SlackSigningSecret, usageService)
+	}
+	var slackInteractionHandler *handler.SlackInteraction
+	if cfg.SlackActionsEnabled {
+		slackActions := service.NewSlackActions(geminiRepo, map[string]rss.FeedRepository{
+			"hatena":   rss.NewHatenaRSSRepository(rssRepo),
+			"lobsters": rss.NewLobstersRSSRepository(rssRepo),
+		}, repository.NewSlackThreadReplier(cfg.SlackBotToken, cfg.SlackBaseURL), usageService)
+		slackInteractionHandler = handler.NewSlackInteraction(cfg.SlackSigningSecret, slackActions)
+	}
 	xHandler := handler.NewX(xRepo)
 	xQuoteChainHandler := handler.NewXQuoteChain(xRepo)
-	hatenaHandler := handler.NewHatenaHandler(rssRepo, geminiRepo, hatenaSlackRepo, processedRepo, articleLimiter)
-	redditHandler := handler.NewRedditHandler(rssRepo, geminiRepo, redditSlackRepo, processedRepo, articleLimiter)
-	lobstersHandler := handler.NewLobstersHandler(rssRepo, geminiRepo, lobstersSlackRepo, processedRepo, articleLimiter)
+	hatenaHandler := handler.NewHatenaHandler(rssRepo, hatenaGeminiRepo, hatenaNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	redditHandler := handler.NewRedditHandler(rssRepo, redditGeminiRepo, redditNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	lobstersHandler := handler.NewLobstersHandler(rssRepo, lobstersGeminiRepo, lobstersNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	hackerNewsHandler := handler.NewHackerNewsHandler(rssRepo, hackerNewsGeminiRepo, hackerNewsNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	arxivHandler := handler.NewArxivHandler(rssRepo, geminiRepo, arxivNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	devToHandler := handler.NewDevToHandler(rssRepo, geminiRepo, devToNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	qiitaHandler := handler.NewQiitaHandler(rssRepo, geminiRepo, qiitaNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	zennHandler := handler.NewZennHandler(rssRepo, geminiRepo, zennNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	productHuntHandler := handler.NewProductHuntHandler(cfg.ProductHuntToken, rssRepo, geminiRepo, productHuntNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	// Drained entries are processed one by one, so the feed limiter does not apply
+	backlogProcessors := map[string]article.ItemProcessor{
+		"hatena":      article.NewHatenaProcessor(rssRepo, hatenaGeminiRepo, hatenaNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"reddit":      article.NewRedditProcessor(rssRepo, redditGeminiRepo, redditNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"lobsters":    article.NewLobstersProcessor(rssRepo, lobstersGeminiRepo, lobstersNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"hackernews":  article.NewHackerNewsProcessor(rssRepo, hackerNewsGeminiRepo, hackerNewsNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"arxiv":       article.NewArxivProcessor(rssRepo, geminiRepo, arxivNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"devto":       article.NewDevToProcessor(rssRepo, geminiRepo, devToNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"qiita":       article.NewQiitaProcessor(rssRepo, geminiRepo, qiitaNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"zenn":        article.NewZennProcessor(rssRepo, geminiRepo, zennNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+		"producthunt": article.NewProductHuntProcessor(cfg.ProductHuntToken, rssRepo, geminiRepo, productHuntNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency),
+	}
+	// YouTube channels are summarized from video transcripts
+	var youtubeHandler *handler.YouTubeHandler
+	if len(cfg.YouTubeChannelIDs) > 0 {
+		youtubeHandler = handler.NewYouTubeHandler(cfg.YouTubeChannelIDs, rssRepo, geminiRepo, youtubeNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+		backlogProcessors["youtube"] = article.NewYouTubeProcessor(cfg.YouTubeChannelIDs, rssRepo, geminiRepo, youtubeNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	}
+	// Link posts of followed X accounts go through the webhook pipeline, posting to their own channel
+	var xAccountsHandler *handler.XAccountsHandler
+	if len(cfg.XAccounts) > 0 {
+		xURLService := service.NewURL(geminiRepo, xNotifier)
+		xAccountsHandler = handler.NewXAccountsHandler(cfg.XAccounts, cfg.XBearerToken, cfg.XBridgeURL, rssRepo, xURLService, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+		backlogProcessors["x"] = article.NewXAccountsProcessor(cfg.XAccounts, cfg.XBearerToken, cfg.XBridgeURL, rssRepo, xURLService, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	}
+	// Podcast episodes are summarized from transcripts of their audio
+	var podcastHandler *handler.PodcastHandler
+	if len(cfg.PodcastFeeds) > 0 {
+		transcriber := repository.NewSTTRepository(cfg.STTBaseURL, cfg.STTAPIKey, cfg.STTModel, int64(cfg.STTMaxAudioMB)<<20)
+		podcastHandler = handler.NewPodcastHandler(cfg.PodcastFeeds, cfg.PodcastMaxEpisodes, transcriber, rssRepo, geminiRepo, podcastNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+		backlogProcessors["podcast"] = article.NewPodcastProcessor(cfg.PodcastFeeds, cfg.PodcastMaxEpisodes, transcriber, rssRepo, geminiRepo, podcastNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	}
+	// Subscriptions imported from OPML run as generic feeds
+	var opmlSource repository.OPMLSource
+	var opmlHandler *handler.OPMLHandler
+	if cfg.OPMLSource != "" {
+		opmlSource, err = repository.NewOPMLSource(cfg.OPMLSource)
+		if err != nil {
+			return nil, fmt.Errorf("creating OPML source: %w", err)
+		}
+		opmlHandler = handler.NewOPMLHandler(opmlSource, rssRepo, geminiRepo, opmlNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+		backlogProcessors["opml"] = article.NewOPMLProcessor(opmlSource, rssRepo, geminiRepo, opmlNotifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+	}
+	// Plain feeds defined in GENERIC_FEEDS, registered by name
+	var feedsHandler *handler.FeedsHandler
+	if len(genericFeeds) > 0 {
+		var processors []*article.GenericFeedProcessor
+		for _, spec := range genericFeeds {
+			channel := spec.Channel
+			if channel == "" {
+				channel = cfg.SlackChannel
+			}
+			notifier := withSummaryTranslation(cfg, "feeds", newFeedNotifier(cfg, "feeds", channel, shared, outbox, budgets, mirrors), geminiRepo)
+			processor := article.NewGenericFeedProcessor(spec, rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, feedStatsRepo, articleLimiter, articleConcurrency)
+			processors = append(processors, processor)
+			backlogProcessors[spec.Name] = processor
+		}
+		feedsHandler = handler.NewFeedsHandler(article.NewFeedRegistry(cfg.feedLocation(), fastLane, processors...))
+	}
+	feedHealthHandler := handler.NewFeedHealth(feedEscalation, slices.Sorted(maps.Keys(backlogProcessors)))
+	// In-process follow-up shards rerun the feed's processor directly
+	if shardScheduler != nil {
+		for feed, processor := range backlogProcessors {
+			if runner, ok := processor.(service.FeedRunner); ok {
+				shardScheduler.Register(feed, runner)
+			}
+		}
+	}
+	// Replicas sharing a schedule elect the one that runs scheduled feeds
+	var leaseRepo repository.LeaseRepository
+	var leader *service.LeaderElector
+	stopLeader := func() {}
+	if cfg.LeaderElectionEnabled {
+		leaseRepo, err = repository.NewLeaseRepository()
+		if err != nil {
+			return nil, fmt.Errorf("creating lease repository: %w", err)
+		}
+		leader = service.NewLeaderElector(leaseRepo, cfg.LeaderID, cfg.LeaderLeaseTTL)
+		var leaderCtx context.Context
+		leaderCtx, stopLeader = context.WithCancel(context.Background())
+		go leader.Run(leaderCtx)
+	}
+	backlogHandler := handler.NewBacklogHandler(article.NewBacklogDrainProcessor(backlogRepo, processedRepo, backlogProcessors, cfg.BacklogDrainLimit, cfg.BacklogDrainInterval))
+	sitemapProcessor := article.NewSitemapProcessor(rssRepo, geminiRepo, sitemapNotifier, processedRepo)
+	var summaryRegenerator *service.SummaryRegenerator
+	if summaryArchiveRepo != nil {
+		summaryRegenerator = service.NewSummaryRegenerator(summaryArchiveRepo, geminiRepo, map[string]repository.Notifier{
+			"reddit":                  redditNotifier,
+			"hatena":                  hatenaNotifier,
+			"lobsters":                lobstersNotifier,
+			"hackernews":              hackerNewsNotifier,
+			"arxiv":                   arxivNotifier,
+			"youtube":                 youtubeNotifier,
+			"devto":                   devToNotifier,
+			"qiita":                   qiitaNotifier,
+			"zenn":                    zennNotifier,
+			"producthunt":             productHuntNotifier,
+			"x":                       xNotifier,
+			"podcast":                 podcastNotifier,
+			repository.SourceOnDemand: webhookNotifier,
+			"sitemap":                 sitemapNotifier,
+		})
+	}
 
 	// Cleanup function
 	cleanup := func() error {
+		stopLeader()
+		if shardScheduler != nil {
+			shardScheduler.Close()
+		}
+		if leaseRepo != nil {
+			leaseRepo.Close()
+		}
+		if backlogRepo != nil {
+			backlogRepo.Close()
+		}
+		if auditRepo != nil {
+			auditRepo.Close()
+		}
+		if usageRepo != nil {
+			usageRepo.Close()
+		}
+		if feedStatsRepo != nil {
+			feedStatsRepo.Close()
+		}
+		if opmlSource != nil {
+			opmlSource.Close()
+		}
+		if summaryFeedRepo != nil {
+			summaryFeedRepo.Close()
+		}
+		if summaryArchiveRepo != nil {
+			summaryArchiveRepo.Close()
+		}
+		if outbox != nil {
+			outbox.Close()
+		}
+		if budgets != nil {
+			budgets.Close()
+		}
 		if processedRepo != nil {
 			return processedRepo.Close()
 		}
@@ -67,15 +468,242 @@ func New() (*Application, error) {
 	return &Application{
 		Config:             cfg,
 		WebhookHandler:     webhookHandler,
+		WebhookStream:      webhookStream,
+		SimpleHook:         simpleHook,
 		XHandler:           xHandler,
 		XQuoteChainHandler: xQuoteChainHandler,
 		HatenaHandler:      hatenaHandler,
 		RedditHandler:      redditHandler,
 		LobstersHandler:    lobstersHandler,
+		HackerNewsHandler:  hackerNewsHandler,
+		ArxivHandler:       arxivHandler,
+		YouTubeHandler:     youtubeHandler,
+		DevToHandler:       devToHandler,
+		QiitaHandler:       qiitaHandler,
+		ZennHandler:        zennHandler,
+		ProductHuntHandler: productHuntHandler,
+		XAccountsHandler:   xAccountsHandler,
+		PodcastHandler:     podcastHandler,
+		OPMLHandler:        opmlHandler,
+		FeedsHandler:       feedsHandler,
+		BacklogHandler:     backlogHandler,
+		HistoryHandler:     historyHandler,
+		ProvidersHandler:   handler.NewProviders(repository.ProviderStatuses),
+		FeedHealthHandler:  feedHealthHandler,
+		SummaryFeedHandler: summaryFeedHandler,
+		SummaryExport:      summaryExport,
+		SummaryArchive:     summaryArchive,
+		AdminProcessed:     adminProcessedHandler,
+		AdminImport:        adminImportHandler,
+		AdminPrune:         adminPruneHandler,
+		AdminAudit:         adminAuditHandler,
+		AdminUsage:         adminUsageHandler,
+		AdminStats:         handler.NewAdminProcessedStats(repository.ProcessedIndexStats),
+		SlackCommand:       slackCommandHandler,
+		SlackInteraction:   slackInteractionHandler,
+		SitemapProcessor:   sitemapProcessor,
+		SummaryRegenerator: summaryRegenerator,
+		leader:             leader,
+		fastLane:           fastLane,
 		cleanup:            cleanup,
 	}, nil
 }
 
+// newSimulation creates an application running the feed pipeline offline and without external side
+// effects: feeds and article pages come from embedded fixtures (or file:// overrides), a stub quotes the
+// pages instead of calling Gemini, each run is capped, notifications are only logged and the processed
+// index lives in memory.
+func newSimulation(cfg *Config) (*Application, error) {
+	rssRepo := repository.NewSimulationRSSRepository(repository.NewRSSRepository())
+	geminiRepo := repository.NewSimulationSummarizer()
+	processedRepo := repository.NewMemoryProcessedArticleRepository()
+	articleLimiter := limiter.NewCappedArticleLimiter(cfg.SimulationArticleLimit)
+	articleConcurrency := limiter.NewConcurrencyController(cfg.ArticleConcurrencyMin, cfg.ArticleConcurrencyMax, cfg.ArticleLatencyTarget)
+
+	// No backlog or feed stats: failed articles are only logged and runs are never skipped
+	return &Application{
+		Config:           cfg,
+		HatenaHandler:    handler.NewHatenaHandler(rssRepo, geminiRepo, repository.NewDryRunNotifier("hatena"), processedRepo, nil, nil, articleLimiter, articleConcurrency),
+		RedditHandler:    handler.NewRedditHandler(rssRepo, geminiRepo, repository.NewDryRunNotifier("reddit"), processedRepo, nil, nil, articleLimiter, articleConcurrency),
+		LobstersHandler:  handler.NewLobstersHandler(rssRepo, geminiRepo, repository.NewDryRunNotifier("lobsters"), processedRepo, nil, nil, articleLimiter, articleConcurrency),
+		HistoryHandler:   handler.NewHistory(service.NewHistory(processedRepo)),
+		ProvidersHandler: handler.NewProviders(repository.ProviderStatuses),
+		cleanup:          processedRepo.Close,
+	}, nil
+}
+
+// newReadOnly creates an application exposing only the read APIs over the processed index
+func newReadOnly(cfg *Config) (*Application, error) {
+	processedRepo, err := repository.NewProcessedArticleRepository()
+	if err != nil {
+		return nil, fmt.Errorf("creating processed article repository: %w", err)
+	}
+
+	summaryFeedRepo, err := repository.NewSummaryFeedRepository()
+	if err != nil {
+		processedRepo.Close()
+		return nil, fmt.Errorf("creating summary feed repository: %w", err)
+	}
+
+	app := &Application{
+		Config:             cfg,
+		HistoryHandler:     handler.NewHistory(service.NewHistory(processedRepo)),
+		SummaryFeedHandler: handler.NewSummaryFeed(summaryFeedRepo),
+		SummaryExport:      handler.NewSummaryExport(service.NewExport(summaryFeedRepo)),
+		cleanup: func() error {
+			summaryFeedRepo.Close()
+			return processedRepo.Close()
+		},
+	}
+	// The public archive re-serves what processing instances archived
+	if cfg.SummaryArchiveEnabled {
+		summaryArchiveRepo, err := repository.NewSummaryArchiveRepository()
+		if err != nil {
+			summaryFeedRepo.Close()
+			processedRepo.Close()
+			return nil, fmt.Errorf("creating summary archive repository: %w", err)
+		}
+		app.SummaryArchive = handler.NewSummaryArchive(summaryArchiveRepo)
+		app.cleanup = func() error {
+			summaryArchiveRepo.Close()
+			summaryFeedRepo.Close()
+			return processedRepo.Close()
+		}
+	}
+	return app, nil
+}
+
+// ReadOnly reports whether processing endpoints are disabled
+func (a *Application) ReadOnly() bool {
+	return a.Config.ServiceMode == ServiceModeReadOnly
+}
+
+// Simulation reports whether only the fixture-backed feed pipeline is available
+func (a *Application) Simulation() bool {
+	return a.Config.ServiceMode == ServiceModeSimulation
+}
+
+// Scheduled limits a built-in feed's endpoint to its active window (ACTIVE_WINDOW_<FEED>) and the leader replica,
+// and lets manual runs bypass the processed index (?reprocess)
+func (a *Application) Scheduled(feed string, next http.Handler) http.Handler {
+	window, err := rss.ParseActiveWindow(a.Config.ActiveWindows[feed])
+	if err != nil || window == nil { // Invalid windows are rejected in Config
+		return handler.NewReprocess(a.LeaderOnly(feed, next))
+	}
+	return handler.NewReprocess(a.LeaderOnly(feed, handler.NewActiveWindow(feed, window, a.Config.feedLocation(), a.fastLane, next)))
+}
+
+// LeaderOnly runs a scheduled endpoint only on the leader replica when leader election is enabled
+func (a *Application) LeaderOnly(feed string, next http.Handler) http.Handler {
+	if a.leader == nil {
+		return next
+	}
+	return handler.NewLeaderOnly(feed, a.leader, next)
+}
+
+// newFeedNotifier fans a feed's notifications out to its own notifier, its Slack mirror channels and the global mirrors.
+// The summary is generated once per article and cross-posted, so mirror channels never trigger another Gemini call.
+func newFeedNotifier(cfg *Config, feed, slackChannel string, shared map[string]repository.Notifier, outbox repository.Storage, budgets *repository.ChannelBudgets, mirrors []repository.Notifier) repository.Notifier {
+	if cfg.DryRun {
+		// Mirrors (archive, summary feed, Notion) are left out as well
+		return repository.NewDryRunNotifier(feed)
+	}
+	var feedMirrors []repository.Notifier
+	for _, channel := range cfg.SlackMirrorChannels[feed] {
+		// Posting twice to the primary channel would duplicate the summary
+		if cfg.Notifiers[feed] == "slack" && channel == slackChannel {
+			continue
+		}
+		feedMirrors = append(feedMirrors, newSlackNotifier(cfg, channel, budgets))
+	}
+	return repository.NewBreakingNotifier(repository.NewFanoutNotifier(newNotifier(cfg, feed, slackChannel, shared, outbox, budgets), append(feedMirrors, mirrors...)...))
+}
+
+// withSummaryTranslation wraps a feed's notifier to deliver summaries with a translation into SUMMARY_TRANSLATION_<FEED>
+// (validated in Config). On-demand summaries are always wrapped, since each request can ask for a translation.
+func withSummaryTranslation(cfg *Config, feed string, notifier repository.Notifier, geminiRepo repository.GeminiRepository) repository.Notifier {
+	language := cfg.SummaryTranslations[feed]
+	if language == "" && feed != repository.SourceOnDemand {
+		return notifier
+	}
+	return article.NewSummaryTranslator(notifier, geminiRepo, language)
+}
+
+// newNotifier returns the notifier selected for a feed via NOTIFIER_<FEED> (validated in Config),
+// preferring a shared notifier of that kind when one exists. Email digests keep their outbox in outbox (nil: none),
+// and Slack channels post within budgets (nil: unlimited).
+func newNotifier(cfg *Config, feed, slackChannel string, shared map[string]repository.Notifier, outbox repository.Storage, budgets *repository.ChannelBudgets) repository.Notifier {
+	if notifier, ok := shared[cfg.Notifiers[feed]]; ok {
+		return notifier
+	}
+
+	switch cfg.Notifiers[feed] {
+	case "email":
+		return repository.NewEmailRepositoryWithOutbox(emailConfig(cfg), feed, repository.NewWriteAheadLog(outbox, "email-"+feed))
+	case "discord":
+		return repository.NewDiscordRepository(cfg.DiscordWebhookURLs[feed])
+	case "telegram":
+		return repository.NewTelegramRepository(cfg.TelegramBotToken, cfg.TelegramChatID, cfg.TelegramBaseURL)
+	case "webhook":
+		return repository.NewOutboundWebhookRepository(repository.OutboundWebhookConfig{
+			URL:         cfg.OutboundWebhookURLs[feed],
+			Secret:      cfg.OutboundWebhookSecret,
+			MaxAttempts: cfg.OutboundWebhookMaxAttempts,
+		})
+	default:
+		return newSlackNotifier(cfg, slackChannel, budgets)
+	}
+}
+
+// newSlackNotifier returns a Slack notifier for channel, with summary buttons when SLACK_ACTIONS_ENABLED is set,
+// within the channel's posting budget and limited to the channel's levels in SLACK_CHANNEL_LEVELS
+func newSlackNotifier(cfg *Config, channel string, budgets *repository.ChannelBudgets) repository.Notifier {
+	var notifier repository.Notifier
+	if cfg.SlackActionsEnabled {
+		notifier = repository.NewSlackActionsRepository(cfg.SlackBotToken, channel, cfg.SlackBaseURL)
+	} else {
+		notifier = repository.NewSlackRepository(cfg.SlackBotToken, channel, cfg.SlackBaseURL)
+	}
+	// Inside the level filter, so held-back summaries do not use up the budget
+	notifier = budgets.Wrap(notifier, channel)
+	// Validated in Config.Validate
+	channelLevels, _ := repository.ParseChannelLevels(cfg.SlackChannelLevels)
+	if levels := repository.ChannelLevels(channelLevels, channel); levels != nil {
+		notifier = repository.NewLevelFilter(notifier, levels)
+	}
+	return notifier
+}
+
+// emailConfig extracts the SMTP settings for email digest notifiers
+func emailConfig(cfg *Config) repository.EmailConfig {
+	return repository.EmailConfig{
+		Host:     cfg.EmailSMTPHost,
+		Port:     cfg.EmailSMTPPort,
+		Username: cfg.EmailSMTPUsername,
+		Password: cfg.EmailSMTPPassword,
+		From:     cfg.EmailFrom,
+		To:       cfg.EmailTo,
+	}
+}
+
+// newFeedGeminiRepository returns the Gemini repository for a feed.
+// A per-feed variant pins that feed to one prompt; otherwise the global experiment (if any) assigns variants randomly.
+func newFeedGeminiRepository(cfg *Config, feedVariant string, defaultRepo repository.GeminiRepository, extractionRules []repository.ExtractionRule) (repository.GeminiRepository, error) {
+	variants := cfg.PromptExperimentVariants
+	if feedVariant != "" {
+		variants = []string{feedVariant}
+	}
+	if len(variants) == 0 {
+		return defaultRepo, nil
+	}
+
+	experiment, err := repository.NewPromptExperiment(variants)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewGeminiRepositoryWithExperiment(cfg.GeminiAPIKey, cfg.GeminiModel, cfg.GeminiBaseURL, experiment, extractionRules), nil
+}
+
 // Close cleans up application resources
 func (a *Application) Close() error {
 	if a.cleanup != nil {
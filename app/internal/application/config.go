@@ -1,69 +1,836 @@
 package application
 
 import (
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata" // FEED_TIMEZONE must resolve even without system zoneinfo
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/transport/middleware"
+)
+
+// Service modes
+const (
+	ServiceModeFull     = "full"     // Ingestion + read APIs
+	ServiceModeReadOnly = "readonly" // Read APIs only (public-facing archive)
+	// Feed pipeline on fixture feeds with dry-run notifiers and an in-memory index (demos, workshops)
+	ServiceModeSimulation = "simulation"
 )
 
+// Auth modes: how non-health endpoints authenticate callers
+const (
+	AuthModeToken = "token" // Bearer tokens with scopes (WEBHOOK_AUTH_TOKEN / AUTH_TOKENS)
+	AuthModeIAP   = "iap"   // Identity-Aware Proxy assertion header
+	AuthModeMTLS  = "mtls"  // Verified TLS client certificate (self-hosted)
+)
+
+// defaultSTTBaseURL is OpenAI's speech-to-text API (Whisper)
+const defaultSTTBaseURL = "https://api.openai.com/v1"
+
+// NotifierFeeds are the notification destinations that can select their own notifier
+var NotifierFeeds = []string{"reddit", "hatena", "lobsters", "hackernews", "arxiv", "youtube", "devto", "qiita", "zenn", "producthunt", "x", "podcast", repository.SourceOnDemand, "sitemap", "opml", "feeds"}
+
+// ScheduledFeeds are the built-in feeds whose scheduled runs can be limited to an active window
+var ScheduledFeeds = []string{"reddit", "hatena", "lobsters", "hackernews", "arxiv", "youtube", "devto", "qiita", "zenn", "producthunt", "x", "podcast", "opml"}
+
 // Config holds all configuration for the application
 type Config struct {
 	// Server settings
-	Port string `json:"port"`
-	Host string `json:"host"`
+	Port        string `json:"port"`
+	Host        string `json:"host"`
+	ServiceMode string `json:"service_mode"` // ServiceModeFull, ServiceModeReadOnly or ServiceModeSimulation
+	// DryRun runs the whole pipeline against production feeds and Gemini, but logs notifications
+	// instead of sending them and leaves the processed index, backlog and feed statistics unchanged
+	DryRun bool `json:"dry_run"`
 
 	// Gemini API settings
 	GeminiAPIKey  string `json:"-"` // Don't expose in JSON
 	GeminiModel   string `json:"gemini_model"`
 	GeminiBaseURL string `json:"gemini_base_url"` // For testing
+	// Data residency: allowed Vertex AI regions in order of preference (empty uses the global Gemini API)
+	GeminiRegions string `json:"gemini_regions"`
+	VertexProject string `json:"vertex_project"`
 
 	// Slack settings
-	SlackBotToken        string `json:"-"` // Don't expose in JSON
-	SlackChannel         string `json:"slack_channel"`
-	SlackChannelReddit   string `json:"slack_channel_reddit"`
-	SlackChannelHatena   string `json:"slack_channel_hatena"`
-	SlackChannelLobsters string `json:"slack_channel_lobsters"`
-	WebhookSlackChannel  string `json:"webhook_slack_channel"`
-	SlackBaseURL         string `json:"slack_base_url"` // For testing
+	SlackBotToken           string `json:"-"` // Don't expose in JSON
+	SlackChannel            string `json:"slack_channel"`
+	SlackChannelReddit      string `json:"slack_channel_reddit"`
+	SlackChannelHatena      string `json:"slack_channel_hatena"`
+	SlackChannelLobsters    string `json:"slack_channel_lobsters"`
+	SlackChannelHackerNews  string `json:"slack_channel_hackernews"`
+	SlackChannelArxiv       string `json:"slack_channel_arxiv"`
+	SlackChannelYouTube     string `json:"slack_channel_youtube"`
+	SlackChannelDevTo       string `json:"slack_channel_devto"`
+	SlackChannelQiita       string `json:"slack_channel_qiita"`
+	SlackChannelZenn        string `json:"slack_channel_zenn"`
+	SlackChannelProductHunt string `json:"slack_channel_producthunt"`
+	SlackChannelX           string `json:"slack_channel_x"`
+	SlackChannelPodcast     string `json:"slack_channel_podcast"`
+	WebhookSlackChannel     string `json:"webhook_slack_channel"`
+	SlackBaseURL            string `json:"slack_base_url"` // For testing
+
+	// Mirror channels: extra Slack channels per feed that receive the same summary (generated once, cross-posted)
+	SlackMirrorChannels map[string][]string `json:"slack_mirror_channels"`
+
+	// Notifier settings: per-feed sink ("slack", "discord", "telegram", "email", "webhook" or "markdown") keyed by NotifierFeeds
+	Notifiers                  map[string]string `json:"notifiers"`
+	DiscordWebhookURLs         map[string]string `json:"-"` // Don't expose in JSON
+	OutboundWebhookURLs        map[string]string `json:"-"` // Don't expose in JSON
+	OutboundWebhookSecret      string            `json:"-"` // HMAC signing key; empty disables signing
+	OutboundWebhookMaxAttempts int               `json:"outbound_webhook_max_attempts"`
+
+	// Email digest settings
+	EmailSMTPHost     string   `json:"email_smtp_host"`
+	EmailSMTPPort     string   `json:"email_smtp_port"`
+	EmailSMTPUsername string   `json:"email_smtp_username"`
+	EmailSMTPPassword string   `json:"-"` // Don't expose in JSON
+	EmailFrom         string   `json:"email_from"`
+	EmailTo           []string `json:"email_to"`
+	EmailDigestMode   string   `json:"email_digest_mode"` // "per_feed" or "combined"
+
+	// Telegram settings
+	TelegramBotToken string `json:"-"` // Don't expose in JSON
+	TelegramChatID   string `json:"telegram_chat_id"`
+	TelegramBaseURL  string `json:"telegram_base_url"` // For testing
+
+	// Notion settings: every feed's summaries are also archived to this database when set
+	NotionToken      string `json:"-"` // Don't expose in JSON
+	NotionDatabaseID string `json:"notion_database_id"`
+	NotionBaseURL    string `json:"notion_base_url"` // For testing
+
+	// Summary feed settings: publish generated summaries as RSS (GET /feed.xml and a GCS export)
+	SummaryFeedEnabled bool `json:"summary_feed_enabled"`
+
+	// Summary archive settings: keep every summary as one storage object per article (GET /api/v1/summaries/archive)
+	SummaryArchiveEnabled bool `json:"summary_archive_enabled"`
+
+	// Read API cache: responses of GET /history, /api/v1/providers, /feed.xml and /api/v1/summaries/archive
+	// are kept in memory this long (0 disables), so that polling clients do not reach the storage each time
+	ReadCacheTTL     time.Duration `json:"read_cache_ttl"`
+	ReadCacheEntries int           `json:"read_cache_entries"` // Responses kept per endpoint
+
+	// Markdown notes settings: local directory or gs://bucket/prefix or s3://bucket/prefix
+	MarkdownOutput string `json:"markdown_output"`
 
 	// Webhook settings
-	WebhookAuthToken string `json:"-"` // Don't expose in JSON
+	WebhookAuthToken string `json:"-"` // Don't expose in JSON; granted every scope
+	AuthTokens       string `json:"-"` // Scoped tokens "token:scope+scope,..." (see middleware.ParseTokenScopes)
+	// Payload fields holding the URL and requester, e.g. the variable names of a Slack Workflow Builder step
+	WebhookURLField  string `json:"webhook_url_field"`
+	WebhookUserField string `json:"webhook_user_field"`
+	// Identity auth for deployments that forbid static tokens: authenticated callers get the scopes
+	// IdentityScopes grants their principal, or every scope but admin when it does not list them
+	AuthMode       string `json:"auth_mode"`       // AuthModeToken, AuthModeIAP or AuthModeMTLS
+	IAPAudience    string `json:"iap_audience"`    // Expected aud claim of IAP assertions
+	IdentityScopes string `json:"identity_scopes"` // "principal:scope+scope,..." (e.g. "iap:alice@example.com:admin+read")
+
+	// On-demand usage settings: per-user (token or Slack user) daily quota, 0 = unlimited
+	OnDemandDailyQuota int    `json:"ondemand_daily_quota"`
+	SlackSigningSecret string `json:"-"` // Enables the /summaries slash command when set
+	// Streaming on-demand requests (/webhook/stream) processed at once; the rest wait in the queue
+	OnDemandStreamWorkers int `json:"ondemand_stream_workers"`
+
+	// Slack actions: summary buttons (詳細要約/コメント要約/再要約) handled at /slack/interactions
+	SlackActionsEnabled bool `json:"slack_actions_enabled"`
+
+	// Channel budget settings: summaries posted per Slack channel per hour (0 = unlimited), and
+	// whether the rest waits for a later run ("queue") or collapses into one message ("digest")
+	SlackMaxPostsPerHour int    `json:"slack_max_posts_per_hour"`
+	SlackBudgetOverflow  string `json:"slack_budget_overflow"`
+
+	// Difficulty settings: articles are classified beginner/intermediate/expert, and channels can be
+	// limited to some levels (see repository.ParseChannelLevels)
+	DifficultyEnabled  bool   `json:"difficulty_enabled"`
+	SlackChannelLevels string `json:"slack_channel_levels"`
+
+	// Comment summary settings: "separate" (default) summarizes an article and its comments in two calls,
+	// "combined" in one call with two labeled sections (half the calls, at some cost in quality)
+	CommentSummaryMode string `json:"comment_summary_mode"`
+
+	// Prompt experiment settings
+	PromptExperimentVariants []string `json:"prompt_experiment_variants"` // Randomly assigned to every feed
+	PromptVariantReddit      string   `json:"prompt_variant_reddit"`      // Fixed per-feed variant (overrides experiment)
+	PromptVariantHatena      string   `json:"prompt_variant_hatena"`
+	PromptVariantLobsters    string   `json:"prompt_variant_lobsters"`
+	PromptVariantHackerNews  string   `json:"prompt_variant_hackernews"`
+
+	// Headline rewrite settings: feeds whose clickbait titles are rewritten by the LLM
+	HeadlineRewriteSources []string `json:"headline_rewrite_sources"`
+
+	// Ops thread settings: feeds whose runs are annotated per article in a Slack thread (empty = quiet)
+	OpsThreadFeeds   []string `json:"ops_thread_feeds"`
+	OpsThreadChannel string   `json:"ops_thread_channel"`
+	// Feed ownership: Slack user or user group IDs keyed by ScheduledFeeds (GENERIC_FEEDS use their owner
+	// field), mentioned in the ops channel once a feed has failed FeedEscalationRuns runs in a row (0 disables)
+	FeedOwners         map[string]string `json:"feed_owners"`
+	FeedEscalationRuns int               `json:"feed_escalation_runs"`
+
+	// Breaking news fast lane: items of these feeds, or whose titles contain a keyword, published within
+	// BreakingMaxAge run first, outside active windows and unbatched, with a 🚨 prefix (see limiter.FastLane)
+	BreakingFeeds    []string      `json:"breaking_feeds"` // ScheduledFeeds or GENERIC_FEEDS names
+	BreakingKeywords []string      `json:"breaking_keywords"`
+	BreakingMaxAge   time.Duration `json:"breaking_max_age"`
+
+	// Shard settings: a run finding more than ShardSize new items (e.g. after a feed's outage) processes the
+	// newest ShardSize and runs the feed again after ShardInterval (see limiter.Sharder). 0 disables sharding.
+	ShardSize       int           `json:"shard_size"`
+	ShardInterval   time.Duration `json:"shard_interval"`
+	CloudTasksQueue string        `json:"cloud_tasks_queue"` // Full queue name; empty runs follow-ups with in-process timers
+	ShardTargetURL  string        `json:"shard_target_url"`  // Base URL of this service, called by the Cloud Tasks
+
+	// Extraction settings: per-domain rules JSON (see repository.ParseExtractionRules)
+	ExtractionRules string `json:"extraction_rules"`
+
+	// Fetch policy settings: article pages are fetched as a polite crawler (see repository.FetchPolicy)
+	UserAgent        string        `json:"user_agent"`         // Its product token (e.g. ArticleSummarizerBot) selects the robots.txt group
+	RespectRobotsTxt bool          `json:"respect_robots_txt"` // Pages disallowed by robots.txt are summarized from the feed description
+	CrawlDelay       time.Duration `json:"crawl_delay"`        // Minimum pause between fetches from one host; robots.txt Crawl-delay can raise it
+
+	// Summary language settings: summaries in another language are re-asked once with an explicit instruction
+	SummaryLanguage string `json:"summary_language"` // ja (default), en, or auto (each article in its own language)
+	SlackLocale     string `json:"slack_locale"`     // Labels of Slack messages: ja (default) or en
+	// SummaryTranslations delivers each feed's summaries with a translation into ja or en (SUMMARY_TRANSLATION_<FEED>);
+	// on-demand requests can ask for one with their translate field
+	SummaryTranslations map[string]string `json:"summary_translations"`
+
+	// OPML settings: local path, gs://bucket/object or s3://bucket/object listing extra generic feeds (empty disables /process/opml)
+	OPMLSource string `json:"opml_source"`
+
+	// Generic feed settings: plain feeds defined entirely by JSON (see rss.ParseGenericFeedSpecs)
+	GenericFeeds string `json:"generic_feeds"`
+
+	// Scheduling settings: hours during which scheduled feed runs post, keyed by ScheduledFeeds
+	// (GENERIC_FEEDS use their active_window field). Empty windows run at any hour.
+	FeedTimezone  string            `json:"feed_timezone"`
+	ActiveWindows map[string]string `json:"active_windows"` // e.g. "07:00-23:00"
+
+	// Leader election settings: replicas of cmd/server sharing a schedule run scheduled feeds, GENERIC_FEEDS and
+	// the backlog drain only on the replica holding the lease in the shared storage (see service.LeaderElector)
+	LeaderElectionEnabled bool          `json:"leader_election_enabled"`
+	LeaderID              string        `json:"leader_id"` // Defaults to the host name
+	LeaderLeaseTTL        time.Duration `json:"leader_lease_ttl"`
+
+	// Reddit settings: subreddits or multireddits /process/reddit merges, each with its own thresholds
+	// (see rss.ParseRedditSubreddits). Empty reads the r/programming RSS feed.
+	RedditSubreddits string `json:"reddit_subreddits"`
+	// Credentials of a Reddit "script" app: listings and comments then come from the OAuth API
+	// instead of anonymous RSS/JSON requests, which Reddit throttles (see rss.RedditCredentialsFromEnv)
+	RedditClientID     string `json:"reddit_client_id"`
+	RedditClientSecret string `json:"-"` // Don't expose in JSON
+	RedditUsername     string `json:"reddit_username"`
+	RedditPassword     string `json:"-"` // Don't expose in JSON
+
+	// arXiv settings: categories whose newest submissions /process/arxiv summarizes (e.g. cs.AI,cs.CL)
+	ArxivCategories []string `json:"arxiv_categories"`
+
+	// YouTube settings: channels whose uploads /process/youtube summarizes from transcripts (empty disables)
+	YouTubeChannelIDs []string `json:"youtube_channel_ids"`
+
+	// Product Hunt settings: /process/producthunt reads votes from the GraphQL API with this developer token,
+	// otherwise the public feed (no vote counts)
+	ProductHuntToken string `json:"-"` // Don't expose in JSON
+
+	// X settings: accounts whose link posts /process/x sends through the webhook pipeline (empty disables).
+	// Posts are read from the X API v2 with XBearerToken, otherwise from the RSS bridge at XBridgeURL.
+	XAccounts    []string `json:"x_accounts"`
+	XBearerToken string   `json:"-"` // Don't expose in JSON
+	XBridgeURL   string   `json:"x_bridge_url"`
+
+	// Podcast settings: feeds whose recent episodes /process/podcast transcribes and summarizes (empty disables).
+	// Audio goes to an OpenAI-compatible speech-to-text API (POST <STTBaseURL>/audio/transcriptions).
+	PodcastFeeds       []string `json:"podcast_feeds"`
+	PodcastMaxEpisodes int      `json:"podcast_max_episodes"` // Per feed and run
+	STTBaseURL         string   `json:"stt_base_url"`
+	STTAPIKey          string   `json:"-"` // Don't expose in JSON
+	STTModel           string   `json:"stt_model"`
+	STTMaxAudioMB      int      `json:"stt_max_audio_mb"` // Larger episodes are summarized from their show notes
+
+	// Internal documentation settings: authenticated fetchers for Confluence Cloud and Google Docs links
+	ConfluenceBaseURL  string `json:"confluence_base_url"` // e.g. https://example.atlassian.net (empty disables)
+	ConfluenceEmail    string `json:"confluence_email"`
+	ConfluenceAPIToken string `json:"-"`                // Don't expose in JSON
+	GoogleDocsAuth     string `json:"google_docs_auth"` // "adc" or path to a service account / OAuth JSON key (empty disables)
+
+	// Redaction settings: patterns scrubbed from text sent to the LLM (see repository.ParseRedactionRules)
+	RedactionRules string `json:"redaction_rules"`
+
+	// Processed index retention: entries processed longer ago are pruned (0 keeps them forever)
+	ProcessedRetentionDays int `json:"processed_retention_days"`
+	// Dedup TTL: days after which a feed's processed article counts as new again, keyed by ScheduledFeeds
+	// (GENERIC_FEEDS use their dedup_ttl_days field; absent feeds deduplicate forever)
+	DedupTTLDays map[string]int `json:"dedup_ttl_days"`
+
+	// Backlog drain settings (low-rate retry of failed articles)
+	BacklogDrainLimit    int           `json:"backlog_drain_limit"`    // Max entries retried per drain run
+	BacklogDrainInterval time.Duration `json:"backlog_drain_interval"` // Pause between retried entries
+
+	// Article concurrency settings (auto-tuned within bounds from Gemini 429s and latency)
+	ArticleConcurrencyMin int           `json:"article_concurrency_min"`
+	ArticleConcurrencyMax int           `json:"article_concurrency_max"`
+	ArticleLatencyTarget  time.Duration `json:"article_latency_target"` // Slower articles shrink the pool (0 disables)
+
+	// Simulation settings: articles summarized per feed run in simulation mode
+	SimulationArticleLimit int `json:"simulation_article_limit"`
 }
 
 // Load reads configuration from environment variables
 func Load() (*Config, error) {
+	return LoadWith(nil)
+}
+
+// LoadWith is Load with override applied before validation, so CLI flags can replace settings
+// (e.g. route a feed to another notifier) without writing to the environment
+func LoadWith(override func(cfg *Config)) (*Config, error) {
 	config := &Config{
-		Port:                 "8080",
-		Host:                 "0.0.0.0",
-		GeminiAPIKey:         getEnvOrDefault("GEMINI_API_KEY", ""),
-		GeminiModel:          "gemini-2.5-flash-preview-05-20",
-		GeminiBaseURL:        getEnvOrDefault("GEMINI_BASE_URL", "https://generativelanguage.googleapis.com/v1beta/models"),
-		SlackBotToken:        getEnvOrDefault("SLACK_BOT_TOKEN", ""),
-		SlackChannel:         getEnvOrDefault("SLACK_CHANNEL", "#article-summarizer"),
-		SlackChannelReddit:   getEnvOrDefault("SLACK_CHANNEL_REDDIT", "#reddit-article-summary"),
-		SlackChannelHatena:   getEnvOrDefault("SLACK_CHANNEL_HATENA", "#hatena-article-summary"),
-		SlackChannelLobsters: getEnvOrDefault("SLACK_CHANNEL_LOBSTERS", "#lobsters-article-summary"),
-		WebhookSlackChannel:  getEnvOrDefault("WEBHOOK_SLACK_CHANNEL", "#ondemand-article-summary"),
-		SlackBaseURL:         getEnvOrDefault("SLACK_BASE_URL", "https://slack.com/api"),
-		WebhookAuthToken:     getEnvOrDefault("WEBHOOK_AUTH_TOKEN", ""),
+		Port:                       "8080",
+		Host:                       "0.0.0.0",
+		ServiceMode:                getEnvOrDefault("SERVICE_MODE", ServiceModeFull),
+		DryRun:                     getEnvOrDefault("DRY_RUN", "false") == "true",
+		GeminiAPIKey:               getEnvOrDefault("GEMINI_API_KEY", ""),
+		GeminiModel:                "gemini-2.5-flash-preview-05-20",
+		GeminiBaseURL:              getEnvOrDefault("GEMINI_BASE_URL", "https://generativelanguage.googleapis.com/v1beta/models"),
+		GeminiRegions:              getEnvOrDefault("GEMINI_REGIONS", ""),
+		VertexProject:              getEnvOrDefault("VERTEX_PROJECT", ""),
+		SlackBotToken:              getEnvOrDefault("SLACK_BOT_TOKEN", ""),
+		SlackChannel:               getEnvOrDefault("SLACK_CHANNEL", "#article-summarizer"),
+		SlackChannelReddit:         getEnvOrDefault("SLACK_CHANNEL_REDDIT", "#reddit-article-summary"),
+		SlackChannelHatena:         getEnvOrDefault("SLACK_CHANNEL_HATENA", "#hatena-article-summary"),
+		SlackChannelLobsters:       getEnvOrDefault("SLACK_CHANNEL_LOBSTERS", "#lobsters-article-summary"),
+		SlackChannelHackerNews:     getEnvOrDefault("SLACK_CHANNEL_HACKERNEWS", "#hackernews-article-summary"),
+		SlackChannelArxiv:          getEnvOrDefault("SLACK_CHANNEL_ARXIV", "#arxiv-paper-summary"),
+		SlackChannelYouTube:        getEnvOrDefault("SLACK_CHANNEL_YOUTUBE", "#youtube-video-summary"),
+		SlackChannelDevTo:          getEnvOrDefault("SLACK_CHANNEL_DEVTO", "#devto-article-summary"),
+		SlackChannelQiita:          getEnvOrDefault("SLACK_CHANNEL_QIITA", "#qiita-article-summary"),
+		SlackChannelZenn:           getEnvOrDefault("SLACK_CHANNEL_ZENN", "#zenn-article-summary"),
+		SlackChannelProductHunt:    getEnvOrDefault("SLACK_CHANNEL_PRODUCTHUNT", "#producthunt-product-summary"),
+		SlackChannelX:              getEnvOrDefault("SLACK_CHANNEL_X", "#x-article-summary"),
+		SlackChannelPodcast:        getEnvOrDefault("SLACK_CHANNEL_PODCAST", "#podcast-episode-summary"),
+		WebhookSlackChannel:        getEnvOrDefault("WEBHOOK_SLACK_CHANNEL", "#ondemand-article-summary"),
+		SlackBaseURL:               getEnvOrDefault("SLACK_BASE_URL", "https://slack.com/api"),
+		WebhookAuthToken:           getEnvOrDefault("WEBHOOK_AUTH_TOKEN", ""),
+		WebhookURLField:            getEnvOrDefault("WEBHOOK_URL_FIELD", "url"),
+		WebhookUserField:           getEnvOrDefault("WEBHOOK_USER_FIELD", "user"),
+		AuthTokens:                 getEnvOrDefault("AUTH_TOKENS", ""),
+		AuthMode:                   getEnvOrDefault("AUTH_MODE", AuthModeToken),
+		IAPAudience:                getEnvOrDefault("IAP_AUDIENCE", ""),
+		IdentityScopes:             getEnvOrDefault("IDENTITY_SCOPES", ""),
+		OnDemandDailyQuota:         getEnvInt("ONDEMAND_DAILY_QUOTA", 0),
+		OnDemandStreamWorkers:      getEnvInt("ONDEMAND_STREAM_WORKERS", 2),
+		SlackSigningSecret:         getEnvOrDefault("SLACK_SIGNING_SECRET", ""),
+		SlackActionsEnabled:        getEnvOrDefault("SLACK_ACTIONS_ENABLED", "false") == "true",
+		SlackMaxPostsPerHour:       getEnvInt("SLACK_CHANNEL_MAX_POSTS_PER_HOUR", 0),
+		SlackBudgetOverflow:        getEnvOrDefault("SLACK_CHANNEL_BUDGET_OVERFLOW", repository.ChannelBudgetQueue),
+		DifficultyEnabled:          getEnvOrDefault("DIFFICULTY_ENABLED", "false") == "true",
+		SlackChannelLevels:         getEnvOrDefault("SLACK_CHANNEL_LEVELS", ""),
+		CommentSummaryMode:         getEnvOrDefault("COMMENT_SUMMARY_MODE", repository.CommentSummarySeparate),
+		ExtractionRules:            getEnvOrDefault("EXTRACTION_RULES", ""),
+		UserAgent:                  getEnvOrDefault("FETCH_USER_AGENT", repository.DefaultUserAgent),
+		RespectRobotsTxt:           getEnvOrDefault("RESPECT_ROBOTS_TXT", "true") != "false",
+		CrawlDelay:                 time.Duration(getEnvInt("CRAWL_DELAY_SECONDS", 0)) * time.Second,
+		SummaryLanguage:            getEnvOrDefault("SUMMARY_LANGUAGE", repository.SummaryLanguageJapanese),
+		SlackLocale:                getEnvOrDefault("SLACK_LOCALE", repository.SlackLocaleJapanese),
+		RedactionRules:             getEnvOrDefault("REDACTION_RULES", ""),
+		OPMLSource:                 getEnvOrDefault("OPML_SOURCE", ""),
+		GenericFeeds:               getEnvOrDefault("GENERIC_FEEDS", ""),
+		FeedTimezone:               getEnvOrDefault("FEED_TIMEZONE", "Asia/Tokyo"),
+		RedditSubreddits:           getEnvOrDefault("REDDIT_SUBREDDITS", ""),
+		RedditClientID:             getEnvOrDefault("REDDIT_CLIENT_ID", ""),
+		RedditClientSecret:         getEnvOrDefault("REDDIT_CLIENT_SECRET", ""),
+		RedditUsername:             getEnvOrDefault("REDDIT_USERNAME", ""),
+		RedditPassword:             getEnvOrDefault("REDDIT_PASSWORD", ""),
+		ArxivCategories:            getEnvList("ARXIV_CATEGORIES"),
+		YouTubeChannelIDs:          getEnvList("YOUTUBE_CHANNEL_IDS"),
+		ProductHuntToken:           getEnvOrDefault("PRODUCTHUNT_TOKEN", ""),
+		XAccounts:                  getEnvList("X_ACCOUNTS"),
+		XBearerToken:               getEnvOrDefault("X_BEARER_TOKEN", ""),
+		XBridgeURL:                 getEnvOrDefault("X_BRIDGE_URL", ""),
+		PodcastFeeds:               getEnvList("PODCAST_FEEDS"),
+		PodcastMaxEpisodes:         getEnvInt("PODCAST_MAX_EPISODES", 3),
+		STTBaseURL:                 getEnvOrDefault("STT_API_URL", defaultSTTBaseURL),
+		STTAPIKey:                  getEnvOrDefault("STT_API_KEY", ""),
+		STTModel:                   getEnvOrDefault("STT_MODEL", "whisper-1"),
+		STTMaxAudioMB:              getEnvInt("STT_MAX_AUDIO_MB", 25),
+		ConfluenceBaseURL:          getEnvOrDefault("CONFLUENCE_BASE_URL", ""),
+		ConfluenceEmail:            getEnvOrDefault("CONFLUENCE_EMAIL", ""),
+		ConfluenceAPIToken:         getEnvOrDefault("CONFLUENCE_API_TOKEN", ""),
+		GoogleDocsAuth:             getEnvOrDefault("GOOGLE_DOCS_AUTH", ""),
+		HeadlineRewriteSources:     getEnvList("HEADLINE_REWRITE_SOURCES"),
+		OpsThreadFeeds:             getEnvList("OPS_THREAD_FEEDS"),
+		OpsThreadChannel:           getEnvOrDefault("OPS_THREAD_CHANNEL", "#article-summarizer-ops"),
+		FeedOwners:                 make(map[string]string),
+		FeedEscalationRuns:         getEnvInt("FEED_ESCALATION_RUNS", 3),
+		BreakingFeeds:              getEnvList("BREAKING_FEEDS"),
+		BreakingKeywords:           getEnvList("BREAKING_KEYWORDS"),
+		BreakingMaxAge:             time.Duration(getEnvInt("BREAKING_MAX_AGE_MINUTES", 120)) * time.Minute,
+		ShardSize:                  getEnvInt("SHARD_SIZE", 0),
+		ShardInterval:              time.Duration(getEnvInt("SHARD_INTERVAL_SECONDS", 600)) * time.Second,
+		CloudTasksQueue:            getEnvOrDefault("CLOUD_TASKS_QUEUE", ""),
+		ShardTargetURL:             strings.TrimRight(getEnvOrDefault("SHARD_TARGET_URL", ""), "/"),
+		MarkdownOutput:             getEnvOrDefault("MARKDOWN_OUTPUT", ""),
+		SummaryFeedEnabled:         getEnvOrDefault("SUMMARY_FEED_ENABLED", "false") == "true",
+		SummaryArchiveEnabled:      getEnvOrDefault("SUMMARY_ARCHIVE_ENABLED", "false") == "true",
+		ReadCacheTTL:               time.Duration(getEnvInt("READ_CACHE_TTL_SECONDS", 10)) * time.Second,
+		ReadCacheEntries:           getEnvInt("READ_CACHE_ENTRIES", 256),
+		EmailSMTPHost:              getEnvOrDefault("EMAIL_SMTP_HOST", ""),
+		EmailSMTPPort:              getEnvOrDefault("EMAIL_SMTP_PORT", "587"),
+		EmailSMTPUsername:          getEnvOrDefault("EMAIL_SMTP_USERNAME", ""),
+		EmailSMTPPassword:          getEnvOrDefault("EMAIL_SMTP_PASSWORD", ""),
+		EmailFrom:                  getEnvOrDefault("EMAIL_FROM", ""),
+		EmailTo:                    getEnvList("EMAIL_TO"),
+		EmailDigestMode:            getEnvOrDefault("EMAIL_DIGEST_MODE", "per_feed"),
+		TelegramBotToken:           getEnvOrDefault("TELEGRAM_BOT_TOKEN", ""),
+		TelegramChatID:             getEnvOrDefault("TELEGRAM_CHAT_ID", ""),
+		TelegramBaseURL:            getEnvOrDefault("TELEGRAM_BASE_URL", "https://api.telegram.org"),
+		NotionToken:                getEnvOrDefault("NOTION_TOKEN", ""),
+		NotionDatabaseID:           getEnvOrDefault("NOTION_DATABASE_ID", ""),
+		NotionBaseURL:              getEnvOrDefault("NOTION_BASE_URL", "https://api.notion.com/v1"),
+		OutboundWebhookSecret:      getEnvOrDefault("OUTBOUND_WEBHOOK_SECRET", ""),
+		OutboundWebhookMaxAttempts: getEnvInt("OUTBOUND_WEBHOOK_MAX_ATTEMPTS", 3),
+
+		PromptExperimentVariants: getEnvList("PROMPT_EXPERIMENT_VARIANTS"),
+		PromptVariantReddit:      getEnvOrDefault("PROMPT_VARIANT_REDDIT", ""),
+		PromptVariantHatena:      getEnvOrDefault("PROMPT_VARIANT_HATENA", ""),
+		PromptVariantLobsters:    getEnvOrDefault("PROMPT_VARIANT_LOBSTERS", ""),
+		PromptVariantHackerNews:  getEnvOrDefault("PROMPT_VARIANT_HACKERNEWS", ""),
+
+		Notifiers:           make(map[string]string),
+		SlackMirrorChannels: make(map[string][]string),
+		DiscordWebhookURLs:  make(map[string]string),
+		OutboundWebhookURLs: make(map[string]string),
+		SummaryTranslations: make(map[string]string),
+		ActiveWindows:       make(map[string]string),
+
+		ProcessedRetentionDays: getEnvInt("PROCESSED_RETENTION_DAYS", 0),
+		DedupTTLDays:           make(map[string]int),
+
+		BacklogDrainLimit:    getEnvInt("BACKLOG_DRAIN_LIMIT", 3),
+		BacklogDrainInterval: time.Duration(getEnvInt("BACKLOG_DRAIN_INTERVAL_SECONDS", 10)) * time.Second,
+
+		LeaderElectionEnabled: getEnvOrDefault("LEADER_ELECTION_ENABLED", "false") == "true",
+		LeaderID:              getEnvOrDefault("LEADER_ID", hostname()),
+		LeaderLeaseTTL:        time.Duration(getEnvInt("LEADER_LEASE_TTL_SECONDS", 30)) * time.Second,
+
+		ArticleConcurrencyMin: getEnvInt("ARTICLE_CONCURRENCY_MIN", 1),
+		ArticleConcurrencyMax: getEnvInt("ARTICLE_CONCURRENCY_MAX", 3),
+		ArticleLatencyTarget:  time.Duration(getEnvInt("ARTICLE_LATENCY_TARGET_SECONDS", 60)) * time.Second,
+
+		SimulationArticleLimit: getEnvInt("SIMULATION_ARTICLE_LIMIT", 2),
+	}
+
+	for _, feed := range NotifierFeeds {
+		suffix := strings.ToUpper(feed)
+		config.Notifiers[feed] = getEnvOrDefault("NOTIFIER_"+suffix, "slack")
+		config.DiscordWebhookURLs[feed] = getEnvOrDefault("DISCORD_WEBHOOK_URL_"+suffix, "")
+		config.OutboundWebhookURLs[feed] = getEnvOrDefault("OUTBOUND_WEBHOOK_URL_"+suffix, "")
+		config.SlackMirrorChannels[feed] = getEnvList("SLACK_MIRROR_CHANNELS_" + suffix)
+		if language := getEnvOrDefault("SUMMARY_TRANSLATION_"+suffix, ""); language != "" {
+			config.SummaryTranslations[feed] = language
+		}
+	}
+	for _, feed := range ScheduledFeeds {
+		config.ActiveWindows[feed] = getEnvOrDefault("ACTIVE_WINDOW_"+strings.ToUpper(feed), "")
+		if owner := getEnvOrDefault("FEED_OWNER_"+strings.ToUpper(feed), ""); owner != "" {
+			config.FeedOwners[feed] = owner
+		}
+		if days := getEnvInt("DEDUP_TTL_DAYS_"+strings.ToUpper(feed), 0); days > 0 {
+			config.DedupTTLDays[feed] = days
+		}
 	}
 
+	if override != nil {
+		override(config)
+	}
 	return config, config.validate()
 }
 
+// ValidateServerAuth checks that the HTTP server can authenticate callers. An empty
+// WEBHOOK_AUTH_TOKEN used to accept any Bearer header; with scoped tokens it would answer 401
+// to every call instead, so the server refuses to start without a token. CLI tools don't need one.
+func (c *Config) ValidateServerAuth() error {
+	if c.AuthMode == AuthModeToken && c.WebhookAuthToken == "" && c.AuthTokens == "" {
+		return &ConfigError{Field: "WEBHOOK_AUTH_TOKEN", Message: "token is required when AUTH_MODE=token (set WEBHOOK_AUTH_TOKEN or AUTH_TOKENS; an empty token no longer accepts any Bearer header)"}
+	}
+	return nil
+}
+
 // validate checks if required configuration values are present
 func (c *Config) validate() error {
-	if c.GeminiAPIKey == "" {
+	if _, err := middleware.ParseTokenScopes(c.AuthTokens); err != nil {
+		return &ConfigError{Field: "AUTH_TOKENS", Message: err.Error()}
+	}
+	if _, err := middleware.ParseTokenScopes(c.IdentityScopes); err != nil {
+		return &ConfigError{Field: "IDENTITY_SCOPES", Message: err.Error()}
+	}
+
+	switch c.AuthMode {
+	case AuthModeToken, AuthModeMTLS:
+	case AuthModeIAP:
+		if c.IAPAudience == "" {
+			return &ConfigError{Field: "IAP_AUDIENCE", Message: "audience is required when AUTH_MODE=iap"}
+		}
+	default:
+		return &ConfigError{Field: "AUTH_MODE", Message: "must be one of token, iap, mtls"}
+	}
+
+	if c.ReadCacheTTL < 0 {
+		return &ConfigError{Field: "READ_CACHE_TTL_SECONDS", Message: "cache TTL must not be negative"}
+	}
+	if c.ReadCacheTTL > 0 && c.ReadCacheEntries <= 0 {
+		return &ConfigError{Field: "READ_CACHE_ENTRIES", Message: "at least one cached response is required"}
+	}
+
+	switch c.ServiceMode {
+	case ServiceModeFull:
+	case ServiceModeReadOnly:
+		// Processing is disabled, so Gemini and notifier credentials are not needed
+		return nil
+	case ServiceModeSimulation:
+		if c.SimulationArticleLimit < 1 {
+			return &ConfigError{Field: "SIMULATION_ARTICLE_LIMIT", Message: "must be at least 1"}
+		}
+	default:
+		return &ConfigError{Field: "SERVICE_MODE", Message: "must be one of full, readonly, simulation"}
+	}
+
+	if _, err := repository.ParseExtractionRules(c.ExtractionRules); err != nil {
+		return &ConfigError{Field: "EXTRACTION_RULES", Message: err.Error()}
+	}
+	if err := repository.ValidateSummaryLanguage(c.SummaryLanguage); err != nil {
+		return &ConfigError{Field: "SUMMARY_LANGUAGE", Message: err.Error()}
+	}
+	for feed, language := range c.SummaryTranslations {
+		if err := repository.ValidateTranslationLanguage(language); err != nil {
+			return &ConfigError{Field: "SUMMARY_TRANSLATION_" + strings.ToUpper(feed), Message: err.Error()}
+		}
+	}
+	if err := repository.ValidateSlackLocale(c.SlackLocale); err != nil {
+		return &ConfigError{Field: "SLACK_LOCALE", Message: err.Error()}
+	}
+	if err := repository.ValidateCommentSummaryMode(c.CommentSummaryMode); err != nil {
+		return &ConfigError{Field: "COMMENT_SUMMARY_MODE", Message: err.Error()}
+	}
+
+	if _, err := repository.ParseRedactionRules(c.RedactionRules); err != nil {
+		return &ConfigError{Field: "REDACTION_RULES", Message: err.Error()}
+	}
+
+	if c.ConfluenceBaseURL != "" {
+		if _, err := repository.NewConfluenceFetcher(c.ConfluenceBaseURL, c.ConfluenceEmail, c.ConfluenceAPIToken, nil); err != nil {
+			return &ConfigError{Field: "CONFLUENCE_BASE_URL", Message: err.Error()}
+		}
+		if c.ConfluenceEmail == "" || c.ConfluenceAPIToken == "" {
+			return &ConfigError{Field: "CONFLUENCE_API_TOKEN", Message: "CONFLUENCE_EMAIL and CONFLUENCE_API_TOKEN are required when CONFLUENCE_BASE_URL is set"}
+		}
+	}
+	if c.GoogleDocsAuth != "" && c.GoogleDocsAuth != "adc" {
+		if _, err := os.Stat(c.GoogleDocsAuth); err != nil {
+			return &ConfigError{Field: "GOOGLE_DOCS_AUTH", Message: "must be adc or a readable credentials file: " + err.Error()}
+		}
+	}
+
+	if _, err := rss.ParseRedditSubreddits(c.RedditSubreddits); err != nil {
+		return &ConfigError{Field: "REDDIT_SUBREDDITS", Message: err.Error()}
+	}
+	if c.RedditClientID != "" || c.RedditClientSecret != "" || c.RedditUsername != "" || c.RedditPassword != "" {
+		for _, credential := range []struct{ field, value string }{
+			{"REDDIT_CLIENT_ID", c.RedditClientID},
+			{"REDDIT_CLIENT_SECRET", c.RedditClientSecret},
+			{"REDDIT_USERNAME", c.RedditUsername},
+			{"REDDIT_PASSWORD", c.RedditPassword},
+		} {
+			if credential.value == "" {
+				return &ConfigError{Field: credential.field, Message: "all Reddit app credentials are required to use the Reddit OAuth API"}
+			}
+		}
+	}
+	if err := rss.ValidateArxivCategories(c.ArxivCategories); err != nil {
+		return &ConfigError{Field: "ARXIV_CATEGORIES", Message: err.Error()}
+	}
+	if err := rss.ValidateYouTubeChannelIDs(c.YouTubeChannelIDs); err != nil {
+		return &ConfigError{Field: "YOUTUBE_CHANNEL_IDS", Message: err.Error()}
+	}
+	if err := rss.ValidateXAccounts(c.XAccounts); err != nil {
+		return &ConfigError{Field: "X_ACCOUNTS", Message: err.Error()}
+	}
+	if len(c.XAccounts) > 0 && c.XBearerToken == "" && c.XBridgeURL == "" {
+		return &ConfigError{Field: "X_ACCOUNTS", Message: "X_BEARER_TOKEN or X_BRIDGE_URL is required to read X accounts"}
+	}
+	if err := rss.ValidatePodcastFeeds(c.PodcastFeeds); err != nil {
+		return &ConfigError{Field: "PODCAST_FEEDS", Message: err.Error()}
+	}
+	if len(c.PodcastFeeds) > 0 && c.STTAPIKey == "" && c.STTBaseURL == defaultSTTBaseURL {
+		return &ConfigError{Field: "STT_API_KEY", Message: "STT API key is required to transcribe podcasts with the default STT_API_URL"}
+	}
+
+	if _, err := time.LoadLocation(c.FeedTimezone); err != nil {
+		return &ConfigError{Field: "FEED_TIMEZONE", Message: "unknown time zone " + c.FeedTimezone}
+	}
+	for _, feed := range ScheduledFeeds {
+		if _, err := rss.ParseActiveWindow(c.ActiveWindows[feed]); err != nil {
+			return &ConfigError{Field: "ACTIVE_WINDOW_" + strings.ToUpper(feed), Message: err.Error()}
+		}
+		if err := repository.ValidateFeedOwner(c.FeedOwners[feed]); err != nil {
+			return &ConfigError{Field: "FEED_OWNER_" + strings.ToUpper(feed), Message: err.Error()}
+		}
+	}
+	if c.LeaderElectionEnabled {
+		if c.LeaderLeaseTTL < 3*time.Second {
+			return &ConfigError{Field: "LEADER_LEASE_TTL_SECONDS", Message: "lease TTL must be at least 3 seconds"}
+		}
+		if c.LeaderID == "" {
+			return &ConfigError{Field: "LEADER_ID", Message: "replica ID is required when the host name is unknown"}
+		}
+	}
+
+	genericFeeds, err := rss.ParseGenericFeedSpecs(c.GenericFeeds)
+	if err != nil {
+		return &ConfigError{Field: "GENERIC_FEEDS", Message: err.Error()}
+	}
+	for _, spec := range genericFeeds {
+		// Generic feed names double as article sources and backlog keys
+		if slices.Contains(NotifierFeeds, spec.Name) {
+			return &ConfigError{Field: "GENERIC_FEEDS", Message: "feed name " + spec.Name + " is reserved for a built-in feed"}
+		}
+	}
+
+	for _, feed := range c.BreakingFeeds {
+		generic := slices.ContainsFunc(genericFeeds, func(spec rss.GenericFeedSpec) bool { return spec.Name == feed })
+		if !slices.Contains(ScheduledFeeds, feed) && !generic {
+			return &ConfigError{Field: "BREAKING_FEEDS", Message: "unknown feed " + feed + " (must be a scheduled feed or a GENERIC_FEEDS name)"}
+		}
+	}
+	if (len(c.BreakingFeeds) > 0 || len(c.BreakingKeywords) > 0) && c.BreakingMaxAge <= 0 {
+		return &ConfigError{Field: "BREAKING_MAX_AGE_MINUTES", Message: "max age must be positive"}
+	}
+
+	if c.ShardSize < 0 {
+		return &ConfigError{Field: "SHARD_SIZE", Message: "shard size must not be negative"}
+	}
+	if c.ShardSize > 0 && c.ShardInterval <= 0 {
+		return &ConfigError{Field: "SHARD_INTERVAL_SECONDS", Message: "shard interval must be positive"}
+	}
+	if c.ShardSize > 0 && c.CloudTasksQueue != "" {
+		if err := repository.ValidateCloudTasksQueue(c.CloudTasksQueue); err != nil {
+			return &ConfigError{Field: "CLOUD_TASKS_QUEUE", Message: err.Error()}
+		}
+		if c.ShardTargetURL == "" {
+			return &ConfigError{Field: "SHARD_TARGET_URL", Message: "target URL is required when CLOUD_TASKS_QUEUE is set"}
+		}
+		// Tasks authenticate with the legacy token, which keeps the process scope
+		if c.WebhookAuthToken == "" {
+			return &ConfigError{Field: "WEBHOOK_AUTH_TOKEN", Message: "token is required for the follow-up tasks when CLOUD_TASKS_QUEUE is set"}
+		}
+	}
+
+	for _, source := range c.HeadlineRewriteSources {
+		switch source {
+		case "reddit", "hatena", "lobsters", "hackernews", "sitemap":
+		default:
+			return &ConfigError{Field: "HEADLINE_REWRITE_SOURCES", Message: "unknown source " + source + " (must be reddit, hatena, lobsters, hackernews or sitemap)"}
+		}
+	}
+
+	if c.SlackActionsEnabled && c.SlackSigningSecret == "" {
+		return &ConfigError{Field: "SLACK_SIGNING_SECRET", Message: "signing secret is required when SLACK_ACTIONS_ENABLED=true"}
+	}
+
+	if c.SlackMaxPostsPerHour < 0 {
+		return &ConfigError{Field: "SLACK_CHANNEL_MAX_POSTS_PER_HOUR", Message: "must not be negative"}
+	}
+	if err := repository.ValidateChannelBudgetOverflow(c.SlackBudgetOverflow); err != nil {
+		return &ConfigError{Field: "SLACK_CHANNEL_BUDGET_OVERFLOW", Message: err.Error()}
+	}
+
+	channelLevels, err := repository.ParseChannelLevels(c.SlackChannelLevels)
+	if err != nil {
+		return &ConfigError{Field: "SLACK_CHANNEL_LEVELS", Message: err.Error()}
+	}
+	// Without classification every article would be held back from the limited channels
+	if len(channelLevels) > 0 && !c.DifficultyEnabled {
+		return &ConfigError{Field: "DIFFICULTY_ENABLED", Message: "classification must be enabled when SLACK_CHANNEL_LEVELS is set"}
+	}
+
+	for _, feed := range c.OpsThreadFeeds {
+		switch feed {
+		case "reddit", "hatena", "lobsters", "hackernews":
+		default:
+			return &ConfigError{Field: "OPS_THREAD_FEEDS", Message: "unknown feed " + feed + " (must be reddit, hatena, lobsters or hackernews)"}
+		}
+	}
+
+	regions, err := repository.ParseRegions(c.GeminiRegions)
+	if err != nil {
+		return &ConfigError{Field: "GEMINI_REGIONS", Message: err.Error()}
+	}
+	if len(regions) > 0 {
+		// Vertex AI authenticates with the service account, so no API key is needed
+		if c.VertexProject == "" {
+			return &ConfigError{Field: "VERTEX_PROJECT", Message: "Vertex AI project is required when GEMINI_REGIONS is set"}
+		}
+	} else if c.GeminiAPIKey == "" && c.ServiceMode != ServiceModeSimulation { // Simulation runs offline with a stub summarizer
 		return &ConfigError{Field: "GEMINI_API_KEY", Message: "Gemini API key is required"}
 	}
-	if c.SlackBotToken == "" {
-		return &ConfigError{Field: "SLACK_BOT_TOKEN", Message: "Slack bot token is required"}
+
+	if c.OnDemandStreamWorkers < 1 {
+		return &ConfigError{Field: "ONDEMAND_STREAM_WORKERS", Message: "must be at least 1"}
+	}
+
+	if c.ArticleConcurrencyMin < 1 {
+		return &ConfigError{Field: "ARTICLE_CONCURRENCY_MIN", Message: "must be at least 1"}
+	}
+	if c.ArticleConcurrencyMax < c.ArticleConcurrencyMin {
+		return &ConfigError{Field: "ARTICLE_CONCURRENCY_MAX", Message: "must not be less than ARTICLE_CONCURRENCY_MIN"}
 	}
-	if !strings.HasPrefix(c.SlackBotToken, "xoxb-") {
-		return &ConfigError{Field: "SLACK_BOT_TOKEN", Message: "must start with xoxb-"}
+
+	// Simulation notifiers are dry-run, so no notifier credentials are needed
+	if c.ServiceMode == ServiceModeSimulation {
+		return nil
+	}
+
+	usesSlack, usesTelegram, usesEmail := false, false, false
+	for _, feed := range NotifierFeeds {
+		suffix := strings.ToUpper(feed)
+		switch c.Notifiers[feed] {
+		case "slack":
+			usesSlack = true
+		case "discord":
+			if c.DiscordWebhookURLs[feed] == "" {
+				return &ConfigError{Field: "DISCORD_WEBHOOK_URL_" + suffix, Message: "Discord webhook URL is required when NOTIFIER_" + suffix + "=discord"}
+			}
+		case "telegram":
+			usesTelegram = true
+		case "email":
+			usesEmail = true
+		case "webhook":
+			if c.OutboundWebhookURLs[feed] == "" {
+				return &ConfigError{Field: "OUTBOUND_WEBHOOK_URL_" + suffix, Message: "outbound webhook URL is required when NOTIFIER_" + suffix + "=webhook"}
+			}
+		case "markdown":
+			if c.MarkdownOutput == "" {
+				return &ConfigError{Field: "MARKDOWN_OUTPUT", Message: "output directory or gs:// / s3:// prefix is required when NOTIFIER_" + suffix + "=markdown"}
+			}
+		default:
+			return &ConfigError{Field: "NOTIFIER_" + suffix, Message: "must be one of slack, discord, telegram, email, webhook, markdown"}
+		}
+		if len(c.SlackMirrorChannels[feed]) > 0 {
+			usesSlack = true
+		}
+	}
+	if len(c.OpsThreadFeeds) > 0 {
+		if c.OpsThreadChannel == "" {
+			return &ConfigError{Field: "OPS_THREAD_CHANNEL", Message: "Slack channel is required when OPS_THREAD_FEEDS is set"}
+		}
+		usesSlack = true
+	}
+	if c.FeedEscalationRuns > 0 && len(c.feedOwners(genericFeeds)) > 0 {
+		// Owners are alerted in the ops channel
+		if c.OpsThreadChannel == "" {
+			return &ConfigError{Field: "OPS_THREAD_CHANNEL", Message: "Slack channel is required to alert feed owners"}
+		}
+		usesSlack = true
+	}
+
+	if usesEmail {
+		if c.EmailSMTPHost == "" {
+			return &ConfigError{Field: "EMAIL_SMTP_HOST", Message: "SMTP host is required"}
+		}
+		if c.EmailFrom == "" {
+			return &ConfigError{Field: "EMAIL_FROM", Message: "sender address is required"}
+		}
+		if len(c.EmailTo) == 0 {
+			return &ConfigError{Field: "EMAIL_TO", Message: "at least one recipient is required"}
+		}
+		if c.EmailDigestMode != "per_feed" && c.EmailDigestMode != "combined" {
+			return &ConfigError{Field: "EMAIL_DIGEST_MODE", Message: "must be one of per_feed, combined"}
+		}
+	}
+
+	if c.NotionDatabaseID != "" && c.NotionToken == "" {
+		return &ConfigError{Field: "NOTION_TOKEN", Message: "Notion integration token is required when NOTION_DATABASE_ID is set"}
+	}
+
+	if usesTelegram {
+		if c.TelegramBotToken == "" {
+			return &ConfigError{Field: "TELEGRAM_BOT_TOKEN", Message: "Telegram bot token is required"}
+		}
+		if c.TelegramChatID == "" {
+			return &ConfigError{Field: "TELEGRAM_CHAT_ID", Message: "Telegram chat id is required"}
+		}
+	}
+
+	// Slack token is only required when at least one feed notifies Slack
+	if usesSlack {
+		if c.SlackBotToken == "" {
+			return &ConfigError{Field: "SLACK_BOT_TOKEN", Message: "Slack bot token is required"}
+		}
+		if !strings.HasPrefix(c.SlackBotToken, "xoxb-") {
+			return &ConfigError{Field: "SLACK_BOT_TOKEN", Message: "must start with xoxb-"}
+		}
 	}
 	return nil
 }
 
+// usesNotifier reports whether any feed selects the given notifier kind
+func (c *Config) usesNotifier(kind string) bool {
+	for _, feed := range NotifierFeeds {
+		if c.Notifiers[feed] == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// feedLocation returns the time zone of the feeds' active windows (validated in validate)
+func (c *Config) feedLocation() *time.Location {
+	location, err := time.LoadLocation(c.FeedTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// feedOwners returns the owners of the built-in feeds (FEED_OWNER_<FEED>) and of GENERIC_FEEDS, by feed name
+func (c *Config) feedOwners(genericFeeds []rss.GenericFeedSpec) map[string]string {
+	owners := make(map[string]string, len(c.FeedOwners)+len(genericFeeds))
+	for feed, owner := range c.FeedOwners {
+		owners[feed] = owner
+	}
+	for _, spec := range genericFeeds {
+		if spec.Owner != "" {
+			owners[spec.Name] = spec.Owner
+		}
+	}
+	return owners
+}
+
+// dedupTTLs returns the dedup TTLs of the built-in feeds (DEDUP_TTL_DAYS_<FEED>) and of GENERIC_FEEDS, by feed name
+func (c *Config) dedupTTLs(genericFeeds []rss.GenericFeedSpec) map[string]time.Duration {
+	ttls := make(map[string]time.Duration, len(c.DedupTTLDays)+len(genericFeeds))
+	for feed, days := range c.DedupTTLDays {
+		ttls[feed] = time.Duration(days) * 24 * time.Hour
+	}
+	for _, spec := range genericFeeds {
+		if spec.DedupTTLDays > 0 {
+			ttls[spec.Name] = time.Duration(spec.DedupTTLDays) * 24 * time.Hour
+		}
+	}
+	return ttls
+}
+
+// shardTargetURL returns the process endpoint a follow-up shard task calls for feed; built-in feeds are
+// forced past their active window and leader checks like a manual run
+func (c *Config) shardTargetURL(feed string) string {
+	if slices.Contains(ScheduledFeeds, feed) {
+		return c.ShardTargetURL + "/process/" + feed + "?force=true"
+	}
+	return c.ShardTargetURL + "/process/feeds/" + url.PathEscape(feed)
+}
+
 // getEnvOrDefault returns environment variable value or default if not set
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -72,6 +839,40 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// hostname identifies the replica by default (the pod or container name)
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// getEnvInt returns an integer environment variable or default if not set or invalid
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList returns a comma-separated environment variable as a trimmed list (nil if not set)
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // ConfigError represents a configuration error
 type ConfigError struct {
 	Field   string
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock arXiv API fetcher (Atom query response)
+type MockArxivRepo struct{}
+
+func (m *MockArxivRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	if !strings.Contains(url, "search_query=") {
+		return "", fmt.Errorf("unexpected status code: 404")
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>http://arxiv.org/abs/2401.00001v1</id>
+    <published>2024-01-01T00:00:00Z</published>
+    <title>Test arXiv
+  Paper</title>
+    <summary>  We study a test problem
+  and report results.</summary>
+    <author><name>Alice Example</name></author>
+    <author><name>Bob Example</name></author>
+    <link href="http://arxiv.org/abs/2401.00001v1" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/2401.00001v1" rel="related" type="application/pdf"/>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`, nil
+}
+
+func (m *MockArxivRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
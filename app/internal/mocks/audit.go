@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Audit Repository
+type MockAuditRepo struct {
+	Entries []repository.AuditEntry
+	Err     error
+}
+
+func (m *MockAuditRepo) Record(ctx context.Context, entry repository.AuditEntry) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+func (m *MockAuditRepo) List(ctx context.Context, limit int) ([]*repository.AuditEntry, error) {
+	var entries []*repository.AuditEntry
+	for i := len(m.Entries) - 1; i >= 0 && (limit <= 0 || len(entries) < limit); i-- {
+		entry := m.Entries[i]
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (m *MockAuditRepo) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Backlog Repository
+type MockBacklogRepo struct {
+	Entries []*repository.BacklogEntry
+	Removed []string
+}
+
+func (m *MockBacklogRepo) Record(ctx context.Context, feed string, article repository.Item, cause error) error {
+	for _, entry := range m.Entries {
+		if entry.Feed == feed && entry.Item.Link == article.Link {
+			entry.Attempts++
+			return nil
+		}
+	}
+	m.Entries = append(m.Entries, &repository.BacklogEntry{Feed: feed, Item: article, Attempts: 1})
+	return nil
+}
+
+func (m *MockBacklogRepo) List(ctx context.Context) ([]*repository.BacklogEntry, error) {
+	return m.Entries, nil
+}
+
+func (m *MockBacklogRepo) Remove(ctx context.Context, key string) error {
+	m.Removed = append(m.Removed, key)
+	return nil
+}
+
+func (m *MockBacklogRepo) Close() error {
+	return nil
+}
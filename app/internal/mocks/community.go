@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Dev.to, Qiita, Zenn and Product Hunt fetcher (one popular post each)
+type MockCommunityRepo struct{}
+
+func (m *MockCommunityRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	switch {
+	case strings.Contains(url, "dev.to/api/articles"):
+		return `[{"id":1,"title":"Test Dev.to Post","url":"https://dev.to/test/post","published_at":"2024-01-01T00:00:00Z",
+"tag_list":["go","testing"],"positive_reactions_count":100,"user":{"name":"Test Author"}}]`, nil
+	case strings.Contains(url, "qiita.com/api/v2/items"):
+		return `[{"id":"abc","title":"Test Qiita Item","url":"https://qiita.com/test/items/abc","created_at":"2024-01-01T09:00:00+09:00",
+"likes_count":100,"tags":[{"name":"Go"},{"name":"テスト"}],"user":{"id":"test"}}]`, nil
+	case strings.Contains(url, "zenn.dev/api/articles"):
+		return `{"articles":[{"title":"Test Zenn Article","path":"/test/articles/post","article_type":"tech","liked_count":100,
+"published_at":"2024-01-01T09:00:00.000+09:00","user":{"name":"Test Author"}}]}`, nil
+	case strings.Contains(url, "producthunt.com/feed"):
+		// Launched at noon of the previous Product Hunt day
+		location, err := time.LoadLocation("America/Los_Angeles")
+		if err != nil {
+			location = time.UTC
+		}
+		now := time.Now().In(location)
+		launched := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location).Add(-12 * time.Hour)
+		return `<?xml version="1.0" encoding="UTF-8"?><feed xmlns="http://www.w3.org/2005/Atom">
+<entry><id>tag:www.producthunt.com,2005:Post/1</id><title>Test Product</title>
+<link rel="alternate" type="text/html" href="https://www.producthunt.com/products/test-product"/>
+<published>` + launched.Format(time.RFC3339) + `</published><content type="html">A test product</content></entry></feed>`, nil
+	}
+	return "", fmt.Errorf("unexpected status code: 404")
+}
+
+func (m *MockCommunityRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Feed Stats Repository
+type MockFeedStatsRepo struct {
+	Stats map[string]*repository.FeedStats
+}
+
+func (m *MockFeedStatsRepo) Load(ctx context.Context, feed string) (*repository.FeedStats, error) {
+	if stats, ok := m.Stats[feed]; ok {
+		copied := *stats
+		return &copied, nil
+	}
+	return &repository.FeedStats{Feed: feed}, nil
+}
+
+func (m *MockFeedStatsRepo) Save(ctx context.Context, stats *repository.FeedStats) error {
+	if m.Stats == nil {
+		m.Stats = make(map[string]*repository.FeedStats)
+	}
+	copied := *stats
+	m.Stats[stats.Feed] = &copied
+	return nil
+}
+
+func (m *MockFeedStatsRepo) Close() error {
+	return nil
+}
@@ -25,6 +25,18 @@ func (m *MockGeminiRepo) SummarizeComments(ctx context.Context, text string) (*r
 	return &repository.SummarizeResponse{Summary: "test comment summary", ContentChars: 3000}, nil
 }
 
+func (m *MockGeminiRepo) RewriteHeadline(ctx context.Context, title, summary string) (string, error) {
+	return "test headline", nil
+}
+
 func (m *MockGeminiRepo) SummarizeOnDemand(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
 	return &repository.SummarizeResponse{Summary: "test summary", ContentChars: 2500}, nil
 }
+
+func (m *MockGeminiRepo) SummarizeRendered(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	return nil, repository.ErrRenderFallbackDisabled
+}
+
+func (m *MockGeminiRepo) TranslateSummary(ctx context.Context, summary, language string) (string, error) {
+	return "test translation", nil
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Hacker News API fetcher (Firebase story list/items and Algolia comment tree)
+type MockHackerNewsRepo struct {
+	ShouldFailComments bool
+}
+
+func (m *MockHackerNewsRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	switch {
+	case strings.HasSuffix(url, "/topstories.json"), strings.HasSuffix(url, "/beststories.json"):
+		return `[1001, 1002]`, nil
+	case strings.HasSuffix(url, "/item/1001.json"):
+		return `{"id":1001,"type":"story","by":"user1","time":1704067200,"title":"Test Hacker News Article","url":"https://example.com/article","score":250,"descendants":2}`, nil
+	case strings.HasSuffix(url, "/item/1002.json"):
+		return `{"id":1002,"type":"story","by":"user2","time":1704067200,"title":"Low Score Article","url":"https://example.com/low","score":3}`, nil
+	case strings.HasSuffix(url, "/items/1001"):
+		if m.ShouldFailComments {
+			return "", fmt.Errorf("mock comment fetch error")
+		}
+		return `{"id":1001,"children":[{"author":"user2","text":"<p>Interesting discussion</p>","children":[{"author":"user1","text":"Thanks &amp; welcome","children":[]}]}]}`, nil
+	}
+	return "", fmt.Errorf("unexpected status code: 404")
+}
+
+func (m *MockHackerNewsRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
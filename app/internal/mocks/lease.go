@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Mock Lease Repository shared by several electors, like replicas sharing a bucket
+type MockLeaseRepo struct {
+	mu      sync.Mutex
+	Holders map[int64]string // Slot start (Unix seconds) -> holder
+	Err     error
+}
+
+func (m *MockLeaseRepo) Claim(ctx context.Context, start time.Time, holder string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return "", m.Err
+	}
+	if m.Holders == nil {
+		m.Holders = make(map[int64]string)
+	}
+	if current, ok := m.Holders[start.Unix()]; ok {
+		return current, nil
+	}
+	m.Holders[start.Unix()] = holder
+	return holder, nil
+}
+
+func (m *MockLeaseRepo) Close() error {
+	return nil
+}
@@ -1,12 +1,14 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/pep299/article-summarizer-v3/internal/repository"
 )
 
 // Mock Limiter
 type MockLimiter struct{}
 
-func (m *MockLimiter) Limit(articles []repository.Item) []repository.Item {
+func (m *MockLimiter) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
 	return articles
 }
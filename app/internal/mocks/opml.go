@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+)
+
+// Mock OPML source serving a fixed document
+type MockOPMLSource struct {
+	Content    string
+	ShouldFail bool
+}
+
+func (m *MockOPMLSource) Load(ctx context.Context) ([]byte, error) {
+	if m.ShouldFail {
+		return nil, fmt.Errorf("mock OPML load error")
+	}
+	return []byte(m.Content), nil
+}
+
+func (m *MockOPMLSource) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+)
+
+// Mock Ops Thread
+type MockOpsThread struct {
+	mu      sync.Mutex
+	Parents []string
+	Replies []string
+}
+
+func (m *MockOpsThread) Start(ctx context.Context, text string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Parents = append(m.Parents, text)
+	return "1700000000.000100", nil
+}
+
+func (m *MockOpsThread) Reply(ctx context.Context, threadID, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Replies = append(m.Replies, text)
+	return nil
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock podcast feed fetcher (one episode published an hour ago)
+type MockPodcastRepo struct{}
+
+func (m *MockPodcastRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	if !strings.HasSuffix(url, "/podcast.xml") {
+		return "", fmt.Errorf("unexpected status code: 404")
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+<channel>
+  <title>Test Show</title>
+  <item>
+    <title>Test Episode</title>
+    <link>https://podcast.example.com/episodes/1</link>
+    <description>Show notes of the test episode, covering testing, tooling and a guest interview about writing reliable software.</description>
+    <pubDate>%s</pubDate>
+    <guid>episode-1</guid>
+    <enclosure url="https://cdn.example.com/episode-1.mp3" type="audio/mpeg" length="1000"/>
+  </item>
+</channel>
+</rss>`, time.Now().Add(-time.Hour).Format(time.RFC1123Z)), nil
+}
+
+func (m *MockPodcastRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
+
+// MockTranscriber records the transcribed audio URLs
+type MockTranscriber struct {
+	Transcript string
+	Err        error
+	AudioURLs  []string
+}
+
+func (m *MockTranscriber) Transcribe(ctx context.Context, audioURL string) (string, error) {
+	m.AudioURLs = append(m.AudioURLs, audioURL)
+	return m.Transcript, m.Err
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/pep299/article-summarizer-v3/internal/repository"
 )
@@ -21,10 +22,34 @@ func (m *MockProcessedRepo) MarkAsProcessed(ctx context.Context, article reposit
 	return nil
 }
 
+func (m *MockProcessedRepo) MarkManyAsProcessed(ctx context.Context, articles []repository.Item) (int, error) {
+	return len(articles), nil
+}
+
+func (m *MockProcessedRepo) ExportEntries(ctx context.Context) ([]*repository.IndexEntry, error) {
+	return nil, nil
+}
+
+func (m *MockProcessedRepo) ImportEntries(ctx context.Context, entries []*repository.IndexEntry) (int, error) {
+	return len(entries), nil
+}
+
+func (m *MockProcessedRepo) UnmarkProcessed(ctx context.Context, article repository.Item) (bool, error) {
+	return false, nil
+}
+
+func (m *MockProcessedRepo) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	return 0, nil
+}
+
 func (m *MockProcessedRepo) GenerateKey(article repository.Item) string {
 	return article.Link
 }
 
+func (m *MockProcessedRepo) Flush(ctx context.Context) error {
+	return nil
+}
+
 func (m *MockProcessedRepo) Close() error {
 	return nil
 }
@@ -8,7 +8,8 @@ import (
 
 // Mock Slack Repository
 type MockSlackRepo struct {
-	SentNotifications []repository.Notification
+	SentNotifications     []repository.Notification
+	SentOnDemandSummaries []repository.SummarizeResponse
 }
 
 func (m *MockSlackRepo) Send(ctx context.Context, notification repository.Notification) error {
@@ -17,5 +18,6 @@ func (m *MockSlackRepo) Send(ctx context.Context, notification repository.Notifi
 }
 
 func (m *MockSlackRepo) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	m.SentOnDemandSummaries = append(m.SentOnDemandSummaries, summary)
 	return nil
 }
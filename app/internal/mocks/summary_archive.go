@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Summary Archive Repository: entries are keyed by the URL passed to Get, as is
+type MockSummaryArchiveRepo struct {
+	Entries       map[string]*repository.SummaryArchiveEntry
+	Notifications []repository.Notification
+	Updated       []*repository.SummaryArchiveEntry
+	Err           error
+}
+
+func (m *MockSummaryArchiveRepo) Send(ctx context.Context, notification repository.Notification) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Notifications = append(m.Notifications, notification)
+	return nil
+}
+
+func (m *MockSummaryArchiveRepo) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	return m.Send(ctx, repository.Notification{Title: article.Title, Source: repository.SourceOnDemand, URL: article.Link, Summary: summary.Summary})
+}
+
+func (m *MockSummaryArchiveRepo) Get(ctx context.Context, articleURL string) (*repository.SummaryArchiveEntry, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Entries[articleURL], nil
+}
+
+func (m *MockSummaryArchiveRepo) List(ctx context.Context, since time.Time) ([]*repository.SummaryArchiveEntry, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var entries []*repository.SummaryArchiveEntry
+	for _, entry := range m.Entries {
+		if !entry.ArchivedAt.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+	})
+	return entries, nil
+}
+
+// Update replaces the entry under its Link
+func (m *MockSummaryArchiveRepo) Update(ctx context.Context, entry *repository.SummaryArchiveEntry) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Updated = append(m.Updated, entry)
+	if m.Entries == nil {
+		m.Entries = map[string]*repository.SummaryArchiveEntry{}
+	}
+	m.Entries[entry.Link] = entry
+	return nil
+}
+
+func (m *MockSummaryArchiveRepo) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock Summary Feed Repository
+type MockSummaryFeedRepo struct {
+	Entries       []*repository.SummaryFeedEntry
+	Notifications []repository.Notification
+	Err           error
+}
+
+func (m *MockSummaryFeedRepo) Send(ctx context.Context, notification repository.Notification) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Notifications = append(m.Notifications, notification)
+	return nil
+}
+
+func (m *MockSummaryFeedRepo) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	return m.Send(ctx, repository.Notification{Title: article.Title, Source: repository.SourceOnDemand, URL: article.Link, Summary: summary.Summary})
+}
+
+func (m *MockSummaryFeedRepo) List(ctx context.Context) ([]*repository.SummaryFeedEntry, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Entries, nil
+}
+
+func (m *MockSummaryFeedRepo) Close() error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+)
+
+// Mock Thread Replier
+type MockThreadReplier struct {
+	mu      sync.Mutex
+	Replies []ThreadReply
+}
+
+// ThreadReply is a reply recorded by MockThreadReplier
+type ThreadReply struct {
+	Channel  string
+	ThreadTS string
+	Text     string
+}
+
+func (m *MockThreadReplier) ReplyInThread(ctx context.Context, channel, threadTS, text string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Replies = append(m.Replies, ThreadReply{Channel: channel, ThreadTS: threadTS, Text: text})
+	return nil
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"time"
+)
+
+// Mock Usage Repository
+type MockUsageRepo struct {
+	Counts map[string]int // user -> requests, regardless of day
+	Err    error
+}
+
+func (m *MockUsageRepo) Record(ctx context.Context, user string, t time.Time) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Counts == nil {
+		m.Counts = make(map[string]int)
+	}
+	m.Counts[user]++
+	return nil
+}
+
+func (m *MockUsageRepo) Count(ctx context.Context, user string, day time.Time) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	return m.Counts[user], nil
+}
+
+func (m *MockUsageRepo) Daily(ctx context.Context, day time.Time) (map[string]int, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	counts := make(map[string]int)
+	for user, count := range m.Counts {
+		counts[user] = count
+	}
+	return counts, nil
+}
+
+func (m *MockUsageRepo) Close() error {
+	return nil
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock X API v2 fetcher: every account has one post linking to an article and one without links
+type MockXAccountsRepo struct{}
+
+func (m *MockXAccountsRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	switch {
+	case strings.Contains(url, "/users/by/username/"):
+		return `{"data":{"id":"1"}}`, nil
+	case strings.Contains(url, "/users/1/tweets"):
+		return `{"data":[
+{"id":"100","text":"Worth reading https://t.co/abc","created_at":"2024-01-01T00:00:00Z","entities":{"urls":[{"expanded_url":"https://example.com/article"}]}},
+{"id":"101","text":"Good morning","created_at":"2024-01-01T01:00:00Z"}]}`, nil
+	}
+	return "", fmt.Errorf("unexpected status code: 404")
+}
+
+func (m *MockXAccountsRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
+
+// MockURLProcessor records the URLs sent through the on-demand pipeline
+type MockURLProcessor struct {
+	URLs []string
+	Err  error
+}
+
+func (m *MockURLProcessor) Process(ctx context.Context, url string) error {
+	m.URLs = append(m.URLs, url)
+	return m.Err
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Mock YouTube channel feed fetcher (one upload per channel)
+type MockYouTubeRepo struct{}
+
+func (m *MockYouTubeRepo) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	if !strings.Contains(url, "channel_id=") {
+		return "", fmt.Errorf("unexpected status code: 404")
+	}
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>yt:video:dQw4w9WgXcQ</id>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <title>Test Video</title>
+    <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+    <author><name>Test Channel</name></author>
+    <published>2024-01-01T00:00:00+00:00</published>
+    <media:group>
+      <media:description>A test video about testing.</media:description>
+    </media:group>
+  </entry>
+</feed>`, nil
+}
+
+func (m *MockYouTubeRepo) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const defaultAuditPrefix = "audit/"
+
+// AuditEntry records one administrative action
+type AuditEntry struct {
+	Time         time.Time         `json:"time"`
+	Action       string            `json:"action"`
+	ActorTokenID string            `json:"actor_token_id"`
+	Params       map[string]string `json:"params,omitempty"`
+}
+
+// AuditRepository is an append-only log of administrative actions
+type AuditRepository interface {
+	Record(ctx context.Context, entry AuditEntry) error
+	List(ctx context.Context, limit int) ([]*AuditEntry, error)
+	Close() error
+}
+
+// auditRepository writes each entry as its own object, so entries are never rewritten
+type auditRepository struct {
+	storage Storage
+	prefix  string
+}
+
+// NewAuditRepository creates an audit log stored next to the processed index
+func NewAuditRepository() (AuditRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := defaultAuditPrefix
+	if env := os.Getenv("AUDIT_PREFIX"); env != "" {
+		prefix = env
+	}
+
+	return &auditRepository{
+		storage: store,
+		prefix:  prefix,
+	}, nil
+}
+
+// Record appends an entry; the object is created with Storage.Create so it can never overwrite another
+func (g *auditRepository) Record(ctx context.Context, entry AuditEntry) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.Printf("Error marshaling audit entry: %v", err)
+		return fmt.Errorf("marshaling audit entry: %w", err)
+	}
+
+	name, err := auditObjectName(g.prefix, entry.Time)
+	if err != nil {
+		return err
+	}
+
+	if err := g.storage.Create(ctx, name, data, "application/json"); err != nil {
+		logger.Printf("Error writing audit entry: %v\nStack:\n%s", err, debug.Stack())
+		return fmt.Errorf("writing audit entry: %w", err)
+	}
+
+	logger.Printf("Audit entry recorded action=%s actor=%s object=%s", entry.Action, entry.ActorTokenID, name)
+	return nil
+}
+
+// List returns up to limit entries, newest first
+func (g *auditRepository) List(ctx context.Context, limit int) ([]*AuditEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	names, err := g.storage.List(ctx, g.prefix)
+	if err != nil {
+		logger.Printf("Error listing audit entries: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("listing audit entries: %w", err)
+	}
+
+	// Object names start with a sortable timestamp
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	entries := make([]*AuditEntry, 0, len(names))
+	for _, name := range names {
+		data, err := g.storage.Read(ctx, name)
+		if err != nil {
+			logger.Printf("Error reading audit entry %s: %v", name, err)
+			return nil, fmt.Errorf("reading audit entry: %w", err)
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			logger.Printf("Error unmarshaling audit entry %s: %v", name, err)
+			return nil, fmt.Errorf("unmarshaling audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// Close closes the storage
+func (g *auditRepository) Close() error {
+	return g.storage.Close()
+}
+
+// auditObjectName builds a unique object name that sorts chronologically
+func auditObjectName(prefix string, t time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating audit entry id: %w", err)
+	}
+	timestamp := strings.Replace(t.UTC().Format("20060102T150405.000000000Z"), ".", "", 1)
+	return fmt.Sprintf("%s%s-%s.json", prefix, timestamp, hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ecsCredentialsHost serves the task role credentials of ECS tasks (AWS_CONTAINER_CREDENTIALS_RELATIVE_URI)
+const ecsCredentialsHost = "http://169.254.170.2"
+
+// awsCredentials sign requests to AWS services
+type awsCredentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	expires      time.Time // Zero for static credentials
+}
+
+// awsCredentialSource returns static credentials, or on ECS the task role's temporary credentials
+// from the container credentials endpoint, fetched again shortly before they expire. Lambda sets
+// the AWS_* variables, so its execution role needs no endpoint.
+type awsCredentialSource struct {
+	static     *awsCredentials
+	endpoint   string // Container credentials endpoint
+	authToken  string // AWS_CONTAINER_AUTHORIZATION_TOKEN
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	cached awsCredentials
+}
+
+// newAWSCredentialSource uses the given keys (with AWS_SESSION_TOKEN) or else the ECS container
+// credentials endpoint; nil when neither is available
+func newAWSCredentialSource(accessKey, secretKey string) *awsCredentialSource {
+	if accessKey != "" && secretKey != "" {
+		return &awsCredentialSource{static: &awsCredentials{
+			accessKey:    accessKey,
+			secretKey:    secretKey,
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		}}
+	}
+
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		endpoint = ecsCredentialsHost + uri
+	}
+	if endpoint == "" {
+		return nil
+	}
+	return &awsCredentialSource{
+		endpoint:   endpoint,
+		authToken:  os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// get returns credentials valid for at least a few more minutes
+func (s *awsCredentialSource) get(ctx context.Context) (awsCredentials, error) {
+	if s.static != nil {
+		return *s.static, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached.accessKey != "" && time.Until(s.cached.expires) > 5*time.Minute {
+		return s.cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.endpoint, nil)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("creating credentials request: %w", err)
+	}
+	if s.authToken != "" {
+		req.Header.Set("Authorization", s.authToken)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("fetching container credentials: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return awsCredentials{}, fmt.Errorf("fetching container credentials: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return awsCredentials{}, fmt.Errorf("decoding container credentials: %w", err)
+	}
+	if body.AccessKeyID == "" || body.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("container credentials endpoint returned no keys")
+	}
+	s.cached = awsCredentials{
+		accessKey:    body.AccessKeyID,
+		secretKey:    body.SecretAccessKey,
+		sessionToken: body.Token,
+		expires:      body.Expiration,
+	}
+	return s.cached, nil
+}
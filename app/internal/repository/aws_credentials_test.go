@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAWSCredentialSource_Container(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if r.Header.Get("Authorization") != "container-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"AccessKeyId":     "ASIA-TASK",
+			"SecretAccessKey": "task-secret",
+			"Token":           "task-session",
+			"Expiration":      time.Now().Add(time.Hour),
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/credentials")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "container-token")
+	source := newAWSCredentialSource("", "")
+	if source == nil {
+		t.Fatal("Expected the container credentials endpoint to be used")
+	}
+
+	for range 2 {
+		credentials, err := source.get(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if credentials.accessKey != "ASIA-TASK" || credentials.sessionToken != "task-session" {
+			t.Errorf("Unexpected credentials %+v", credentials)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected the credentials to be cached until they near expiry, got %d fetches", fetches)
+	}
+
+	// Static keys take precedence
+	if static, _ := newAWSCredentialSource("AKIA", "secret").get(context.Background()); static.accessKey != "AKIA" {
+		t.Errorf("Expected the static keys, got %+v", static)
+	}
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const (
+	defaultBacklogFileName = "backlog.json"
+	// defaultBacklogMaxAttempts is the number of failures after which an entry moves to the dead-letter backlog
+	defaultBacklogMaxAttempts = 3
+	// commentTaskSuffix marks backlog feeds that only retry the comment summary of a posted article
+	commentTaskSuffix = "/comments"
+)
+
+// BacklogEntry is an article whose processing failed and is waiting to be retried
+type BacklogEntry struct {
+	Feed        string    `json:"feed"`
+	Item        Item      `json:"item"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	FirstFailed time.Time `json:"first_failed"`
+	LastFailed  time.Time `json:"last_failed"`
+	DeadLetter  bool      `json:"dead_letter"`
+}
+
+// CommentTaskFeed is the backlog feed for retrying only the comment summary of an article
+// already posted by feed (e.g. after the comment API answered 429)
+func CommentTaskFeed(feed string) string {
+	return feed + commentTaskSuffix
+}
+
+// CommentTask reports whether the entry only retries a comment summary, and for which feed
+func (e *BacklogEntry) CommentTask() (string, bool) {
+	return strings.CutSuffix(e.Feed, commentTaskSuffix)
+}
+
+// Key identifies the entry in the backlog: the article link, suffixed for comment tasks
+// so a pending comment summary does not collide with a retry of the article itself
+func (e *BacklogEntry) Key() string {
+	return backlogKey(e.Feed, e.Item.Link)
+}
+
+func backlogKey(feed, link string) string {
+	if strings.HasSuffix(feed, commentTaskSuffix) {
+		return link + "#comments"
+	}
+	return link
+}
+
+// BacklogRepository keeps failed articles in a retry-later backlog, moving them to
+// the dead-letter backlog once they have failed too many times.
+type BacklogRepository interface {
+	Record(ctx context.Context, feed string, article Item, cause error) error
+	List(ctx context.Context) ([]*BacklogEntry, error)
+	// Remove drops the entry with the given Key
+	Remove(ctx context.Context, key string) error
+	Close() error
+}
+
+type backlogRepository struct {
+	storage     Storage
+	backlogFile string
+	maxAttempts int
+	mu          sync.Mutex // serializes this instance's backlog updates for concurrent article workers
+}
+
+// NewBacklogRepository creates a backlog repository stored next to the processed index
+func NewBacklogRepository() (BacklogRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	backlogFile := defaultBacklogFileName
+	if env := os.Getenv("BACKLOG_FILE"); env != "" {
+		backlogFile = env
+	}
+
+	maxAttempts := defaultBacklogMaxAttempts
+	if env := os.Getenv("BACKLOG_MAX_ATTEMPTS"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			maxAttempts = n
+		}
+	}
+
+	return &backlogRepository{
+		storage:     store,
+		backlogFile: backlogFile,
+		maxAttempts: maxAttempts,
+	}, nil
+}
+
+// Record adds a failed article to the backlog, or bumps its attempt count if already present
+func (g *backlogRepository) Record(ctx context.Context, feed string, article Item, cause error) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	var entry *BacklogEntry
+	err := g.update(ctx, func(backlog map[string]*BacklogEntry) bool {
+		entry = recordBacklogEntry(backlog, feed, article, cause, g.maxAttempts, Now(ctx))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if entry.DeadLetter {
+		logger.Printf("Backlog entry dead-lettered url=%s feed=%s attempts=%d", article.Link, feed, entry.Attempts)
+	}
+	return nil
+}
+
+// List returns the retry-later entries (oldest first) followed by the dead-letter entries
+func (g *backlogRepository) List(ctx context.Context) ([]*BacklogEntry, error) {
+	backlog, err := g.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortBacklogEntries(backlog), nil
+}
+
+// Remove drops an entry once it has been processed
+func (g *backlogRepository) Remove(ctx context.Context, key string) error {
+	return g.update(ctx, func(backlog map[string]*BacklogEntry) bool {
+		if _, exists := backlog[key]; !exists {
+			return false
+		}
+		delete(backlog, key)
+		return true
+	})
+}
+
+// Close closes the storage
+func (g *backlogRepository) Close() error {
+	return g.storage.Close()
+}
+
+func (g *backlogRepository) load(ctx context.Context) (map[string]*BacklogEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := g.storage.Read(ctx, g.backlogFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string]*BacklogEntry), nil
+		}
+		logger.Printf("Error reading backlog data: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("reading backlog data: %w", err)
+	}
+
+	var backlog map[string]*BacklogEntry
+	if err := json.Unmarshal(data, &backlog); err != nil {
+		logger.Printf("Error unmarshaling backlog: %v", err)
+		return nil, fmt.Errorf("unmarshaling backlog: %w", err)
+	}
+
+	return backlog, nil
+}
+
+// update applies mutate to the latest backlog and saves it when mutate reports a change. Like the
+// processed index (see processedIndexRepository.updateIndex), the mutex only covers this instance;
+// on versioned storage the save is conditional on the generation that was read, and a concurrent
+// invocation's write (a feed run recording a failure during a drain) makes it re-read and re-apply mutate.
+func (g *backlogRepository) update(ctx context.Context, mutate func(backlog map[string]*BacklogEntry) bool) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	versioned, ok := g.storage.(VersionedStorage)
+	if !ok {
+		backlog, err := g.load(ctx)
+		if err != nil {
+			return err
+		}
+		if !mutate(backlog) {
+			return nil
+		}
+		return g.save(ctx, backlog)
+	}
+
+	for attempt := 1; ; attempt++ {
+		backlog, generation, err := g.loadVersioned(ctx, versioned)
+		if err != nil {
+			return err
+		}
+		if !mutate(backlog) {
+			return nil
+		}
+		data, err := json.Marshal(backlog)
+		if err != nil {
+			return fmt.Errorf("marshaling backlog: %w", err)
+		}
+
+		err = versioned.WriteIfGeneration(ctx, g.backlogFile, data, "application/json", generation)
+		if !errors.Is(err, ErrVersionConflict) {
+			if err != nil {
+				logger.Printf("Error writing backlog data: %v\nStack:\n%s", err, debug.Stack())
+				return fmt.Errorf("writing backlog data: %w", err)
+			}
+			return nil
+		}
+		if attempt == maxIndexUpdateAttempts {
+			return fmt.Errorf("writing backlog data: %w (%d attempts)", err, attempt)
+		}
+		logger.Printf("Backlog changed concurrently, retrying update attempt=%d", attempt)
+		// Jittered so invocations that collided do not collide again
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt)*50*time.Millisecond + rand.N(50*time.Millisecond)):
+		}
+	}
+}
+
+// loadVersioned reads the backlog with its generation (0 while it does not exist)
+func (g *backlogRepository) loadVersioned(ctx context.Context, versioned VersionedStorage) (map[string]*BacklogEntry, int64, error) {
+	data, generation, err := versioned.ReadVersioned(ctx, g.backlogFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*BacklogEntry), 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading backlog data: %w", err)
+	}
+	var backlog map[string]*BacklogEntry
+	if err := json.Unmarshal(data, &backlog); err != nil {
+		return nil, 0, fmt.Errorf("unmarshaling backlog: %w", err)
+	}
+	if backlog == nil {
+		backlog = make(map[string]*BacklogEntry)
+	}
+	return backlog, generation, nil
+}
+
+func (g *backlogRepository) save(ctx context.Context, backlog map[string]*BacklogEntry) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	data, err := json.Marshal(backlog)
+	if err != nil {
+		logger.Printf("Error marshaling backlog: %v", err)
+		return fmt.Errorf("marshaling backlog: %w", err)
+	}
+
+	if err := g.storage.Write(ctx, g.backlogFile, data, "application/json"); err != nil {
+		logger.Printf("Error writing backlog data: %v\nStack:\n%s", err, debug.Stack())
+		return fmt.Errorf("writing backlog data: %w", err)
+	}
+
+	return nil
+}
+
+// recordBacklogEntry updates the backlog map in place and returns the affected entry
+func recordBacklogEntry(backlog map[string]*BacklogEntry, feed string, article Item, cause error, maxAttempts int, now time.Time) *BacklogEntry {
+	key := backlogKey(feed, article.Link)
+	entry, exists := backlog[key]
+	if !exists {
+		entry = &BacklogEntry{
+			Feed:        feed,
+			Item:        article,
+			FirstFailed: now,
+		}
+		backlog[key] = entry
+	}
+
+	entry.Attempts++
+	entry.LastFailed = now
+	if cause != nil {
+		entry.LastError = cause.Error()
+	}
+	// Permanent failures (e.g. the article answering 404) would fail the same way on every retry
+	if entry.Attempts >= maxAttempts || httperr.IsPermanent(cause) {
+		entry.DeadLetter = true
+	}
+
+	return entry
+}
+
+// sortBacklogEntries orders retry-later entries before dead-letter ones, oldest failure first
+func sortBacklogEntries(backlog map[string]*BacklogEntry) []*BacklogEntry {
+	entries := make([]*BacklogEntry, 0, len(backlog))
+	for _, entry := range backlog {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].DeadLetter != entries[j].DeadLetter {
+			return !entries[i].DeadLetter
+		}
+		return entries[i].LastFailed.Before(entries[j].LastFailed)
+	})
+
+	return entries
+}
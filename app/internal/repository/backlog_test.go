@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestRecordBacklogEntry(t *testing.T) {
+	backlog := make(map[string]*BacklogEntry)
+	article := Item{Title: "Test", Link: "https://example.com/a"}
+	now := time.Now()
+
+	entry := recordBacklogEntry(backlog, "hatena", article, errors.New("timeout"), 2, now)
+	if entry.Attempts != 1 || entry.DeadLetter {
+		t.Errorf("Expected first failure to stay in retry-later backlog, got %+v", entry)
+	}
+	if entry.LastError != "timeout" || entry.Feed != "hatena" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	entry = recordBacklogEntry(backlog, "hatena", article, errors.New("timeout again"), 2, now.Add(time.Hour))
+	if entry.Attempts != 2 || !entry.DeadLetter {
+		t.Errorf("Expected entry to be dead-lettered after max attempts, got %+v", entry)
+	}
+	if !entry.FirstFailed.Equal(now) {
+		t.Error("FirstFailed should not change on later failures")
+	}
+	if len(backlog) != 1 {
+		t.Errorf("Expected 1 backlog entry, got %d", len(backlog))
+	}
+}
+
+func TestRecordBacklogEntry_PermanentFailure(t *testing.T) {
+	backlog := make(map[string]*BacklogEntry)
+	article := Item{Title: "Gone", Link: "https://example.com/gone"}
+
+	cause := fmt.Errorf("summarizing article: %w", httperr.Status(http.StatusNotFound, "unexpected status code: 404"))
+	entry := recordBacklogEntry(backlog, "hatena", article, cause, 3, time.Now())
+	if !entry.DeadLetter || entry.Attempts != 1 {
+		t.Errorf("Expected a permanent failure to be dead-lettered right away, got %+v", entry)
+	}
+
+	temporary := fmt.Errorf("summarizing article: %w", httperr.Status(http.StatusBadGateway, "unexpected status code: 502"))
+	entry = recordBacklogEntry(backlog, "lobsters", Item{Link: "https://example.com/flaky"}, temporary, 3, time.Now())
+	if entry.DeadLetter {
+		t.Errorf("Expected a temporary failure to stay in the retry-later backlog, got %+v", entry)
+	}
+}
+
+func TestRecordBacklogEntry_CommentTask(t *testing.T) {
+	backlog := make(map[string]*BacklogEntry)
+	article := Item{Title: "Test", Link: "https://example.com/a"}
+	now := time.Now()
+
+	recordBacklogEntry(backlog, "lobsters", article, errors.New("timeout"), 3, now)
+	task := recordBacklogEntry(backlog, CommentTaskFeed("lobsters"), article, errors.New("429"), 3, now)
+	if len(backlog) != 2 || task.Attempts != 1 {
+		t.Fatalf("Expected the comment task next to the article entry, got %d entries (task %+v)", len(backlog), task)
+	}
+	if task.Key() != "https://example.com/a#comments" || backlog[task.Key()] != task {
+		t.Errorf("Unexpected comment task key %s", task.Key())
+	}
+
+	if feed, ok := task.CommentTask(); !ok || feed != "lobsters" {
+		t.Errorf("Expected a lobsters comment task, got %s %t", feed, ok)
+	}
+	if _, ok := backlog[article.Link].CommentTask(); ok {
+		t.Error("Article entry should not be a comment task")
+	}
+}
+
+func TestSortBacklogEntries(t *testing.T) {
+	now := time.Now()
+	backlog := map[string]*BacklogEntry{
+		"dead":  {Item: Item{Link: "dead"}, DeadLetter: true, LastFailed: now.Add(-3 * time.Hour)},
+		"new":   {Item: Item{Link: "new"}, LastFailed: now},
+		"older": {Item: Item{Link: "older"}, LastFailed: now.Add(-time.Hour)},
+	}
+
+	entries := sortBacklogEntries(backlog)
+	expected := []string{"older", "new", "dead"}
+	for i, link := range expected {
+		if entries[i].Item.Link != link {
+			t.Errorf("Position %d: expected %s, got %s", i, link, entries[i].Item.Link)
+		}
+	}
+}
+
+func TestBacklogRepository_ConcurrentWriteRetries(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := &backlogRepository{storage: store, backlogFile: defaultBacklogFileName, maxAttempts: defaultBacklogMaxAttempts}
+	ctx := context.Background()
+	if err := repo.Record(ctx, "hatena", Item{Link: "https://example.com/drained"}, errors.New("timeout")); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// A feed run on another instance records a failure between the drain's read and its write, once
+	concurrent := &backlogRepository{storage: store, backlogFile: defaultBacklogFileName, maxAttempts: defaultBacklogMaxAttempts}
+	store.beforeWrite = func(s *versionedMemoryStorage) {
+		s.beforeWrite = nil
+		if err := concurrent.Record(ctx, "reddit", Item{Link: "https://example.com/new"}, errors.New("timeout")); err != nil {
+			t.Fatalf("Concurrent record failed: %v", err)
+		}
+	}
+	if err := repo.Remove(ctx, "https://example.com/drained"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	entries, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Item.Link != "https://example.com/new" {
+		t.Errorf("Expected only the concurrently recorded entry, got %v", entries)
+	}
+
+	// Writers that keep colliding give up instead of looping forever
+	store.beforeWrite = func(s *versionedMemoryStorage) { s.generation++ }
+	if err := repo.Record(ctx, "hatena", Item{Link: "https://example.com/late"}, errors.New("timeout")); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected a version conflict after %d attempts, got %v", maxIndexUpdateAttempts, err)
+	}
+}
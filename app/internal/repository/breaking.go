@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+)
+
+// BreakingPrefix marks breaking news in notification titles
+const BreakingPrefix = "🚨 "
+
+type breakingNotifier struct {
+	next Notifier
+}
+
+// NewBreakingNotifier prefixes the titles of breaking notifications with BreakingPrefix before
+// forwarding. Wrap it inside headline rewriting so that rewritten titles keep the prefix.
+func NewBreakingNotifier(next Notifier) Notifier {
+	return &breakingNotifier{next: next}
+}
+
+func (b *breakingNotifier) Send(ctx context.Context, notification Notification) error {
+	if notification.Breaking {
+		notification.Title = BreakingPrefix + notification.Title
+	}
+	return b.next.Send(ctx, notification)
+}
+
+func (b *breakingNotifier) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	return b.next.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush forwards to the wrapped notifier when it batches
+func (b *breakingNotifier) Flush(ctx context.Context) error {
+	if flusher, ok := b.next.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+func TestBreakingNotifier_PrefixesTitleAndSkipsBatching(t *testing.T) {
+	var sent []string
+	email := NewEmailRepository(EmailConfig{Host: "smtp.example.com", Port: "587", From: "bot@example.com", To: []string{"team@example.com"}}, "hatena").(*emailRepository)
+	email.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, string(msg))
+		return nil
+	}
+	notifier := NewBreakingNotifier(email)
+	ctx := context.Background()
+
+	if err := notifier.Send(ctx, Notification{Title: "Weekly roundup", URL: "https://example.com/1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("Expected the regular notification to wait for the digest, got %d emails", len(sent))
+	}
+
+	if err := notifier.Send(ctx, Notification{Title: "Registry outage", URL: "https://example.com/2", Breaking: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0], BreakingPrefix+"Registry outage") || strings.Contains(sent[0], "Weekly roundup") {
+		t.Fatalf("Expected the breaking notification emailed alone and prefixed, got %v", sent)
+	}
+
+	if err := notifier.(Flusher).Flush(ctx); err != nil || len(sent) != 2 || !strings.Contains(sent[1], "Weekly roundup") {
+		t.Errorf("Expected the digest with the regular notification on flush, got %d emails (%v)", len(sent), err)
+	}
+}
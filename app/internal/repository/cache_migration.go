@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+const defaultMigrationBatchSize = 500
+
+// CacheMigration copies every processed entry of one backend into another (cli migrate-cache), so
+// that switching CACHE_TYPE does not make the new backend summarize and notify the history again
+type CacheMigration struct {
+	From ProcessedArticleRepository
+	To   ProcessedArticleRepository
+	// BatchSize is the number of entries imported (and reported) at a time (default 500)
+	BatchSize int
+	// DryRun only counts the entries that would be copied
+	DryRun bool
+	// Progress is called after each batch (optional)
+	Progress func(progress CacheMigrationProgress)
+}
+
+// CacheMigrationProgress counts the entries of a migration so far
+type CacheMigrationProgress struct {
+	Total  int // Entries in the source backend
+	Done   int // Entries handled so far
+	Copied int // Entries added to the target (would be added, for a dry run)
+}
+
+// Run copies the source's entries in batches, keeping their processed dates and the entries
+// already in the target. A failed run can be run again: copied entries are skipped.
+func (m *CacheMigration) Run(ctx context.Context) (CacheMigrationProgress, error) {
+	var progress CacheMigrationProgress
+
+	entries, err := m.From.ExportEntries(ctx)
+	if err != nil {
+		return progress, fmt.Errorf("reading source entries: %w", err)
+	}
+	progress.Total = len(entries)
+
+	var existing map[string]bool
+	if m.DryRun {
+		targetEntries, err := m.To.ExportEntries(ctx)
+		if err != nil {
+			return progress, fmt.Errorf("reading target entries: %w", err)
+		}
+		existing = make(map[string]bool, len(targetEntries))
+		for _, entry := range targetEntries {
+			existing[m.To.GenerateKey(Item{Link: entry.URL})] = true
+		}
+	}
+
+	batchSize := m.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMigrationBatchSize
+	}
+	for start := 0; start < len(entries); start += batchSize {
+		batch := entries[start:min(start+batchSize, len(entries))]
+		if m.DryRun {
+			for _, entry := range batch {
+				key := m.To.GenerateKey(Item{Link: entry.URL})
+				if key != "" && !existing[key] {
+					existing[key] = true
+					progress.Copied++
+				}
+			}
+		} else {
+			copied, err := m.To.ImportEntries(ctx, batch)
+			progress.Copied += copied
+			if err != nil {
+				return progress, fmt.Errorf("copying entries %d-%d: %w", start+1, start+len(batch), err)
+			}
+		}
+		progress.Done += len(batch)
+		if m.Progress != nil {
+			m.Progress(progress)
+		}
+	}
+	return progress, nil
+}
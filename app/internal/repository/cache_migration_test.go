@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheMigration(t *testing.T) {
+	ctx := context.Background()
+	processed := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+
+	from := newProcessedIndexRepository(&versionedMemoryStorage{}, defaultIndexFileName)
+	if _, err := from.ImportEntries(ctx, []*IndexEntry{
+		{URL: "https://example.com/a", ProcessedDate: processed},
+		{URL: "https://example.com/b", ProcessedDate: processed},
+		{URL: "https://example.com/c", ProcessedDate: processed},
+	}); err != nil {
+		t.Fatalf("Seeding failed: %v", err)
+	}
+	to := newProcessedIndexRepository(&versionedMemoryStorage{}, defaultIndexFileName)
+	if _, err := to.ImportEntries(ctx, []*IndexEntry{{URL: "https://example.com/b", ProcessedDate: processed}}); err != nil {
+		t.Fatalf("Seeding failed: %v", err)
+	}
+
+	// A dry run counts without writing
+	dryRun := &CacheMigration{From: from, To: to, DryRun: true}
+	progress, err := dryRun.Run(ctx)
+	if err != nil || progress.Total != 3 || progress.Copied != 2 {
+		t.Fatalf("Expected 2 of 3 entries to be copied, got %+v (%v)", progress, err)
+	}
+	if index, _ := to.LoadIndex(ctx); len(index) != 1 {
+		t.Fatalf("Expected a dry run not to write, got %v", index)
+	}
+
+	var reports []CacheMigrationProgress
+	migration := &CacheMigration{From: from, To: to, BatchSize: 2, Progress: func(p CacheMigrationProgress) {
+		reports = append(reports, p)
+	}}
+	progress, err = migration.Run(ctx)
+	if err != nil || progress.Copied != 2 {
+		t.Fatalf("Expected 2 copied entries, got %+v (%v)", progress, err)
+	}
+	if len(reports) != 2 || reports[0].Done != 2 || reports[1].Done != 3 {
+		t.Errorf("Expected progress after each batch, got %+v", reports)
+	}
+	index, err := to.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 3 || !index["https://example.com/a"].ProcessedDate.Equal(processed) {
+		t.Errorf("Expected every entry with its processed date, got %v", index)
+	}
+
+	// Running again copies nothing
+	if progress, err := migration.Run(ctx); err != nil || progress.Copied != 0 {
+		t.Errorf("Expected nothing left to copy, got %+v (%v)", progress, err)
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// mobileHostPrefixes are subdomains used for mobile variants of a desktop site
+var mobileHostPrefixes = []string{"m.", "mobile."}
+
+// ampCacheQueryParams are added by AMP caches and viewers, not by the publisher
+var ampCacheQueryParams = []string{"amp_js_v", "usqp"}
+
+var canonicalLinkTagRe = regexp.MustCompile(`(?i)<link\b[^>]*\brel=["']?canonical["']?[^>]*>`)
+
+// CanonicalizeURL rewrites AMP cache/viewer URLs to the publisher's URL and mobile (m.-subdomain)
+// variants to the canonical desktop URL. AMP markup extracts poorly and defeats cross-feed dedupe, so
+// this is applied before fetching and deduping. AMP markers in the publisher's own URLs (/amp, .amp,
+// ?amp=1, amp.-subdomains) are left alone: genuine pages use them too (github.com/ampproject/amp), so
+// they are only followed once the page's canonical link confirms them (see confirmedDesktopURL).
+// URLs that cannot be parsed are returned unchanged.
+func CanonicalizeURL(rawURL string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsedURL.Host == "" {
+		return rawURL
+	}
+
+	// 1. Unwrap AMP caches (Google AMP viewer / cdn.ampproject.org)
+	if unwrapped := unwrapAMPCache(parsedURL); unwrapped != nil {
+		parsedURL = unwrapped
+	}
+
+	// 2. Strip mobile subdomains (m.example.com, en.m.wikipedia.org)
+	host := strings.ToLower(parsedURL.Host)
+	for _, prefix := range mobileHostPrefixes {
+		if strings.HasPrefix(host, prefix) && strings.Count(host, ".") >= 2 {
+			host = host[len(prefix):]
+			break
+		}
+	}
+	host = strings.Replace(host, ".m.", ".", 1)
+	parsedURL.Host = host
+
+	return parsedURL.String()
+}
+
+// ampDesktopCandidate returns the URL with the publisher's AMP markers removed (/amp, /amp/, .amp,
+// .amp.html, the /amp/ prefix, the amp. subdomain and AMP query parameters), or "" when it has none
+func ampDesktopCandidate(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Host == "" {
+		return ""
+	}
+	changed := false
+
+	if host := strings.ToLower(parsedURL.Host); strings.HasPrefix(host, "amp.") && strings.Count(host, ".") >= 2 {
+		parsedURL.Host = strings.TrimPrefix(host, "amp.")
+		changed = true
+	}
+
+	path := parsedURL.Path
+	switch {
+	case strings.HasSuffix(path, "/amp/"):
+		path = strings.TrimSuffix(path, "amp/")
+	case strings.HasSuffix(path, "/amp"):
+		path = strings.TrimSuffix(path, "amp")
+	case strings.HasSuffix(path, ".amp"):
+		path = strings.TrimSuffix(path, ".amp")
+	case strings.HasSuffix(path, ".amp.html"):
+		path = strings.TrimSuffix(path, ".amp.html") + ".html"
+	case strings.HasPrefix(path, "/amp/"):
+		path = strings.TrimPrefix(path, "/amp")
+	}
+	if path != parsedURL.Path {
+		parsedURL.Path = path
+		parsedURL.RawPath = ""
+		changed = true
+	}
+
+	if parsedURL.RawQuery != "" {
+		query := parsedURL.Query()
+		removed := false
+		for _, key := range append([]string{"amp", "outputType"}, ampCacheQueryParams...) {
+			if !query.Has(key) || (key == "outputType" && query.Get(key) != "amp") {
+				continue
+			}
+			query.Del(key)
+			removed = true
+		}
+		if removed {
+			parsedURL.RawQuery = query.Encode()
+			changed = true
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+	return parsedURL.String()
+}
+
+// confirmedDesktopURL returns the desktop page of an AMP variant when the fetched page's
+// <link rel="canonical"> names the URL without its AMP markers, or "" otherwise
+func confirmedDesktopURL(pageURL, page string) string {
+	candidate := ampDesktopCandidate(pageURL)
+	if candidate == "" {
+		return ""
+	}
+	tag := canonicalLinkTagRe.FindString(page)
+	m := hrefAttrRe.FindStringSubmatch(tag)
+	if len(m) < 2 {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	ref, err := url.Parse(strings.TrimSpace(html.UnescapeString(m[1])))
+	if err != nil {
+		return ""
+	}
+	canonical := base.ResolveReference(ref).String()
+
+	// Compared as processed-index keys, so scheme, www., query and trailing slash differences do not matter
+	want, err := normalizeArticleURL(candidate)
+	if err != nil {
+		return ""
+	}
+	if got, err := normalizeArticleURL(canonical); err != nil || got != want {
+		return ""
+	}
+	return canonical
+}
+
+// unwrapAMPCache extracts the origin URL from AMP cache URLs
+//
+//	https://www-example-com.cdn.ampproject.org/c/s/www.example.com/path -> https://www.example.com/path
+//	https://www.google.com/amp/s/example.com/path                      -> https://example.com/path
+func unwrapAMPCache(parsedURL *url.URL) *url.URL {
+	host := strings.ToLower(parsedURL.Host)
+	path := parsedURL.Path
+
+	var rest string
+	switch {
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		// /c/s/<host>/<path> (s = https), /v/s/... for viewer, /i/s/... for images
+		parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+		if len(parts) < 2 {
+			return nil
+		}
+		rest = parts[1]
+	case (host == "google.com" || host == "www.google.com") && strings.HasPrefix(path, "/amp/"):
+		rest = strings.TrimPrefix(path, "/amp/")
+	default:
+		return nil
+	}
+
+	scheme := "http"
+	if strings.HasPrefix(rest, "s/") {
+		scheme = "https"
+		rest = strings.TrimPrefix(rest, "s/")
+	}
+
+	origin, err := url.Parse(scheme + "://" + rest)
+	if err != nil || origin.Host == "" {
+		return nil
+	}
+	query := parsedURL.Query()
+	for _, key := range ampCacheQueryParams {
+		query.Del(key)
+	}
+	if len(query) > 0 {
+		origin.RawQuery = query.Encode()
+	}
+	return origin
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "mobile subdomain",
+			input:    "https://m.example.com/news/123",
+			expected: "https://example.com/news/123",
+		},
+		{
+			name:     "infix mobile subdomain",
+			input:    "https://en.m.wikipedia.org/wiki/Go",
+			expected: "https://en.wikipedia.org/wiki/Go",
+		},
+		{
+			name:     "publisher amp path left for canonical link",
+			input:    "https://example.com/2024/01/article/amp/",
+			expected: "https://example.com/2024/01/article/amp/",
+		},
+		{
+			name:     "repository named amp unchanged",
+			input:    "https://github.com/ampproject/amp",
+			expected: "https://github.com/ampproject/amp",
+		},
+		{
+			name:     "amp query parameter unchanged",
+			input:    "https://example.com/article?amp=1&id=5",
+			expected: "https://example.com/article?amp=1&id=5",
+		},
+		{
+			name:     "google amp viewer",
+			input:    "https://www.google.com/amp/s/www.example.com/article?usqp=mq331AQ",
+			expected: "https://www.example.com/article",
+		},
+		{
+			name:     "ampproject cache",
+			input:    "https://www-example-com.cdn.ampproject.org/c/s/www.example.com/article",
+			expected: "https://www.example.com/article",
+		},
+		{
+			name:     "two-label host keeps amp prefix",
+			input:    "https://amp.dev/documentation",
+			expected: "https://amp.dev/documentation",
+		},
+		{
+			name:     "desktop URL unchanged",
+			input:    "https://example.com/article?id=5&b=2",
+			expected: "https://example.com/article?id=5&b=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CanonicalizeURL(tt.input)
+			if result != tt.expected {
+				t.Errorf("CanonicalizeURL(%s) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfirmedDesktopURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageURL  string
+		page     string
+		expected string
+	}{
+		{
+			name:     "canonical link confirms the desktop URL",
+			pageURL:  "https://example.com/2024/01/article/amp/",
+			page:     `<html><head><link rel="canonical" href="https://www.example.com/2024/01/article/"></head></html>`,
+			expected: "https://www.example.com/2024/01/article/",
+		},
+		{
+			name:     "relative canonical link on an amp subdomain",
+			pageURL:  "https://amp.example.com/article",
+			page:     `<link href="https://example.com/article" rel=canonical>`,
+			expected: "https://example.com/article",
+		},
+		{
+			name:     "canonical link to the page itself",
+			pageURL:  "https://github.com/ampproject/amp",
+			page:     `<link rel="canonical" href="https://github.com/ampproject/amp">`,
+			expected: "",
+		},
+		{
+			name:     "canonical link elsewhere",
+			pageURL:  "https://example.com/article.amp",
+			page:     `<link rel="canonical" href="https://example.com/other">`,
+			expected: "",
+		},
+		{
+			name:     "no canonical link",
+			pageURL:  "https://example.com/article?amp=1",
+			page:     `<html><body>AMP</body></html>`,
+			expected: "",
+		},
+		{
+			name:     "no amp markers",
+			pageURL:  "https://example.com/article",
+			page:     `<link rel="canonical" href="https://example.com/article">`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confirmedDesktopURL(tt.pageURL, tt.page); got != tt.expected {
+				t.Errorf("confirmedDesktopURL(%s) = %q, expected %q", tt.pageURL, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeminiRepository_FetchArticlePages_AMPCanonical(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/amp") {
+			fmt.Fprintf(w, `<html><head><link rel="canonical" href="%s/article"></head><body>amp page</body></html>`, server.URL)
+			return
+		}
+		fmt.Fprint(w, `<html><body>desktop page</body></html>`)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxPages:   1,
+	}
+
+	pages, err := repo.fetchArticlePages(context.Background(), server.URL+"/article/amp")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pages) != 1 || !strings.Contains(pages[0], "desktop page") {
+		t.Errorf("Expected the confirmed desktop page, got %v", pages)
+	}
+}
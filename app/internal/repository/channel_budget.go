@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// Overflow modes of a channel posting budget
+const (
+	ChannelBudgetQueue  = "queue"  // Summaries over the budget wait in the channel's outbox for a later run
+	ChannelBudgetDigest = "digest" // Summaries over the budget collapse into one "N more articles" message
+)
+
+const (
+	channelBudgetPrefix = "channel-budget/"
+	channelBudgetWindow = time.Hour
+)
+
+// ChannelBudgets caps the summaries posted to each Slack channel per hour, so that a news spike does
+// not flood a channel. Posting times and the outbox of summaries over the budget are kept in the
+// shared storage (one object per channel), so the budget holds across runs and instances.
+type ChannelBudgets struct {
+	storage  Storage
+	max      int
+	overflow string
+	mu       sync.Mutex // Serializes the read-modify-write of the channel states
+}
+
+// channelBudgetState is the stored state of one channel
+type channelBudgetState struct {
+	Posts  []time.Time    `json:"posts"`  // Posting times within the last hour
+	Queued []Notification `json:"queued"` // Outbox of summaries over the budget, oldest first
+}
+
+// ValidateChannelBudgetOverflow checks SLACK_CHANNEL_BUDGET_OVERFLOW
+func ValidateChannelBudgetOverflow(overflow string) error {
+	if overflow != ChannelBudgetQueue && overflow != ChannelBudgetDigest {
+		return fmt.Errorf("unsupported overflow mode %q (expected %s or %s)", overflow, ChannelBudgetQueue, ChannelBudgetDigest)
+	}
+	return nil
+}
+
+// NewChannelBudgets allows maxPerHour posts per channel; overflow is ChannelBudgetQueue or ChannelBudgetDigest
+func NewChannelBudgets(store Storage, maxPerHour int, overflow string) *ChannelBudgets {
+	return &ChannelBudgets{
+		storage:  store,
+		max:      maxPerHour,
+		overflow: overflow,
+	}
+}
+
+// Close closes the storage
+func (b *ChannelBudgets) Close() error {
+	return b.storage.Close()
+}
+
+// Wrap returns next limited to the channel's budget (next itself for nil budgets)
+func (b *ChannelBudgets) Wrap(next Notifier, channel string) Notifier {
+	if b == nil {
+		return next
+	}
+	return &channelBudgetNotifier{budgets: b, channel: channel, next: next}
+}
+
+type channelBudgetNotifier struct {
+	budgets *ChannelBudgets
+	channel string
+	next    Notifier
+}
+
+// Send posts within the budget and queues the rest into the channel's outbox (delivered by Flush).
+// Breaking news is posted at once but still counts; while the outbox holds summaries, new ones
+// queue behind them so the channel keeps the feed order.
+func (c *channelBudgetNotifier) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	b := c.budgets
+
+	b.mu.Lock()
+	state, err := b.load(ctx, c.channel)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	queue := !notification.Breaking && (len(state.Queued) > 0 || len(state.Posts) >= b.max)
+	if queue {
+		state.Queued = append(state.Queued, notification)
+	} else {
+		state.Posts = append(state.Posts, Now(ctx))
+	}
+	err = b.save(ctx, c.channel, state)
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if queue {
+		logger.Printf("Channel budget exhausted, notification queued channel=%s title=%s queued=%d max_per_hour=%d",
+			c.channel, notification.Title, len(state.Queued), b.max)
+		return nil
+	}
+	return c.next.Send(ctx, notification)
+}
+
+// SendOnDemandSummary is not limited: it answers a request
+func (c *channelBudgetNotifier) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	return c.next.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush posts as much of the outbox as the budget allows. With ChannelBudgetDigest the rest is
+// collapsed into one message; with ChannelBudgetQueue it waits for a later run.
+func (c *channelBudgetNotifier) Flush(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	b := c.budgets
+	digester, canDigest := c.next.(DigestSender)
+
+	b.mu.Lock()
+	state, err := b.load(ctx, c.channel)
+	if err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	n := min(max(b.max-len(state.Posts), 0), len(state.Queued))
+	send := state.Queued[:n]
+	var digest []Notification
+	state.Queued = state.Queued[n:]
+	if b.overflow == ChannelBudgetDigest && canDigest && len(state.Queued) > 0 {
+		digest, state.Queued = state.Queued, nil
+	}
+	now := Now(ctx)
+	for range send {
+		state.Posts = append(state.Posts, now)
+	}
+	if len(digest) > 0 {
+		state.Posts = append(state.Posts, now)
+	}
+	if len(send) == 0 && len(digest) == 0 {
+		b.mu.Unlock()
+		return flushNext(ctx, c.next)
+	}
+	err = b.save(ctx, c.channel, state)
+	b.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var failed []Notification
+	var errs []error
+	for _, notification := range send {
+		if err := c.next.Send(ctx, notification); err != nil {
+			failed = append(failed, notification)
+			errs = append(errs, err)
+		}
+	}
+	sent := len(send) - len(failed)
+	if len(digest) > 0 {
+		if err := digester.SendDigest(ctx, digest); err != nil {
+			failed = append(failed, digest...)
+			errs = append(errs, err)
+		}
+	}
+	logger.Printf("Channel outbox flushed channel=%s sent=%d digested=%d failed=%d queued=%d",
+		c.channel, sent, len(digest), len(failed), len(state.Queued))
+	if len(failed) > 0 {
+		c.requeue(ctx, failed)
+	}
+
+	errs = append(errs, flushNext(ctx, c.next))
+	return errors.Join(errs...)
+}
+
+// requeue puts summaries whose delivery failed back at the head of the outbox
+func (c *channelBudgetNotifier) requeue(ctx context.Context, notifications []Notification) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	b := c.budgets
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, err := b.load(ctx, c.channel)
+	if err == nil {
+		state.Queued = append(notifications, state.Queued...)
+		err = b.save(ctx, c.channel, state)
+	}
+	if err != nil {
+		logger.Printf("Warning: Failed to requeue notifications channel=%s count=%d: %v", c.channel, len(notifications), err)
+	}
+}
+
+func flushNext(ctx context.Context, next Notifier) error {
+	if flusher, ok := next.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// objectName names the channel's state object; channel names may contain "#"
+func (b *ChannelBudgets) objectName(channel string) string {
+	return channelBudgetPrefix + url.PathEscape(channel) + ".json"
+}
+
+// load reads the channel's state, dropping posts older than the window
+func (b *ChannelBudgets) load(ctx context.Context, channel string) (*channelBudgetState, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	state := &channelBudgetState{}
+	data, err := b.storage.Read(ctx, b.objectName(channel))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return state, nil
+	case err != nil:
+		logger.Printf("Error reading channel budget channel=%s: %v", channel, err)
+		return nil, fmt.Errorf("reading channel budget: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		logger.Printf("Error unmarshaling channel budget channel=%s: %v", channel, err)
+		return nil, fmt.Errorf("unmarshaling channel budget: %w", err)
+	}
+
+	cutoff := Now(ctx).Add(-channelBudgetWindow)
+	recent := state.Posts[:0]
+	for _, posted := range state.Posts {
+		if posted.After(cutoff) {
+			recent = append(recent, posted)
+		}
+	}
+	state.Posts = recent
+	return state, nil
+}
+
+func (b *ChannelBudgets) save(ctx context.Context, channel string, state *channelBudgetState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshaling channel budget: %w", err)
+	}
+	if err := b.storage.Write(ctx, b.objectName(channel), data, "application/json"); err != nil {
+		return fmt.Errorf("writing channel budget: %w", err)
+	}
+	return nil
+}
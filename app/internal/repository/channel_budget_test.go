@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// digestRecordingNotifier records the posted titles and digests
+type digestRecordingNotifier struct {
+	recordingNotifier
+	titles  []string
+	digests [][]Notification
+}
+
+func (d *digestRecordingNotifier) Send(ctx context.Context, notification Notification) error {
+	d.titles = append(d.titles, notification.Title)
+	return d.recordingNotifier.Send(ctx, notification)
+}
+
+func (d *digestRecordingNotifier) SendDigest(ctx context.Context, notifications []Notification) error {
+	d.digests = append(d.digests, notifications)
+	return nil
+}
+
+func sendTitles(t *testing.T, ctx context.Context, notifier Notifier, titles ...string) {
+	t.Helper()
+	for _, title := range titles {
+		if err := notifier.Send(ctx, Notification{Title: title}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+}
+
+func TestChannelBudgets_Queue(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	budgets := NewChannelBudgets(store, 2, ChannelBudgetQueue)
+	next := &digestRecordingNotifier{}
+	notifier := budgets.Wrap(next, "#news").(Flusher)
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	ctx := WithClock(context.Background(), FixedClock(now))
+
+	sendTitles(t, ctx, notifier.(Notifier), "a", "b", "c", "d")
+	if err := notifier.(Notifier).Send(ctx, Notification{Title: "breaking", Breaking: true}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if fmt.Sprint(next.titles) != "[a b breaking]" {
+		t.Errorf("Expected the budget and breaking news to be posted, got %v", next.titles)
+	}
+
+	// Still within the hour: nothing more is posted
+	if err := notifier.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(next.titles) != 3 || next.flushed != 1 {
+		t.Errorf("Expected the outbox to wait, got %v flushed=%d", next.titles, next.flushed)
+	}
+
+	// An hour later the outbox is posted in order, two at a time
+	later := WithClock(context.Background(), FixedClock(now.Add(61*time.Minute)))
+	if err := notifier.Flush(later); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if fmt.Sprint(next.titles) != "[a b breaking c d]" || len(next.digests) != 0 {
+		t.Errorf("Expected the queued notifications in order, got %v digests=%d", next.titles, len(next.digests))
+	}
+
+	// The budget is shared by the notifiers of the channel
+	other := budgets.Wrap(next, "#news")
+	sendTitles(t, later, other, "e")
+	if len(next.titles) != 5 {
+		t.Errorf("Expected the channel budget to be used up, got %v", next.titles)
+	}
+}
+
+func TestChannelBudgets_Digest(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	next := &digestRecordingNotifier{}
+	notifier := NewChannelBudgets(store, 1, ChannelBudgetDigest).Wrap(next, "#news")
+	ctx := WithClock(context.Background(), FixedClock(time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)))
+
+	sendTitles(t, ctx, notifier, "a", "b", "c")
+	if err := notifier.(Flusher).Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if fmt.Sprint(next.titles) != "[a]" {
+		t.Errorf("Expected only the budget to be posted, got %v", next.titles)
+	}
+	if len(next.digests) != 1 || len(next.digests[0]) != 2 {
+		t.Fatalf("Expected the rest collapsed into one digest, got %v", next.digests)
+	}
+
+	// The outbox is empty afterwards
+	if err := notifier.(Flusher).Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if len(next.digests) != 1 {
+		t.Errorf("Expected no second digest, got %d", len(next.digests))
+	}
+}
+
+func TestChannelBudgets_NilWrap(t *testing.T) {
+	var budgets *ChannelBudgets
+	next := &recordingNotifier{}
+	if notifier := budgets.Wrap(next, "#news"); notifier != Notifier(next) {
+		t.Error("Expected nil budgets to leave the notifier unlimited")
+	}
+}
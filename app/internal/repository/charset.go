@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// decodeHTML converts a fetched page to UTF-8. The charset comes from a byte order mark, the
+// Content-Type header or a <meta> tag in the first 1024 bytes (Shift_JIS and EUC-JP are common on
+// Japanese sites); undeclared pages are kept as they are when they are valid UTF-8. It also returns
+// the charset the page was decoded from.
+func decodeHTML(body []byte, contentType string) (string, string) {
+	encoding, name, certain := charset.DetermineEncoding(body, contentType)
+	if name == "utf-8" || (!certain && utf8.Valid(body)) {
+		return string(body), "utf-8"
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		// 変換できないページは元のバイト列のまま渡す
+		return string(body), "utf-8"
+	}
+	return string(decoded), name
+}
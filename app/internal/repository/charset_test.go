@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestDecodeHTML(t *testing.T) {
+	const text = "日本語の記事です"
+	shiftJIS, _ := japanese.ShiftJIS.NewEncoder().String(text)
+	eucJP, _ := japanese.EUCJP.NewEncoder().String(text)
+	// A multi-byte character straddling the 1024-byte charset prescan window
+	longUTF8 := strings.Repeat("a", 1023) + text
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		charset     string
+		want        string
+	}{
+		{"header", "<p>" + shiftJIS + "</p>", "text/html; charset=Shift_JIS", "shift_jis", "<p>" + text + "</p>"},
+		{"meta charset", `<meta charset="euc-jp"><p>` + eucJP + "</p>", "text/html", "euc-jp", `<meta charset="euc-jp"><p>` + text + "</p>"},
+		{"meta http-equiv", `<meta http-equiv="Content-Type" content="text/html; charset=x-sjis"><p>` + shiftJIS + "</p>", "", "shift_jis", `<meta http-equiv="Content-Type" content="text/html; charset=x-sjis"><p>` + text + "</p>"},
+		{"utf-8 header", "<p>" + text + "</p>", "text/html; charset=utf-8", "utf-8", "<p>" + text + "</p>"},
+		{"undeclared utf-8", longUTF8, "text/html", "utf-8", longUTF8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, charset := decodeHTML([]byte(tt.body), tt.contentType)
+			if charset != tt.charset {
+				t.Errorf("Expected charset %s, got %s", tt.charset, charset)
+			}
+			if got != tt.want {
+				t.Errorf("decodeHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiRepository_FetchHTML_ShiftJIS(t *testing.T) {
+	body, _ := japanese.ShiftJIS.NewEncoder().String("<html><body><p>文字化けしない本文</p></body></html>")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{httpClient: &http.Client{Timeout: 5 * time.Second}}
+	page, err := repo.fetchHTML(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(page, "文字化けしない本文") {
+		t.Errorf("Expected the page decoded to UTF-8, got %q", page)
+	}
+}
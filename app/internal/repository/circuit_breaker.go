@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the endpoint while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Circuit breaker defaults for outbound endpoints
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerCooldown         = time.Minute
+)
+
+// circuitBreaker opens after threshold consecutive failures and, once the cooldown
+// has passed, lets a single trial call through (half-open) before closing again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	failures int
+	openedAt time.Time // zero while closed
+	trial    bool      // a half-open trial call is in flight
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may be made now
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Record feeds the outcome of an allowed call back into the breaker
+func (b *circuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if success {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	if !b.openedAt.IsZero() || b.failures >= b.threshold {
+		// A failed half-open trial restarts the cooldown
+		b.openedAt = b.now()
+	}
+}
+
+// State reports the breaker's circuit state and, unless closed, when it opened
+func (b *circuitBreaker) State() (string, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.openedAt.IsZero():
+		return CircuitClosed, time.Time{}
+	case b.trial || b.now().Sub(b.openedAt) >= b.cooldown:
+		return CircuitHalfOpen, b.openedAt
+	default:
+		return CircuitOpen, b.openedAt
+	}
+}
+
+// Breakers are shared per endpoint so feeds posting to the same URL trip together
+var (
+	endpointBreakersMu sync.Mutex
+	endpointBreakers   = make(map[string]*circuitBreaker)
+)
+
+func endpointBreaker(endpoint string) *circuitBreaker {
+	endpointBreakersMu.Lock()
+	defer endpointBreakersMu.Unlock()
+
+	breaker, exists := endpointBreakers[endpoint]
+	if !exists {
+		breaker = newCircuitBreaker(defaultBreakerFailureThreshold, defaultBreakerCooldown)
+		endpointBreakers[endpoint] = breaker
+	}
+	return breaker
+}
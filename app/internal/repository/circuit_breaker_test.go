@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	// Closed: failures below the threshold keep it closed
+	if !breaker.Allow() {
+		t.Fatal("Expected closed breaker to allow")
+	}
+	breaker.Record(false)
+	if !breaker.Allow() {
+		t.Fatal("Expected breaker to stay closed below threshold")
+	}
+	breaker.Record(false)
+
+	// Open: rejected until the cooldown passes
+	if breaker.Allow() {
+		t.Fatal("Expected breaker to open at threshold")
+	}
+
+	// Half-open: exactly one trial
+	now = now.Add(time.Minute)
+	if !breaker.Allow() {
+		t.Fatal("Expected a trial call after cooldown")
+	}
+	if breaker.Allow() {
+		t.Fatal("Expected only one trial call while half-open")
+	}
+
+	// Failed trial reopens for another cooldown
+	breaker.Record(false)
+	if breaker.Allow() {
+		t.Fatal("Expected breaker to reopen after failed trial")
+	}
+
+	// Successful trial closes
+	now = now.Add(time.Minute)
+	if !breaker.Allow() {
+		t.Fatal("Expected a trial call after second cooldown")
+	}
+	breaker.Record(true)
+	if !breaker.Allow() || !breaker.Allow() {
+		t.Fatal("Expected breaker to close after successful trial")
+	}
+}
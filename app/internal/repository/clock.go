@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the current time for date-based decisions: item age filters, active windows,
+// retention cutoffs and the timestamps that later decisions compare against (processed dates,
+// backlog failures, feed runs). Elapsed-time measurements (durations, rate limits) keep using
+// the real time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// FixedClock always returns t (tests)
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// AsOfClock starts at t and advances in real time, so that a run is replayed as it would have
+// happened at t (the CLI's -as-of flag)
+func AsOfClock(t time.Time) Clock {
+	offset := time.Until(t)
+	return ClockFunc(func() time.Time { return time.Now().Add(offset) })
+}
+
+type clockKey struct{}
+
+// WithClock returns a context whose date-based decisions use clock
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// Now returns the current time of the context's clock (the real time without one)
+func Now(ctx context.Context) time.Time {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNow(t *testing.T) {
+	if got := Now(context.Background()); time.Since(got) > time.Minute {
+		t.Errorf("Expected the real time without a clock, got %s", got)
+	}
+
+	fixed := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	if got := Now(WithClock(context.Background(), FixedClock(fixed))); !got.Equal(fixed) {
+		t.Errorf("Expected the fixed time, got %s", got)
+	}
+}
+
+func TestAsOfClock(t *testing.T) {
+	asOf := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	clock := AsOfClock(asOf)
+
+	first := clock.Now()
+	if diff := first.Sub(asOf); diff < 0 || diff > time.Minute {
+		t.Errorf("Expected the clock to start at %s, got %s", asOf, first)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !clock.Now().After(first) {
+		t.Error("Expected the clock to advance in real time")
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// cloudTasksQueuePattern matches a full Cloud Tasks queue name
+var cloudTasksQueuePattern = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/queues/[^/]+$`)
+
+// taskNameUnsafe matches characters not allowed in Cloud Tasks task IDs
+var taskNameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// ValidateCloudTasksQueue checks CLOUD_TASKS_QUEUE
+func ValidateCloudTasksQueue(queue string) error {
+	if !cloudTasksQueuePattern.MatchString(queue) {
+		return fmt.Errorf("invalid queue %q (expected projects/<project>/locations/<location>/queues/<queue>)", queue)
+	}
+	return nil
+}
+
+// CloudTasksScheduler schedules follow-up feed runs as Cloud Tasks HTTP tasks that call the feed's
+// process endpoint, so they survive instance restarts. Tasks are named after the feed and their
+// schedule slot, so a feed gets at most one follow-up per slot even when several runs ask for it.
+type CloudTasksScheduler struct {
+	queue string
+	// targetURL returns the process endpoint of a feed
+	targetURL func(feed string) string
+	// authToken authorizes the task's request to the process endpoint
+	authToken  string
+	httpClient *http.Client
+	// baseURL is the Cloud Tasks API root (overridable for tests)
+	baseURL string
+	// token returns an OAuth access token for Cloud Tasks (overridable for tests)
+	token func(ctx context.Context) (string, error)
+	now   func() time.Time
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+// NewCloudTasksScheduler creates a scheduler adding tasks to queue with application default credentials
+func NewCloudTasksScheduler(queue string, targetURL func(feed string) string, authToken string) *CloudTasksScheduler {
+	s := &CloudTasksScheduler{
+		queue:      queue,
+		targetURL:  targetURL,
+		authToken:  authToken,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    "https://cloudtasks.googleapis.com/v2",
+		now:        time.Now,
+	}
+	s.token = s.defaultToken
+	return s
+}
+
+func (s *CloudTasksScheduler) Schedule(ctx context.Context, feed string, delay time.Duration) error {
+	at := s.now().Add(delay)
+	slot := at.Truncate(delay).Unix()
+	if delay <= 0 {
+		slot = at.Unix()
+	}
+	headers := map[string]string{}
+	if s.authToken != "" {
+		headers["Authorization"] = "Bearer " + s.authToken
+	}
+	body, err := json.Marshal(map[string]any{
+		"task": map[string]any{
+			"name":         fmt.Sprintf("%s/tasks/shard-%s-%d", s.queue, taskNameUnsafe.ReplaceAllString(feed, "_"), slot),
+			"scheduleTime": at.UTC().Format(time.RFC3339),
+			"httpRequest": map[string]any{
+				"url":        s.targetURL(feed),
+				"httpMethod": "POST",
+				"headers":    headers,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding task: %w", err)
+	}
+
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/"+s.queue+"/tasks", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return httperr.Transport(fmt.Errorf("creating Cloud Task: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		// Another run already scheduled this slot's follow-up
+		return nil
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return httperr.FromResponse(resp, fmt.Errorf("creating Cloud Task failed with status %d: %s", resp.StatusCode, string(respBody)))
+	}
+}
+
+// defaultToken uses application default credentials, loaded on first use
+func (s *CloudTasksScheduler) defaultToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.source == nil {
+		source, err := google.DefaultTokenSource(context.Background(), "https://www.googleapis.com/auth/cloud-platform")
+		if err != nil {
+			return "", fmt.Errorf("loading Cloud Tasks credentials: %w", err)
+		}
+		s.source = source
+	}
+	token, err := s.source.Token()
+	if err != nil {
+		return "", fmt.Errorf("fetching Cloud Tasks token: %w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCloudTasksScheduler_Schedule(t *testing.T) {
+	const queue = "projects/p/locations/asia-northeast1/queues/shards"
+	var created []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+queue+"/tasks" || r.Header.Get("Authorization") != "Bearer api-token" {
+			t.Errorf("Unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var body struct {
+			Task map[string]any `json:"task"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Invalid body: %v", err)
+		}
+		for _, task := range created {
+			if task["name"] == body.Task["name"] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		created = append(created, body.Task)
+	}))
+	defer server.Close()
+
+	scheduler := NewCloudTasksScheduler(queue, func(feed string) string {
+		return "https://summarizer.example.com/process/feeds/" + feed
+	}, "process-token")
+	scheduler.baseURL = server.URL
+	scheduler.token = func(ctx context.Context) (string, error) { return "api-token", nil }
+	scheduler.now = func() time.Time { return time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC) }
+
+	// The second request for the same slot is a duplicate, not an error
+	for i := 0; i < 2; i++ {
+		if err := scheduler.Schedule(context.Background(), "status.page", 10*time.Minute); err != nil {
+			t.Fatalf("Schedule failed: %v", err)
+		}
+	}
+	if len(created) != 1 {
+		t.Fatalf("Expected one task, got %d", len(created))
+	}
+
+	task := created[0]
+	if name := task["name"]; name != queue+"/tasks/shard-status_page-1704111000" {
+		t.Errorf("Unexpected task name %v", name)
+	}
+	if at := task["scheduleTime"]; at != "2024-01-01T12:13:00Z" {
+		t.Errorf("Unexpected schedule time %v", at)
+	}
+	request := task["httpRequest"].(map[string]any)
+	if request["url"] != "https://summarizer.example.com/process/feeds/status.page" || request["httpMethod"] != "POST" {
+		t.Errorf("Unexpected HTTP request %v", request)
+	}
+	if headers := request["headers"].(map[string]any); headers["Authorization"] != "Bearer process-token" {
+		t.Errorf("Expected the process token, got %v", headers)
+	}
+}
+
+func TestValidateCloudTasksQueue(t *testing.T) {
+	if err := ValidateCloudTasksQueue("projects/p/locations/l/queues/q"); err != nil {
+		t.Errorf("Expected a full queue name to be valid: %v", err)
+	}
+	if err := ValidateCloudTasksQueue("shards"); err == nil {
+		t.Error("Expected a bare queue name to be rejected")
+	}
+}
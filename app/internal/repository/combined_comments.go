@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// Comment summary modes (COMMENT_SUMMARY_MODE)
+const (
+	CommentSummarySeparate = "separate" // The article and its comments are summarized in two calls (default)
+	CommentSummaryCombined = "combined" // One call summarizes both into two labeled sections
+)
+
+// Markers labelling the two sections of a combined summary
+const (
+	combinedArticleMarker  = "---記事---"
+	combinedCommentsMarker = "---コメント---"
+)
+
+// CommentCombiner is implemented by Gemini repositories that can summarize an article together with
+// its comments, halving the calls per commented item at some cost in summary quality
+type CommentCombiner interface {
+	// CombinesComments reports whether COMMENT_SUMMARY_MODE=combined
+	CombinesComments() bool
+
+	// SummarizeURLWithComments summarizes the article page and commentsText in one call. The comment
+	// summary is nil when the page had no readable content or the model left out its section.
+	SummarizeURLWithComments(ctx context.Context, url, commentsText string) (*SummarizeResponse, *SummarizeResponse, error)
+}
+
+// ValidateCommentSummaryMode checks COMMENT_SUMMARY_MODE
+func ValidateCommentSummaryMode(mode string) error {
+	if mode != CommentSummarySeparate && mode != CommentSummaryCombined {
+		return fmt.Errorf("unsupported mode %q (expected %s or %s)", mode, CommentSummarySeparate, CommentSummaryCombined)
+	}
+	return nil
+}
+
+// commentSummaryModeFromEnv reads COMMENT_SUMMARY_MODE (validated in Config)
+func commentSummaryModeFromEnv() string {
+	if mode := os.Getenv("COMMENT_SUMMARY_MODE"); ValidateCommentSummaryMode(mode) == nil {
+		return mode
+	}
+	return CommentSummarySeparate
+}
+
+func (g *geminiRepository) CombinesComments() bool {
+	return g.combineComments
+}
+
+func (g *geminiRepository) SummarizeURLWithComments(ctx context.Context, url, commentsText string) (*SummarizeResponse, *SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("HTML fetch started url=%s", url)
+	pages, err := g.fetchArticlePages(ctx, url)
+	if err != nil {
+		logger.Printf("Error fetching HTML from URL %s: %v", url, err)
+		return nil, nil, fmt.Errorf("fetching HTML: %w", err)
+	}
+	logger.Printf("HTML fetch completed url=%s content_length=%d pages=%d duration_ms=%d", url, contentLength(pages), len(pages), time.Since(start).Milliseconds())
+
+	textContent, extraction := g.extractTextFromPages(ctx, url, pages)
+	if textContent == "" {
+		// The caller retries the article on its own, so the comments are not spent on an empty page
+		summary, err := g.summarizePages(ctx, url, pages, start, false)
+		return summary, nil, err
+	}
+
+	promptText, mapReduced, err := g.preparePromptText(ctx, textContent)
+	if err != nil {
+		logger.Printf("Error in map-reduce summarization for URL %s: %v", url, err)
+		return nil, nil, err
+	}
+
+	variant := ""
+	if g.experiment != nil {
+		variant = g.experiment.Assign()
+	}
+	ctx, language := g.withPromptLanguage(ctx, textContent)
+	prompt := g.buildCombinedPrompt(promptText, commentsText, variant, language)
+
+	geminiStart := time.Now()
+	logger.Printf("Gemini API call started url=%s prompt_variant=%s combined=true comments_length=%d", url, variant, len(commentsText))
+	text, annotations, err := g.callAnnotatedSummary(ctx, prompt)
+	if err != nil {
+		logger.Printf("Error calling Gemini API for URL %s: %v", url, err)
+		return nil, nil, err
+	}
+	articleSummary, commentSummary := splitCombinedSummary(text)
+	logger.Printf("Gemini API completed url=%s summary_length=%d comment_summary_length=%d gemini_duration_ms=%d total_duration_ms=%d",
+		url, len(articleSummary), len(commentSummary), time.Since(geminiStart).Milliseconds(), time.Since(start).Milliseconds())
+
+	promptName := languagePromptName(combinedPromptName(variant), language)
+	article := &SummarizeResponse{
+		Summary:       articleSummary,
+		ProcessedAt:   time.Now(),
+		ContentChars:  len(textContent),
+		PromptVariant: variant,
+		Provenance:    g.provenance(promptName, g.pageExtraction(url, extraction, false, mapReduced)...),
+		Glossary:      annotations.Glossary,
+		Difficulty:    annotations.Difficulty,
+	}
+	if commentSummary == "" {
+		return article, nil, nil
+	}
+	return article, &SummarizeResponse{
+		Summary:      commentSummary,
+		ProcessedAt:  time.Now(),
+		ContentChars: len(commentsText),
+		Provenance:   g.provenance(promptName, ExtractionComments),
+	}, nil
+}
+
+// buildCombinedPrompt asks for the RSS summary of the article and the comment summary in one answer,
+// each under its marker line
+func (g *geminiRepository) buildCombinedPrompt(textContent, commentsText, variant, language string) string {
+	if language == SummaryLanguageEnglish {
+		return fmt.Sprintf(combinedPromptEnglish, combinedArticleMarker, combinedCommentsMarker,
+			g.buildRSSPrompt(textContent, variant, language), g.buildCommentsPrompt(commentsText, language))
+	}
+	return fmt.Sprintf(`以下の2つの依頼に、1回の回答でまとめて答えてください。
+
+**出力形式:**
+- 1行目に `+"`%s`"+` だけの行を置き、続けて依頼1の記事の要約を出力してください
+- 次に `+"`%s`"+` だけの行を置き、続けて依頼2のコメントの要約を出力してください
+- 記事の要約にコメントの内容を、コメントの要約に記事本文の内容を混ぜないでください
+- ほかに追加で出力するものがあれば、コメントの要約の後に出力してください
+
+## 依頼1: 記事の要約
+
+%s
+
+## 依頼2: コメントの要約
+
+%s`, combinedArticleMarker, combinedCommentsMarker, g.buildRSSPrompt(textContent, variant, language), g.buildCommentsPrompt(commentsText, language))
+}
+
+// splitCombinedSummary separates the article and comment sections of a combined answer
+// (the comment summary is empty when the model left out its section)
+func splitCombinedSummary(text string) (string, string) {
+	article, comments, _ := strings.Cut(text, combinedCommentsMarker)
+	if _, after, found := strings.Cut(article, combinedArticleMarker); found {
+		article = after
+	}
+	return strings.TrimSpace(article), strings.TrimSpace(comments)
+}
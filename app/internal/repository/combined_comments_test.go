@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitCombinedSummary(t *testing.T) {
+	article, comments := splitCombinedSummary(combinedArticleMarker + "\n記事の要約。\n\n" + combinedCommentsMarker + "\nコメントの要約。\n")
+	if article != "記事の要約。" || comments != "コメントの要約。" {
+		t.Errorf("Expected both sections, got %q %q", article, comments)
+	}
+
+	// Without the comment section the whole answer is the article summary
+	if article, comments := splitCombinedSummary("記事の要約だけ。"); article != "記事の要約だけ。" || comments != "" {
+		t.Errorf("Expected only the article section, got %q %q", article, comments)
+	}
+}
+
+func TestGeminiRepository_SummarizeURLWithComments(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "generateContent"):
+			var req geminiRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			prompts = append(prompts, req.Contents[0].Parts[0].Text)
+			answer := combinedArticleMarker + "\n記事の要約です。\n" + combinedCommentsMarker + "\nコメントの要約です。\n難易度: expert"
+			fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": %q}]}}]}`, answer)
+		default:
+			fmt.Fprint(w, `<html><body><article>本文</article></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:         server.URL,
+		model:           "test-model",
+		maxPages:        1,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		difficulty:      true,
+		combineComments: true,
+	}
+	if !repo.CombinesComments() {
+		t.Error("Expected the repository to combine comments")
+	}
+
+	article, comments, err := repo.SummarizeURLWithComments(context.Background(), server.URL+"/article", "良い記事だ (score 10)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "本文") || !strings.Contains(prompts[0], "良い記事だ") {
+		t.Fatalf("Expected one call with the article and the comments, got %v", prompts)
+	}
+	if article.Summary != "記事の要約です。" || article.Difficulty != DifficultyExpert {
+		t.Errorf("Unexpected article summary %+v", article)
+	}
+	if article.Provenance.Prompt != "combined:default@v1" || article.Provenance.Extraction != ExtractionHTML {
+		t.Errorf("Unexpected article provenance %+v", article.Provenance)
+	}
+	if comments == nil || comments.Summary != "コメントの要約です。" || comments.Provenance.Extraction != ExtractionComments {
+		t.Errorf("Unexpected comment summary %+v", comments)
+	}
+}
+
+func TestValidateCommentSummaryMode(t *testing.T) {
+	for _, mode := range []string{CommentSummarySeparate, CommentSummaryCombined} {
+		if err := ValidateCommentSummaryMode(mode); err != nil {
+			t.Errorf("Expected %s to be valid: %v", mode, err)
+		}
+	}
+	if err := ValidateCommentSummaryMode("single"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func compress(t *testing.T, encoding, text string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "zlib":
+		w = zlib.NewWriter(&b)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+	}
+	w.Write([]byte(text))
+	w.Close()
+	return b.Bytes()
+}
+
+func TestDecodedBody(t *testing.T) {
+	const text = "<rss><channel><title>Feed</title></channel></rss>"
+	tests := []struct {
+		name            string
+		contentEncoding string
+		contentType     string
+		body            []byte
+	}{
+		{"gzip", "gzip", "application/rss+xml", compress(t, "gzip", text)},
+		{"zlib deflate", "deflate", "application/rss+xml", compress(t, "zlib", text)},
+		{"raw deflate", "deflate", "application/rss+xml", compress(t, "raw-deflate", text)},
+		{"gzip file", "", "application/x-gzip", compress(t, "gzip", text)},
+		{"plain body labelled gzip", "gzip", "application/rss+xml", []byte(text)},
+		{"identity", "", "application/rss+xml", []byte(text)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(tt.body))}
+			resp.Header.Set("Content-Encoding", tt.contentEncoding)
+			resp.Header.Set("Content-Type", tt.contentType)
+
+			reader, err := decodedBody(resp)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil || string(got) != text {
+				t.Errorf("Expected the decoded feed, got %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestCompressedFetches(t *testing.T) {
+	const page = "<html><body><p>Compressed page</p></body></html>"
+	var acceptEncodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncodings = append(acceptEncodings, r.Header.Get("Accept-Encoding"))
+		if strings.HasSuffix(r.URL.Path, ".xml") {
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(compress(t, "zlib", "<rss></rss>"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compress(t, "gzip", page))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	repo := &geminiRepository{httpClient: &http.Client{Timeout: 5 * time.Second}}
+	html, err := repo.fetchHTML(ctx, server.URL+"/article")
+	if err != nil || html != page {
+		t.Errorf("Expected the decompressed page, got %q, %v", html, err)
+	}
+
+	feed, err := NewRSSRepository().FetchFeedXML(ctx, server.URL+"/feed.xml", map[string]string{"User-Agent": "test"})
+	if err != nil || feed != "<rss></rss>" {
+		t.Errorf("Expected the decompressed feed, got %q, %v", feed, err)
+	}
+
+	for _, encoding := range acceptEncodings {
+		if encoding != acceptEncoding {
+			t.Errorf("Expected Accept-Encoding %q, got %q", acceptEncoding, encoding)
+		}
+	}
+}
+
+func TestCompressedFetches_DecompressionBomb(t *testing.T) {
+	defer func(max int64) { maxDecodedBodyBytes = max }(maxDecodedBodyBytes)
+	maxDecodedBodyBytes = 1 << 20
+
+	// A few KB of gzip expanding to 8 MB of zeros
+	var bomb bytes.Buffer
+	w := gzip.NewWriter(&bomb)
+	w.Write(make([]byte, 8<<20))
+	w.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb.Bytes())
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	repo := &geminiRepository{httpClient: &http.Client{Timeout: 5 * time.Second}}
+	if _, err := repo.fetchHTML(ctx, server.URL+"/article"); !errors.Is(err, errDecodedBodyTooLarge) || !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent too-large error for the page, got %v", err)
+	}
+	if _, err := NewRSSRepository().FetchFeedXML(ctx, server.URL+"/feed.xml", nil); !errors.Is(err, errDecodedBodyTooLarge) {
+		t.Errorf("Expected a too-large error for the feed, got %v", err)
+	}
+
+	// Bodies up to the limit are read in full
+	exact := compress(t, "gzip", strings.Repeat("a", int(maxDecodedBodyBytes)))
+	resp := &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}, Body: io.NopCloser(bytes.NewReader(exact))}
+	reader, err := decodedBody(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, err := io.ReadAll(reader); err != nil || int64(len(got)) != maxDecodedBodyBytes {
+		t.Errorf("Expected %d bytes, got %d, %v", maxDecodedBodyBytes, len(got), err)
+	}
+}
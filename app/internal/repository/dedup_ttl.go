@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DedupTTLRepository is a ProcessedArticleRepository decorator that lets a feed's processed marks
+// expire: an article processed longer than the feed's TTL ago counts as new again, so that an
+// article resurfacing months later (trending again on Hatena) is summarized again, and its entry
+// is overwritten. Feeds without a TTL deduplicate forever. Everything else passes through.
+type DedupTTLRepository struct {
+	ProcessedArticleRepository
+	ttls map[string]time.Duration // By feed name
+}
+
+func NewDedupTTLRepository(base ProcessedArticleRepository, ttls map[string]time.Duration) *DedupTTLRepository {
+	return &DedupTTLRepository{
+		ProcessedArticleRepository: base,
+		ttls:                       ttls,
+	}
+}
+
+// TTL returns the feed's dedup TTL (0 keeps its marks forever)
+func (r *DedupTTLRepository) TTL(feed string) time.Duration {
+	return r.ttls[feed]
+}
+
+// Expired reports whether the feed's TTL has passed since the entry was processed. Entries
+// without a processed date (imported from older indexes) never expire.
+func (r *DedupTTLRepository) Expired(ctx context.Context, feed string, entry *IndexEntry) bool {
+	ttl := r.ttls[feed]
+	if ttl <= 0 || entry == nil || entry.ProcessedDate.IsZero() {
+		return false
+	}
+	return entry.ProcessedDate.Before(Now(ctx).Add(-ttl))
+}
+
+// Unwrap returns the decorated repository, for its optional interfaces
+func (r *DedupTTLRepository) Unwrap() ProcessedArticleRepository {
+	return r.ProcessedArticleRepository
+}
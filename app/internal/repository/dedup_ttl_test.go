@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDedupTTLRepository_Expired(t *testing.T) {
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	ctx := WithClock(context.Background(), FixedClock(now))
+	repo := NewDedupTTLRepository(NewMemoryProcessedArticleRepository(), map[string]time.Duration{"hatena": 90 * 24 * time.Hour})
+
+	tests := []struct {
+		name     string
+		feed     string
+		entry    *IndexEntry
+		expected bool
+	}{
+		{"within ttl", "hatena", &IndexEntry{ProcessedDate: now.AddDate(0, 0, -30)}, false},
+		{"past ttl", "hatena", &IndexEntry{ProcessedDate: now.AddDate(0, 0, -91)}, true},
+		{"feed without ttl", "lobsters", &IndexEntry{ProcessedDate: now.AddDate(-2, 0, 0)}, false},
+		{"no processed date", "hatena", &IndexEntry{}, false},
+		{"no entry", "hatena", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repo.Expired(ctx, tt.feed, tt.entry); got != tt.expected {
+				t.Errorf("Expected expired=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
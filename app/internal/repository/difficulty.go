@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// Audience levels of an article (DIFFICULTY_ENABLED)
+const (
+	DifficultyBeginner     = "beginner"
+	DifficultyIntermediate = "intermediate"
+	DifficultyExpert       = "expert"
+)
+
+// difficultyLevels are the levels from easiest to hardest
+var difficultyLevels = []string{DifficultyBeginner, DifficultyIntermediate, DifficultyExpert}
+
+// difficultyInstruction asks for the level in the same call as the summary
+const difficultyInstruction = "\n\n**難易度:** 最後に、記事が前提とする知識に応じて `難易度: beginner`（新人・入門者でも読める）、" +
+	"`難易度: intermediate`（実務経験者向け）、`難易度: expert`（専門家向け）のいずれか1行を追加してください。"
+
+// difficultyLinePattern matches the level line the model appends
+var difficultyLinePattern = regexp.MustCompile(`(?mi)^[ \t*]*難易度[ \t*]*[:：][ \t*` + "`" + `]*(beginner|intermediate|expert)[ \t*` + "`" + `]*$\n?`)
+
+// splitDifficulty separates the model's level line from the summary ("" when it gave none)
+func splitDifficulty(text string) (string, string) {
+	m := difficultyLinePattern.FindStringSubmatchIndex(text)
+	if m == nil {
+		return text, ""
+	}
+	level := strings.ToLower(text[m[2]:m[3]])
+	return strings.TrimSpace(text[:m[0]] + text[m[1]:]), level
+}
+
+// ParseChannelLevels parses SLACK_CHANNEL_LEVELS, e.g. "#new-grads:beginner+intermediate,#sre:expert":
+// the Slack channels that only get articles of the listed levels
+func ParseChannelLevels(raw string) (map[string][]string, error) {
+	channelLevels := make(map[string][]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		channel, rawLevels, ok := strings.Cut(entry, ":")
+		channel = strings.TrimPrefix(strings.TrimSpace(channel), "#")
+		if !ok || channel == "" {
+			return nil, fmt.Errorf("invalid entry %q (expected channel:level+level)", entry)
+		}
+		var levels []string
+		for _, level := range strings.Split(rawLevels, "+") {
+			level = strings.ToLower(strings.TrimSpace(level))
+			if !slices.Contains(difficultyLevels, level) {
+				return nil, fmt.Errorf("unknown level %q for channel %s (expected %s, %s or %s)", level, channel, DifficultyBeginner, DifficultyIntermediate, DifficultyExpert)
+			}
+			levels = append(levels, level)
+		}
+		channelLevels[channel] = levels
+	}
+	return channelLevels, nil
+}
+
+// ChannelLevels returns the levels a Slack channel is limited to (nil when it gets every article)
+func ChannelLevels(channelLevels map[string][]string, channel string) []string {
+	return channelLevels[strings.TrimPrefix(channel, "#")]
+}
+
+type levelFilterNotifier struct {
+	next   Notifier
+	levels []string
+}
+
+// NewLevelFilter passes on only notifications of articles at one of levels, so that e.g. a
+// new graduates' channel only gets approachable content. Unclassified articles are held back too;
+// on-demand summaries were asked for and always pass.
+func NewLevelFilter(next Notifier, levels []string) Notifier {
+	return &levelFilterNotifier{next: next, levels: levels}
+}
+
+func (f *levelFilterNotifier) Send(ctx context.Context, notification Notification) error {
+	if !slices.Contains(f.levels, notification.Difficulty) {
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+		logger.Printf("Skipping notification outside channel levels title=%s difficulty=%s levels=%s",
+			notification.Title, notification.Difficulty, strings.Join(f.levels, "+"))
+		return nil
+	}
+	return f.next.Send(ctx, notification)
+}
+
+func (f *levelFilterNotifier) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	return f.next.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush forwards to a batching notifier
+func (f *levelFilterNotifier) Flush(ctx context.Context) error {
+	if flusher, ok := f.next.(Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
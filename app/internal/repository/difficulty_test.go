@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSplitDifficulty(t *testing.T) {
+	summary, level := splitDifficulty("CRDT による同期の解説。\n**難易度:** Expert\n" + glossaryMarker + "\n- CRDT: 競合なく複製できるデータ型")
+	if level != DifficultyExpert {
+		t.Errorf("Expected expert, got %q", level)
+	}
+	if summary != "CRDT による同期の解説。\n"+glossaryMarker+"\n- CRDT: 競合なく複製できるデータ型" {
+		t.Errorf("Expected only the level line removed, got %q", summary)
+	}
+
+	if summary, level := splitDifficulty("難易度の高い話題を扱う記事。"); level != "" || summary != "難易度の高い話題を扱う記事。" {
+		t.Errorf("Expected prose to be kept unclassified, got %q %q", summary, level)
+	}
+}
+
+func TestParseChannelLevels(t *testing.T) {
+	channelLevels, err := ParseChannelLevels("#new-grads:beginner+Intermediate, sre:expert")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if levels := ChannelLevels(channelLevels, "#new-grads"); strings.Join(levels, "+") != "beginner+intermediate" {
+		t.Errorf("Unexpected levels %v", levels)
+	}
+	if levels := ChannelLevels(channelLevels, "#sre"); strings.Join(levels, "+") != "expert" {
+		t.Errorf("Unexpected levels %v", levels)
+	}
+	if levels := ChannelLevels(channelLevels, "#general"); levels != nil {
+		t.Errorf("Expected an unlimited channel, got %v", levels)
+	}
+
+	for _, raw := range []string{"#new-grads", "#new-grads:novice", ":beginner"} {
+		if _, err := ParseChannelLevels(raw); err == nil {
+			t.Errorf("Expected an error for %q", raw)
+		}
+	}
+}
+
+func TestLevelFilter(t *testing.T) {
+	next := &recordingNotifier{}
+	notifier := NewLevelFilter(next, []string{DifficultyBeginner, DifficultyIntermediate})
+	ctx := context.Background()
+
+	notifier.Send(ctx, Notification{Title: "入門", Difficulty: DifficultyBeginner})
+	notifier.Send(ctx, Notification{Title: "論文", Difficulty: DifficultyExpert})
+	notifier.Send(ctx, Notification{Title: "未分類"})
+	if next.sent != 1 {
+		t.Errorf("Expected only the beginner article to pass, got %d sends", next.sent)
+	}
+
+	notifier.SendOnDemandSummary(ctx, Item{Link: "https://example.com"}, SummarizeResponse{Difficulty: DifficultyExpert}, "#new-grads")
+	if next.sent != 2 {
+		t.Errorf("Expected on-demand summaries to pass, got %d sends", next.sent)
+	}
+
+	notifier.(Flusher).Flush(ctx)
+	if next.flushed != 1 {
+		t.Errorf("Expected Flush to be forwarded")
+	}
+}
+
+func TestSlackRepository_DifficultyTag(t *testing.T) {
+	slack := NewSlackRepository("xoxb-test", "#test", "https://slack.example.com").(*slackRepository)
+
+	message := slack.formatNotification(Notification{Title: "Test", URL: "https://example.com", Summary: "要約", Difficulty: DifficultyBeginner})
+	if !strings.Contains(message, "🎓 難易度: 🟢 初級") {
+		t.Errorf("Expected the difficulty tag, got %s", message)
+	}
+	message = slack.formatNotification(Notification{Title: "Test - コメント", URL: "https://example.com", Summary: "要約", Difficulty: DifficultyBeginner, Comment: true})
+	if strings.Contains(message, "難易度") {
+		t.Errorf("Unexpected difficulty tag on a comment summary: %s", message)
+	}
+	message = slack.formatNotification(Notification{Title: "Test", URL: "https://example.com", Summary: "要約"})
+	if strings.Contains(message, "難易度") {
+		t.Errorf("Unexpected difficulty tag on an unclassified article: %s", message)
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// Discord embed limits
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+)
+
+// Discord embed colors per source
+var discordSourceColors = map[string]int{
+	"reddit":       0xFF4500,
+	"hatena":       0x00A4DE,
+	"lobsters":     0xAC130D,
+	SourceOnDemand: 0x5865F2,
+}
+
+type discordRepository struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewDiscordRepository creates a Notifier posting to a Discord channel webhook
+func NewDiscordRepository(webhookURL string) Notifier {
+	return &discordRepository{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type discordWebhookRequest struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	Embeds    []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+func (d *discordRepository) sendMessage(ctx context.Context, req discordWebhookRequest) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	req.Username = "Article Summarizer"
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		logger.Printf("Error marshaling Discord request: %v", err)
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", d.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		logger.Printf("Error creating Discord HTTP request: %v", err)
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(httpReq)
+	if err != nil {
+		logger.Printf("Error sending request to Discord webhook: %v request_body=%s\nStack:\n%s", err, string(body), debug.Stack())
+		return httperr.Transport(fmt.Errorf("sending request: %w", err))
+	}
+	defer resp.Body.Close()
+
+	// Discord returns 204 No Content on success (200 with ?wait=true)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(resp.Body)
+		logger.Printf("Discord webhook request failed status_code=%d request_body=%s response_headers=%v response_body=%s\nStack:\n%s",
+			resp.StatusCode, string(body), resp.Header, string(responseBody), debug.Stack())
+		return httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// Send sends a unified notification (article or comment summary) as an embed
+func (d *discordRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Discord notification started title=%s source=%s", notification.Title, notification.Source)
+
+	if err := d.sendMessage(ctx, d.formatNotification(notification)); err != nil {
+		logger.Printf("Error sending notification to Discord: %v", err)
+		return err
+	}
+
+	duration := time.Since(start)
+	logger.Printf("Discord notification completed title=%s source=%s duration_ms=%d",
+		notification.Title, notification.Source, duration.Milliseconds())
+	return nil
+}
+
+// SendOnDemandSummary sends an on-demand summary. Discord webhooks are bound to one channel,
+// so targetChannel is ignored.
+func (d *discordRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("On-demand Discord notification started url=%s", article.Link)
+	if err := d.sendMessage(ctx, d.formatOnDemandMessage(article, summary)); err != nil {
+		logger.Printf("Error sending on-demand summary to Discord: %v", err)
+		return err
+	}
+
+	duration := time.Since(start)
+	logger.Printf("On-demand Discord notification completed url=%s duration_ms=%d", article.Link, duration.Milliseconds())
+	return nil
+}
+
+func (d *discordRepository) formatNotification(notification Notification) discordWebhookRequest {
+	footer := "⏰ 処理時刻"
+	if notification.PromptVariant != "" {
+		footer = fmt.Sprintf("🧪 プロンプト: %s", notification.PromptVariant)
+	}
+
+	return discordWebhookRequest{
+		Embeds: []discordEmbed{
+			{
+				Title:       truncateRunes(notification.Title, discordTitleLimit),
+				URL:         notification.URL,
+				Description: truncateRunes(notification.Summary, discordDescriptionLimit),
+				Color:       discordSourceColors[notification.Source],
+				Fields: []discordEmbedField{
+					{Name: "📰 ソース", Value: notification.Source, Inline: true},
+					{Name: "📊 コンテンツ文字数", Value: strconv.Itoa(notification.ContentChars) + "文字", Inline: true},
+				},
+				Footer:    &discordEmbedFooter{Text: footer},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+func (d *discordRepository) formatOnDemandMessage(article Item, summary SummarizeResponse) discordWebhookRequest {
+	title := article.Title
+	if title == "" {
+		title = article.Link
+	}
+
+	return discordWebhookRequest{
+		Content: "🔗 **オンデマンド要約リクエスト完了**",
+		Embeds: []discordEmbed{
+			{
+				Title:       truncateRunes(title, discordTitleLimit),
+				URL:         article.Link,
+				Description: truncateRunes(summary.Summary, discordDescriptionLimit),
+				Color:       discordSourceColors[SourceOnDemand],
+				Fields: []discordEmbedField{
+					{Name: "📊 コンテンツ文字数", Value: strconv.Itoa(summary.ContentChars) + "文字", Inline: true},
+				},
+				Footer:    &discordEmbedFooter{Text: "📝 要約方法: オンデマンドAPI"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
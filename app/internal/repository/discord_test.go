@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiscordRepository_Send(t *testing.T) {
+	var received discordWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordRepository(server.URL)
+	err := notifier.Send(context.Background(), Notification{
+		Title:         "Test Article",
+		Source:        "reddit",
+		URL:           "https://example.com/article",
+		Summary:       strings.Repeat("あ", 5000),
+		ContentChars:  1234,
+		PromptVariant: "concise",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(received.Embeds) != 1 {
+		t.Fatalf("Expected 1 embed, got %d", len(received.Embeds))
+	}
+	embed := received.Embeds[0]
+	if embed.Title != "Test Article" || embed.URL != "https://example.com/article" {
+		t.Errorf("Unexpected embed title/url: %s %s", embed.Title, embed.URL)
+	}
+	if n := len([]rune(embed.Description)); n > discordDescriptionLimit {
+		t.Errorf("Description should be truncated to %d runes, got %d", discordDescriptionLimit, n)
+	}
+	if embed.Color != discordSourceColors["reddit"] {
+		t.Errorf("Expected reddit color, got %x", embed.Color)
+	}
+	if embed.Footer == nil || !strings.Contains(embed.Footer.Text, "concise") {
+		t.Errorf("Expected prompt variant in footer, got %+v", embed.Footer)
+	}
+}
+
+func TestDiscordRepository_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "Invalid Webhook Token"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordRepository(server.URL)
+	err := notifier.SendOnDemandSummary(context.Background(), Item{Link: "https://example.com"}, SummarizeResponse{Summary: "summary"}, "")
+	if err == nil {
+		t.Fatal("Expected error for non-2xx response")
+	}
+}
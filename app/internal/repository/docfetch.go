@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// DocumentFetcher fetches internal documentation pages through their authenticated APIs,
+// where a plain GET would only return the login page. Fetch returns HTML so the usual
+// title and text extraction apply.
+type DocumentFetcher interface {
+	Matches(pageURL *url.URL) bool
+	Fetch(ctx context.Context, pageURL string) (string, error)
+}
+
+// fetcherFor returns the first fetcher handling pageURL, or nil for public pages
+func fetcherFor(fetchers []DocumentFetcher, pageURL string) DocumentFetcher {
+	if len(fetchers) == 0 {
+		return nil
+	}
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	for _, fetcher := range fetchers {
+		if fetcher.Matches(parsed) {
+			return fetcher
+		}
+	}
+	return nil
+}
+
+// documentHTML wraps a fetched document body so extractTitleFromHTML finds its title
+func documentHTML(title, body string) string {
+	return "<html><head><title>" + html.EscapeString(title) + "</title></head><body>" + body + "</body></html>"
+}
+
+// confluencePageIDPattern matches /wiki/spaces/<KEY>/pages/<id>[/<title>] page URLs
+var confluencePageIDPattern = regexp.MustCompile(`/pages/(\d+)`)
+
+// ConfluenceFetcher reads Confluence Cloud pages via the REST API with an API token (basic auth)
+type ConfluenceFetcher struct {
+	baseURL    string
+	host       string
+	email      string
+	apiToken   string
+	httpClient *http.Client
+}
+
+// NewConfluenceFetcher creates a fetcher for the site at baseURL (e.g. https://example.atlassian.net)
+func NewConfluenceFetcher(baseURL, email, apiToken string, httpClient *http.Client) (*ConfluenceFetcher, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Confluence base URL %q", baseURL)
+	}
+	return &ConfluenceFetcher{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		host:       parsed.Host,
+		email:      email,
+		apiToken:   apiToken,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (c *ConfluenceFetcher) Matches(pageURL *url.URL) bool {
+	return pageURL.Host == c.host
+}
+
+func (c *ConfluenceFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	pageID, err := confluencePageID(pageURL)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/wiki/rest/api/content/"+pageID+"?expand=body.view", nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.SetBasicAuth(c.email, c.apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("fetching Confluence page: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		// Confluence answers 404 for pages the token's user cannot see
+		return "", httperr.FromResponse(resp, fmt.Errorf("confluence page %s not accessible with CONFLUENCE_EMAIL's API token (status %d)", pageID, resp.StatusCode))
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return "", httperr.FromResponse(resp, fmt.Errorf("confluence API request failed with status %d: %s", resp.StatusCode, string(body)))
+	}
+
+	var page struct {
+		Title string `json:"title"`
+		Body  struct {
+			View struct {
+				Value string `json:"value"`
+			} `json:"view"`
+		} `json:"body"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return "", fmt.Errorf("decoding Confluence page: %w", err)
+	}
+	return documentHTML(page.Title, page.Body.View.Value), nil
+}
+
+// confluencePageID extracts the page ID from a /pages/<id> or ?pageId=<id> URL
+func confluencePageID(pageURL string) (string, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing Confluence URL: %w", err)
+	}
+	if id := parsed.Query().Get("pageId"); id != "" {
+		return id, nil
+	}
+	if m := confluencePageIDPattern.FindStringSubmatch(parsed.Path); m != nil {
+		return m[1], nil
+	}
+	return "", fmt.Errorf("no Confluence page ID in %s (tiny links are not supported)", pageURL)
+}
+
+// googleDocIDPattern matches docs.google.com/document/d/<id>/... URLs
+var googleDocIDPattern = regexp.MustCompile(`^/document/(?:u/\d+/)?d/([a-zA-Z0-9_-]+)`)
+
+// GoogleDocsFetcher exports Google Docs as HTML via the Drive API. The documents must be shared
+// with the service account (or readable by the OAuth user) configured in GOOGLE_DOCS_AUTH.
+type GoogleDocsFetcher struct {
+	// driveBaseURL is the Drive API root (overridable for tests)
+	driveBaseURL string
+	httpClient   *http.Client
+	// token returns an OAuth access token with Drive read access (overridable for tests)
+	token func(ctx context.Context) (string, error)
+
+	mu     sync.Mutex
+	auth   string
+	source oauth2.TokenSource
+}
+
+// NewGoogleDocsFetcher creates a fetcher authenticated by auth: "adc" for the runtime service account,
+// otherwise the path to a service account or OAuth authorized-user JSON key
+func NewGoogleDocsFetcher(auth string, httpClient *http.Client) *GoogleDocsFetcher {
+	f := &GoogleDocsFetcher{
+		driveBaseURL: "https://www.googleapis.com/drive/v3",
+		httpClient:   httpClient,
+		auth:         auth,
+	}
+	f.token = f.defaultToken
+	return f
+}
+
+func (f *GoogleDocsFetcher) Matches(pageURL *url.URL) bool {
+	return pageURL.Host == "docs.google.com" && googleDocIDPattern.MatchString(pageURL.Path)
+}
+
+func (f *GoogleDocsFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing Google Docs URL: %w", err)
+	}
+	m := googleDocIDPattern.FindStringSubmatch(parsed.Path)
+	if m == nil {
+		return "", fmt.Errorf("no document ID in %s", pageURL)
+	}
+	docID := m[1]
+
+	token, err := f.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", f.driveBaseURL+"/files/"+docID+"/export?mimeType=text%2Fhtml", nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("exporting Google Doc: %w", err))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return "", httperr.FromResponse(resp, fmt.Errorf("google doc %s not accessible with GOOGLE_DOCS_AUTH credentials; share it with the service account (status %d)", docID, resp.StatusCode))
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return "", httperr.FromResponse(resp, fmt.Errorf("drive export failed with status %d: %s", resp.StatusCode, string(body)))
+	}
+
+	// The exported document already carries its title in <title>
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading Google Doc export: %w", err)
+	}
+	return string(body), nil
+}
+
+// defaultToken loads the configured credentials on first use
+func (f *GoogleDocsFetcher) defaultToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.source == nil {
+		const scope = "https://www.googleapis.com/auth/drive.readonly"
+		if f.auth == "adc" {
+			source, err := google.DefaultTokenSource(context.Background(), scope)
+			if err != nil {
+				return "", fmt.Errorf("loading Google Docs credentials: %w", err)
+			}
+			f.source = source
+		} else {
+			key, err := os.ReadFile(f.auth)
+			if err != nil {
+				return "", fmt.Errorf("reading Google Docs credentials: %w", err)
+			}
+			creds, err := google.CredentialsFromJSON(context.Background(), key, scope)
+			if err != nil {
+				return "", fmt.Errorf("parsing Google Docs credentials: %w", err)
+			}
+			f.source = creds.TokenSource
+		}
+	}
+	token, err := f.source.Token()
+	if err != nil {
+		return "", fmt.Errorf("fetching Google Docs token: %w", err)
+	}
+	return token.AccessToken, nil
+}
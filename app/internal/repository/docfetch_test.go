@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfluencePageID(t *testing.T) {
+	tests := map[string]string{
+		"https://example.atlassian.net/wiki/spaces/ENG/pages/12345/Deploy+Guide": "12345",
+		"https://example.atlassian.net/wiki/spaces/ENG/pages/678":                "678",
+		"https://example.atlassian.net/wiki/pages/viewpage.action?pageId=999":    "999",
+	}
+	for pageURL, want := range tests {
+		if got, err := confluencePageID(pageURL); err != nil || got != want {
+			t.Errorf("%s: expected %s, got %s (%v)", pageURL, want, got, err)
+		}
+	}
+
+	if _, err := confluencePageID("https://example.atlassian.net/wiki/x/AbCd"); err == nil {
+		t.Error("Expected error for a tiny link")
+	}
+}
+
+func TestConfluenceFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		if !ok || user != "bot@example.com" || token != "api-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/wiki/rest/api/content/12345" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"title":"Deploy <Guide>","body":{"view":{"value":"<p>Run make deploy.</p>"}}}`))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewConfluenceFetcher(server.URL, "bot@example.com", "api-token", server.Client())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	html, err := fetcher.Fetch(context.Background(), server.URL+"/wiki/spaces/ENG/pages/12345/Deploy+Guide")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(html, "<title>Deploy &lt;Guide&gt;</title>") || !strings.Contains(html, "Run make deploy.") {
+		t.Errorf("Unexpected HTML: %s", html)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/wiki/spaces/ENG/pages/404"); err == nil || !strings.Contains(err.Error(), "not accessible") {
+		t.Errorf("Expected access error, got %v", err)
+	}
+}
+
+func TestGoogleDocsFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer docs-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/files/doc-123_x/export" || r.URL.Query().Get("mimeType") != "text/html" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`<html><head><title>Design Doc</title></head><body><p>Overview</p></body></html>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewGoogleDocsFetcher("adc", server.Client())
+	fetcher.driveBaseURL = server.URL
+	fetcher.token = func(ctx context.Context) (string, error) { return "docs-token", nil }
+
+	html, err := fetcher.Fetch(context.Background(), "https://docs.google.com/document/d/doc-123_x/edit#heading=h.1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(html, "Design Doc") {
+		t.Errorf("Unexpected HTML: %s", html)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), "https://docs.google.com/document/d/private/edit"); err == nil || !strings.Contains(err.Error(), "share it with the service account") {
+		t.Errorf("Expected access error, got %v", err)
+	}
+}
+
+func TestGeminiRepository_SummarizesConfluencePage(t *testing.T) {
+	var prompt string
+	gemini := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "summary"}]}}]}`))
+	}))
+	defer gemini.Close()
+	confluence := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/wiki/rest/api/content/") {
+			// The login page a plain GET would see
+			w.Write([]byte(`<html><body>Log in to continue</body></html>`))
+			return
+		}
+		w.Write([]byte(`{"title":"Runbook","body":{"view":{"value":"<p>Restart the worker pool first.</p>"}}}`))
+	}))
+	defer confluence.Close()
+
+	t.Setenv("CONFLUENCE_BASE_URL", confluence.URL)
+	t.Setenv("CONFLUENCE_EMAIL", "bot@example.com")
+	t.Setenv("CONFLUENCE_API_TOKEN", "api-token")
+	repo := NewGeminiRepository("test-key", "test-model", gemini.URL)
+	repo.(*geminiRepository).httpClient.Timeout = 5 * time.Second
+
+	resp, err := repo.SummarizeOnDemand(context.Background(), confluence.URL+"/wiki/spaces/OPS/pages/42/Runbook")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Title != "Runbook" {
+		t.Errorf("Expected title from the API, got %q", resp.Title)
+	}
+	if !strings.Contains(prompt, "Restart the worker pool first.") || strings.Contains(prompt, "Log in to continue") {
+		t.Errorf("Expected the page body in the prompt, got %s", prompt)
+	}
+}
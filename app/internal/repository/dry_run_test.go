@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingBacklog counts the writes reaching the wrapped backlog
+type recordingBacklog struct {
+	entries []*BacklogEntry
+	writes  int
+}
+
+func (b *recordingBacklog) Record(ctx context.Context, feed string, article Item, cause error) error {
+	b.writes++
+	return nil
+}
+
+func (b *recordingBacklog) List(ctx context.Context) ([]*BacklogEntry, error) {
+	return b.entries, nil
+}
+
+func (b *recordingBacklog) Remove(ctx context.Context, key string) error {
+	b.writes++
+	return nil
+}
+
+func (b *recordingBacklog) Close() error {
+	return nil
+}
+
+func TestDryRunProcessedRepository(t *testing.T) {
+	base := NewMemoryProcessedArticleRepository()
+	ctx := context.Background()
+	known := Item{Link: "https://example.com/known"}
+	if err := base.MarkAsProcessed(ctx, known); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := NewDryRunProcessedRepository(base)
+
+	// Dedup still sees the production index
+	index, _ := repo.LoadIndex(ctx)
+	if !repo.IsProcessed(repo.GenerateKey(known), index) {
+		t.Error("Expected the known article to be processed")
+	}
+
+	fresh := Item{Link: "https://example.com/fresh"}
+	if err := repo.MarkAsProcessed(ctx, fresh); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if added, err := repo.MarkManyAsProcessed(ctx, []Item{fresh}); err != nil || added != 0 {
+		t.Errorf("Expected nothing marked, got %d: %v", added, err)
+	}
+	if removed, err := repo.UnmarkProcessed(ctx, known); err != nil || removed {
+		t.Errorf("Expected nothing unmarked, got %t: %v", removed, err)
+	}
+	if pruned, err := repo.Prune(ctx, time.Now().Add(time.Hour)); err != nil || pruned != 0 {
+		t.Errorf("Expected nothing pruned, got %d: %v", pruned, err)
+	}
+
+	index, _ = base.LoadIndex(ctx)
+	if len(index) != 1 || !base.IsProcessed(base.GenerateKey(known), index) {
+		t.Errorf("Expected the index unchanged, got %v", index)
+	}
+}
+
+func TestDryRunBacklogRepository(t *testing.T) {
+	base := &recordingBacklog{entries: []*BacklogEntry{{Feed: "hatena", Item: Item{Link: "https://example.com/a"}}}}
+	repo := NewDryRunBacklogRepository(base)
+	ctx := context.Background()
+
+	if entries, _ := repo.List(ctx); len(entries) != 1 {
+		t.Errorf("Expected the backlog to be listed, got %v", entries)
+	}
+	repo.Record(ctx, "hatena", Item{Link: "https://example.com/b"}, errors.New("timeout"))
+	repo.Remove(ctx, "hatena|https://example.com/a")
+	if base.writes != 0 {
+		t.Errorf("Expected no backlog writes, got %d", base.writes)
+	}
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const (
+	defaultDynamoDBTable = "processed-articles"
+	// dynamoDBBatchSize is the most requests a BatchWriteItem call accepts
+	dynamoDBBatchSize = 25
+	// dynamoDBBatchRetries bounds the resubmission of unprocessed (throttled) batch items
+	dynamoDBBatchRetries = 5
+)
+
+// dynamoDBConditionFailed is the error type of a PutItem whose condition did not hold
+const dynamoDBConditionFailed = "ConditionalCheckFailedException"
+
+// dynamoDBProcessedRepository keeps one DynamoDB item per processed article (partition key "url",
+// the normalized URL), for AWS deployments without Google credentials. Requests use the DynamoDB
+// JSON API signed with Signature Version 4, like s3Storage, so no SDK is needed.
+type dynamoDBProcessedRepository struct {
+	httpClient  *http.Client
+	endpoint    string
+	region      string
+	table       string
+	credentials *awsCredentialSource
+	now         func() time.Time
+}
+
+// newDynamoDBProcessedRepository reads DYNAMODB_TABLE (default processed-articles), DYNAMODB_REGION
+// (default AWS_REGION, then us-east-1), DYNAMODB_ENDPOINT (e.g. DynamoDB Local) and the AWS_*
+// credentials, or on ECS the task role (see newAWSCredentialSource)
+func newDynamoDBProcessedRepository() (*dynamoDBProcessedRepository, error) {
+	region := firstEnv("DYNAMODB_REGION", "AWS_REGION")
+	if region == "" {
+		region = defaultS3Region
+	}
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://dynamodb." + region + ".amazonaws.com"
+	}
+	if parsed, err := url.Parse(endpoint); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid DynamoDB endpoint %q", endpoint)
+	}
+	table := defaultDynamoDBTable
+	if env := os.Getenv("DYNAMODB_TABLE"); env != "" {
+		table = env
+	}
+
+	credentials := newAWSCredentialSource(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	if credentials == nil {
+		return nil, errors.New("AWS credentials are required when CACHE_TYPE=dynamodb (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or an ECS task role)")
+	}
+	return &dynamoDBProcessedRepository{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		endpoint:    strings.TrimRight(endpoint, "/"),
+		region:      region,
+		table:       table,
+		credentials: credentials,
+		now:         time.Now,
+	}, nil
+}
+
+// LoadIndex scans the whole table
+func (r *dynamoDBProcessedRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	index := make(map[string]*IndexEntry)
+	var startKey map[string]dynamoDBValue
+	for {
+		request := map[string]any{"TableName": r.table}
+		if startKey != nil {
+			request["ExclusiveStartKey"] = startKey
+		}
+		var page struct {
+			Items            []map[string]dynamoDBValue `json:"Items"`
+			LastEvaluatedKey map[string]dynamoDBValue   `json:"LastEvaluatedKey"`
+		}
+		if err := r.do(ctx, "Scan", request, &page); err != nil {
+			logger.Printf("Error scanning processed items: %v", err)
+			return nil, fmt.Errorf("scanning processed items: %w", err)
+		}
+		for _, item := range page.Items {
+			entry := entryFromDynamoDB(item)
+			index[entry.URL] = entry
+		}
+		if len(page.LastEvaluatedKey) == 0 {
+			return index, nil
+		}
+		startKey = page.LastEvaluatedKey
+	}
+}
+
+// IsProcessed checks if an article is already processed using the startup index
+func (r *dynamoDBProcessedRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	return exists
+}
+
+// MarkAsProcessed creates or replaces the article's item
+func (r *dynamoDBProcessedRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	entry := &IndexEntry{
+		Title:         article.Title,
+		URL:           r.GenerateKey(article), // Normalized URL
+		Source:        article.Source,
+		PubDate:       article.ParsedDate,
+		ProcessedDate: Now(ctx),
+		PromptVariant: article.PromptVariant,
+		Provenance:    article.Provenance,
+	}
+	request := map[string]any{"TableName": r.table, "Item": entry.dynamoDBItem()}
+	if err := r.do(ctx, "PutItem", request, nil); err != nil {
+		return fmt.Errorf("writing processed item: %w", err)
+	}
+	return nil
+}
+
+// MarkManyAsProcessed creates items, keeping existing ones as they are
+func (r *dynamoDBProcessedRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	now := Now(ctx)
+	entries := make([]*IndexEntry, 0, len(articles))
+	for _, article := range articles {
+		entries = append(entries, &IndexEntry{
+			Title:         article.Title,
+			URL:           article.Link,
+			Source:        article.Source,
+			PubDate:       article.ParsedDate,
+			ProcessedDate: now,
+		})
+	}
+	return r.ImportEntries(ctx, entries)
+}
+
+// ExportEntries lists the whole index (LoadIndex already returns every entry)
+func (r *dynamoDBProcessedRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntries(index), nil
+}
+
+// ImportEntries creates an item per entry, keeping existing ones as they are. BatchWriteItem has
+// no conditions, so each entry is a conditional PutItem.
+func (r *dynamoDBProcessedRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	seen := make(map[string]bool)
+	added := 0
+	for _, entry := range entries {
+		key := r.GenerateKey(Item{Link: entry.URL})
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		imported := *entry
+		imported.URL = key // Normalized URL
+
+		request := map[string]any{
+			"TableName":                r.table,
+			"Item":                     imported.dynamoDBItem(),
+			"ConditionExpression":      "attribute_not_exists(#url)",
+			"ExpressionAttributeNames": map[string]string{"#url": "url"},
+		}
+		err := r.do(ctx, "PutItem", request, nil)
+		var apiErr *dynamoDBError
+		switch {
+		case errors.As(err, &apiErr) && apiErr.Type == dynamoDBConditionFailed:
+			// Already marked
+		case err != nil:
+			return added, fmt.Errorf("writing processed item: %w", err)
+		default:
+			added++
+		}
+	}
+	return added, nil
+}
+
+// UnmarkProcessed deletes the article's item; reports whether it was present
+func (r *dynamoDBProcessedRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	request := map[string]any{
+		"TableName":    r.table,
+		"Key":          map[string]dynamoDBValue{"url": dynamoDBString(r.GenerateKey(article))},
+		"ReturnValues": "ALL_OLD",
+	}
+	var result struct {
+		Attributes map[string]dynamoDBValue `json:"Attributes"`
+	}
+	if err := r.do(ctx, "DeleteItem", request, &result); err != nil {
+		return false, fmt.Errorf("deleting processed item: %w", err)
+	}
+	return len(result.Attributes) > 0, nil
+}
+
+// Prune deletes the items processed before cutoff in batches
+func (r *dynamoDBProcessedRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var deletes []map[string]any
+	for key, entry := range index {
+		if entry.ProcessedDate.Before(cutoff) {
+			deletes = append(deletes, map[string]any{
+				"DeleteRequest": map[string]any{"Key": map[string]dynamoDBValue{"url": dynamoDBString(key)}},
+			})
+		}
+	}
+
+	removed := 0
+	for start := 0; start < len(deletes); start += dynamoDBBatchSize {
+		batch := deletes[start:min(start+dynamoDBBatchSize, len(deletes))]
+		if err := r.batchWrite(ctx, batch); err != nil {
+			return removed, fmt.Errorf("deleting processed items: %w", err)
+		}
+		removed += len(batch)
+	}
+	return removed, nil
+}
+
+// batchWrite sends a BatchWriteItem, resubmitting the items DynamoDB left unprocessed (throttling)
+func (r *dynamoDBProcessedRepository) batchWrite(ctx context.Context, requests []map[string]any) error {
+	pending := map[string]any{r.table: requests}
+	for attempt := 0; ; attempt++ {
+		var result struct {
+			UnprocessedItems map[string][]json.RawMessage `json:"UnprocessedItems"`
+		}
+		if err := r.do(ctx, "BatchWriteItem", map[string]any{"RequestItems": pending}, &result); err != nil {
+			return err
+		}
+		unprocessed := result.UnprocessedItems[r.table]
+		if len(unprocessed) == 0 {
+			return nil
+		}
+		if attempt == dynamoDBBatchRetries {
+			return fmt.Errorf("%d items left unprocessed", len(unprocessed))
+		}
+		pending = map[string]any{r.table: unprocessed}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(100<<attempt) * time.Millisecond):
+		}
+	}
+}
+
+// GenerateKey generates a key for an article
+func (r *dynamoDBProcessedRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// Flush is a no-op: each mark is written immediately
+func (r *dynamoDBProcessedRepository) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (r *dynamoDBProcessedRepository) Close() error {
+	return nil
+}
+
+// dynamoDBError is an error response of the DynamoDB API
+type dynamoDBError struct {
+	Type    string // e.g. ConditionalCheckFailedException
+	Message string
+}
+
+func (e *dynamoDBError) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+// do calls a DynamoDB operation, decoding the response into out when it is not nil
+func (r *dynamoDBProcessedRepository) do(ctx context.Context, operation string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	credentials, err := r.credentials.get(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", r.endpoint+"/", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "DynamoDB_20120810."+operation)
+	if credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+	payloadHash := sha256.Sum256(data)
+	signAWSRequest(req, hex.EncodeToString(payloadHash[:]), credentials.accessKey, credentials.secretKey, r.region, "dynamodb", r.now())
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return httperr.Transport(fmt.Errorf("calling DynamoDB: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		if apiErr.Type != "" {
+			// "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException"
+			errType := apiErr.Type[strings.LastIndex(apiErr.Type, "#")+1:]
+			return httperr.FromResponse(resp, &dynamoDBError{Type: errType, Message: apiErr.Message})
+		}
+		return httperr.FromResponse(resp, fmt.Errorf("dynamodb %s failed with status %d: %s", operation, resp.StatusCode, string(respBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding DynamoDB response: %w", err)
+	}
+	return nil
+}
+
+// dynamoDBValue is a DynamoDB attribute value; only the types the index uses are mapped
+type dynamoDBValue struct {
+	S *string                  `json:"S,omitempty"`
+	M map[string]dynamoDBValue `json:"M,omitempty"`
+}
+
+func dynamoDBString(s string) dynamoDBValue {
+	return dynamoDBValue{S: &s}
+}
+
+func (v dynamoDBValue) string() string {
+	if v.S == nil {
+		return ""
+	}
+	return *v.S
+}
+
+func (v dynamoDBValue) time() time.Time {
+	t, _ := parseIndexTime(v.string())
+	return t
+}
+
+// dynamoDBItem encodes an entry as an item with attributes named like its JSON in the index file
+func (e *IndexEntry) dynamoDBItem() map[string]dynamoDBValue {
+	item := map[string]dynamoDBValue{
+		"url":            dynamoDBString(e.URL),
+		"title":          dynamoDBString(e.Title),
+		"source":         dynamoDBString(e.Source),
+		"pub_date":       dynamoDBString(formatIndexTime(e.PubDate)),
+		"processed_date": dynamoDBString(formatIndexTime(e.ProcessedDate)),
+	}
+	if e.PromptVariant != "" {
+		item["prompt_variant"] = dynamoDBString(e.PromptVariant)
+	}
+	if e.Provenance != nil {
+		item["provenance"] = dynamoDBValue{M: map[string]dynamoDBValue{
+			"provider":   dynamoDBString(e.Provenance.Provider),
+			"model":      dynamoDBString(e.Provenance.Model),
+			"prompt":     dynamoDBString(e.Provenance.Prompt),
+			"extraction": dynamoDBString(e.Provenance.Extraction),
+		}}
+	}
+	return item
+}
+
+func entryFromDynamoDB(item map[string]dynamoDBValue) *IndexEntry {
+	entry := &IndexEntry{
+		Title:         item["title"].string(),
+		URL:           item["url"].string(),
+		Source:        item["source"].string(),
+		PubDate:       item["pub_date"].time(),
+		ProcessedDate: item["processed_date"].time(),
+		PromptVariant: item["prompt_variant"].string(),
+	}
+	if provenance := item["provenance"].M; provenance != nil {
+		entry.Provenance = &Provenance{
+			Provider:   provenance["provider"].string(),
+			Model:      provenance["model"].string(),
+			Prompt:     provenance["prompt"].string(),
+			Extraction: provenance["extraction"].string(),
+		}
+	}
+	return entry
+}
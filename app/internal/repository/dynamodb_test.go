@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDynamoDB serves the operations the processed repository uses on one table, one item per
+// Scan page and with the first BatchWriteItem item left unprocessed once, to exercise paging and retries
+type fakeDynamoDB struct {
+	mu        sync.Mutex
+	items     map[string]map[string]dynamoDBValue // Keyed by url
+	throttled bool
+	auth      []string
+}
+
+func (f *fakeDynamoDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	var body struct {
+		Item                map[string]dynamoDBValue `json:"Item"`
+		Key                 map[string]dynamoDBValue `json:"Key"`
+		ConditionExpression string                   `json:"ConditionExpression"`
+		ExclusiveStartKey   map[string]dynamoDBValue `json:"ExclusiveStartKey"`
+		RequestItems        map[string][]struct {
+			DeleteRequest struct {
+				Key map[string]dynamoDBValue `json:"Key"`
+			} `json:"DeleteRequest"`
+		} `json:"RequestItems"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810.") {
+	case "PutItem":
+		url := body.Item["url"].string()
+		if _, exists := f.items[url]; exists && body.ConditionExpression != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"__type":"com.amazonaws.dynamodb.v20120810#%s","message":"The conditional request failed"}`, dynamoDBConditionFailed)
+			return
+		}
+		f.items[url] = body.Item
+		w.Write([]byte("{}"))
+	case "DeleteItem":
+		url := body.Key["url"].string()
+		result := map[string]any{}
+		if item, exists := f.items[url]; exists {
+			result["Attributes"] = item
+		}
+		delete(f.items, url)
+		json.NewEncoder(w).Encode(result)
+	case "Scan":
+		var urls []string
+		for url := range f.items {
+			if url > body.ExclusiveStartKey["url"].string() {
+				urls = append(urls, url)
+			}
+		}
+		slices.Sort(urls)
+		page := map[string]any{"Items": []any{}}
+		if len(urls) > 0 {
+			page["Items"] = []any{f.items[urls[0]]}
+		}
+		if len(urls) > 1 {
+			page["LastEvaluatedKey"] = map[string]dynamoDBValue{"url": dynamoDBString(urls[0])}
+		}
+		json.NewEncoder(w).Encode(page)
+	case "BatchWriteItem":
+		var unprocessed []any
+		for _, request := range body.RequestItems[defaultDynamoDBTable] {
+			if !f.throttled {
+				f.throttled = true
+				unprocessed = append(unprocessed, request)
+				continue
+			}
+			delete(f.items, request.DeleteRequest.Key["url"].string())
+		}
+		json.NewEncoder(w).Encode(map[string]any{"UnprocessedItems": map[string]any{defaultDynamoDBTable: unprocessed}})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazon.coral.service#UnknownOperationException"}`))
+	}
+}
+
+func newTestDynamoDBRepository(t *testing.T) (*dynamoDBProcessedRepository, *fakeDynamoDB) {
+	t.Helper()
+	fake := &fakeDynamoDB{items: make(map[string]map[string]dynamoDBValue)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("DYNAMODB_ENDPOINT", server.URL)
+	t.Setenv("DYNAMODB_REGION", "ap-northeast-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "local")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "local-secret")
+	repo, err := newDynamoDBProcessedRepository()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return repo, fake
+}
+
+func TestDynamoDBProcessedRepository(t *testing.T) {
+	repo, fake := newTestDynamoDBRepository(t)
+	ctx := context.Background()
+
+	article := Item{
+		Title:      "Go 1.23",
+		Link:       "https://www.example.com/go/?utm_source=feed",
+		Source:     "hatena",
+		ParsedDate: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC),
+		Provenance: &Provenance{Provider: "gemini", Model: "gemini-2.5-flash", Prompt: "rss:default@v1", Extraction: "html"},
+	}
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+
+	added, err := repo.MarkManyAsProcessed(ctx, []Item{
+		{Title: "Go 1.23", Link: "https://example.com/go"}, // Already marked
+		{Title: "Rust 1.80", Link: "https://example.com/rust"},
+		{Title: "Rust 1.80", Link: "https://example.com/rust?ref=dup"},
+	})
+	if err != nil {
+		t.Fatalf("MarkManyAsProcessed failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d", added)
+	}
+
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 2 {
+		t.Fatalf("Expected 2 entries across pages, got %d", len(index))
+	}
+	key := repo.GenerateKey(article)
+	entry := index[key]
+	if entry == nil || entry.Title != "Go 1.23" || entry.Source != "hatena" || !entry.PubDate.Equal(article.ParsedDate) {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	if entry.Provenance == nil || entry.Provenance.Model != "gemini-2.5-flash" {
+		t.Errorf("Expected the provenance to round-trip, got %+v", entry.Provenance)
+	}
+
+	removed, err := repo.UnmarkProcessed(ctx, article)
+	if err != nil || !removed {
+		t.Fatalf("Expected the article to be unmarked, got %t, %v", removed, err)
+	}
+	removed, err = repo.UnmarkProcessed(ctx, article)
+	if err != nil || removed {
+		t.Errorf("Expected a second unmark to report absence, got %t, %v", removed, err)
+	}
+
+	for _, auth := range fake.auth {
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=local/") || !strings.Contains(auth, "/ap-northeast-1/dynamodb/aws4_request") {
+			t.Errorf("Expected requests signed for DynamoDB, got %q", auth)
+		}
+	}
+}
+
+func TestDynamoDBProcessedRepository_Prune(t *testing.T) {
+	repo, fake := newTestDynamoDBRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := repo.ImportEntries(ctx, []*IndexEntry{
+		{URL: "https://example.com/old", ProcessedDate: now.AddDate(0, 0, -120)},
+		{URL: "https://example.com/older", ProcessedDate: now.AddDate(0, 0, -200)},
+		{URL: "https://example.com/recent", ProcessedDate: now.AddDate(0, 0, -10)},
+	}); err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+
+	// The throttled item is resubmitted
+	removed, err := repo.Prune(ctx, now.AddDate(0, 0, -90))
+	if err != nil || removed != 2 {
+		t.Fatalf("Expected 2 pruned entries, got %d (%v)", removed, err)
+	}
+	if len(fake.items) != 1 || fake.items["https://example.com/recent"] == nil {
+		t.Errorf("Expected only the recent item to remain, got %v", fake.items)
+	}
+}
+
+func TestNewDynamoDBProcessedRepository_RequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	if _, err := newDynamoDBProcessedRepository(); err == nil {
+		t.Error("Expected an error without credentials")
+	}
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// EmailConfig holds SMTP settings for the email digest notifier
+type EmailConfig struct {
+	Host     string
+	Port     string
+	Username string // Empty disables SMTP AUTH
+	Password string
+	From     string
+	To       []string
+}
+
+type emailRepository struct {
+	config   EmailConfig
+	label    string // Subject label: feed name, or empty for a combined digest
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+	mu      sync.Mutex
+	pending []Notification
+	// outbox records the pending notifications so a crash before the digest does not drop them (nil: disabled)
+	outbox *WriteAheadLog
+}
+
+// outboxReplayAge is how old another instance's outbox entries must be to be treated as left
+// behind by a crash; longer than a feed run, during which a live instance still holds them
+const outboxReplayAge = 30 * time.Minute
+
+// NewEmailRepository creates a Notifier that batches a run's notifications into one HTML email.
+// label names the feed in the subject; leave it empty for a digest combining several feeds.
+func NewEmailRepository(config EmailConfig, label string) Notifier {
+	return &emailRepository{
+		config:   config,
+		label:    label,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// NewEmailRepositoryWithOutbox is NewEmailRepository recording queued notifications in outbox;
+// the next digest also carries the notifications crashed instances left there
+func NewEmailRepositoryWithOutbox(config EmailConfig, label string, outbox *WriteAheadLog) Notifier {
+	repo := NewEmailRepository(config, label).(*emailRepository)
+	repo.outbox = outbox
+	return repo
+}
+
+// Send queues a notification for the next digest; breaking news is emailed at once
+func (e *emailRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if notification.Breaking {
+		return e.deliver(ctx, notification.Title, []Notification{notification})
+	}
+
+	e.mu.Lock()
+	// Recorded under the lock, so a concurrent Flush takes the entry together with the notification
+	if err := e.outbox.Append(ctx, notification); err != nil {
+		// Still queued; only lost if this instance crashes before the digest
+		logger.Printf("Warning: Failed to record notification in email outbox: %v", err)
+	}
+	e.pending = append(e.pending, notification)
+	count := len(e.pending)
+	e.mu.Unlock()
+
+	logger.Printf("Email digest notification queued title=%s source=%s pending=%d", notification.Title, notification.Source, count)
+	return nil
+}
+
+// SendOnDemandSummary emails an on-demand summary immediately (not batched)
+func (e *emailRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	title := article.Title
+	if title == "" {
+		title = article.Link
+	}
+
+	notifications := []Notification{{
+		Title:        title,
+		Source:       SourceOnDemand,
+		URL:          article.Link,
+		Summary:      summary.Summary,
+		ContentChars: summary.ContentChars,
+	}}
+	return e.deliver(ctx, "オンデマンド要約: "+title, notifications)
+}
+
+// Flush sends the queued notifications as one digest email, after the ones replayed from the outbox
+func (e *emailRepository) Flush(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	e.mu.Lock()
+	queued := e.pending
+	e.pending = nil
+	logged := e.outbox.take()
+	e.mu.Unlock()
+
+	replayed, err := e.outbox.replay(ctx, outboxReplayAge)
+	if err != nil {
+		logger.Printf("Warning: Failed to replay email outbox: %v", err)
+	}
+	var notifications []Notification
+	for _, entry := range replayed {
+		var notification Notification
+		if err := json.Unmarshal(entry.data, &notification); err == nil {
+			notifications = append(notifications, notification)
+		}
+	}
+	notifications = append(notifications, queued...)
+
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("記事要約ダイジェスト (%d件)", len(notifications))
+	if e.label != "" {
+		subject = fmt.Sprintf("[%s] %s", e.label, subject)
+	}
+
+	if err := e.deliver(ctx, subject, notifications); err != nil {
+		// Keep the notifications for the next flush (replayed ones stay in the outbox)
+		e.mu.Lock()
+		e.pending = append(queued, e.pending...)
+		e.outbox.restore(logged)
+		e.mu.Unlock()
+		return err
+	}
+	if err := e.outbox.discard(ctx, logged); err != nil {
+		logger.Printf("Warning: Failed to commit email outbox: %v", err)
+	}
+	if err := e.outbox.discardEntries(ctx, replayed); err != nil {
+		logger.Printf("Warning: Failed to discard replayed email outbox: %v", err)
+	}
+	if len(replayed) > 0 {
+		logger.Printf("Replayed notifications from email outbox count=%d", len(replayed))
+	}
+	return nil
+}
+
+func (e *emailRepository) deliver(ctx context.Context, subject string, notifications []Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	body, err := renderDigestHTML(subject, notifications)
+	if err != nil {
+		logger.Printf("Error rendering email digest: %v", err)
+		return fmt.Errorf("rendering digest: %w", err)
+	}
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+
+	addr := e.config.Host + ":" + e.config.Port
+	if err := e.sendMail(addr, auth, e.config.From, e.config.To, buildEmailMessage(e.config.From, e.config.To, subject, body)); err != nil {
+		logger.Printf("Error sending email digest addr=%s count=%d: %v", addr, len(notifications), err)
+		return fmt.Errorf("sending email: %w", err)
+	}
+
+	logger.Printf("Email digest sent count=%d recipients=%d duration_ms=%d", len(notifications), len(e.config.To), time.Since(start).Milliseconds())
+	return nil
+}
+
+// buildEmailMessage assembles an RFC 5322 message with an HTML body
+func buildEmailMessage(from string, to []string, subject, htmlBody string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(htmlBody)
+	return b.Bytes()
+}
+
+var digestTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html><head><meta charset="UTF-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif; max-width: 720px;">
+<h1 style="font-size: 20px;">{{.Subject}}</h1>
+{{range .Sections}}<h2 style="font-size: 16px; border-bottom: 1px solid #ddd;">📰 {{.Source}}</h2>
+{{range .Notifications}}<div style="margin-bottom: 24px;">
+<h3 style="font-size: 15px;"><a href="{{.URL}}">{{.Title}}</a></h3>
+<p style="white-space: pre-wrap;">{{.Summary}}</p>
+<p style="color: #888; font-size: 12px;">📊 コンテンツ文字数: {{.ContentChars}}文字{{if .PromptVariant}} | 🧪 プロンプト: {{.PromptVariant}}{{end}}</p>
+</div>
+{{end}}{{end}}</body></html>
+`))
+
+type digestSection struct {
+	Source        string
+	Notifications []Notification
+}
+
+// renderDigestHTML renders notifications grouped by source (sources in alphabetical order)
+func renderDigestHTML(subject string, notifications []Notification) (string, error) {
+	bySource := make(map[string][]Notification)
+	for _, n := range notifications {
+		bySource[n.Source] = append(bySource[n.Source], n)
+	}
+
+	var sections []digestSection
+	for source, items := range bySource {
+		sections = append(sections, digestSection{Source: source, Notifications: items})
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].Source < sections[j].Source
+	})
+
+	var b bytes.Buffer
+	if err := digestTemplate.Execute(&b, struct {
+		Subject  string
+		Sections []digestSection
+	}{subject, sections}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
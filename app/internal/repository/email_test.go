@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailRepository_FlushDigest(t *testing.T) {
+	var sent []string
+	repo := NewEmailRepository(EmailConfig{
+		Host: "smtp.example.com",
+		Port: "587",
+		From: "bot@example.com",
+		To:   []string{"team@example.com"},
+	}, "hatena").(*emailRepository)
+	repo.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:587" {
+			t.Errorf("Unexpected addr: %s", addr)
+		}
+		if a != nil {
+			t.Error("Expected no SMTP auth without username")
+		}
+		sent = append(sent, string(msg))
+		return nil
+	}
+
+	ctx := context.Background()
+	// Nothing queued: no email
+	if err := repo.Flush(ctx); err != nil || len(sent) != 0 {
+		t.Fatalf("Expected empty flush to send nothing, err=%v sent=%d", err, len(sent))
+	}
+
+	repo.Send(ctx, Notification{Title: "Article <1>", Source: "hatena", URL: "https://example.com/1", Summary: "summary & more"})
+	repo.Send(ctx, Notification{Title: "Article 2", Source: "hatena", URL: "https://example.com/2", Summary: "second"})
+
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Expected one digest email, got %d", len(sent))
+	}
+
+	msg := sent[0]
+	if !strings.Contains(msg, "Content-Type: text/html; charset=UTF-8") {
+		t.Error("Expected HTML content type")
+	}
+	if !strings.Contains(msg, "Article &lt;1&gt;") || !strings.Contains(msg, "summary &amp; more") {
+		t.Error("Expected HTML-escaped title and summary")
+	}
+	if !strings.Contains(msg, `href="https://example.com/2"`) {
+		t.Error("Expected link to second article")
+	}
+
+	// Queue is cleared after a successful flush
+	repo.Flush(ctx)
+	if len(sent) != 1 {
+		t.Errorf("Expected no second email, got %d", len(sent))
+	}
+}
+
+func TestEmailRepository_FlushFailureKeepsQueue(t *testing.T) {
+	repo := NewEmailRepository(EmailConfig{Host: "smtp.example.com", Port: "587", From: "a@example.com", To: []string{"b@example.com"}}, "").(*emailRepository)
+	repo.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		return errors.New("connection refused")
+	}
+
+	ctx := context.Background()
+	repo.Send(ctx, Notification{Title: "Article", Source: "reddit"})
+	if err := repo.Flush(ctx); err == nil {
+		t.Fatal("Expected error from failed send")
+	}
+	if len(repo.pending) != 1 {
+		t.Errorf("Expected notification to stay queued, got %d", len(repo.pending))
+	}
+}
+
+func TestEmailRepository_OutboxReplaysCrashedDigest(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config := EmailConfig{Host: "smtp.example.com", Port: "587", From: "a@example.com", To: []string{"b@example.com"}}
+	ctx := context.Background()
+
+	var sent []string
+	newRepo := func() *emailRepository {
+		repo := NewEmailRepositoryWithOutbox(config, "hatena", NewWriteAheadLog(store, "email-hatena")).(*emailRepository)
+		repo.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+			sent = append(sent, string(msg))
+			return nil
+		}
+		return repo
+	}
+
+	// An instance queues a notification and crashes before its digest
+	newRepo().Send(ctx, Notification{Title: "Queued before the crash", Source: "hatena"})
+
+	// While recent, the entry may belong to a live instance and is left alone
+	next := newRepo()
+	next.Send(ctx, Notification{Title: "Fresh", Source: "hatena"})
+	if err := next.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 1 || strings.Contains(sent[0], "Queued before the crash") {
+		t.Fatalf("Expected a digest without the recent entry, got %v", sent)
+	}
+
+	// Once older than outboxReplayAge, it joins the next digest and leaves the outbox
+	names, _ := store.List(ctx, walPrefix)
+	if len(names) != 1 {
+		t.Fatalf("Expected only the crashed instance's entry to remain, got %v", names)
+	}
+	data, _ := store.Read(ctx, names[0])
+	store.Delete(ctx, names[0])
+	aged := fmt.Sprintf("%semail-hatena/%020d-crashed-000001.json", walPrefix, time.Now().Add(-time.Hour).UnixNano())
+	if err := store.Create(ctx, aged, data, "application/json"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	later := newRepo()
+	later.Send(ctx, Notification{Title: "Later", Source: "hatena"})
+	if err := later.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 2 || !strings.Contains(sent[1], "Queued before the crash") || !strings.Contains(sent[1], "Later") {
+		t.Errorf("Expected the replayed notification in the next digest, got %v", sent[1:])
+	}
+	if names, _ := store.List(ctx, walPrefix); len(names) != 0 {
+		t.Errorf("Expected an empty outbox, got %v", names)
+	}
+}
+
+func TestRenderDigestHTML_GroupsBySource(t *testing.T) {
+	body, err := renderDigestHTML("digest", []Notification{
+		{Title: "R", Source: "reddit"},
+		{Title: "H", Source: "hatena"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Index(body, "📰 hatena") > strings.Index(body, "📰 reddit") {
+		t.Error("Expected sections ordered by source")
+	}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// DefaultPromptVariant is the production RSS prompt
+const DefaultPromptVariant = "default"
+
+// rssPromptVariants holds the RSS prompt templates that can be compared in an experiment.
+// Each template receives the extracted text via a single %s.
+var rssPromptVariants = map[string]string{
+	DefaultPromptVariant: `以下のテキストを、Slackチャンネルでチームメンバーが素早く理解できるよう、1000文字以内で簡潔に要約してください。
+
+**重要な制約:**
+- 推測や創作は一切せず、実際に記載されている内容のみを要約してください
+- 記載されていない情報は追加しないでください
+
+チーム共有を前提とした読みやすい形式で、以下の構造で出力してください：
+- 📝 **要約:** 実際の内容を3-4行で簡潔に
+- 🎯 **対象者:** 記載されている課題や対象を基に
+- 💡 **解決効果:** 明記されている効果や解決策のみ
+
+テキスト内容:
+%s`,
+
+	"concise": `以下のテキストを、Slackでチームメンバーが10秒で把握できるよう、500文字以内で要約してください。
+
+**重要な制約:**
+- 推測や創作は一切せず、実際に記載されている内容のみを要約してください
+- 記載されていない情報は追加しないでください
+
+以下の構造で出力してください：
+- 📝 **一言で:** 記事の主張を1行で
+- 🔑 **ポイント:** 重要な事実を箇条書きで3つまで
+
+テキスト内容:
+%s`,
+
+	"takeaways": `以下のテキストを読み、エンジニアが実務に持ち帰れる内容を中心に1000文字以内で要約してください。
+
+**重要な制約:**
+- 推測や創作は一切せず、実際に記載されている内容のみを要約してください
+- 記載されていない情報は追加しないでください
+
+以下の構造で出力してください：
+- 📝 **要約:** 実際の内容を2-3行で
+- 🛠️ **持ち帰れること:** 記載されている手法・ツール・教訓を箇条書きで
+- ⚠️ **注意点:** 記載されている制約や前提条件のみ
+
+テキスト内容:
+%s`,
+}
+
+// PromptExperiment assigns one of N RSS prompt variants to each summarization.
+// A single variant means a fixed (per-feed) assignment; several variants are assigned uniformly at random.
+type PromptExperiment struct {
+	variants []string
+}
+
+// NewPromptExperiment creates an experiment over the given variant names
+func NewPromptExperiment(variants []string) (*PromptExperiment, error) {
+	var names []string
+	for _, v := range variants {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, ok := rssPromptVariants[v]; !ok {
+			return nil, fmt.Errorf("unknown prompt variant: %s", v)
+		}
+		names = append(names, v)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("prompt experiment requires at least one variant")
+	}
+	return &PromptExperiment{variants: names}, nil
+}
+
+// Assign picks the variant for one summarization
+func (e *PromptExperiment) Assign() string {
+	if len(e.variants) == 1 {
+		return e.variants[0]
+	}
+	return e.variants[rand.IntN(len(e.variants))]
+}
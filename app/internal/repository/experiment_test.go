@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewPromptExperiment(t *testing.T) {
+	if _, err := NewPromptExperiment([]string{"default", "unknown"}); err == nil {
+		t.Error("Expected error for unknown variant")
+	}
+
+	if _, err := NewPromptExperiment([]string{" ", ""}); err == nil {
+		t.Error("Expected error for empty variant list")
+	}
+
+	experiment, err := NewPromptExperiment([]string{"concise"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if variant := experiment.Assign(); variant != "concise" {
+			t.Errorf("Expected fixed variant 'concise', got '%s'", variant)
+		}
+	}
+}
+
+func TestPromptExperiment_AssignRandom(t *testing.T) {
+	experiment, err := NewPromptExperiment([]string{"default", "concise", "takeaways"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 300; i++ {
+		seen[experiment.Assign()] = true
+	}
+
+	if len(seen) != 3 {
+		t.Errorf("Expected all 3 variants to be assigned, got %v", seen)
+	}
+}
+
+func TestGeminiRepository_BuildRSSPrompt_Variant(t *testing.T) {
+	repo := &geminiRepository{}
+
+	defaultPrompt := repo.buildRSSPrompt("本文", "", SummaryLanguageJapanese)
+	if !strings.Contains(defaultPrompt, "🎯 **対象者:**") || !strings.HasSuffix(defaultPrompt, "本文") {
+		t.Errorf("Expected default prompt, got %s", defaultPrompt)
+	}
+
+	concisePrompt := repo.buildRSSPrompt("本文", "concise", SummaryLanguageJapanese)
+	if !strings.Contains(concisePrompt, "🔑 **ポイント:**") {
+		t.Errorf("Expected concise prompt, got %s", concisePrompt)
+	}
+}
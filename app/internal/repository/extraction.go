@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ExtractionRule narrows extraction for one domain (and its subdomains) to the article body
+type ExtractionRule struct {
+	Domain   string   `json:"domain"`
+	Selector string   `json:"selector"` // Article body; empty keeps the whole page
+	Strip    []string `json:"strip"`    // Elements removed before extraction (ads, share bars, ...)
+}
+
+// ParseExtractionRules parses EXTRACTION_RULES, a JSON array of rules such as
+// [{"domain":"example.com","selector":"article .post-body","strip":[".ad","aside"]}].
+// Selectors support tag, #id and .class compounds joined by descendant spaces.
+func ParseExtractionRules(raw string) ([]ExtractionRule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var rules []ExtractionRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("parsing extraction rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Domain == "" {
+			return nil, fmt.Errorf("extraction rule %d: domain is required", i)
+		}
+		rules[i].Domain = strings.ToLower(strings.TrimPrefix(rule.Domain, "www."))
+		for _, selector := range append([]string{rule.Selector}, rule.Strip...) {
+			if _, err := parseSelector(selector); err != nil {
+				return nil, fmt.Errorf("extraction rule %d (%s): %w", i, rule.Domain, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// ruleFor returns the most specific rule for pageURL's host, or nil
+func ruleFor(rules []ExtractionRule, pageURL string) *ExtractionRule {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(parsed.Hostname())
+
+	var best *ExtractionRule
+	for i := range rules {
+		rule := &rules[i]
+		if host != rule.Domain && !strings.HasSuffix(host, "."+rule.Domain) {
+			continue
+		}
+		if best == nil || len(rule.Domain) > len(best.Domain) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// applyExtractionRule returns the HTML of the rule's article body with stripped elements removed.
+// ok is false when the selector matches nothing, so the caller can fall back to the whole page.
+func applyExtractionRule(rule *ExtractionRule, page string) (string, bool) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", false
+	}
+
+	root := doc
+	if rule.Selector != "" {
+		selector, _ := parseSelector(rule.Selector)
+		root = findFirst(doc, selector)
+		if root == nil {
+			return "", false
+		}
+	}
+
+	for _, strip := range rule.Strip {
+		selector, _ := parseSelector(strip)
+		for _, n := range findAll(root, selector) {
+			if n.Parent != nil {
+				n.Parent.RemoveChild(n)
+			}
+		}
+	}
+
+	var b bytes.Buffer
+	if err := html.Render(&b, root); err != nil {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// compoundSelector matches one element, e.g. div#main.post.body
+type compoundSelector struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// selector is a chain of compounds joined by the descendant combinator
+type selector []compoundSelector
+
+func parseSelector(raw string) (selector, error) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	var sel selector
+	for _, field := range fields {
+		var c compoundSelector
+		kind, start := byte(0), 0
+		flush := func(end int) error {
+			name := field[start:end]
+			if name == "" {
+				if kind == 0 && end == 0 {
+					return nil // compound starts with . or #
+				}
+				return fmt.Errorf("invalid selector %q", raw)
+			}
+			switch kind {
+			case 0:
+				c.tag = strings.ToLower(name)
+			case '#':
+				c.id = name
+			case '.':
+				c.classes = append(c.classes, name)
+			}
+			return nil
+		}
+		for i := 0; i < len(field); i++ {
+			ch := field[i]
+			switch {
+			case ch == '.' || ch == '#':
+				if err := flush(i); err != nil {
+					return nil, err
+				}
+				kind, start = ch, i+1
+			case ch == '-' || ch == '_' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z':
+			default:
+				return nil, fmt.Errorf("unsupported selector %q (only tag, #id, .class and descendant)", raw)
+			}
+		}
+		if err := flush(len(field)); err != nil {
+			return nil, err
+		}
+		sel = append(sel, c)
+	}
+	return sel, nil
+}
+
+func (c compoundSelector) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && n.Data != c.tag {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	classes := strings.Fields(attr(n, "class"))
+	for _, want := range c.classes {
+		found := false
+		for _, class := range classes {
+			if class == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matches checks the last compound against n and the rest against its ancestors
+func (s selector) matches(n *html.Node) bool {
+	if len(s) == 0 || !s[len(s)-1].matches(n) {
+		return false
+	}
+	i := len(s) - 2
+	for p := n.Parent; p != nil && i >= 0; p = p.Parent {
+		if s[i].matches(p) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+func findFirst(n *html.Node, s selector) *html.Node {
+	if s.matches(n) {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findFirst(child, s); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAll returns the outermost matches under n (nested matches go with their ancestor)
+func findAll(n *html.Node, s selector) []*html.Node {
+	var found []*html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if s.matches(child) {
+			found = append(found, child)
+			continue
+		}
+		found = append(found, findAll(child, s)...)
+	}
+	return found
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
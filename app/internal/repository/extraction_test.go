@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseExtractionRules(t *testing.T) {
+	rules, err := ParseExtractionRules(`[{"domain":"www.Example.com","selector":"article .post-body","strip":[".ad","aside#share"]}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Domain != "example.com" {
+		t.Errorf("Expected normalized domain example.com, got %+v", rules)
+	}
+
+	for _, raw := range []string{
+		`not json`,
+		`[{"selector":"article"}]`,
+		`[{"domain":"example.com","selector":"div > p"}]`,
+		`[{"domain":"example.com","strip":["a[href]"]}]`,
+		`[{"domain":"example.com","selector":"div..post"}]`,
+	} {
+		if _, err := ParseExtractionRules(raw); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+
+	if rules, err := ParseExtractionRules(""); err != nil || rules != nil {
+		t.Errorf("Expected no rules for empty input, got %v %v", rules, err)
+	}
+}
+
+func TestRuleFor(t *testing.T) {
+	rules := []ExtractionRule{
+		{Domain: "example.com", Selector: "main"},
+		{Domain: "blog.example.com", Selector: "article"},
+	}
+
+	tests := []struct {
+		url      string
+		selector string
+	}{
+		{"https://example.com/a", "main"},
+		{"https://www.example.com/a", "main"},
+		{"https://blog.example.com/a", "article"},
+		{"https://notexample.com/a", ""},
+	}
+	for _, tt := range tests {
+		rule := ruleFor(rules, tt.url)
+		got := ""
+		if rule != nil {
+			got = rule.Selector
+		}
+		if got != tt.selector {
+			t.Errorf("ruleFor(%s) = %q, want %q", tt.url, got, tt.selector)
+		}
+	}
+}
+
+func TestApplyExtractionRule(t *testing.T) {
+	page := `<html><body>
+<nav>Menu Home About</nav>
+<article><div class="post-body main">
+<p>Article text.</p>
+<div class="ad">Buy now</div>
+<aside id="share">Share this</aside>
+<p>More text.</p>
+</div></article>
+<footer>Copyright</footer>
+</body></html>`
+
+	rules, err := ParseExtractionRules(`[{"domain":"example.com","selector":"article .post-body","strip":[".ad","aside#share"]}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	body, ok := applyExtractionRule(&rules[0], page)
+	if !ok {
+		t.Fatal("Expected selector to match")
+	}
+	text := (&geminiRepository{}).extractTextFromHTML(body)
+	if text != "Article text.\nMore text." {
+		t.Errorf("Unexpected extracted text: %q", text)
+	}
+
+	missing := ExtractionRule{Domain: "example.com", Selector: "section.content"}
+	if _, ok := applyExtractionRule(&missing, page); ok {
+		t.Error("Expected no match for missing selector")
+	}
+}
+
+func TestExtractTextFromPages_FallsBackWithoutMatch(t *testing.T) {
+	repo := &geminiRepository{extractionRules: []ExtractionRule{{Domain: "example.com", Selector: "section.content"}}}
+	text, method := repo.extractTextFromPages(context.Background(), "https://example.com/a", []string{"<p>Whole page</p>"})
+	if !strings.Contains(text, "Whole page") {
+		t.Errorf("Expected generic extraction fallback, got %q", text)
+	}
+	if method != ExtractionHTML {
+		t.Errorf("Expected %q extraction when the rule did not match, got %q", ExtractionHTML, method)
+	}
+}
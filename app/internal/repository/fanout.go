@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+type fanoutNotifier struct {
+	primary Notifier
+	mirrors []Notifier
+}
+
+// NewFanoutNotifier sends every notification to primary and then to each mirror (e.g. a Notion archive).
+// Only the primary's error is returned; mirror failures are logged so they never fail or re-queue an article.
+// Summaries of private documents (see Provenance.Private) go to primary only.
+func NewFanoutNotifier(primary Notifier, mirrors ...Notifier) Notifier {
+	if len(mirrors) == 0 {
+		return primary
+	}
+	return &fanoutNotifier{primary: primary, mirrors: mirrors}
+}
+
+func (f *fanoutNotifier) Send(ctx context.Context, notification Notification) error {
+	if err := f.primary.Send(ctx, notification); err != nil {
+		return err
+	}
+
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if notification.Provenance.Private() {
+		logger.Printf("Private document not mirrored url=%s", notification.URL)
+		return nil
+	}
+	for _, mirror := range f.mirrors {
+		if err := mirror.Send(ctx, notification); err != nil {
+			logger.Printf("Warning: Failed to mirror notification title=%s source=%s: %v", notification.Title, notification.Source, err)
+		}
+	}
+	return nil
+}
+
+func (f *fanoutNotifier) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	if err := f.primary.SendOnDemandSummary(ctx, article, summary, targetChannel); err != nil {
+		return err
+	}
+
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if summary.Provenance.Private() {
+		logger.Printf("Private document not mirrored url=%s", article.Link)
+		return nil
+	}
+	for _, mirror := range f.mirrors {
+		if err := mirror.SendOnDemandSummary(ctx, article, summary, targetChannel); err != nil {
+			logger.Printf("Warning: Failed to mirror on-demand summary url=%s: %v", article.Link, err)
+		}
+	}
+	return nil
+}
+
+// Flush flushes every notifier that batches
+func (f *fanoutNotifier) Flush(ctx context.Context) error {
+	var errs []error
+	for _, notifier := range append([]Notifier{f.primary}, f.mirrors...) {
+		if flusher, ok := notifier.(Flusher); ok {
+			if err := flusher.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
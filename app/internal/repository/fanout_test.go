@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type recordingNotifier struct {
+	sent    int
+	flushed int
+	err     error
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, notification Notification) error {
+	r.sent++
+	return r.err
+}
+
+func (r *recordingNotifier) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	r.sent++
+	return r.err
+}
+
+func (r *recordingNotifier) Flush(ctx context.Context) error {
+	r.flushed++
+	return nil
+}
+
+func TestFanoutNotifier(t *testing.T) {
+	primary := &recordingNotifier{}
+	mirror := &recordingNotifier{err: errors.New("mirror down")}
+	notifier := NewFanoutNotifier(primary, mirror)
+
+	if err := notifier.Send(context.Background(), Notification{Title: "t"}); err != nil {
+		t.Fatalf("Mirror failure should not be returned, got %v", err)
+	}
+	if primary.sent != 1 || mirror.sent != 1 {
+		t.Errorf("Expected both notifiers to be called, got primary=%d mirror=%d", primary.sent, mirror.sent)
+	}
+
+	notifier.(Flusher).Flush(context.Background())
+	if primary.flushed != 1 || mirror.flushed != 1 {
+		t.Errorf("Expected both notifiers to be flushed, got primary=%d mirror=%d", primary.flushed, mirror.flushed)
+	}
+
+	primary.err = errors.New("slack down")
+	if err := notifier.Send(context.Background(), Notification{Title: "t"}); err == nil {
+		t.Fatal("Expected primary failure to be returned")
+	}
+	if mirror.sent != 1 {
+		t.Errorf("Mirror should not be called when the primary fails, got %d", mirror.sent)
+	}
+}
+
+func TestFanoutNotifier_PrivateDocuments(t *testing.T) {
+	primary := &recordingNotifier{}
+	mirror := &recordingNotifier{}
+	notifier := NewFanoutNotifier(primary, mirror)
+	ctx := context.Background()
+
+	private := &Provenance{Provider: "gemini", Model: "m", Prompt: "ondemand@v1", Extraction: "confluence+map-reduce"}
+	notifier.Send(ctx, Notification{Title: "t", Provenance: private})
+	notifier.SendOnDemandSummary(ctx, Item{Link: "https://docs.google.com/document/d/1"}, SummarizeResponse{Provenance: &Provenance{Extraction: ExtractionGoogleDocs}}, "")
+	if primary.sent != 2 || mirror.sent != 0 {
+		t.Errorf("Expected private documents to reach the primary only, got primary=%d mirror=%d", primary.sent, mirror.sent)
+	}
+
+	notifier.SendOnDemandSummary(ctx, Item{Link: "https://github.com/example/tool"}, SummarizeResponse{Provenance: &Provenance{Extraction: "github-readme"}}, "")
+	if mirror.sent != 1 {
+		t.Errorf("Expected public pages to be mirrored, got %d", mirror.sent)
+	}
+}
+
+func TestFanoutNotifier_NoMirrors(t *testing.T) {
+	primary := &recordingNotifier{}
+	if NewFanoutNotifier(primary) != Notifier(primary) {
+		t.Error("Expected primary to be returned unchanged without mirrors")
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// feedOwnerPattern matches Slack user (U…, W…) and user group (S…) IDs
+var feedOwnerPattern = regexp.MustCompile(`^[UWS][A-Z0-9]{2,}$`)
+
+// ValidateFeedOwner checks that a feed owner (FEED_OWNER_<FEED>, a GENERIC_FEEDS owner) is a Slack
+// user or user group ID; "" leaves the feed unowned
+func ValidateFeedOwner(owner string) error {
+	if owner == "" || feedOwnerPattern.MatchString(owner) {
+		return nil
+	}
+	return fmt.Errorf("owner %q must be a Slack user ID (U…) or user group ID (S…)", owner)
+}
+
+// SlackMention formats a feed owner as a Slack mention: <@U123> for users, <!subteam^S123> for user groups
+func SlackMention(owner string) string {
+	if strings.HasPrefix(owner, "S") {
+		return "<!subteam^" + owner + ">"
+	}
+	return "<@" + owner + ">"
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "testing"
+
+func TestValidateFeedOwner(t *testing.T) {
+	for _, owner := range []string{"", "U024BE7LH", "W012A3CDE", "SAZ94GDB8"} {
+		if err := ValidateFeedOwner(owner); err != nil {
+			t.Errorf("Expected %q to be valid: %v", owner, err)
+		}
+	}
+	for _, owner := range []string{"@alice", "#team-feeds", "u024be7lh", "C024BE91L"} {
+		if err := ValidateFeedOwner(owner); err == nil {
+			t.Errorf("Expected %q to be rejected", owner)
+		}
+	}
+}
+
+func TestSlackMention(t *testing.T) {
+	if got := SlackMention("U024BE7LH"); got != "<@U024BE7LH>" {
+		t.Errorf("Unexpected user mention %q", got)
+	}
+	if got := SlackMention("SAZ94GDB8"); got != "<!subteam^SAZ94GDB8>" {
+		t.Errorf("Unexpected user group mention %q", got)
+	}
+}
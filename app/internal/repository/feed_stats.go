@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const (
+	defaultFeedStatsFileName = "feed_stats.json"
+	// feedStatsWeight is the weight of the latest run in the rolling averages
+	feedStatsWeight = 0.2
+)
+
+// FeedStats are a feed's rolling run statistics (exponentially weighted averages over runs)
+type FeedStats struct {
+	Feed            string    `json:"feed"`
+	Runs            int       `json:"runs"`
+	AvgItems        float64   `json:"avg_items"`         // Items in the fetched feed
+	AvgSummaryChars float64   `json:"avg_summary_chars"` // Characters per generated summary
+	FailureRate     float64   `json:"failure_rate"`      // Failed articles / attempted articles
+	LastRun         time.Time `json:"last_run"`
+	// Anomalies counts consecutive runs rejected as anomalous (reset by a normal run)
+	Anomalies   int    `json:"anomalies,omitempty"`
+	LastAnomaly string `json:"last_anomaly,omitempty"`
+	// FailedRuns counts consecutive runs in which every attempted article failed (reset by a success)
+	FailedRuns int `json:"failed_runs,omitempty"`
+}
+
+// FeedRun is the outcome of one feed run
+type FeedRun struct {
+	Items        int // Items in the fetched feed
+	Attempted    int // Articles summarized (or attempted)
+	Failed       int
+	Summaries    int
+	SummaryChars int // Total characters of the generated summaries
+}
+
+// Record folds a run into the rolling averages; the first run seeds them
+func (s *FeedStats) Record(run FeedRun, now time.Time) {
+	weight := feedStatsWeight
+	if s.Runs == 0 {
+		weight = 1
+	}
+	s.AvgItems += weight * (float64(run.Items) - s.AvgItems)
+	if run.Summaries > 0 {
+		avgChars := float64(run.SummaryChars) / float64(run.Summaries)
+		if s.AvgSummaryChars == 0 {
+			s.AvgSummaryChars = avgChars
+		} else {
+			s.AvgSummaryChars += weight * (avgChars - s.AvgSummaryChars)
+		}
+	}
+	if run.Attempted > 0 {
+		s.FailureRate += weight * (float64(run.Failed)/float64(run.Attempted) - s.FailureRate)
+	}
+	switch {
+	case run.Attempted > 0 && run.Failed == run.Attempted:
+		s.FailedRuns++
+	case run.Attempted > run.Failed:
+		s.FailedRuns = 0
+	}
+	s.Runs++
+	s.Anomalies = 0
+	s.LastRun = now
+}
+
+// FeedStatsRepository persists per-feed run statistics
+type FeedStatsRepository interface {
+	// Load returns the feed's statistics (empty stats for a feed without runs)
+	Load(ctx context.Context, feed string) (*FeedStats, error)
+	Save(ctx context.Context, stats *FeedStats) error
+	Close() error
+}
+
+type feedStatsRepository struct {
+	storage   Storage
+	statsFile string
+	mu        sync.Mutex // serializes read-modify-write across feeds sharing the object
+}
+
+// NewFeedStatsRepository creates a feed statistics repository stored next to the processed index
+func NewFeedStatsRepository() (FeedStatsRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	statsFile := defaultFeedStatsFileName
+	if env := os.Getenv("FEED_STATS_FILE"); env != "" {
+		statsFile = env
+	}
+
+	return &feedStatsRepository{
+		storage:   store,
+		statsFile: statsFile,
+	}, nil
+}
+
+func (g *feedStatsRepository) Load(ctx context.Context, feed string) (*FeedStats, error) {
+	all, err := g.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stats, ok := all[feed]; ok {
+		return stats, nil
+	}
+	return &FeedStats{Feed: feed}, nil
+}
+
+func (g *feedStatsRepository) Save(ctx context.Context, stats *FeedStats) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	all, err := g.load(ctx)
+	if err != nil {
+		return err
+	}
+	all[stats.Feed] = stats
+	return g.save(ctx, all)
+}
+
+// Close closes the storage
+func (g *feedStatsRepository) Close() error {
+	return g.storage.Close()
+}
+
+func (g *feedStatsRepository) load(ctx context.Context) (map[string]*FeedStats, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := g.storage.Read(ctx, g.statsFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return make(map[string]*FeedStats), nil
+		}
+		logger.Printf("Error reading feed stats data: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("reading feed stats data: %w", err)
+	}
+
+	var all map[string]*FeedStats
+	if err := json.Unmarshal(data, &all); err != nil {
+		logger.Printf("Error unmarshaling feed stats: %v", err)
+		return nil, fmt.Errorf("unmarshaling feed stats: %w", err)
+	}
+
+	return all, nil
+}
+
+func (g *feedStatsRepository) save(ctx context.Context, all map[string]*FeedStats) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	data, err := json.Marshal(all)
+	if err != nil {
+		logger.Printf("Error marshaling feed stats: %v", err)
+		return fmt.Errorf("marshaling feed stats: %w", err)
+	}
+
+	if err := g.storage.Write(ctx, g.statsFile, data, "application/json"); err != nil {
+		logger.Printf("Error writing feed stats data: %v\nStack:\n%s", err, debug.Stack())
+		return fmt.Errorf("writing feed stats data: %w", err)
+	}
+
+	return nil
+}
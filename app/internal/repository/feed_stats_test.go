@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFeedStats_Record(t *testing.T) {
+	stats := &FeedStats{Feed: "hatena"}
+	now := time.Now()
+
+	stats.Record(FeedRun{Items: 30, Attempted: 4, Failed: 0, Summaries: 4, SummaryChars: 1200}, now)
+	if stats.Runs != 1 || stats.AvgItems != 30 || stats.AvgSummaryChars != 300 || stats.FailureRate != 0 {
+		t.Fatalf("Expected the first run to seed the averages, got %+v", stats)
+	}
+
+	stats.Anomalies = 2
+	stats.Record(FeedRun{Items: 40, Attempted: 2, Failed: 1, Summaries: 1, SummaryChars: 200}, now)
+	if math.Abs(stats.AvgItems-32) > 1e-9 || math.Abs(stats.AvgSummaryChars-280) > 1e-9 || math.Abs(stats.FailureRate-0.1) > 1e-9 {
+		t.Errorf("Unexpected rolling averages %+v", stats)
+	}
+	if stats.Runs != 2 || stats.Anomalies != 0 {
+		t.Errorf("Expected runs=2 and anomalies reset, got %+v", stats)
+	}
+
+	// Runs without attempts leave the failure rate and summary length untouched
+	stats.Record(FeedRun{Items: 32}, now)
+	if math.Abs(stats.FailureRate-0.1) > 1e-9 || math.Abs(stats.AvgSummaryChars-280) > 1e-9 {
+		t.Errorf("Expected failure rate and summary length unchanged, got %+v", stats)
+	}
+}
+
+func TestFeedStats_RecordFailedRuns(t *testing.T) {
+	stats := &FeedStats{Feed: "hatena"}
+	now := time.Now()
+
+	stats.Record(FeedRun{Attempted: 3, Failed: 3}, now)
+	stats.Record(FeedRun{}, now) // Nothing new: the streak goes on
+	stats.Record(FeedRun{Attempted: 1, Failed: 1}, now)
+	if stats.FailedRuns != 2 {
+		t.Errorf("Expected 2 consecutive failed runs, got %d", stats.FailedRuns)
+	}
+
+	stats.Record(FeedRun{Attempted: 2, Failed: 1}, now)
+	if stats.FailedRuns != 0 {
+		t.Errorf("Expected a success to end the streak, got %d", stats.FailedRuns)
+	}
+}
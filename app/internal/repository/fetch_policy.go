@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// DefaultUserAgent is sent with article fetches when FETCH_USER_AGENT is not set
+const DefaultUserAgent = "Mozilla/5.0 (compatible; Article Summarizer Bot/1.0)"
+
+const (
+	// robotsCacheTTL is how long a host's robots.txt is reused before it is fetched again
+	robotsCacheTTL = 24 * time.Hour
+	// maxCrawlDelay caps the pause before a fetch, so that a huge Crawl-delay cannot stall a run
+	maxCrawlDelay = 30 * time.Second
+	// maxRobotsBytes is the size of robots.txt read (RFC 9309 requires at least 500 KiB)
+	maxRobotsBytes = 512 << 10
+)
+
+// ErrDisallowedByRobots is returned for pages the site's robots.txt does not allow the bot to fetch
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// FetchPolicy makes the article fetches of a polite crawler: a configurable User-Agent, pages
+// disallowed by robots.txt are skipped, and fetches from the same host are spaced by the crawl
+// delay (the configured one or the host's robots.txt Crawl-delay, whichever is longer)
+type FetchPolicy struct {
+	userAgent     string
+	respectRobots bool
+	crawlDelay    time.Duration
+	httpClient    *http.Client
+
+	mu        sync.Mutex
+	robots    map[string]*robotsEntry // By scheme and host
+	nextFetch map[string]time.Time    // Earliest time of the next fetch, by host
+}
+
+type robotsEntry struct {
+	rules     *robotsRules
+	fetchedAt time.Time
+}
+
+func NewFetchPolicy(userAgent string, respectRobots bool, crawlDelay time.Duration, httpClient *http.Client) *FetchPolicy {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return &FetchPolicy{
+		userAgent:     userAgent,
+		respectRobots: respectRobots,
+		crawlDelay:    crawlDelay,
+		httpClient:    httpClient,
+		robots:        make(map[string]*robotsEntry),
+		nextFetch:     make(map[string]time.Time),
+	}
+}
+
+// UserAgent is the User-Agent header of article fetches
+func (p *FetchPolicy) UserAgent() string {
+	if p == nil {
+		return DefaultUserAgent
+	}
+	return p.userAgent
+}
+
+// Wait checks pageURL against its host's robots.txt and blocks until the host's crawl delay has
+// passed since the previous fetch. It returns ErrDisallowedByRobots for disallowed pages and the
+// context's error when cancelled while waiting. A nil policy allows every fetch immediately.
+func (p *FetchPolicy) Wait(ctx context.Context, pageURL string) error {
+	if p == nil {
+		return nil
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Host == "" {
+		return nil // The fetch itself reports the invalid URL
+	}
+
+	delay := p.crawlDelay
+	if p.respectRobots {
+		rules := p.robotsFor(ctx, u)
+		if !rules.allowed(u.EscapedPath(), u.RawQuery) {
+			return fmt.Errorf("%w: %s", ErrDisallowedByRobots, pageURL)
+		}
+		delay = max(delay, rules.crawlDelay)
+	}
+	delay = min(delay, maxCrawlDelay)
+	if delay <= 0 {
+		return nil
+	}
+
+	// Reserve the host's next slot, so that concurrent fetches queue up behind each other
+	p.mu.Lock()
+	now := time.Now()
+	at := p.nextFetch[u.Host]
+	if at.Before(now) {
+		at = now
+	}
+	p.nextFetch[u.Host] = at.Add(delay)
+	p.mu.Unlock()
+
+	wait := at.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// robotsFor returns the rules of u's host for this bot, fetching robots.txt when not cached
+func (p *FetchPolicy) robotsFor(ctx context.Context, u *url.URL) *robotsRules {
+	key := u.Scheme + "://" + u.Host
+	p.mu.Lock()
+	entry, ok := p.robots[key]
+	p.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < robotsCacheTTL {
+		return entry.rules
+	}
+
+	rules := p.fetchRobots(ctx, key)
+	p.mu.Lock()
+	p.robots[key] = &robotsEntry{rules: rules, fetchedAt: time.Now()}
+	p.mu.Unlock()
+	return rules
+}
+
+// fetchRobots reads a host's robots.txt. A missing file (4xx) allows everything; so does an
+// unreachable one, since a flaky robots.txt should not silence a feed.
+func (p *FetchPolicy) fetchRobots(ctx context.Context, origin string) *robotsRules {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logger.Printf("Warning: Failed to fetch robots.txt, allowing all origin=%s: %v", origin, err)
+		return &robotsRules{}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= 500 {
+			logger.Printf("Warning: robots.txt unavailable, allowing all origin=%s status_code=%d", origin, resp.StatusCode)
+		}
+		return &robotsRules{}
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxRobotsBytes), robotsToken(p.userAgent))
+}
+
+// robotsToken is the product token robots.txt groups are matched against, e.g. ArticleSummarizerBot
+// for "Mozilla/5.0 (compatible; Article Summarizer Bot/1.0)"
+func robotsToken(userAgent string) string {
+	product := userAgent
+	if _, after, ok := strings.Cut(userAgent, "compatible;"); ok {
+		product = after
+	}
+	product, _, _ = strings.Cut(product, "/")
+	product, _, _ = strings.Cut(product, ";")
+	product = strings.TrimSuffix(strings.TrimSpace(product), ")")
+	return strings.ReplaceAll(product, " ", "")
+}
+
+// robotsRules are the rules of the robots.txt group that applies to the bot
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots reads the group naming token (case-insensitively), or else the * group (RFC 9309)
+func parseRobots(r io.Reader, token string) *robotsRules {
+	token = strings.ToLower(token)
+	var own, wildcard robotsRules
+	var hasOwn bool
+
+	// Consecutive user-agent lines start a group that the following rules belong to
+	var current []*robotsRules
+	inAgents := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+				inAgents = true
+			}
+			switch agent := strings.ToLower(value); {
+			case agent == "*":
+				current = append(current, &wildcard)
+			case agent == token:
+				current = append(current, &own)
+				hasOwn = true
+			}
+			continue
+		}
+		inAgents = false
+		for _, group := range current {
+			switch key {
+			case "allow", "disallow":
+				if value != "" {
+					group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					group.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+	if hasOwn {
+		return &own
+	}
+	return &wildcard
+}
+
+// allowed applies the longest matching rule to the path (allow wins ties); no match allows
+func (r *robotsRules) allowed(path, query string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if query != "" {
+		path += "?" + query
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allow, longest := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > longest || n == longest && rule.allow {
+			allow, longest = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a robots.txt path pattern, where * matches any characters and a trailing $
+// anchors the end, against the start of path
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
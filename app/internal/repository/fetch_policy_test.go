@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const testRobots = `# Example robots.txt
+User-agent: *
+Disallow: /private/
+Crawl-delay: 5
+
+User-agent: GPTBot
+User-agent: ArticleSummarizerBot
+Disallow: /drafts/
+Disallow: /*.pdf$
+Allow: /drafts/public/
+Disallow: /search?
+Crawl-delay: 0.05
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestRobotsToken(t *testing.T) {
+	tests := map[string]string{
+		DefaultUserAgent: "ArticleSummarizerBot",
+		"TeamDigest/2.0 (+https://example.com/bot)":               "TeamDigest",
+		"Mozilla/5.0 (compatible; Foo Bot; +https://example.com)": "FooBot",
+	}
+	for userAgent, want := range tests {
+		if got := robotsToken(userAgent); got != want {
+			t.Errorf("robotsToken(%q) = %q, want %q", userAgent, got, want)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	own := parseRobots(strings.NewReader(testRobots), "ArticleSummarizerBot")
+	tests := []struct {
+		path, query string
+		want        bool
+	}{
+		{"/", "", true},
+		{"/private/page", "", true}, // Only the * group disallows it
+		{"/drafts/post", "", false},
+		{"/drafts/public/post", "", true}, // The longer allow wins
+		{"/papers/paper.pdf", "", false},
+		{"/papers/paper.pdf.html", "", true},
+		{"/search", "q=go", false},
+	}
+	for _, tt := range tests {
+		if got := own.allowed(tt.path, tt.query); got != tt.want {
+			t.Errorf("allowed(%q, %q) = %v, want %v", tt.path, tt.query, got, tt.want)
+		}
+	}
+	if own.crawlDelay != 50*time.Millisecond {
+		t.Errorf("Expected the group's crawl delay, got %v", own.crawlDelay)
+	}
+
+	other := parseRobots(strings.NewReader(testRobots), "OtherBot")
+	if other.allowed("/private/page", "") || !other.allowed("/drafts/post", "") || other.crawlDelay != 5*time.Second {
+		t.Errorf("Expected the * group for other bots, got %+v", other)
+	}
+}
+
+func TestFetchPolicy_Wait(t *testing.T) {
+	robotsFetches := 0
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		robotsFetches++
+		userAgent = r.UserAgent()
+		fmt.Fprint(w, testRobots)
+	}))
+	defer server.Close()
+
+	policy := NewFetchPolicy("", true, 0, server.Client())
+	ctx := context.Background()
+
+	if err := policy.Wait(ctx, server.URL+"/drafts/post"); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Errorf("Expected ErrDisallowedByRobots, got %v", err)
+	}
+
+	// Fetches from the same host are spaced by the robots.txt Crawl-delay
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := policy.Wait(ctx, server.URL+"/posts/"+fmt.Sprint(i)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected fetches spaced by the crawl delay, took %v", elapsed)
+	}
+	if robotsFetches != 1 || userAgent != DefaultUserAgent {
+		t.Errorf("Expected robots.txt fetched once with the bot's User-Agent, got %d fetches as %q", robotsFetches, userAgent)
+	}
+
+	// RESPECT_ROBOTS_TXT=false skips robots.txt entirely
+	if err := NewFetchPolicy("", false, 0, server.Client()).Wait(ctx, server.URL+"/drafts/post"); err != nil {
+		t.Errorf("Expected no robots.txt check, got %v", err)
+	}
+}
+
+func TestFetchPolicy_MissingRobotsAllowsAll(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if err := NewFetchPolicy("", true, 0, server.Client()).Wait(context.Background(), server.URL+"/drafts/post"); err != nil {
+		t.Errorf("Expected a missing robots.txt to allow all, got %v", err)
+	}
+}
+
+func TestGeminiRepository_FetchArticlePages_RobotsDisallowed(t *testing.T) {
+	pageFetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: TeamDigest\nDisallow: /members/\n")
+			return
+		}
+		pageFetches++
+		if r.UserAgent() != "TeamDigest/2.0" {
+			t.Errorf("Unexpected User-Agent %q", r.UserAgent())
+		}
+		fmt.Fprint(w, "<html><body><p>page</p></body></html>")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	repo := &geminiRepository{
+		maxPages:   1,
+		httpClient: client,
+		policy:     NewFetchPolicy("TeamDigest/2.0", true, 0, client),
+	}
+
+	_, err := repo.fetchArticlePages(context.Background(), server.URL+"/members/only")
+	if !errors.Is(err, ErrDisallowedByRobots) || !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent robots.txt error, got %v", err)
+	}
+	if _, err := repo.fetchArticlePages(context.Background(), server.URL+"/blog/post"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if pageFetches != 1 {
+		t.Errorf("Expected only the allowed page fetched, got %d fetches", pageFetches)
+	}
+}
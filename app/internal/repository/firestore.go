@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// Processed index backends (CACHE_TYPE)
+const (
+	CacheTypeStorage   = "storage"
+	CacheTypeFirestore = "firestore"
+	CacheTypeSQLite    = "sqlite"
+	CacheTypePostgres  = "postgres"
+	CacheTypeDynamoDB  = "dynamodb"
+)
+
+const (
+	defaultFirestoreDatabase   = "(default)"
+	defaultFirestoreCollection = "processed-articles"
+	// firestoreBatchSize is the most writes a batchWrite request accepts
+	firestoreBatchSize = 500
+	// firestorePageSize is the number of documents listed per request
+	firestorePageSize = 300
+)
+
+// gRPC status codes returned per write by batchWrite
+const (
+	firestoreStatusOK                 = 0
+	firestoreStatusAlreadyExists      = 6
+	firestoreStatusFailedPrecondition = 9
+)
+
+// firestoreProcessedRepository keeps one Firestore document per processed article, so marking an
+// article writes a single document instead of rewriting the whole index
+type firestoreProcessedRepository struct {
+	// baseURL is the Firestore REST API root (the emulator's with FIRESTORE_EMULATOR_HOST)
+	baseURL string
+	// documentsName is the database's documents resource, e.g. projects/p/databases/(default)/documents
+	documentsName string
+	collection    string
+	httpClient    *http.Client
+	// token returns an OAuth access token for Firestore (overridable for tests)
+	token func(ctx context.Context) (string, error)
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+// newFirestoreProcessedRepository reads FIRESTORE_PROJECT_ID (default GOOGLE_CLOUD_PROJECT), FIRESTORE_DATABASE
+// and FIRESTORE_COLLECTION; FIRESTORE_EMULATOR_HOST points it at the emulator without credentials
+func newFirestoreProcessedRepository() (*firestoreProcessedRepository, error) {
+	project := os.Getenv("FIRESTORE_PROJECT_ID")
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	if project == "" {
+		return nil, errors.New("FIRESTORE_PROJECT_ID is required when CACHE_TYPE=firestore")
+	}
+	database := defaultFirestoreDatabase
+	if env := os.Getenv("FIRESTORE_DATABASE"); env != "" {
+		database = env
+	}
+	collection := defaultFirestoreCollection
+	if env := os.Getenv("FIRESTORE_COLLECTION"); env != "" {
+		collection = env
+	}
+	baseURL := "https://firestore.googleapis.com/v1"
+	emulator := os.Getenv("FIRESTORE_EMULATOR_HOST")
+	if emulator != "" {
+		baseURL = "http://" + emulator + "/v1"
+	}
+
+	r := newFirestoreProcessedRepositoryAt(baseURL, project, database, collection)
+	if emulator != "" {
+		r.token = func(ctx context.Context) (string, error) { return "owner", nil }
+	}
+	return r, nil
+}
+
+func newFirestoreProcessedRepositoryAt(baseURL, project, database, collection string) *firestoreProcessedRepository {
+	documentsName := "projects/" + project + "/databases/" + database + "/documents"
+	r := &firestoreProcessedRepository{
+		baseURL:       baseURL,
+		documentsName: documentsName,
+		collection:    collection,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+	}
+	r.token = r.defaultToken
+	return r
+}
+
+// LoadIndex lists every document of the collection
+func (r *firestoreProcessedRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	index := make(map[string]*IndexEntry)
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {fmt.Sprint(firestorePageSize)}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Documents []struct {
+				Fields map[string]firestoreValue `json:"fields"`
+			} `json:"documents"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := r.do(ctx, "GET", r.baseURL+"/"+r.documentsName+"/"+r.collection+"?"+query.Encode(), nil, &page); err != nil {
+			logger.Printf("Error listing processed documents: %v", err)
+			return nil, fmt.Errorf("listing processed documents: %w", err)
+		}
+		for _, doc := range page.Documents {
+			entry := entryFromFirestore(doc.Fields)
+			index[entry.URL] = entry
+		}
+		if page.NextPageToken == "" {
+			return index, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// IsProcessed checks if an article is already processed using the startup index
+func (r *firestoreProcessedRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	return exists
+}
+
+// MarkAsProcessed creates or replaces the article's document
+func (r *firestoreProcessedRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	key := r.GenerateKey(article)
+	entry := &IndexEntry{
+		Title:         article.Title,
+		URL:           key, // Normalized URL
+		Source:        article.Source,
+		PubDate:       article.ParsedDate,
+		ProcessedDate: Now(ctx),
+		PromptVariant: article.PromptVariant,
+		Provenance:    article.Provenance,
+	}
+	body := map[string]any{"fields": entry.firestoreFields()}
+	if err := r.do(ctx, "PATCH", r.documentURL(key), body, nil); err != nil {
+		return fmt.Errorf("writing processed document: %w", err)
+	}
+	return nil
+}
+
+// MarkManyAsProcessed creates documents in batches, keeping existing ones as they are
+func (r *firestoreProcessedRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	now := Now(ctx)
+	entries := make([]*IndexEntry, 0, len(articles))
+	for _, article := range articles {
+		entries = append(entries, &IndexEntry{
+			Title:         article.Title,
+			URL:           article.Link,
+			Source:        article.Source,
+			PubDate:       article.ParsedDate,
+			ProcessedDate: now,
+		})
+	}
+	return r.ImportEntries(ctx, entries)
+}
+
+// ExportEntries lists the whole index (LoadIndex already returns every entry)
+func (r *firestoreProcessedRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntries(index), nil
+}
+
+// ImportEntries creates documents for the entries in batches, keeping existing ones as they are
+func (r *firestoreProcessedRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	seen := make(map[string]bool)
+	var writes []map[string]any
+	for _, entry := range entries {
+		key := r.GenerateKey(Item{Link: entry.URL})
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		imported := *entry
+		imported.URL = key // Normalized URL
+		writes = append(writes, map[string]any{
+			"update": map[string]any{
+				"name":   r.documentName(key),
+				"fields": imported.firestoreFields(),
+			},
+			// Fails (and is skipped) for articles already marked
+			"currentDocument": map[string]any{"exists": false},
+		})
+	}
+
+	added := 0
+	for start := 0; start < len(writes); start += firestoreBatchSize {
+		batch := writes[start:min(start+firestoreBatchSize, len(writes))]
+		var result struct {
+			Status []struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"status"`
+		}
+		if err := r.do(ctx, "POST", r.baseURL+"/"+r.documentsName+":batchWrite", map[string]any{"writes": batch}, &result); err != nil {
+			return added, fmt.Errorf("writing processed documents: %w", err)
+		}
+		for _, status := range result.Status {
+			switch status.Code {
+			case firestoreStatusOK:
+				added++
+			case firestoreStatusAlreadyExists, firestoreStatusFailedPrecondition:
+			default:
+				return added, fmt.Errorf("writing processed document: %s (code %d)", status.Message, status.Code)
+			}
+		}
+	}
+	return added, nil
+}
+
+// UnmarkProcessed deletes the article's document; reports whether it was present
+func (r *firestoreProcessedRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	documentURL := r.documentURL(r.GenerateKey(article))
+	err := r.do(ctx, "GET", documentURL, nil, nil)
+	var httpErr *httperr.Error
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading processed document: %w", err)
+	}
+	if err := r.do(ctx, "DELETE", documentURL, nil, nil); err != nil {
+		return false, fmt.Errorf("deleting processed document: %w", err)
+	}
+	return true, nil
+}
+
+// Prune deletes the documents processed before cutoff in batches
+func (r *firestoreProcessedRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var deletes []map[string]any
+	for key, entry := range index {
+		if entry.ProcessedDate.Before(cutoff) {
+			deletes = append(deletes, map[string]any{"delete": r.documentName(key)})
+		}
+	}
+
+	removed := 0
+	for start := 0; start < len(deletes); start += firestoreBatchSize {
+		batch := deletes[start:min(start+firestoreBatchSize, len(deletes))]
+		var result struct {
+			Status []struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"status"`
+		}
+		if err := r.do(ctx, "POST", r.baseURL+"/"+r.documentsName+":batchWrite", map[string]any{"writes": batch}, &result); err != nil {
+			return removed, fmt.Errorf("deleting processed documents: %w", err)
+		}
+		for _, status := range result.Status {
+			if status.Code != firestoreStatusOK {
+				return removed, fmt.Errorf("deleting processed document: %s (code %d)", status.Message, status.Code)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// GenerateKey generates a key for an article
+func (r *firestoreProcessedRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// Flush is a no-op: each mark is written immediately
+func (r *firestoreProcessedRepository) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (r *firestoreProcessedRepository) Close() error {
+	return nil
+}
+
+// documentName is the resource name of a processed key's document, named by the key's SHA-256
+// since normalized URLs contain slashes
+func (r *firestoreProcessedRepository) documentName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return r.documentsName + "/" + r.collection + "/" + hex.EncodeToString(sum[:])
+}
+
+func (r *firestoreProcessedRepository) documentURL(key string) string {
+	return r.baseURL + "/" + r.documentName(key)
+}
+
+// do sends a Firestore REST request, decoding the response into out when it is not nil
+func (r *firestoreProcessedRepository) do(ctx context.Context, method, requestURL string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	token, err := r.token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return httperr.Transport(fmt.Errorf("calling Firestore: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return httperr.FromResponse(resp, fmt.Errorf("firestore %s failed with status %d: %s", method, resp.StatusCode, string(respBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding Firestore response: %w", err)
+	}
+	return nil
+}
+
+// defaultToken uses application default credentials, loaded on first use
+func (r *firestoreProcessedRepository) defaultToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.source == nil {
+		source, err := google.DefaultTokenSource(context.Background(), "https://www.googleapis.com/auth/datastore")
+		if err != nil {
+			return "", fmt.Errorf("loading Firestore credentials: %w", err)
+		}
+		r.source = source
+	}
+	token, err := r.source.Token()
+	if err != nil {
+		return "", fmt.Errorf("fetching Firestore token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// firestoreValue is a Firestore REST value; only the types the index uses are mapped
+type firestoreValue struct {
+	StringValue    *string `json:"stringValue,omitempty"`
+	TimestampValue *string `json:"timestampValue,omitempty"`
+	MapValue       *struct {
+		Fields map[string]firestoreValue `json:"fields"`
+	} `json:"mapValue,omitempty"`
+}
+
+func firestoreString(s string) firestoreValue {
+	return firestoreValue{StringValue: &s}
+}
+
+func firestoreTimestamp(t time.Time) firestoreValue {
+	s := t.UTC().Format(time.RFC3339Nano)
+	return firestoreValue{TimestampValue: &s}
+}
+
+func (v firestoreValue) string() string {
+	if v.StringValue == nil {
+		return ""
+	}
+	return *v.StringValue
+}
+
+func (v firestoreValue) time() time.Time {
+	if v.TimestampValue == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339Nano, *v.TimestampValue)
+	return t
+}
+
+// firestoreFields encodes an entry as document fields named like its JSON in the index file
+func (e *IndexEntry) firestoreFields() map[string]firestoreValue {
+	fields := map[string]firestoreValue{
+		"title":          firestoreString(e.Title),
+		"url":            firestoreString(e.URL),
+		"source":         firestoreString(e.Source),
+		"pub_date":       firestoreTimestamp(e.PubDate),
+		"processed_date": firestoreTimestamp(e.ProcessedDate),
+	}
+	if e.PromptVariant != "" {
+		fields["prompt_variant"] = firestoreString(e.PromptVariant)
+	}
+	if e.Provenance != nil {
+		provenance := firestoreValue{MapValue: &struct {
+			Fields map[string]firestoreValue `json:"fields"`
+		}{Fields: map[string]firestoreValue{
+			"provider":   firestoreString(e.Provenance.Provider),
+			"model":      firestoreString(e.Provenance.Model),
+			"prompt":     firestoreString(e.Provenance.Prompt),
+			"extraction": firestoreString(e.Provenance.Extraction),
+		}}}
+		fields["provenance"] = provenance
+	}
+	return fields
+}
+
+func entryFromFirestore(fields map[string]firestoreValue) *IndexEntry {
+	entry := &IndexEntry{
+		Title:         fields["title"].string(),
+		URL:           fields["url"].string(),
+		Source:        fields["source"].string(),
+		PubDate:       fields["pub_date"].time(),
+		ProcessedDate: fields["processed_date"].time(),
+		PromptVariant: fields["prompt_variant"].string(),
+	}
+	if provenance := fields["provenance"].MapValue; provenance != nil {
+		entry.Provenance = &Provenance{
+			Provider:   provenance.Fields["provider"].string(),
+			Model:      provenance.Fields["model"].string(),
+			Prompt:     provenance.Fields["prompt"].string(),
+			Extraction: provenance.Fields["extraction"].string(),
+		}
+	}
+	return entry
+}
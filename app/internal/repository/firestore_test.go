@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeFirestore serves the document endpoints the processed repository uses
+type fakeFirestore struct {
+	mu   sync.Mutex
+	docs map[string]map[string]firestoreValue // Keyed by resource name
+}
+
+func (f *fakeFirestore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	switch {
+	case r.Method == "POST" && strings.HasSuffix(name, ":batchWrite"):
+		var body struct {
+			Writes []struct {
+				Update struct {
+					Name   string                    `json:"name"`
+					Fields map[string]firestoreValue `json:"fields"`
+				} `json:"update"`
+			} `json:"writes"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var statuses []map[string]any
+		for _, write := range body.Writes {
+			if _, exists := f.docs[write.Update.Name]; exists {
+				statuses = append(statuses, map[string]any{"code": firestoreStatusAlreadyExists})
+				continue
+			}
+			f.docs[write.Update.Name] = write.Update.Fields
+			statuses = append(statuses, map[string]any{})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"status": statuses})
+	case r.Method == "PATCH":
+		var body struct {
+			Fields map[string]firestoreValue `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.docs[name] = body.Fields
+		w.Write([]byte("{}"))
+	case r.Method == "DELETE":
+		delete(f.docs, name)
+		w.Write([]byte("{}"))
+	case r.Method == "GET" && f.docs[name] != nil:
+		json.NewEncoder(w).Encode(map[string]any{"name": name, "fields": f.docs[name]})
+	case r.Method == "GET" && strings.HasSuffix(name, "/processed-articles"):
+		// One document per page, to exercise paging
+		var names []string
+		for docName := range f.docs {
+			names = append(names, docName)
+		}
+		slices.Sort(names) // Stable across pages
+		offset := 0
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			offset = len(token)
+		}
+		page := map[string]any{}
+		if offset < len(names) {
+			page["documents"] = []map[string]any{{"name": names[offset], "fields": f.docs[names[offset]]}}
+		}
+		if offset+1 < len(names) {
+			page["nextPageToken"] = strings.Repeat("x", offset+1)
+		}
+		json.NewEncoder(w).Encode(page)
+	default:
+		http.Error(w, `{"error":{"status":"NOT_FOUND"}}`, http.StatusNotFound)
+	}
+}
+
+func TestFirestoreProcessedRepository(t *testing.T) {
+	fake := &fakeFirestore{docs: make(map[string]map[string]firestoreValue)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	repo := newFirestoreProcessedRepositoryAt(server.URL+"/v1", "p", defaultFirestoreDatabase, defaultFirestoreCollection)
+	repo.token = func(ctx context.Context) (string, error) { return "token", nil }
+	ctx := context.Background()
+
+	article := Item{
+		Title:      "Go 1.23",
+		Link:       "https://www.example.com/go/?utm_source=feed",
+		Source:     "hatena",
+		ParsedDate: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC),
+		Provenance: &Provenance{Provider: "gemini", Model: "gemini-2.5-flash", Prompt: "rss:default@v1", Extraction: "html"},
+	}
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+
+	added, err := repo.MarkManyAsProcessed(ctx, []Item{
+		{Title: "Go 1.23", Link: "https://example.com/go"}, // Already marked
+		{Title: "Rust 1.80", Link: "https://example.com/rust"},
+		{Title: "Rust 1.80", Link: "https://example.com/rust?ref=dup"},
+	})
+	if err != nil {
+		t.Fatalf("MarkManyAsProcessed failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d", added)
+	}
+
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 2 {
+		t.Fatalf("Expected 2 entries across pages, got %d", len(index))
+	}
+	key := repo.GenerateKey(article)
+	if !repo.IsProcessed(key, index) {
+		t.Fatalf("Expected %s to be processed", key)
+	}
+	entry := index[key]
+	if entry.Title != "Go 1.23" || entry.Source != "hatena" || !entry.PubDate.Equal(article.ParsedDate) {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if entry.Provenance == nil || entry.Provenance.Model != "gemini-2.5-flash" {
+		t.Errorf("Expected the provenance to round-trip, got %+v", entry.Provenance)
+	}
+
+	removed, err := repo.UnmarkProcessed(ctx, article)
+	if err != nil || !removed {
+		t.Fatalf("Expected the article to be unmarked, got %t, %v", removed, err)
+	}
+	removed, err = repo.UnmarkProcessed(ctx, article)
+	if err != nil || removed {
+		t.Errorf("Expected a second unmark to report absence, got %t, %v", removed, err)
+	}
+}
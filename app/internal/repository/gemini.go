@@ -4,24 +4,41 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
 )
 
+// ErrRenderFallbackDisabled is returned by SummarizeRendered when neither RENDER_CHROME_PATH nor
+// RENDER_FALLBACK_URL is set
+var ErrRenderFallbackDisabled = errors.New("render fallback disabled")
+
 // SummarizeResponse represents a summarization response
 type SummarizeResponse struct {
 	Summary      string    `json:"summary"`
 	ProcessedAt  time.Time `json:"processed_at"`
 	ContentChars int       `json:"content_chars"` // Original content character count
 	Title        string    `json:"title"`         // Article title extracted from HTML
+	// PromptVariant is the experiment variant used for the prompt (empty when no experiment is running)
+	PromptVariant string `json:"prompt_variant,omitempty"`
+	// Provenance records the model, prompt and extraction behind the summary (nil when no model was called)
+	Provenance *Provenance `json:"provenance,omitempty"`
+	// Glossary explains the summary's acronyms (only with GLOSSARY_CHANNELS)
+	Glossary []GlossaryEntry `json:"glossary,omitempty"`
+	// Difficulty is the article's audience level (only with DIFFICULTY_ENABLED; empty when unclassified)
+	Difficulty string `json:"difficulty,omitempty"`
 }
 
 type GeminiRepository interface {
@@ -33,6 +50,17 @@ type GeminiRepository interface {
 	// New unified methods for article processing service
 	SummarizeComments(ctx context.Context, text string) (*SummarizeResponse, error)
 	SummarizeOnDemand(ctx context.Context, url string) (*SummarizeResponse, error)
+
+	// SummarizeRendered is SummarizeURL over the page as rendered by the headless browser or the
+	// render fallback (for JavaScript-built pages); ErrRenderFallbackDisabled when none is configured
+	SummarizeRendered(ctx context.Context, url string) (*SummarizeResponse, error)
+
+	// RewriteHeadline turns a clickbait title into a neutral, descriptive headline based on the summary
+	RewriteHeadline(ctx context.Context, title, summary string) (string, error)
+
+	// TranslateSummary translates a summary into language (ja or en), keeping its structure; a summary
+	// already in that language is returned as-is
+	TranslateSummary(ctx context.Context, summary, language string) (string, error)
 }
 
 type geminiRepository struct {
@@ -40,16 +68,199 @@ type geminiRepository struct {
 	model      string
 	httpClient *http.Client
 	baseURL    string
+	maxPages   int
+	experiment *PromptExperiment
+
+	// maxPDFBytes and maxPDFPages bound the PDFs whose text is summarized (0 uses the defaults)
+	maxPDFBytes int64
+	maxPDFPages int
+
+	// mapReduceThreshold is the text length above which long articles are summarized chunk by chunk (0 disables)
+	mapReduceThreshold int
+
+	// extractionRules narrow extraction to the article body on known noisy domains
+	extractionRules []ExtractionRule
+
+	// renderFallbackURL is a prerendering service the query-escaped article URL is appended to (empty disables)
+	renderFallbackURL string
+
+	// policy checks robots.txt, spaces fetches per host and names the User-Agent of article fetches (nil allows all)
+	policy *FetchPolicy
+
+	// browser renders pages in a local headless Chrome, in preference to renderFallbackURL (nil disables)
+	browser *HeadlessBrowser
+
+	// renderMinChars is the extracted text length below which a page is rendered before it is
+	// summarized (0 renders only when a summary reports missing content)
+	renderMinChars int
+
+	// redactor scrubs configured patterns from every prompt before it is sent (nil disables)
+	redactor *Redactor
+
+	// regional pins calls to Vertex AI in the allowed regions instead of the global API (nil disables)
+	regional *regionalEndpoint
+
+	// documentFetchers read internal documentation (Confluence, Google Docs) through their authenticated APIs
+	documentFetchers []DocumentFetcher
+
+	// summaryLanguage is the language summaries must be written in; a summary in another language
+	// is re-asked once (empty disables the check)
+	summaryLanguage string
+
+	// glossary asks for a glossary of the summary's acronyms (GLOSSARY_CHANNELS is set)
+	glossary bool
+
+	// difficulty asks for the article's audience level (DIFFICULTY_ENABLED)
+	difficulty bool
+
+	// combineComments summarizes an article and its comments in one call (COMMENT_SUMMARY_MODE=combined)
+	combineComments bool
+
+	// health returns the shared health and circuit breaker of a provider (nil disables tracking)
+	health func(provider string) *providerHealth
 }
 
 func NewGeminiRepository(apiKey, model, baseURL string) GeminiRepository {
+	return NewGeminiRepositoryWithExperiment(apiKey, model, baseURL, nil, nil)
+}
+
+// NewGeminiRepositoryWithExperiment creates a Gemini repository whose RSS prompt is chosen by the experiment.
+// A nil experiment always uses the default prompt and leaves PromptVariant empty.
+// extractionRules are the parsed EXTRACTION_RULES from Config (nil uses generic extraction everywhere).
+func NewGeminiRepositoryWithExperiment(apiKey, model, baseURL string, experiment *PromptExperiment, extractionRules []ExtractionRule) GeminiRepository {
+	// Get max article pages from environment (pagination bound)
+	maxPages := defaultMaxArticlePages
+	if env := os.Getenv("MAX_ARTICLE_PAGES"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			maxPages = n
+		}
+	}
+
+	// Get PDF limits from environment
+	maxPDFBytes := int64(defaultMaxPDFBytes)
+	if env := os.Getenv("PDF_MAX_BYTES"); env != "" {
+		if n, err := strconv.ParseInt(env, 10, 64); err == nil && n > 0 {
+			maxPDFBytes = n
+		}
+	}
+	maxPDFPages := defaultMaxPDFPages
+	if env := os.Getenv("PDF_MAX_PAGES"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			maxPDFPages = n
+		}
+	}
+
+	// Get map-reduce threshold from environment (0 disables map-reduce summarization)
+	mapReduceThreshold := defaultMapReduceThreshold
+	if env := os.Getenv("MAP_REDUCE_THRESHOLD"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n >= 0 {
+			mapReduceThreshold = n
+		}
+	}
+
+	// Get rendering backend from environment: a local headless browser or a prerendering service
+	var browser *HeadlessBrowser
+	if path := os.Getenv("RENDER_CHROME_PATH"); path != "" {
+		browser = NewHeadlessBrowser(path, os.Getenv("FETCH_USER_AGENT"))
+	}
+	renderMinChars := defaultRenderMinChars
+	if env := os.Getenv("RENDER_MIN_CHARS"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n >= 0 {
+			renderMinChars = n
+		}
+	}
+
+	// Get redaction rules from environment (validated in Config)
+	var redactor *Redactor
+	redactionRules, err := ParseRedactionRules(os.Getenv("REDACTION_RULES"))
+	if err != nil {
+		log.Printf("Ignoring invalid REDACTION_RULES: %v", err)
+	} else if len(redactionRules) > 0 {
+		redactor = NewRedactor(redactionRules)
+	}
+
+	// Get allowed regions from environment (validated in Config)
+	var regional *regionalEndpoint
+	regions, err := ParseRegions(os.Getenv("GEMINI_REGIONS"))
+	if err != nil {
+		// Fail closed: with no usable region every call is refused rather than sent to the global API
+		log.Printf("Invalid GEMINI_REGIONS, refusing Gemini calls: %v", err)
+		regional = newRegionalEndpoint(os.Getenv("VERTEX_PROJECT"), nil)
+	} else if len(regions) > 0 {
+		regional = newRegionalEndpoint(os.Getenv("VERTEX_PROJECT"), regions)
+	}
+
+	httpClient := &http.Client{
+		Timeout: 60 * time.Second,
+	}
+
+	// Get fetch policy from environment (robots.txt is respected unless RESPECT_ROBOTS_TXT=false)
+	crawlDelaySeconds, _ := strconv.Atoi(os.Getenv("CRAWL_DELAY_SECONDS"))
+	policy := NewFetchPolicy(os.Getenv("FETCH_USER_AGENT"), os.Getenv("RESPECT_ROBOTS_TXT") != "false",
+		time.Duration(max(crawlDelaySeconds, 0))*time.Second, httpClient)
+
+	// Get authenticated document fetchers from environment (validated in Config)
+	var documentFetchers []DocumentFetcher
+	if baseURL := os.Getenv("CONFLUENCE_BASE_URL"); baseURL != "" {
+		fetcher, err := NewConfluenceFetcher(baseURL, os.Getenv("CONFLUENCE_EMAIL"), os.Getenv("CONFLUENCE_API_TOKEN"), httpClient)
+		if err != nil {
+			log.Printf("Ignoring invalid CONFLUENCE_BASE_URL: %v", err)
+		} else {
+			documentFetchers = append(documentFetchers, fetcher)
+		}
+	}
+	if auth := os.Getenv("GOOGLE_DOCS_AUTH"); auth != "" {
+		documentFetchers = append(documentFetchers, NewGoogleDocsFetcher(auth, httpClient))
+	}
+	// YouTube videos are summarized from their captions (the watch page has no readable content)
+	transcriptLanguages := []string{"ja", "en"}
+	if env := os.Getenv("YOUTUBE_TRANSCRIPT_LANGUAGES"); env != "" {
+		transcriptLanguages = strings.Split(strings.ReplaceAll(env, " ", ""), ",")
+	}
+	documentFetchers = append(documentFetchers, NewYouTubeTranscriptFetcher(transcriptLanguages, httpClient))
+	// GitHub repositories are summarized from their README and metadata (the repository page is built by JavaScript)
+	documentFetchers = append(documentFetchers, NewGitHubRepoFetcher(os.Getenv("GITHUB_TOKEN"), httpClient))
+
+	// Providers are listed by ProviderStatuses from startup, before their first call
+	if regional != nil {
+		for _, region := range regional.regions {
+			providerHealthFor(regionProvider(region))
+		}
+	} else {
+		providerHealthFor(providerGemini)
+	}
+
+	// Get summary language from environment (validated in Config)
+	summaryLanguage := os.Getenv("SUMMARY_LANGUAGE")
+	if ValidateSummaryLanguage(summaryLanguage) != nil {
+		summaryLanguage = SummaryLanguageJapanese
+	}
+
 	return &geminiRepository{
-		apiKey:  apiKey,
-		model:   model,
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		maxPages:   maxPages,
+		experiment: experiment,
+
+		maxPDFBytes: maxPDFBytes,
+		maxPDFPages: maxPDFPages,
+
+		mapReduceThreshold: mapReduceThreshold,
+		extractionRules:    extractionRules,
+		renderFallbackURL:  os.Getenv("RENDER_FALLBACK_URL"),
+		policy:             policy,
+		browser:            browser,
+		renderMinChars:     renderMinChars,
+		redactor:           redactor,
+		regional:           regional,
+		documentFetchers:   documentFetchers,
+		summaryLanguage:    summaryLanguage,
+		glossary:           len(GlossaryChannelsFromEnv()) > 0,
+		difficulty:         os.Getenv("DIFFICULTY_ENABLED") == "true",
+		combineComments:    commentSummaryModeFromEnv() == CommentSummaryCombined,
+		health:             providerHealthFor,
+		httpClient:         httpClient,
 	}
 }
 
@@ -58,18 +269,48 @@ func (g *geminiRepository) SummarizeURL(ctx context.Context, url string) (*Summa
 	start := time.Now()
 
 	logger.Printf("HTML fetch started url=%s", url)
-	// Fetch HTML content
-	htmlContent, err := g.fetchHTML(ctx, url)
+	// Fetch HTML content (following pagination for multi-page articles)
+	pages, err := g.fetchArticlePages(ctx, url)
 	if err != nil {
 		logger.Printf("Error fetching HTML from URL %s: %v", url, err)
 		return nil, fmt.Errorf("fetching HTML: %w", err)
 	}
 
 	fetchDuration := time.Since(start)
-	logger.Printf("HTML fetch completed url=%s content_length=%d duration_ms=%d", url, len(htmlContent), fetchDuration.Milliseconds())
+	logger.Printf("HTML fetch completed url=%s content_length=%d pages=%d duration_ms=%d", url, contentLength(pages), len(pages), fetchDuration.Milliseconds())
+
+	// Pages built by JavaScript are rendered when their static HTML has too little text
+	pages, rendered := g.renderSparsePages(ctx, url, pages)
+
+	return g.summarizePages(ctx, url, pages, start, rendered)
+}
+
+func (g *geminiRepository) SummarizeRendered(ctx context.Context, articleURL string) (*SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if !g.renderEnabled() {
+		return nil, ErrRenderFallbackDisabled
+	}
+	start := time.Now()
+
+	logger.Printf("Rendered HTML fetch started url=%s", articleURL)
+	html, err := g.render(ctx, articleURL)
+	if err != nil {
+		logger.Printf("Error fetching rendered HTML for URL %s: %v", articleURL, err)
+		return nil, fmt.Errorf("fetching rendered HTML: %w", err)
+	}
+	logger.Printf("Rendered HTML fetch completed url=%s content_length=%d duration_ms=%d", articleURL, len(html), time.Since(start).Milliseconds())
+
+	return g.summarizePages(ctx, articleURL, []string{html}, start, true)
+}
+
+// summarizePages summarizes an article's fetched pages with the RSS prompt; rendered marks pages
+// from the headless browser or the render fallback
+func (g *geminiRepository) summarizePages(ctx context.Context, url string, pages []string, start time.Time, rendered bool) (*SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	var err error
 
 	// Extract text from HTML
-	textContent := g.extractTextFromHTML(htmlContent)
+	textContent, extraction := g.extractTextFromPages(ctx, url, pages)
 	if textContent == "" {
 		logger.Printf("No text content found url=%s", url)
 		return &SummarizeResponse{
@@ -81,13 +322,24 @@ func (g *geminiRepository) SummarizeURL(ctx context.Context, url string) (*Summa
 
 	logger.Printf("Text extraction completed url=%s text_length=%d", url, len(textContent))
 
-	// Create prompt for RSS mode (shorter summary for team sharing)
-	prompt := g.buildRSSPrompt(textContent)
+	promptText, mapReduced, err := g.preparePromptText(ctx, textContent)
+	if err != nil {
+		logger.Printf("Error in map-reduce summarization for URL %s: %v", url, err)
+		return nil, err
+	}
+
+	// Create prompt for RSS mode (shorter summary for team sharing), in the article's language with SUMMARY_LANGUAGE=auto
+	variant := ""
+	if g.experiment != nil {
+		variant = g.experiment.Assign()
+	}
+	ctx, language := g.withPromptLanguage(ctx, textContent)
+	prompt := g.buildRSSPrompt(promptText, variant, language)
 
 	// Call Gemini API
 	geminiStart := time.Now()
-	logger.Printf("Gemini API call started url=%s", url)
-	summary, err := g.callGeminiAPI(ctx, prompt)
+	logger.Printf("Gemini API call started url=%s prompt_variant=%s", url, variant)
+	summary, annotations, err := g.callAnnotatedSummary(ctx, prompt)
 	if err != nil {
 		logger.Printf("Error calling Gemini API for URL %s: %v", url, err)
 		return nil, err
@@ -99,9 +351,13 @@ func (g *geminiRepository) SummarizeURL(ctx context.Context, url string) (*Summa
 		url, len(summary), geminiDuration.Milliseconds(), totalDuration.Milliseconds())
 
 	return &SummarizeResponse{
-		Summary:      summary,
-		ProcessedAt:  time.Now(),
-		ContentChars: len(textContent),
+		Summary:       summary,
+		ProcessedAt:   time.Now(),
+		ContentChars:  len(textContent),
+		PromptVariant: variant,
+		Provenance:    g.provenance(languagePromptName(rssPromptName(variant), language), g.pageExtraction(url, extraction, rendered, mapReduced)...),
+		Glossary:      annotations.Glossary,
+		Difficulty:    annotations.Difficulty,
 	}, nil
 }
 
@@ -114,35 +370,93 @@ func (g *geminiRepository) fetchHTML(ctx context.Context, url string) (string, e
 		return "", fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Article Summarizer Bot/1.0)")
+	req.Header.Set("User-Agent", g.policy.UserAgent())
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
 		logger.Printf("Error making HTTP request to URL %s: %v request_headers=%v\nStack:\n%s", url, err, req.Header, debug.Stack())
-		return "", fmt.Errorf("fetching URL: %w", err)
+		return "", httperr.Transport(fmt.Errorf("fetching URL: %w", err))
 	}
 	defer resp.Body.Close()
 
+	bodyReader, err := decodedBody(resp)
+	if err != nil {
+		logger.Printf("Error decoding response body from URL %s content_encoding=%s: %v", url, resp.Header.Get("Content-Encoding"), err)
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		// Read response body for error details
-		responseBody, _ := io.ReadAll(resp.Body)
+		responseBody, _ := io.ReadAll(bodyReader)
 
 		// Log detailed error information
 		logger.Printf("HTTP request failed url=%s status_code=%d request_headers=%v response_headers=%v response_body=%s\nStack:\n%s",
 			url, resp.StatusCode, req.Header, resp.Header, string(responseBody), debug.Stack())
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// PDFs (papers, whitepapers) are read up to the size cap, one byte more to detect larger ones
+	contentType := resp.Header.Get("Content-Type")
+	reader := bodyReader
+	if isPDF(contentType, nil) {
+		reader = io.LimitReader(bodyReader, g.pdfMaxBytes()+1)
+	}
+	body, err := io.ReadAll(reader)
 	if err != nil {
 		logger.Printf("Error reading response body from URL %s: %v", url, err)
 		return "", fmt.Errorf("reading response body: %w", err)
 	}
+	if isPDF(contentType, body) {
+		return g.pdfPage(ctx, url, body), nil
+	}
+
+	page, pageCharset := decodeHTML(body, contentType)
+	if pageCharset != "utf-8" {
+		logger.Printf("Page decoded to UTF-8 url=%s charset=%s", url, pageCharset)
+	}
+	return page, nil
+}
+
+// pdfPage converts a fetched PDF into HTML holding its text. PDFs over the size cap or without
+// extractable text (scanned images) become an empty page, so that the article is summarized
+// from its feed description instead.
+func (g *geminiRepository) pdfPage(ctx context.Context, url string, body []byte) string {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if int64(len(body)) > g.pdfMaxBytes() {
+		logger.Printf("Warning: PDF too large, skipping its text url=%s max_bytes=%d", url, g.pdfMaxBytes())
+		return ""
+	}
+	page, err := pdfHTML(body, g.pdfMaxPages())
+	if err != nil {
+		logger.Printf("Warning: Failed to extract PDF text url=%s bytes=%d: %v", url, len(body), err)
+		return ""
+	}
+	logger.Printf("PDF converted url=%s bytes=%d max_pages=%d", url, len(body), g.pdfMaxPages())
+	return page
+}
 
-	return string(body), nil
+func (g *geminiRepository) pdfMaxBytes() int64 {
+	if g.maxPDFBytes > 0 {
+		return g.maxPDFBytes
+	}
+	return defaultMaxPDFBytes
+}
+
+func (g *geminiRepository) pdfMaxPages() int {
+	if g.maxPDFPages > 0 {
+		return g.maxPDFPages
+	}
+	return defaultMaxPDFPages
 }
 
 func (g *geminiRepository) extractTextFromHTML(html string) string {
+	// Keep lists and tables structured (see htmlToText)
+	if text, ok := htmlToText(html); ok {
+		return text
+	}
+
+	// Fallback: strip tags and flatten
 	// Remove script and style tags
 	scriptRe := regexp.MustCompile(`(?i)<script[^>]*>[\s\S]*?</script>`)
 	html = scriptRe.ReplaceAllString(html, "")
@@ -156,7 +470,7 @@ func (g *geminiRepository) extractTextFromHTML(html string) string {
 
 	// Normalize whitespace
 	spaceRe := regexp.MustCompile(`\s+`)
-	text = spaceRe.ReplaceAllString(text, " ")
+	text = spaceRe.ReplaceAllString(decodeEntities(text), " ")
 
 	return strings.TrimSpace(text)
 }
@@ -166,7 +480,7 @@ func (g *geminiRepository) extractTitleFromHTML(html string) string {
 	titleRe := regexp.MustCompile(`(?i)<title[^>]*>([^<]*)</title>`)
 	matches := titleRe.FindStringSubmatch(html)
 	if len(matches) > 1 {
-		title := strings.TrimSpace(matches[1])
+		title := strings.TrimSpace(decodeEntities(matches[1]))
 		if title != "" {
 			return title
 		}
@@ -176,7 +490,7 @@ func (g *geminiRepository) extractTitleFromHTML(html string) string {
 	ogTitleRe := regexp.MustCompile(`(?i)<meta[^>]*property=["\']og:title["\'][^>]*content=["\']([^"\']*)["\']`)
 	matches = ogTitleRe.FindStringSubmatch(html)
 	if len(matches) > 1 {
-		title := strings.TrimSpace(matches[1])
+		title := strings.TrimSpace(decodeEntities(matches[1]))
 		if title != "" {
 			return title
 		}
@@ -185,25 +499,19 @@ func (g *geminiRepository) extractTitleFromHTML(html string) string {
 	return ""
 }
 
-func (g *geminiRepository) buildRSSPrompt(textContent string) string {
-	// Limit content to 10KB
-	if len(textContent) > 10000 {
-		textContent = textContent[:10000]
+// buildRSSPrompt builds the RSS prompt for the given experiment variant (empty means default) in the
+// prompt language (see promptLanguage)
+func (g *geminiRepository) buildRSSPrompt(textContent, variant, language string) string {
+	variants := rssPromptVariants
+	if language == SummaryLanguageEnglish {
+		variants = rssPromptVariantsEnglish
+	}
+	template, ok := variants[variant]
+	if !ok {
+		template = variants[DefaultPromptVariant]
 	}
 
-	return fmt.Sprintf(`以下のテキストを、Slackチャンネルでチームメンバーが素早く理解できるよう、1000文字以内で簡潔に要約してください。
-
-**重要な制約:**
-- 推測や創作は一切せず、実際に記載されている内容のみを要約してください
-- 記載されていない情報は追加しないでください
-
-チーム共有を前提とした読みやすい形式で、以下の構造で出力してください：
-- 📝 **要約:** 実際の内容を3-4行で簡潔に
-- 🎯 **対象者:** 記載されている課題や対象を基に
-- 💡 **解決効果:** 明記されている効果や解決策のみ
-
-テキスト内容:
-%s`, textContent)
+	return fmt.Sprintf(template, textContent)
 }
 
 // Gemini API types
@@ -213,6 +521,7 @@ type geminiRequest struct {
 }
 
 type geminiContent struct {
+	Role  string       `json:"role,omitempty"` // Required by Vertex AI
 	Parts []geminiPart `json:"parts"`
 }
 
@@ -237,9 +546,13 @@ type geminiCandidate struct {
 func (g *geminiRepository) callGeminiAPI(ctx context.Context, prompt string) (string, error) {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
+	// Article text, comments and summaries all reach the LLM through here
+	prompt = g.redactor.Redact(ctx, prompt)
+
 	geminiReq := geminiRequest{
 		Contents: []geminiContent{
 			{
+				Role: "user",
 				Parts: []geminiPart{
 					{Text: prompt},
 				},
@@ -252,47 +565,173 @@ func (g *geminiRepository) callGeminiAPI(ctx context.Context, prompt string) (st
 		},
 	}
 
-	url := fmt.Sprintf("%s/%s:generateContent?key=%s", g.baseURL, g.model, g.apiKey)
-
 	body, err := json.Marshal(geminiReq)
 	if err != nil {
 		logger.Printf("Error marshaling Gemini request: %v", err)
 		return "", fmt.Errorf("marshaling request: %w", err)
 	}
 
+	// Data residency: only the allowed regions' Vertex AI endpoints may receive the prompt
+	if g.regional != nil {
+		return g.callRegionalAPI(ctx, body)
+	}
+
+	health := g.providerHealth(providerGemini)
+	if !health.Allow() {
+		return "", &httperr.Error{Kind: httperr.ErrTemporary, Err: fmt.Errorf("gemini: %w", ErrCircuitOpen)}
+	}
+	url := fmt.Sprintf("%s/%s:generateContent?key=%s", g.baseURL, g.model, g.apiKey)
+	text, _, err := g.sendGenerateContent(ctx, url, "", body)
+	health.Record(err)
+	return text, err
+}
+
+// callGeminiSummary calls the Gemini API for a summary and, when the model answered in another language
+// than the summary language (e.g. English to the Japanese prompt), re-asks once with an explicit instruction
+func (g *geminiRepository) callGeminiSummary(ctx context.Context, prompt string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	language := g.outputLanguage(ctx)
+
+	firstPrompt := prompt
+	// The prompts are written in Japanese (unless chosen per article with SUMMARY_LANGUAGE=auto),
+	// so other languages are requested up front
+	if language != "" && language != SummaryLanguageJapanese && g.summaryLanguage != SummaryLanguageAuto {
+		firstPrompt += languageInstructions[language]
+	}
+	summary, err := g.callGeminiAPI(ctx, firstPrompt)
+	if err != nil || language == "" {
+		return summary, err
+	}
+	detected := detectLanguage(summary)
+	if detected == "" || detected == language {
+		return summary, nil
+	}
+
+	logger.Printf("Summary language mismatch, retrying expected=%s detected=%s", language, detected)
+	retried, err := g.callGeminiAPI(ctx, prompt+languageInstructions[language])
+	if err != nil {
+		// A summary in the wrong language still beats none
+		logger.Printf("Error retrying summary in %s, keeping the first answer: %v", language, err)
+		return summary, nil
+	}
+	if detected := detectLanguage(retried); detected != "" && detected != language {
+		logger.Printf("Summary language still mismatched after retry expected=%s detected=%s", language, detected)
+	}
+	return retried, nil
+}
+
+// summaryAnnotations are asked for in the same call as a summary
+type summaryAnnotations struct {
+	Glossary   []GlossaryEntry
+	Difficulty string
+}
+
+// callAnnotatedSummary calls callGeminiSummary, asking in the same call for a glossary of the summary's
+// acronyms (GLOSSARY_CHANNELS) and the article's difficulty (DIFFICULTY_ENABLED), which are split off the summary
+func (g *geminiRepository) callAnnotatedSummary(ctx context.Context, prompt string) (string, summaryAnnotations, error) {
+	var annotations summaryAnnotations
+	if g.glossary {
+		prompt += glossaryInstruction
+	}
+	if g.difficulty {
+		prompt += difficultyInstruction
+	}
+	summary, err := g.callGeminiSummary(ctx, prompt)
+	if err != nil {
+		return "", annotations, err
+	}
+	if g.difficulty {
+		summary, annotations.Difficulty = splitDifficulty(summary)
+	}
+	if g.glossary {
+		summary, annotations.Glossary = splitGlossary(summary)
+	}
+	return summary, annotations, nil
+}
+
+// callRegionalAPI sends the request to the allowed regions in order, moving on only when a region
+// is unreachable or cannot serve the model. When every region fails the request is refused with
+// ErrRegionUnavailable (never sent to the global endpoint).
+func (g *geminiRepository) callRegionalAPI(ctx context.Context, body []byte) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if len(g.regional.regions) == 0 {
+		return "", fmt.Errorf("%w: no valid region configured in GEMINI_REGIONS", ErrRegionUnavailable)
+	}
+	token, err := g.regional.token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var failures []string
+	for _, region := range g.regional.regions {
+		health := g.providerHealth(regionProvider(region))
+		if !health.Allow() {
+			// A region failing repeatedly is skipped until its cooldown passes
+			failures = append(failures, fmt.Sprintf("%s: %v", region, ErrCircuitOpen))
+			continue
+		}
+		text, status, err := g.sendGenerateContent(ctx, g.regional.url(region, g.model), token, body)
+		health.Record(err)
+		if err == nil {
+			return text, nil
+		}
+		if !regionUnavailable(err) {
+			// Rate limits and bad requests are not fixed by changing region
+			return "", fmt.Errorf("region %s: %w", region, err)
+		}
+		logger.Printf("Warning: Gemini region unavailable region=%s status=%d: %v", region, status, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", region, err))
+	}
+	// Outages pass, so the article stays retryable
+	return "", &httperr.Error{
+		Kind: httperr.ErrTemporary,
+		Err:  fmt.Errorf("%w %s: %s", ErrRegionUnavailable, strings.Join(g.regional.regions, ","), strings.Join(failures, "; ")),
+	}
+}
+
+// sendGenerateContent posts a generateContent request and returns the response text.
+// The status code is 0 when no response was received.
+func (g *geminiRepository) sendGenerateContent(ctx context.Context, url, bearerToken string, body []byte) (string, int, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
 		logger.Printf("Error creating Gemini API request: %v", err)
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", 0, fmt.Errorf("creating request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	if bearerToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
 
 	resp, err := g.httpClient.Do(httpReq)
 	if err != nil {
 		logger.Printf("Error sending request to Gemini API: %v\nStack:\n%s", err, debug.Stack())
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", 0, httperr.Transport(fmt.Errorf("sending request: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		logger.Printf("Gemini API request failed status_code=%d response=%s\nStack:\n%s", resp.StatusCode, string(bodyBytes), debug.Stack())
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return "", resp.StatusCode, httperr.FromResponse(resp, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(bodyBytes)))
 	}
 
 	var geminiResp geminiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
 		logger.Printf("Error decoding Gemini API response: %v", err)
-		return "", fmt.Errorf("decoding response: %w", err)
+		return "", resp.StatusCode, fmt.Errorf("decoding response: %w", err)
 	}
 
 	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
 		logger.Printf("No content in Gemini API response")
-		return "", fmt.Errorf("no content in response")
+		// Blocked or empty candidates come back the same way on retry
+		return "", resp.StatusCode, httperr.Permanent(errors.New("no content in response"))
 	}
 
-	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+	return geminiResp.Candidates[0].Content.Parts[0].Text, resp.StatusCode, nil
 }
 
 func (g *geminiRepository) SummarizeURLForOnDemand(ctx context.Context, url string) (*SummarizeResponse, error) {
@@ -300,19 +739,21 @@ func (g *geminiRepository) SummarizeURLForOnDemand(ctx context.Context, url stri
 	start := time.Now()
 
 	logger.Printf("On-demand HTML fetch started url=%s", url)
-	// Fetch HTML content
-	htmlContent, err := g.fetchHTML(ctx, url)
+	ReportProgress(ctx, ProgressFetching)
+	// Fetch HTML content (following pagination for multi-page articles)
+	pages, err := g.fetchArticlePages(ctx, url)
 	if err != nil {
 		logger.Printf("Error fetching HTML for on-demand from URL %s: %v", url, err)
 		return nil, fmt.Errorf("fetching HTML: %w", err)
 	}
 
 	fetchDuration := time.Since(start)
-	logger.Printf("On-demand HTML fetch completed url=%s content_length=%d duration_ms=%d", url, len(htmlContent), fetchDuration.Milliseconds())
+	logger.Printf("On-demand HTML fetch completed url=%s content_length=%d pages=%d duration_ms=%d", url, contentLength(pages), len(pages), fetchDuration.Milliseconds())
 
-	// Extract title and text from HTML
-	title := g.extractTitleFromHTML(htmlContent)
-	textContent := g.extractTextFromHTML(htmlContent)
+	// Extract title and text from HTML (title comes from the first page)
+	ReportProgress(ctx, ProgressExtracting)
+	title := g.extractTitleFromHTML(pages[0])
+	textContent, extraction := g.extractTextFromPages(ctx, url, pages)
 	if textContent == "" {
 		logger.Printf("No text content found for on-demand url=%s", url)
 		return &SummarizeResponse{
@@ -324,14 +765,22 @@ func (g *geminiRepository) SummarizeURLForOnDemand(ctx context.Context, url stri
 	}
 
 	logger.Printf("On-demand text extraction completed url=%s text_length=%d", url, len(textContent))
+	ReportProgress(ctx, ProgressSummarizing)
+
+	promptText, mapReduced, err := g.preparePromptText(ctx, textContent)
+	if err != nil {
+		logger.Printf("Error in on-demand map-reduce summarization for URL %s: %v", url, err)
+		return nil, err
+	}
 
 	// Create prompt for on-demand mode (longer summary for individual requests)
-	prompt := g.buildOnDemandPrompt(textContent)
+	ctx, language := g.withPromptLanguage(ctx, textContent)
+	prompt := g.buildOnDemandPrompt(promptText, language)
 
 	// Call Gemini API
 	geminiStart := time.Now()
 	logger.Printf("On-demand Gemini API call started url=%s", url)
-	summary, err := g.callGeminiAPI(ctx, prompt)
+	summary, annotations, err := g.callAnnotatedSummary(ctx, prompt)
 	if err != nil {
 		logger.Printf("Error calling Gemini API for on-demand URL %s: %v", url, err)
 		return nil, err
@@ -347,13 +796,15 @@ func (g *geminiRepository) SummarizeURLForOnDemand(ctx context.Context, url stri
 		ProcessedAt:  time.Now(),
 		ContentChars: len(textContent),
 		Title:        title,
+		Provenance:   g.provenance(languagePromptName("ondemand@"+onDemandPromptVersion, language), g.pageExtraction(url, extraction, false, mapReduced)...),
+		Glossary:     annotations.Glossary,
+		Difficulty:   annotations.Difficulty,
 	}, nil
 }
 
-func (g *geminiRepository) buildOnDemandPrompt(textContent string) string {
-	// Limit content to 10KB
-	if len(textContent) > 10000 {
-		textContent = textContent[:10000]
+func (g *geminiRepository) buildOnDemandPrompt(textContent, language string) string {
+	if language == SummaryLanguageEnglish {
+		return fmt.Sprintf(onDemandPromptEnglish, textContent)
 	}
 
 	return fmt.Sprintf(`以下のテキストを、個人のリクエストに応じて詳細に要約してください。800-1200文字程度の詳細な要約を作成してください。
@@ -377,14 +828,21 @@ func (g *geminiRepository) buildOnDemandPrompt(textContent string) string {
 func (g *geminiRepository) SummarizeText(ctx context.Context, text string) (string, error) {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
-	// Build prompt for text summarization (using detailed on-demand format)
-	prompt := g.buildOnDemandPrompt(text)
-
 	logger.Printf("Text summarization started text_length=%d", len(text))
 
+	promptText, _, err := g.preparePromptText(ctx, text)
+	if err != nil {
+		logger.Printf("Error in map-reduce text summarization: %v", err)
+		return "", err
+	}
+
+	// Build prompt for text summarization (using detailed on-demand format)
+	ctx, language := g.withPromptLanguage(ctx, text)
+	prompt := g.buildOnDemandPrompt(promptText, language)
+
 	// Call Gemini API
 	geminiStart := time.Now()
-	summary, err := g.callGeminiAPI(ctx, prompt)
+	summary, err := g.callGeminiSummary(ctx, prompt)
 	if err != nil {
 		logger.Printf("Error calling Gemini API for text summarization: %v", err)
 		return "", fmt.Errorf("calling Gemini API: %w", err)
@@ -402,13 +860,14 @@ func (g *geminiRepository) SummarizeComments(ctx context.Context, commentsText s
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
 	// Build specialized prompt for comments
-	prompt := g.buildCommentsPrompt(commentsText)
+	ctx, language := g.withPromptLanguage(ctx, commentsText)
+	prompt := g.buildCommentsPrompt(commentsText, language)
 
 	logger.Printf("Comments summarization started text_length=%d", len(commentsText))
 
 	// Call Gemini API
 	geminiStart := time.Now()
-	summary, err := g.callGeminiAPI(ctx, prompt)
+	summary, err := g.callGeminiSummary(ctx, prompt)
 	if err != nil {
 		logger.Printf("Error calling Gemini API for comments summarization: %v", err)
 		return nil, fmt.Errorf("calling Gemini API: %w", err)
@@ -422,14 +881,107 @@ func (g *geminiRepository) SummarizeComments(ctx context.Context, commentsText s
 		Summary:      summary,
 		ProcessedAt:  time.Now(),
 		ContentChars: len(commentsText),
+		Provenance:   g.provenance(languagePromptName("comments@"+commentsPromptVersion, language), ExtractionComments),
 	}, nil
 }
 
+// RewriteHeadline asks for a neutral, descriptive one-line headline grounded in the summary
+func (g *geminiRepository) RewriteHeadline(ctx context.Context, title, summary string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	geminiStart := time.Now()
+	headline, err := g.callGeminiAPI(ctx, buildHeadlinePrompt(title, summary))
+	if err != nil {
+		logger.Printf("Error calling Gemini API for headline rewrite: %v", err)
+		return "", fmt.Errorf("calling Gemini API: %w", err)
+	}
+
+	headline = cleanHeadline(headline)
+	if headline == "" {
+		return "", fmt.Errorf("empty headline")
+	}
+
+	logger.Printf("Headline rewrite completed original=%s rewritten=%s duration_ms=%d",
+		title, headline, time.Since(geminiStart).Milliseconds())
+	return headline, nil
+}
+
+func buildHeadlinePrompt(title, summary string) string {
+	summary = truncateUTF8(summary, 3000)
+
+	return fmt.Sprintf(`以下は記事の元タイトルと要約です。煽り・誇張・釣りの表現を取り除き、記事の内容を中立的かつ具体的に表す見出しを1つだけ作成してください。
+
+**重要な制約:**
+- 要約に書かれている内容のみに基づき、推測や創作はしないでください
+- 元タイトルと同じ言語で、80文字以内の1行で出力してください
+- 見出しのみを出力し、説明・引用符・記号の装飾は付けないでください
+
+元タイトル:
+%s
+
+要約:
+%s`, title, summary)
+}
+
+// TranslateSummary translates a summary into language for delivery next to the original
+func (g *geminiRepository) TranslateSummary(ctx context.Context, summary, language string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if detectLanguage(summary) == language {
+		return summary, nil
+	}
+
+	geminiStart := time.Now()
+	translation, err := g.callGeminiAPI(ctx, buildTranslationPrompt(summary, language))
+	if err != nil {
+		logger.Printf("Error calling Gemini API for summary translation: %v", err)
+		return "", fmt.Errorf("calling Gemini API: %w", err)
+	}
+
+	translation = strings.TrimSpace(translation)
+	if translation == "" {
+		return "", fmt.Errorf("empty translation")
+	}
+
+	logger.Printf("Summary translation completed language=%s duration_ms=%d", language, time.Since(geminiStart).Milliseconds())
+	return translation, nil
+}
+
+func buildTranslationPrompt(summary, language string) string {
+	target := "日本語"
+	if language == SummaryLanguageEnglish {
+		target = "英語"
+	}
+
+	return fmt.Sprintf(`以下の要約を%sに翻訳してください。
+
+**重要な制約:**
+- 内容を追加・省略せず、要約に書かれている内容のみを翻訳してください
+- 見出しの絵文字・太字・箇条書きなどの構成はそのまま保ってください
+- 固有名詞・コード・URLは翻訳せずそのまま残してください
+- 翻訳文のみを出力し、説明や前置きは付けないでください
+
+要約:
+%s`, target, summary)
+}
+
+// cleanHeadline keeps the first non-empty line without surrounding quotes or Markdown emphasis
+func cleanHeadline(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "\"'「」*#` ")
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 // buildCommentsPrompt creates specialized prompt for comments/discussions
-func (g *geminiRepository) buildCommentsPrompt(commentsText string) string {
+func (g *geminiRepository) buildCommentsPrompt(commentsText, language string) string {
 	// Limit content to 10KB for better focus and 1000-char summary
-	if len(commentsText) > 10000 {
-		commentsText = commentsText[:10000]
+	commentsText = truncateUTF8(commentsText, maxPromptTextBytes)
+	if language == SummaryLanguageEnglish {
+		return fmt.Sprintf(commentsPromptEnglish, commentsText)
 	}
 
 	return fmt.Sprintf(`以下はオンラインディスカッション・コメントのテキストです。コミュニティの議論内容を分析し、1000文字以内で簡潔に要約してください。
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestGitHubRepo(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/golang/go":                    "golang/go",
+		"https://www.github.com/golang/go/":               "golang/go",
+		"https://github.com/golang/go.git":                "golang/go",
+		"https://github.com/golang/go#readme":             "golang/go",
+		"https://github.com/golang/go/issues/1":           "",
+		"https://github.com/golang/go/blob/master/x.go":   "",
+		"https://github.com/golang":                       "",
+		"https://github.com/topics/go":                    "",
+		"https://gist.github.com/golang/0123456789abcdef": "",
+	}
+	for rawURL, want := range tests {
+		parsed, _ := url.Parse(rawURL)
+		owner, repo := GitHubRepo(parsed)
+		got := ""
+		if owner != "" {
+			got = owner + "/" + repo
+		}
+		if got != want {
+			t.Errorf("GitHubRepo(%s) = %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestGitHubRepoFetcher_Fetch(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/repos/example/tool":
+			fmt.Fprint(w, `{"full_name":"example/tool","description":"A <fast> build tool","language":"Go","topics":["build","cli"],"stargazers_count":1234,"forks_count":56,"license":{"spdx_id":"MIT"}}`)
+		case "/repos/example/tool/readme":
+			if r.Header.Get("Accept") != "application/vnd.github.html+json" {
+				t.Errorf("Expected the rendered README to be requested, got Accept %q", r.Header.Get("Accept"))
+			}
+			fmt.Fprint(w, `<div id="readme"><h1>tool</h1><p>Builds Go projects incrementally.</p></div>`)
+		case "/repos/example/empty":
+			fmt.Fprint(w, `{"full_name":"example/empty","stargazers_count":3,"forks_count":0}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewGitHubRepoFetcher("gh-token", server.Client())
+	fetcher.apiBaseURL = server.URL
+
+	page, err := fetcher.Fetch(context.Background(), "https://github.com/example/tool")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"<title>example/tool: A &lt;fast&gt; build tool</title>",
+		"Stars: 1234 / Forks: 56 / Language: Go / License: MIT",
+		"Topics: build, cli",
+		"<p>Builds Go projects incrementally.</p>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q in the page, got %s", want, page)
+		}
+	}
+	if authorization != "Bearer gh-token" {
+		t.Errorf("Expected GITHUB_TOKEN to be sent, got %q", authorization)
+	}
+
+	// Repositories without a README keep their metadata
+	page, err = fetcher.Fetch(context.Background(), "https://github.com/example/empty")
+	if err != nil || !strings.Contains(page, "<title>example/empty</title>") || !strings.Contains(page, "Stars: 3") {
+		t.Errorf("Expected the metadata without a README, got %s (%v)", page, err)
+	}
+
+	_, err = fetcher.Fetch(context.Background(), "https://github.com/example/missing")
+	if !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent error for a missing repository, got %v", err)
+	}
+}
+
+func TestGitHubRepoFetcher_RateLimited(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+	}))
+	defer server.Close()
+
+	fetcher := NewGitHubRepoFetcher("", server.Client())
+	fetcher.apiBaseURL = server.URL
+
+	_, err := fetcher.Fetch(context.Background(), "https://github.com/example/tool")
+	if !errors.Is(err, httperr.ErrRateLimited) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if wait, ok := httperr.RetryAfter(err); !ok || wait < 9*time.Minute || wait > 10*time.Minute {
+		t.Errorf("Expected to retry after the rate limit reset, got %v", wait)
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxGlossaryEntries is the number of terms kept per summary
+const maxGlossaryEntries = 5
+
+// glossaryMarker separates the glossary the model appends from the summary
+const glossaryMarker = "---用語---"
+
+// glossaryInstruction asks for the glossary in the same call as the summary
+const glossaryInstruction = "\n\n**用語集:** 要約に専門的な略語（例: CRDT, eBPF, RLHF）が含まれる場合だけ、要約の後に `" + glossaryMarker +
+	"` だけの行を置き、続けて `- 略語: 一行の説明` の形式で最大5件書いてください。一般的な略語（API, URL, AI など）や該当がない場合はこの部分を出力しないでください。"
+
+// acronymPattern matches short tokens of letters and digits; isAcronym also requires two capitals (CRDT, eBPF, gRPC)
+var acronymPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9+./-]{1,15}$`)
+
+// GlossaryEntry explains one acronym used in a summary
+type GlossaryEntry struct {
+	Term       string `json:"term"`
+	Definition string `json:"definition"`
+}
+
+// GlossaryChannelsFromEnv reads GLOSSARY_CHANNELS, the Slack channels whose summaries get a glossary line
+func GlossaryChannelsFromEnv() []string {
+	var channels []string
+	for _, channel := range strings.Split(os.Getenv("GLOSSARY_CHANNELS"), ",") {
+		if channel = strings.TrimPrefix(strings.TrimSpace(channel), "#"); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// glossaryEnabled reports whether channel is one of channels (with or without a leading #)
+func glossaryEnabled(channels []string, channel string) bool {
+	return slices.Contains(channels, strings.TrimPrefix(channel, "#"))
+}
+
+// splitGlossary separates the model's glossary from the summary, keeping only acronyms that appear in it
+func splitGlossary(text string) (string, []GlossaryEntry) {
+	summary, block, found := strings.Cut(text, glossaryMarker)
+	if !found {
+		return text, nil
+	}
+	summary = strings.TrimSpace(summary)
+
+	var glossary []GlossaryEntry
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*・"))
+		term, definition, ok := strings.Cut(strings.Replace(line, "：", ":", 1), ":")
+		term, definition = strings.Trim(strings.TrimSpace(term), "*`"), strings.TrimSpace(definition)
+		if !ok || definition == "" || !isAcronym(term) || !strings.Contains(summary, term) {
+			continue
+		}
+		glossary = append(glossary, GlossaryEntry{Term: term, Definition: definition})
+		if len(glossary) == maxGlossaryEntries {
+			break
+		}
+	}
+	return summary, glossary
+}
+
+// isAcronym reports whether term looks like a technical acronym rather than a word
+func isAcronym(term string) bool {
+	if !acronymPattern.MatchString(term) {
+		return false
+	}
+	upper := 0
+	for _, r := range term {
+		if r >= 'A' && r <= 'Z' {
+			upper++
+		}
+	}
+	return upper >= 2
+}
+
+// FormatGlossary renders a glossary on one line, e.g. "CRDT（競合なく複製できるデータ型） / eBPF（…）"
+func FormatGlossary(glossary []GlossaryEntry) string {
+	parts := make([]string, len(glossary))
+	for i, entry := range glossary {
+		parts[i] = entry.Term + "（" + entry.Definition + "）"
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitGlossary(t *testing.T) {
+	text := "CRDT と eBPF を使った同期基盤の紹介。API も公開している。\n\n" + glossaryMarker + `
+- CRDT: 競合なく複製できるデータ型
+- **eBPF**：カーネル内で安全にプログラムを実行する仕組み
+- API: アプリケーションのインターフェース
+- RLHF: 人間のフィードバックによる強化学習
+- Kubernetes: コンテナ基盤`
+
+	summary, glossary := splitGlossary(text)
+	if summary != "CRDT と eBPF を使った同期基盤の紹介。API も公開している。" {
+		t.Errorf("Expected the summary without the glossary, got %q", summary)
+	}
+	// RLHF is not in the summary and Kubernetes is a word, not an acronym
+	var terms []string
+	for _, entry := range glossary {
+		terms = append(terms, entry.Term)
+	}
+	if strings.Join(terms, ",") != "CRDT,eBPF,API" {
+		t.Errorf("Unexpected terms %v", terms)
+	}
+	if glossary[1].Definition != "カーネル内で安全にプログラムを実行する仕組み" {
+		t.Errorf("Expected a full-width colon to be accepted, got %+v", glossary[1])
+	}
+
+	if summary, glossary := splitGlossary("要約だけ。"); summary != "要約だけ。" || glossary != nil {
+		t.Errorf("Expected a summary without glossary to be kept, got %q %v", summary, glossary)
+	}
+}
+
+func TestFormatGlossary(t *testing.T) {
+	line := FormatGlossary([]GlossaryEntry{{Term: "CRDT", Definition: "競合なく複製できるデータ型"}, {Term: "eBPF", Definition: "カーネル内の実行基盤"}})
+	if line != "CRDT（競合なく複製できるデータ型） / eBPF（カーネル内の実行基盤）" {
+		t.Errorf("Unexpected glossary line %q", line)
+	}
+}
+
+func TestGeminiRepository_Glossary(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompts = append(prompts, req.Contents[0].Parts[0].Text)
+		fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": %q}]}}]}`, "CRDT による同期の解説。\n"+glossaryMarker+"\n- CRDT: 競合なく複製できるデータ型")
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:    server.URL,
+		model:      "test-model",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		glossary:   true,
+	}
+	summary, annotations, err := repo.callAnnotatedSummary(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	glossary := annotations.Glossary
+	if summary != "CRDT による同期の解説。" || len(glossary) != 1 || glossary[0].Term != "CRDT" {
+		t.Errorf("Expected the glossary split from the summary, got %q %+v", summary, glossary)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], glossaryMarker) {
+		t.Errorf("Expected one call asking for the glossary, got %v", prompts)
+	}
+}
+
+func TestSlackRepository_GlossaryChannels(t *testing.T) {
+	t.Setenv("GLOSSARY_CHANNELS", "#mixed, general")
+	glossary := []GlossaryEntry{{Term: "CRDT", Definition: "競合なく複製できるデータ型"}}
+
+	mixed := NewSlackRepository("xoxb-test", "#mixed", "https://slack.example.com").(*slackRepository)
+	message := mixed.formatNotification(Notification{Title: "Test", URL: "https://example.com", Summary: "CRDT の解説", Glossary: glossary})
+	if !strings.Contains(message, "CRDT の解説\n📖 用語: CRDT（競合なく複製できるデータ型）") {
+		t.Errorf("Expected the glossary line after the summary, got %s", message)
+	}
+	message = mixed.formatOnDemandMessage(Item{Link: "https://example.com"}, SummarizeResponse{Summary: "CRDT の解説", Glossary: glossary}, "#experts")
+	if strings.Contains(message, "📖 用語") {
+		t.Errorf("Unexpected glossary in a channel without it: %s", message)
+	}
+
+	experts := NewSlackRepository("xoxb-test", "#experts", "https://slack.example.com").(*slackRepository)
+	message = experts.formatNotification(Notification{Title: "Test", URL: "https://example.com", Summary: "CRDT の解説", Glossary: glossary})
+	if strings.Contains(message, "📖 用語") {
+		t.Errorf("Unexpected glossary in a channel without it: %s", message)
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Elements that start a new line in the extracted text
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Br: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true,
+	atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true, atom.Nav: true, atom.Ol: true,
+	atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true, atom.Ul: true,
+}
+
+// htmlToText converts HTML into prompt-friendly text: one line per block, list items as
+// "- " / "1. " bullets and tables as Markdown, so benchmarks and comparisons keep their structure.
+func htmlToText(page string) (string, bool) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	writeNodeText(&b, doc, 0)
+	return normalizeTextLines(b.String()), true
+}
+
+func writeNodeText(b *strings.Builder, n *html.Node, depth int) {
+	switch n.Type {
+	case html.TextNode:
+		// Source line breaks are just whitespace, except inside <pre>
+		if inPre(n) {
+			b.WriteString(n.Data)
+		} else {
+			b.WriteString(strings.NewReplacer("\n", " ", "\r", " ").Replace(n.Data))
+		}
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Script, atom.Style, atom.Noscript, atom.Template:
+			return
+		case atom.Table:
+			b.WriteString("\n")
+			writeMarkdownTable(b, n)
+			b.WriteString("\n")
+			return
+		case atom.Ul, atom.Ol:
+			b.WriteString("\n")
+			writeList(b, n, depth)
+			b.WriteString("\n")
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if block {
+		b.WriteString("\n")
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeNodeText(b, child, depth)
+	}
+	if block {
+		b.WriteString("\n")
+	}
+}
+
+// writeList writes each <li> as a bullet, indenting nested lists by two spaces per level
+func writeList(b *strings.Builder, list *html.Node, depth int) {
+	ordered := list.DataAtom == atom.Ol
+	index := 1
+	if start, err := strconv.Atoi(attr(list, "start")); ordered && err == nil {
+		index = start
+	}
+
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		// Nested lists go on their own lines; everything else is the item's text
+		var text strings.Builder
+		var nested []*html.Node
+		for child := item.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.DataAtom == atom.Ul || child.DataAtom == atom.Ol) {
+				nested = append(nested, child)
+				continue
+			}
+			writeNodeText(&text, child, depth+1)
+		}
+
+		b.WriteString("\n" + strings.Repeat("  ", depth) + marker + collapseSpaces(text.String()))
+		for _, child := range nested {
+			writeList(b, child, depth+1)
+		}
+	}
+}
+
+// writeMarkdownTable writes the table's rows as a Markdown table; the first row is the header
+func writeMarkdownTable(b *strings.Builder, table *html.Node) {
+	var rows [][]string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.DataAtom {
+			case atom.Tr:
+				var row []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.DataAtom == atom.Td || cell.DataAtom == atom.Th) {
+						var text strings.Builder
+						writeNodeText(&text, cell, 0)
+						row = append(row, strings.ReplaceAll(collapseSpaces(text.String()), "|", `\|`))
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			case atom.Table:
+				// Nested (layout) tables would break the grid; skip them
+			default:
+				collect(child)
+			}
+		}
+	}
+	collect(table)
+
+	if caption := findFirst(table, selector{{tag: "caption"}}); caption != nil {
+		var text strings.Builder
+		writeNodeText(&text, caption, 0)
+		b.WriteString(collapseSpaces(text.String()) + "\n")
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+}
+
+// normalizeTextLines collapses whitespace within lines and drops empty lines
+func normalizeTextLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		line = collapseSpaces(line)
+		if line == "" {
+			continue
+		}
+		// Keep the indentation of nested list items
+		if strings.HasPrefix(line, "- ") || isOrderedMarker(line) {
+			line = strings.Repeat(" ", indent) + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inPre(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.DataAtom == atom.Pre {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeEntities decodes HTML entities (&amp;, &quot;, &#39;, &#x2014;, ...) in feed titles and
+// descriptions and in text extracted without a parser. Feeds that escape their text twice
+// (&amp;#39;) are decoded until nothing changes.
+func decodeEntities(s string) string {
+	for range 3 {
+		if !strings.Contains(s, "&") {
+			break
+		}
+		decoded := html.UnescapeString(s)
+		if decoded == s {
+			break
+		}
+		s = decoded
+	}
+	return s
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func isOrderedMarker(line string) bool {
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	return digits > 0 && strings.HasPrefix(line[digits:], ". ")
+}
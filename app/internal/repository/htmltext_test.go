@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+)
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "paragraphs and inline elements",
+			html: `<html><head><title>T</title><style>p{}</style></head><body><p>Hello <b>bold</b>
+world</p><script>alert(1)</script><p>Second</p></body></html>`,
+			want: "T\nHello bold world\nSecond",
+		},
+		{
+			name: "preformatted",
+			html: `<pre>line 1
+line 2</pre>`,
+			want: "line 1\nline 2",
+		},
+		{
+			name: "lists",
+			html: `<p>Features:</p><ul><li>Fast</li><li>Small<ul><li>under 1MB</li></ul></li></ul><ol start="3"><li>three</li><li>four</li></ol>`,
+			want: "Features:\n- Fast\n- Small\n  - under 1MB\n3. three\n4. four",
+		},
+		{
+			name: "table",
+			html: `<table><caption>Benchmarks</caption><thead><tr><th>Lib</th><th>ops/s</th></tr></thead>
+<tbody><tr><td>foo</td><td>1,000</td></tr><tr><td>a|b</td></tr></tbody></table><p>After</p>`,
+			want: "Benchmarks\n| Lib | ops/s |\n| --- | --- |\n| foo | 1,000 |\n| a\\|b | |\nAfter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := htmlToText(tt.html)
+			if !ok {
+				t.Fatal("Expected HTML to parse")
+			}
+			if got != tt.want {
+				t.Errorf("htmlToText() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEntities(t *testing.T) {
+	tests := map[string]string{
+		"Tom &amp; Jerry":                   "Tom & Jerry",
+		"&quot;Go&quot; 1.23 &#8212; notes": "\"Go\" 1.23 — notes",
+		"It&#x27;s &lt;fast&gt;":            "It's <fast>",
+		"Rock &amp;amp; Roll&amp;#39;s":     "Rock & Roll's", // Escaped twice by the feed
+		"no entities, & alone":              "no entities, & alone",
+	}
+	for input, want := range tests {
+		if got := decodeEntities(input); got != want {
+			t.Errorf("decodeEntities(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package httperr classifies failed outbound HTTP calls so retry, dead-letter and
+// circuit breaker decisions can use errors.Is instead of matching error messages.
+package httperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error kinds, matched with errors.Is
+var (
+	// ErrTemporary marks failures that may succeed when retried (network errors, 408, 5xx)
+	ErrTemporary = errors.New("temporary failure")
+	// ErrPermanent marks failures that will fail the same way again (other 4xx, invalid responses)
+	ErrPermanent = errors.New("permanent failure")
+	// ErrRateLimited marks 429 responses; RetryAfter tells when to try again
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Error is a classified HTTP client failure
+type Error struct {
+	Kind       error         // ErrTemporary, ErrPermanent or ErrRateLimited
+	StatusCode int           // 0 when no response was received
+	RetryAfter time.Duration // Server-requested delay for ErrRateLimited (0 when not given)
+	Err        error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the kind and the underlying error to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// FromResponse classifies an unexpected response status: 429 is rate limited (honoring Retry-After),
+// 408 and 5xx are temporary, and everything else is permanent. err describes the failure.
+func FromResponse(resp *http.Response, err error) *Error {
+	e := &Error{StatusCode: resp.StatusCode, Err: err}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+		e.RetryAfter = ParseRetryAfter(resp.Header, time.Now())
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		e.Kind = ErrTemporary
+	default:
+		e.Kind = ErrPermanent
+	}
+	return e
+}
+
+// Status wraps a failure for statusCode using the same classification as FromResponse
+func Status(statusCode int, format string, args ...any) *Error {
+	return FromResponse(&http.Response{StatusCode: statusCode, Header: http.Header{}}, fmt.Errorf(format, args...))
+}
+
+// Transport classifies a request that got no response as temporary: network errors, timeouts and
+// canceled contexts (shutdown, deadlines) say nothing about the URL, so the article is retried
+// later instead of being dead-lettered. The cause stays visible to errors.Is.
+func Transport(err error) *Error {
+	return &Error{Kind: ErrTemporary, Err: err}
+}
+
+// Permanent marks err as not worth retrying (e.g. a response that cannot be decoded)
+func Permanent(err error) *Error {
+	return &Error{Kind: ErrPermanent, Err: err}
+}
+
+// Retryable reports whether err is temporary or rate limited
+func Retryable(err error) bool {
+	return errors.Is(err, ErrTemporary) || errors.Is(err, ErrRateLimited)
+}
+
+// IsPermanent reports whether err is classified as permanent
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrPermanent)
+}
+
+// RetryAfter returns the server-requested delay of a rate-limited error
+func RetryAfter(err error) (time.Duration, bool) {
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != ErrRateLimited {
+		return 0, false
+	}
+	return e.RetryAfter, true
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an HTTP date (0 when absent or invalid)
+func ParseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
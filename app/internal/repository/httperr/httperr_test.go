@@ -0,0 +1,79 @@
+package httperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFromResponse(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusRequestTimeout, ErrTemporary},
+		{http.StatusInternalServerError, ErrTemporary},
+		{http.StatusServiceUnavailable, ErrTemporary},
+		{http.StatusBadRequest, ErrPermanent},
+		{http.StatusNotFound, ErrPermanent},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		err := fmt.Errorf("summarizing article: %w", FromResponse(resp, fmt.Errorf("unexpected status code: %d", tt.status)))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+		if Retryable(err) == (tt.want == ErrPermanent) {
+			t.Errorf("status %d: unexpected Retryable=%t", tt.status, Retryable(err))
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	err := fmt.Errorf("sending: %w", FromResponse(resp, errors.New("rate limited")))
+
+	if d, ok := RetryAfter(err); !ok || d != 7*time.Second {
+		t.Errorf("Expected 7s Retry-After, got %s %t", d, ok)
+	}
+	if _, ok := RetryAfter(Status(http.StatusServiceUnavailable, "down")); ok {
+		t.Error("Expected no Retry-After for a temporary failure")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"3":                             3 * time.Second,
+		"":                              0,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 00:00:30 GMT": 30 * time.Second,
+		"Sun, 31 Dec 2023 23:59:00 GMT": 0,
+	}
+
+	for value, want := range tests {
+		header := http.Header{}
+		header.Set("Retry-After", value)
+		if got := ParseRetryAfter(header, now); got != want {
+			t.Errorf("ParseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	if err := Transport(errors.New("connection reset")); !Retryable(err) {
+		t.Errorf("Expected network errors to be retryable, got %v", err)
+	}
+	for _, cause := range []error{context.Canceled, context.DeadlineExceeded} {
+		if err := Transport(fmt.Errorf("sending request: %w", cause)); !Retryable(err) || IsPermanent(err) || !errors.Is(err, cause) {
+			t.Errorf("Expected %v to be retryable and keep its cause, got %v", cause, err)
+		}
+	}
+}
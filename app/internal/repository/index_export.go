@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Processed index export formats
+const (
+	IndexFormatJSON = "json"
+	IndexFormatCSV  = "csv"
+)
+
+// indexCSVHeader names the CSV columns; provenance is JSON-encoded
+var indexCSVHeader = []string{"url", "title", "source", "pub_date", "processed_date", "prompt_variant", "provenance"}
+
+// IndexFormatFor picks the export format of a file by its extension (.csv is CSV, anything else JSON)
+func IndexFormatFor(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return IndexFormatCSV
+	}
+	return IndexFormatJSON
+}
+
+// ValidateIndexFormat checks an export format
+func ValidateIndexFormat(format string) error {
+	if format != IndexFormatJSON && format != IndexFormatCSV {
+		return fmt.Errorf("unsupported format %q (expected %s or %s)", format, IndexFormatJSON, IndexFormatCSV)
+	}
+	return nil
+}
+
+// WriteIndexEntries writes entries as a JSON array, or as CSV with a header row
+func WriteIndexEntries(w io.Writer, entries []*IndexEntry, format string) error {
+	if format == IndexFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []*IndexEntry{}
+		}
+		return encoder.Encode(entries)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(indexCSVHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var provenance string
+		if entry.Provenance != nil {
+			data, err := json.Marshal(entry.Provenance)
+			if err != nil {
+				return fmt.Errorf("marshaling provenance of %s: %w", entry.URL, err)
+			}
+			provenance = string(data)
+		}
+		if err := writer.Write([]string{
+			entry.URL,
+			entry.Title,
+			entry.Source,
+			formatIndexTime(entry.PubDate),
+			formatIndexTime(entry.ProcessedDate),
+			entry.PromptVariant,
+			provenance,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadIndexEntries reads entries written by WriteIndexEntries. JSON may also be an index object
+// as stored (index-v2.json or a shard), keyed by URL.
+func ReadIndexEntries(r io.Reader, format string) ([]*IndexEntry, error) {
+	if format == IndexFormatJSON {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			var index map[string]*IndexEntry
+			if err := json.Unmarshal(trimmed, &index); err != nil {
+				return nil, fmt.Errorf("parsing index object: %w", err)
+			}
+			return indexEntries(index), nil
+		}
+		var entries []*IndexEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parsing exported entries: %w", err)
+		}
+		return entries, nil
+	}
+
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.FieldsPerRecord = len(indexCSVHeader)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing CSV: %w", err)
+	}
+	if len(records) == 0 || records[0][0] != indexCSVHeader[0] {
+		return nil, fmt.Errorf("parsing CSV: missing header row %s", strings.Join(indexCSVHeader, ","))
+	}
+
+	entries := make([]*IndexEntry, 0, len(records)-1)
+	for i, record := range records[1:] {
+		line := i + 2
+		entry := &IndexEntry{URL: record[0], Title: record[1], Source: record[2], PromptVariant: record[5]}
+		if entry.PubDate, err = parseIndexTime(record[3]); err != nil {
+			return nil, fmt.Errorf("line %d: pub_date: %w", line, err)
+		}
+		if entry.ProcessedDate, err = parseIndexTime(record[4]); err != nil {
+			return nil, fmt.Errorf("line %d: processed_date: %w", line, err)
+		}
+		if record[6] != "" {
+			entry.Provenance = &Provenance{}
+			if err := json.Unmarshal([]byte(record[6]), entry.Provenance); err != nil {
+				return nil, fmt.Errorf("line %d: provenance: %w", line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// formatIndexTime writes RFC 3339 (empty for the zero time)
+func formatIndexTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseIndexTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, value)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIndexFormatFor(t *testing.T) {
+	tests := map[string]string{
+		"index.csv":  IndexFormatCSV,
+		"INDEX.CSV":  IndexFormatCSV,
+		"index.json": IndexFormatJSON,
+		"-":          IndexFormatJSON,
+	}
+	for path, expected := range tests {
+		if format := IndexFormatFor(path); format != expected {
+			t.Errorf("IndexFormatFor(%q) = %s, expected %s", path, format, expected)
+		}
+	}
+	if err := ValidateIndexFormat("xml"); err == nil {
+		t.Error("Expected an unsupported format to be rejected")
+	}
+}
+
+func TestIndexEntries_RoundTrip(t *testing.T) {
+	processed := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	entries := []*IndexEntry{
+		{URL: "https://example.com/a", Title: "A, \"quoted\"", Source: "hatena", ProcessedDate: processed},
+		{
+			URL:           "https://example.com/b",
+			Title:         "B",
+			Source:        "reddit",
+			PubDate:       processed.Add(-time.Hour),
+			ProcessedDate: processed.Add(time.Minute),
+			PromptVariant: "b",
+			Provenance:    &Provenance{Model: "gemini-2.5-flash"},
+		},
+	}
+
+	for _, format := range []string{IndexFormatJSON, IndexFormatCSV} {
+		var buf bytes.Buffer
+		if err := WriteIndexEntries(&buf, entries, format); err != nil {
+			t.Fatalf("%s: WriteIndexEntries failed: %v", format, err)
+		}
+		read, err := ReadIndexEntries(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: ReadIndexEntries failed: %v", format, err)
+		}
+		if len(read) != 2 {
+			t.Fatalf("%s: expected 2 entries, got %d", format, len(read))
+		}
+		if read[0].Title != entries[0].Title || !read[0].PubDate.IsZero() || !read[0].ProcessedDate.Equal(processed) {
+			t.Errorf("%s: unexpected first entry %+v", format, read[0])
+		}
+		if read[1].PromptVariant != "b" || !read[1].PubDate.Equal(entries[1].PubDate) || read[1].Provenance == nil || read[1].Provenance.Model != "gemini-2.5-flash" {
+			t.Errorf("%s: unexpected second entry %+v", format, read[1])
+		}
+	}
+}
+
+func TestReadIndexEntries_IndexObject(t *testing.T) {
+	object := `{"https://example.com/b": {"url": "https://example.com/b", "processed_date": "2024-06-04T10:00:00Z"},
+		"https://example.com/a": {"url": "https://example.com/a", "processed_date": "2024-06-04T09:00:00Z"}}`
+	entries, err := ReadIndexEntries(strings.NewReader(object), IndexFormatJSON)
+	if err != nil {
+		t.Fatalf("ReadIndexEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].URL != "https://example.com/a" {
+		t.Errorf("Expected the index object's entries oldest first, got %v", entries)
+	}
+}
+
+func TestReadIndexEntries_CSVWithoutHeader(t *testing.T) {
+	row := "https://example.com/a,A,hatena,,2024-06-04T09:00:00Z,,\n"
+	if _, err := ReadIndexEntries(strings.NewReader(row), IndexFormatCSV); err == nil {
+		t.Error("Expected CSV without the header row to be rejected")
+	}
+}
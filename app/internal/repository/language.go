@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Summary languages (SUMMARY_LANGUAGE)
+const (
+	SummaryLanguageJapanese = "ja"
+	SummaryLanguageEnglish  = "en"
+	// SummaryLanguageAuto summarizes each article in its own language with the matching prompt templates
+	SummaryLanguageAuto = "auto"
+)
+
+// japaneseShareThreshold is the share of Japanese script among letters above which text counts as
+// Japanese; Japanese summaries keep plenty of English product names and terms
+const japaneseShareThreshold = 0.15
+
+// languageNoisePattern matches URLs and code spans, which are Latin in any language
+var languageNoisePattern = regexp.MustCompile("https?://\\S+|`[^`]*`")
+
+// languageInstructions are appended to a prompt when the model answered in the wrong language
+var languageInstructions = map[string]string{
+	SummaryLanguageJapanese: "\n\n**出力言語:** 英語ではなく、必ず日本語で出力してください（固有名詞・コード・URLはそのままで構いません）。",
+	SummaryLanguageEnglish:  "\n\n**Output language:** Write the whole answer in English, even though these instructions are in Japanese.",
+}
+
+// ValidateSummaryLanguage checks SUMMARY_LANGUAGE
+func ValidateSummaryLanguage(language string) error {
+	if _, ok := languageInstructions[language]; !ok && language != SummaryLanguageAuto {
+		return fmt.Errorf("unsupported summary language %q (expected %s, %s or %s)", language, SummaryLanguageJapanese, SummaryLanguageEnglish, SummaryLanguageAuto)
+	}
+	return nil
+}
+
+// ValidateTranslationLanguage checks a summary translation target (SUMMARY_TRANSLATION_<FEED>, on-demand translate)
+func ValidateTranslationLanguage(language string) error {
+	if _, ok := languageInstructions[language]; !ok {
+		return fmt.Errorf("unsupported translation language %q (expected %s or %s)", language, SummaryLanguageJapanese, SummaryLanguageEnglish)
+	}
+	return nil
+}
+
+type outputLanguageKey struct{}
+
+// withOutputLanguage sets the language of the summaries asked for under ctx (SUMMARY_LANGUAGE=auto)
+func withOutputLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, outputLanguageKey{}, language)
+}
+
+// outputLanguage is the language a summary must be written in: the article's language set by
+// withOutputLanguage with SUMMARY_LANGUAGE=auto (Japanese when unset), else SUMMARY_LANGUAGE
+func (g *geminiRepository) outputLanguage(ctx context.Context) string {
+	if g.summaryLanguage != SummaryLanguageAuto {
+		return g.summaryLanguage
+	}
+	if language, ok := ctx.Value(outputLanguageKey{}).(string); ok {
+		return language
+	}
+	return SummaryLanguageJapanese
+}
+
+// promptLanguage is the language of the prompt templates for text: its own language (English or
+// Japanese) with SUMMARY_LANGUAGE=auto, else Japanese, whose templates ask for SUMMARY_LANGUAGE
+func (g *geminiRepository) promptLanguage(text string) string {
+	if g.summaryLanguage == SummaryLanguageAuto && detectLanguage(text) == SummaryLanguageEnglish {
+		return SummaryLanguageEnglish
+	}
+	return SummaryLanguageJapanese
+}
+
+// withPromptLanguage returns ctx carrying text's prompt language as the output language, and that language
+func (g *geminiRepository) withPromptLanguage(ctx context.Context, text string) (context.Context, string) {
+	language := g.promptLanguage(text)
+	if g.summaryLanguage == SummaryLanguageAuto {
+		ctx = withOutputLanguage(ctx, language)
+	}
+	return ctx, language
+}
+
+// languagePromptName marks a prompt name (e.g. rss:default@v1) as using the English templates
+// (rss:default:en@v1); Japanese template names are unchanged
+func languagePromptName(name, language string) string {
+	if language != SummaryLanguageEnglish {
+		return name
+	}
+	template, version, _ := strings.Cut(name, "@")
+	return template + ":en@" + version
+}
+
+// detectLanguage tells Japanese from English text by its share of kana and kanji; it returns ""
+// when the text has no letters to judge by
+func detectLanguage(text string) string {
+	var japanese, latin int
+	for _, r := range languageNoisePattern.ReplaceAllString(text, "") {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han):
+			japanese++
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latin++
+		}
+	}
+	if japanese+latin == 0 {
+		return ""
+	}
+	if float64(japanese)/float64(japanese+latin) >= japaneseShareThreshold {
+		return SummaryLanguageJapanese
+	}
+	return SummaryLanguageEnglish
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"📝 **概要:** Go 1.23 では range over func が導入され、iterator パターンを標準化した。", SummaryLanguageJapanese},
+		{"📝 **Overview:** Go 1.23 introduces range-over-func iterators to the standard library.", SummaryLanguageEnglish},
+		{"詳細は `go doc iter.Seq` と https://go.dev/blog/range-functions を参照", SummaryLanguageJapanese},
+		{"🎉 123", ""},
+	}
+	for _, tt := range tests {
+		if got := detectLanguage(tt.text); got != tt.expected {
+			t.Errorf("detectLanguage(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestGeminiRepository_SummaryLanguageRetry(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt := req.Contents[0].Parts[0].Text
+		prompts = append(prompts, prompt)
+
+		answer := "The article explains how the new iterator functions work."
+		if strings.Contains(prompt, "**出力言語:**") {
+			answer = "新しいイテレータ関数の仕組みを解説している。"
+		}
+		fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": %q}]}}]}`, answer)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:         server.URL,
+		model:           "test-model",
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		summaryLanguage: SummaryLanguageJapanese,
+	}
+
+	summary, err := repo.SummarizeText(context.Background(), "Go 1.23 adds range-over-func iterators.")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary != "新しいイテレータ関数の仕組みを解説している。" {
+		t.Errorf("Expected the Japanese retry, got %q", summary)
+	}
+	if len(prompts) != 2 || strings.Contains(prompts[0], "**出力言語:**") {
+		t.Errorf("Expected one plain call and one retry with the language instruction, got %d calls", len(prompts))
+	}
+
+	// A summary already in the right language is kept without a retry
+	prompts = nil
+	repo.summaryLanguage = SummaryLanguageEnglish
+	if summary, err := repo.SummarizeText(context.Background(), "text"); err != nil || !strings.HasPrefix(summary, "The article") {
+		t.Errorf("Expected the English summary, got %q (%v)", summary, err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "**Output language:**") {
+		t.Errorf("Expected a single call asking for English, got %d calls", len(prompts))
+	}
+}
+
+func TestGeminiRepository_AutoSummaryLanguage(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "generateContent") {
+			fmt.Fprint(w, `<html><body><article><p>Go 1.23 adds range-over-func iterators, letting any function that yields values drive a for loop, and the iter package defines the standard Seq and Seq2 types for them.</p></article></body></html>`)
+			return
+		}
+		var req geminiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt := req.Contents[0].Parts[0].Text
+		prompts = append(prompts, prompt)
+
+		// Each prompt is answered in its own language
+		answer := "新しいイテレータ関数の仕組みを解説している。"
+		if strings.HasPrefix(prompt, "Summarize") {
+			answer = "The article explains how the new iterator functions work."
+		}
+		fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": %q}]}}]}`, answer)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:         server.URL,
+		model:           "test-model",
+		maxPages:        1,
+		httpClient:      &http.Client{Timeout: 5 * time.Second},
+		summaryLanguage: SummaryLanguageAuto,
+	}
+	ctx := context.Background()
+
+	// English articles get the English template, with no language instruction or retry
+	summary, err := repo.SummarizeURL(ctx, server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Summary != "The article explains how the new iterator functions work." || summary.Provenance.Prompt != "rss:default:en@v1" {
+		t.Errorf("Expected an English summary from the English template, got %q (%s)", summary.Summary, summary.Provenance.Prompt)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "🎯 **Audience:**") || strings.Contains(prompts[0], "**Output language:**") {
+		t.Errorf("Expected a single English prompt, got %q", prompts)
+	}
+
+	// Japanese text keeps the Japanese template
+	prompts = nil
+	text, err := repo.SummarizeText(ctx, "Go 1.23 では range over func が導入され、イテレータを標準化した。")
+	if err != nil || text != "新しいイテレータ関数の仕組みを解説している。" {
+		t.Errorf("Expected the Japanese summary, got %q (%v)", text, err)
+	}
+	if len(prompts) != 1 || !strings.HasPrefix(prompts[0], "以下のテキストを") {
+		t.Errorf("Expected a single Japanese prompt, got %q", prompts)
+	}
+
+	if err := ValidateSummaryLanguage(SummaryLanguageAuto); err != nil {
+		t.Errorf("Expected auto to be valid, got %v", err)
+	}
+	if !PromptOutdated(&Provenance{Prompt: "rss:default:en@v0"}) || PromptOutdated(&Provenance{Prompt: "rss:default:en@" + rssPromptVersion}) {
+		t.Error("Expected English prompt names to be versioned like the Japanese ones")
+	}
+}
+
+func TestGeminiRepository_TranslateSummary(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompts = append(prompts, req.Contents[0].Parts[0].Text)
+		fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": %q}]}}]}`, "📝 **Overview:** The article explains the new iterators.\n")
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:    server.URL,
+		model:      "test-model",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	ctx := context.Background()
+
+	translation, err := repo.TranslateSummary(ctx, "📝 **概要:** 新しいイテレータを解説している。", SummaryLanguageEnglish)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if translation != "📝 **Overview:** The article explains the new iterators." {
+		t.Errorf("Unexpected translation %q", translation)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "英語に翻訳") || !strings.Contains(prompts[0], "新しいイテレータ") {
+		t.Errorf("Expected a single translation prompt, got %q", prompts)
+	}
+
+	// A summary already in the target language is returned without a call
+	prompts = nil
+	summary := "📝 **Overview:** The article explains the new iterators."
+	if translation, err := repo.TranslateSummary(ctx, summary, SummaryLanguageEnglish); err != nil || translation != summary {
+		t.Errorf("Expected the summary unchanged, got %q (%v)", translation, err)
+	}
+	if len(prompts) != 0 {
+		t.Errorf("Expected no Gemini call, got %d", len(prompts))
+	}
+}
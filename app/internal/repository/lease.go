@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultLeasePrefix = "leader/"
+
+// LeaseRepository hands out time slots of the leader lease; each slot has exactly one holder
+type LeaseRepository interface {
+	// Claim takes the slot starting at start for holder unless another replica already holds it,
+	// and returns the slot's holder
+	Claim(ctx context.Context, start time.Time, holder string) (string, error)
+	Close() error
+}
+
+// leaseRepository writes one object per slot (<prefix><day>/<unix start>) with the exclusive
+// Create of the storage, so two replicas can never both hold a slot. Objects are a few bytes
+// and are never rewritten; expire <prefix> with a bucket lifecycle rule to prune them.
+type leaseRepository struct {
+	storage Storage
+	prefix  string
+}
+
+// NewLeaseRepository creates the leader lease stored next to the processed index
+func NewLeaseRepository() (LeaseRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := defaultLeasePrefix
+	if env := os.Getenv("LEADER_LEASE_PREFIX"); env != "" {
+		prefix = env
+	}
+
+	return &leaseRepository{
+		storage: store,
+		prefix:  prefix,
+	}, nil
+}
+
+func (l *leaseRepository) Claim(ctx context.Context, start time.Time, holder string) (string, error) {
+	start = start.UTC()
+	name := l.prefix + start.Format("2006-01-02") + "/" + strconv.FormatInt(start.Unix(), 10)
+
+	err := l.storage.Create(ctx, name, []byte(holder), "text/plain")
+	if err == nil {
+		return holder, nil
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("claiming lease %s: %w", name, err)
+	}
+
+	current, err := l.storage.Read(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("reading lease %s: %w", name, err)
+	}
+	return string(current), nil
+}
+
+func (l *leaseRepository) Close() error {
+	return l.storage.Close()
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeaseRepository_Claim(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := &leaseRepository{storage: store, prefix: defaultLeasePrefix}
+	ctx := context.Background()
+	slot := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	holder, err := repo.Claim(ctx, slot, "replica-a")
+	if err != nil || holder != "replica-a" {
+		t.Fatalf("Expected replica-a to take a free slot, got %q (%v)", holder, err)
+	}
+	holder, err = repo.Claim(ctx, slot, "replica-b")
+	if err != nil || holder != "replica-a" {
+		t.Errorf("Expected the slot to stay with replica-a, got %q (%v)", holder, err)
+	}
+	holder, err = repo.Claim(ctx, slot.Add(time.Minute), "replica-b")
+	if err != nil || holder != "replica-b" {
+		t.Errorf("Expected replica-b to take the next slot, got %q (%v)", holder, err)
+	}
+
+	names, err := store.List(ctx, "leader/2024-01-01/")
+	if err != nil || len(names) != 2 {
+		t.Errorf("Expected one object per slot, got %v (%v)", names, err)
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const (
+	// maxPromptTextBytes is the most article text a single summary prompt carries
+	maxPromptTextBytes = 10000
+	// defaultMapReduceThreshold is the text length above which map-reduce summarization kicks in;
+	// it matches maxPromptTextBytes so that no article is cut off at the prompt limit by default
+	defaultMapReduceThreshold = maxPromptTextBytes
+	// mapReduceChunkSize is the target size of each chunk summarized in the map phase
+	mapReduceChunkSize = 8000
+	// mapReduceMaxChunks bounds the number of Gemini calls in the map phase
+	mapReduceMaxChunks = 8
+)
+
+// needsMapReduce reports whether the text is long enough to be summarized chunk by chunk
+func (g *geminiRepository) needsMapReduce(text string) bool {
+	return g.mapReduceThreshold > 0 && len(text) > g.mapReduceThreshold
+}
+
+// preparePromptText returns the article text a summary prompt carries and whether it was map-reduced.
+// Long articles are summarized chunk by chunk first and synthesized from the partial summaries; the
+// 10KB prompt cap only cuts text when MAP_REDUCE_THRESHOLD is disabled or raised above it.
+func (g *geminiRepository) preparePromptText(ctx context.Context, text string) (string, bool, error) {
+	if !g.needsMapReduce(text) {
+		if len(text) > maxPromptTextBytes {
+			logger := log.New(funcframework.LogWriter(ctx), "", 0)
+			logger.Printf("Text truncated to prompt limit text_length=%d max_bytes=%d", len(text), maxPromptTextBytes)
+		}
+		return truncateUTF8(text, maxPromptTextBytes), false, nil
+	}
+
+	notes, err := g.mapChunks(ctx, text)
+	if err != nil {
+		return "", true, fmt.Errorf("map-reduce summarization: %w", err)
+	}
+	return notes, true, nil
+}
+
+// mapChunks summarizes each chunk of a long text (map phase) and returns the combined
+// partial summaries, which are then passed to the regular prompt as the synthesis (reduce) input.
+func (g *geminiRepository) mapChunks(ctx context.Context, text string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	chunks := splitIntoChunks(text, mapReduceChunkSize)
+	header := "（長文記事のため、各パートの要点を順に示します）"
+	if len(chunks) > mapReduceMaxChunks {
+		dropped := 0
+		for _, chunk := range chunks[mapReduceMaxChunks:] {
+			dropped += len(chunk)
+		}
+		logger.Printf("Map-reduce chunk limit reached, dropping the rest of the article chunks=%d max_chunks=%d dropped_bytes=%d",
+			len(chunks), mapReduceMaxChunks, dropped)
+		chunks = chunks[:mapReduceMaxChunks]
+		// The synthesis must not present the summary as covering the whole article
+		header = "（長文記事のため、各パートの要点を順に示します。記事の後半は長さの上限により省略されています）"
+	}
+
+	logger.Printf("Map-reduce summarization started text_length=%d chunks=%d", len(text), len(chunks))
+
+	var notes []string
+	for i, chunk := range chunks {
+		partial, err := g.callGeminiAPI(ctx, buildChunkPrompt(chunk, i+1, len(chunks)))
+		if err != nil {
+			logger.Printf("Error summarizing chunk %d/%d: %v", i+1, len(chunks), err)
+			return "", fmt.Errorf("summarizing chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+		notes = append(notes, fmt.Sprintf("【パート%d/%d】\n%s", i+1, len(chunks), strings.TrimSpace(partial)))
+	}
+
+	logger.Printf("Map-reduce map phase completed chunks=%d duration_ms=%d", len(chunks), time.Since(start).Milliseconds())
+
+	return header + "\n\n" + strings.Join(notes, "\n\n"), nil
+}
+
+// buildChunkPrompt creates the map-phase prompt for one chunk
+func buildChunkPrompt(chunk string, part, total int) string {
+	return fmt.Sprintf(`以下は長い記事の一部（パート%d/%d）です。このパートに記載されている重要な事実・主張・数値を、箇条書きで500文字以内に抽出してください。
+
+**重要な制約:**
+- 推測や創作は一切せず、実際に記載されている内容のみを抽出してください
+- 前後のパートの内容を補完しないでください
+
+テキスト内容:
+%s`, part, total, chunk)
+}
+
+// truncateUTF8 cuts text to at most maxBytes bytes without breaking a UTF-8 character
+func truncateUTF8(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
+// splitIntoChunks splits text into chunks of at most size bytes without breaking UTF-8 characters,
+// preferring to cut at sentence ends or whitespace near the boundary.
+func splitIntoChunks(text string, size int) []string {
+	var chunks []string
+	for len(text) > size {
+		cut := size
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+
+		// Prefer a natural boundary in the last 20% of the chunk
+		window := text[size*4/5 : cut]
+		if i := strings.LastIndexAny(window, "。.!?！？\n"); i >= 0 {
+			_, width := utf8.DecodeRuneInString(window[i:])
+			cut = size*4/5 + i + width
+		} else if i := strings.LastIndex(window, " "); i >= 0 {
+			cut = size*4/5 + i + 1
+		}
+
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = text[cut:]
+	}
+	if rest := strings.TrimSpace(text); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
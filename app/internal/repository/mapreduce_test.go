@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSplitIntoChunks(t *testing.T) {
+	text := strings.Repeat("これはテストの文章です。", 2000)
+
+	chunks := splitIntoChunks(text, 8000)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+
+	total := 0
+	for i, chunk := range chunks {
+		if len(chunk) > 8000 {
+			t.Errorf("Chunk %d exceeds size: %d", i, len(chunk))
+		}
+		if !utf8.ValidString(chunk) {
+			t.Errorf("Chunk %d is not valid UTF-8", i)
+		}
+		if i < len(chunks)-1 && !strings.HasSuffix(chunk, "。") {
+			t.Errorf("Chunk %d should end at a sentence boundary", i)
+		}
+		total += len(chunk)
+	}
+
+	if total != len(text) {
+		t.Errorf("Expected chunks to cover the whole text (%d bytes), got %d", len(text), total)
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	text := strings.Repeat("日本語", 2000)
+
+	got := truncateUTF8(text, maxPromptTextBytes)
+	if len(got) > maxPromptTextBytes || !utf8.ValidString(got) {
+		t.Errorf("Expected valid UTF-8 within %d bytes, got %d bytes (valid=%v)", maxPromptTextBytes, len(got), utf8.ValidString(got))
+	}
+	if !strings.HasPrefix(text, got) || maxPromptTextBytes-len(got) >= utf8.UTFMax {
+		t.Errorf("Expected a cut at the last character boundary, got %d bytes", len(got))
+	}
+	if short := "短い本文"; truncateUTF8(short, maxPromptTextBytes) != short {
+		t.Error("Text within the limit should be left as is")
+	}
+}
+
+func TestGeminiRepository_DefaultMapReduceThreshold(t *testing.T) {
+	repo := &geminiRepository{mapReduceThreshold: defaultMapReduceThreshold}
+
+	// Articles too long for one prompt are summarized in parts instead of being cut at the prompt limit
+	if !repo.needsMapReduce(strings.Repeat("word ", 3000)) {
+		t.Error("A 15KB article should use map-reduce by default")
+	}
+	if repo.needsMapReduce(strings.Repeat("word ", 2000)) {
+		t.Error("An article within the prompt limit should not use map-reduce")
+	}
+}
+
+func TestGeminiRepository_MapReduce(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"candidates": [{"content": {"parts": [{"text": "partial %d"}]}}]}`, n)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:            server.URL,
+		model:              "test-model",
+		httpClient:         &http.Client{Timeout: 5 * time.Second},
+		mapReduceThreshold: 10000,
+	}
+
+	short := strings.Repeat("a ", 1000)
+	if repo.needsMapReduce(short) {
+		t.Error("Short text should not use map-reduce")
+	}
+
+	long := strings.Repeat("word ", 5000)
+	if !repo.needsMapReduce(long) {
+		t.Fatal("Long text should use map-reduce")
+	}
+
+	notes, err := repo.mapChunks(context.Background(), long)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedChunks := len(splitIntoChunks(long, mapReduceChunkSize))
+	if int(calls) != expectedChunks {
+		t.Errorf("Expected %d map calls, got %d", expectedChunks, calls)
+	}
+	if !strings.Contains(notes, fmt.Sprintf("【パート1/%d】", expectedChunks)) || !strings.Contains(notes, "partial 1") {
+		t.Errorf("Unexpected notes: %s", notes)
+	}
+
+	repo.mapReduceThreshold = 0
+	if repo.needsMapReduce(long) {
+		t.Error("Threshold 0 should disable map-reduce")
+	}
+}
+
+func TestGeminiRepository_PreparePromptText(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates": [{"content": {"parts": [{"text": "partial"}]}}]}`)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:    server.URL,
+		model:      "test-model",
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+
+	// Map-reduce disabled: the text is cut at the prompt limit
+	long := strings.Repeat("word ", 20000)
+	text, mapReduced, err := repo.preparePromptText(context.Background(), long)
+	if err != nil || mapReduced || len(text) > maxPromptTextBytes {
+		t.Errorf("Expected the text cut to %d bytes without map-reduce, got %d bytes (mapReduced=%v, err=%v)", maxPromptTextBytes, len(text), mapReduced, err)
+	}
+
+	// Beyond the chunk limit the synthesis input says the rest of the article was left out
+	repo.mapReduceThreshold = defaultMapReduceThreshold
+	text, mapReduced, err = repo.preparePromptText(context.Background(), long)
+	if err != nil || !mapReduced {
+		t.Fatalf("Expected map-reduce, got mapReduced=%v err=%v", mapReduced, err)
+	}
+	if int(calls) != mapReduceMaxChunks {
+		t.Errorf("Expected %d map calls, got %d", mapReduceMaxChunks, calls)
+	}
+	if !strings.Contains(text, "省略されています") {
+		t.Errorf("Expected the omission to be noted, got: %s", text)
+	}
+}
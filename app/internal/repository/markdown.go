@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// markdownSlugLimit keeps file names short enough for every filesystem
+const markdownSlugLimit = 80
+
+// markdownHashLength is the number of hex digits of the URL hash in note names
+const markdownHashLength = 8
+
+const markdownContentType = "text/markdown; charset=utf-8"
+
+type markdownRepository struct {
+	store Storage
+	now   func() time.Time
+
+	mu    sync.Mutex
+	files map[string]string // article URL -> note name, so comment summaries land in the article's note
+}
+
+// NewMarkdownRepository creates a Notifier writing one Markdown note (YAML front matter + summary)
+// per article, for Obsidian/Logseq vaults. output is a local directory or gs://bucket/prefix.
+func NewMarkdownRepository(output string) (Notifier, error) {
+	store, err := NewStorageAt(output)
+	if err != nil {
+		return nil, err
+	}
+	return newMarkdownRepository(store), nil
+}
+
+func newMarkdownRepository(store Storage) *markdownRepository {
+	return &markdownRepository{
+		store: store,
+		now:   time.Now,
+		files: make(map[string]string),
+	}
+}
+
+// Send writes an article note, or appends a comment summary to the article's note
+func (m *markdownRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if notification.Comment {
+		if name, ok := m.files[notification.URL]; ok {
+			existing, err := m.store.Read(ctx, name)
+			if err != nil {
+				logger.Printf("Error reading Markdown note %s: %v", name, err)
+				return fmt.Errorf("reading note: %w", err)
+			}
+			note := string(existing) + formatMarkdownCommentSection(notification)
+			if err := m.store.Write(ctx, name, []byte(note), markdownContentType); err != nil {
+				logger.Printf("Error writing Markdown note %s: %v", name, err)
+				return fmt.Errorf("writing note: %w", err)
+			}
+			logger.Printf("Markdown comment summary appended title=%s file=%s", notification.Title, name)
+			return nil
+		}
+		// Article note is from another run; write the comments as their own note
+	}
+
+	now := m.now()
+	name := markdownNoteName(now, notification.Title, notification.URL)
+	if err := m.store.Write(ctx, name, []byte(formatMarkdownNote(notification, now)), markdownContentType); err != nil {
+		logger.Printf("Error writing Markdown note %s: %v", name, err)
+		return fmt.Errorf("writing note: %w", err)
+	}
+	if !notification.Comment {
+		m.files[notification.URL] = name
+	}
+
+	logger.Printf("Markdown note written title=%s source=%s file=%s", notification.Title, notification.Source, name)
+	return nil
+}
+
+// SendOnDemandSummary writes an on-demand summary note; targetChannel does not apply
+func (m *markdownRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	title := article.Title
+	if title == "" {
+		title = summary.Title
+	}
+	if title == "" {
+		title = article.Link
+	}
+
+	return m.Send(ctx, Notification{
+		Title:         title,
+		Source:        SourceOnDemand,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+	})
+}
+
+func formatMarkdownNote(notification Notification, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(notification.Title))
+	fmt.Fprintf(&b, "url: %s\n", strconv.Quote(notification.URL))
+	fmt.Fprintf(&b, "source: %s\n", notification.Source)
+	fmt.Fprintf(&b, "date: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "content_chars: %d\n", notification.ContentChars)
+	if notification.PromptVariant != "" {
+		fmt.Fprintf(&b, "prompt_variant: %s\n", notification.PromptVariant)
+	}
+	if p := notification.Provenance; p != nil {
+		fmt.Fprintf(&b, "provider: %s\nmodel: %s\nprompt: %s\nextraction: %s\n", p.Provider, p.Model, p.Prompt, p.Extraction)
+	}
+	fmt.Fprintf(&b, "tags: [%s]\n", notification.Source)
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", notification.Title)
+	fmt.Fprintf(&b, "%s\n", notification.URL)
+	if notification.Comment {
+		b.WriteString(formatMarkdownCommentSection(notification))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n## 要約\n\n%s\n", notification.Summary)
+	return b.String()
+}
+
+func formatMarkdownCommentSection(notification Notification) string {
+	return fmt.Sprintf("\n## コメント要約\n\n%s\n", notification.Summary)
+}
+
+// markdownNoteName builds "2006-01-02-<slug>-<hash>.md" from the title; the short hash of the URL
+// keeps articles sharing a title on the same day from overwriting each other's notes
+func markdownNoteName(now time.Time, title, url string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	slug := strings.Trim(truncateRunes(b.String(), markdownSlugLimit), "-…")
+	if slug == "" {
+		slug = "article"
+	}
+	sum := sha256.Sum256([]byte(url))
+	return fmt.Sprintf("%s-%s-%s.md", now.Format("2006-01-02"), slug, hex.EncodeToString(sum[:])[:markdownHashLength])
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdownRepository_WritesNoteWithComments(t *testing.T) {
+	dir := t.TempDir()
+	notifier, err := NewMarkdownRepository(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := notifier.(*markdownRepository)
+	repo.now = func() time.Time { return time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC) }
+
+	ctx := context.Background()
+	if err := repo.Send(ctx, Notification{
+		Title:   "Go 1.24 Released: What's New?",
+		Source:  "hatena",
+		URL:     "https://example.com/go",
+		Summary: "article summary",
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := repo.Send(ctx, Notification{
+		Title:   "Go 1.24 Released: What's New? - コメント",
+		Source:  "hatena",
+		URL:     "https://example.com/go",
+		Summary: "comment summary",
+		Comment: true,
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one note, got %d", len(entries))
+	}
+	if name := entries[0].Name(); name != "2025-03-04-go-1-24-released-what-s-new-386e1a7c.md" {
+		t.Errorf("Unexpected note name: %s", name)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	note := string(data)
+	for _, want := range []string{
+		"---\ntitle: \"Go 1.24 Released: What's New?\"\n",
+		"url: \"https://example.com/go\"\n",
+		"source: hatena\n",
+		"date: 2025-03-04T05:06:07Z\n",
+		"## 要約\n\narticle summary\n",
+		"## コメント要約\n\ncomment summary\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("Expected note to contain %q, got:\n%s", want, note)
+		}
+	}
+}
+
+func TestMarkdownNoteName(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	url := "https://example.com/a"
+	hash := "2dce0a4c" // First hex digits of the URL's SHA-256
+	tests := map[string]string{
+		"日本語のタイトル！":              "2025-01-02-日本語のタイトル-" + hash + ".md",
+		"  ???  ":                "2025-01-02-article-" + hash + ".md",
+		strings.Repeat("a", 200): "2025-01-02-" + strings.Repeat("a", markdownSlugLimit-1) + "-" + hash + ".md",
+		"Hello, World / Part 2 ": "2025-01-02-hello-world-part-2-" + hash + ".md",
+	}
+	for title, want := range tests {
+		if got := markdownNoteName(now, title, url); got != want {
+			t.Errorf("markdownNoteName(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	// Articles sharing a title on the same day get their own notes
+	if markdownNoteName(now, "Weekly News", "https://example.com/1") == markdownNoteName(now, "Weekly News", "https://example.com/2") {
+		t.Error("Expected different URLs to get different note names")
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryProcessedRepository keeps the processed index in memory, so simulation runs
+// neither need GCS nor touch the production index
+type memoryProcessedRepository struct {
+	mu    sync.Mutex
+	index map[string]*IndexEntry
+}
+
+// NewMemoryProcessedArticleRepository creates an empty in-memory processed index
+func NewMemoryProcessedArticleRepository() ProcessedArticleRepository {
+	return &memoryProcessedRepository{
+		index: make(map[string]*IndexEntry),
+	}
+}
+
+// LoadIndex returns a copy of the index
+func (m *memoryProcessedRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := make(map[string]*IndexEntry, len(m.index))
+	for key, entry := range m.index {
+		copied := *entry
+		index[key] = &copied
+	}
+	return index, nil
+}
+
+func (m *memoryProcessedRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	return exists
+}
+
+func (m *memoryProcessedRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := processedKey(article)
+	m.index[key] = &IndexEntry{
+		Title:         article.Title,
+		URL:           key,
+		Source:        article.Source,
+		PubDate:       article.ParsedDate,
+		ProcessedDate: Now(ctx),
+		PromptVariant: article.PromptVariant,
+		Provenance:    article.Provenance,
+	}
+	return nil
+}
+
+func (m *memoryProcessedRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := Now(ctx)
+	added := 0
+	for _, article := range articles {
+		key := processedKey(article)
+		if _, exists := m.index[key]; key == "" || exists {
+			continue
+		}
+		m.index[key] = &IndexEntry{
+			Title:         article.Title,
+			URL:           key,
+			Source:        article.Source,
+			PubDate:       article.ParsedDate,
+			ProcessedDate: now,
+		}
+		added++
+	}
+	return added, nil
+}
+
+// ExportEntries lists the whole index (LoadIndex already returns every entry)
+func (m *memoryProcessedRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	index, err := m.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntries(index), nil
+}
+
+func (m *memoryProcessedRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	added := 0
+	for _, entry := range entries {
+		key := processedKey(Item{Link: entry.URL})
+		if _, exists := m.index[key]; key == "" || exists {
+			continue
+		}
+		imported := *entry
+		imported.URL = key
+		m.index[key] = &imported
+		added++
+	}
+	return added, nil
+}
+
+func (m *memoryProcessedRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := processedKey(article)
+	if _, exists := m.index[key]; !exists {
+		return false, nil
+	}
+	delete(m.index, key)
+	return true, nil
+}
+
+func (m *memoryProcessedRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return pruneIndex(m.index, cutoff), nil
+}
+
+func (m *memoryProcessedRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// Flush is a no-op: marks are kept in memory only
+func (m *memoryProcessedRepository) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (m *memoryProcessedRepository) Close() error {
+	return nil
+}
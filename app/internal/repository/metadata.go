@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Meta tags describing a page, in order of preference (OpenGraph, Twitter cards, plain HTML)
+var (
+	metaTitleKeys       = []string{"og:title", "twitter:title"}
+	metaDescriptionKeys = []string{"og:description", "twitter:description", "description"}
+)
+
+// extractMetadataText returns the page's title and description from its OpenGraph, Twitter card
+// and description meta tags, for pages whose body has no article text (video pages, image posts).
+// ok is false without a description, since a title alone is not worth summarizing.
+func extractMetadataText(page string) (string, bool) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", false
+	}
+
+	meta := make(map[string]string)
+	var title string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Meta:
+				// OpenGraph uses property, Twitter cards and plain HTML use name
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				content := collapseSpaces(decodeEntities(attr(n, "content")))
+				if _, seen := meta[key]; key != "" && content != "" && !seen {
+					meta[key] = content
+				}
+			case atom.Title:
+				if title == "" {
+					title = nodeText(n)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	// Descriptions often repeat each other; distinct ones are all kept
+	var descriptions []string
+	for _, key := range metaDescriptionKeys {
+		if d := meta[key]; d != "" && !containsFold(descriptions, d) {
+			descriptions = append(descriptions, d)
+		}
+	}
+	if len(descriptions) == 0 {
+		return "", false
+	}
+	for _, key := range metaTitleKeys {
+		if meta[key] != "" {
+			title = meta[key]
+			break
+		}
+	}
+
+	var b strings.Builder
+	if title = decodeEntities(title); title != "" {
+		b.WriteString("Title: " + title + "\n\n")
+	}
+	b.WriteString("Description:\n" + strings.Join(descriptions, "\n"))
+	return b.String(), true
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+const videoPage = `<html><head>
+<title>Watch - VideoSite</title>
+<meta property="og:title" content="Go 1.24 release party">
+<meta property="og:description" content="Talks on generic type aliases, Swiss tables &amp; the new weak package.">
+<meta name="twitter:description" content="Talks on generic type aliases, Swiss tables &amp; the new weak package.">
+<meta name="description" content="Recorded live at GopherCon.">
+</head><body><div id="player"><video src="/v.mp4"></video></div><script>load()</script></body></html>`
+
+func TestExtractMetadataText(t *testing.T) {
+	text, ok := extractMetadataText(videoPage)
+	expected := "Title: Go 1.24 release party\n\nDescription:\nTalks on generic type aliases, Swiss tables & the new weak package.\nRecorded live at GopherCon."
+	if !ok || text != expected {
+		t.Errorf("Expected %q, got %q (ok=%v)", expected, text, ok)
+	}
+
+	// The <title> is used without og:title; a title alone is not enough
+	if text, ok := extractMetadataText(`<html><head><title>Photo</title><meta name="description" content="A sunset."></head></html>`); !ok || text != "Title: Photo\n\nDescription:\nA sunset." {
+		t.Errorf("Unexpected metadata text %q (ok=%v)", text, ok)
+	}
+	if _, ok := extractMetadataText(`<html><head><title>Photo</title></head><body><img src="a.jpg"></body></html>`); ok {
+		t.Error("Expected no metadata text without a description")
+	}
+}
+
+func TestExtractTextFromPages_MetadataFallback(t *testing.T) {
+	repo := &geminiRepository{}
+
+	text, method := repo.extractTextFromPages(context.Background(), "https://video.example.com/watch?v=1", []string{videoPage})
+	if method != ExtractionMetadata || text == "" {
+		t.Errorf("Expected the metadata fallback, got %q (%s)", text, method)
+	}
+
+	// Body text saying more than the meta tags is kept
+	_, method = repo.extractTextFromPages(context.Background(), "https://example.com/post", []string{`<html><head><meta name="description" content="d"></head><body><p>A short post with its own body text.</p></body></html>`})
+	if method == ExtractionMetadata {
+		t.Error("Expected the body text to be used")
+	}
+}
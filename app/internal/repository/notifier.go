@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// SourceOnDemand is the source of on-demand summaries (webhook, Slack actions) in notifications,
+// the processed index and history, so that every filter matches the same value
+const SourceOnDemand = "ondemand"
+
+// Notification represents a unified notification structure
+type Notification struct {
+	Title        string
+	Source       string // "reddit" | "hatena" | "lobsters" | SourceOnDemand
+	URL          string
+	Summary      string
+	ContentChars int // Original content character count
+	// PromptVariant tags the message with the prompt experiment variant (omitted when empty)
+	PromptVariant string
+	// Provenance records the model, prompt and extraction behind the summary (nil when unknown)
+	Provenance *Provenance
+	// Glossary explains the summary's acronyms; Slack shows it in GLOSSARY_CHANNELS
+	Glossary []GlossaryEntry
+	// Difficulty is the article's audience level; Slack tags the message with it (empty when unclassified)
+	Difficulty string
+	// Breaking marks breaking news: the title gets BreakingPrefix and batching notifiers send it at once
+	Breaking bool
+	// Comment marks a comment summary (sent after the article notification)
+	Comment bool
+	// OriginalTitle is the feed's title when Title is a rewritten headline (empty otherwise)
+	OriginalTitle string
+	// CommentsDelayed notes that the comment summary failed and will be posted once retried
+	CommentsDelayed bool
+	// Authors and PDFURL are shown for papers (empty otherwise)
+	Authors []string
+	PDFURL  string
+	// Tags and Likes are shown for community posts (Dev.to, Qiita, Zenn)
+	Tags  []string
+	Likes int
+	// Votes is shown for product launches (Product Hunt)
+	Votes int
+}
+
+// Notifier delivers summaries to a notification sink (Slack, Discord, ...)
+type Notifier interface {
+	Send(ctx context.Context, notification Notification) error
+	// SendOnDemandSummary posts an on-demand summary; targetChannel overrides the default destination
+	// where the sink supports it (empty uses the default)
+	SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error
+}
+
+// Flusher is implemented by notifiers that batch notifications (e.g. email digests);
+// processors call Flush once a run has finished.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// DigestSender is implemented by notifiers that can collapse several summaries into one message
+// listing their titles (see ChannelBudgets)
+type DigestSender interface {
+	SendDigest(ctx context.Context, notifications []Notification) error
+}
+
+// truncateRunes shortens s to at most max characters without breaking UTF-8, marking the cut with "…"
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
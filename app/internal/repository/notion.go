@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// Notion API limits
+const (
+	notionAPIVersion    = "2022-06-28"
+	notionRichTextLimit = 2000 // characters per rich text object
+	notionTitleLimit    = 2000
+	notionMaxBodyBlocks = 100 // children per create-page request
+)
+
+// Property names expected in the Notion database
+const (
+	NotionPropertyName   = "Name"   // title
+	NotionPropertyURL    = "URL"    // url
+	NotionPropertySource = "Source" // select
+	NotionPropertyTags   = "Tags"   // multi_select
+)
+
+type notionRepository struct {
+	token      string
+	databaseID string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewNotionRepository creates a Notifier appending each summary as a page in a Notion database.
+// The summary becomes the page body; comment summaries are skipped.
+func NewNotionRepository(token, databaseID, baseURL string) Notifier {
+	return &notionRepository{
+		token:      token,
+		databaseID: databaseID,
+		baseURL:    baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type notionCreatePageRequest struct {
+	Parent     notionParent              `json:"parent"`
+	Properties map[string]notionProperty `json:"properties"`
+	Children   []notionBlock             `json:"children,omitempty"`
+}
+
+type notionParent struct {
+	DatabaseID string `json:"database_id"`
+}
+
+type notionProperty struct {
+	Title       []notionRichText `json:"title,omitempty"`
+	URL         string           `json:"url,omitempty"`
+	Select      *notionOption    `json:"select,omitempty"`
+	MultiSelect []notionOption   `json:"multi_select,omitempty"`
+}
+
+type notionOption struct {
+	Name string `json:"name"`
+}
+
+type notionRichText struct {
+	Text notionText `json:"text"`
+}
+
+type notionText struct {
+	Content string `json:"content"`
+}
+
+type notionBlock struct {
+	Object    string          `json:"object"`
+	Type      string          `json:"type"`
+	Paragraph notionParagraph `json:"paragraph"`
+}
+
+type notionParagraph struct {
+	RichText []notionRichText `json:"rich_text"`
+}
+
+func (n *notionRepository) createPage(ctx context.Context, req notionCreatePageRequest) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		logger.Printf("Error marshaling Notion request: %v", err)
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", n.baseURL+"/pages", bytes.NewReader(body))
+	if err != nil {
+		logger.Printf("Error creating Notion HTTP request: %v", err)
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+n.token)
+	httpReq.Header.Set("Notion-Version", notionAPIVersion)
+
+	resp, err := n.httpClient.Do(httpReq)
+	if err != nil {
+		logger.Printf("Error sending request to Notion API: %v\nStack:\n%s", err, debug.Stack())
+		return httperr.Transport(fmt.Errorf("sending request: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(resp.Body)
+		logger.Printf("Notion API request failed status_code=%d response_body=%s\nStack:\n%s",
+			resp.StatusCode, string(responseBody), debug.Stack())
+		return httperr.FromResponse(resp, fmt.Errorf("notion API error: status %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// Send appends an article summary to the database
+func (n *notionRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if notification.Comment {
+		// Comment summaries would duplicate the article row
+		return nil
+	}
+
+	start := time.Now()
+	logger.Printf("Notion page creation started title=%s source=%s", notification.Title, notification.Source)
+	if err := n.createPage(ctx, n.buildPage(notification)); err != nil {
+		logger.Printf("Error creating Notion page: %v", err)
+		return err
+	}
+
+	logger.Printf("Notion page creation completed title=%s source=%s duration_ms=%d",
+		notification.Title, notification.Source, time.Since(start).Milliseconds())
+	return nil
+}
+
+// SendOnDemandSummary appends an on-demand summary; targetChannel does not apply to Notion
+func (n *notionRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	title := article.Title
+	if title == "" {
+		title = article.Link
+	}
+
+	return n.Send(ctx, Notification{
+		Title:         title,
+		Source:        SourceOnDemand,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+	})
+}
+
+func (n *notionRepository) buildPage(notification Notification) notionCreatePageRequest {
+	tags := []notionOption{{Name: notification.Source}}
+	if notification.PromptVariant != "" {
+		tags = append(tags, notionOption{Name: "prompt:" + notification.PromptVariant})
+	}
+	if p := notification.Provenance; p != nil {
+		tags = append(tags, notionOption{Name: "model:" + p.Model}, notionOption{Name: "extraction:" + p.Extraction})
+	}
+
+	properties := map[string]notionProperty{
+		NotionPropertyName:   {Title: []notionRichText{{Text: notionText{Content: truncateRunes(notification.Title, notionTitleLimit)}}}},
+		NotionPropertySource: {Select: &notionOption{Name: notification.Source}},
+		NotionPropertyTags:   {MultiSelect: tags},
+	}
+	if notification.URL != "" {
+		properties[NotionPropertyURL] = notionProperty{URL: notification.URL}
+	}
+
+	var children []notionBlock
+	for _, chunk := range splitRunes(notification.Summary, notionRichTextLimit) {
+		if len(children) == notionMaxBodyBlocks {
+			break
+		}
+		children = append(children, notionBlock{
+			Object:    "block",
+			Type:      "paragraph",
+			Paragraph: notionParagraph{RichText: []notionRichText{{Text: notionText{Content: chunk}}}},
+		})
+	}
+
+	return notionCreatePageRequest{
+		Parent:     notionParent{DatabaseID: n.databaseID},
+		Properties: properties,
+		Children:   children,
+	}
+}
+
+// splitRunes splits s into chunks of at most size characters
+func splitRunes(s string, size int) []string {
+	runes := []rune(s)
+	var chunks []string
+	for len(runes) > 0 {
+		end := size
+		if len(runes) < end {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[:end]))
+		runes = runes[end:]
+	}
+	return chunks
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotionRepository_Send(t *testing.T) {
+	var received notionCreatePageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pages" {
+			t.Errorf("Expected /pages, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			t.Errorf("Unexpected Authorization header: %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Notion-Version") == "" {
+			t.Error("Expected Notion-Version header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"object": "page"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewNotionRepository("secret-token", "db-123", server.URL)
+	err := notifier.Send(context.Background(), Notification{
+		Title:         "Test Article",
+		Source:        "lobsters",
+		URL:           "https://example.com/article",
+		Summary:       strings.Repeat("あ", notionRichTextLimit+10),
+		PromptVariant: "concise",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if received.Parent.DatabaseID != "db-123" {
+		t.Errorf("Expected database db-123, got %s", received.Parent.DatabaseID)
+	}
+	if title := received.Properties[NotionPropertyName].Title; len(title) != 1 || title[0].Text.Content != "Test Article" {
+		t.Errorf("Unexpected title property: %+v", title)
+	}
+	if received.Properties[NotionPropertyURL].URL != "https://example.com/article" {
+		t.Errorf("Unexpected URL property: %+v", received.Properties[NotionPropertyURL])
+	}
+	if tags := received.Properties[NotionPropertyTags].MultiSelect; len(tags) != 2 || tags[1].Name != "prompt:concise" {
+		t.Errorf("Unexpected tags: %+v", tags)
+	}
+	if len(received.Children) != 2 {
+		t.Errorf("Expected summary split into 2 paragraphs, got %d", len(received.Children))
+	}
+}
+
+func TestNotionRepository_SkipsComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Comment notifications should not create pages")
+	}))
+	defer server.Close()
+
+	notifier := NewNotionRepository("token", "db", server.URL)
+	if err := notifier.Send(context.Background(), Notification{Title: "t - コメント", Comment: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
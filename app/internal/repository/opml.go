@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// OPMLSource reads the OPML file listing additional feeds to process
+type OPMLSource interface {
+	Load(ctx context.Context) ([]byte, error)
+	Close() error
+}
+
+// NewOPMLSource creates a source for a local path or a gs://bucket/object or s3://bucket/object location
+func NewOPMLSource(location string) (OPMLSource, error) {
+	scheme, bucket, object, ok := parseBucketLocation(location)
+	if !ok {
+		return localOPMLSource{path: location}, nil
+	}
+
+	if bucket == "" || object == "" {
+		return nil, fmt.Errorf("invalid OPML location %q (expected %s://bucket/object)", location, scheme)
+	}
+	store, err := newBucketStorage(scheme, bucket, "")
+	if err != nil {
+		return nil, err
+	}
+	return &storageOPMLSource{storage: store, object: object}, nil
+}
+
+type localOPMLSource struct {
+	path string
+}
+
+func (l localOPMLSource) Load(ctx context.Context) ([]byte, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading OPML file: %w", err)
+	}
+	return data, nil
+}
+
+func (l localOPMLSource) Close() error {
+	return nil
+}
+
+// storageOPMLSource reads the OPML file from an object store
+type storageOPMLSource struct {
+	storage Storage
+	object  string
+}
+
+func (s *storageOPMLSource) Load(ctx context.Context) ([]byte, error) {
+	data, err := s.storage.Read(ctx, s.object)
+	if err != nil {
+		return nil, fmt.Errorf("reading OPML object: %w", err)
+	}
+	return data, nil
+}
+
+func (s *storageOPMLSource) Close() error {
+	return s.storage.Close()
+}
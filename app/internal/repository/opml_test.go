@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewOPMLSource_Local(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "subscriptions.opml")
+	if err := os.WriteFile(path, []byte("<opml/>"), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	source, err := NewOPMLSource(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer source.Close()
+
+	data, err := source.Load(context.Background())
+	if err != nil || string(data) != "<opml/>" {
+		t.Errorf("Expected file content, got %q (%v)", data, err)
+	}
+
+	missing, _ := NewOPMLSource(filepath.Join(t.TempDir(), "missing.opml"))
+	if _, err := missing.Load(context.Background()); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+func TestNewOPMLSource_InvalidGCSLocation(t *testing.T) {
+	for _, location := range []string{"gs://", "gs://bucket", "gs://bucket/"} {
+		if _, err := NewOPMLSource(location); err == nil {
+			t.Errorf("Expected error for %q", location)
+		}
+	}
+}
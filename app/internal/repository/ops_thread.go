@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// OpsThread posts a per-run parent message and threaded annotation lines to an ops channel
+type OpsThread interface {
+	// Start posts the run's parent message and returns its thread id
+	Start(ctx context.Context, text string) (string, error)
+	// Reply adds a line to the run's thread
+	Reply(ctx context.Context, threadID, text string) error
+}
+
+// NewSlackOpsThread creates an OpsThread in a Slack channel; posts share the channel's pacing
+func NewSlackOpsThread(botToken, channel, baseURL string) OpsThread {
+	return NewSlackRepository(botToken, channel, baseURL).(*slackRepository)
+}
+
+func (s *slackRepository) Start(ctx context.Context, text string) (string, error) {
+	ts, err := s.sendThreaded(ctx, text, nil, s.channel, "")
+	if err != nil {
+		return "", err
+	}
+	if ts == "" {
+		return "", fmt.Errorf("slack response has no ts")
+	}
+	return ts, nil
+}
+
+func (s *slackRepository) Reply(ctx context.Context, threadID, text string) error {
+	_, err := s.sendThreaded(ctx, text, nil, s.channel, threadID)
+	return err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// Signature headers: the HMAC-SHA256 of "<timestamp>.<body>" ("sha256=<hex>") and the Unix timestamp it covers.
+// Receivers should reject stale timestamps to prevent replays.
+const (
+	OutboundWebhookSignatureHeader = "X-Signature-256"
+	OutboundWebhookTimestampHeader = "X-Signature-Timestamp"
+)
+
+// Retry defaults for outbound webhook delivery
+const (
+	defaultOutboundWebhookMaxAttempts    = 3
+	defaultOutboundWebhookInitialBackoff = time.Second
+)
+
+// Outbound webhook event types
+const (
+	OutboundWebhookEventArticle  = "article"
+	OutboundWebhookEventComment  = "comment"
+	OutboundWebhookEventOnDemand = "ondemand"
+)
+
+// OutboundWebhookPayload is the JSON body POSTed to the configured URL
+type OutboundWebhookPayload struct {
+	Event          string    `json:"event"`
+	Title          string    `json:"title"`
+	Source         string    `json:"source"`
+	URL            string    `json:"url"`
+	Summary        string    `json:"summary,omitempty"`
+	CommentSummary string    `json:"comment_summary,omitempty"`
+	ContentChars   int       `json:"content_chars"`
+	PromptVariant  string    `json:"prompt_variant,omitempty"`
+	SentAt         time.Time `json:"sent_at"`
+	// Provenance is the model, prompt and extraction behind the summary (omitted when unknown)
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// OutboundWebhookConfig holds the endpoint and delivery settings for the outbound webhook notifier
+type OutboundWebhookConfig struct {
+	URL            string
+	Secret         string        // Empty disables signing
+	MaxAttempts    int           // Deliveries per notification, including the first (0 uses the default)
+	InitialBackoff time.Duration // Doubled after each failed attempt (0 uses the default)
+}
+
+type outboundWebhookRepository struct {
+	config     OutboundWebhookConfig
+	breaker    *circuitBreaker
+	httpClient *http.Client
+}
+
+// NewOutboundWebhookRepository creates a Notifier POSTing summaries as JSON to an arbitrary URL.
+// Failed deliveries are retried with exponential backoff behind a circuit breaker shared per URL.
+func NewOutboundWebhookRepository(config OutboundWebhookConfig) Notifier {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = defaultOutboundWebhookMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultOutboundWebhookInitialBackoff
+	}
+	return &outboundWebhookRepository{
+		config:  config,
+		breaker: endpointBreaker(config.URL),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// post delivers the payload, retrying temporary and rate-limited failures with exponential backoff
+// (or the endpoint's Retry-After when longer)
+func (o *outboundWebhookRepository) post(ctx context.Context, payload OutboundWebhookPayload) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	payload.SentAt = time.Now().UTC()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Printf("Error marshaling outbound webhook payload: %v", err)
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	backoff := o.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if !o.breaker.Allow() {
+			logger.Printf("Outbound webhook skipped, circuit open event=%s", payload.Event)
+			return ErrCircuitOpen
+		}
+
+		err = o.deliver(ctx, body)
+		// Permanent failures (4xx) mean the endpoint is up, so only retryable ones trip the breaker
+		o.breaker.Record(!httperr.Retryable(err))
+
+		if err == nil || !httperr.Retryable(err) || attempt >= o.config.MaxAttempts {
+			return err
+		}
+
+		wait := backoff
+		if retryAfter, ok := httperr.RetryAfter(err); ok && retryAfter > wait {
+			wait = retryAfter
+		}
+		logger.Printf("Outbound webhook attempt failed, retrying event=%s attempt=%d backoff_ms=%d: %v",
+			payload.Event, attempt, wait.Milliseconds(), err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes a single signed POST
+func (o *outboundWebhookRepository) deliver(ctx context.Context, body []byte) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", o.config.URL, bytes.NewReader(body))
+	if err != nil {
+		logger.Printf("Error creating outbound webhook request: %v", err)
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Article Summarizer Webhook")
+	if o.config.Secret != "" {
+		// Sign at send time so retries carry a fresh timestamp
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		httpReq.Header.Set(OutboundWebhookTimestampHeader, timestamp)
+		httpReq.Header.Set(OutboundWebhookSignatureHeader, SignOutboundWebhook(o.config.Secret, timestamp, body))
+	}
+
+	resp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		logger.Printf("Error sending outbound webhook: %v\nStack:\n%s", err, debug.Stack())
+		return httperr.Transport(fmt.Errorf("sending request: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(resp.Body)
+		logger.Printf("Outbound webhook request failed status_code=%d response_body=%s", resp.StatusCode, string(responseBody))
+		return httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+
+	return nil
+}
+
+// Send posts an article or comment summary
+func (o *outboundWebhookRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	payload := OutboundWebhookPayload{
+		Event:         OutboundWebhookEventArticle,
+		Title:         notification.Title,
+		Source:        notification.Source,
+		URL:           notification.URL,
+		Summary:       notification.Summary,
+		ContentChars:  notification.ContentChars,
+		PromptVariant: notification.PromptVariant,
+		Provenance:    notification.Provenance,
+	}
+	if notification.Comment {
+		payload.Event = OutboundWebhookEventComment
+		payload.Summary = ""
+		payload.CommentSummary = notification.Summary
+	}
+
+	logger.Printf("Outbound webhook notification started title=%s source=%s event=%s", notification.Title, notification.Source, payload.Event)
+	if err := o.post(ctx, payload); err != nil {
+		logger.Printf("Error sending notification to outbound webhook: %v", err)
+		return err
+	}
+
+	logger.Printf("Outbound webhook notification completed title=%s source=%s duration_ms=%d",
+		notification.Title, notification.Source, time.Since(start).Milliseconds())
+	return nil
+}
+
+// SendOnDemandSummary posts an on-demand summary; the URL is fixed, so targetChannel is ignored
+func (o *outboundWebhookRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("On-demand outbound webhook notification started url=%s", article.Link)
+	if err := o.post(ctx, OutboundWebhookPayload{
+		Event:         OutboundWebhookEventOnDemand,
+		Title:         article.Title,
+		Source:        SourceOnDemand,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+	}); err != nil {
+		logger.Printf("Error sending on-demand summary to outbound webhook: %v", err)
+		return err
+	}
+
+	logger.Printf("On-demand outbound webhook notification completed url=%s duration_ms=%d", article.Link, time.Since(start).Milliseconds())
+	return nil
+}
+
+// SignOutboundWebhook returns the signature header value for a timestamp and body;
+// receivers recompute it with the shared secret
+func SignOutboundWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
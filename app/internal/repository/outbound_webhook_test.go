@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestOutboundWebhookRepository_SendSigned(t *testing.T) {
+	var received OutboundWebhookPayload
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(OutboundWebhookSignatureHeader)
+		if signature != SignOutboundWebhook("secret", r.Header.Get(OutboundWebhookTimestampHeader), body) {
+			t.Errorf("Signature does not match body: %s", signature)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL, Secret: "secret"})
+	err := notifier.Send(context.Background(), Notification{
+		Title:        "Test Article - コメント",
+		Source:       "hatena",
+		URL:          "https://example.com/article",
+		Summary:      "comment summary",
+		ContentChars: 42,
+		Comment:      true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if signature == "" {
+		t.Error("Expected signature header when secret is set")
+	}
+	if received.Event != OutboundWebhookEventComment {
+		t.Errorf("Expected comment event, got %s", received.Event)
+	}
+	if received.CommentSummary != "comment summary" || received.Summary != "" {
+		t.Errorf("Expected comment summary only, got summary=%q comment_summary=%q", received.Summary, received.CommentSummary)
+	}
+	if received.URL != "https://example.com/article" || received.ContentChars != 42 {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+}
+
+func TestOutboundWebhookRepository_Unsigned(t *testing.T) {
+	var received OutboundWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sig := r.Header.Get(OutboundWebhookSignatureHeader); sig != "" {
+			t.Errorf("Expected no signature without secret, got %s", sig)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL})
+	err := notifier.SendOnDemandSummary(context.Background(), Item{Title: "Title", Link: "https://example.com"}, SummarizeResponse{Summary: "summary", ContentChars: 10}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Event != OutboundWebhookEventOnDemand || received.Summary != "summary" {
+		t.Errorf("Unexpected payload: %+v", received)
+	}
+}
+
+func TestOutboundWebhookRepository_RetriesServerErrors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL, MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if err := notifier.Send(context.Background(), Notification{Title: "t", Source: "reddit"}); err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestOutboundWebhookRepository_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL, MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	if err := notifier.Send(context.Background(), Notification{Title: "t", Source: "reddit"}); err == nil {
+		t.Fatal("Expected error for non-2xx response")
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt for 4xx, got %d", calls)
+	}
+}
+
+func TestOutboundWebhookRepository_CircuitOpens(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL, MaxAttempts: 1, InitialBackoff: time.Millisecond})
+	for i := 0; i < defaultBreakerFailureThreshold; i++ {
+		notifier.Send(context.Background(), Notification{Title: "t", Source: "reddit"})
+	}
+
+	err := notifier.Send(context.Background(), Notification{Title: "t", Source: "reddit"})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls != defaultBreakerFailureThreshold {
+		t.Errorf("Expected no call while open, got %d calls", calls)
+	}
+}
+
+func TestOutboundWebhookRepository_ClientErrorsKeepCircuitClosed(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	notifier := NewOutboundWebhookRepository(OutboundWebhookConfig{URL: server.URL, MaxAttempts: 1, InitialBackoff: time.Millisecond})
+	for i := 0; i <= defaultBreakerFailureThreshold; i++ {
+		err := notifier.Send(context.Background(), Notification{Title: "t", Source: "reddit"})
+		if errors.Is(err, ErrCircuitOpen) || !httperr.IsPermanent(err) {
+			t.Fatalf("Expected a permanent delivery error, got %v", err)
+		}
+	}
+	if calls != defaultBreakerFailureThreshold+1 {
+		t.Errorf("Expected every delivery to reach the endpoint, got %d calls", calls)
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// defaultMaxArticlePages bounds how many pages of a multi-page article are fetched
+const defaultMaxArticlePages = 5
+
+var (
+	relNextTagRe   = regexp.MustCompile(`(?i)<(?:link|a)\b[^>]*\brel=["']?next["']?[^>]*>`)
+	hrefAttrRe     = regexp.MustCompile(`(?i)\bhref=["']([^"']+)["']`)
+	anchorRe       = regexp.MustCompile(`(?is)<a\b([^>]*)>(.*?)</a>`)
+	innerTagRe     = regexp.MustCompile(`<[^>]+>`)
+	pageNumberText = regexp.MustCompile(`(?i)^(?:page\s*(\d+)|(\d+)\s*ページ目?|次のページ|next\s*page)$`)
+)
+
+// findNextPageURL detects the next page of a paginated article.
+// rel="next" takes precedence; otherwise an anchor labelled "Page N" / "Nページ" (N = currentPage+1)
+// or "次のページ" / "Next page" pointing to the same host is used.
+func findNextPageURL(html, pageURL string, currentPage int) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	if tag := relNextTagRe.FindString(html); tag != "" {
+		if m := hrefAttrRe.FindStringSubmatch(tag); len(m) > 1 {
+			if next := resolveSameHost(base, m[1]); next != "" {
+				return next
+			}
+		}
+	}
+
+	for _, m := range anchorRe.FindAllStringSubmatch(html, -1) {
+		label := strings.TrimSpace(innerTagRe.ReplaceAllString(m[2], ""))
+		pm := pageNumberText.FindStringSubmatch(label)
+		if pm == nil {
+			continue
+		}
+
+		// "Page N" must point to the page right after the current one
+		if n := pm[1] + pm[2]; n != "" {
+			if num, err := strconv.Atoi(n); err != nil || num != currentPage+1 {
+				continue
+			}
+		}
+
+		hm := hrefAttrRe.FindStringSubmatch(m[1])
+		if len(hm) < 2 {
+			continue
+		}
+		if next := resolveSameHost(base, hm[1]); next != "" {
+			return next
+		}
+	}
+
+	return ""
+}
+
+// resolveSameHost resolves href against base and rejects links leaving the article's host
+func resolveSameHost(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(ref)
+	if !strings.EqualFold(resolved.Host, base.Host) {
+		return ""
+	}
+	resolved.Fragment = ""
+	if resolved.String() == base.String() {
+		return ""
+	}
+	return resolved.String()
+}
+
+// fetchArticlePages fetches the article and follows pagination links up to maxPages.
+// The first page must succeed; failures on subsequent pages stop pagination but keep what was fetched.
+func (g *geminiRepository) fetchArticlePages(ctx context.Context, pageURL string) ([]string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	// Internal documentation is read through its API as a single page (a plain GET returns the login page)
+	if fetcher := fetcherFor(g.documentFetchers, pageURL); fetcher != nil {
+		html, err := fetcher.Fetch(ctx, pageURL)
+		if err != nil {
+			logger.Printf("Error fetching document via API url=%s: %v", pageURL, err)
+			return nil, err
+		}
+		return []string{html}, nil
+	}
+
+	// Pages disallowed by robots.txt are permanent failures: retrying cannot make them fetchable
+	if err := g.policy.Wait(ctx, pageURL); err != nil {
+		logger.Printf("Fetch skipped by policy url=%s: %v", pageURL, err)
+		if errors.Is(err, ErrDisallowedByRobots) {
+			return nil, httperr.Permanent(err)
+		}
+		return nil, err
+	}
+	first, err := g.fetchHTML(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// AMP variants are read from their desktop page once the AMP page's canonical link confirms it
+	if desktop := confirmedDesktopURL(pageURL, first); desktop != "" && g.policy.Wait(ctx, desktop) == nil {
+		if html, err := g.fetchHTML(ctx, desktop); err == nil {
+			logger.Printf("AMP page replaced by its canonical page url=%s canonical=%s", pageURL, desktop)
+			first, pageURL = html, desktop
+		} else {
+			logger.Printf("Warning: Failed to fetch canonical page of AMP variant url=%s canonical=%s: %v", pageURL, desktop, err)
+		}
+	}
+
+	pages := []string{first}
+	visited := map[string]bool{pageURL: true}
+	current := pageURL
+
+	for len(pages) < g.maxPages {
+		next := findNextPageURL(pages[len(pages)-1], current, len(pages))
+		if next == "" || visited[next] {
+			break
+		}
+		visited[next] = true
+
+		if err := g.policy.Wait(ctx, next); err != nil {
+			logger.Printf("Pagination stopped url=%s page=%d error=%v", next, len(pages)+1, err)
+			break
+		}
+		html, err := g.fetchHTML(ctx, next)
+		if err != nil {
+			logger.Printf("Pagination stopped url=%s page=%d error=%v", next, len(pages)+1, err)
+			break
+		}
+		pages = append(pages, html)
+		current = next
+	}
+
+	if len(pages) > 1 {
+		logger.Printf("Paginated article fetched url=%s pages=%d", pageURL, len(pages))
+	}
+
+	return pages, nil
+}
+
+// extractTextFromPages extracts text from each page and concatenates it in page order, returning the
+// extraction method for provenance. A matching per-domain rule is applied first; other pages, and
+// pages where its selector finds nothing, use their main content (see extractMainContent), or the
+// whole page when no main content stands out.
+func (g *geminiRepository) extractTextFromPages(ctx context.Context, pageURL string, pages []string) (string, string) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	rule := ruleFor(g.extractionRules, pageURL)
+	method := ExtractionHTML
+
+	var texts []string
+	for _, page := range pages {
+		if isPDFPage(page) {
+			if text := g.extractTextFromHTML(page); text != "" {
+				texts = append(texts, text)
+				method = ExtractionPDF
+			}
+			continue
+		}
+		ruled := false
+		if rule != nil {
+			if body, ok := applyExtractionRule(rule, page); ok {
+				page = body
+				method = "rule:" + rule.Domain
+				ruled = true
+			} else {
+				logger.Printf("Extraction rule selector not found, using generic extraction domain=%s url=%s", rule.Domain, pageURL)
+			}
+		}
+		if !ruled {
+			if text, ok := extractMainContent(page); ok {
+				texts = append(texts, text)
+				if method == ExtractionHTML {
+					method = ExtractionReadability
+				}
+				continue
+			}
+		}
+		if text := g.extractTextFromHTML(page); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	// Pages without body text (video pages, image posts, where only the title and page chrome are
+	// left) are summarized from their meta tags when those say more
+	text := strings.Join(texts, "\n\n")
+	if method == ExtractionHTML && utf8.RuneCountInString(text) < minMainContent && len(pages) > 0 {
+		if metadata, ok := extractMetadataText(pages[0]); ok && utf8.RuneCountInString(metadata) > utf8.RuneCountInString(text) {
+			logger.Printf("No body text, using page metadata url=%s text_chars=%d", pageURL, utf8.RuneCountInString(text))
+			return metadata, ExtractionMetadata
+		}
+	}
+	return text, method
+}
+
+// contentLength returns the total HTML length of all fetched pages
+func contentLength(pages []string) int {
+	total := 0
+	for _, page := range pages {
+		total += len(page)
+	}
+	return total
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindNextPageURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		html        string
+		pageURL     string
+		currentPage int
+		expected    string
+	}{
+		{
+			name:        "rel next link tag",
+			html:        `<head><link rel="next" href="/post?page=2"></head>`,
+			pageURL:     "https://example.com/post",
+			currentPage: 1,
+			expected:    "https://example.com/post?page=2",
+		},
+		{
+			name:        "Page 2 anchor",
+			html:        `<a href="/post/1">Page 1</a> <a href="/post/2">Page 2</a>`,
+			pageURL:     "https://example.com/post/1",
+			currentPage: 1,
+			expected:    "https://example.com/post/2",
+		},
+		{
+			name:        "Japanese page anchor",
+			html:        `<a href="/post/3"><span>3ページ</span></a>`,
+			pageURL:     "https://example.com/post/2",
+			currentPage: 2,
+			expected:    "https://example.com/post/3",
+		},
+		{
+			name:        "page anchor for wrong number",
+			html:        `<a href="/post/3">Page 3</a>`,
+			pageURL:     "https://example.com/post/1",
+			currentPage: 1,
+			expected:    "",
+		},
+		{
+			name:        "external host is ignored",
+			html:        `<a rel="next" href="https://other.example.org/post/2">Next</a>`,
+			pageURL:     "https://example.com/post/1",
+			currentPage: 1,
+			expected:    "",
+		},
+		{
+			name:        "no pagination",
+			html:        `<p>single page</p>`,
+			pageURL:     "https://example.com/post",
+			currentPage: 1,
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := findNextPageURL(tt.html, tt.pageURL, tt.currentPage)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGeminiRepository_FetchArticlePages_Bounded(t *testing.T) {
+	// Every page links to the next one, forever
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("p")
+		if page == "" {
+			page = "1"
+		}
+		var next int
+		fmt.Sscanf(page, "%d", &next)
+		fmt.Fprintf(w, `<html><body><p>content %s</p><a rel="next" href="%s/?p=%d">next</a></body></html>`, page, server.URL, next+1)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		maxPages:   3,
+	}
+
+	pages, err := repo.fetchArticlePages(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+
+	text, _ := repo.extractTextFromPages(context.Background(), "https://example.com/article", pages)
+	for _, expected := range []string{"content 1", "content 2", "content 3"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected text to contain '%s', got '%s'", expected, text)
+		}
+	}
+}
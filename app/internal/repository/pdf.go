@@ -0,0 +1,722 @@
+package repository
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// PDF limits (PDF_MAX_BYTES, PDF_MAX_PAGES)
+const (
+	defaultMaxPDFBytes = 20 << 20
+	defaultMaxPDFPages = 30
+)
+
+// pdfGenerator marks the HTML a PDF was converted to, so that its extraction is recorded as ExtractionPDF
+const pdfGenerator = "article-summarizer-pdf"
+
+// ErrPDFNoText is returned for PDFs without extractable text (scanned images, unsupported encodings)
+var ErrPDFNoText = errors.New("no text found in PDF")
+
+// isPDF reports whether a response is a PDF, by its Content-Type or, for servers sending
+// application/octet-stream, by the %PDF- signature
+func isPDF(contentType string, body []byte) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), "application/pdf") || bytes.HasPrefix(body, []byte("%PDF-"))
+}
+
+// pdfHTML converts a PDF's text into HTML (one paragraph per line) for the usual extraction
+func pdfHTML(data []byte, maxPages int) (string, error) {
+	pages, err := extractPDFText(data, maxPages)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(`<html><head><meta name="generator" content="` + pdfGenerator + `"></head><body>`)
+	for _, page := range pages {
+		b.WriteString("<section>")
+		for _, line := range strings.Split(page, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				b.WriteString("<p>" + html.EscapeString(line) + "</p>")
+			}
+		}
+		b.WriteString("</section>")
+	}
+	b.WriteString("</body></html>")
+	return b.String(), nil
+}
+
+// isPDFPage reports whether a fetched page is the HTML of a converted PDF
+func isPDFPage(page string) bool {
+	return strings.Contains(page, `<meta name="generator" content="`+pdfGenerator+`">`)
+}
+
+// extractPDFText returns the text of the PDF's first maxPages pages. It reads classic and
+// cross-reference-stream PDFs with uncompressed or Flate-compressed content, and maps font codes
+// to Unicode through ToUnicode CMaps (CID fonts of Japanese PDFs); other fonts are read as Latin-1.
+func extractPDFText(data []byte, maxPages int) ([]string, error) {
+	doc := parsePDF(data)
+	root, ok := doc.resolve(doc.root).(pdfDict)
+	if !ok {
+		return nil, errors.New("PDF has no document catalog")
+	}
+
+	var pages []string
+	var walk func(node pdfDict, resources pdfDict, depth int)
+	walk = func(node pdfDict, resources pdfDict, depth int) {
+		if len(pages) >= maxPages || depth > 32 {
+			return
+		}
+		if r, ok := doc.resolve(node["Resources"]).(pdfDict); ok {
+			resources = r // Inherited by the node's kids
+		}
+		if kids, ok := doc.resolve(node["Kids"]).(pdfArray); ok {
+			for _, kid := range kids {
+				if kidNode, ok := doc.resolve(kid).(pdfDict); ok {
+					walk(kidNode, resources, depth+1)
+				}
+			}
+			return
+		}
+		if node["Type"] == pdfName("Page") || node["Contents"] != nil {
+			pages = append(pages, doc.pageText(node, resources))
+		}
+	}
+	if pageTree, ok := doc.resolve(root["Pages"]).(pdfDict); ok {
+		walk(pageTree, nil, 0)
+	}
+
+	for _, page := range pages {
+		if strings.TrimSpace(page) != "" {
+			return pages, nil
+		}
+	}
+	return nil, ErrPDFNoText
+}
+
+// PDF object model
+type (
+	pdfName    string
+	pdfString  string
+	pdfKeyword string
+	pdfRef     int
+	pdfArray   []any
+	pdfDict    map[string]any
+	pdfStream  struct {
+		dict pdfDict
+		data []byte // Raw (still encoded) stream data
+	}
+)
+
+type pdfDocument struct {
+	objects map[int]any
+	root    any
+}
+
+var pdfObjectPattern = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+var pdfTrailerPattern = regexp.MustCompile(`trailer\s*<<`)
+
+// parsePDF reads every object in file order (later revisions replace earlier ones), expanding
+// object streams; the cross-reference table is not needed for that
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{objects: make(map[int]any)}
+	end := 0
+	for _, match := range pdfObjectPattern.FindAllSubmatchIndex(data, -1) {
+		if match[0] < end {
+			continue // Inside the previous object's stream
+		}
+		num, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		lex := &pdfLexer{data: data, pos: match[1]}
+		obj, ok := lex.object()
+		if !ok {
+			continue
+		}
+		if dict, isDict := obj.(pdfDict); isDict && lex.keyword("stream") {
+			stream := &pdfStream{dict: dict, data: lex.streamData()}
+			obj = stream
+			if dict["Type"] == pdfName("XRef") && dict["Root"] != nil {
+				doc.root = dict["Root"]
+			}
+		}
+		doc.objects[num] = obj
+		end = lex.pos
+	}
+	for _, match := range pdfTrailerPattern.FindAllIndex(data, -1) {
+		lex := &pdfLexer{data: data, pos: match[1] - 2}
+		if trailer, ok := lex.object(); ok {
+			if dict, ok := trailer.(pdfDict); ok && dict["Root"] != nil {
+				doc.root = dict["Root"]
+			}
+		}
+	}
+
+	// Objects compressed into object streams (PDF 1.5+)
+	for _, obj := range doc.objects {
+		stream, ok := obj.(*pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		decoded, err := doc.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := doc.resolve(stream.dict["N"]).(float64)
+		first, _ := doc.resolve(stream.dict["First"]).(float64)
+		header := &pdfLexer{data: decoded}
+		for i := 0; i < int(n); i++ {
+			num, ok1 := header.object()
+			offset, ok2 := header.object()
+			numValue, ok3 := num.(float64)
+			offsetValue, ok4 := offset.(float64)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				break
+			}
+			if _, exists := doc.objects[int(numValue)]; exists {
+				continue
+			}
+			lex := &pdfLexer{data: decoded, pos: int(first + offsetValue)}
+			if value, ok := lex.object(); ok {
+				doc.objects[int(numValue)] = value
+			}
+		}
+	}
+	return doc
+}
+
+// resolve follows indirect references
+func (d *pdfDocument) resolve(obj any) any {
+	for i := 0; i < 8; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = d.objects[int(ref)]
+	}
+	return nil
+}
+
+// decodeStream returns the stream's data with its filters applied (FlateDecode only)
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	var filters []any
+	switch filter := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{filter}
+	case pdfArray:
+		filters = filter
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		if d.resolve(filter) != pdfName("FlateDecode") {
+			return nil, fmt.Errorf("unsupported PDF filter %v", filter)
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		// Truncated streams still yield what was decoded
+		decoded, err := io.ReadAll(reader)
+		if len(decoded) == 0 && err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+	return data, nil
+}
+
+// pageText extracts the text shown by the page's content streams
+func (d *pdfDocument) pageText(page, resources pdfDict) string {
+	var content []byte
+	switch contents := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		content, _ = d.decodeStream(contents)
+	case pdfArray:
+		for _, part := range contents {
+			if stream, ok := d.resolve(part).(*pdfStream); ok {
+				if decoded, err := d.decodeStream(stream); err == nil {
+					content = append(append(content, decoded...), '\n')
+				}
+			}
+		}
+	}
+
+	fonts, _ := d.resolve(resources["Font"]).(pdfDict)
+	cmaps := make(map[string]*pdfCMap)
+	var current *pdfCMap
+
+	var b strings.Builder
+	show := func(s pdfString) {
+		if current != nil {
+			b.WriteString(current.decode([]byte(s)))
+			return
+		}
+		for _, c := range []byte(s) {
+			b.WriteRune(rune(c))
+		}
+	}
+
+	var operands []any
+	lastY, haveY := 0.0, false
+	lex := &pdfLexer{data: content}
+	for {
+		obj, ok := lex.object()
+		if !ok {
+			break
+		}
+		op, isOp := obj.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+		switch op {
+		case "Tf":
+			if len(operands) >= 2 {
+				name, _ := operands[len(operands)-2].(pdfName)
+				if _, loaded := cmaps[string(name)]; !loaded {
+					cmaps[string(name)] = d.fontCMap(fonts[string(name)])
+				}
+				current = cmaps[string(name)]
+			}
+		case "Tj", "'", "\"":
+			if op != "Tj" {
+				b.WriteString("\n")
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				if array, ok := operands[len(operands)-1].(pdfArray); ok {
+					for _, item := range array {
+						switch item := item.(type) {
+						case pdfString:
+							show(item)
+						case float64:
+							if item < -200 { // A gap wide enough to be a space
+								b.WriteString(" ")
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, _ := operands[len(operands)-1].(float64); ty != 0 {
+					b.WriteString("\n")
+				} else if tx, _ := operands[len(operands)-2].(float64); tx > 0 {
+					b.WriteString(" ")
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := operands[len(operands)-1].(float64)
+				if haveY && y != lastY {
+					b.WriteString("\n")
+				}
+				lastY, haveY = y, true
+			}
+		case "T*", "ET":
+			b.WriteString("\n")
+		case "ID":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	return normalizePDFText(b.String())
+}
+
+// normalizePDFText collapses spaces within lines and drops empty lines
+func normalizePDFText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = collapseSpaces(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pdfCMap maps character codes of a font to Unicode text (a ToUnicode CMap)
+type pdfCMap struct {
+	codeLength int // Bytes per code
+	mapping    map[uint32]string
+}
+
+var (
+	pdfBFCharPattern  = regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`)
+	pdfBFRangePattern = regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`)
+)
+
+// fontCMap returns the font's ToUnicode CMap, or nil to read its codes as Latin-1
+func (d *pdfDocument) fontCMap(font any) *pdfCMap {
+	fontDict, ok := d.resolve(font).(pdfDict)
+	if !ok {
+		return nil
+	}
+	stream, ok := d.resolve(fontDict["ToUnicode"]).(*pdfStream)
+	if !ok {
+		return nil
+	}
+	data, err := d.decodeStream(stream)
+	if err != nil {
+		return nil
+	}
+
+	cmap := &pdfCMap{codeLength: 1, mapping: make(map[uint32]string)}
+	if fontDict["Subtype"] == pdfName("Type0") {
+		cmap.codeLength = 2 // Composite (CID) fonts
+	}
+	for _, section := range pdfBFCharPattern.FindAllSubmatch(data, -1) {
+		values := pdfSectionValues(section[1])
+		for i := 0; i+1 < len(values); i += 2 {
+			src, srcOK := values[i].(pdfString)
+			dst, dstOK := values[i+1].(pdfString)
+			if srcOK && dstOK {
+				cmap.mapping[pdfCode(src)] = utf16BE(dst)
+			}
+		}
+	}
+	for _, section := range pdfBFRangePattern.FindAllSubmatch(data, -1) {
+		values := pdfSectionValues(section[1])
+		for i := 0; i+2 < len(values); i += 3 {
+			lo, loOK := values[i].(pdfString)
+			hi, hiOK := values[i+1].(pdfString)
+			if !loOK || !hiOK {
+				continue
+			}
+			from, to := pdfCode(lo), pdfCode(hi)
+			if to < from || to-from > 0xffff {
+				continue
+			}
+			switch dst := values[i+2].(type) {
+			case pdfString:
+				base := []rune(utf16BE(dst))
+				if len(base) == 0 {
+					continue
+				}
+				for code := from; code <= to; code++ {
+					offset := rune(code - from)
+					cmap.mapping[code] = string(base[:len(base)-1]) + string(base[len(base)-1]+offset)
+				}
+			case pdfArray:
+				for j, item := range dst {
+					if s, ok := item.(pdfString); ok && from+uint32(j) <= to {
+						cmap.mapping[from+uint32(j)] = utf16BE(s)
+					}
+				}
+			}
+		}
+	}
+	return cmap
+}
+
+func (c *pdfCMap) decode(s []byte) string {
+	var b strings.Builder
+	for i := 0; i+c.codeLength <= len(s); i += c.codeLength {
+		text, ok := c.mapping[pdfCode(pdfString(s[i:i+c.codeLength]))]
+		if !ok {
+			continue // Unmapped glyph
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+func pdfSectionValues(section []byte) []any {
+	var values []any
+	lex := &pdfLexer{data: section}
+	for {
+		value, ok := lex.object()
+		if !ok {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+func pdfCode(s pdfString) uint32 {
+	var code uint32
+	for _, c := range []byte(s) {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+func utf16BE(s pdfString) string {
+	raw := []byte(s)
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// pdfLexer reads PDF objects (and content stream operators as pdfKeyword)
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPDFWhitespace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// keyword consumes the keyword if it comes next
+func (l *pdfLexer) keyword(word string) bool {
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte(word)) {
+		return false
+	}
+	l.pos += len(word)
+	return true
+}
+
+// streamData returns the data after the stream keyword, up to endstream
+func (l *pdfLexer) streamData() []byte {
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	start := l.pos
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return l.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+	return bytes.TrimRight(l.data[start:start+end], "\r\n")
+}
+
+// skipInlineImage skips the binary data of an inline image (BI ... ID <data> EI)
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos; i+2 < len(l.data); i++ {
+		if isPDFWhitespace(l.data[i]) && l.data[i+1] == 'E' && l.data[i+2] == 'I' && (i+3 == len(l.data) || isPDFWhitespace(l.data[i+3])) {
+			l.pos = i + 3
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// object reads the next object; ok is false at the end of the data or on a closing delimiter
+func (l *pdfLexer) object() (any, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.name(), true
+	case c == '(':
+		return l.literalString(), true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		dict := make(pdfDict)
+		for {
+			l.skipSpace()
+			if l.pos+1 < len(l.data) && l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+				l.pos += 2
+				return dict, true
+			}
+			key, ok := l.object()
+			name, isName := key.(pdfName)
+			if !ok || !isName {
+				return dict, l.pos < len(l.data)
+			}
+			value, ok := l.object()
+			if !ok {
+				return dict, false
+			}
+			dict[string(name)] = value
+		}
+	case c == '<':
+		return l.hexString(), true
+	case c == '[':
+		l.pos++
+		var array pdfArray
+		for {
+			l.skipSpace()
+			if l.pos < len(l.data) && l.data[l.pos] == ']' {
+				l.pos++
+				return array, true
+			}
+			value, ok := l.object()
+			if !ok {
+				return array, false
+			}
+			array = append(array, value)
+		}
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		if c == '{' || c == '}' {
+			return pdfKeyword([]byte{c}), true // PostScript procedures in CMaps
+		}
+		return nil, false
+	case c == '+' || c == '-' || c == '.' || c >= '0' && c <= '9':
+		number := l.number()
+		// num gen R is an indirect reference
+		save := l.pos
+		if generation, ok := l.object(); ok {
+			if _, isNumber := generation.(float64); isNumber && l.keyword("R") && (l.pos >= len(l.data) || isPDFWhitespace(l.data[l.pos]) || isPDFDelimiter(l.data[l.pos])) {
+				return pdfRef(int(number)), true
+			}
+		}
+		l.pos = save
+		return number, true
+	default:
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		switch word := string(l.data[start:l.pos]); word {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		case "null":
+			return nil, true
+		default:
+			return pdfKeyword(word), true
+		}
+	}
+}
+
+func (l *pdfLexer) name() pdfName {
+	l.pos++ // /
+	var b strings.Builder
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if value, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b.WriteByte(byte(value))
+				l.pos += 3
+				continue
+			}
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+	return pdfName(b.String())
+}
+
+func (l *pdfLexer) number() float64 {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) && (l.data[l.pos] == '.' || l.data[l.pos] >= '0' && l.data[l.pos] <= '9') {
+		l.pos++
+	}
+	value, _ := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+	return value
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(b)
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return pdfString(b)
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// Line continuation
+				if e == '\r' && l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					value := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						value = value*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(value)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return pdfString(b)
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // >
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		value, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			break
+		}
+		b = append(b, byte(value))
+	}
+	return pdfString(b)
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPDF assembles a PDF from numbered object bodies; streams are Flate-compressed
+type testPDF struct {
+	objects map[int]string
+	streams map[int]string // Stream data by object number (its dictionary is generated)
+	root    int
+}
+
+func (p testPDF) bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.5\n")
+	size := 0
+	for num := range p.objects {
+		size = max(size, num)
+	}
+	for num := range p.streams {
+		size = max(size, num)
+	}
+	for num := 1; num <= size; num++ {
+		if body, ok := p.objects[num]; ok {
+			fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", num, body)
+			continue
+		}
+		data, ok := p.streams[num]
+		if !ok {
+			continue
+		}
+		var compressed bytes.Buffer
+		w := zlib.NewWriter(&compressed)
+		w.Write([]byte(data))
+		w.Close()
+		fmt.Fprintf(&b, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", num, compressed.Len())
+		b.Write(compressed.Bytes())
+		b.WriteString("\nendstream\nendobj\n")
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\n%%%%EOF\n", size+1, p.root)
+	return b.Bytes()
+}
+
+func simplePDF(pageTexts ...string) []byte {
+	pdf := testPDF{
+		objects: map[int]string{
+			1: "<< /Type /Catalog /Pages 2 0 R >>",
+			3: "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		},
+		streams: map[int]string{},
+		root:    1,
+	}
+	var kids []string
+	for i, text := range pageTexts {
+		page, content := 4+2*i, 5+2*i
+		pdf.objects[page] = fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", content)
+		pdf.streams[content] = text
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	// Fonts are inherited from the page tree
+	pdf.objects[2] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 3 0 R >> >> >>", strings.Join(kids, " "), len(pageTexts))
+	return pdf.bytes()
+}
+
+func TestExtractPDFText(t *testing.T) {
+	data := simplePDF(
+		`BT /F1 12 Tf 72 720 Td (Hello PDF world) Tj 0 -14 Td [(Second) -250 (line \(escaped\))] TJ ET`,
+		`BT /F1 12 Tf 72 720 Td (Page two) Tj ET`,
+		`BT /F1 12 Tf 72 720 Td (Page three) Tj ET`,
+	)
+
+	pages, err := extractPDFText(data, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pages) != 3 || pages[0] != "Hello PDF world\nSecond line (escaped)" || pages[1] != "Page two" {
+		t.Errorf("Unexpected pages %q", pages)
+	}
+
+	if pages, _ := extractPDFText(data, 2); len(pages) != 2 {
+		t.Errorf("Expected the page limit to stop after 2 pages, got %d", len(pages))
+	}
+}
+
+func TestExtractPDFText_ToUnicodeAndObjectStreams(t *testing.T) {
+	// A CID font whose 2-byte codes map to 日本語の資料 through its ToUnicode CMap
+	cmap := `/CIDInit /ProcSet findresource begin 12 dict begin begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0001> <65E5> <0002> <672C> endbfchar
+1 beginbfrange <0003> <0004> <8A9E> endbfrange
+1 beginbfrange <0005> <0006> [<306E> <8CC7>] endbfrange
+endcmap end end`
+	// The catalog, page tree, page and font live in an object stream, as written by PDF 1.5+ tools
+	objStm := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /HeiseiKakuGo-W5 /ToUnicode 5 0 R >>",
+	}
+	var header, body strings.Builder
+	for i, obj := range objStm {
+		fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+		body.WriteString(obj + "\n")
+	}
+
+	pdf := testPDF{
+		objects: map[int]string{},
+		streams: map[int]string{
+			5: cmap,
+			6: `BT /F1 10.5 Tf 1 0 0 1 72 720 Tm <000100020003> Tj 1 0 0 1 72 700 Tm <00050006> Tj ET`,
+			7: header.String() + body.String(),
+		},
+		root: 1,
+	}
+	data := pdf.bytes()
+	// Declare object 7 as the object stream
+	data = bytes.Replace(data, []byte("7 0 obj\n<< "), []byte(fmt.Sprintf("7 0 obj\n<< /Type /ObjStm /N %d /First %d ", len(objStm), header.Len())), 1)
+
+	pages, err := extractPDFText(data, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pages) != 1 || pages[0] != "日本語\nの資" {
+		t.Errorf("Unexpected pages %q", pages)
+	}
+}
+
+func TestExtractPDFText_NoText(t *testing.T) {
+	if _, err := extractPDFText(simplePDF(`q 100 0 0 100 0 0 cm /Im1 Do Q`), 10); err != ErrPDFNoText {
+		t.Errorf("Expected ErrPDFNoText for an image-only PDF, got %v", err)
+	}
+	if _, err := extractPDFText([]byte("%PDF-1.4\ngarbage"), 10); err == nil {
+		t.Error("Expected an error for a PDF without catalog")
+	}
+}
+
+func TestGeminiRepository_FetchHTML_PDF(t *testing.T) {
+	data := simplePDF(`BT /F1 12 Tf 72 720 Td (Whitepaper text about distributed consensus.) Tj ET`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{httpClient: &http.Client{Timeout: 5 * time.Second}}
+	page, err := repo.fetchHTML(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text, method := repo.extractTextFromPages(context.Background(), server.URL, []string{page})
+	if text != "Whitepaper text about distributed consensus." || method != ExtractionPDF {
+		t.Errorf("Unexpected extraction %q (%s)", text, method)
+	}
+
+	// Over the size cap the page is empty, so the feed description is summarized instead
+	repo.maxPDFBytes = 100
+	if page, err := repo.fetchHTML(context.Background(), server.URL); err != nil || page != "" {
+		t.Errorf("Expected an empty page for an oversized PDF, got %q, %v", page, err)
+	}
+}
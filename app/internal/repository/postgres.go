@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// postgresDriverName is the database/sql driver registered by postgres_driver.go (built with -tags postgres)
+const postgresDriverName = "pgx"
+
+// postgresMigrationLock is the advisory lock key serializing migrations across instances
+const postgresMigrationLock = 4_060_312
+
+// postgresMigrations are applied in order, each once, and recorded in schema_migrations;
+// append new ones instead of editing applied ones
+var postgresMigrations = []string{
+	// 1: processed articles keyed by normalized URL
+	`CREATE TABLE processed_articles (
+		url            TEXT PRIMARY KEY,
+		title          TEXT NOT NULL,
+		source         TEXT NOT NULL,
+		pub_date       TIMESTAMPTZ,
+		processed_date TIMESTAMPTZ NOT NULL,
+		prompt_variant TEXT NOT NULL DEFAULT '',
+		provenance     JSONB
+	)`,
+	// 2: reporting by processing date
+	`CREATE INDEX processed_articles_processed_date_idx ON processed_articles (processed_date)`,
+}
+
+// postgresProcessedRepository keeps one row per processed article in PostgreSQL, for larger teams
+// sharing an index and for ad-hoc SQL reporting over processed articles
+type postgresProcessedRepository struct {
+	db *sql.DB
+}
+
+// newPostgresProcessedRepository connects to DATABASE_URL and migrates the schema
+func newPostgresProcessedRepository(ctx context.Context) (*postgresProcessedRepository, error) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		return nil, errors.New("DATABASE_URL is required when CACHE_TYPE=postgres")
+	}
+	return openPostgresProcessedRepository(ctx, dsn)
+}
+
+func openPostgresProcessedRepository(ctx context.Context, dsn string) (*postgresProcessedRepository, error) {
+	if !slices.Contains(sql.Drivers(), postgresDriverName) {
+		return nil, fmt.Errorf("CACHE_TYPE=%s requires a binary built with -tags postgres", CacheTypePostgres)
+	}
+	db, err := sql.Open(postgresDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening PostgreSQL database: %w", err)
+	}
+	db.SetMaxOpenConns(10)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+	if err := migratePostgres(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &postgresProcessedRepository{db: db}, nil
+}
+
+// migratePostgres applies the migrations not yet recorded, under an advisory lock so instances
+// starting together do not race
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresMigrationLock); err != nil {
+		return fmt.Errorf("locking migrations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	var applied int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&applied); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	for version := applied + 1; version <= len(postgresMigrations); version++ {
+		if _, err := tx.ExecContext(ctx, postgresMigrations[version-1]); err != nil {
+			return fmt.Errorf("applying migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			return fmt.Errorf("recording migration %d: %w", version, err)
+		}
+		logger.Printf("Applied PostgreSQL migration version=%d", version)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing migrations: %w", err)
+	}
+	return nil
+}
+
+// LoadIndex reads every row
+func (r *postgresProcessedRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	rows, err := r.db.QueryContext(ctx, `SELECT url, title, source, pub_date, processed_date, prompt_variant, provenance FROM processed_articles`)
+	if err != nil {
+		logger.Printf("Error reading processed articles: %v", err)
+		return nil, fmt.Errorf("reading processed articles: %w", err)
+	}
+	defer rows.Close()
+
+	index := make(map[string]*IndexEntry)
+	for rows.Next() {
+		var entry IndexEntry
+		var pubDate sql.NullTime
+		var provenance []byte
+		if err := rows.Scan(&entry.URL, &entry.Title, &entry.Source, &pubDate, &entry.ProcessedDate, &entry.PromptVariant, &provenance); err != nil {
+			return nil, fmt.Errorf("reading processed article: %w", err)
+		}
+		entry.PubDate = pubDate.Time
+		if len(provenance) > 0 {
+			entry.Provenance = &Provenance{}
+			if err := json.Unmarshal(provenance, entry.Provenance); err != nil {
+				entry.Provenance = nil
+			}
+		}
+		index[entry.URL] = &entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading processed articles: %w", err)
+	}
+	return index, nil
+}
+
+// IsProcessed checks if an article is already processed using the startup index
+func (r *postgresProcessedRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	return exists
+}
+
+// MarkAsProcessed creates or replaces the article's row
+func (r *postgresProcessedRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO processed_articles
+		(url, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (url) DO UPDATE SET title = EXCLUDED.title, source = EXCLUDED.source, pub_date = EXCLUDED.pub_date,
+			processed_date = EXCLUDED.processed_date, prompt_variant = EXCLUDED.prompt_variant, provenance = EXCLUDED.provenance`,
+		r.rowValues(article, Now(ctx))...)
+	if err != nil {
+		return fmt.Errorf("writing processed article: %w", err)
+	}
+	return nil
+}
+
+// MarkManyAsProcessed inserts rows in one transaction, keeping existing ones as they are
+func (r *postgresProcessedRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := Now(ctx)
+	added := 0
+	for _, article := range articles {
+		if r.GenerateKey(article) == "" {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, `INSERT INTO processed_articles
+			(url, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (url) DO NOTHING`,
+			r.rowValues(article, now)...)
+		if err != nil {
+			return 0, fmt.Errorf("writing processed article: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing processed articles: %w", err)
+	}
+	return added, nil
+}
+
+// ExportEntries lists the whole index (LoadIndex already returns every entry)
+func (r *postgresProcessedRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntries(index), nil
+}
+
+// ImportEntries inserts the entries with their own processed dates, keeping existing rows as they are
+func (r *postgresProcessedRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, entry := range entries {
+		article := entry.item()
+		if r.GenerateKey(article) == "" {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, `INSERT INTO processed_articles
+			(url, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (url) DO NOTHING`,
+			r.rowValues(article, entry.ProcessedDate)...)
+		if err != nil {
+			return 0, fmt.Errorf("writing processed article: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing processed articles: %w", err)
+	}
+	return added, nil
+}
+
+// UnmarkProcessed deletes the article's row; reports whether it was present
+func (r *postgresProcessedRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM processed_articles WHERE url = $1`, r.GenerateKey(article))
+	if err != nil {
+		return false, fmt.Errorf("deleting processed article: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Prune deletes the rows processed before cutoff
+func (r *postgresProcessedRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM processed_articles WHERE processed_date < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("pruning processed articles: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// GenerateKey generates a key for an article
+func (r *postgresProcessedRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// Flush is a no-op: each mark is written immediately
+func (r *postgresProcessedRepository) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (r *postgresProcessedRepository) Close() error {
+	return r.db.Close()
+}
+
+// rowValues are the column values of an article's row
+func (r *postgresProcessedRepository) rowValues(article Item, processedAt time.Time) []any {
+	var pubDate sql.NullTime
+	if !article.ParsedDate.IsZero() {
+		pubDate = sql.NullTime{Time: article.ParsedDate, Valid: true}
+	}
+	var provenance any // NULL without provenance
+	if article.Provenance != nil {
+		if data, err := json.Marshal(article.Provenance); err == nil {
+			provenance = string(data)
+		}
+	}
+	return []any{
+		r.GenerateKey(article), // Normalized URL
+		article.Title,
+		article.Source,
+		pubDate,
+		processedAt,
+		article.PromptVariant,
+		provenance,
+	}
+}
@@ -0,0 +1,6 @@
+//go:build postgres
+
+package repository
+
+// Registers the pgx driver as "pgx"; only linked into binaries built with -tags postgres
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestPostgresProcessedRepository runs against POSTGRES_TEST_URL (a scratch database) with -tags postgres
+func TestPostgresProcessedRepository(t *testing.T) {
+	ctx := context.Background()
+	if !slices.Contains(sql.Drivers(), postgresDriverName) {
+		if _, err := openPostgresProcessedRepository(ctx, "postgres://localhost/test"); err == nil {
+			t.Fatal("Expected an error without the PostgreSQL driver")
+		}
+		t.Skip("PostgreSQL driver not built in (go test -tags postgres)")
+	}
+	dsn := os.Getenv("POSTGRES_TEST_URL")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_URL not set")
+	}
+
+	repo, err := openPostgresProcessedRepository(ctx, dsn)
+	if err != nil {
+		t.Fatalf("Opening failed: %v", err)
+	}
+	defer repo.Close()
+	if _, err := repo.db.ExecContext(ctx, `TRUNCATE processed_articles`); err != nil {
+		t.Fatalf("Truncating failed: %v", err)
+	}
+	// Migrations are applied once
+	if err := migratePostgres(ctx, repo.db); err != nil {
+		t.Fatalf("Re-running migrations failed: %v", err)
+	}
+
+	article := Item{
+		Title:      "Go 1.23",
+		Link:       "https://www.example.com/go/?utm_source=feed",
+		Source:     "hatena",
+		ParsedDate: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC),
+		Provenance: &Provenance{Provider: "gemini", Model: "gemini-2.5-flash", Prompt: "rss:default@v1", Extraction: "html"},
+	}
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	added, err := repo.MarkManyAsProcessed(ctx, []Item{
+		{Title: "Go 1.23", Link: "https://example.com/go"}, // Already marked
+		{Title: "Rust 1.80", Link: "https://example.com/rust"},
+		{Title: "Rust 1.80", Link: "https://example.com/rust?ref=dup"},
+	})
+	if err != nil {
+		t.Fatalf("MarkManyAsProcessed failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d", added)
+	}
+
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(index))
+	}
+	entry := index[repo.GenerateKey(article)]
+	if entry == nil || entry.Title != "Go 1.23" || !entry.PubDate.Equal(article.ParsedDate) {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	if entry.Provenance == nil || entry.Provenance.Model != "gemini-2.5-flash" {
+		t.Errorf("Expected the provenance to round-trip, got %+v", entry.Provenance)
+	}
+
+	removed, err := repo.UnmarkProcessed(ctx, article)
+	if err != nil || !removed {
+		t.Fatalf("Expected the article to be unmarked, got %t, %v", removed, err)
+	}
+	removed, err = repo.UnmarkProcessed(ctx, article)
+	if err != nil || removed {
+		t.Errorf("Expected a second unmark to report absence, got %t, %v", removed, err)
+	}
+
+	// The remaining article was just processed: pruning keeps it until the cutoff passes it
+	if pruned, err := repo.Prune(ctx, time.Now().Add(-time.Hour)); err != nil || pruned != 0 {
+		t.Errorf("Expected nothing to prune, got %d, %v", pruned, err)
+	}
+	if pruned, err := repo.Prune(ctx, time.Now().Add(time.Hour)); err != nil || pruned != 1 {
+		t.Errorf("Expected the remaining article to be pruned, got %d, %v", pruned, err)
+	}
+}
@@ -0,0 +1,968 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+// IndexEntry represents a processed item in the index
+type IndexEntry struct {
+	Title         string    `json:"title"`
+	URL           string    `json:"url"`
+	Source        string    `json:"source"`
+	PubDate       time.Time `json:"pub_date"`
+	ProcessedDate time.Time `json:"processed_date"`
+	PromptVariant string    `json:"prompt_variant,omitempty"`
+	// Provenance is the model, prompt and extraction of the summary (absent in older entries)
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// item is the article an entry records, for backends that store articles
+func (e *IndexEntry) item() Item {
+	return Item{
+		Title:         e.Title,
+		Link:          e.URL,
+		Source:        e.Source,
+		ParsedDate:    e.PubDate,
+		PromptVariant: e.PromptVariant,
+		Provenance:    e.Provenance,
+	}
+}
+
+// ProcessedArticleRepository manages processed articles index for Cloud Function
+type ProcessedArticleRepository interface {
+	LoadIndex(ctx context.Context) (map[string]*IndexEntry, error)
+	IsProcessed(key string, index map[string]*IndexEntry) bool
+	MarkAsProcessed(ctx context.Context, article Item) error
+	MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error)
+	// ExportEntries returns every stored entry, including the ones LoadIndex leaves out (older
+	// shards), for backups and migrations
+	ExportEntries(ctx context.Context) ([]*IndexEntry, error)
+	// ImportEntries restores exported entries as they are (processed dates included), keeping
+	// entries already in the index; returns how many were added
+	ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error)
+	UnmarkProcessed(ctx context.Context, article Item) (bool, error)
+	// Prune removes the entries processed before cutoff (retention); returns how many were removed
+	Prune(ctx context.Context, cutoff time.Time) (int, error)
+	GenerateKey(article Item) string
+	// Flush persists marks buffered by MarkAsProcessed; call it at the end of a run
+	Flush(ctx context.Context) error
+	Close() error
+}
+
+type processedIndexRepository struct {
+	storage   Storage
+	indexFile string
+	mu        sync.Mutex // serializes index read-modify-write for concurrent article workers
+
+	// pending are marks not yet written to the index; they are written together on Flush or at a
+	// checkpoint, instead of uploading the whole index once per article
+	pending            map[string]*IndexEntry
+	checkpointArticles int           // Write once this many marks are pending
+	checkpointInterval time.Duration // Write once the oldest pending mark is this old
+	pendingSince       time.Time
+	// wal records the pending marks so a crash before the write does not repost the articles (nil: disabled)
+	wal      *WriteAheadLog
+	replayed bool // Marks left in wal by crashed instances were written
+	// retention drops entries processed longer ago when the index is loaded (0 keeps them forever)
+	retention time.Duration
+	// shardMonths splits the index into one object per month of processing (<index>/YYYY-MM.json)
+	// and loads only the latest shardMonths of them for dedup (0: one object holds the whole index)
+	shardMonths int
+	migrated    bool // The monolithic index was split into shards
+}
+
+// StorageIndexStats reports how the storage-backed processed index was used since the process started
+type StorageIndexStats struct {
+	Hits      int64     `json:"hits"`   // Articles found processed
+	Misses    int64     `json:"misses"` // Articles found new
+	Writes    int64     `json:"writes"` // Index objects written (one per shard of a flush)
+	LastFlush time.Time `json:"last_flush"`
+}
+
+// Usage counters of every storage-backed index of the process: the repository is created per
+// request, so its own fields would only ever count one run
+var (
+	processedIndexHits, processedIndexMisses atomic.Int64
+	processedIndexWrites                     atomic.Int64
+	processedIndexLastFlush                  atomic.Int64 // Unix nanoseconds (0: never flushed)
+)
+
+// ProcessedIndexStats returns the storage-backed processed index's usage since the process started
+func ProcessedIndexStats() StorageIndexStats {
+	stats := StorageIndexStats{
+		Hits:   processedIndexHits.Load(),
+		Misses: processedIndexMisses.Load(),
+		Writes: processedIndexWrites.Load(),
+	}
+	if lastFlush := processedIndexLastFlush.Load(); lastFlush != 0 {
+		stats.LastFlush = time.Unix(0, lastFlush).UTC()
+	}
+	return stats
+}
+
+const (
+	defaultIndexFileName = "index-v2.json"
+
+	defaultCheckpointArticles = 20
+	defaultCheckpointInterval = time.Minute
+)
+
+// NewProcessedArticleRepository creates the processed article repository selected by CACHE_TYPE:
+// "storage" (default) keeps the index in one object of the shared storage, "firestore" one
+// Firestore document per article (see newFirestoreProcessedRepository), "sqlite" one row per
+// article in a local database file (see newSQLiteProcessedRepository), "postgres" one row per
+// article in PostgreSQL (see newPostgresProcessedRepository) and "dynamodb" one item per article
+// in a DynamoDB table (see newDynamoDBProcessedRepository)
+func NewProcessedArticleRepository() (ProcessedArticleRepository, error) {
+	return NewProcessedArticleRepositoryFor(os.Getenv("CACHE_TYPE"))
+}
+
+// NewProcessedArticleRepositoryFor creates the processed article repository of a cache type,
+// regardless of CACHE_TYPE (cli migrate-cache opens two backends); "" is the storage backend
+func NewProcessedArticleRepositoryFor(cacheType string) (ProcessedArticleRepository, error) {
+	switch cacheType {
+	case "", CacheTypeStorage:
+	case CacheTypeFirestore:
+		return newFirestoreProcessedRepository()
+	case CacheTypeSQLite:
+		return newSQLiteProcessedRepository()
+	case CacheTypePostgres:
+		return newPostgresProcessedRepository(context.Background())
+	case CacheTypeDynamoDB:
+		return newDynamoDBProcessedRepository()
+	default:
+		return nil, fmt.Errorf("unknown cache type %q (expected %s, %s, %s, %s or %s)", cacheType, CacheTypeStorage, CacheTypeFirestore, CacheTypeSQLite, CacheTypePostgres, CacheTypeDynamoDB)
+	}
+
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get index file name from environment (for testing)
+	indexFileName := defaultIndexFileName
+	if env := os.Getenv("CACHE_INDEX_FILE"); env != "" {
+		indexFileName = env
+	}
+
+	repo := newProcessedIndexRepository(store, indexFileName)
+	// Checkpoints bound what a crashed run loses (those articles are summarized again next run)
+	if n, err := strconv.Atoi(os.Getenv("PROCESSED_CHECKPOINT_ARTICLES")); err == nil && n > 0 {
+		repo.checkpointArticles = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("PROCESSED_CHECKPOINT_SECONDS")); err == nil && n > 0 {
+		repo.checkpointInterval = time.Duration(n) * time.Second
+	}
+	if n, err := strconv.Atoi(os.Getenv("PROCESSED_RETENTION_DAYS")); err == nil && n > 0 {
+		repo.retention = time.Duration(n) * 24 * time.Hour
+	}
+	if n, err := strconv.Atoi(os.Getenv("PROCESSED_INDEX_SHARD_MONTHS")); err == nil && n > 0 {
+		repo.shardMonths = n
+	}
+	if os.Getenv("WAL_ENABLED") == "true" {
+		walStore := store
+		if dir := os.Getenv("WAL_DIR"); dir != "" {
+			if walStore, err = NewLocalStorage(dir); err != nil {
+				store.Close()
+				return nil, err
+			}
+		}
+		repo.wal = NewWriteAheadLog(walStore, "processed")
+	}
+	return repo, nil
+}
+
+func newProcessedIndexRepository(store Storage, indexFile string) *processedIndexRepository {
+	return &processedIndexRepository{
+		storage:            store,
+		indexFile:          indexFile,
+		pending:            make(map[string]*IndexEntry),
+		checkpointArticles: defaultCheckpointArticles,
+		checkpointInterval: defaultCheckpointInterval,
+	}
+}
+
+// LoadIndex loads the index from storage, including marks not yet flushed. With a retention
+// window, entries older than it are pruned from the stored index first. A sharded index loads
+// only its latest shards, so articles processed before them count as new again.
+func (g *processedIndexRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	if !g.replayed {
+		g.replayLocked(ctx)
+	}
+	index, err := g.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if g.retention > 0 && pruneIndex(index, Now(ctx).Add(-g.retention)) > 0 {
+		// Stale entries are left out either way; a failed write is retried on the next load
+		if removed, err := g.pruneLocked(ctx, Now(ctx).Add(-g.retention)); err != nil {
+			logger.Printf("Warning: Failed to prune processed index: %v", err)
+		} else {
+			logger.Printf("Processed index pruned removed=%d retention=%s", removed, g.retention)
+		}
+	}
+	for key, entry := range g.pending {
+		index[key] = entry
+	}
+	return index, nil
+}
+
+// replayLocked writes the marks crashed instances left in the WAL; g.mu must be held. A failure
+// is retried by the next LoadIndex.
+func (g *processedIndexRepository) replayLocked(ctx context.Context) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	entries, err := g.wal.replay(ctx, 0) // Writing a live instance's marks early is harmless
+	if err != nil {
+		logger.Printf("Warning: Failed to replay processed WAL: %v", err)
+		return
+	}
+	for _, walEntry := range entries {
+		var entry IndexEntry
+		if err := json.Unmarshal(walEntry.data, &entry); err != nil || entry.URL == "" {
+			continue
+		}
+		if _, exists := g.pending[entry.URL]; !exists {
+			g.pending[entry.URL] = &entry
+		}
+	}
+	if len(entries) > 0 {
+		if err := g.flushLocked(ctx); err != nil {
+			logger.Printf("Warning: Failed to write replayed processed marks: %v", err)
+			return
+		}
+		if err := g.wal.discardEntries(ctx, entries); err != nil {
+			logger.Printf("Warning: Failed to discard replayed processed WAL: %v", err)
+		}
+		logger.Printf("Replayed processed marks from WAL count=%d", len(entries))
+	}
+	g.replayed = true
+}
+
+// readIndex reads the index as stored (the latest shards of a sharded index, merged)
+func (g *processedIndexRepository) readIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	if g.shardMonths == 0 {
+		return g.readIndexObject(ctx, g.indexFile)
+	}
+	index := make(map[string]*IndexEntry)
+	for _, name := range g.recentShards(Now(ctx)) {
+		shard, err := g.readIndexObject(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for key, entry := range shard {
+			index[key] = entry
+		}
+	}
+	return index, nil
+}
+
+// readIndexObject reads one index object (the whole index, or a shard)
+func (g *processedIndexRepository) readIndexObject(ctx context.Context, name string) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := g.storage.Read(ctx, name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Index doesn't exist yet, return empty index
+			return make(map[string]*IndexEntry), nil
+		}
+		logger.Printf("Error reading index data: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("reading index data: %w", err)
+	}
+
+	var index map[string]*IndexEntry
+	if err := json.Unmarshal(data, &index); err != nil {
+		logger.Printf("Error unmarshaling index: %v", err)
+		return nil, fmt.Errorf("unmarshaling index: %w", err)
+	}
+	if index == nil {
+		index = make(map[string]*IndexEntry)
+	}
+
+	return index, nil
+}
+
+// maxIndexUpdateAttempts bounds the re-reads when concurrent invocations keep changing the index
+const maxIndexUpdateAttempts = 5
+
+// saveIndex saves an index object to storage
+func (g *processedIndexRepository) saveIndex(ctx context.Context, name string, index map[string]*IndexEntry) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	data, err := json.Marshal(index)
+	if err != nil {
+		logger.Printf("Error marshaling index: %v", err)
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+
+	if err := g.storage.Write(ctx, name, data, "application/json"); err != nil {
+		logger.Printf("Error writing index data: %v\nStack:\n%s", err, debug.Stack())
+		return fmt.Errorf("writing index data: %w", err)
+	}
+	processedIndexWrites.Add(1)
+
+	return nil
+}
+
+// updateIndex applies mutate to the latest content of the index object name (the index, or a
+// shard) and saves it when mutate reports a change. The mutex only covers this instance; on
+// versioned storage (GCS) the save is also conditional on the generation that was read, and a
+// concurrent invocation's write makes it re-read and re-apply mutate, so no processed entry is lost.
+func (g *processedIndexRepository) updateIndex(ctx context.Context, name string, mutate func(index map[string]*IndexEntry) bool) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	versioned, ok := g.storage.(VersionedStorage)
+	if !ok {
+		index, err := g.readIndexObject(ctx, name)
+		if err != nil {
+			return fmt.Errorf("loading latest index: %w", err)
+		}
+		if !mutate(index) {
+			return nil
+		}
+		return g.saveIndex(ctx, name, index)
+	}
+
+	for attempt := 1; ; attempt++ {
+		index, generation, err := g.loadVersionedIndex(ctx, versioned, name)
+		if err != nil {
+			return fmt.Errorf("loading latest index: %w", err)
+		}
+		if !mutate(index) {
+			return nil
+		}
+		data, err := json.Marshal(index)
+		if err != nil {
+			return fmt.Errorf("marshaling index: %w", err)
+		}
+
+		err = versioned.WriteIfGeneration(ctx, name, data, "application/json", generation)
+		if !errors.Is(err, ErrVersionConflict) {
+			if err != nil {
+				logger.Printf("Error writing index data: %v\nStack:\n%s", err, debug.Stack())
+				return fmt.Errorf("writing index data: %w", err)
+			}
+			processedIndexWrites.Add(1)
+			return nil
+		}
+		if attempt == maxIndexUpdateAttempts {
+			return fmt.Errorf("writing index data: %w (%d attempts)", err, attempt)
+		}
+		logger.Printf("Index changed concurrently, retrying update attempt=%d", attempt)
+		// Jittered so invocations that collided do not collide again
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt)*50*time.Millisecond + rand.N(50*time.Millisecond)):
+		}
+	}
+}
+
+// loadVersionedIndex reads an index object with its generation (0 while it does not exist)
+func (g *processedIndexRepository) loadVersionedIndex(ctx context.Context, versioned VersionedStorage, name string) (map[string]*IndexEntry, int64, error) {
+	data, generation, err := versioned.ReadVersioned(ctx, name)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]*IndexEntry), 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading index data: %w", err)
+	}
+	var index map[string]*IndexEntry
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, 0, fmt.Errorf("unmarshaling index: %w", err)
+	}
+	if index == nil {
+		index = make(map[string]*IndexEntry)
+	}
+	return index, generation, nil
+}
+
+// IsProcessed checks if an article is already processed using the startup index
+func (g *processedIndexRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	if exists {
+		processedIndexHits.Add(1)
+	} else {
+		processedIndexMisses.Add(1)
+	}
+	return exists
+}
+
+// MarkAsProcessed marks an article as processed. The mark is buffered and written with the
+// others at the next checkpoint or Flush.
+func (g *processedIndexRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := g.GenerateKey(article)
+	entry := &IndexEntry{
+		Title:         article.Title,
+		URL:           key, // Normalized URL
+		Source:        article.Source,
+		PubDate:       article.ParsedDate,
+		ProcessedDate: Now(ctx),
+		PromptVariant: article.PromptVariant,
+		Provenance:    article.Provenance,
+	}
+	logged := true
+	if err := g.wal.Append(ctx, entry); err != nil {
+		// Not crash-safe while buffered, so written at once
+		logger.Printf("Warning: Failed to record processed mark in WAL: %v", err)
+		logged = false
+	}
+	if len(g.pending) == 0 {
+		g.pendingSince = time.Now()
+	}
+	g.pending[key] = entry
+	if logged && len(g.pending) < g.checkpointArticles && time.Since(g.pendingSince) < g.checkpointInterval {
+		return nil
+	}
+	if err := g.flushLocked(ctx); err != nil {
+		// Kept pending; written with the next checkpoint or Flush
+		logger.Printf("Error updating index for marking processed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Flush writes the buffered marks to the index in one update
+func (g *processedIndexRepository) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.flushLocked(ctx)
+}
+
+// flushLocked writes the pending marks; g.mu must be held. On failure they stay pending.
+func (g *processedIndexRepository) flushLocked(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if len(g.pending) == 0 {
+		return nil
+	}
+	// Each shard gets the marks of its month (all of them go to the current shard, unless replayed)
+	objects := make(map[string]map[string]*IndexEntry)
+	for key, entry := range g.pending {
+		name := g.objectFor(entry)
+		if objects[name] == nil {
+			objects[name] = make(map[string]*IndexEntry)
+		}
+		objects[name][key] = entry
+	}
+	for name, entries := range objects {
+		err := g.updateIndex(ctx, name, func(index map[string]*IndexEntry) bool {
+			for key, entry := range entries {
+				index[key] = entry
+			}
+			return true
+		})
+		if err != nil {
+			// Shards already written are written again with the rest, which is harmless
+			return fmt.Errorf("flushing %d processed marks: %w", len(g.pending), err)
+		}
+	}
+	clear(g.pending)
+	processedIndexLastFlush.Store(Now(ctx).UnixNano())
+	if err := g.wal.Commit(ctx); err != nil {
+		// Left entries are replayed later, which rewrites the same marks
+		logger.Printf("Warning: Failed to commit processed WAL: %v", err)
+	}
+	return nil
+}
+
+// MarkManyAsProcessed marks articles as processed in a single index update (for migrations);
+// entries already in the index are kept as is. Returns how many articles were newly marked.
+func (g *processedIndexRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	if err := g.flushLocked(ctx); err != nil {
+		logger.Printf("Error updating index for bulk marking processed: %v", err)
+		return 0, err
+	}
+	now := Now(ctx)
+	// The new entries all go to the current shard; the others are only checked for existing ones
+	existing := make(map[string]bool)
+	if g.shardMonths > 0 {
+		keys, err := g.shardKeys(ctx, g.shardName(now))
+		if err != nil {
+			logger.Printf("Error updating index for bulk marking processed: %v", err)
+			return 0, err
+		}
+		existing = keys
+	}
+	added := 0
+	err := g.updateIndex(ctx, g.objectFor(&IndexEntry{ProcessedDate: now}), func(index map[string]*IndexEntry) bool {
+		added = 0 // Counted again on each attempt
+		for _, article := range articles {
+			key := g.GenerateKey(article)
+			if key == "" {
+				continue
+			}
+			if _, exists := index[key]; exists || existing[key] {
+				continue
+			}
+			index[key] = &IndexEntry{
+				Title:         article.Title,
+				URL:           key, // Normalized URL
+				Source:        article.Source,
+				PubDate:       article.ParsedDate,
+				ProcessedDate: now,
+			}
+			added++
+		}
+		return added > 0
+	})
+	if err != nil {
+		logger.Printf("Error updating index for bulk marking processed: %v", err)
+		return 0, err
+	}
+	return added, nil
+}
+
+// ExportEntries reads every index object, after writing the pending marks
+func (g *processedIndexRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	if err := g.flushLocked(ctx); err != nil {
+		logger.Printf("Error flushing processed marks for export: %v", err)
+		return nil, err
+	}
+	names, err := g.indexObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]*IndexEntry)
+	for _, name := range names {
+		object, err := g.readIndexObject(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for key, entry := range object {
+			index[key] = entry
+		}
+	}
+	return indexEntries(index), nil
+}
+
+// indexEntries lists an index's entries in processing order (by URL for equal dates)
+func indexEntries(index map[string]*IndexEntry) []*IndexEntry {
+	entries := make([]*IndexEntry, 0, len(index))
+	for _, entry := range index {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].ProcessedDate.Equal(entries[j].ProcessedDate) {
+			return entries[i].ProcessedDate.Before(entries[j].ProcessedDate)
+		}
+		return entries[i].URL < entries[j].URL
+	})
+	return entries
+}
+
+// ImportEntries restores entries (e.g. from cli export-processed) in one update per index object;
+// a sharded index gets each entry in the shard of its processing month
+func (g *processedIndexRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	if err := g.flushLocked(ctx); err != nil {
+		logger.Printf("Error updating index for importing processed entries: %v", err)
+		return 0, err
+	}
+	objects := make(map[string]map[string]*IndexEntry)
+	for _, entry := range entries {
+		key := g.GenerateKey(Item{Link: entry.URL})
+		if key == "" {
+			continue
+		}
+		imported := *entry
+		imported.URL = key // Normalized URL
+		name := g.objectFor(&imported)
+		if objects[name] == nil {
+			objects[name] = make(map[string]*IndexEntry)
+		}
+		objects[name][key] = &imported
+	}
+	// An entry may already be in another shard than the one of its processing month
+	existing := make(map[string]bool)
+	if g.shardMonths > 0 && len(objects) > 0 {
+		keys, err := g.shardKeys(ctx, "")
+		if err != nil {
+			logger.Printf("Error updating index for importing processed entries: %v", err)
+			return 0, err
+		}
+		existing = keys
+	}
+
+	added := 0
+	for name, group := range objects {
+		addedToObject := 0
+		err := g.updateIndex(ctx, name, func(index map[string]*IndexEntry) bool {
+			addedToObject = 0 // Counted again on each attempt
+			for key, entry := range group {
+				if _, exists := index[key]; exists || existing[key] {
+					continue
+				}
+				index[key] = entry
+				addedToObject++
+			}
+			return addedToObject > 0
+		})
+		if err != nil {
+			logger.Printf("Error updating index for importing processed entries: %v", err)
+			return added, err
+		}
+		added += addedToObject
+	}
+	return added, nil
+}
+
+// UnmarkProcessed removes an article from the index so it is summarized again; reports whether it was present
+func (g *processedIndexRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	key := g.GenerateKey(article)
+	_, wasPending := g.pending[key]
+	delete(g.pending, key)
+	names, err := g.indexObjects(ctx)
+	if err != nil {
+		logger.Printf("Error updating index for unmarking processed: %v", err)
+		return false, err
+	}
+	removed := false
+	for _, name := range names {
+		inObject := false
+		err := g.updateIndex(ctx, name, func(index map[string]*IndexEntry) bool {
+			_, inObject = index[key]
+			delete(index, key)
+			return inObject
+		})
+		if err != nil {
+			logger.Printf("Error updating index for unmarking processed: %v", err)
+			return false, err
+		}
+		removed = removed || inObject
+	}
+	return removed || wasPending, nil
+}
+
+// Prune removes the entries processed before cutoff from the stored index
+func (g *processedIndexRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.migrateLocked(ctx)
+	removed, err := g.pruneLocked(ctx, cutoff)
+	if err != nil {
+		logger.Printf("Error updating index for pruning: %v", err)
+		return 0, err
+	}
+	logger.Printf("Processed index pruned removed=%d cutoff=%s", removed, cutoff.Format(time.RFC3339))
+	return removed, nil
+}
+
+// pruneLocked removes stale entries from the stored index; g.mu must be held. Shards of months
+// that ended before cutoff are deleted whole.
+func (g *processedIndexRepository) pruneLocked(ctx context.Context, cutoff time.Time) (int, error) {
+	names, err := g.indexObjects(ctx)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, name := range names {
+		if month, ok := g.shardMonth(name); ok && !month.AddDate(0, 1, 0).After(cutoff) {
+			shard, err := g.readIndexObject(ctx, name)
+			if err != nil {
+				return total, err
+			}
+			if err := g.storage.Delete(ctx, name); err != nil {
+				return total, fmt.Errorf("deleting index shard %s: %w", name, err)
+			}
+			total += len(shard)
+			continue
+		}
+		removed := 0
+		err := g.updateIndex(ctx, name, func(index map[string]*IndexEntry) bool {
+			removed = pruneIndex(index, cutoff) // Counted again on each attempt
+			return removed > 0
+		})
+		if err != nil {
+			return total, err
+		}
+		total += removed
+	}
+	return total, nil
+}
+
+// pruneIndex deletes the entries processed before cutoff and returns how many it deleted.
+// Entries without a processing time predate it and count as stale.
+func pruneIndex(index map[string]*IndexEntry, cutoff time.Time) int {
+	removed := 0
+	for key, entry := range index {
+		if entry == nil || entry.ProcessedDate.Before(cutoff) {
+			delete(index, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// shardPrefix is the prefix of the shard objects: the index name without its extension
+func (g *processedIndexRepository) shardPrefix() string {
+	return strings.TrimSuffix(g.indexFile, ".json") + "/"
+}
+
+// shardName is the shard of the month t falls in (e.g. index-v2/2024-05.json)
+func (g *processedIndexRepository) shardName(t time.Time) string {
+	return g.shardPrefix() + t.UTC().Format("2006-01") + ".json"
+}
+
+// shardMonth returns the first instant of a shard's month; ok is false for other objects
+func (g *processedIndexRepository) shardMonth(name string) (time.Time, bool) {
+	if g.shardMonths == 0 || !strings.HasPrefix(name, g.shardPrefix()) {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("2006-01", strings.TrimSuffix(strings.TrimPrefix(name, g.shardPrefix()), ".json"))
+	return month, err == nil
+}
+
+// recentShards names the shards loaded for dedup, oldest first (so newer entries win the merge)
+func (g *processedIndexRepository) recentShards(now time.Time) []string {
+	year, month, _ := now.UTC().Date()
+	current := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	names := make([]string, 0, g.shardMonths)
+	for i := g.shardMonths - 1; i >= 0; i-- {
+		names = append(names, g.shardName(current.AddDate(0, -i, 0)))
+	}
+	return names
+}
+
+// objectFor names the index object an entry is stored in. Entries without a processing time
+// (written before it was recorded) go to the current shard.
+func (g *processedIndexRepository) objectFor(entry *IndexEntry) string {
+	if g.shardMonths == 0 {
+		return g.indexFile
+	}
+	if entry.ProcessedDate.IsZero() {
+		return g.shardName(time.Now())
+	}
+	return g.shardName(entry.ProcessedDate)
+}
+
+// indexObjects names every stored index object: the index, or all of its shards
+func (g *processedIndexRepository) indexObjects(ctx context.Context) ([]string, error) {
+	if g.shardMonths == 0 {
+		return []string{g.indexFile}, nil
+	}
+	names, err := g.storage.List(ctx, g.shardPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("listing index shards: %w", err)
+	}
+	shards := names[:0]
+	for _, name := range names {
+		if _, ok := g.shardMonth(name); ok {
+			shards = append(shards, name)
+		}
+	}
+	return shards, nil
+}
+
+// shardKeys returns the keys stored in the shards other than except
+func (g *processedIndexRepository) shardKeys(ctx context.Context, except string) (map[string]bool, error) {
+	names, err := g.indexObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool)
+	for _, name := range names {
+		if name == except {
+			continue
+		}
+		shard, err := g.readIndexObject(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for key := range shard {
+			keys[key] = true
+		}
+	}
+	return keys, nil
+}
+
+// migrateLocked splits a monolithic index left from before sharding was enabled into shards, then
+// deletes it; g.mu must be held. A failure is logged and retried by the next call.
+func (g *processedIndexRepository) migrateLocked(ctx context.Context) {
+	if g.shardMonths == 0 || g.migrated {
+		return
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	index, err := g.readIndexObject(ctx, g.indexFile)
+	if err != nil {
+		logger.Printf("Warning: Failed to read processed index for sharding: %v", err)
+		return
+	}
+	if len(index) > 0 {
+		objects := make(map[string]map[string]*IndexEntry)
+		for key, entry := range index {
+			if entry == nil {
+				continue
+			}
+			name := g.objectFor(entry)
+			if objects[name] == nil {
+				objects[name] = make(map[string]*IndexEntry)
+			}
+			objects[name][key] = entry
+		}
+		for name, entries := range objects {
+			err := g.updateIndex(ctx, name, func(shard map[string]*IndexEntry) bool {
+				for key, entry := range entries {
+					if _, exists := shard[key]; !exists {
+						shard[key] = entry
+					}
+				}
+				return true
+			})
+			if err != nil {
+				logger.Printf("Warning: Failed to write processed index shard %s: %v", name, err)
+				return
+			}
+		}
+		if err := g.storage.Delete(ctx, g.indexFile); err != nil {
+			logger.Printf("Warning: Failed to delete sharded processed index: %v", err)
+			return
+		}
+		logger.Printf("Processed index split into shards entries=%d shards=%d", len(index), len(objects))
+	}
+	g.migrated = true
+}
+
+// GenerateKey generates a key for an article
+func (g *processedIndexRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// processedKey is the processed-index key of an article: its normalized link
+func processedKey(article Item) string {
+	// Always use Link for consistent URL-based deduplication
+	identifier := article.Link
+
+	// Normalize URL
+	normalizedURL, err := normalizeArticleURL(identifier)
+	if err != nil {
+		// Fallback to original identifier if normalization fails
+		return strings.TrimSpace(identifier)
+	}
+
+	return normalizedURL
+}
+
+// WithLegacyKeys returns index with entries recorded before AMP cache and mobile URLs were
+// canonicalized also reachable under their canonical key (https://m.example.com/a is looked up as
+// https://example.com/a), so those articles are not summarized again. The aliases are for lookups
+// only and are never stored.
+func WithLegacyKeys(index map[string]*IndexEntry) map[string]*IndexEntry {
+	aliases := make(map[string]*IndexEntry)
+	for key, entry := range index {
+		canonical, err := normalizeArticleURL(key)
+		if err != nil || canonical == key {
+			continue
+		}
+		if _, exists := index[canonical]; !exists {
+			aliases[canonical] = entry
+		}
+	}
+	if len(aliases) == 0 {
+		return index
+	}
+
+	merged := make(map[string]*IndexEntry, len(index)+len(aliases))
+	for key, entry := range index {
+		merged[key] = entry
+	}
+	for key, entry := range aliases {
+		merged[key] = entry
+	}
+	return merged
+}
+
+// Close writes marks that were not flushed and closes the storage
+func (g *processedIndexRepository) Close() error {
+	if err := g.Flush(context.Background()); err != nil {
+		g.storage.Close()
+		return err
+	}
+	return g.storage.Close()
+}
+
+// normalizeURL normalizes URL for consistent duplicate detection
+func (g *processedIndexRepository) normalizeURL(rawURL string) (string, error) {
+	return normalizeArticleURL(rawURL)
+}
+
+func normalizeArticleURL(rawURL string) (string, error) {
+	// 0. Rewrite AMP/mobile variants to the canonical desktop URL
+	parsedURL, err := url.Parse(CanonicalizeURL(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("parsing URL: %w", err)
+	}
+
+	// 1. Force HTTPS protocol
+	if parsedURL.Scheme == "http" {
+		parsedURL.Scheme = "https"
+	}
+
+	// 2. Remove www subdomain
+	if strings.HasPrefix(parsedURL.Host, "www.") {
+		parsedURL.Host = parsedURL.Host[4:]
+	}
+
+	// 3. Remove query parameters and fragments
+	parsedURL.RawQuery = ""
+	parsedURL.Fragment = ""
+
+	// 4. Remove trailing slash from path (except root)
+	if parsedURL.Path != "/" && strings.HasSuffix(parsedURL.Path, "/") {
+		parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/")
+	}
+
+	// 5. Convert host to lowercase
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+
+	// 6. Convert path to lowercase for case-insensitive comparison
+	parsedURL.Path = strings.ToLower(parsedURL.Path)
+
+	return parsedURL.String(), nil
+}
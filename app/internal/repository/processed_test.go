@@ -0,0 +1,662 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProcessedIndexRepository_GenerateKey(t *testing.T) {
+	repo := &processedIndexRepository{}
+
+	tests := []struct {
+		name     string
+		article  Item
+		expected string
+	}{
+		{
+			name: "URL with GUID",
+			article: Item{
+				GUID: "https://example.com/article/123",
+				Link: "https://example.com/article/123?utm_source=test",
+			},
+			expected: "https://example.com/article/123",
+		},
+		{
+			name: "URL without GUID",
+			article: Item{
+				GUID: "",
+				Link: "https://example.com/article/456?param=value",
+			},
+			expected: "https://example.com/article/456",
+		},
+		{
+			name: "URL with fragment",
+			article: Item{
+				GUID: "",
+				Link: "https://example.com/article/789#section1",
+			},
+			expected: "https://example.com/article/789",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := repo.GenerateKey(tt.article)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestProcessedIndexRepository_IsProcessed(t *testing.T) {
+	repo := &processedIndexRepository{}
+
+	// テスト用インデックス作成
+	index := map[string]*IndexEntry{
+		"https://example.com/processed": {
+			Title:         "Processed Article",
+			URL:           "https://example.com/processed",
+			Source:        "test",
+			PubDate:       time.Now(),
+			ProcessedDate: time.Now(),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected bool
+	}{
+		{
+			name:     "existing article",
+			key:      "https://example.com/processed",
+			expected: true,
+		},
+		{
+			name:     "non-existing article",
+			key:      "https://example.com/new",
+			expected: false,
+		},
+		{
+			name:     "empty key",
+			key:      "",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := repo.IsProcessed(tt.key, index)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWithLegacyKeys(t *testing.T) {
+	repo := &processedIndexRepository{}
+
+	// Keys recorded before AMP cache and mobile URLs were canonicalized
+	index := map[string]*IndexEntry{
+		"https://m.example.com/news/1":                                           {URL: "https://m.example.com/news/1"},
+		"https://www-example-com.cdn.ampproject.org/c/s/www.example.com/article": {URL: "https://www-example-com.cdn.ampproject.org/c/s/www.example.com/article"},
+		"https://github.com/ampproject/amp":                                      {URL: "https://github.com/ampproject/amp"},
+	}
+
+	lookup := WithLegacyKeys(index)
+	for _, link := range []string{
+		"https://m.example.com/news/1",
+		"https://www.google.com/amp/s/www.example.com/article",
+		"https://github.com/ampproject/amp",
+	} {
+		if !repo.IsProcessed(repo.GenerateKey(Item{Link: CanonicalizeURL(link)}), lookup) {
+			t.Errorf("Expected %s to match its legacy key", link)
+		}
+	}
+	if len(index) != 3 {
+		t.Errorf("The loaded index should be left as is, got %d entries", len(index))
+	}
+	if current := map[string]*IndexEntry{"https://example.com/a": {}}; len(WithLegacyKeys(current)) != 1 {
+		t.Error("Current keys should not get aliases")
+	}
+}
+
+func TestProcessedIndexRepository_NormalizeURL(t *testing.T) {
+	repo := &processedIndexRepository{}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{
+			name:     "URL with query parameters",
+			input:    "https://example.com/article?utm_source=test&param=value",
+			expected: "https://example.com/article",
+			hasError: false,
+		},
+		{
+			name:     "URL with fragment",
+			input:    "https://example.com/article#section1",
+			expected: "https://example.com/article",
+			hasError: false,
+		},
+		{
+			name:     "URL with both query and fragment",
+			input:    "https://example.com/article?param=value#section1",
+			expected: "https://example.com/article",
+			hasError: false,
+		},
+		{
+			name:     "clean URL",
+			input:    "https://example.com/article",
+			expected: "https://example.com/article",
+			hasError: false,
+		},
+		{
+			name:     "invalid URL",
+			input:    "://invalid-url",
+			expected: "",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.normalizeURL(tt.input)
+
+			if tt.hasError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				if result != tt.expected {
+					t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessedIndexRepository_DuplicateCheckWorkflow(t *testing.T) {
+	// 重複チェックの統合ワークフローテスト
+	repo := &processedIndexRepository{}
+
+	// テスト記事
+	article1 := Item{
+		Title: "Test Article 1",
+		Link:  "https://example.com/article/1?utm_source=rss",
+		GUID:  "https://example.com/article/1",
+	}
+
+	article2 := Item{
+		Title: "Test Article 2",
+		Link:  "https://example.com/article/1?utm_source=twitter", // 同じ記事、異なるパラメータ
+		GUID:  "https://example.com/article/1",
+	}
+
+	article3 := Item{
+		Title: "Test Article 3",
+		Link:  "https://example.com/article/2",
+		GUID:  "",
+	}
+
+	// 空のインデックスから開始
+	index := make(map[string]*IndexEntry)
+
+	// 1. 記事1をチェック（未処理）
+	key1 := repo.GenerateKey(article1)
+	if repo.IsProcessed(key1, index) {
+		t.Error("Article 1 should not be processed initially")
+	}
+
+	// 2. 記事1を処理済みとしてマーク（シミュレート）
+	index[key1] = &IndexEntry{
+		Title:         article1.Title,
+		URL:           key1,
+		Source:        "test",
+		PubDate:       time.Now(),
+		ProcessedDate: time.Now(),
+	}
+
+	// 3. 記事2をチェック（同じ記事なので処理済みのはず）
+	key2 := repo.GenerateKey(article2)
+	if !repo.IsProcessed(key2, index) {
+		t.Error("Article 2 should be processed (same as article 1)")
+	}
+
+	// 4. キーが同じことを確認
+	if key1 != key2 {
+		t.Errorf("Keys should be the same: key1='%s', key2='%s'", key1, key2)
+	}
+
+	// 5. 記事3をチェック（未処理）
+	key3 := repo.GenerateKey(article3)
+	if repo.IsProcessed(key3, index) {
+		t.Error("Article 3 should not be processed")
+	}
+
+	// 6. キーが異なることを確認
+	if key1 == key3 {
+		t.Error("Key1 and Key3 should be different")
+	}
+
+	t.Logf("✅ Duplicate check workflow test passed")
+	t.Logf("Key1 (article1): %s", key1)
+	t.Logf("Key2 (article2): %s", key2)
+	t.Logf("Key3 (article3): %s", key3)
+}
+
+// versionedMemoryStorage is an in-memory VersionedStorage; beforeWrite runs before each conditional write
+type versionedMemoryStorage struct {
+	Storage
+	data        []byte
+	generation  int64
+	beforeWrite func(s *versionedMemoryStorage)
+}
+
+func (s *versionedMemoryStorage) ReadVersioned(ctx context.Context, name string) ([]byte, int64, error) {
+	if s.generation == 0 {
+		return nil, 0, os.ErrNotExist
+	}
+	return s.data, s.generation, nil
+}
+
+func (s *versionedMemoryStorage) WriteIfGeneration(ctx context.Context, name string, data []byte, contentType string, generation int64) error {
+	if s.beforeWrite != nil {
+		s.beforeWrite(s)
+	}
+	if generation != s.generation {
+		return ErrVersionConflict
+	}
+	s.data, s.generation = data, s.generation+1
+	return nil
+}
+
+func (s *versionedMemoryStorage) Read(ctx context.Context, name string) ([]byte, error) {
+	data, _, err := s.ReadVersioned(ctx, name)
+	return data, err
+}
+
+func (s *versionedMemoryStorage) Close() error {
+	return nil
+}
+
+func TestProcessedIndexRepository_ConcurrentWriteRetries(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	// Another invocation marks an article between our read and our write, once
+	concurrent := newProcessedIndexRepository(&versionedMemoryStorage{}, defaultIndexFileName)
+	concurrent.checkpointArticles = 1 // Write each mark immediately
+	store.beforeWrite = func(s *versionedMemoryStorage) {
+		s.beforeWrite = nil
+		concurrent.storage = s
+		if err := concurrent.MarkAsProcessed(context.Background(), Item{Title: "Other", Link: "https://example.com/other"}); err != nil {
+			t.Fatalf("Concurrent write failed: %v", err)
+		}
+	}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	repo.checkpointArticles = 1
+	ctx := context.Background()
+
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "Mine", Link: "https://example.com/mine"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if store.generation != 2 {
+		t.Errorf("Expected the concurrent write and the retried write, got generation %d", store.generation)
+	}
+	index, _, err := repo.loadVersionedIndex(ctx, store, defaultIndexFileName)
+	if err != nil {
+		t.Fatalf("Loading failed: %v", err)
+	}
+	if len(index) != 2 || index["https://example.com/other"] == nil || index["https://example.com/mine"] == nil {
+		t.Errorf("Expected both entries to survive, got %v", index)
+	}
+
+	// Writers that keep colliding give up instead of looping forever
+	store.beforeWrite = func(s *versionedMemoryStorage) { s.generation++ }
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "Late", Link: "https://example.com/late"}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected a version conflict after %d attempts, got %v", maxIndexUpdateAttempts, err)
+	}
+}
+
+func TestProcessedIndexRepository_BuffersMarksUntilFlush(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	repo.checkpointArticles = 3
+	ctx := context.Background()
+
+	for _, link := range []string{"https://example.com/a", "https://example.com/b"} {
+		if err := repo.MarkAsProcessed(ctx, Item{Title: link, Link: link}); err != nil {
+			t.Fatalf("MarkAsProcessed failed: %v", err)
+		}
+	}
+	if store.generation != 0 {
+		t.Errorf("Expected no index write before the checkpoint, got generation %d", store.generation)
+	}
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 2 {
+		t.Errorf("Expected LoadIndex to include pending marks, got %v", index)
+	}
+
+	// The third mark reaches the checkpoint and writes all three at once
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "c", Link: "https://example.com/c"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if store.generation != 1 {
+		t.Errorf("Expected one index write at the checkpoint, got generation %d", store.generation)
+	}
+
+	// Unmarking a pending article drops it without a write of its own
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "d", Link: "https://example.com/d"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if removed, err := repo.UnmarkProcessed(ctx, Item{Link: "https://example.com/d"}); err != nil || !removed {
+		t.Errorf("Expected the pending mark to be removed, got removed=%v err=%v", removed, err)
+	}
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if store.generation != 1 {
+		t.Errorf("Expected nothing left to flush, got generation %d", store.generation)
+	}
+
+	// Close writes what was not flushed
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "e", Link: "https://example.com/e"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	stored, _, err := repo.loadVersionedIndex(ctx, store, defaultIndexFileName)
+	if err != nil {
+		t.Fatalf("Loading failed: %v", err)
+	}
+	if len(stored) != 4 || stored["https://example.com/e"] == nil || stored["https://example.com/d"] != nil {
+		t.Errorf("Expected a, b, c and e in the stored index, got %v", stored)
+	}
+}
+
+func TestProcessedIndexRepository_ReplaysWAL(t *testing.T) {
+	walStore, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store := &versionedMemoryStorage{}
+	ctx := context.Background()
+
+	// An instance marks an article after posting it and crashes before writing the index
+	crashed := newProcessedIndexRepository(store, defaultIndexFileName)
+	crashed.wal = NewWriteAheadLog(walStore, "processed")
+	if err := crashed.MarkAsProcessed(ctx, Item{Title: "Posted", Link: "https://example.com/posted"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if store.generation != 0 {
+		t.Fatalf("Expected the mark to be buffered, got generation %d", store.generation)
+	}
+
+	// The next instance sees the mark and writes it, so the article is not posted again
+	next := newProcessedIndexRepository(store, defaultIndexFileName)
+	next.wal = NewWriteAheadLog(walStore, "processed")
+	index, err := next.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if !next.IsProcessed("https://example.com/posted", index) {
+		t.Errorf("Expected the replayed mark in the index, got %v", index)
+	}
+	if store.generation != 1 {
+		t.Errorf("Expected the replayed mark to be written, got generation %d", store.generation)
+	}
+	if names, _ := walStore.List(ctx, walPrefix); len(names) != 0 {
+		t.Errorf("Expected replayed entries to be discarded, got %v", names)
+	}
+}
+
+func TestProcessedIndexRepository_Prune(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	ctx := context.Background()
+
+	now := time.Now()
+	seed, _ := json.Marshal(map[string]*IndexEntry{
+		"https://example.com/old":    {URL: "https://example.com/old", ProcessedDate: now.AddDate(0, 0, -120)},
+		"https://example.com/legacy": {URL: "https://example.com/legacy"}, // Written before processing times were recorded
+		"https://example.com/recent": {URL: "https://example.com/recent", ProcessedDate: now.AddDate(0, 0, -10)},
+	})
+	store.data, store.generation = seed, 1
+
+	removed, err := repo.Prune(ctx, now.AddDate(0, 0, -90))
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 stale entries removed, got %d", removed)
+	}
+	index, _, _ := repo.loadVersionedIndex(ctx, store, defaultIndexFileName)
+	if len(index) != 1 || index["https://example.com/recent"] == nil {
+		t.Errorf("Expected only the recent entry to remain, got %v", index)
+	}
+
+	// Nothing stale: no write
+	if removed, err := repo.Prune(ctx, now.AddDate(0, 0, -90)); err != nil || removed != 0 || store.generation != 2 {
+		t.Errorf("Expected a no-op, got removed=%d err=%v generation=%d", removed, err, store.generation)
+	}
+}
+
+func TestProcessedIndexRepository_RetentionOnLoad(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	repo.retention = 90 * 24 * time.Hour
+	ctx := context.Background()
+
+	now := time.Now()
+	seed, _ := json.Marshal(map[string]*IndexEntry{
+		"https://example.com/old":    {URL: "https://example.com/old", ProcessedDate: now.AddDate(0, 0, -120)},
+		"https://example.com/recent": {URL: "https://example.com/recent", ProcessedDate: now.AddDate(0, 0, -10)},
+	})
+	store.data, store.generation = seed, 1
+
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 1 || index["https://example.com/recent"] == nil {
+		t.Errorf("Expected the stale entry to be left out, got %v", index)
+	}
+	stored, _, _ := repo.loadVersionedIndex(ctx, store, defaultIndexFileName)
+	if len(stored) != 1 {
+		t.Errorf("Expected the stale entry to be pruned from storage, got %v", stored)
+	}
+}
+
+func TestProcessedIndexRepository_RetentionAsOf(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	repo.retention = 90 * 24 * time.Hour
+
+	// Both entries are past retention today, but one of them was still recent as of asOf
+	asOf := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	seed, _ := json.Marshal(map[string]*IndexEntry{
+		"https://example.com/old":    {URL: "https://example.com/old", ProcessedDate: asOf.AddDate(0, 0, -120)},
+		"https://example.com/recent": {URL: "https://example.com/recent", ProcessedDate: asOf.AddDate(0, 0, -10)},
+	})
+	store.data, store.generation = seed, 1
+
+	index, err := repo.LoadIndex(WithClock(context.Background(), FixedClock(asOf)))
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 1 || index["https://example.com/recent"] == nil {
+		t.Errorf("Expected the entry within retention as of %s, got %v", asOf, index)
+	}
+}
+
+func TestProcessedIndexRepository_Sharded(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	// A monolithic index written before sharding was enabled
+	seed, _ := json.Marshal(map[string]*IndexEntry{
+		"https://example.com/old":    {URL: "https://example.com/old", ProcessedDate: now.AddDate(0, -6, 0)},
+		"https://example.com/recent": {URL: "https://example.com/recent", ProcessedDate: now},
+	})
+	if err := store.Write(ctx, defaultIndexFileName, seed, "application/json"); err != nil {
+		t.Fatalf("Seeding failed: %v", err)
+	}
+
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	repo.shardMonths = 2
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	// Only the latest shards are loaded
+	if len(index) != 1 || index["https://example.com/recent"] == nil {
+		t.Errorf("Expected only the recent entry, got %v", index)
+	}
+	if _, err := store.Read(ctx, defaultIndexFileName); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the monolithic index to be deleted after sharding, got %v", err)
+	}
+	names, _ := store.List(ctx, "index-v2/")
+	if len(names) != 2 {
+		t.Errorf("Expected 2 shards, got %v", names)
+	}
+
+	// Marks go to the current month's shard
+	if err := repo.MarkAsProcessed(ctx, Item{Title: "New", Link: "https://example.com/new"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	current, err := repo.readIndexObject(ctx, repo.shardName(now))
+	if err != nil || len(current) != 2 || current["https://example.com/new"] == nil {
+		t.Errorf("Expected the new mark in the current shard, got %v (%v)", current, err)
+	}
+
+	// Bulk marks skip entries of shards that are not loaded
+	added, err := repo.MarkManyAsProcessed(ctx, []Item{{Link: "https://example.com/old"}, {Link: "https://example.com/imported"}})
+	if err != nil || added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d (%v)", added, err)
+	}
+
+	// Unmarking searches every shard
+	if removed, err := repo.UnmarkProcessed(ctx, Item{Link: "https://example.com/old"}); err != nil || !removed {
+		t.Errorf("Expected the old entry to be unmarked, got %v (%v)", removed, err)
+	}
+
+	// Shards of months before the cutoff are deleted whole
+	if err := repo.MarkAsProcessed(ctx, Item{Link: "https://example.com/new"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	seed, _ = json.Marshal(map[string]*IndexEntry{
+		"https://example.com/ancient": {URL: "https://example.com/ancient", ProcessedDate: now.AddDate(-1, 0, 0)},
+	})
+	if err := store.Write(ctx, repo.shardName(now.AddDate(-1, 0, 0)), seed, "application/json"); err != nil {
+		t.Fatalf("Seeding failed: %v", err)
+	}
+	removed, err := repo.Prune(ctx, now.AddDate(0, -3, 0))
+	if err != nil || removed != 1 {
+		t.Errorf("Expected 1 pruned entry, got %d (%v)", removed, err)
+	}
+	if _, err := store.Read(ctx, repo.shardName(now.AddDate(-1, 0, 0))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the stale shard to be deleted, got %v", err)
+	}
+}
+
+func TestProcessedIndexRepository_ExportImport(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	source := newProcessedIndexRepository(store, defaultIndexFileName)
+	source.shardMonths = 1
+	seed, _ := json.Marshal(map[string]*IndexEntry{
+		"https://example.com/old": {URL: "https://example.com/old", ProcessedDate: now.AddDate(0, -6, 0)},
+	})
+	if err := store.Write(ctx, source.shardName(now.AddDate(0, -6, 0)), seed, "application/json"); err != nil {
+		t.Fatalf("Seeding failed: %v", err)
+	}
+	if err := source.MarkAsProcessed(ctx, Item{Title: "Recent", Link: "https://example.com/recent"}); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+
+	// The export includes the pending mark and the shard LoadIndex leaves out, oldest first
+	entries, err := source.ExportEntries(ctx)
+	if err != nil {
+		t.Fatalf("ExportEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].URL != "https://example.com/old" || entries[1].Title != "Recent" {
+		t.Fatalf("Expected both entries oldest first, got %v", entries)
+	}
+
+	// Importing into another index keeps the processed dates and the entries already there
+	target := newProcessedIndexRepository(&versionedMemoryStorage{}, defaultIndexFileName)
+	if _, err := target.ImportEntries(ctx, entries[1:]); err != nil {
+		t.Fatalf("ImportEntries failed: %v", err)
+	}
+	added, err := target.ImportEntries(ctx, append(entries, &IndexEntry{URL: "https://example.com/other/?utm_source=x"}))
+	if err != nil || added != 2 {
+		t.Fatalf("Expected 2 added entries, got %d (%v)", added, err)
+	}
+	index, err := target.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 3 || index["https://example.com/other"] == nil {
+		t.Errorf("Expected 3 entries keyed by normalized URL, got %v", index)
+	}
+	if old := index["https://example.com/old"]; old == nil || !old.ProcessedDate.Equal(entries[0].ProcessedDate) {
+		t.Errorf("Expected the processed date to be kept, got %v", old)
+	}
+}
+
+func TestProcessedIndexStats(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ctx := WithClock(context.Background(), FixedClock(now))
+	before := ProcessedIndexStats()
+
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	if err := repo.MarkAsProcessed(ctx, Item{Link: "https://example.com/a"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The next request's repository adds to the same counters
+	repo = newProcessedIndexRepository(store, defaultIndexFileName)
+	index, _ := repo.LoadIndex(ctx)
+	repo.IsProcessed("https://example.com/a", index)
+	repo.IsProcessed("https://example.com/b", index)
+	repo.IsProcessed("https://example.com/c", index)
+
+	stats := ProcessedIndexStats()
+	if stats.Hits-before.Hits != 1 || stats.Misses-before.Misses != 2 || stats.Writes-before.Writes != 1 || !stats.LastFlush.Equal(now) {
+		t.Errorf("Unexpected stats %+v (before %+v)", stats, before)
+	}
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "context"
+
+// On-demand processing stages reported to progress listeners
+const (
+	ProgressFetching    = "fetching"
+	ProgressExtracting  = "extracting"
+	ProgressSummarizing = "summarizing"
+	ProgressPosting     = "posting"
+)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose processing stages are reported to report
+func WithProgress(ctx context.Context, report func(stage string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// ReportProgress notes that processing entered stage (no-op without a listener)
+func ReportProgress(ctx context.Context, stage string) {
+	if report, ok := ctx.Value(progressKey{}).(func(stage string)); ok {
+		report(stage)
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+// English prompt templates, used for English articles with SUMMARY_LANGUAGE=auto. They follow the
+// structure of the Japanese templates so that both render the same way in Slack.
+
+var rssPromptVariantsEnglish = map[string]string{
+	DefaultPromptVariant: `Summarize the following text in at most 200 words so that team members on a Slack channel can grasp it quickly.
+
+**Important constraints:**
+- Do not guess or invent anything; summarize only what the text actually says
+- Do not add information that is not in the text
+
+Write for sharing with the team, using this structure:
+- 📝 **Summary:** What the text says, in 3-4 lines
+- 🎯 **Audience:** Based on the problems or readers the text describes
+- 💡 **Impact:** Only the effects or solutions the text states
+
+Text:
+%s`,
+
+	"concise": `Summarize the following text in at most 100 words so that team members on Slack can grasp it in 10 seconds.
+
+**Important constraints:**
+- Do not guess or invent anything; summarize only what the text actually says
+- Do not add information that is not in the text
+
+Use this structure:
+- 📝 **In one line:** The article's main point in one line
+- 🔑 **Key points:** Up to 3 bullet points of the most important facts
+
+Text:
+%s`,
+
+	"takeaways": `Read the following text and summarize it in at most 200 words, focusing on what engineers can take back to their work.
+
+**Important constraints:**
+- Do not guess or invent anything; summarize only what the text actually says
+- Do not add information that is not in the text
+
+Use this structure:
+- 📝 **Summary:** What the text says, in 2-3 lines
+- 🛠️ **Takeaways:** Bullet points of the techniques, tools and lessons described
+- ⚠️ **Caveats:** Only the constraints and prerequisites the text states
+
+Text:
+%s`,
+}
+
+const onDemandPromptEnglish = `Summarize the following text in detail for an individual request, in about 150-250 words.
+
+**Important constraints:**
+- Do not guess or invent anything; summarize only what the text actually says
+- Do not add information that is not in the text
+
+As an on-demand summary, write it in detail with this structure:
+- 📝 **Overview:** What the text says, in 4-6 lines
+- 🎯 **Audience and problem:** The readers and problems the text describes
+- 💡 **Solution and impact:** The solutions and effects the text states
+- 🔍 **Technical details:** Any technical content, in detail
+- 📊 **Results and data:** Any concrete numbers or results
+
+Text:
+%s`
+
+const commentsPromptEnglish = `The following is the text of an online discussion. Analyze what the community discussed and summarize it in at most 200 words.
+
+**Important constraints:**
+- Do not guess or invent anything; summarize only what the comments actually say
+- Do not add information that is not in the comments
+- Give weight to highly scored comments and constructive discussion
+- Stay within the 200-word limit
+
+Write the comment summary with this structure:
+- 📝 **Overview:** The main topics of the discussion (2-3 lines)
+- 💡 **Main solutions:** Solutions and alternatives proposed (2-3 lines)
+- 🔍 **Technical points:** Important technical discussion (2-3 lines)
+- 🗣️ **Community reaction:** Notable opinions and trends (1-2 lines)
+
+Comments:
+%s`
+
+const combinedPromptEnglish = `Answer the following two requests together in a single answer.
+
+**Output format:**
+- Put a line containing only ` + "`%s`" + ` first, followed by the article summary of request 1
+- Then put a line containing only ` + "`%s`" + `, followed by the comment summary of request 2
+- Do not mix the comments into the article summary, or the article body into the comment summary
+- Output anything else you are asked for after the comment summary
+
+## Request 1: Article summary
+
+%s
+
+## Request 2: Comment summary
+
+%s`
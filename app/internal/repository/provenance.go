@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"strings"
+)
+
+// Prompt template versions recorded in Provenance; bump one whenever its template's wording changes
+const (
+	rssPromptVersion      = "v1" // Shared by every RSS experiment variant
+	onDemandPromptVersion = "v1"
+	commentsPromptVersion = "v1"
+	combinedPromptVersion = "v1" // Article and comments in one call (COMMENT_SUMMARY_MODE=combined)
+)
+
+// Extraction methods recorded in Provenance
+const (
+	ExtractionHTML        = "html"        // Generic text extraction from the fetched page(s)
+	ExtractionReadability = "readability" // The main content of the fetched page(s), without boilerplate
+	ExtractionPDF         = "pdf"         // The text of a fetched PDF
+	ExtractionMetadata    = "metadata"    // The page's OpenGraph/description meta tags, when its body had no text
+	ExtractionRendered    = "rendered"    // The page as rendered by RENDER_CHROME_PATH or RENDER_FALLBACK_URL
+	ExtractionComments    = "comments"    // Comment threads collected by the feed
+	ExtractionDescription = "description" // The feed's description, when the page was unreadable
+	extractionMapReduce   = "map-reduce"  // Long text summarized chunk by chunk first
+	// Internal documents read through their APIs with credentials (see Provenance.Private)
+	ExtractionConfluence = "confluence"
+	ExtractionGoogleDocs = "google-docs"
+)
+
+// Provenance records how a summary was produced, so that quality regressions can be traced
+// back to model, prompt or extraction changes
+type Provenance struct {
+	Provider   string `json:"provider"`   // "gemini" (global API) or "vertex" (GEMINI_REGIONS)
+	Model      string `json:"model"`      // e.g. gemini-2.5-flash
+	Prompt     string `json:"prompt"`     // Template and version, e.g. rss:default@v1 (rss:default:en@v1 with English templates)
+	Extraction string `json:"extraction"` // e.g. html, rule:example.com, rendered+html, confluence, html+map-reduce
+}
+
+// String formats the provenance on one line for footers and tags
+func (p *Provenance) String() string {
+	return p.Provider + "/" + p.Model + " · " + p.Prompt + " · " + p.Extraction
+}
+
+// Private reports whether the summary is of an internal document read with credentials
+// (Confluence, Google Docs), which must stay out of mirrors such as the public summary feed
+func (p *Provenance) Private() bool {
+	if p == nil {
+		return false
+	}
+	method, _, _ := strings.Cut(p.Extraction, "+")
+	return method == ExtractionConfluence || method == ExtractionGoogleDocs
+}
+
+// ForText is the provenance of a summary of text passed to SummarizeText by the same repository
+func (p *Provenance) ForText(extraction string) *Provenance {
+	return &Provenance{
+		Provider:   p.Provider,
+		Model:      p.Model,
+		Prompt:     "ondemand@" + onDemandPromptVersion,
+		Extraction: extraction,
+	}
+}
+
+// provenance describes a summary made by this repository with prompt and extraction
+func (g *geminiRepository) provenance(prompt string, extraction ...string) *Provenance {
+	provider := "gemini"
+	if g.regional != nil {
+		provider = "vertex"
+	}
+	return &Provenance{
+		Provider:   provider,
+		Model:      g.model,
+		Prompt:     prompt,
+		Extraction: strings.Join(extraction, "+"),
+	}
+}
+
+// pageExtraction names how the text of pageURL was obtained: the document API that read it, or the
+// generic or per-domain extraction from the (rendered) page; map-reduce is noted for long texts
+func (g *geminiRepository) pageExtraction(pageURL, extracted string, rendered, mapReduced bool) []string {
+	var methods []string
+	if fetcher := fetcherFor(g.documentFetchers, pageURL); fetcher != nil && !rendered {
+		methods = append(methods, documentExtraction(fetcher))
+	} else {
+		if rendered {
+			methods = append(methods, ExtractionRendered)
+		}
+		methods = append(methods, extracted)
+	}
+	if mapReduced {
+		methods = append(methods, extractionMapReduce)
+	}
+	return methods
+}
+
+// documentExtraction names a document fetcher for provenance
+func documentExtraction(fetcher DocumentFetcher) string {
+	switch fetcher.(type) {
+	case *ConfluenceFetcher:
+		return ExtractionConfluence
+	case *GoogleDocsFetcher:
+		return ExtractionGoogleDocs
+	case *YouTubeTranscriptFetcher:
+		return "youtube-transcript"
+	case *GitHubRepoFetcher:
+		return "github-readme"
+	default:
+		return "document"
+	}
+}
+
+// rssPromptName names the RSS template of an experiment variant (the default without one)
+func rssPromptName(variant string) string {
+	if variant == "" {
+		variant = DefaultPromptVariant
+	}
+	return "rss:" + variant + "@" + rssPromptVersion
+}
+
+// promptVersions are the current versions of the prompt templates, by the name Provenance.Prompt starts with
+var promptVersions = map[string]string{
+	"rss":          rssPromptVersion,
+	SourceOnDemand: onDemandPromptVersion,
+	"comments":     commentsPromptVersion,
+	"combined":     combinedPromptVersion,
+}
+
+// PromptOutdated reports whether a summary was made with an older version of its prompt template
+// than the current one. Summaries without provenance predate versioning and count as outdated.
+func PromptOutdated(p *Provenance) bool {
+	if p == nil {
+		return true
+	}
+	name, version, ok := strings.Cut(p.Prompt, "@")
+	if !ok {
+		return true
+	}
+	template, _, _ := strings.Cut(name, ":")
+	current, known := promptVersions[template]
+	return known && version != current
+}
+
+// combinedPromptName names the combined article and comments template of an experiment variant
+func combinedPromptName(variant string) string {
+	if variant == "" {
+		variant = DefaultPromptVariant
+	}
+	return "combined:" + variant + "@" + combinedPromptVersion
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeminiRepository_SummaryProvenance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "generateContent"):
+			fmt.Fprint(w, `{"candidates": [{"content": {"parts": [{"text": "要約です。"}]}}]}`)
+		default:
+			fmt.Fprint(w, `<html><body><article class="post">本文</article><aside>広告</aside></body></html>`)
+		}
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:    server.URL,
+		model:      "test-model",
+		maxPages:   1,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	ctx := context.Background()
+
+	summary, err := repo.SummarizeURL(ctx, server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Provenance{Provider: "gemini", Model: "test-model", Prompt: "rss:default@v1", Extraction: ExtractionHTML}
+	if summary.Provenance == nil || *summary.Provenance != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summary.Provenance)
+	}
+
+	// Per-domain rules and the render fallback are named in the extraction
+	repo.extractionRules = []ExtractionRule{{Domain: "127.0.0.1", Selector: "article.post"}}
+	repo.renderFallbackURL = server.URL + "/render?url="
+	summary, err = repo.SummarizeRendered(ctx, server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Provenance == nil || summary.Provenance.Extraction != "rendered+rule:127.0.0.1" {
+		t.Errorf("Expected rendered rule extraction, got %+v", summary.Provenance)
+	}
+
+	summary, err = repo.SummarizeComments(ctx, "comment")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Provenance == nil || summary.Provenance.Prompt != "comments@v1" || summary.Provenance.Extraction != ExtractionComments {
+		t.Errorf("Expected comment provenance, got %+v", summary.Provenance)
+	}
+}
+
+func TestSlackRepository_ProvenanceFooter(t *testing.T) {
+	provenance := &Provenance{Provider: "vertex", Model: "gemini-2.5-flash", Prompt: "rss:default@v1", Extraction: "html+map-reduce"}
+	notification := Notification{Title: "Test", URL: "https://example.com", Provenance: provenance}
+
+	notifier := NewSlackRepository("xoxb-test", "#format", "https://slack.example.com").(*slackRepository)
+	if message := notifier.formatNotification(notification); strings.Contains(message, "gemini-2.5-flash") {
+		t.Errorf("Expected no footer unless SLACK_PROVENANCE_FOOTER is set, got %s", message)
+	}
+
+	t.Setenv("SLACK_PROVENANCE_FOOTER", "true")
+	notifier = NewSlackRepository("xoxb-test", "#format", "https://slack.example.com").(*slackRepository)
+	message := notifier.formatNotification(notification)
+	if !strings.Contains(message, "\n_vertex/gemini-2.5-flash · rss:default@v1 · html+map-reduce_") {
+		t.Errorf("Expected the provenance footer, got %s", message)
+	}
+}
+
+func TestPromptOutdated(t *testing.T) {
+	tests := []struct {
+		provenance *Provenance
+		outdated   bool
+	}{
+		{nil, true},
+		{&Provenance{Prompt: "rss:default"}, true},
+		{&Provenance{Prompt: "rss:concise@v0"}, true},
+		{&Provenance{Prompt: "rss:concise@" + rssPromptVersion}, false},
+		{&Provenance{Prompt: "ondemand@" + onDemandPromptVersion}, false},
+		{&Provenance{Prompt: combinedPromptName("")}, false},
+		{&Provenance{Prompt: "custom@v9"}, false}, // Unknown templates are left alone
+	}
+	for _, tt := range tests {
+		if got := PromptOutdated(tt.provenance); got != tt.outdated {
+			t.Errorf("PromptOutdated(%+v) = %t, want %t", tt.provenance, got, tt.outdated)
+		}
+	}
+}
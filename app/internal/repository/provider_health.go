@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// providerGemini names the global Gemini API provider
+const providerGemini = "gemini"
+
+// Provider health settings
+const (
+	// providerHealthWindow is how far back calls count towards the error rate
+	providerHealthWindow = 15 * time.Minute
+	// providerHealthMaxCalls caps the calls kept per provider
+	providerHealthMaxCalls = 100
+	// providerDegradedErrorRate is the error rate from which a provider counts as degraded
+	providerDegradedErrorRate = 0.25
+)
+
+// Provider states reported by ProviderStatuses
+const (
+	ProviderStateOK       = "ok"
+	ProviderStateDegraded = "degraded" // Many recent calls failed, or the circuit is testing recovery
+	ProviderStateDown     = "down"     // The circuit is open: calls fail fast until the cooldown passes
+)
+
+// Circuit states reported by ProviderStatuses
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// ProviderStatus is the health of a summarizer provider ("gemini", or "vertex:<region>" with GEMINI_REGIONS)
+type ProviderStatus struct {
+	Name         string     `json:"name"`
+	State        string     `json:"state"`
+	Circuit      string     `json:"circuit"`
+	RecentCalls  int        `json:"recent_calls"` // Calls within the last 15 minutes
+	ErrorRate    float64    `json:"error_rate"`   // Share of the recent calls that failed on the provider's side
+	LastSuccess  *time.Time `json:"last_success,omitempty"`
+	LastError    string     `json:"last_error,omitempty"` // e.g. "HTTP 503" (never the error text, which may contain the API key)
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+	CircuitSince *time.Time `json:"circuit_since,omitempty"` // When the circuit opened
+}
+
+type providerCall struct {
+	at     time.Time
+	failed bool
+}
+
+// providerHealth tracks a provider's recent calls behind a circuit breaker
+type providerHealth struct {
+	breaker *circuitBreaker
+	now     func() time.Time
+
+	mu          sync.Mutex
+	calls       []providerCall
+	lastSuccess time.Time
+	lastError   string
+	lastErrorAt time.Time
+}
+
+func newProviderHealth() *providerHealth {
+	return &providerHealth{
+		breaker: newCircuitBreaker(defaultBreakerFailureThreshold, defaultBreakerCooldown),
+		now:     time.Now,
+	}
+}
+
+// Allow reports whether a call may be made now; a nil providerHealth (not tracked) always allows
+func (h *providerHealth) Allow() bool {
+	return h == nil || h.breaker.Allow()
+}
+
+// Record feeds the outcome of a call into the history and the breaker. Permanent failures (bad
+// requests, blocked content) mean the provider is up, so only retryable ones count as errors; a call
+// the caller canceled says nothing about the provider either.
+func (h *providerHealth) Record(err error) {
+	if h == nil {
+		return
+	}
+	failed := err != nil && httperr.Retryable(err) && !errors.Is(err, context.Canceled)
+	h.breaker.Record(!failed)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	h.calls = append(h.calls, providerCall{at: now, failed: failed})
+	if len(h.calls) > providerHealthMaxCalls {
+		h.calls = h.calls[len(h.calls)-providerHealthMaxCalls:]
+	}
+	switch {
+	case err == nil:
+		h.lastSuccess = now
+	case failed:
+		h.lastError = providerErrorSummary(err)
+		h.lastErrorAt = now
+	}
+}
+
+// status reports the provider's health under name
+func (h *providerHealth) status(name string) ProviderStatus {
+	circuit, openedAt := h.breaker.State()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	status := ProviderStatus{Name: name, Circuit: circuit, LastError: h.lastError}
+	cutoff := h.now().Add(-providerHealthWindow)
+	failures := 0
+	for _, call := range h.calls {
+		if call.at.Before(cutoff) {
+			continue
+		}
+		status.RecentCalls++
+		if call.failed {
+			failures++
+		}
+	}
+	if status.RecentCalls > 0 {
+		status.ErrorRate = float64(failures) / float64(status.RecentCalls)
+	}
+	status.LastSuccess = optionalTime(h.lastSuccess)
+	status.LastErrorAt = optionalTime(h.lastErrorAt)
+	status.CircuitSince = optionalTime(openedAt)
+
+	switch {
+	case circuit == CircuitOpen:
+		status.State = ProviderStateDown
+	case circuit == CircuitHalfOpen || status.ErrorRate >= providerDegradedErrorRate:
+		status.State = ProviderStateDegraded
+	default:
+		status.State = ProviderStateOK
+	}
+	return status
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// providerErrorSummary describes a failure without its message
+func providerErrorSummary(err error) string {
+	var httpErr *httperr.Error
+	switch {
+	case errors.As(err, &httpErr) && httpErr.StatusCode != 0:
+		return fmt.Sprintf("HTTP %d", httpErr.StatusCode)
+	default:
+		return "network error"
+	}
+}
+
+// Provider health is shared per provider so feeds with their own Gemini repositories trip together
+var (
+	providerHealthMu sync.Mutex
+	providerHealths  = make(map[string]*providerHealth)
+)
+
+func providerHealthFor(name string) *providerHealth {
+	providerHealthMu.Lock()
+	defer providerHealthMu.Unlock()
+
+	health, exists := providerHealths[name]
+	if !exists {
+		health = newProviderHealth()
+		providerHealths[name] = health
+	}
+	return health
+}
+
+// ProviderStatuses reports the health of every configured summarizer provider, sorted by name
+func ProviderStatuses() []ProviderStatus {
+	providerHealthMu.Lock()
+	names := make([]string, 0, len(providerHealths))
+	healths := make(map[string]*providerHealth, len(providerHealths))
+	for name, health := range providerHealths {
+		names = append(names, name)
+		healths[name] = health
+	}
+	providerHealthMu.Unlock()
+
+	slices.Sort(names)
+	statuses := make([]ProviderStatus, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, healths[name].status(name))
+	}
+	return statuses
+}
+
+// providerHealth returns the shared health of a provider (nil when the repository does not track it)
+func (g *geminiRepository) providerHealth(name string) *providerHealth {
+	if g.health == nil {
+		return nil
+	}
+	return g.health(name)
+}
+
+// regionProvider names the provider of a Vertex AI region
+func regionProvider(region string) string {
+	return "vertex:" + region
+}
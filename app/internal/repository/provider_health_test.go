@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// isolatedProviderHealth returns a provider health lookup that is not shared with other repositories
+func isolatedProviderHealth() func(provider string) *providerHealth {
+	healths := make(map[string]*providerHealth)
+	return func(provider string) *providerHealth {
+		if healths[provider] == nil {
+			healths[provider] = newProviderHealth()
+		}
+		return healths[provider]
+	}
+}
+
+func TestProviderHealth_Status(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	health := newProviderHealth()
+	health.now = func() time.Time { return now }
+	health.breaker.now = health.now
+
+	status := health.status("gemini")
+	if status.State != ProviderStateOK || status.Circuit != CircuitClosed || status.RecentCalls != 0 || status.LastSuccess != nil {
+		t.Errorf("Expected a fresh provider to be ok, got %+v", status)
+	}
+
+	health.Record(nil)
+	// Bad requests mean the provider is up
+	health.Record(httperr.Status(http.StatusBadRequest, "bad request"))
+	health.Record(httperr.Transport(fmt.Errorf("dial tcp: https://example.com/?key=secret")))
+	// A call the caller canceled is not a provider failure
+	health.Record(httperr.Transport(fmt.Errorf("sending request: %w", context.Canceled)))
+	status = health.status("gemini")
+	if status.State != ProviderStateDegraded || status.RecentCalls != 4 || status.ErrorRate != 0.25 {
+		t.Errorf("Expected 1 of 4 calls failed and a degraded state, got %+v", status)
+	}
+	if status.LastError != "network error" || status.LastSuccess == nil || !status.LastSuccess.Equal(now) {
+		t.Errorf("Expected the error summary without its text and the last success, got %+v", status)
+	}
+
+	for i := 0; i < defaultBreakerFailureThreshold; i++ {
+		health.Record(httperr.Status(http.StatusServiceUnavailable, "unavailable"))
+	}
+	status = health.status("gemini")
+	if status.State != ProviderStateDown || status.Circuit != CircuitOpen || status.LastError != "HTTP 503" || status.CircuitSince == nil {
+		t.Errorf("Expected an open circuit after repeated 503s, got %+v", status)
+	}
+
+	// Calls older than the window no longer count, and the circuit half-opens after the cooldown
+	now = now.Add(providerHealthWindow + time.Second)
+	status = health.status("gemini")
+	if status.RecentCalls != 0 || status.Circuit != CircuitHalfOpen || status.State != ProviderStateDegraded {
+		t.Errorf("Expected no recent calls and a half-open circuit, got %+v", status)
+	}
+}
+
+func TestGeminiRepository_RegionalCircuitOpen(t *testing.T) {
+	repo, called := newRegionalTestRepo(t, "asia-northeast1,asia-northeast2", map[string]int{
+		"asia-northeast1": http.StatusServiceUnavailable,
+	})
+
+	for i := 0; i < defaultBreakerFailureThreshold+2; i++ {
+		if _, err := repo.SummarizeText(context.Background(), "article text"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	// Once the breaker opens, the failing region is skipped
+	failing := strings.Count(strings.Join(*called, ","), "asia-northeast1")
+	if failing != defaultBreakerFailureThreshold {
+		t.Errorf("Expected %d calls to the failing region, got %d", defaultBreakerFailureThreshold, failing)
+	}
+	if status := repo.(*geminiRepository).providerHealth("vertex:asia-northeast1").status("vertex:asia-northeast1"); status.State != ProviderStateDown {
+		t.Errorf("Expected the failing region to be down, got %+v", status)
+	}
+}
+
+func TestGeminiRepository_CircuitOpenFailsFast(t *testing.T) {
+	repo := &geminiRepository{model: "test-model", baseURL: "http://global.invalid", httpClient: http.DefaultClient, health: isolatedProviderHealth()}
+	health := repo.providerHealth(providerGemini)
+	for i := 0; i < defaultBreakerFailureThreshold; i++ {
+		health.Record(httperr.Status(http.StatusServiceUnavailable, "unavailable"))
+	}
+
+	_, err := repo.callGeminiAPI(context.Background(), "prompt")
+	if !errors.Is(err, ErrCircuitOpen) || !httperr.Retryable(err) {
+		t.Errorf("Expected a retryable ErrCircuitOpen, got %v", err)
+	}
+}
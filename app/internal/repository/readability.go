@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Main-content extraction in the style of Mozilla's Readability: boilerplate (navigation, cookie
+// banners, share bars, footers) is removed, every paragraph scores its parent and grandparent, and
+// the best-scoring container with its related siblings is taken as the article.
+
+// Elements that never hold article text
+var boilerplateElements = map[atom.Atom]bool{
+	atom.Nav: true, atom.Footer: true, atom.Aside: true, atom.Form: true, atom.Button: true,
+	atom.Iframe: true, atom.Select: true, atom.Svg: true, atom.Dialog: true,
+}
+
+var (
+	// Class or id names of page chrome; dropped unless they also look like content
+	unlikelyCandidatePattern = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|consent|cookie|disqus|extra|foot|gdpr|header|legends|menu|modal|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|tweet|twitter|widget|ad-break|agegate|ads\b`)
+	maybeCandidatePattern    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	// Class or id names that raise or lower a container's score
+	positiveScorePattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeScorePattern = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// Elements whose text scores their ancestors
+var scoredElements = map[atom.Atom]bool{atom.P: true, atom.Pre: true, atom.Td: true, atom.Blockquote: true, atom.Li: true}
+
+const (
+	minScoredParagraph = 25  // Runes a paragraph needs to count
+	minMainContent     = 100 // Runes the main content needs, or the whole page is used
+)
+
+// extractMainContent returns the text of the page's main article (see htmlToText for the format).
+// ok is false when no container holds enough text, e.g. for short pages or link lists, so the caller
+// can fall back to the whole page.
+func extractMainContent(page string) (string, bool) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", false
+	}
+	removeBoilerplate(doc)
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node // In document order, so that ties go to the first container
+	var score func(n *html.Node)
+	score = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if scoredElements[child.DataAtom] {
+				candidates = scoreParagraph(scores, candidates, child)
+			}
+			score(child)
+		}
+	}
+	score(doc)
+
+	var top *html.Node
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(n)
+		if top == nil || scores[n] > scores[top] {
+			top = n
+		}
+	}
+	if top == nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, n := range withRelatedSiblings(scores, top) {
+		writeNodeText(&b, n, 0)
+		b.WriteString("\n")
+	}
+	text := normalizeTextLines(b.String())
+	if utf8.RuneCountInString(text) < minMainContent {
+		return "", false
+	}
+	return text, true
+}
+
+// removeBoilerplate drops elements that are page chrome by tag, role or class/id
+func removeBoilerplate(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode && isBoilerplate(child) {
+			n.RemoveChild(child)
+		} else {
+			removeBoilerplate(child)
+		}
+		child = next
+	}
+}
+
+func isBoilerplate(n *html.Node) bool {
+	switch {
+	case boilerplateElements[n.DataAtom]:
+		return true
+	case n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main || n.DataAtom == atom.A:
+		return false
+	}
+	switch attr(n, "role") {
+	case "navigation", "banner", "contentinfo", "complementary", "dialog", "alertdialog", "menu":
+		return true
+	}
+	if attr(n, "aria-hidden") == "true" || hasAttr(n, "hidden") {
+		return true
+	}
+	names := attr(n, "class") + " " + attr(n, "id")
+	return unlikelyCandidatePattern.MatchString(names) && !maybeCandidatePattern.MatchString(names)
+}
+
+// scoreParagraph adds a paragraph's score to its parent and half of it to its grandparent, returning
+// candidates with the newly scored ancestors. Longer paragraphs and more commas (、 in Japanese) score higher.
+func scoreParagraph(scores map[*html.Node]float64, candidates []*html.Node, n *html.Node) []*html.Node {
+	text := nodeText(n)
+	length := utf8.RuneCountInString(text)
+	if length < minScoredParagraph {
+		return candidates
+	}
+	s := 1 + float64(strings.Count(text, ",")+strings.Count(text, "、")+strings.Count(text, "，"))
+	s += min(float64(length)/100, 3)
+
+	for level, ancestor := 0, n.Parent; level < 2 && ancestor != nil && ancestor.Type == html.ElementNode; level, ancestor = level+1, ancestor.Parent {
+		if _, ok := scores[ancestor]; !ok {
+			scores[ancestor] = initialScore(ancestor)
+			candidates = append(candidates, ancestor)
+		}
+		if level == 0 {
+			scores[ancestor] += s
+		} else {
+			scores[ancestor] += s / 2
+		}
+	}
+	return candidates
+}
+
+// initialScore favours typical article containers and their class/id names
+func initialScore(n *html.Node) float64 {
+	var s float64
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		s = 10
+	case atom.Div, atom.Section:
+		s = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		s = 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		s = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th, atom.Header:
+		s = -5
+	}
+	for _, name := range []string{attr(n, "class"), attr(n, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativeScorePattern.MatchString(name) {
+			s -= 25
+		}
+		if positiveScorePattern.MatchString(name) {
+			s += 25
+		}
+	}
+	return s
+}
+
+// withRelatedSiblings returns the top container preceded and followed by the siblings that belong to
+// the same article: well-scoring containers and plain paragraphs with few links
+func withRelatedSiblings(scores map[*html.Node]float64, top *html.Node) []*html.Node {
+	if top.Parent == nil {
+		return []*html.Node{top}
+	}
+	threshold := max(10, scores[top]*0.2)
+	topClass := attr(top, "class")
+
+	var nodes []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		bonus := 0.0
+		if topClass != "" && attr(sibling, "class") == topClass {
+			bonus = scores[top] * 0.2
+		}
+		if s, ok := scores[sibling]; ok && s+bonus >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.DataAtom == atom.P {
+			length := utf8.RuneCountInString(nodeText(sibling))
+			density := linkDensity(sibling)
+			if length > 80 && density < 0.25 || length > 0 && density == 0 && strings.ContainsAny(nodeText(sibling), ".。") {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+	return nodes
+}
+
+// linkDensity is the share of n's text inside links (navigation lists score close to 1)
+func linkDensity(n *html.Node) float64 {
+	length := utf8.RuneCountInString(nodeText(n))
+	if length == 0 {
+		return 0
+	}
+	links := 0
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.DataAtom == atom.A {
+				links += utf8.RuneCountInString(nodeText(child))
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	return float64(links) / float64(length)
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns n's text with whitespace collapsed
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style || n.DataAtom == atom.Noscript) {
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return collapseSpaces(b.String())
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const readabilityPage = `<html><head><title>Release notes</title></head><body>
+<header class="site-header"><a href="/">Example Blog</a></header>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/about">About</a></li></ul></nav>
+<div id="cookie-banner">We use cookies to improve your experience. <button>Accept all</button></div>
+<div class="layout">
+<div class="sidebar"><h3>Popular posts</h3><ul><li><a href="/a">How we scaled our database to a million users</a></li><li><a href="/b">Ten tips for faster builds in large monorepos</a></li></ul></div>
+<article><div class="post-content">
+<h1>Version 2.0 released</h1>
+<p>Version 2.0 rewrites the scheduler, which now batches requests, retries failed calls and keeps latency flat under load.</p>
+<p>The new storage engine, built on an append-only log, cuts write amplification in half and makes recovery after crashes instant.</p>
+<ul><li>Faster startup</li><li>Smaller binaries</li></ul>
+<div class="share-buttons">Share on <a href="#">X</a> <a href="#">Facebook</a></div>
+</div></article>
+</div>
+<footer>Copyright 2024 Example Inc. All rights reserved. <a href="/privacy">Privacy</a></footer>
+</body></html>`
+
+func TestExtractMainContent(t *testing.T) {
+	text, ok := extractMainContent(readabilityPage)
+	if !ok {
+		t.Fatal("Expected main content to be found")
+	}
+	for _, want := range []string{"Version 2.0 released", "rewrites the scheduler", "append-only log", "- Faster startup"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in main content, got %q", want, text)
+		}
+	}
+	for _, boilerplate := range []string{"Example Blog", "Home", "cookies", "Popular posts", "Share on", "Copyright"} {
+		if strings.Contains(text, boilerplate) {
+			t.Errorf("Expected %q to be removed, got %q", boilerplate, text)
+		}
+	}
+}
+
+func TestExtractMainContent_Japanese(t *testing.T) {
+	page := `<html><body><div class="menu"><a href="/">トップ</a><a href="/ranking">ランキング</a></div>
+<div id="main"><p>新しいバージョンでは、スケジューラを書き直し、リクエストをまとめて送るようにしました。負荷が高いときでも、レイテンシはほとんど変わりません。</p>
+<p>また、追記型のログを使う新しいストレージエンジンにより、書き込みの増幅が半分になり、クラッシュ後の復旧もすぐに終わるようになりました。</p></div>
+<div class="footer">運営会社・利用規約・プライバシーポリシー</div></body></html>`
+
+	text, ok := extractMainContent(page)
+	if !ok {
+		t.Fatal("Expected main content to be found")
+	}
+	if !strings.Contains(text, "スケジューラを書き直し") || strings.Contains(text, "ランキング") || strings.Contains(text, "利用規約") {
+		t.Errorf("Unexpected main content %q", text)
+	}
+}
+
+func TestExtractMainContent_FallsBackForShortPages(t *testing.T) {
+	for name, page := range map[string]string{
+		"short":     `<p>Whole page</p>`,
+		"link list": `<ul><li><a href="/a">First article about something interesting</a></li><li><a href="/b">Second article about something else</a></li></ul>`,
+	} {
+		if text, ok := extractMainContent(page); ok {
+			t.Errorf("%s: expected no main content, got %q", name, text)
+		}
+	}
+}
+
+func TestExtractTextFromPages_Readability(t *testing.T) {
+	text, method := (&geminiRepository{}).extractTextFromPages(context.Background(), "https://example.com/release", []string{readabilityPage})
+	if method != ExtractionReadability {
+		t.Errorf("Expected %q extraction, got %q", ExtractionReadability, method)
+	}
+	if strings.Contains(text, "cookies") || !strings.Contains(text, "append-only log") {
+		t.Errorf("Unexpected text %q", text)
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const defaultRedactionReplacement = "[REDACTED]"
+
+// RedactionRule replaces every match of a pattern in text sent to the LLM
+type RedactionRule struct {
+	Name        string `json:"name"`        // Counter label (defaults to rule_<index>)
+	Pattern     string `json:"pattern"`     // Go regular expression, e.g. "(?i)[a-z0-9-]+\\.corp\\.example\\.com"
+	Replacement string `json:"replacement"` // Defaults to [REDACTED]
+
+	re *regexp.Regexp
+}
+
+// ParseRedactionRules parses REDACTION_RULES, a JSON array of rules such as
+// [{"name":"internal-host","pattern":"(?i)[a-z0-9-]+\\.corp\\.example\\.com","replacement":"[internal host]"}]
+func ParseRedactionRules(raw string) ([]RedactionRule, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var rules []RedactionRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("parsing redaction rules: %w", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("redaction rule %d: pattern is required", i)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction rule %d: %w", i, err)
+		}
+		rule.re = re
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule_%d", i)
+		}
+		if rule.Replacement == "" {
+			rule.Replacement = defaultRedactionReplacement
+		}
+	}
+	return rules, nil
+}
+
+// Redactor scrubs configured patterns from text before it leaves for the LLM and keeps
+// per-rule counts of the redactions made by this instance (for data-handling audits)
+type Redactor struct {
+	rules []RedactionRule
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func NewRedactor(rules []RedactionRule) *Redactor {
+	return &Redactor{
+		rules:  rules,
+		counts: make(map[string]int),
+	}
+}
+
+// Redact applies every rule to text and logs the redactions made (nothing is logged when none matched).
+// A nil Redactor returns text unchanged.
+func (r *Redactor) Redact(ctx context.Context, text string) string {
+	if r == nil || len(r.rules) == 0 {
+		return text
+	}
+
+	var applied []string
+	for _, rule := range r.rules {
+		matches := len(rule.re.FindAllStringIndex(text, -1))
+		if matches == 0 {
+			continue
+		}
+		text = rule.re.ReplaceAllLiteralString(text, rule.Replacement)
+
+		r.mu.Lock()
+		r.counts[rule.Name] += matches
+		r.mu.Unlock()
+		applied = append(applied, fmt.Sprintf("%s=%d", rule.Name, matches))
+	}
+
+	if len(applied) > 0 {
+		// マッチした文字列自体はログに残さない
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+		logger.Printf("Redaction audit redactions=%s totals=%s", strings.Join(applied, ","), r.totals())
+	}
+	return text
+}
+
+// Counts returns the cumulative redactions per rule name
+func (r *Redactor) Counts() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int, len(r.counts))
+	for name, n := range r.counts {
+		counts[name] = n
+	}
+	return counts
+}
+
+// totals formats the cumulative counts as name=n pairs in name order
+func (r *Redactor) totals() string {
+	counts := r.Counts()
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, counts[name]))
+	}
+	return strings.Join(parts, ",")
+}
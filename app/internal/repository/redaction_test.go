@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRedactionRules(t *testing.T) {
+	rules, err := ParseRedactionRules(`[{"name":"host","pattern":"(?i)[a-z0-9-]+\\.corp\\.example\\.com"},{"pattern":"Acme Corp","replacement":"[customer]"}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[0].Replacement != "[REDACTED]" || rules[1].Name != "rule_1" {
+		t.Errorf("Expected defaults to be filled, got %+v", rules)
+	}
+
+	if rules, err := ParseRedactionRules(" "); err != nil || rules != nil {
+		t.Errorf("Expected no rules for empty config, got %v (%v)", rules, err)
+	}
+	for _, raw := range []string{`{}`, `[{"name":"empty"}]`, `[{"pattern":"("}]`} {
+		if _, err := ParseRedactionRules(raw); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	rules, err := ParseRedactionRules(`[{"name":"host","pattern":"(?i)[a-z0-9-]+\\.corp\\.example\\.com"},{"name":"customer","pattern":"Acme Corp","replacement":"[customer]"}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	redactor := NewRedactor(rules)
+
+	text := redactor.Redact(context.Background(), "Acme Corp moved build.corp.example.com and CI.corp.example.com to $1 capacity")
+	if text != "[customer] moved [REDACTED] and [REDACTED] to $1 capacity" {
+		t.Errorf("Unexpected redacted text: %q", text)
+	}
+	redactor.Redact(context.Background(), "nothing to see here")
+	redactor.Redact(context.Background(), "Acme Corp again")
+
+	counts := redactor.Counts()
+	if counts["host"] != 2 || counts["customer"] != 2 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+
+	var disabled *Redactor
+	if disabled.Redact(context.Background(), "Acme Corp") != "Acme Corp" {
+		t.Error("Expected nil redactor to leave text unchanged")
+	}
+}
+
+func TestGeminiRepository_RedactsPrompt(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "summary"}]}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("REDACTION_RULES", `[{"name":"host","pattern":"[a-z0-9-]+\\.corp\\.example\\.com"}]`)
+	repo := NewGeminiRepository("test-key", "test-model", server.URL)
+	repo.(*geminiRepository).httpClient = &http.Client{Timeout: 5 * time.Second}
+
+	if _, err := repo.SummarizeText(context.Background(), "Deploys go through build.corp.example.com now"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(sent, "corp.example.com") || !strings.Contains(sent, "[REDACTED]") {
+		t.Errorf("Expected internal host to be redacted from the request, got %s", sent)
+	}
+}
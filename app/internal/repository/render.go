@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const (
+	// defaultRenderMinChars is the extracted text length (in runes) below which a page is rendered
+	defaultRenderMinChars = 200
+	// defaultRenderTimeout bounds a headless browser run, including the page's scripts
+	defaultRenderTimeout = 30 * time.Second
+)
+
+// HeadlessBrowser renders JavaScript-built pages (SPA blogs, docs sites) with a local headless
+// Chrome or Chromium and returns the resulting DOM
+type HeadlessBrowser struct {
+	path      string
+	userAgent string
+	timeout   time.Duration
+}
+
+// NewHeadlessBrowser creates a browser running the executable at path; an empty userAgent uses DefaultUserAgent
+func NewHeadlessBrowser(path, userAgent string) *HeadlessBrowser {
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+	return &HeadlessBrowser{
+		path:      path,
+		userAgent: userAgent,
+		timeout:   defaultRenderTimeout,
+	}
+}
+
+// Render loads pageURL in the browser, lets its scripts run and returns the serialized DOM
+func (b *HeadlessBrowser) Render(ctx context.Context, pageURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, b.path,
+		"--headless=new",
+		"--disable-gpu",
+		"--no-sandbox", // Containers run as root without user namespaces
+		"--disable-dev-shm-usage",
+		"--hide-scrollbars",
+		"--mute-audio",
+		"--user-agent="+b.userAgent,
+		// Virtual time lets pending network requests and timers settle before the DOM is dumped
+		"--virtual-time-budget=10000",
+		"--dump-dom",
+		pageURL,
+	)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running headless browser: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// renderEnabled reports whether pages can be rendered, by headless browser or prerendering service
+func (g *geminiRepository) renderEnabled() bool {
+	return g.browser != nil || g.renderFallbackURL != ""
+}
+
+// render returns pageURL as rendered by the headless browser, or else by the prerendering service;
+// ErrRenderFallbackDisabled when neither is configured. Both load the page itself, so the fetch
+// policy applies to it.
+func (g *geminiRepository) render(ctx context.Context, pageURL string) (string, error) {
+	if !g.renderEnabled() {
+		return "", ErrRenderFallbackDisabled
+	}
+	if err := g.policy.Wait(ctx, pageURL); err != nil {
+		return "", err
+	}
+	if g.browser != nil {
+		return g.browser.Render(ctx, pageURL)
+	}
+	return g.fetchHTML(ctx, g.renderFallbackURL+url.QueryEscape(pageURL))
+}
+
+// renderSparsePages renders the article when its static HTML yields less than renderMinChars of
+// text, so that pages built by JavaScript are summarized from their real content. The rendered page
+// replaces the fetched pages only when it holds more text; rendered reports whether it did.
+// Documents read through an API and PDFs are never rendered.
+func (g *geminiRepository) renderSparsePages(ctx context.Context, pageURL string, pages []string) ([]string, bool) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if g.renderMinChars <= 0 || !g.renderEnabled() || fetcherFor(g.documentFetchers, pageURL) != nil {
+		return pages, false
+	}
+	for _, page := range pages {
+		if isPDFPage(page) {
+			return pages, false
+		}
+	}
+
+	text, _ := g.extractTextFromPages(ctx, pageURL, pages)
+	staticChars := utf8.RuneCountInString(text)
+	if staticChars >= g.renderMinChars {
+		return pages, false
+	}
+
+	start := time.Now()
+	rendered, err := g.render(ctx, pageURL)
+	if err != nil {
+		logger.Printf("Warning: Failed to render sparse page url=%s text_chars=%d: %v", pageURL, staticChars, err)
+		return pages, false
+	}
+	renderedText, _ := g.extractTextFromPages(ctx, pageURL, []string{rendered})
+	renderedChars := utf8.RuneCountInString(renderedText)
+	logger.Printf("Sparse page rendered url=%s static_chars=%d rendered_chars=%d duration_ms=%d", pageURL, staticChars, renderedChars, time.Since(start).Milliseconds())
+	if renderedChars <= staticChars {
+		return pages, false
+	}
+	return []string{rendered}, true
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var renderedArticle = "<html><body><article><p>" + strings.Repeat("JavaScript で描画された記事の本文です。段落には、十分な長さの文章が含まれています。", 5) + "</p></article></body></html>"
+
+func TestHeadlessBrowser_Render(t *testing.T) {
+	// A stand-in browser that prints a DOM naming the page it was given (the last argument)
+	path := filepath.Join(t.TempDir(), "chrome")
+	script := "#!/bin/sh\nfor last; do :; done\necho \"<html><body>$last</body></html>\"\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := NewHeadlessBrowser(path, "").Render(context.Background(), "https://example.com/spa")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.TrimSpace(page) != "<html><body>https://example.com/spa</body></html>" {
+		t.Errorf("Unexpected DOM %q", page)
+	}
+
+	if _, err := NewHeadlessBrowser(filepath.Join(t.TempDir(), "missing"), "").Render(context.Background(), "https://example.com/spa"); err == nil {
+		t.Error("Expected an error for a missing browser")
+	}
+}
+
+func TestGeminiRepository_SummarizeURL_RendersSparsePages(t *testing.T) {
+	var prompt string
+	renders := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "generateContent"):
+			body, _ := io.ReadAll(r.Body)
+			prompt = string(body)
+			fmt.Fprint(w, `{"candidates": [{"content": {"parts": [{"text": "要約です。"}]}}]}`)
+		case r.URL.Path == "/render":
+			renders++
+			fmt.Fprint(w, renderedArticle)
+		case r.URL.Path == "/spa":
+			fmt.Fprint(w, `<html><body><div id="root">Loading...</div><script src="/app.js"></script></body></html>`)
+		default:
+			fmt.Fprint(w, renderedArticle)
+		}
+	}))
+	defer server.Close()
+
+	repo := &geminiRepository{
+		baseURL:           server.URL,
+		model:             "test-model",
+		maxPages:          1,
+		httpClient:        &http.Client{Timeout: 5 * time.Second},
+		renderFallbackURL: server.URL + "/render?url=",
+		renderMinChars:    defaultRenderMinChars,
+	}
+	ctx := context.Background()
+
+	summary, err := repo.SummarizeURL(ctx, server.URL+"/spa")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if renders != 1 || summary.Provenance == nil || summary.Provenance.Extraction != "rendered+readability" {
+		t.Errorf("Expected the sparse page to be rendered, got %d renders and %+v", renders, summary.Provenance)
+	}
+	if !strings.Contains(prompt, "JavaScript で描画された記事の本文です。") {
+		t.Errorf("Expected the rendered text in the prompt, got %q", prompt)
+	}
+
+	// Pages with enough static text are summarized as fetched
+	summary, err = repo.SummarizeURL(ctx, server.URL+"/static")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if renders != 1 || summary.Provenance.Extraction != ExtractionReadability {
+		t.Errorf("Expected no render for a static page, got %d renders and %+v", renders, summary.Provenance)
+	}
+
+	// RENDER_MIN_CHARS=0 leaves rendering to the missing-content retry
+	repo.renderMinChars = 0
+	if _, err := repo.SummarizeURL(ctx, server.URL+"/spa"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if renders != 1 {
+		t.Errorf("Expected no render with RENDER_MIN_CHARS=0, got %d renders", renders)
+	}
+}
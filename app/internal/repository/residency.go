@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// ErrRegionUnavailable is returned (wrapped) when no allowed region could serve a Gemini request.
+// The request is refused rather than sent to an endpoint outside the allowed regions.
+var ErrRegionUnavailable = errors.New("gemini unavailable in allowed regions")
+
+// vertexEndpointFormat is the regional Vertex AI generateContent endpoint (region, project, region, model)
+const vertexEndpointFormat = "https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s/publishers/google/models/%s:generateContent"
+
+// regionPattern matches Google Cloud region names such as asia-northeast1 (the multi-region "global" is not a region)
+var regionPattern = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+
+// ParseRegions parses GEMINI_REGIONS, a comma-separated list of allowed regions in order of preference
+func ParseRegions(raw string) ([]string, error) {
+	var regions []string
+	for _, region := range strings.Split(raw, ",") {
+		region = strings.TrimSpace(region)
+		if region == "" {
+			continue
+		}
+		if !regionPattern.MatchString(region) {
+			return nil, fmt.Errorf("invalid region %q (expected e.g. asia-northeast1)", region)
+		}
+		regions = append(regions, region)
+	}
+	return regions, nil
+}
+
+// regionalEndpoint pins Gemini calls to Vertex AI in the allowed regions, tried in order.
+// Requests never fall back to the global Gemini API.
+type regionalEndpoint struct {
+	project string
+	regions []string
+	// urlFormat is vertexEndpointFormat (overridable for tests)
+	urlFormat string
+	// token returns an OAuth access token for Vertex AI (overridable for tests)
+	token func(ctx context.Context) (string, error)
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+func newRegionalEndpoint(project string, regions []string) *regionalEndpoint {
+	e := &regionalEndpoint{
+		project:   project,
+		regions:   regions,
+		urlFormat: vertexEndpointFormat,
+	}
+	e.token = e.defaultToken
+	return e
+}
+
+// defaultToken uses application default credentials, loaded on first use
+func (e *regionalEndpoint) defaultToken(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.source == nil {
+		source, err := google.DefaultTokenSource(context.Background(), "https://www.googleapis.com/auth/cloud-platform")
+		if err != nil {
+			return "", fmt.Errorf("loading Vertex AI credentials: %w", err)
+		}
+		e.source = source
+	}
+	token, err := e.source.Token()
+	if err != nil {
+		return "", fmt.Errorf("fetching Vertex AI token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// url returns the generateContent endpoint for model in region
+func (e *regionalEndpoint) url(region, model string) string {
+	return fmt.Sprintf(e.urlFormat, region, e.project, region, model)
+}
+
+// regionUnavailable reports whether err means the region cannot serve the request (network
+// failure, outage, or the model is not offered there), so the next allowed region should be tried
+func regionUnavailable(err error) bool {
+	var httpErr *httperr.Error
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return true
+	}
+	return errors.Is(err, httperr.ErrTemporary)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestParseRegions(t *testing.T) {
+	regions, err := ParseRegions(" asia-northeast1, asia-northeast2 ,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(regions, ",") != "asia-northeast1,asia-northeast2" {
+		t.Errorf("Unexpected regions: %v", regions)
+	}
+
+	if regions, err := ParseRegions(""); err != nil || regions != nil {
+		t.Errorf("Expected no regions for empty config, got %v (%v)", regions, err)
+	}
+	for _, raw := range []string{"global", "us", "asia-northeast1,Europe-West1"} {
+		if _, err := ParseRegions(raw); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+}
+
+// newRegionalTestRepo points the repository's regional endpoint at server, with per-region status codes
+func newRegionalTestRepo(t *testing.T, regions string, status map[string]int) (GeminiRepository, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var called []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Path: /<region>/projects/<project>/locations/<region>/publishers/google/models/<model>:generateContent
+		region := strings.Split(r.URL.Path, "/")[1]
+		mu.Lock()
+		called = append(called, region)
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer test-token" || r.URL.Query().Get("key") != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if code, ok := status[region]; ok {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "summary from ` + region + `"}]}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GEMINI_REGIONS", regions)
+	t.Setenv("VERTEX_PROJECT", "test-project")
+	repo := NewGeminiRepository("", "test-model", "http://global.invalid")
+	regional := repo.(*geminiRepository).regional
+	regional.urlFormat = server.URL + "/%s/projects/%s/locations/%s/publishers/google/models/%s:generateContent"
+	regional.token = func(ctx context.Context) (string, error) { return "test-token", nil }
+	// The placeholder replies are English; count only the regional calls
+	repo.(*geminiRepository).summaryLanguage = ""
+	// Breakers are not shared with other tests
+	repo.(*geminiRepository).health = isolatedProviderHealth()
+	return repo, &called
+}
+
+func TestGeminiRepository_RegionalFailover(t *testing.T) {
+	repo, called := newRegionalTestRepo(t, "asia-northeast1,asia-northeast2", map[string]int{
+		"asia-northeast1": http.StatusServiceUnavailable,
+	})
+
+	summary, err := repo.SummarizeText(context.Background(), "article text")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary != "summary from asia-northeast2" {
+		t.Errorf("Expected the second region to answer, got %q", summary)
+	}
+	if strings.Join(*called, ",") != "asia-northeast1,asia-northeast2" {
+		t.Errorf("Unexpected regions called: %v", *called)
+	}
+}
+
+func TestGeminiRepository_RegionsUnavailable(t *testing.T) {
+	repo, called := newRegionalTestRepo(t, "asia-northeast1,asia-northeast2", map[string]int{
+		"asia-northeast1": http.StatusServiceUnavailable,
+		"asia-northeast2": http.StatusNotFound, // Model not offered in the region
+	})
+
+	_, err := repo.SummarizeText(context.Background(), "article text")
+	if !errors.Is(err, ErrRegionUnavailable) {
+		t.Fatalf("Expected ErrRegionUnavailable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "asia-northeast1,asia-northeast2") {
+		t.Errorf("Expected the allowed regions in the error, got %v", err)
+	}
+	if len(*called) != 2 {
+		t.Errorf("Expected only the allowed regions to be called, got %v", *called)
+	}
+}
+
+func TestGeminiRepository_RegionalRateLimitNoFailover(t *testing.T) {
+	repo, called := newRegionalTestRepo(t, "asia-northeast1,asia-northeast2", map[string]int{
+		"asia-northeast1": http.StatusTooManyRequests,
+	})
+
+	_, err := repo.SummarizeText(context.Background(), "article text")
+	if !errors.Is(err, httperr.ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if len(*called) != 1 {
+		t.Errorf("Expected no failover on rate limits, got %v", *called)
+	}
+}
+
+func TestGeminiRepository_InvalidRegionsFailClosed(t *testing.T) {
+	t.Setenv("GEMINI_REGIONS", "global")
+	repo := NewGeminiRepository("test-key", "test-model", "http://global.invalid")
+
+	if _, err := repo.SummarizeText(context.Background(), "article text"); !errors.Is(err, ErrRegionUnavailable) {
+		t.Errorf("Expected ErrRegionUnavailable, got %v", err)
+	}
+}
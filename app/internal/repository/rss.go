@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
 )
 
 // Item represents an RSS item
@@ -23,6 +25,23 @@ type Item struct {
 	ParsedDate  time.Time `xml:"-"`
 	Source      string    `xml:"-"`
 	CommentURL  string    `xml:"-"` // コメント/ディスカッションのURL
+	// PromptVariant is the prompt experiment variant used to summarize this item (recorded in the index)
+	PromptVariant string `xml:"-"`
+	// Provenance records how this item's summary was produced (recorded in the index)
+	Provenance *Provenance `xml:"-"`
+	// Breaking marks an item picked by the fast lane (posted immediately with BreakingPrefix)
+	Breaking bool `xml:"-"`
+	// Authors and PDFURL describe papers (arXiv)
+	Authors []string `xml:"-"`
+	PDFURL  string   `xml:"-"`
+	// Likes is the community reaction count (Dev.to reactions, Qiita and Zenn likes)
+	Likes int `xml:"-"`
+	// Votes is the upvote count of a product launch (Product Hunt)
+	Votes int `xml:"-"`
+	// LinkedURL is the external page a post links to (X accounts, Product Hunt websites); Link is the post itself
+	LinkedURL string `xml:"-"`
+	// AudioURL is the episode audio (podcast enclosure), transcribed for the summary
+	AudioURL string `xml:"-"`
 }
 
 func (i *Item) GetUniqueID() string {
@@ -52,12 +71,18 @@ func NewRSSRepository() RSSRepository {
 func (r *rssRepository) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
+	// Local and embedded fixture feeds (simulation mode, workshops)
+	if isFixtureURL(url) {
+		return readFixture(url)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("creating request: %w", err)
 	}
 
-	// Set headers from strategy
+	// Set headers from strategy (compressed feeds are decoded below)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
@@ -65,12 +90,18 @@ func (r *rssRepository) FetchFeedXML(ctx context.Context, url string, headers ma
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		logger.Printf("Error making HTTP request to RSS feed %s: %v request_headers=%v\nStack:\n%s", url, err, req.Header, debug.Stack())
-		return "", fmt.Errorf("fetching feed: %w", err)
+		return "", httperr.Transport(fmt.Errorf("fetching feed: %w", err))
 	}
 	defer resp.Body.Close()
 
+	bodyReader, err := decodedBody(resp)
+	if err != nil {
+		logger.Printf("Error decoding RSS feed url=%s content_encoding=%s: %v", url, resp.Header.Get("Content-Encoding"), err)
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
+		responseBody, _ := io.ReadAll(bodyReader)
 
 		// Limit response body size for logging (first 1000 chars)
 		responseBodyStr := string(responseBody)
@@ -80,10 +111,10 @@ func (r *rssRepository) FetchFeedXML(ctx context.Context, url string, headers ma
 
 		logger.Printf("RSS feed request failed url=%s status_code=%d request_headers=%v response_headers=%v response_body=%s\nStack:\n%s",
 			url, resp.StatusCode, req.Header, resp.Header, responseBodyStr, debug.Stack())
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(bodyReader)
 	if err != nil {
 		return "", fmt.Errorf("reading response body: %w", err)
 	}
@@ -96,6 +127,14 @@ func (r *rssRepository) GetUniqueItems(items []Item) []Item {
 	var unique []Item
 
 	for _, item := range items {
+		// Rewrite AMP/mobile variants to the canonical desktop URL before fetching and deduping
+		if item.Link != "" {
+			item.Link = CanonicalizeURL(item.Link)
+		}
+		// Raw entities would end up in Slack messages and prompts
+		item.Title = decodeEntities(item.Title)
+		item.Description = decodeEntities(item.Description)
+
 		// Always use Link as the primary key for deduplication
 		key := item.Link
 		if key == "" {
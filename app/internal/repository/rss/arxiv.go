@@ -0,0 +1,195 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultArxivAPIURL     = "https://export.arxiv.org/api/query"
+	defaultArxivCategory   = "cs.AI"
+	defaultArxivMaxResults = 20
+	arxivHTMLURL           = "https://arxiv.org/html/"
+)
+
+// arxivCategoryPattern matches arXiv category names such as cs.AI, hep-th or q-bio.NC
+var arxivCategoryPattern = regexp.MustCompile(`^[a-z-]+(\.[A-Za-z-]+)?$`)
+
+// ValidateArxivCategories checks ARXIV_CATEGORIES entries
+func ValidateArxivCategories(categories []string) error {
+	for _, category := range categories {
+		if !arxivCategoryPattern.MatchString(category) {
+			return fmt.Errorf("invalid arXiv category %q (e.g. cs.AI, cs.CL, stat.ML)", category)
+		}
+	}
+	return nil
+}
+
+// arxivFeed is the subset of the arXiv API Atom response mapped into Items
+type arxivFeed struct {
+	Entries []arxivEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type arxivEntry struct {
+	ID        string `xml:"http://www.w3.org/2005/Atom id"`
+	Title     string `xml:"http://www.w3.org/2005/Atom title"`
+	Summary   string `xml:"http://www.w3.org/2005/Atom summary"`
+	Published string `xml:"http://www.w3.org/2005/Atom published"`
+	Authors   []struct {
+		Name string `xml:"http://www.w3.org/2005/Atom name"`
+	} `xml:"http://www.w3.org/2005/Atom author"`
+	Links []struct {
+		Href  string `xml:"href,attr"`
+		Rel   string `xml:"rel,attr"`
+		Type  string `xml:"type,attr"`
+		Title string `xml:"title,attr"`
+	} `xml:"http://www.w3.org/2005/Atom link"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://www.w3.org/2005/Atom category"`
+}
+
+// ArxivRepository reads the newest submissions of the configured categories from the arXiv API
+type ArxivRepository struct {
+	rssRepo    repository.RSSRepository
+	apiURL     string
+	categories []string
+	maxResults int
+}
+
+func NewArxivRepository(rssRepo repository.RSSRepository) *ArxivRepository {
+	repo := &ArxivRepository{
+		rssRepo:    rssRepo,
+		apiURL:     defaultArxivAPIURL,
+		categories: []string{defaultArxivCategory},
+		maxResults: defaultArxivMaxResults,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("ARXIV_API_URL"); env != "" {
+		repo.apiURL = env
+	}
+	if env := os.Getenv("ARXIV_CATEGORIES"); env != "" {
+		var categories []string
+		for _, category := range strings.Split(env, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+		if len(categories) > 0 && ValidateArxivCategories(categories) == nil {
+			repo.categories = categories
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("ARXIV_MAX_RESULTS")); err == nil && n > 0 {
+		repo.maxResults = n
+	}
+	return repo
+}
+
+// queryURL requests the newest submissions of any configured category
+func (a *ArxivRepository) queryURL() string {
+	terms := make([]string, len(a.categories))
+	for i, category := range a.categories {
+		terms[i] = "cat:" + category
+	}
+	query := url.Values{}
+	query.Set("search_query", strings.Join(terms, " OR "))
+	query.Set("sortBy", "submittedDate")
+	query.Set("sortOrder", "descending")
+	query.Set("max_results", strconv.Itoa(a.maxResults))
+	return a.apiURL + "?" + query.Encode()
+}
+
+func (a *ArxivRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (arXiv)",
+		"Accept":     "application/atom+xml",
+	}
+
+	content, err := a.rssRepo.FetchFeedXML(ctx, a.queryURL(), headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching arXiv papers: %w", err)
+	}
+
+	var feed arxivFeed
+	if err := xml.Unmarshal([]byte(content), &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse arXiv API response: %w", err)
+	}
+
+	items := make([]repository.Item, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		items = append(items, arxivItem(entry))
+	}
+	return a.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: arXiv has no discussion threads
+func (a *ArxivRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
+
+// arxivItem maps an entry into an Item linking to the abstract page, with the abstract as description
+func arxivItem(entry arxivEntry) repository.Item {
+	absURL := strings.TrimSpace(entry.ID)
+	var pdfURL string
+	for _, link := range entry.Links {
+		switch {
+		case link.Rel == "alternate" && link.Type == "text/html":
+			absURL = link.Href
+		case link.Title == "pdf" || link.Type == "application/pdf":
+			pdfURL = link.Href
+		}
+	}
+	// arXiv API links are http://; the site redirects them anyway
+	absURL = strings.Replace(absURL, "http://", "https://", 1)
+	pdfURL = strings.Replace(pdfURL, "http://", "https://", 1)
+
+	authors := make([]string, 0, len(entry.Authors))
+	for _, author := range entry.Authors {
+		authors = append(authors, strings.TrimSpace(author.Name))
+	}
+	categories := make([]string, 0, len(entry.Categories))
+	for _, category := range entry.Categories {
+		categories = append(categories, category.Term)
+	}
+
+	item := repository.Item{
+		Title:       collapseSpace(entry.Title),
+		Link:        absURL,
+		Description: collapseSpace(entry.Summary),
+		PubDate:     entry.Published,
+		GUID:        strings.TrimSpace(entry.ID),
+		Category:    categories,
+		Source:      "arxiv",
+		Authors:     authors,
+		PDFURL:      pdfURL,
+	}
+	if published, err := time.Parse(time.RFC3339, entry.Published); err == nil {
+		item.ParsedDate = published
+		item.PubDate = published.Format(time.RFC1123Z)
+	}
+	return item
+}
+
+// ArxivHTMLURL returns the HTML rendering of a paper (arxiv.org/abs/<id> → arxiv.org/html/<id>),
+// or "" for links that are not abstract pages
+func ArxivHTMLURL(absURL string) string {
+	_, id, found := strings.Cut(absURL, "arxiv.org/abs/")
+	if !found || id == "" {
+		return ""
+	}
+	return arxivHTMLURL + id
+}
+
+// collapseSpace joins the hard-wrapped lines of arXiv titles and abstracts
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,83 @@
+package rss
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+const arxivFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>http://arxiv.org/abs/2401.01234v2</id>
+    <published>2024-01-03T18:59:59Z</published>
+    <title>Scaling Laws for
+  Test Models</title>
+    <summary>  We measure how test models scale
+  with data.</summary>
+    <author><name>Alice Example</name></author>
+    <author><name>Bob Example</name></author>
+    <link href="http://arxiv.org/abs/2401.01234v2" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/2401.01234v2" rel="related" type="application/pdf"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+func TestArxivRepository_FetchArticles(t *testing.T) {
+	t.Setenv("ARXIV_CATEGORIES", "cs.AI, cs.LG")
+	t.Setenv("ARXIV_MAX_RESULTS", "5")
+	repo := NewArxivRepository(nil)
+
+	query, err := url.Parse(repo.queryURL())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := query.Query().Get("search_query"); got != "cat:cs.AI OR cat:cs.LG" {
+		t.Errorf("Unexpected search query %q", got)
+	}
+	if query.Query().Get("max_results") != "5" || query.Query().Get("sortBy") != "submittedDate" {
+		t.Errorf("Unexpected query %s", query.RawQuery)
+	}
+
+	repo.rssRepo = &stubFetcher{docs: map[string]string{repo.queryURL(): arxivFixture}}
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 paper, got %d", len(items))
+	}
+
+	paper := items[0]
+	if paper.Title != "Scaling Laws for Test Models" || paper.Description != "We measure how test models scale with data." {
+		t.Errorf("Expected unwrapped title and abstract, got %q / %q", paper.Title, paper.Description)
+	}
+	if paper.Link != "https://arxiv.org/abs/2401.01234v2" || paper.PDFURL != "https://arxiv.org/pdf/2401.01234v2" {
+		t.Errorf("Unexpected links: %s %s", paper.Link, paper.PDFURL)
+	}
+	if len(paper.Authors) != 2 || paper.Authors[1] != "Bob Example" {
+		t.Errorf("Unexpected authors: %v", paper.Authors)
+	}
+	if paper.Source != "arxiv" || paper.ParsedDate.IsZero() || len(paper.Category) != 2 {
+		t.Errorf("Unexpected item: %+v", paper)
+	}
+}
+
+func TestArxivHTMLURL(t *testing.T) {
+	if got := ArxivHTMLURL("https://arxiv.org/abs/2401.01234v2"); got != "https://arxiv.org/html/2401.01234v2" {
+		t.Errorf("Unexpected HTML URL %s", got)
+	}
+	if got := ArxivHTMLURL("https://example.com/paper"); got != "" {
+		t.Errorf("Expected no HTML URL for other links, got %s", got)
+	}
+}
+
+func TestValidateArxivCategories(t *testing.T) {
+	if err := ValidateArxivCategories([]string{"cs.AI", "hep-th", "q-bio.NC"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateArxivCategories([]string{"cs.AI OR 1"}); err == nil {
+		t.Error("Expected error for an invalid category")
+	}
+}
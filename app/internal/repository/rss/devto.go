@@ -0,0 +1,103 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultDevToAPIURL       = "https://dev.to/api"
+	defaultDevToMinReactions = 20
+	devToMaxArticles         = 30
+)
+
+// DevToArticle is an article of the Dev.to (Forem) articles API
+type DevToArticle struct {
+	ID                     int      `json:"id"`
+	Title                  string   `json:"title"`
+	Description            string   `json:"description"`
+	URL                    string   `json:"url"`
+	PublishedAt            string   `json:"published_at"`
+	TagList                []string `json:"tag_list"`
+	PositiveReactionsCount int      `json:"positive_reactions_count"`
+	User                   struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+// DevToRepository reads Dev.to's top articles of the last day
+type DevToRepository struct {
+	rssRepo      repository.RSSRepository
+	apiURL       string
+	minReactions int
+}
+
+func NewDevToRepository(rssRepo repository.RSSRepository) *DevToRepository {
+	repo := &DevToRepository{
+		rssRepo:      rssRepo,
+		apiURL:       defaultDevToAPIURL,
+		minReactions: defaultDevToMinReactions,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("DEVTO_API_URL"); env != "" {
+		repo.apiURL = strings.TrimRight(env, "/")
+	}
+	if n, err := strconv.Atoi(os.Getenv("DEVTO_MIN_REACTIONS")); err == nil && n >= 0 {
+		repo.minReactions = n
+	}
+	return repo
+}
+
+// FetchArticles keeps the day's top articles at or above the reaction threshold
+func (d *DevToRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Dev.to)",
+		"Accept":     "application/json",
+	}
+
+	content, err := d.rssRepo.FetchFeedXML(ctx, fmt.Sprintf("%s/articles?top=1&per_page=%d", d.apiURL, devToMaxArticles), headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Dev.to articles: %w", err)
+	}
+
+	var articles []DevToArticle
+	if err := json.Unmarshal([]byte(content), &articles); err != nil {
+		return nil, fmt.Errorf("failed to parse Dev.to articles: %w", err)
+	}
+
+	var items []repository.Item
+	for _, article := range articles {
+		if article.URL == "" || article.PositiveReactionsCount < d.minReactions {
+			continue
+		}
+		item := repository.Item{
+			Title:       article.Title,
+			Link:        article.URL,
+			Description: article.Description,
+			PubDate:     article.PublishedAt,
+			GUID:        article.URL,
+			Category:    article.TagList,
+			Source:      "devto",
+			Likes:       article.PositiveReactionsCount,
+			Authors:     []string{article.User.Name},
+		}
+		if published, err := time.Parse(time.RFC3339, article.PublishedAt); err == nil {
+			item.ParsedDate = published
+			item.PubDate = published.Format(time.RFC1123Z)
+		}
+		items = append(items, item)
+	}
+	return d.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: only the articles are summarized
+func (d *DevToRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
@@ -0,0 +1,34 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDevToRepository_FetchArticles(t *testing.T) {
+	t.Setenv("DEVTO_MIN_REACTIONS", "50")
+	repo := NewDevToRepository(nil)
+	repo.rssRepo = &stubFetcher{docs: map[string]string{
+		defaultDevToAPIURL + "/articles?top=1&per_page=30": `[
+  {"id":1,"title":"Popular post","description":"About Go","url":"https://dev.to/alice/popular-post","published_at":"2024-01-02T10:00:00Z",
+   "tag_list":["go","webdev"],"positive_reactions_count":120,"user":{"name":"Alice"}},
+  {"id":2,"title":"Quiet post","url":"https://dev.to/bob/quiet-post","published_at":"2024-01-02T11:00:00Z",
+   "tag_list":["rust"],"positive_reactions_count":10,"user":{"name":"Bob"}}
+]`,
+	}}
+
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected only the post above the reaction threshold, got %d", len(items))
+	}
+	post := items[0]
+	if post.Source != "devto" || post.Likes != 120 || len(post.Category) != 2 || post.Category[0] != "go" {
+		t.Errorf("Unexpected item: %+v", post)
+	}
+	if len(post.Authors) != 1 || post.Authors[0] != "Alice" || post.ParsedDate.IsZero() {
+		t.Errorf("Unexpected author or date: %+v", post)
+	}
+}
@@ -0,0 +1,39 @@
+package rss
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestSimulationFixtures_Parse(t *testing.T) {
+	rssRepo := repository.NewSimulationRSSRepository(repository.NewRSSRepository())
+	ctx := context.Background()
+
+	hatena, err := NewHatenaRSSRepository(rssRepo).FetchArticles(ctx)
+	if err != nil || len(hatena) != 3 {
+		t.Errorf("Hatena fixture: got %d items, err %v", len(hatena), err)
+	}
+
+	reddit, err := NewRedditRSSRepository(rssRepo).FetchArticles(ctx)
+	if err != nil || len(reddit) != 2 {
+		t.Fatalf("Reddit fixture: got %d items, err %v", len(reddit), err)
+	}
+	if reddit[0].Link != "https://go.dev/blog/range-functions" {
+		t.Errorf("Expected the external article link, got %s", reddit[0].Link)
+	}
+
+	lobsters, err := NewLobstersRSSRepository(rssRepo).FetchArticles(ctx)
+	if err != nil || len(lobsters) != 2 {
+		t.Errorf("Lobsters fixture: expected 2 items after the ask filter, got %d, err %v", len(lobsters), err)
+	}
+}
+
+func TestSimulationFixtures_CommentsDisabled(t *testing.T) {
+	rssRepo := repository.NewSimulationRSSRepository(repository.NewRSSRepository())
+
+	if _, err := NewHatenaRSSRepository(rssRepo).FetchComments(context.Background(), "https://go.dev/blog/go1.23"); err == nil {
+		t.Error("Expected comment fetches to be refused in simulation mode")
+	}
+}
@@ -0,0 +1,298 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// genericFeedNamePattern keeps feed names usable as sources, URL path segments and env-style keys
+var genericFeedNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// GenericFeedSpec describes a plain feed entirely from configuration
+type GenericFeedSpec struct {
+	Name              string            `json:"name"` // Source of its Items
+	URL               string            `json:"url"`
+	Headers           map[string]string `json:"headers"`            // Extra request headers (override the defaults)
+	IncludeCategories []string          `json:"include_categories"` // Keep only items with one of these categories
+	ExcludeCategories []string          `json:"exclude_categories"` // Drop items with any of these categories
+	Channel           string            `json:"channel"`            // Slack channel (defaults to SLACK_CHANNEL)
+	Schedule          string            `json:"schedule"`           // Minimum interval between scheduled runs, e.g. "6h"
+	ActiveWindow      string            `json:"active_window"`      // Time of day scheduled runs happen in, e.g. "07:00-23:00"
+	Owner             string            `json:"owner"`              // Slack user or user group ID alerted when the feed fails persistently
+	DedupTTLDays      int               `json:"dedup_ttl_days"`     // Days after which a processed item counts as new again (0 = never)
+
+	interval time.Duration
+	window   *ActiveWindow
+}
+
+// Interval returns the parsed schedule (0 runs the feed on every scheduled call)
+func (s GenericFeedSpec) Interval() time.Duration {
+	return s.interval
+}
+
+// Window returns the parsed active window (nil runs the feed at any hour)
+func (s GenericFeedSpec) Window() *ActiveWindow {
+	return s.window
+}
+
+// ParseGenericFeedSpecs parses GENERIC_FEEDS, a JSON array of feeds such as
+// [{"name":"go-blog","url":"https://go.dev/blog/feed.atom","exclude_categories":["ask"],"schedule":"6h","active_window":"07:00-23:00","owner":"S0123ABCD","dedup_ttl_days":90}]
+func ParseGenericFeedSpecs(raw string) ([]GenericFeedSpec, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var specs []GenericFeedSpec
+	if err := json.Unmarshal([]byte(raw), &specs); err != nil {
+		return nil, fmt.Errorf("parsing generic feeds: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range specs {
+		spec := &specs[i]
+		if !genericFeedNamePattern.MatchString(spec.Name) {
+			return nil, fmt.Errorf("generic feed %d: name %q must be lowercase letters, digits and dashes", i, spec.Name)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("generic feed %d: duplicate name %q", i, spec.Name)
+		}
+		seen[spec.Name] = true
+		if spec.URL == "" {
+			return nil, fmt.Errorf("generic feed %s: url is required", spec.Name)
+		}
+		if spec.Schedule != "" {
+			interval, err := time.ParseDuration(spec.Schedule)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("generic feed %s: schedule must be a positive duration such as 6h", spec.Name)
+			}
+			spec.interval = interval
+		}
+		window, err := ParseActiveWindow(spec.ActiveWindow)
+		if err != nil {
+			return nil, fmt.Errorf("generic feed %s: %w", spec.Name, err)
+		}
+		spec.window = window
+		if err := repository.ValidateFeedOwner(spec.Owner); err != nil {
+			return nil, fmt.Errorf("generic feed %s: %w", spec.Name, err)
+		}
+		if spec.DedupTTLDays < 0 {
+			return nil, fmt.Errorf("generic feed %s: dedup_ttl_days must not be negative", spec.Name)
+		}
+	}
+	return specs, nil
+}
+
+// GenericFeedRepository reads any RSS 2.0, RSS 1.0 (RDF), Atom or JSON Feed document,
+// for feeds that do not need feed-specific handling (GENERIC_FEEDS and OPML subscriptions)
+type GenericFeedRepository struct {
+	rssRepo repository.RSSRepository
+	spec    GenericFeedSpec
+}
+
+func NewGenericFeedRepository(rssRepo repository.RSSRepository, spec GenericFeedSpec) *GenericFeedRepository {
+	return &GenericFeedRepository{
+		rssRepo: rssRepo,
+		spec:    spec,
+	}
+}
+
+func (g *GenericFeedRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Feed)",
+		"Accept":     "application/rss+xml, application/atom+xml, application/feed+json, application/xml, text/xml",
+	}
+	for key, value := range g.spec.Headers {
+		headers[key] = value
+	}
+
+	content, err := g.rssRepo.FetchFeedXML(ctx, g.spec.URL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed %s: %w", g.spec.URL, err)
+	}
+
+	items, err := ParseGenericFeed(content, g.spec.Name)
+	if err != nil {
+		return nil, err
+	}
+	return g.rssRepo.GetUniqueItems(g.filterItems(items)), nil
+}
+
+// filterItems applies the spec's category filters (case-insensitive)
+func (g *GenericFeedRepository) filterItems(items []repository.Item) []repository.Item {
+	if len(g.spec.IncludeCategories) == 0 && len(g.spec.ExcludeCategories) == 0 {
+		return items
+	}
+
+	var filtered []repository.Item
+	for _, item := range items {
+		if len(g.spec.IncludeCategories) > 0 && !hasAnyCategory(item.Category, g.spec.IncludeCategories) {
+			continue
+		}
+		if hasAnyCategory(item.Category, g.spec.ExcludeCategories) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+func hasAnyCategory(categories, wanted []string) bool {
+	for _, category := range categories {
+		for _, w := range wanted {
+			if strings.EqualFold(strings.TrimSpace(category), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FetchComments is not supported: generic feeds have no comment threads
+func (g *GenericFeedRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{}, nil
+}
+
+// genericFeed covers the elements of RSS 2.0 (channel/item), RSS 1.0 (item under rdf:RDF) and Atom (entry)
+type genericFeed struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []genericItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []genericItem `xml:"item"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		ID        string `xml:"id"`
+		Category  []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+type genericItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"date"` // dc:date (RSS 1.0)
+	GUID        string   `xml:"guid"`
+	Category    []string `xml:"category"`
+	Comments    string   `xml:"comments"`
+}
+
+// ParseGenericFeed detects the feed format and maps its entries into Items tagged with source
+func ParseGenericFeed(content, source string) ([]repository.Item, error) {
+	if strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return ParseJSONFeed(content, source)
+	}
+
+	var feed genericFeed
+	if err := xml.Unmarshal([]byte(content), &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var items []repository.Item
+	switch feed.XMLName.Local {
+	case "rss", "RDF":
+		entries := feed.Channel.Items
+		if feed.XMLName.Local == "RDF" {
+			entries = feed.Items
+		}
+		for _, item := range entries {
+			link := strings.TrimSpace(item.Link)
+			if link == "" {
+				continue
+			}
+			date := item.PubDate
+			if date == "" {
+				date = item.Date
+			}
+			guid := item.GUID
+			if guid == "" {
+				guid = link
+			}
+			items = append(items, repository.Item{
+				Title:       strings.TrimSpace(item.Title),
+				Link:        link,
+				Description: item.Description,
+				PubDate:     date,
+				GUID:        guid,
+				Category:    item.Category,
+				ParsedDate:  parseGenericDate(date),
+				Source:      source,
+				CommentURL:  item.Comments,
+			})
+		}
+	case "feed":
+		for _, entry := range feed.Entries {
+			var link string
+			for _, l := range entry.Links {
+				// rel is "alternate" when omitted
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = strings.TrimSpace(l.Href)
+					break
+				}
+			}
+			if link == "" {
+				continue
+			}
+			date := entry.Published
+			if date == "" {
+				date = entry.Updated
+			}
+			description := entry.Summary
+			if description == "" {
+				description = entry.Content
+			}
+			var categories []string
+			for _, c := range entry.Category {
+				categories = append(categories, c.Term)
+			}
+			items = append(items, repository.Item{
+				Title:       strings.TrimSpace(entry.Title),
+				Link:        link,
+				Description: description,
+				PubDate:     date,
+				GUID:        entry.ID,
+				Category:    categories,
+				ParsedDate:  parseGenericDate(date),
+				Source:      source,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed format: <%s>", feed.XMLName.Local)
+	}
+
+	return items, nil
+}
+
+// parseGenericDate accepts the RSS (RFC 1123) and Atom/dc:date (RFC 3339) formats; zero when unparseable
+func parseGenericDate(dateStr string) time.Time {
+	formats := []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		time.RFC3339,
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, strings.TrimSpace(dateStr)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
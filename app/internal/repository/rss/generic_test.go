@@ -0,0 +1,147 @@
+package rss
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestParseGenericFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		link    string
+		comment string
+	}{
+		{
+			name: "rss 2.0",
+			content: `<?xml version="1.0"?><rss version="2.0"><channel>
+<item><title>Post</title><link>https://blog.example.com/post</link><pubDate>Tue, 02 Jan 2024 10:00:00 +0000</pubDate><comments>https://blog.example.com/post#comments</comments></item>
+<item><title>No link</title></item>
+</channel></rss>`,
+			link:    "https://blog.example.com/post",
+			comment: "https://blog.example.com/post#comments",
+		},
+		{
+			name: "rss 1.0",
+			content: `<?xml version="1.0"?><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel><title>Blog</title></channel>
+<item><title>Post</title><link>https://blog.example.com/post</link><dc:date>2024-01-02T10:00:00+09:00</dc:date></item>
+</rdf:RDF>`,
+			link: "https://blog.example.com/post",
+		},
+		{
+			name: "atom",
+			content: `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom">
+<entry><title>Post</title><link rel="replies" href="https://blog.example.com/post/comments"/><link href="https://blog.example.com/post"/><id>tag:blog,2024:1</id><updated>2024-01-02T10:00:00Z</updated></entry>
+</feed>`,
+			link: "https://blog.example.com/post",
+		},
+		{
+			name:    "json feed",
+			content: `{"version":"https://jsonfeed.org/version/1.1","items":[{"id":"1","url":"https://blog.example.com/post","title":"Post","date_published":"2024-01-02T10:00:00Z"}]}`,
+			link:    "https://blog.example.com/post",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ParseGenericFeed(tt.content, "opml:blog")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(items) != 1 {
+				t.Fatalf("Expected 1 item, got %d", len(items))
+			}
+			item := items[0]
+			if item.Link != tt.link || item.Title != "Post" || item.Source != "opml:blog" {
+				t.Errorf("Unexpected item: %+v", item)
+			}
+			if item.ParsedDate.IsZero() {
+				t.Error("Expected date to be parsed")
+			}
+			if item.CommentURL != tt.comment {
+				t.Errorf("Expected comment URL %q, got %q", tt.comment, item.CommentURL)
+			}
+		})
+	}
+
+	if _, err := ParseGenericFeed(`<html><body>Not a feed</body></html>`, "opml:blog"); err == nil {
+		t.Error("Expected error for an HTML page")
+	}
+}
+
+func TestParseGenericFeedSpecs(t *testing.T) {
+	specs, err := ParseGenericFeedSpecs(`[
+		{"name":"go-blog","url":"https://go.dev/blog/feed.atom","schedule":"6h","channel":"#go","owner":"S0123ABCD","dedup_ttl_days":90},
+		{"name":"zenn","url":"https://zenn.dev/feed","headers":{"User-Agent":"custom"}}
+	]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("Expected 2 specs, got %d", len(specs))
+	}
+	if specs[0].Interval() != 6*time.Hour || specs[0].Channel != "#go" || specs[0].Owner != "S0123ABCD" || specs[0].DedupTTLDays != 90 {
+		t.Errorf("Unexpected spec: %+v", specs[0])
+	}
+	if specs[1].Interval() != 0 || specs[1].Headers["User-Agent"] != "custom" {
+		t.Errorf("Unexpected spec: %+v", specs[1])
+	}
+
+	if specs, err := ParseGenericFeedSpecs(""); err != nil || specs != nil {
+		t.Errorf("Expected no specs for empty config, got %v, %v", specs, err)
+	}
+
+	invalid := map[string]string{
+		"bad json":       `{"name":"x"}`,
+		"missing name":   `[{"url":"https://example.com/feed"}]`,
+		"uppercase name": `[{"name":"Blog","url":"https://example.com/feed"}]`,
+		"missing url":    `[{"name":"blog"}]`,
+		"duplicate name": `[{"name":"blog","url":"https://a.example.com/feed"},{"name":"blog","url":"https://b.example.com/feed"}]`,
+		"bad schedule":   `[{"name":"blog","url":"https://example.com/feed","schedule":"daily"}]`,
+		"zero schedule":  `[{"name":"blog","url":"https://example.com/feed","schedule":"0s"}]`,
+		"channel owner":  `[{"name":"blog","url":"https://example.com/feed","owner":"#blog-team"}]`,
+		"negative ttl":   `[{"name":"blog","url":"https://example.com/feed","dedup_ttl_days":-1}]`,
+	}
+	for name, raw := range invalid {
+		if _, err := ParseGenericFeedSpecs(raw); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestGenericFeedRepository_CategoryFilters(t *testing.T) {
+	items := []repository.Item{
+		{Link: "https://example.com/a", Category: []string{"Go"}},
+		{Link: "https://example.com/b", Category: []string{"go", "ask"}},
+		{Link: "https://example.com/c", Category: []string{"rust"}},
+		{Link: "https://example.com/d"},
+	}
+
+	tests := []struct {
+		name  string
+		spec  GenericFeedSpec
+		links []string
+	}{
+		{"no filters", GenericFeedSpec{}, []string{"https://example.com/a", "https://example.com/b", "https://example.com/c", "https://example.com/d"}},
+		{"include", GenericFeedSpec{IncludeCategories: []string{"go"}}, []string{"https://example.com/a", "https://example.com/b"}},
+		{"exclude", GenericFeedSpec{ExcludeCategories: []string{"ASK"}}, []string{"https://example.com/a", "https://example.com/c", "https://example.com/d"}},
+		{"include and exclude", GenericFeedSpec{IncludeCategories: []string{"go"}, ExcludeCategories: []string{"ask"}}, []string{"https://example.com/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := NewGenericFeedRepository(nil, tt.spec).filterItems(items)
+			var links []string
+			for _, item := range filtered {
+				links = append(links, item.Link)
+			}
+			if strings.Join(links, ",") != strings.Join(tt.links, ",") {
+				t.Errorf("Expected %v, got %v", tt.links, links)
+			}
+		})
+	}
+}
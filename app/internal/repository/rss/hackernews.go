@@ -0,0 +1,220 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultHackerNewsAPIURL     = "https://hacker-news.firebaseio.com/v0"
+	defaultHackerNewsAlgoliaURL = "https://hn.algolia.com/api/v1"
+	hackerNewsItemURL           = "https://news.ycombinator.com/item?id="
+	defaultHackerNewsMinScore   = 100
+	defaultHackerNewsMaxStories = 30
+)
+
+// HackerNewsStory is a story from the official Firebase API
+type HackerNewsStory struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Text        string `json:"text"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Dead        bool   `json:"dead"`
+	Deleted     bool   `json:"deleted"`
+}
+
+// HackerNewsComment is a node of the Algolia item tree (the story itself is the root)
+type HackerNewsComment struct {
+	Author   string              `json:"author"`
+	Text     string              `json:"text"`
+	Children []HackerNewsComment `json:"children"`
+}
+
+type HackerNewsRepository struct {
+	rssRepo    repository.RSSRepository
+	apiURL     string
+	algoliaURL string
+	storyList  string // "top" or "best"
+	minScore   int
+	maxStories int
+}
+
+func NewHackerNewsRepository(rssRepo repository.RSSRepository) *HackerNewsRepository {
+	repo := &HackerNewsRepository{
+		rssRepo:    rssRepo,
+		apiURL:     defaultHackerNewsAPIURL,
+		algoliaURL: defaultHackerNewsAlgoliaURL,
+		storyList:  "top",
+		minScore:   defaultHackerNewsMinScore,
+		maxStories: defaultHackerNewsMaxStories,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("HN_API_URL"); env != "" {
+		repo.apiURL = strings.TrimRight(env, "/")
+	}
+	if env := os.Getenv("HN_ALGOLIA_URL"); env != "" {
+		repo.algoliaURL = strings.TrimRight(env, "/")
+	}
+	if env := os.Getenv("HN_STORY_LIST"); env == "best" {
+		repo.storyList = env
+	}
+	if n, err := strconv.Atoi(os.Getenv("HN_MIN_SCORE")); err == nil && n >= 0 {
+		repo.minScore = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("HN_MAX_STORIES")); err == nil && n > 0 {
+		repo.maxStories = n
+	}
+	return repo
+}
+
+func (h *HackerNewsRepository) headers() map[string]string {
+	return map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Hacker News)",
+		"Accept":     "application/json",
+	}
+}
+
+// FetchArticles reads the top (or best) story ids and keeps the stories at or above the score threshold
+func (h *HackerNewsRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	listURL := fmt.Sprintf("%s/%sstories.json", h.apiURL, h.storyList)
+	content, err := h.rssRepo.FetchFeedXML(ctx, listURL, h.headers())
+	if err != nil {
+		return nil, fmt.Errorf("fetching Hacker News %s stories: %w", h.storyList, err)
+	}
+
+	var ids []int
+	if err := json.Unmarshal([]byte(content), &ids); err != nil {
+		return nil, fmt.Errorf("failed to parse Hacker News story list: %w", err)
+	}
+	if len(ids) > h.maxStories {
+		ids = ids[:h.maxStories]
+	}
+
+	var items []repository.Item
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		content, err := h.rssRepo.FetchFeedXML(ctx, fmt.Sprintf("%s/item/%d.json", h.apiURL, id), h.headers())
+		if err != nil {
+			return nil, fmt.Errorf("fetching Hacker News item %d: %w", id, err)
+		}
+
+		var story HackerNewsStory
+		if err := json.Unmarshal([]byte(content), &story); err != nil {
+			return nil, fmt.Errorf("failed to parse Hacker News item %d: %w", id, err)
+		}
+		if story.Type != "story" || story.Dead || story.Deleted || story.Score < h.minScore {
+			continue
+		}
+		items = append(items, hackerNewsItem(story))
+	}
+
+	return h.rssRepo.GetUniqueItems(items), nil
+}
+
+// hackerNewsItem maps a story into an Item; Ask/Show HN posts without a URL link to the discussion itself
+func hackerNewsItem(story HackerNewsStory) repository.Item {
+	discussionURL := hackerNewsItemURL + strconv.Itoa(story.ID)
+	link := strings.TrimSpace(story.URL)
+	if link == "" {
+		link = discussionURL
+	}
+	published := time.Unix(story.Time, 0).UTC()
+
+	return repository.Item{
+		Title:       story.Title,
+		Link:        link,
+		Description: hackerNewsPlainText(story.Text),
+		PubDate:     published.Format(time.RFC1123Z),
+		GUID:        discussionURL,
+		ParsedDate:  published,
+		Source:      "hackernews",
+		CommentURL:  discussionURL,
+	}
+}
+
+// FetchComments reads the discussion tree of a news.ycombinator.com/item?id=... URL from the Algolia API
+func (h *HackerNewsRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	parsed, err := url.Parse(commentURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Hacker News comment URL: %w", err)
+	}
+	id := parsed.Query().Get("id")
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, fmt.Errorf("invalid Hacker News comment URL: %s", commentURL)
+	}
+
+	content, err := h.rssRepo.FetchFeedXML(ctx, fmt.Sprintf("%s/items/%s", h.algoliaURL, id), h.headers())
+	if err != nil {
+		return nil, fmt.Errorf("fetching Hacker News comments: %w", err)
+	}
+
+	var root HackerNewsComment
+	if err := json.Unmarshal([]byte(content), &root); err != nil {
+		return nil, fmt.Errorf("failed to parse Hacker News comments JSON: %w", err)
+	}
+
+	var commentTexts []string
+	extractHackerNewsComments(root.Children, &commentTexts)
+	if len(commentTexts) == 0 {
+		return &Comments{Text: ""}, nil
+	}
+
+	return &Comments{
+		Text: combineHackerNewsComments(commentTexts),
+	}, nil
+}
+
+// extractHackerNewsComments flattens the comment tree depth-first (deleted comments have no text)
+func extractHackerNewsComments(comments []HackerNewsComment, commentTexts *[]string) {
+	for _, comment := range comments {
+		if text := hackerNewsPlainText(comment.Text); text != "" {
+			*commentTexts = append(*commentTexts, comment.Author+": "+text)
+		}
+		if len(comment.Children) > 0 {
+			extractHackerNewsComments(comment.Children, commentTexts)
+		}
+	}
+}
+
+// combineHackerNewsComments joins comments up to the same 10KB budget as Reddit
+func combineHackerNewsComments(commentTexts []string) string {
+	var parts []string
+	totalLength := 0
+	const maxLength = 10000 // 10KB limit
+
+	for _, text := range commentTexts {
+		if totalLength+len(text)+2 > maxLength {
+			break
+		}
+		parts = append(parts, text)
+		totalLength += len(text) + 2
+	}
+
+	return "以下はHacker Newsのコメントです:\n\n" + strings.Join(parts, "\n\n")
+}
+
+var hackerNewsTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// hackerNewsPlainText converts the HTML fragments HN uses for text (<p>, <a>, <i>, entities) into plain text
+func hackerNewsPlainText(text string) string {
+	text = strings.ReplaceAll(text, "<p>", "\n")
+	text = hackerNewsTagPattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
@@ -0,0 +1,95 @@
+package rss
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func hackerNewsFixtures() *stubFetcher {
+	return &stubFetcher{docs: map[string]string{
+		"https://hacker-news.firebaseio.com/v0/topstories.json": `[1, 2, 3, 4]`,
+		"https://hacker-news.firebaseio.com/v0/item/1.json":     `{"id":1,"type":"story","time":1704067200,"title":"Linked story","url":"https://example.com/post","score":320}`,
+		"https://hacker-news.firebaseio.com/v0/item/2.json":     `{"id":2,"type":"story","time":1704067200,"title":"Ask HN: Something?","text":"Question &amp; details<p>More","score":150}`,
+		"https://hacker-news.firebaseio.com/v0/item/3.json":     `{"id":3,"type":"story","time":1704067200,"title":"Below threshold","url":"https://example.com/low","score":12}`,
+		"https://hacker-news.firebaseio.com/v0/item/4.json":     `{"id":4,"type":"job","time":1704067200,"title":"Hiring","url":"https://example.com/jobs","score":500}`,
+		"https://hn.algolia.com/api/v1/items/1": `{"id":1,"children":[
+			{"author":"alice","text":"<p>Great <i>write-up</i></p>","children":[{"author":"bob","text":"Agreed &gt; 1","children":[]}]},
+			{"author":null,"text":null,"children":[{"author":"carol","text":"Reply to a deleted comment","children":[]}]}
+		]}`,
+		"https://hn.algolia.com/api/v1/items/2": `{"id":2,"children":[]}`,
+	}}
+}
+
+func TestHackerNewsRepository_FetchArticles(t *testing.T) {
+	repo := NewHackerNewsRepository(hackerNewsFixtures())
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 stories above the score threshold, got %d", len(items))
+	}
+
+	linked := items[0]
+	if linked.Link != "https://example.com/post" {
+		t.Errorf("Expected story URL as link, got '%s'", linked.Link)
+	}
+	if linked.CommentURL != "https://news.ycombinator.com/item?id=1" {
+		t.Errorf("Expected discussion URL as comment URL, got '%s'", linked.CommentURL)
+	}
+	if linked.Source != "hackernews" || linked.ParsedDate.IsZero() {
+		t.Errorf("Unexpected item: %+v", linked)
+	}
+
+	ask := items[1]
+	if ask.Link != "https://news.ycombinator.com/item?id=2" {
+		t.Errorf("Expected Ask HN to link to the discussion, got '%s'", ask.Link)
+	}
+	if ask.Description != "Question & details\nMore" {
+		t.Errorf("Expected plain-text description, got %q", ask.Description)
+	}
+}
+
+func TestHackerNewsRepository_FetchArticles_Settings(t *testing.T) {
+	t.Setenv("HN_MIN_SCORE", "0")
+	t.Setenv("HN_MAX_STORIES", "3")
+
+	repo := NewHackerNewsRepository(hackerNewsFixtures())
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Story 4 is past the limit; story 3 passes the lowered threshold
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 stories, got %d", len(items))
+	}
+}
+
+func TestHackerNewsRepository_FetchComments(t *testing.T) {
+	repo := NewHackerNewsRepository(hackerNewsFixtures())
+	comments, err := repo.FetchComments(context.Background(), "https://news.ycombinator.com/item?id=1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"alice: Great write-up", "bob: Agreed > 1", "carol: Reply to a deleted comment"} {
+		if !strings.Contains(comments.Text, want) {
+			t.Errorf("Expected comments to contain %q, got %q", want, comments.Text)
+		}
+	}
+
+	empty, err := repo.FetchComments(context.Background(), "https://news.ycombinator.com/item?id=2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if empty.Text != "" {
+		t.Errorf("Expected no comment text, got %q", empty.Text)
+	}
+
+	if _, err := repo.FetchComments(context.Background(), "https://example.com/post"); err == nil {
+		t.Error("Expected error for a non-HN comment URL")
+	}
+}
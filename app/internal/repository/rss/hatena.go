@@ -25,7 +25,7 @@ func NewHatenaRSSRepository(rssRepo repository.RSSRepository) *HatenaRSSReposito
 
 func (h *HatenaRSSRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
 	url := "https://b.hatena.ne.jp/hotentry/it.rss"
-	// テスト用URLオーバーライド
+	// テスト用URLオーバーライド（file:// / fixture:// も可）
 	if testURL := os.Getenv("HATENA_RSS_URL"); testURL != "" {
 		url = testURL
 	}
@@ -0,0 +1,152 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// jsonFeedVersionPrefix matches JSON Feed 1.0 and 1.1 documents
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/1"
+
+// JSONFeedRepository reads a JSON Feed (jsonfeed.org, v1.0/v1.1) for blogs that only publish feed.json
+type JSONFeedRepository struct {
+	rssRepo repository.RSSRepository
+	feedURL string
+	source  string
+}
+
+func NewJSONFeedRepository(rssRepo repository.RSSRepository, feedURL, source string) *JSONFeedRepository {
+	return &JSONFeedRepository{
+		rssRepo: rssRepo,
+		feedURL: feedURL,
+		source:  source,
+	}
+}
+
+// jsonFeed is the subset of a JSON Feed document mapped into Items
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            json.RawMessage `json:"id"` // A string per spec, but numbers appear in the wild
+	URL           string          `json:"url"`
+	ExternalURL   string          `json:"external_url"`
+	Title         string          `json:"title"`
+	Summary       string          `json:"summary"`
+	ContentText   string          `json:"content_text"`
+	ContentHTML   string          `json:"content_html"`
+	DatePublished string          `json:"date_published"`
+	DateModified  string          `json:"date_modified"`
+	Tags          []string        `json:"tags"`
+}
+
+func (j *JSONFeedRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (JSON Feed)",
+		"Accept":     "application/feed+json, application/json",
+	}
+
+	content, err := j.rssRepo.FetchFeedXML(ctx, j.feedURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching JSON feed: %w", err)
+	}
+
+	items, err := ParseJSONFeed(content, j.source)
+	if err != nil {
+		return nil, err
+	}
+	return j.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: JSON Feed has no comment threads
+func (j *JSONFeedRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{}, nil
+}
+
+// ParseJSONFeed maps a JSON Feed document into Items tagged with source.
+// Link blogs put the linked article in external_url; the post itself then becomes the CommentURL.
+func ParseJSONFeed(content, source string) ([]repository.Item, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal([]byte(content), &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Feed format: %w", err)
+	}
+	if !strings.HasPrefix(feed.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("unsupported JSON Feed version: %q", feed.Version)
+	}
+
+	var items []repository.Item
+	for _, entry := range feed.Items {
+		link := strings.TrimSpace(entry.URL)
+		var commentURL string
+		if external := strings.TrimSpace(entry.ExternalURL); external != "" {
+			if link != "" && link != external {
+				commentURL = link
+			}
+			link = external
+		}
+		if link == "" {
+			continue
+		}
+
+		date := entry.DatePublished
+		if date == "" {
+			date = entry.DateModified
+		}
+		parsedDate, _ := time.Parse(time.RFC3339, date)
+
+		items = append(items, repository.Item{
+			Title:       jsonFeedTitle(entry),
+			Link:        link,
+			Description: jsonFeedDescription(entry),
+			PubDate:     date,
+			GUID:        jsonFeedID(entry.ID, link),
+			Category:    entry.Tags,
+			ParsedDate:  parsedDate,
+			Source:      source,
+			CommentURL:  commentURL,
+		})
+	}
+
+	return items, nil
+}
+
+// jsonFeedTitle falls back to the start of the text for title-less (microblog) items
+func jsonFeedTitle(entry jsonFeedItem) string {
+	if title := strings.TrimSpace(entry.Title); title != "" {
+		return title
+	}
+	text := []rune(strings.TrimSpace(strings.Join(strings.Fields(entry.ContentText), " ")))
+	if len(text) > 80 {
+		return string(text[:80]) + "…"
+	}
+	return string(text)
+}
+
+func jsonFeedDescription(entry jsonFeedItem) string {
+	for _, text := range []string{entry.Summary, entry.ContentText, entry.ContentHTML} {
+		if text = strings.TrimSpace(text); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// jsonFeedID returns the item id as a string (numbers are kept verbatim), defaulting to the link
+func jsonFeedID(raw json.RawMessage, link string) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil && id != "" {
+		return id
+	}
+	if trimmed := strings.TrimSpace(string(raw)); trimmed != "" && trimmed != "null" && trimmed != `""` {
+		return trimmed
+	}
+	return link
+}
@@ -0,0 +1,78 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+const testJSONFeed = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example Blog",
+	"items": [
+		{
+			"id": "post-1",
+			"url": "https://blog.example.com/posts/1",
+			"title": "First post",
+			"summary": "Short summary",
+			"content_html": "<p>Body</p>",
+			"date_published": "2024-01-02T10:00:00+09:00",
+			"tags": ["go", "feeds"]
+		},
+		{
+			"id": 42,
+			"url": "https://blog.example.com/links/42",
+			"external_url": "https://other.example.org/article",
+			"content_text": "A link post without a title"
+		},
+		{
+			"id": "no-url",
+			"content_text": "Items without a URL are skipped"
+		}
+	]
+}`
+
+func TestParseJSONFeed(t *testing.T) {
+	items, err := ParseJSONFeed(testJSONFeed, "example")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	first := items[0]
+	if first.Title != "First post" || first.Link != "https://blog.example.com/posts/1" || first.GUID != "post-1" {
+		t.Errorf("Unexpected first item %+v", first)
+	}
+	if first.Description != "Short summary" || first.ParsedDate.IsZero() || len(first.Category) != 2 || first.Source != "example" {
+		t.Errorf("Unexpected first item fields %+v", first)
+	}
+
+	link := items[1]
+	if link.Link != "https://other.example.org/article" || link.CommentURL != "https://blog.example.com/links/42" {
+		t.Errorf("Expected external_url as link and the post as comment URL, got %+v", link)
+	}
+	if link.GUID != "42" || link.Title != "A link post without a title" {
+		t.Errorf("Expected numeric id and text title fallback, got %+v", link)
+	}
+}
+
+func TestParseJSONFeed_RejectsOtherDocuments(t *testing.T) {
+	for _, doc := range []string{`<rss version="2.0"></rss>`, `{"version":"https://example.com/not-a-feed","items":[]}`} {
+		if _, err := ParseJSONFeed(doc, "example"); err == nil {
+			t.Errorf("Expected an error for %s", doc)
+		}
+	}
+}
+
+func TestJSONFeedRepository_FetchArticles(t *testing.T) {
+	fetcher := &stubFetcher{docs: map[string]string{"https://blog.example.com/feed.json": testJSONFeed}}
+
+	items, err := NewJSONFeedRepository(fetcher, "https://blog.example.com/feed.json", "example").FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(items))
+	}
+}
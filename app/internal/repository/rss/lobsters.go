@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -23,6 +24,10 @@ func NewLobstersRSSRepository(rssRepo repository.RSSRepository) *LobstersRSSRepo
 
 func (l *LobstersRSSRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
 	url := "https://lobste.rs/rss"
+	// テスト用URLオーバーライド（file:// / fixture:// も可）
+	if testURL := os.Getenv("LOBSTERS_RSS_URL"); testURL != "" {
+		url = testURL
+	}
 	headers := map[string]string{
 		"User-Agent": "Article Summarizer Bot/1.0 (Lobsters)",
 		"Accept":     "application/rss+xml, application/xml, text/xml",
@@ -0,0 +1,99 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// opmlSourcePrefix marks Items from OPML subscriptions (e.g. "opml:go-blog")
+const opmlSourcePrefix = "opml:"
+
+// OPMLFeed is a feed subscription read from an OPML file
+type OPMLFeed struct {
+	Title  string
+	URL    string
+	Source string // "opml:<slug>", unique within the file
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	Type     string        `xml:"type,attr"`
+	XMLURL   string        `xml:"xmlUrl,attr"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ParseOPML returns the feed subscriptions of an OPML document (outlines with an xmlUrl,
+// at any folder depth). Duplicate feed URLs are listed once.
+func ParseOPML(content []byte) ([]OPMLFeed, error) {
+	var doc struct {
+		XMLName xml.Name      `xml:"opml"`
+		Body    []opmlOutline `xml:"body>outline"`
+	}
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	var feeds []OPMLFeed
+	seenURLs := make(map[string]bool)
+	seenSources := make(map[string]int)
+
+	var walk func(outlines []opmlOutline)
+	walk = func(outlines []opmlOutline) {
+		for _, outline := range outlines {
+			if feedURL := strings.TrimSpace(outline.XMLURL); feedURL != "" && !seenURLs[feedURL] {
+				seenURLs[feedURL] = true
+
+				title := strings.TrimSpace(outline.Title)
+				if title == "" {
+					title = strings.TrimSpace(outline.Text)
+				}
+				slug := opmlSlug(title, feedURL)
+				seenSources[slug]++
+				if n := seenSources[slug]; n > 1 {
+					slug = fmt.Sprintf("%s-%d", slug, n)
+				}
+
+				feeds = append(feeds, OPMLFeed{
+					Title:  title,
+					URL:    feedURL,
+					Source: opmlSourcePrefix + slug,
+				})
+			}
+			walk(outline.Outlines)
+		}
+	}
+	walk(doc.Body)
+
+	return feeds, nil
+}
+
+// opmlSlug derives a short source label from the feed title, falling back to the feed's host
+func opmlSlug(title, feedURL string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if b.Len() > 0 && !dash {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if runes := []rune(slug); len(runes) > 40 {
+		slug = strings.TrimSuffix(string(runes[:40]), "-")
+	}
+	if slug != "" {
+		return slug
+	}
+
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Hostname() != "" {
+		return strings.TrimPrefix(parsed.Hostname(), "www.")
+	}
+	return "feed"
+}
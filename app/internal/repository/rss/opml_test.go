@@ -0,0 +1,46 @@
+package rss
+
+import (
+	"testing"
+)
+
+func TestParseOPML(t *testing.T) {
+	content := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+	<head><title>Subscriptions</title></head>
+	<body>
+		<outline text="Tech">
+			<outline type="rss" text="The Go Blog" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+			<outline type="rss" text="Rust Blog" title="Rust Blog" xmlUrl="https://blog.rust-lang.org/feed.xml"/>
+		</outline>
+		<outline type="rss" text="" xmlUrl="https://www.example.com/rss"/>
+		<outline type="rss" text="The Go Blog!" xmlUrl="https://go.dev/blog/other.atom"/>
+		<outline type="rss" text="Duplicate" xmlUrl="https://go.dev/blog/feed.atom"/>
+		<outline text="Folder without feeds"/>
+	</body>
+</opml>`)
+
+	feeds, err := ParseOPML(content)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []OPMLFeed{
+		{Title: "The Go Blog", URL: "https://go.dev/blog/feed.atom", Source: "opml:the-go-blog"},
+		{Title: "Rust Blog", URL: "https://blog.rust-lang.org/feed.xml", Source: "opml:rust-blog"},
+		{Title: "", URL: "https://www.example.com/rss", Source: "opml:example.com"},
+		{Title: "The Go Blog!", URL: "https://go.dev/blog/other.atom", Source: "opml:the-go-blog-2"},
+	}
+	if len(feeds) != len(expected) {
+		t.Fatalf("Expected %d feeds, got %d: %+v", len(expected), len(feeds), feeds)
+	}
+	for i := range expected {
+		if feeds[i] != expected[i] {
+			t.Errorf("Feed %d: expected %+v, got %+v", i, expected[i], feeds[i])
+		}
+	}
+
+	if _, err := ParseOPML([]byte(`<rss version="2.0"></rss>`)); err == nil {
+		t.Error("Expected error for a non-OPML document")
+	}
+}
@@ -0,0 +1,160 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	// defaultPodcastMaxEpisodes bounds the episodes transcribed per feed and run
+	defaultPodcastMaxEpisodes = 3
+	// podcastLookback skips older episodes, so subscribing to a show does not transcribe its archive
+	podcastLookback = 7 * 24 * time.Hour
+)
+
+// ValidatePodcastFeeds checks PODCAST_FEEDS entries
+func ValidatePodcastFeeds(feedURLs []string) error {
+	for _, feedURL := range feedURLs {
+		parsed, err := url.Parse(feedURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid podcast feed URL %q", feedURL)
+		}
+	}
+	return nil
+}
+
+// podcastFeed is the subset of a podcast's RSS feed mapped into Items
+type podcastFeed struct {
+	Channel struct {
+		Title  string           `xml:"title"`
+		Author string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+		Items  []podcastEpisode `xml:"item"`
+	} `xml:"channel"`
+}
+
+type podcastEpisode struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Summary     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	PubDate     string `xml:"pubDate"`
+	GUID        string `xml:"guid"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// PodcastRepository reads the latest audio episodes of the configured podcast feeds
+type PodcastRepository struct {
+	rssRepo     repository.RSSRepository
+	feedURLs    []string
+	maxEpisodes int
+}
+
+func NewPodcastRepository(rssRepo repository.RSSRepository, feedURLs []string, maxEpisodes int) *PodcastRepository {
+	if maxEpisodes <= 0 {
+		maxEpisodes = defaultPodcastMaxEpisodes
+	}
+	return &PodcastRepository{
+		rssRepo:     rssRepo,
+		feedURLs:    feedURLs,
+		maxEpisodes: maxEpisodes,
+	}
+}
+
+// FetchArticles reads every feed; a failing feed does not hide the other shows' episodes
+func (p *PodcastRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Podcast)",
+		"Accept":     "application/rss+xml, application/xml",
+	}
+
+	var items []repository.Item
+	var errs []error
+	for _, feedURL := range p.feedURLs {
+		content, err := p.rssRepo.FetchFeedXML(ctx, feedURL, headers)
+		if err != nil {
+			logger.Printf("Error fetching podcast feed %s: %v", feedURL, err)
+			errs = append(errs, fmt.Errorf("fetching podcast feed %s: %w", feedURL, err))
+			continue
+		}
+
+		var feed podcastFeed
+		if err := xml.Unmarshal([]byte(content), &feed); err != nil {
+			logger.Printf("Error parsing podcast feed %s: %v", feedURL, err)
+			errs = append(errs, fmt.Errorf("failed to parse podcast feed %s: %w", feedURL, err))
+			continue
+		}
+		items = append(items, p.episodeItems(ctx, feed)...)
+	}
+	if len(errs) == len(p.feedURLs) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return p.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: podcasts have no discussion
+func (p *PodcastRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
+
+// episodeItems maps the newest episodes published recently as of the context's clock (feeds list
+// newest first) into Items
+func (p *PodcastRepository) episodeItems(ctx context.Context, feed podcastFeed) []repository.Item {
+	show := strings.TrimSpace(feed.Channel.Title)
+	if author := strings.TrimSpace(feed.Channel.Author); author != "" && show == "" {
+		show = author
+	}
+	cutoff := repository.Now(ctx).Add(-podcastLookback)
+
+	var items []repository.Item
+	for _, episode := range feed.Channel.Items {
+		if len(items) >= p.maxEpisodes {
+			break
+		}
+		audioURL := strings.TrimSpace(episode.Enclosure.URL)
+		if audioURL == "" || (episode.Enclosure.Type != "" && !strings.HasPrefix(episode.Enclosure.Type, "audio/")) {
+			continue
+		}
+		published := parseGenericDate(episode.PubDate)
+		if !published.IsZero() && published.Before(cutoff) {
+			continue
+		}
+
+		link := strings.TrimSpace(episode.Link)
+		if link == "" {
+			link = audioURL
+		}
+		description := strings.TrimSpace(episode.Description)
+		if description == "" {
+			description = strings.TrimSpace(episode.Summary)
+		}
+		item := repository.Item{
+			Title:       strings.TrimSpace(episode.Title),
+			Link:        link,
+			Description: description,
+			PubDate:     episode.PubDate,
+			GUID:        strings.TrimSpace(episode.GUID),
+			ParsedDate:  published,
+			Source:      "podcast",
+			AudioURL:    audioURL,
+		}
+		if show != "" {
+			item.Authors = []string{show}
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,93 @@
+package rss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const testPodcastFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+  <title>Go Time</title>
+  <itunes:author>Changelog Media</itunes:author>
+  <item>
+    <title>Episode 3</title>
+    <link>https://example.com/episodes/3</link>
+    <description>Show notes of episode 3</description>
+    <pubDate>Mon, 08 Jan 2024 10:00:00 +0000</pubDate>
+    <guid>ep-3</guid>
+    <enclosure url="https://cdn.example.com/ep3.mp3" type="audio/mpeg" length="1000"/>
+  </item>
+  <item>
+    <title>Video bonus</title>
+    <pubDate>Sun, 07 Jan 2024 10:00:00 +0000</pubDate>
+    <enclosure url="https://cdn.example.com/bonus.mp4" type="video/mp4" length="1000"/>
+  </item>
+  <item>
+    <title>Episode 2</title>
+    <itunes:summary>Show notes of episode 2</itunes:summary>
+    <pubDate>Sat, 06 Jan 2024 10:00:00 +0000</pubDate>
+    <guid>ep-2</guid>
+    <enclosure url="https://cdn.example.com/ep2.mp3" type="audio/mpeg" length="1000"/>
+  </item>
+  <item>
+    <title>Episode 1</title>
+    <pubDate>Mon, 01 Jan 2023 10:00:00 +0000</pubDate>
+    <enclosure url="https://cdn.example.com/ep1.mp3" type="audio/mpeg" length="1000"/>
+  </item>
+</channel>
+</rss>`
+
+func TestPodcastRepository_FetchArticles(t *testing.T) {
+	repo := NewPodcastRepository(&stubFetcher{docs: map[string]string{
+		"https://example.com/feed.xml": testPodcastFeed,
+	}}, []string{"https://example.com/feed.xml"}, 0)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)))
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected the 2 recent audio episodes, got %d: %+v", len(items), items)
+	}
+
+	latest := items[0]
+	if latest.Link != "https://example.com/episodes/3" || latest.AudioURL != "https://cdn.example.com/ep3.mp3" {
+		t.Errorf("Unexpected episode: %+v", latest)
+	}
+	if latest.Source != "podcast" || len(latest.Authors) != 1 || latest.Authors[0] != "Go Time" || latest.ParsedDate.IsZero() {
+		t.Errorf("Unexpected episode metadata: %+v", latest)
+	}
+	// Episodes without a page link to their audio; itunes:summary stands in for the description
+	if items[1].Link != "https://cdn.example.com/ep2.mp3" || items[1].Description != "Show notes of episode 2" {
+		t.Errorf("Unexpected episode: %+v", items[1])
+	}
+}
+
+func TestPodcastRepository_FetchArticlesMaxEpisodes(t *testing.T) {
+	repo := NewPodcastRepository(&stubFetcher{docs: map[string]string{
+		"https://example.com/feed.xml": testPodcastFeed,
+	}}, []string{"https://example.com/feed.xml"}, 1)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)))
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Episode 3" {
+		t.Errorf("Expected only the newest episode, got %+v", items)
+	}
+}
+
+func TestValidatePodcastFeeds(t *testing.T) {
+	if err := ValidatePodcastFeeds([]string{"https://feeds.example.com/show.xml"}); err != nil {
+		t.Errorf("Expected a valid feed URL, got %v", err)
+	}
+	if err := ValidatePodcastFeeds([]string{"feeds.example.com/show.xml"}); err == nil {
+		t.Error("Expected a URL without scheme to be rejected")
+	}
+}
@@ -0,0 +1,261 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const (
+	defaultProductHuntAPIURL      = "https://api.producthunt.com/v2/api/graphql"
+	defaultProductHuntFeedURL     = "https://www.producthunt.com/feed"
+	defaultProductHuntMaxProducts = 10
+)
+
+// productHuntQuery reads a day's launches ordered by votes (Product Hunt API v2)
+const productHuntQuery = `query($postedAfter: DateTime!, $postedBefore: DateTime!, $first: Int!) {
+  posts(order: VOTES, postedAfter: $postedAfter, postedBefore: $postedBefore, first: $first) {
+    edges { node {
+      id name tagline description url website votesCount createdAt
+      topics(first: 5) { edges { node { name } } }
+      makers { name }
+    } }
+  }
+}`
+
+// productHuntPost is a post (product launch) of the Product Hunt API
+type productHuntPost struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Tagline     string `json:"tagline"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Website     string `json:"website"`
+	VotesCount  int    `json:"votesCount"`
+	CreatedAt   string `json:"createdAt"`
+	Topics      struct {
+		Edges []struct {
+			Node struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"topics"`
+	Makers []struct {
+		Name string `json:"name"`
+	} `json:"makers"`
+}
+
+// ProductHuntRepository reads the top products of the last completed Product Hunt day
+// (midnight to midnight US Pacific time). Products and their vote counts come from the
+// GraphQL API when a developer token is set; otherwise the public feed is read, which has no votes.
+type ProductHuntRepository struct {
+	rssRepo     repository.RSSRepository
+	httpClient  *http.Client
+	token       string
+	apiURL      string
+	feedURL     string
+	maxProducts int
+}
+
+func NewProductHuntRepository(rssRepo repository.RSSRepository, token string) *ProductHuntRepository {
+	repo := &ProductHuntRepository{
+		rssRepo:     rssRepo,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		token:       token,
+		apiURL:      defaultProductHuntAPIURL,
+		feedURL:     defaultProductHuntFeedURL,
+		maxProducts: defaultProductHuntMaxProducts,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("PRODUCTHUNT_API_URL"); env != "" {
+		repo.apiURL = env
+	}
+	if env := os.Getenv("PRODUCTHUNT_FEED_URL"); env != "" {
+		repo.feedURL = env
+	}
+	if n, err := strconv.Atoi(os.Getenv("PRODUCTHUNT_MAX_PRODUCTS")); err == nil && n > 0 {
+		repo.maxProducts = n
+	}
+	return repo
+}
+
+// FetchArticles returns up to PRODUCTHUNT_MAX_PRODUCTS of the day's products
+func (p *ProductHuntRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	start, end := p.day(ctx)
+	var items []repository.Item
+	var err error
+	if p.token != "" {
+		items, err = p.fetchFromAPI(ctx, start, end)
+	} else {
+		items, err = p.fetchFromFeed(ctx, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: only the products are summarized
+func (p *ProductHuntRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
+
+// day returns the bounds of the last completed day in Product Hunt's time zone, as of the context's clock
+func (p *ProductHuntRepository) day(ctx context.Context) (time.Time, time.Time) {
+	location, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		location = time.UTC
+	}
+	now := repository.Now(ctx).In(location)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	return end.AddDate(0, 0, -1), end
+}
+
+// fetchFromAPI queries the day's posts, most voted first
+func (p *ProductHuntRepository) fetchFromAPI(ctx context.Context, start, end time.Time) ([]repository.Item, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": productHuntQuery,
+		"variables": map[string]any{
+			"postedAfter":  start.Format(time.RFC3339),
+			"postedBefore": end.Format(time.RFC3339),
+			"first":        p.maxProducts,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building Product Hunt query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating Product Hunt request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Article Summarizer Bot/1.0 (Product Hunt)")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, httperr.Transport(fmt.Errorf("fetching Product Hunt posts: %w", err))
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, httperr.Transport(fmt.Errorf("reading Product Hunt response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+
+	var result struct {
+		Data struct {
+			Posts struct {
+				Edges []struct {
+					Node productHuntPost `json:"node"`
+				} `json:"edges"`
+			} `json:"posts"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse Product Hunt response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("querying Product Hunt posts: %s", result.Errors[0].Message)
+	}
+
+	var items []repository.Item
+	for _, edge := range result.Data.Posts.Edges {
+		if item, ok := productHuntItem(edge.Node); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// productHuntItem maps a post into an Item linking to its Product Hunt page; the product's
+// own website is kept as LinkedURL for the summary
+func productHuntItem(post productHuntPost) (repository.Item, bool) {
+	if post.URL == "" {
+		return repository.Item{}, false
+	}
+	link := post.URL
+	// Drop the utm parameters the API appends, so the index keeps one entry per product
+	if i := strings.Index(link, "?"); i >= 0 {
+		link = link[:i]
+	}
+
+	title := strings.TrimSpace(post.Name)
+	if tagline := strings.TrimSpace(post.Tagline); tagline != "" {
+		title += " — " + tagline
+	}
+	description := strings.TrimSpace(post.Description)
+	if description == "" {
+		description = strings.TrimSpace(post.Tagline)
+	}
+
+	item := repository.Item{
+		Title:       title,
+		Link:        link,
+		Description: description,
+		PubDate:     post.CreatedAt,
+		GUID:        "producthunt:post:" + post.ID,
+		Source:      "producthunt",
+		Votes:       post.VotesCount,
+		LinkedURL:   post.Website,
+	}
+	for _, topic := range post.Topics.Edges {
+		item.Category = append(item.Category, topic.Node.Name)
+	}
+	for _, maker := range post.Makers {
+		if maker.Name != "" {
+			item.Authors = append(item.Authors, maker.Name)
+		}
+	}
+	if created, err := time.Parse(time.RFC3339, post.CreatedAt); err == nil {
+		item.ParsedDate = created
+		item.PubDate = created.Format(time.RFC1123Z)
+	}
+	return item, true
+}
+
+// fetchFromFeed reads the public feed, keeping the day's launches in feed order
+func (p *ProductHuntRepository) fetchFromFeed(ctx context.Context, start, end time.Time) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Product Hunt)",
+		"Accept":     "application/atom+xml, application/rss+xml, application/xml",
+	}
+	content, err := p.rssRepo.FetchFeedXML(ctx, p.feedURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Product Hunt feed: %w", err)
+	}
+	entries, err := ParseGenericFeed(content, "producthunt")
+	if err != nil {
+		return nil, err
+	}
+
+	var items []repository.Item
+	for _, item := range entries {
+		if len(items) >= p.maxProducts {
+			break
+		}
+		if item.ParsedDate.Before(start) || !item.ParsedDate.Before(end) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
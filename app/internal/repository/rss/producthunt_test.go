@@ -0,0 +1,81 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestProductHuntRepository_FetchArticlesFromAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var request struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Unexpected request body: %v", err)
+		}
+		// 2024-01-10 03:00 UTC is still January 9th in Product Hunt's time zone
+		if request.Variables["postedAfter"] != "2024-01-08T00:00:00-08:00" || request.Variables["postedBefore"] != "2024-01-09T00:00:00-08:00" {
+			t.Errorf("Unexpected day bounds: %v", request.Variables)
+		}
+		w.Write([]byte(`{"data":{"posts":{"edges":[
+  {"node":{"id":"1","name":"Rocket","tagline":"Ship faster","description":"Rocket deploys your app","url":"https://www.producthunt.com/posts/rocket?utm_source=graphql",
+   "website":"https://www.producthunt.com/r/ABC","votesCount":512,"createdAt":"2024-01-08T00:01:00-08:00",
+   "topics":{"edges":[{"node":{"name":"Developer Tools"}},{"node":{"name":"SaaS"}}]},"makers":[{"name":"Alice"}]}},
+  {"node":{"id":"2","name":"Quiet","tagline":"Small tool","url":"https://www.producthunt.com/posts/quiet","votesCount":12,"createdAt":"2024-01-08T09:00:00-08:00"}}
+]}}}`))
+	}))
+	defer server.Close()
+	t.Setenv("PRODUCTHUNT_API_URL", server.URL)
+
+	repo := NewProductHuntRepository(&stubFetcher{}, "test-token")
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(time.Date(2024, 1, 10, 3, 0, 0, 0, time.UTC)))
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 products, got %d", len(items))
+	}
+	top := items[0]
+	if top.Title != "Rocket — Ship faster" || top.Link != "https://www.producthunt.com/posts/rocket" || top.LinkedURL != "https://www.producthunt.com/r/ABC" {
+		t.Errorf("Unexpected product: %+v", top)
+	}
+	if top.Source != "producthunt" || top.Votes != 512 || len(top.Category) != 2 || len(top.Authors) != 1 || top.ParsedDate.IsZero() {
+		t.Errorf("Unexpected product metadata: %+v", top)
+	}
+	// Without a description the tagline is summarized
+	if items[1].Description != "Small tool" {
+		t.Errorf("Expected the tagline as description, got %q", items[1].Description)
+	}
+}
+
+func TestProductHuntRepository_FetchArticlesFromFeed(t *testing.T) {
+	repo := NewProductHuntRepository(&stubFetcher{docs: map[string]string{
+		defaultProductHuntFeedURL: `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry><id>3</id><title>Today</title><link rel="alternate" href="https://www.producthunt.com/products/today"/><published>2024-01-09T01:00:00-08:00</published></entry>
+  <entry><id>2</id><title>Yesterday</title><link rel="alternate" href="https://www.producthunt.com/products/yesterday"/><published>2024-01-08T10:00:00-08:00</published></entry>
+  <entry><id>1</id><title>Older</title><link rel="alternate" href="https://www.producthunt.com/products/older"/><published>2024-01-07T10:00:00-08:00</published></entry>
+</feed>`,
+	}}, "")
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(time.Date(2024, 1, 10, 3, 0, 0, 0, time.UTC)))
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Title != "Yesterday" || items[0].Source != "producthunt" {
+		t.Errorf("Expected only the previous day's launch, got %+v", items)
+	}
+}
@@ -0,0 +1,124 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultQiitaAPIURL   = "https://qiita.com/api/v2"
+	defaultQiitaMinLikes = 30
+	qiitaMaxItems        = 30
+	// qiitaLookback is how far back items are searched; trending items are rarely older
+	qiitaLookback = 3 * 24 * time.Hour
+)
+
+// QiitaItem is an item of the Qiita API v2
+type QiitaItem struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	CreatedAt  string `json:"created_at"`
+	LikesCount int    `json:"likes_count"`
+	Tags       []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+// QiitaRepository reads recent Qiita items at or above a likes threshold
+type QiitaRepository struct {
+	rssRepo     repository.RSSRepository
+	apiURL      string
+	accessToken string
+	minLikes    int
+}
+
+func NewQiitaRepository(rssRepo repository.RSSRepository) *QiitaRepository {
+	repo := &QiitaRepository{
+		rssRepo:     rssRepo,
+		apiURL:      defaultQiitaAPIURL,
+		accessToken: os.Getenv("QIITA_ACCESS_TOKEN"), // Optional: raises the API rate limit
+		minLikes:    defaultQiitaMinLikes,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("QIITA_API_URL"); env != "" {
+		repo.apiURL = strings.TrimRight(env, "/")
+	}
+	if n, err := strconv.Atoi(os.Getenv("QIITA_MIN_LIKES")); err == nil && n >= 0 {
+		repo.minLikes = n
+	}
+	return repo
+}
+
+// searchURL searches the lookback period for popular items. The search has no likes qualifier,
+// so stocks (which trending items collect at least as fast as likes) narrow it down.
+func (q *QiitaRepository) searchURL(ctx context.Context) string {
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("created:>=%s stocks:>=%d", repository.Now(ctx).Add(-qiitaLookback).Format("2006-01-02"), q.minLikes))
+	query.Set("per_page", strconv.Itoa(qiitaMaxItems))
+	return q.apiURL + "/items?" + query.Encode()
+}
+
+func (q *QiitaRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Qiita)",
+		"Accept":     "application/json",
+	}
+	if q.accessToken != "" {
+		headers["Authorization"] = "Bearer " + q.accessToken
+	}
+
+	content, err := q.rssRepo.FetchFeedXML(ctx, q.searchURL(ctx), headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Qiita items: %w", err)
+	}
+
+	var qiitaItems []QiitaItem
+	if err := json.Unmarshal([]byte(content), &qiitaItems); err != nil {
+		return nil, fmt.Errorf("failed to parse Qiita items: %w", err)
+	}
+
+	var items []repository.Item
+	for _, qiitaItem := range qiitaItems {
+		// The likes threshold itself is enforced here
+		if qiitaItem.URL == "" || qiitaItem.LikesCount < q.minLikes {
+			continue
+		}
+		tags := make([]string, 0, len(qiitaItem.Tags))
+		for _, tag := range qiitaItem.Tags {
+			tags = append(tags, tag.Name)
+		}
+		item := repository.Item{
+			Title:    qiitaItem.Title,
+			Link:     qiitaItem.URL,
+			PubDate:  qiitaItem.CreatedAt,
+			GUID:     qiitaItem.URL,
+			Category: tags,
+			Source:   "qiita",
+			Likes:    qiitaItem.LikesCount,
+			Authors:  []string{"@" + qiitaItem.User.ID},
+		}
+		if created, err := time.Parse(time.RFC3339, qiitaItem.CreatedAt); err == nil {
+			item.ParsedDate = created
+			item.PubDate = created.Format(time.RFC1123Z)
+		}
+		items = append(items, item)
+	}
+	return q.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: only the articles are summarized
+func (q *QiitaRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
@@ -0,0 +1,46 @@
+package rss
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestQiitaRepository_FetchArticles(t *testing.T) {
+	t.Setenv("QIITA_MIN_LIKES", "40")
+	repo := NewQiitaRepository(nil)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)))
+
+	search, err := url.Parse(repo.searchURL(ctx))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := search.Query().Get("query"); got != "created:>=2024-01-02 stocks:>=40" {
+		t.Errorf("Unexpected search query %q", got)
+	}
+
+	repo.rssRepo = &stubFetcher{docs: map[string]string{repo.searchURL(ctx): `[
+  {"id":"a1","title":"Goの並行処理","url":"https://qiita.com/alice/items/a1","created_at":"2024-01-03T09:00:00+09:00",
+   "likes_count":85,"tags":[{"name":"Go"},{"name":"並行処理"}],"user":{"id":"alice"}},
+  {"id":"b2","title":"Stocked but not liked","url":"https://qiita.com/bob/items/b2","created_at":"2024-01-03T10:00:00+09:00",
+   "likes_count":12,"tags":[],"user":{"id":"bob"}}
+]`}}
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected only the item above the likes threshold, got %d", len(items))
+	}
+	item := items[0]
+	if item.Source != "qiita" || item.Likes != 85 || len(item.Category) != 2 || item.Category[1] != "並行処理" {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+	if item.Authors[0] != "@alice" || item.ParsedDate.IsZero() {
+		t.Errorf("Unexpected author or date: %+v", item)
+	}
+}
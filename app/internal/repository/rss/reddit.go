@@ -4,11 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
 	"github.com/pep299/article-summarizer-v3/internal/repository"
 )
 
@@ -38,18 +46,122 @@ type RedditCommentListing struct {
 // RedditAPIResponse represents the structure of Reddit JSON API response
 type RedditAPIResponse []RedditCommentListing
 
+const (
+	defaultRedditURL    = "https://www.reddit.com"
+	redditListingLimit  = 50
+	redditSubredditsEnv = "REDDIT_SUBREDDITS"
+)
+
+var (
+	// redditSubredditPattern matches a subreddit or a "+"-joined multireddit (golang+rust)
+	redditSubredditPattern = regexp.MustCompile(`^[A-Za-z0-9_]+(\+[A-Za-z0-9_]+)*$`)
+	// redditMultiPattern matches a user's saved multireddit
+	redditMultiPattern = regexp.MustCompile(`^user/[A-Za-z0-9_-]+/m/[A-Za-z0-9_]+$`)
+)
+
+// RedditSubreddit is one REDDIT_SUBREDDITS entry: a subreddit (or multireddit) and its thresholds
+type RedditSubreddit struct {
+	Path        string // "golang", "golang+rust" or "user/<name>/m/<multi>"
+	MinScore    int
+	MinComments int
+}
+
+// ParseRedditSubreddits parses REDDIT_SUBREDDITS, a comma-separated list of
+// path[:min_score[:min_comments]] entries (e.g. "programming:100,golang+rust:30:5")
+func ParseRedditSubreddits(raw string) ([]RedditSubreddit, error) {
+	var subreddits []RedditSubreddit
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid subreddit entry %q (expected path[:min_score[:min_comments]])", entry)
+		}
+		subreddit := RedditSubreddit{Path: strings.TrimPrefix(strings.Trim(parts[0], "/"), "r/")}
+		if !redditSubredditPattern.MatchString(subreddit.Path) && !redditMultiPattern.MatchString(subreddit.Path) {
+			return nil, fmt.Errorf("invalid subreddit %q", parts[0])
+		}
+		thresholds := []*int{&subreddit.MinScore, &subreddit.MinComments}
+		for i, part := range parts[1:] {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid threshold %q in subreddit entry %q", part, entry)
+			}
+			*thresholds[i] = n
+		}
+		subreddits = append(subreddits, subreddit)
+	}
+	return subreddits, nil
+}
+
+// redditPost is a post of a Reddit JSON API listing
+type redditPost struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Permalink   string  `json:"permalink"`
+	Selftext    string  `json:"selftext"`
+	Subreddit   string  `json:"subreddit"`
+	Score       int     `json:"score"`
+	NumComments int     `json:"num_comments"`
+	CreatedUTC  float64 `json:"created_utc"`
+	IsSelf      bool    `json:"is_self"`
+	Stickied    bool    `json:"stickied"`
+}
+
+// redditListing is a subreddit listing of the Reddit JSON API
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data redditPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// RedditRSSRepository reads r/programming's RSS feed, or the hot listings of the subreddits
+// configured in REDDIT_SUBREDDITS, keeping each listing's posts at or above its thresholds.
+// With script app credentials (RedditCredentialsFromEnv) listings and comments come from the
+// OAuth API instead, which is not blocked like anonymous requests (429/403).
 type RedditRSSRepository struct {
-	rssRepo repository.RSSRepository
+	rssRepo    repository.RSSRepository
+	baseURL    string
+	subreddits []RedditSubreddit
+	oauth      *redditOAuthClient // nil without credentials
 }
 
 func NewRedditRSSRepository(rssRepo repository.RSSRepository) *RedditRSSRepository {
-	return &RedditRSSRepository{
+	repo := &RedditRSSRepository{
 		rssRepo: rssRepo,
+		baseURL: defaultRedditURL,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("REDDIT_API_URL"); env != "" {
+		repo.baseURL = strings.TrimRight(env, "/")
+	}
+	// Validated at startup (application.Config)
+	repo.subreddits, _ = ParseRedditSubreddits(os.Getenv(redditSubredditsEnv))
+	if creds, ok := RedditCredentialsFromEnv(); ok {
+		repo.oauth = newRedditOAuthClient(creds)
+		// The API replaces the RSS feed, so r/programming is read as a listing
+		if len(repo.subreddits) == 0 {
+			repo.subreddits = []RedditSubreddit{{Path: "programming"}}
+		}
 	}
+	return repo
 }
 
 func (r *RedditRSSRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	if len(r.subreddits) > 0 {
+		return r.fetchSubreddits(ctx)
+	}
+
 	url := "https://www.reddit.com/r/programming/.rss"
+	// テスト用URLオーバーライド（file:// / fixture:// も可）
+	if testURL := os.Getenv("REDDIT_RSS_URL"); testURL != "" {
+		url = testURL
+	}
 	headers := map[string]string{
 		"User-Agent": "Article Summarizer Bot/1.0 (Reddit)",
 		"Accept":     "application/rss+xml, application/xml, text/xml",
@@ -68,18 +180,107 @@ func (r *RedditRSSRepository) FetchArticles(ctx context.Context) ([]repository.I
 	return r.rssRepo.GetUniqueItems(items), nil
 }
 
-func (r *RedditRSSRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
-	// Convert Reddit post URL to JSON API URL with sort=top parameter
-	jsonURL := strings.Replace(commentURL, "reddit.com", "reddit.com", 1) + ".json?sort=top&limit=100"
+// fetchSubreddits merges the configured listings, most upvoted first; a failing subreddit
+// does not hide the others' posts. Cross-posted links are kept once.
+func (r *RedditRSSRepository) fetchSubreddits(ctx context.Context) ([]repository.Item, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
-	// Set headers for JSON API access
-	headers := map[string]string{
-		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
-		"Accept":     "application/json",
+	type scoredItem struct {
+		item  repository.Item
+		score int
 	}
+	var scored []scoredItem
+	var errs []error
+	for _, subreddit := range r.subreddits {
+		listing, err := r.fetchListing(ctx, subreddit)
+		if err != nil {
+			logger.Printf("Error fetching subreddit %s: %v", subreddit.Path, err)
+			errs = append(errs, fmt.Errorf("fetching subreddit %s: %w", subreddit.Path, err))
+			continue
+		}
+		kept := 0
+		for _, child := range listing.Data.Children {
+			post := child.Data
+			if post.Stickied || post.Score < subreddit.MinScore || post.NumComments < subreddit.MinComments {
+				continue
+			}
+			scored = append(scored, scoredItem{item: r.listingItem(post), score: post.Score})
+			kept++
+		}
+		logger.Printf("Subreddit fetched subreddit=%s posts=%d kept=%d min_score=%d min_comments=%d",
+			subreddit.Path, len(listing.Data.Children), kept, subreddit.MinScore, subreddit.MinComments)
+	}
+	if len(errs) == len(r.subreddits) {
+		return nil, errors.Join(errs...)
+	}
+
+	// Most upvoted first; a link cross-posted to several subreddits keeps its best-scored post
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	items := make([]repository.Item, 0, len(scored))
+	seen := make(map[string]bool)
+	for _, s := range scored {
+		if seen[s.item.Link] {
+			continue
+		}
+		seen[s.item.Link] = true
+		items = append(items, s.item)
+	}
+	return r.rssRepo.GetUniqueItems(items), nil
+}
+
+func (r *RedditRSSRepository) fetchListing(ctx context.Context, subreddit RedditSubreddit) (*redditListing, error) {
+	path := subreddit.Path
+	if !strings.HasPrefix(path, "user/") {
+		path = "r/" + path
+	}
+	var content string
+	var err error
+	if r.oauth != nil {
+		content, err = r.oauth.Get(ctx, fmt.Sprintf("/%s/hot?limit=%d&raw_json=1", path, redditListingLimit))
+	} else {
+		listingURL := fmt.Sprintf("%s/%s/hot.json?limit=%d&raw_json=1", r.baseURL, path, redditListingLimit)
+		headers := map[string]string{
+			"User-Agent": "Article Summarizer Bot/1.0 (Reddit)",
+			"Accept":     "application/json",
+		}
+		content, err = r.rssRepo.FetchFeedXML(ctx, listingURL, headers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var listing redditListing
+	if err := json.Unmarshal([]byte(content), &listing); err != nil {
+		return nil, fmt.Errorf("failed to parse Reddit listing: %w", err)
+	}
+	return &listing, nil
+}
+
+// listingItem maps a listing post like the RSS entries: the linked article, or the post itself for text posts
+func (r *RedditRSSRepository) listingItem(post redditPost) repository.Item {
+	redditURL := defaultRedditURL + post.Permalink
+	linkURL := post.URL
+	if post.IsSelf || linkURL == "" || strings.Contains(linkURL, "reddit.com") {
+		linkURL = redditURL
+	}
+	created := time.Unix(int64(post.CreatedUTC), 0).UTC()
+	item := repository.Item{
+		Title:       post.Title,
+		Link:        linkURL,
+		Description: post.Selftext,
+		PubDate:     created.Format(time.RFC3339),
+		GUID:        "t3_" + post.ID,
+		ParsedDate:  created,
+		Source:      "reddit",
+		CommentURL:  redditURL,
+	}
+	if post.Subreddit != "" {
+		item.Category = []string{post.Subreddit}
+	}
+	return item
+}
 
-	// Fetch JSON data
-	jsonContent, err := r.rssRepo.FetchFeedXML(ctx, jsonURL, headers)
+func (r *RedditRSSRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	jsonContent, err := r.fetchCommentsJSON(ctx, commentURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch Reddit comments: %w", err)
 	}
@@ -101,6 +302,27 @@ func (r *RedditRSSRepository) FetchComments(ctx context.Context, commentURL stri
 	return &Comments{Text: combinedText}, nil
 }
 
+// fetchCommentsJSON reads a post's comment tree, top comments first
+func (r *RedditRSSRepository) fetchCommentsJSON(ctx context.Context, commentURL string) (string, error) {
+	if r.oauth != nil {
+		parsed, err := url.Parse(commentURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid Reddit comment URL %q: %w", commentURL, err)
+		}
+		return r.oauth.Get(ctx, strings.TrimRight(parsed.Path, "/")+"?sort=top&limit=100")
+	}
+
+	// Convert Reddit post URL to JSON API URL with sort=top parameter
+	jsonURL := strings.Replace(commentURL, "reddit.com", "reddit.com", 1) + ".json?sort=top&limit=100"
+
+	// Set headers for JSON API access
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		"Accept":     "application/json",
+	}
+	return r.rssRepo.FetchFeedXML(ctx, jsonURL, headers)
+}
+
 func (r *RedditRSSRepository) parseFeed(xmlContent string) ([]repository.Item, error) {
 	// Reddit uses Atom format
 	var atomFeed struct {
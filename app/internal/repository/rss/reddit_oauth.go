@@ -0,0 +1,177 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const (
+	defaultRedditAuthURL  = "https://www.reddit.com/api/v1/access_token"
+	defaultRedditOAuthURL = "https://oauth.reddit.com"
+	// Tokens are renewed this long before they expire, so a run never starts with a dying token
+	redditTokenRefreshMargin = time.Minute
+)
+
+// RedditCredentials are the credentials of a Reddit "script" app, authorized with its owner's account
+type RedditCredentials struct {
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+	UserAgent    string // Reddit asks for a unique, descriptive User-Agent; defaults to one naming the account
+}
+
+// RedditCredentialsFromEnv reads REDDIT_CLIENT_ID, REDDIT_CLIENT_SECRET, REDDIT_USERNAME, REDDIT_PASSWORD
+// and REDDIT_USER_AGENT; ok is false unless all four credentials are set
+func RedditCredentialsFromEnv() (RedditCredentials, bool) {
+	creds := RedditCredentials{
+		ClientID:     os.Getenv("REDDIT_CLIENT_ID"),
+		ClientSecret: os.Getenv("REDDIT_CLIENT_SECRET"),
+		Username:     os.Getenv("REDDIT_USERNAME"),
+		Password:     os.Getenv("REDDIT_PASSWORD"),
+		UserAgent:    os.Getenv("REDDIT_USER_AGENT"),
+	}
+	if creds.UserAgent == "" {
+		creds.UserAgent = "server:article-summarizer:v3 (by /u/" + creds.Username + ")"
+	}
+	return creds, creds.ClientID != "" && creds.ClientSecret != "" && creds.Username != "" && creds.Password != ""
+}
+
+// redditOAuthClient calls the Reddit OAuth API, fetching a token with the password grant and
+// renewing it before it expires (or when the API rejects it)
+type redditOAuthClient struct {
+	httpClient *http.Client
+	creds      RedditCredentials
+	authURL    string
+	apiURL     string
+	now        func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newRedditOAuthClient(creds RedditCredentials) *redditOAuthClient {
+	client := &redditOAuthClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		creds:      creds,
+		authURL:    defaultRedditAuthURL,
+		apiURL:     defaultRedditOAuthURL,
+		now:        time.Now,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("REDDIT_AUTH_URL"); env != "" {
+		client.authURL = env
+	}
+	if env := os.Getenv("REDDIT_OAUTH_API_URL"); env != "" {
+		client.apiURL = strings.TrimRight(env, "/")
+	}
+	return client
+}
+
+// Get returns the body of an API path (e.g. /r/golang/hot?limit=50), retrying once with a new
+// token when the current one was revoked
+func (c *redditOAuthClient) Get(ctx context.Context, path string) (string, error) {
+	body, err := c.get(ctx, path)
+	var httpErr *httperr.Error
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized {
+		c.invalidate()
+		body, err = c.get(ctx, path)
+	}
+	return body, err
+}
+
+func (c *redditOAuthClient) get(ctx context.Context, path string) (string, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.apiURL+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating Reddit API request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", c.creds.UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("fetching Reddit API %s: %w", path, err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("reading Reddit API response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code from Reddit API %s: %d", path, resp.StatusCode))
+	}
+	return string(body), nil
+}
+
+// accessToken returns the cached token, fetching a new one when it is missing or about to expire
+func (c *redditOAuthClient) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && c.now().Before(c.expires.Add(-redditTokenRefreshMargin)) {
+		return c.token, nil
+	}
+
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {c.creds.Username},
+		"password":   {c.creds.Password},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.authURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating Reddit token request: %w", err)
+	}
+	req.SetBasicAuth(c.creds.ClientID, c.creds.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.creds.UserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("fetching Reddit access token: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code from Reddit token endpoint: %d", resp.StatusCode))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse Reddit token response: %w", err)
+	}
+	// Wrong credentials are reported with a 200 status and an error field
+	if result.AccessToken == "" {
+		return "", httperr.Permanent(fmt.Errorf("requesting Reddit access token: %s", result.Error))
+	}
+
+	c.token = result.AccessToken
+	c.expires = c.now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+func (c *redditOAuthClient) invalidate() {
+	c.mu.Lock()
+	c.token = ""
+	c.mu.Unlock()
+}
@@ -0,0 +1,98 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newRedditOAuthServer serves the token endpoint and r/golang's listing and comments,
+// rejecting the first token once to exercise the refresh
+func newRedditOAuthServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var tokens atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || secret != "secret" || r.FormValue("grant_type") != "password" || r.FormValue("password") != "hunter2" {
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		n := tokens.Add(1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":86400}`, n)
+	})
+	authorized := func(w http.ResponseWriter, r *http.Request) bool {
+		if r.Header.Get("Authorization") != "Bearer token-2" || !strings.Contains(r.Header.Get("User-Agent"), "/u/bot") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("GET /r/golang/hot", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) {
+			fmt.Fprint(w, `{"data":{"children":[{"data":{"id":"a2","title":"Go 1.23 released","url":"https://go.dev/blog/go1.23","permalink":"/r/golang/comments/a2/go_123/","subreddit":"golang","score":300,"num_comments":40}}]}}`)
+		}
+	})
+	mux.HandleFunc("GET /r/golang/comments/a2/go_123", func(w http.ResponseWriter, r *http.Request) {
+		if authorized(w, r) && r.URL.Query().Get("sort") == "top" {
+			fmt.Fprint(w, `[{"kind":"Listing","data":{"children":[]}},{"kind":"Listing","data":{"children":[{"kind":"t1","data":{"body":"Range over func!","author":"gopher","score":12}}]}}]`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &tokens
+}
+
+func setRedditCredentials(t *testing.T, server *httptest.Server, password string) {
+	t.Setenv("REDDIT_CLIENT_ID", "client")
+	t.Setenv("REDDIT_CLIENT_SECRET", "secret")
+	t.Setenv("REDDIT_USERNAME", "bot")
+	t.Setenv("REDDIT_PASSWORD", password)
+	t.Setenv("REDDIT_AUTH_URL", server.URL+"/api/v1/access_token")
+	t.Setenv("REDDIT_OAUTH_API_URL", server.URL)
+}
+
+func TestRedditRSSRepository_OAuth(t *testing.T) {
+	server, tokens := newRedditOAuthServer(t)
+	setRedditCredentials(t, server, "hunter2")
+	t.Setenv("REDDIT_SUBREDDITS", "golang:100")
+	// Anonymous requests would go through the fetcher, which knows no URLs
+	repo := NewRedditRSSRepository(&stubFetcher{})
+	ctx := context.Background()
+
+	items, err := repo.FetchArticles(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Link != "https://go.dev/blog/go1.23" || items[0].CommentURL != "https://www.reddit.com/r/golang/comments/a2/go_123/" {
+		t.Fatalf("Unexpected items: %+v", items)
+	}
+
+	comments, err := repo.FetchComments(ctx, items[0].CommentURL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(comments.Text, "gopher: Range over func!") {
+		t.Errorf("Expected the top comment, got %q", comments.Text)
+	}
+	// The rejected first token is replaced once, then reused
+	if n := tokens.Load(); n != 2 {
+		t.Errorf("Expected 2 token requests, got %d", n)
+	}
+}
+
+func TestRedditRSSRepository_OAuthInvalidCredentials(t *testing.T) {
+	server, _ := newRedditOAuthServer(t)
+	setRedditCredentials(t, server, "wrong")
+	t.Setenv("REDDIT_SUBREDDITS", "")
+	repo := NewRedditRSSRepository(&stubFetcher{})
+
+	_, err := repo.FetchArticles(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Expected the token error, got %v", err)
+	}
+}
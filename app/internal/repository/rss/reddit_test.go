@@ -0,0 +1,77 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRedditSubreddits(t *testing.T) {
+	subreddits, err := ParseRedditSubreddits(" programming:100, r/golang+rust:30:5 ,user/alice/m/dev,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []RedditSubreddit{
+		{Path: "programming", MinScore: 100},
+		{Path: "golang+rust", MinScore: 30, MinComments: 5},
+		{Path: "user/alice/m/dev"},
+	}
+	if len(subreddits) != len(expected) {
+		t.Fatalf("Expected %d subreddits, got %+v", len(expected), subreddits)
+	}
+	for i := range expected {
+		if subreddits[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], subreddits[i])
+		}
+	}
+
+	for _, raw := range []string{"golang:abc", "golang:-1", "golang:1:2:3", "../etc", "go lang"} {
+		if _, err := ParseRedditSubreddits(raw); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestRedditRSSRepository_FetchSubreddits(t *testing.T) {
+	t.Setenv("REDDIT_SUBREDDITS", "golang:50,rust:10:5,missing")
+	repo := NewRedditRSSRepository(&stubFetcher{docs: map[string]string{
+		defaultRedditURL + "/r/golang/hot.json?limit=50&raw_json=1": `{"data":{"children":[
+  {"data":{"id":"a1","title":"Weekly thread","url":"https://www.reddit.com/r/golang/comments/a1/","permalink":"/r/golang/comments/a1/weekly/","score":500,"num_comments":90,"stickied":true,"is_self":true}},
+  {"data":{"id":"a2","title":"Go 1.23 released","url":"https://go.dev/blog/go1.23","permalink":"/r/golang/comments/a2/go_123/","subreddit":"golang","score":300,"num_comments":40,"created_utc":1704067200}},
+  {"data":{"id":"a3","title":"Small post","url":"https://example.com/small","permalink":"/r/golang/comments/a3/small/","score":20,"num_comments":1}}
+]}}`,
+		defaultRedditURL + "/r/rust/hot.json?limit=50&raw_json=1": `{"data":{"children":[
+  {"data":{"id":"b1","title":"Go 1.23 (cross-post)","url":"https://go.dev/blog/go1.23","permalink":"/r/rust/comments/b1/go_123/","score":100,"num_comments":10}},
+  {"data":{"id":"b2","title":"Ask: lifetimes?","url":"https://www.reddit.com/r/rust/comments/b2/ask/","permalink":"/r/rust/comments/b2/ask/","selftext":"How do lifetimes work?","subreddit":"rust","score":400,"num_comments":50,"is_self":true}},
+  {"data":{"id":"b3","title":"Quiet discussion","url":"https://example.com/quiet","permalink":"/r/rust/comments/b3/quiet/","score":200,"num_comments":2}}
+]}}`,
+	}})
+
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Merged most upvoted first; the cross-post keeps the r/golang post
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 posts above the thresholds, got %d: %+v", len(items), items)
+	}
+	self := items[0]
+	if self.Link != "https://www.reddit.com/r/rust/comments/b2/ask/" || self.CommentURL != self.Link || self.Description != "How do lifetimes work?" {
+		t.Errorf("Unexpected text post: %+v", self)
+	}
+	link := items[1]
+	if link.Link != "https://go.dev/blog/go1.23" || link.CommentURL != "https://www.reddit.com/r/golang/comments/a2/go_123/" || link.Source != "reddit" {
+		t.Errorf("Unexpected link post: %+v", link)
+	}
+	if len(link.Category) != 1 || link.Category[0] != "golang" || link.ParsedDate.IsZero() {
+		t.Errorf("Unexpected link post metadata: %+v", link)
+	}
+}
+
+func TestRedditRSSRepository_FetchSubredditsAllFailing(t *testing.T) {
+	t.Setenv("REDDIT_SUBREDDITS", "golang")
+	repo := NewRedditRSSRepository(&stubFetcher{})
+
+	if _, err := repo.FetchArticles(context.Background()); err == nil {
+		t.Error("Expected an error when every subreddit fails")
+	}
+}
@@ -0,0 +1,111 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// maxNestedSitemaps bounds how many child sitemaps of a sitemap index are fetched
+const maxNestedSitemaps = 20
+
+// SitemapRepository enumerates article URLs from a site's sitemap.xml
+// for sites that have no RSS feed at all.
+type SitemapRepository struct {
+	rssRepo repository.RSSRepository
+}
+
+func NewSitemapRepository(rssRepo repository.RSSRepository) *SitemapRepository {
+	return &SitemapRepository{
+		rssRepo: rssRepo,
+	}
+}
+
+// sitemapDocument covers both <urlset> and <sitemapindex> documents
+type sitemapDocument struct {
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// FetchEntries returns every URL listed in the sitemap, following one level of sitemap index
+func (s *SitemapRepository) FetchEntries(ctx context.Context, sitemapURL string) ([]repository.Item, error) {
+	doc, err := s.fetchDocument(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	locs := doc.URLs
+	for i, child := range doc.Sitemaps {
+		if i >= maxNestedSitemaps {
+			break
+		}
+		childDoc, err := s.fetchDocument(ctx, strings.TrimSpace(child.Loc))
+		if err != nil {
+			return nil, fmt.Errorf("fetching child sitemap %s: %w", child.Loc, err)
+		}
+		locs = append(locs, childDoc.URLs...)
+	}
+
+	var items []repository.Item
+	for _, loc := range locs {
+		link := strings.TrimSpace(loc.Loc)
+		if link == "" {
+			continue
+		}
+		parsedDate, _ := s.parseDate(strings.TrimSpace(loc.LastMod))
+		items = append(items, repository.Item{
+			Link:       link,
+			PubDate:    loc.LastMod,
+			GUID:       link,
+			ParsedDate: parsedDate,
+			Source:     "sitemap",
+		})
+	}
+
+	return s.rssRepo.GetUniqueItems(items), nil
+}
+
+func (s *SitemapRepository) fetchDocument(ctx context.Context, sitemapURL string) (*sitemapDocument, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Sitemap)",
+		"Accept":     "application/xml, text/xml",
+	}
+
+	xmlContent, err := s.rssRepo.FetchFeedXML(ctx, sitemapURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching sitemap: %w", err)
+	}
+
+	var doc sitemapDocument
+	if err := xml.Unmarshal([]byte(xmlContent), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse sitemap format: %w", err)
+	}
+
+	return &doc, nil
+}
+
+func (s *SitemapRepository) parseDate(dateStr string) (time.Time, error) {
+	// Sitemaps use W3C Datetime (date only or full timestamp)
+	formats := []string{
+		time.RFC3339,
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02",
+	}
+
+	for _, format := range formats {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse sitemap date: %s", dateStr)
+}
@@ -0,0 +1,64 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// stubFetcher serves fixed documents by URL
+type stubFetcher struct {
+	docs map[string]string
+}
+
+func (s *stubFetcher) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	doc, ok := s.docs[url]
+	if !ok {
+		return "", fmt.Errorf("unexpected status code: 404")
+	}
+	return doc, nil
+}
+
+func (s *stubFetcher) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
+
+func TestSitemapRepository_FetchEntries(t *testing.T) {
+	fetcher := &stubFetcher{docs: map[string]string{
+		"https://blog.example.com/sitemap.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://blog.example.com/sitemap-posts.xml</loc></sitemap>
+</sitemapindex>`,
+		"https://blog.example.com/sitemap-posts.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://blog.example.com/posts/1</loc><lastmod>2024-01-02</lastmod></url>
+	<url><loc> https://blog.example.com/posts/2 </loc><lastmod>2024-01-03T10:00:00+09:00</lastmod></url>
+	<url><loc>https://blog.example.com/about</loc></url>
+</urlset>`,
+	}}
+
+	repo := NewSitemapRepository(fetcher)
+	items, err := repo.FetchEntries(context.Background(), "https://blog.example.com/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+
+	if items[1].Link != "https://blog.example.com/posts/2" {
+		t.Errorf("Expected trimmed link, got '%s'", items[1].Link)
+	}
+	if items[0].ParsedDate.IsZero() || items[1].ParsedDate.IsZero() {
+		t.Error("Expected lastmod to be parsed")
+	}
+	if !items[2].ParsedDate.IsZero() {
+		t.Error("Expected zero date without lastmod")
+	}
+	if items[0].Source != "sitemap" {
+		t.Errorf("Expected source 'sitemap', got '%s'", items[0].Source)
+	}
+}
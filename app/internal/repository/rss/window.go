@@ -0,0 +1,73 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActiveWindow is the time of day during which a feed runs, e.g. 07:00-23:00.
+// A window whose end is before its start wraps past midnight (22:00-06:00).
+type ActiveWindow struct {
+	start, end time.Duration // Offsets from midnight
+	spec       string
+}
+
+// ParseActiveWindow parses "HH:MM-HH:MM"; an empty spec returns nil (always active)
+func ParseActiveWindow(spec string) (*ActiveWindow, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	from, to, found := strings.Cut(spec, "-")
+	if !found {
+		return nil, fmt.Errorf("active window %q must look like 07:00-23:00", spec)
+	}
+	start, err := parseTimeOfDay(from)
+	if err != nil {
+		return nil, fmt.Errorf("active window %q: %w", spec, err)
+	}
+	end, err := parseTimeOfDay(to)
+	if err != nil {
+		return nil, fmt.Errorf("active window %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("active window %q is empty", spec)
+	}
+	return &ActiveWindow{start: start, end: end, spec: spec}, nil
+}
+
+// parseTimeOfDay parses HH:MM (24:00 is allowed as the end of the day)
+func parseTimeOfDay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t's wall-clock time is inside the window (a nil window always is).
+// Callers convert t to the feed time zone first.
+func (w *ActiveWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	hour, minute, second := t.Clock()
+	offset := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
+
+func (w *ActiveWindow) String() string {
+	if w == nil {
+		return "always"
+	}
+	return w.spec
+}
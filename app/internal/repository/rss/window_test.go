@@ -0,0 +1,50 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActiveWindow_Contains(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse("15:04", clock)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return time.Date(2024, 1, 1, parsed.Hour(), parsed.Minute(), 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec   string
+		clock  string
+		active bool
+	}{
+		{"07:00-23:00", "07:00", true},
+		{"07:00-23:00", "22:59", true},
+		{"07:00-23:00", "23:00", false},
+		{"07:00-23:00", "03:00", false},
+		{"22:00-06:00", "23:30", true}, // Wraps past midnight
+		{"22:00-06:00", "05:59", true},
+		{"22:00-06:00", "12:00", false},
+		{"06:00-24:00", "23:59", true},
+		{"", "03:00", true}, // No window: always active
+	}
+
+	for _, tt := range tests {
+		window, err := ParseActiveWindow(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.spec, err)
+		}
+		if active := window.Contains(at(tt.clock)); active != tt.active {
+			t.Errorf("%s at %s: expected active=%v, got %v", tt.spec, tt.clock, tt.active, active)
+		}
+	}
+}
+
+func TestParseActiveWindow_Invalid(t *testing.T) {
+	for _, spec := range []string{"07:00", "7-23", "07:00-25:00", "09:00-09:00"} {
+		if _, err := ParseActiveWindow(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultXAPIURL   = "https://api.x.com/2"
+	xMaxPostsPerUser = 20
+)
+
+var (
+	// xUsernamePattern matches X usernames (without the leading @)
+	xUsernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+	// xStatusIDPattern extracts the post ID from a status permalink (x.com, twitter.com or a bridge)
+	xStatusIDPattern = regexp.MustCompile(`/status(?:es)?/(\d+)`)
+	// hrefPattern extracts the links of a bridged post's HTML
+	hrefPattern = regexp.MustCompile(`href="([^"]+)"`)
+)
+
+// ValidateXAccounts checks X_ACCOUNTS entries
+func ValidateXAccounts(accounts []string) error {
+	for _, account := range accounts {
+		if !xUsernamePattern.MatchString(strings.TrimPrefix(account, "@")) {
+			return fmt.Errorf("invalid X username %q", account)
+		}
+	}
+	return nil
+}
+
+// xPost is a post of the X API v2 user timeline
+type xPost struct {
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+	Entities  struct {
+		URLs []struct {
+			ExpandedURL string `json:"expanded_url"`
+			UnwoundURL  string `json:"unwound_url"`
+		} `json:"urls"`
+	} `json:"entities"`
+}
+
+// XAccountsRepository reads the recent posts of the configured X accounts and keeps those
+// linking to an external page. Posts come from the X API v2 when a bearer token is set,
+// otherwise from an RSS bridge (e.g. a Nitter or RSSHub instance serving <bridge>/<user>/rss).
+type XAccountsRepository struct {
+	rssRepo     repository.RSSRepository
+	accounts    []string
+	apiURL      string
+	bearerToken string
+	bridgeURL   string
+}
+
+func NewXAccountsRepository(rssRepo repository.RSSRepository, accounts []string, bearerToken, bridgeURL string) *XAccountsRepository {
+	repo := &XAccountsRepository{
+		rssRepo:     rssRepo,
+		accounts:    accounts,
+		apiURL:      defaultXAPIURL,
+		bearerToken: bearerToken,
+		bridgeURL:   strings.TrimRight(bridgeURL, "/"),
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("X_API_URL"); env != "" {
+		repo.apiURL = strings.TrimRight(env, "/")
+	}
+	return repo
+}
+
+// FetchArticles reads every account; a failing account does not hide the others' posts
+func (x *XAccountsRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	var items []repository.Item
+	var errs []error
+	for _, account := range x.accounts {
+		username := strings.TrimPrefix(account, "@")
+		var posts []repository.Item
+		var err error
+		if x.bearerToken != "" {
+			posts, err = x.fetchFromAPI(ctx, username)
+		} else {
+			posts, err = x.fetchFromBridge(ctx, username)
+		}
+		if err != nil {
+			logger.Printf("Error fetching X account @%s: %v", username, err)
+			errs = append(errs, fmt.Errorf("fetching X account @%s: %w", username, err))
+			continue
+		}
+		items = append(items, posts...)
+	}
+	if len(errs) == len(x.accounts) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return x.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: replies are not summarized
+func (x *XAccountsRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
+
+func (x *XAccountsRepository) apiHeaders() map[string]string {
+	return map[string]string{
+		"User-Agent":    "Article Summarizer Bot/1.0 (X)",
+		"Accept":        "application/json",
+		"Authorization": "Bearer " + x.bearerToken,
+	}
+}
+
+// fetchFromAPI resolves the username and reads its timeline without retweets and replies
+func (x *XAccountsRepository) fetchFromAPI(ctx context.Context, username string) ([]repository.Item, error) {
+	content, err := x.rssRepo.FetchFeedXML(ctx, x.apiURL+"/users/by/username/"+username, x.apiHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("looking up user: %w", err)
+	}
+	var user struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(content), &user); err != nil {
+		return nil, fmt.Errorf("failed to parse user: %w", err)
+	}
+	if user.Data.ID == "" {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	query := url.Values{}
+	query.Set("max_results", fmt.Sprint(xMaxPostsPerUser))
+	query.Set("exclude", "retweets,replies")
+	query.Set("tweet.fields", "created_at,entities")
+	content, err = x.rssRepo.FetchFeedXML(ctx, x.apiURL+"/users/"+user.Data.ID+"/tweets?"+query.Encode(), x.apiHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("fetching timeline: %w", err)
+	}
+	var timeline struct {
+		Data []xPost `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(content), &timeline); err != nil {
+		return nil, fmt.Errorf("failed to parse timeline: %w", err)
+	}
+
+	var items []repository.Item
+	for _, post := range timeline.Data {
+		var links []string
+		for _, u := range post.Entities.URLs {
+			link := u.UnwoundURL
+			if link == "" {
+				link = u.ExpandedURL
+			}
+			links = append(links, link)
+		}
+		linked := firstExternalLink(links)
+		if post.ID == "" || linked == "" {
+			continue
+		}
+		items = append(items, xItem(username, post.ID, post.Text, linked, post.CreatedAt))
+	}
+	return items, nil
+}
+
+// fetchFromBridge reads the account's RSS from the bridge, taking the post ID from each permalink
+func (x *XAccountsRepository) fetchFromBridge(ctx context.Context, username string) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (X)",
+		"Accept":     "application/rss+xml",
+	}
+	content, err := x.rssRepo.FetchFeedXML(ctx, x.bridgeURL+"/"+username+"/rss", headers)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ParseGenericFeed(content, "x")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bridge feed: %w", err)
+	}
+
+	var items []repository.Item
+	for _, entry := range entries {
+		match := xStatusIDPattern.FindStringSubmatch(entry.Link)
+		if match == nil {
+			continue
+		}
+		var links []string
+		for _, href := range hrefPattern.FindAllStringSubmatch(entry.Description, -1) {
+			links = append(links, href[1])
+		}
+		// Bridges rewrite post links to their own host, so those are not external either
+		linked := firstExternalLink(links, bridgeHost(x.bridgeURL))
+		if linked == "" {
+			continue
+		}
+		item := xItem(username, match[1], entry.Title, linked, "")
+		item.ParsedDate = entry.ParsedDate
+		item.PubDate = entry.PubDate
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func bridgeHost(bridgeURL string) string {
+	parsed, err := url.Parse(bridgeURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// firstExternalLink returns the first http(s) link leaving X (links to other posts, media and
+// profiles are skipped)
+func firstExternalLink(links []string, extraHosts ...string) string {
+	for _, link := range links {
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+		switch host {
+		case "", "x.com", "twitter.com", "mobile.twitter.com", "pic.twitter.com", "t.co":
+			continue
+		}
+		bridged := false
+		for _, extra := range extraHosts {
+			if extra != "" && host == strings.TrimPrefix(strings.ToLower(extra), "www.") {
+				bridged = true
+			}
+		}
+		if !bridged {
+			return link
+		}
+	}
+	return ""
+}
+
+// xItem maps a post into an Item keyed by its permalink, so each post is processed once
+// even when several posts share a link
+func xItem(username, id, text, linked, createdAt string) repository.Item {
+	item := repository.Item{
+		Title:       "@" + username + ": " + truncateRunes(strings.Join(strings.Fields(text), " "), 80),
+		Link:        "https://x.com/" + username + "/status/" + id,
+		Description: strings.TrimSpace(text),
+		PubDate:     createdAt,
+		GUID:        "x:post:" + id,
+		Source:      "x",
+		Authors:     []string{"@" + username},
+		LinkedURL:   linked,
+	}
+	if created, err := time.Parse(time.RFC3339, createdAt); err == nil {
+		item.ParsedDate = created
+		item.PubDate = created.Format(time.RFC1123Z)
+	}
+	return item
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
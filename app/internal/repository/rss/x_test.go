@@ -0,0 +1,88 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateXAccounts(t *testing.T) {
+	if err := ValidateXAccounts([]string{"golang", "@rob_pike"}); err != nil {
+		t.Errorf("Expected valid usernames, got %v", err)
+	}
+	if err := ValidateXAccounts([]string{"https://x.com/golang"}); err == nil {
+		t.Error("Expected a profile URL to be rejected")
+	}
+}
+
+func TestXAccountsRepository_FetchArticlesFromAPI(t *testing.T) {
+	repo := NewXAccountsRepository(nil, []string{"@golang", "missing"}, "token", "")
+	repo.rssRepo = &stubFetcher{docs: map[string]string{
+		defaultXAPIURL + "/users/by/username/golang": `{"data":{"id":"42","username":"golang"}}`,
+		defaultXAPIURL + "/users/42/tweets?exclude=retweets%2Creplies&max_results=20&tweet.fields=created_at%2Centities": `{"data":[
+  {"id":"100","text":"Go 1.23 is released https://t.co/abc","created_at":"2024-08-13T16:00:00Z",
+   "entities":{"urls":[{"expanded_url":"https://go.dev/blog/go1.23"}]}},
+  {"id":"101","text":"Look at this post https://t.co/def","created_at":"2024-08-13T17:00:00Z",
+   "entities":{"urls":[{"expanded_url":"https://x.com/someone/status/99"}]}},
+  {"id":"102","text":"No links today","created_at":"2024-08-13T18:00:00Z"}
+]}`,
+	}}
+
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected only the post linking outside X, got %d: %+v", len(items), items)
+	}
+	post := items[0]
+	if post.Link != "https://x.com/golang/status/100" || post.GUID != "x:post:100" {
+		t.Errorf("Expected the post to be keyed by its ID, got link=%s guid=%s", post.Link, post.GUID)
+	}
+	if post.LinkedURL != "https://go.dev/blog/go1.23" || post.Source != "x" || post.ParsedDate.IsZero() {
+		t.Errorf("Unexpected item: %+v", post)
+	}
+	if len(post.Authors) != 1 || post.Authors[0] != "@golang" {
+		t.Errorf("Expected the account as author, got %v", post.Authors)
+	}
+}
+
+func TestXAccountsRepository_FetchArticlesFromBridge(t *testing.T) {
+	repo := NewXAccountsRepository(nil, []string{"golang"}, "", "https://nitter.example.com/")
+	repo.rssRepo = &stubFetcher{docs: map[string]string{
+		"https://nitter.example.com/golang/rss": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>golang / @golang</title>
+<item>
+  <title>Go 1.23 is released</title>
+  <link>https://nitter.example.com/golang/status/100#m</link>
+  <description><![CDATA[<p>Go 1.23 is released <a href="https://go.dev/blog/go1.23">go.dev/blog/go1.23</a></p>]]></description>
+  <pubDate>Tue, 13 Aug 2024 16:00:00 GMT</pubDate>
+</item>
+<item>
+  <title>Quoting a friend</title>
+  <link>https://nitter.example.com/golang/status/101#m</link>
+  <description><![CDATA[<p><a href="https://nitter.example.com/someone/status/99">nitter.example.com/someone/status/99</a></p>]]></description>
+  <pubDate>Tue, 13 Aug 2024 17:00:00 GMT</pubDate>
+</item>
+</channel></rss>`,
+	}}
+
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected only the post linking outside X, got %d: %+v", len(items), items)
+	}
+	if items[0].Link != "https://x.com/golang/status/100" || items[0].LinkedURL != "https://go.dev/blog/go1.23" {
+		t.Errorf("Unexpected item: %+v", items[0])
+	}
+}
+
+func TestXAccountsRepository_FetchArticlesAllAccountsFail(t *testing.T) {
+	repo := NewXAccountsRepository(nil, []string{"golang"}, "", "https://nitter.example.com")
+	repo.rssRepo = &stubFetcher{docs: map[string]string{}}
+
+	if _, err := repo.FetchArticles(context.Background()); err == nil {
+		t.Error("Expected an error when every account fails")
+	}
+}
@@ -0,0 +1,121 @@
+package rss
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const youTubeFeedURL = "https://www.youtube.com/feeds/videos.xml?channel_id="
+
+// youTubeChannelIDPattern matches channel IDs (UC followed by 22 characters)
+var youTubeChannelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+
+// ValidateYouTubeChannelIDs checks YOUTUBE_CHANNEL_IDS entries
+func ValidateYouTubeChannelIDs(channelIDs []string) error {
+	for _, id := range channelIDs {
+		if !youTubeChannelIDPattern.MatchString(id) {
+			return fmt.Errorf("invalid YouTube channel ID %q (the UC... ID from the channel's about page, not its @handle)", id)
+		}
+	}
+	return nil
+}
+
+// youTubeFeed is the subset of a channel's Atom feed mapped into Items
+type youTubeFeed struct {
+	Entries []youTubeEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+type youTubeEntry struct {
+	VideoID     string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+	Title       string `xml:"http://www.w3.org/2005/Atom title"`
+	Published   string `xml:"http://www.w3.org/2005/Atom published"`
+	Author      string `xml:"http://www.w3.org/2005/Atom author>name"`
+	Description string `xml:"http://search.yahoo.com/mrss/ group>description"`
+}
+
+// YouTubeRepository reads the latest uploads of the configured channels from their feeds
+type YouTubeRepository struct {
+	rssRepo    repository.RSSRepository
+	feedURL    string
+	channelIDs []string
+}
+
+func NewYouTubeRepository(rssRepo repository.RSSRepository, channelIDs []string) *YouTubeRepository {
+	return &YouTubeRepository{
+		rssRepo:    rssRepo,
+		feedURL:    youTubeFeedURL,
+		channelIDs: channelIDs,
+	}
+}
+
+// FetchArticles reads every channel; a failing channel does not hide the others' uploads
+func (y *YouTubeRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (YouTube)",
+		"Accept":     "application/atom+xml",
+	}
+
+	var items []repository.Item
+	var errs []error
+	for _, channelID := range y.channelIDs {
+		content, err := y.rssRepo.FetchFeedXML(ctx, y.feedURL+channelID, headers)
+		if err != nil {
+			logger.Printf("Error fetching YouTube channel %s: %v", channelID, err)
+			errs = append(errs, fmt.Errorf("fetching YouTube channel %s: %w", channelID, err))
+			continue
+		}
+
+		var feed youTubeFeed
+		if err := xml.Unmarshal([]byte(content), &feed); err != nil {
+			logger.Printf("Error parsing YouTube channel %s: %v", channelID, err)
+			errs = append(errs, fmt.Errorf("failed to parse YouTube channel %s: %w", channelID, err))
+			continue
+		}
+		for _, entry := range feed.Entries {
+			if entry.VideoID != "" {
+				items = append(items, youTubeItem(entry))
+			}
+		}
+	}
+	if len(errs) == len(y.channelIDs) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return y.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: video comments are not summarized
+func (y *YouTubeRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
+
+// youTubeItem maps an upload into an Item linking to its watch page (Shorts included),
+// with the video description as description
+func youTubeItem(entry youTubeEntry) repository.Item {
+	item := repository.Item{
+		Title:       strings.TrimSpace(entry.Title),
+		Link:        "https://www.youtube.com/watch?v=" + entry.VideoID,
+		Description: strings.TrimSpace(entry.Description),
+		PubDate:     entry.Published,
+		GUID:        "yt:video:" + entry.VideoID,
+		Source:      "youtube",
+	}
+	if author := strings.TrimSpace(entry.Author); author != "" {
+		item.Authors = []string{author}
+	}
+	if published, err := time.Parse(time.RFC3339, entry.Published); err == nil {
+		item.ParsedDate = published
+		item.PubDate = published.Format(time.RFC1123Z)
+	}
+	return item
+}
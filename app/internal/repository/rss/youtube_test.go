@@ -0,0 +1,65 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+const youTubeFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+  <title>Test Channel</title>
+  <entry>
+    <id>yt:video:dQw4w9WgXcQ</id>
+    <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+    <yt:channelId>UCabcdefghijklmnopqrstuv</yt:channelId>
+    <title>Understanding Go Generics</title>
+    <link rel="alternate" href="https://www.youtube.com/shorts/dQw4w9WgXcQ"/>
+    <author><name>Test Channel</name></author>
+    <published>2024-01-03T12:00:00+00:00</published>
+    <media:group>
+      <media:title>Understanding Go Generics</media:title>
+      <media:description>A talk about type parameters.</media:description>
+    </media:group>
+  </entry>
+</feed>`
+
+func TestYouTubeRepository_FetchArticles(t *testing.T) {
+	repo := NewYouTubeRepository(&stubFetcher{docs: map[string]string{
+		youTubeFeedURL + "UCabcdefghijklmnopqrstuv": youTubeFixture,
+	}}, []string{"UCabcdefghijklmnopqrstuv", "UCzzzzzzzzzzzzzzzzzzzzzz"})
+
+	// The second channel fails (404) without hiding the first one's uploads
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 video, got %d", len(items))
+	}
+
+	video := items[0]
+	if video.Link != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" || video.Source != "youtube" {
+		t.Errorf("Unexpected item: %+v", video)
+	}
+	if video.Description != "A talk about type parameters." || len(video.Authors) != 1 || video.Authors[0] != "Test Channel" {
+		t.Errorf("Unexpected description or channel: %q %v", video.Description, video.Authors)
+	}
+	if video.ParsedDate.IsZero() {
+		t.Error("Expected the publication date to be parsed")
+	}
+
+	// Every channel failing is an error
+	repo = NewYouTubeRepository(&stubFetcher{}, []string{"UCzzzzzzzzzzzzzzzzzzzzzz"})
+	if _, err := repo.FetchArticles(context.Background()); err == nil {
+		t.Error("Expected error when no channel could be fetched")
+	}
+}
+
+func TestValidateYouTubeChannelIDs(t *testing.T) {
+	if err := ValidateYouTubeChannelIDs([]string{"UCabcdefghijklmnopqrstuv"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateYouTubeChannelIDs([]string{"@golang"}); err == nil {
+		t.Error("Expected error for a channel handle")
+	}
+}
@@ -0,0 +1,112 @@
+package rss
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultZennURL      = "https://zenn.dev"
+	defaultZennMinLikes = 20
+)
+
+// ZennArticle is an article of Zenn's trending list
+type ZennArticle struct {
+	Title       string `json:"title"`
+	Path        string `json:"path"`         // e.g. /<user>/articles/<slug>
+	ArticleType string `json:"article_type"` // "tech" or "idea"
+	LikedCount  int    `json:"liked_count"`
+	PublishedAt string `json:"published_at"`
+	User        struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+// ZennRepository reads Zenn's daily trending articles
+type ZennRepository struct {
+	rssRepo     repository.RSSRepository
+	baseURL     string
+	articleType string // "tech", "idea" or "" for both
+	minLikes    int
+}
+
+func NewZennRepository(rssRepo repository.RSSRepository) *ZennRepository {
+	repo := &ZennRepository{
+		rssRepo:     rssRepo,
+		baseURL:     defaultZennURL,
+		articleType: "tech",
+		minLikes:    defaultZennMinLikes,
+	}
+	// テスト用URLオーバーライド
+	if env := os.Getenv("ZENN_URL"); env != "" {
+		repo.baseURL = strings.TrimRight(env, "/")
+	}
+	switch env := os.Getenv("ZENN_ARTICLE_TYPE"); env {
+	case "tech", "idea":
+		repo.articleType = env
+	case "all":
+		repo.articleType = ""
+	}
+	if n, err := strconv.Atoi(os.Getenv("ZENN_MIN_LIKES")); err == nil && n >= 0 {
+		repo.minLikes = n
+	}
+	return repo
+}
+
+// FetchArticles keeps the trending articles of the configured type at or above the likes threshold
+func (z *ZennRepository) FetchArticles(ctx context.Context) ([]repository.Item, error) {
+	headers := map[string]string{
+		"User-Agent": "Article Summarizer Bot/1.0 (Zenn)",
+		"Accept":     "application/json",
+	}
+
+	content, err := z.rssRepo.FetchFeedXML(ctx, z.baseURL+"/api/articles?order=daily", headers)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Zenn articles: %w", err)
+	}
+
+	var response struct {
+		Articles []ZennArticle `json:"articles"`
+	}
+	if err := json.Unmarshal([]byte(content), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Zenn articles: %w", err)
+	}
+
+	var items []repository.Item
+	for _, article := range response.Articles {
+		if article.Path == "" || article.LikedCount < z.minLikes {
+			continue
+		}
+		if z.articleType != "" && article.ArticleType != z.articleType {
+			continue
+		}
+		link := z.baseURL + article.Path
+		item := repository.Item{
+			Title:   article.Title,
+			Link:    link,
+			PubDate: article.PublishedAt,
+			GUID:    link,
+			Source:  "zenn",
+			Likes:   article.LikedCount,
+			Authors: []string{article.User.Name},
+		}
+		if published, err := time.Parse(time.RFC3339, article.PublishedAt); err == nil {
+			item.ParsedDate = published
+			item.PubDate = published.Format(time.RFC1123Z)
+		}
+		items = append(items, item)
+	}
+	return z.rssRepo.GetUniqueItems(items), nil
+}
+
+// FetchComments is not supported: only the articles are summarized
+func (z *ZennRepository) FetchComments(ctx context.Context, commentURL string) (*Comments, error) {
+	return &Comments{Text: ""}, nil
+}
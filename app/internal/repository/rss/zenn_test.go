@@ -0,0 +1,43 @@
+package rss
+
+import (
+	"context"
+	"testing"
+)
+
+const zennFixture = `{"articles":[
+  {"title":"Rustで作るCLI","path":"/alice/articles/rust-cli","article_type":"tech","liked_count":150,
+   "published_at":"2024-01-03T09:00:00.000+09:00","user":{"name":"Alice"}},
+  {"title":"エンジニアのキャリア","path":"/bob/articles/career","article_type":"idea","liked_count":300,
+   "published_at":"2024-01-03T10:00:00.000+09:00","user":{"name":"Bob"}},
+  {"title":"あまり読まれていない記事","path":"/carol/articles/quiet","article_type":"tech","liked_count":3,
+   "published_at":"2024-01-03T11:00:00.000+09:00","user":{"name":"Carol"}}
+],"next_page":2}`
+
+func TestZennRepository_FetchArticles(t *testing.T) {
+	repo := NewZennRepository(&stubFetcher{docs: map[string]string{
+		defaultZennURL + "/api/articles?order=daily": zennFixture,
+	}})
+
+	items, err := repo.FetchArticles(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Ideas and articles below the likes threshold are skipped by default
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 tech article, got %d", len(items))
+	}
+	article := items[0]
+	if article.Link != "https://zenn.dev/alice/articles/rust-cli" || article.Source != "zenn" || article.Likes != 150 {
+		t.Errorf("Unexpected item: %+v", article)
+	}
+	if article.ParsedDate.IsZero() {
+		t.Error("Expected the publication date to be parsed")
+	}
+
+	t.Setenv("ZENN_ARTICLE_TYPE", "all")
+	repo = NewZennRepository(repo.rssRepo)
+	if items, _ := repo.FetchArticles(context.Background()); len(items) != 2 {
+		t.Errorf("Expected tech and idea articles with ZENN_ARTICLE_TYPE=all, got %d", len(items))
+	}
+}
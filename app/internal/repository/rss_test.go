@@ -0,0 +1,20 @@
+package repository
+
+import "testing"
+
+func TestRSSRepository_GetUniqueItems_DecodesEntities(t *testing.T) {
+	items := NewRSSRepository().GetUniqueItems([]Item{
+		{Title: "Q&amp;A: what&#39;s new in Go", Link: "https://example.com/a", Description: "&lt;p&gt;Fast &amp;amp; small&lt;/p&gt;"},
+		{Title: "Duplicate", Link: "https://example.com/a"},
+	})
+
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 unique item, got %d", len(items))
+	}
+	if items[0].Title != "Q&A: what's new in Go" {
+		t.Errorf("Unexpected title %q", items[0].Title)
+	}
+	if items[0].Description != "<p>Fast & small</p>" {
+		t.Errorf("Unexpected description %q", items[0].Description)
+	}
+}
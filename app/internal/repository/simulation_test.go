@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSimulationRSSRepository_FetchFeedXML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	if err := os.WriteFile(path, []byte("<rss/>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSimulationRSSRepository(NewRSSRepository())
+	ctx := context.Background()
+
+	if got, err := repo.FetchFeedXML(ctx, "file://"+path, nil); err != nil || got != "<rss/>" {
+		t.Errorf("file:// fetch = %q, %v", got, err)
+	}
+	if got, err := repo.FetchFeedXML(ctx, "https://lobste.rs/rss", nil); err != nil || len(got) == 0 {
+		t.Errorf("Expected the live Lobsters URL to be served from the fixture, got %v", err)
+	}
+	if _, err := repo.FetchFeedXML(ctx, "https://example.com/feed.xml", nil); err == nil {
+		t.Error("Expected external fetches to be refused")
+	}
+	if _, err := repo.FetchFeedXML(ctx, "fixture://missing.xml", nil); err == nil {
+		t.Error("Expected an error for an unknown fixture")
+	}
+}
+
+func TestSimulationSummarizer(t *testing.T) {
+	summarizer := NewSimulationSummarizer()
+	ctx := context.Background()
+
+	for url := range SimulationPages {
+		summary, err := summarizer.SummarizeURL(ctx, url)
+		if err != nil {
+			t.Errorf("SummarizeURL(%s) failed: %v", url, err)
+			continue
+		}
+		if summary.Title == "" || !strings.HasPrefix(summary.Summary, "[simulation]") || strings.Count(summary.Summary, "\n- ") != simulationSummaryLines {
+			t.Errorf("Unexpected summary for %s: %+v", url, summary)
+		}
+	}
+
+	if _, err := summarizer.SummarizeURL(ctx, "https://example.com/article"); err == nil {
+		t.Error("Expected pages outside the fixtures to be refused")
+	}
+}
+
+func TestMemoryProcessedArticleRepository(t *testing.T) {
+	repo := NewMemoryProcessedArticleRepository()
+	ctx := context.Background()
+	article := Item{Title: "A", Link: "http://www.Example.com/a/?utm_source=x", Source: "hatena"}
+
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	index, _ := repo.LoadIndex(ctx)
+	if !repo.IsProcessed(repo.GenerateKey(Item{Link: "https://example.com/a"}), index) {
+		t.Errorf("Expected the normalized URL to be processed, index: %v", index)
+	}
+
+	if added, _ := repo.MarkManyAsProcessed(ctx, []Item{article, {Link: "https://example.com/b"}}); added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d", added)
+	}
+	if removed, _ := repo.UnmarkProcessed(ctx, article); !removed {
+		t.Error("Expected the article to be removed")
+	}
+}
@@ -4,35 +4,37 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
-)
 
-// Notification represents a unified notification structure
-type Notification struct {
-	Title        string
-	Source       string // "reddit" | "hatena" | "lobsters" | "ondemand"
-	URL          string
-	Summary      string
-	ContentChars int // Original content character count
-}
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
 
+// SlackRepository is the Slack implementation of Notifier
 type SlackRepository interface {
-	Send(ctx context.Context, notification Notification) error
-	SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error
+	Notifier
 }
 
 type slackRepository struct {
-	botToken   string
-	channel    string
-	baseURL    string
-	httpClient *http.Client
+	botToken     string
+	channel      string
+	baseURL      string
+	httpClient   *http.Client
+	sendInterval time.Duration // Minimum spacing between messages to one channel
+	maxAttempts  int           // Attempts per message when Slack answers 429
+	actions      bool          // Attach 詳細要約/コメント要約/再要約 (localized) buttons to feed summaries
+	provenance   bool          // Append the model/prompt/extraction footer (SLACK_PROVENANCE_FOOTER)
+	glossary     []string      // Channels whose summaries get a glossary line (GLOSSARY_CHANNELS)
+	text         *slackStrings // UI labels in SLACK_LOCALE
 }
 
 func NewSlackRepository(botToken, channel, baseURL string) SlackRepository {
@@ -43,22 +45,62 @@ func NewSlackRepository(botToken, channel, baseURL string) SlackRepository {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		sendInterval: defaultSlackSendInterval,
+		maxAttempts:  defaultSlackMaxAttempts,
+		provenance:   os.Getenv("SLACK_PROVENANCE_FOOTER") == "true",
+		glossary:     GlossaryChannelsFromEnv(),
+		text:         slackStringsFromEnv(),
 	}
 }
 
+// sendMessage posts a top-level message to channel
 func (s *slackRepository) sendMessage(ctx context.Context, message, channel string) error {
+	_, err := s.sendThreaded(ctx, message, nil, channel, "")
+	return err
+}
+
+// sendThreaded posts through the channel's pacer and retries rate-limited messages after Retry-After.
+// blocks (optional) carry the Block Kit layout with message as the fallback text;
+// threadTS replies in that thread (empty posts top-level); returns the new message's ts.
+func (s *slackRepository) sendThreaded(ctx context.Context, message string, blocks []slackBlock, channel, threadTS string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	pacer := channelPacer(channel)
+
+	for attempt := 1; ; attempt++ {
+		if err := pacer.wait(ctx, s.sendInterval); err != nil {
+			return "", err
+		}
+
+		ts, err := s.postMessage(ctx, message, blocks, channel, threadTS)
+		retryAfter, rateLimited := httperr.RetryAfter(err)
+		if err == nil || !rateLimited || attempt >= s.maxAttempts {
+			return ts, err
+		}
+
+		logger.Printf("Slack rate limited, retrying channel=%s attempt=%d retry_after_ms=%d",
+			channel, attempt, retryAfter.Milliseconds())
+		pacer.pause(retryAfter)
+	}
+}
+
+// postMessage makes a single chat.postMessage call and returns the posted message's ts
+func (s *slackRepository) postMessage(ctx context.Context, message string, blocks []slackBlock, channel, threadTS string) (string, error) {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
 	type chatPostMessageRequest struct {
-		Channel   string `json:"channel"`
-		Text      string `json:"text"`
-		Username  string `json:"username,omitempty"`
-		IconEmoji string `json:"icon_emoji,omitempty"`
+		Channel   string       `json:"channel"`
+		Text      string       `json:"text"`
+		Blocks    []slackBlock `json:"blocks,omitempty"`
+		ThreadTS  string       `json:"thread_ts,omitempty"`
+		Username  string       `json:"username,omitempty"`
+		IconEmoji string       `json:"icon_emoji,omitempty"`
 	}
 
 	req := chatPostMessageRequest{
 		Channel:   channel,
 		Text:      message,
+		Blocks:    blocks,
+		ThreadTS:  threadTS,
 		Username:  "Article Summarizer",
 		IconEmoji: ":robot_face:",
 	}
@@ -66,13 +108,13 @@ func (s *slackRepository) sendMessage(ctx context.Context, message, channel stri
 	body, err := json.Marshal(req)
 	if err != nil {
 		logger.Printf("Error marshaling Slack request: %v", err)
-		return fmt.Errorf("marshaling request: %w", err)
+		return "", fmt.Errorf("marshaling request: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/chat.postMessage", bytes.NewReader(body))
 	if err != nil {
 		logger.Printf("Error creating Slack HTTP request: %v", err)
-		return fmt.Errorf("creating request: %w", err)
+		return "", fmt.Errorf("creating request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -81,18 +123,29 @@ func (s *slackRepository) sendMessage(ctx context.Context, message, channel stri
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
 		logger.Printf("Error sending request to Slack API: %v request_body=%s request_headers=%v\nStack:\n%s", err, string(body), httpReq.Header, debug.Stack())
-		return fmt.Errorf("sending request: %w", err)
+		return "", httperr.Transport(fmt.Errorf("sending request: %w", err))
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateLimited := httperr.FromResponse(resp, errors.New("rate limited"))
+		rateLimited.RetryAfter = slackRetryAfter(resp.Header)
+		return "", rateLimited
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		responseBody, _ := io.ReadAll(resp.Body)
 		logger.Printf("Slack API request failed channel=%s status_code=%d request_body=%s request_headers=%v response_headers=%v response_body=%s\nStack:\n%s",
 			channel, resp.StatusCode, string(body), httpReq.Header, resp.Header, string(responseBody), debug.Stack())
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
 	}
 
-	return nil
+	// ts is only needed to thread replies; a body without it is not an error
+	var result struct {
+		TS string `json:"ts"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return result.TS, nil
 }
 
 func (s *slackRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
@@ -106,7 +159,7 @@ func (s *slackRepository) SendOnDemandSummary(ctx context.Context, article Item,
 	}
 
 	logger.Printf("On-demand Slack notification started url=%s channel=%s", article.Link, channel)
-	message := s.formatOnDemandMessage(article, summary)
+	message := s.formatOnDemandMessage(article, summary, channel)
 	if err := s.sendMessage(ctx, message, channel); err != nil {
 		logger.Printf("Error sending on-demand summary to Slack: %v", err)
 		return err
@@ -118,7 +171,7 @@ func (s *slackRepository) SendOnDemandSummary(ctx context.Context, article Item,
 	return nil
 }
 
-func (s *slackRepository) formatOnDemandMessage(article Item, summary SummarizeResponse) string {
+func (s *slackRepository) formatOnDemandMessage(article Item, summary SummarizeResponse, channel string) string {
 	timestamp := time.Now().In(time.FixedZone("JST", 9*3600)).Format("2006-01-02 15:04:05")
 
 	var titleSection string
@@ -126,20 +179,26 @@ func (s *slackRepository) formatOnDemandMessage(article Item, summary SummarizeR
 		titleSection = fmt.Sprintf("*%s*\n", article.Title)
 	}
 
-	return fmt.Sprintf(`🔗 *オンデマンド要約リクエスト完了*
-
-%s🔗 URL: %s
-📊 コンテンツ文字数: %d文字
+	return fmt.Sprintf(`%s
 
+%s🔗 URL: %s%s
 %s
 
-📝 要約方法: オンデマンドAPI
-⏰ 処理時刻: %s`,
+%s%s
+
+%s
+%s%s%s`,
+		s.text.OnDemandHeader,
 		titleSection,
 		article.Link,
-		summary.ContentChars,
+		s.difficultySection(summary.Difficulty),
+		fmt.Sprintf(s.text.ContentChars, summary.ContentChars),
 		summary.Summary,
-		timestamp)
+		s.glossarySection(channel, summary.Glossary),
+		s.text.OnDemandMethod,
+		s.text.ProcessedAt,
+		timestamp,
+		s.provenanceFooter(summary.Provenance))
 }
 
 // Send sends a unified notification
@@ -151,7 +210,11 @@ func (s *slackRepository) Send(ctx context.Context, notification Notification) e
 		notification.Title, notification.Source, s.channel)
 
 	message := s.formatNotification(notification)
-	if err := s.sendMessage(ctx, message, s.channel); err != nil {
+	var blocks []slackBlock
+	if s.actions && !notification.Comment {
+		blocks = slackActionBlocks(message, notification, s.text)
+	}
+	if _, err := s.sendThreaded(ctx, message, blocks, s.channel, ""); err != nil {
 		logger.Printf("Error sending notification to Slack: %v", err)
 		return err
 	}
@@ -162,40 +225,143 @@ func (s *slackRepository) Send(ctx context.Context, notification Notification) e
 	return nil
 }
 
+// SendDigest posts one message linking to every notification's article (see ChannelBudgets)
+func (s *slackRepository) SendDigest(ctx context.Context, notifications []Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	var lines []string
+	for _, notification := range notifications {
+		if notification.Comment {
+			continue // Listed once, by the article
+		}
+		lines = append(lines, fmt.Sprintf("• <%s|%s> (%s)", notification.URL, notification.Title, notification.Source))
+	}
+	message := fmt.Sprintf(s.text.MoreArticles, len(lines)) + "\n" + strings.Join(lines, "\n")
+	if err := s.sendMessage(ctx, message, s.channel); err != nil {
+		logger.Printf("Error sending digest to Slack channel=%s count=%d: %v", s.channel, len(notifications), err)
+		return err
+	}
+	logger.Printf("Slack digest sent channel=%s count=%d", s.channel, len(notifications))
+	return nil
+}
+
 func (s *slackRepository) formatNotification(notification Notification) string {
 	timestamp := time.Now().In(time.FixedZone("JST", 9*3600)).Format("2006-01-02 15:04:05")
 
-	return fmt.Sprintf(`*%s*
-📰 ソース: %s
-🔗 URL: %s
-📊 コンテンツ文字数: %d文字
+	var variantSection string
+	if notification.PromptVariant != "" {
+		variantSection = "\n" + s.text.Prompt + notification.PromptVariant
+	}
+
+	variantSection += s.provenanceFooter(notification.Provenance)
+
+	var originalTitleSection string
+	if notification.OriginalTitle != "" {
+		originalTitleSection = "\n" + s.text.OriginalTitle + notification.OriginalTitle
+	}
+
+	var paperSection string
+	if !notification.Comment {
+		paperSection = s.difficultySection(notification.Difficulty)
+	}
+	if len(notification.Authors) > 0 {
+		paperSection += "\n" + s.text.Authors + s.formatAuthors(notification.Authors)
+	}
+	if notification.PDFURL != "" {
+		paperSection += "\n📄 PDF: " + notification.PDFURL
+	}
+	if len(notification.Tags) > 0 {
+		paperSection += "\n" + s.text.Tags + strings.Join(notification.Tags, ", ")
+	}
+	if notification.Likes > 0 {
+		paperSection += "\n" + fmt.Sprintf(s.text.Likes, notification.Likes)
+	}
+	if notification.Votes > 0 {
+		paperSection += "\n" + fmt.Sprintf(s.text.Votes, notification.Votes)
+	}
 
+	var commentsDelayedSection string
+	if notification.CommentsDelayed {
+		commentsDelayedSection = "\n" + s.text.CommentsDelayed
+	}
+
+	return fmt.Sprintf(`*%s*%s
+%s%s
+🔗 URL: %s%s
 %s
 
-⏰ 処理時刻: %s`,
+%s%s
+
+%s%s%s%s`,
 		notification.Title,
+		originalTitleSection,
+		s.text.Source,
 		notification.Source,
 		notification.URL,
-		notification.ContentChars,
+		paperSection,
+		fmt.Sprintf(s.text.ContentChars, notification.ContentChars),
 		notification.Summary,
-		timestamp)
+		s.glossarySection(s.channel, notification.Glossary),
+		s.text.ProcessedAt,
+		timestamp,
+		variantSection,
+		commentsDelayedSection)
+}
+
+// provenanceFooter is a subtle last line naming the model, prompt and extraction (empty when disabled)
+func (s *slackRepository) provenanceFooter(provenance *Provenance) string {
+	if !s.provenance || provenance == nil {
+		return ""
+	}
+	return "\n_" + provenance.String() + "_"
+}
+
+// glossarySection is a line explaining the summary's acronyms in glossary channels (empty elsewhere)
+func (s *slackRepository) glossarySection(channel string, glossary []GlossaryEntry) string {
+	if len(glossary) == 0 || !glossaryEnabled(s.glossary, channel) {
+		return ""
+	}
+	return "\n" + s.text.Glossary + FormatGlossary(glossary)
+}
+
+// difficultySection is a line tagging the article's level (empty when unclassified)
+func (s *slackRepository) difficultySection(level string) string {
+	label := s.text.DifficultyLevels[level]
+	if label == "" {
+		return ""
+	}
+	return "\n" + s.text.Difficulty + label
+}
+
+// maxListedAuthors is the number of paper authors named before the rest are counted
+const maxListedAuthors = 5
+
+// formatAuthors lists the first authors of a paper, e.g. "A, B, C, D, E 他3名"
+func (s *slackRepository) formatAuthors(authors []string) string {
+	if len(authors) <= maxListedAuthors {
+		return strings.Join(authors, ", ")
+	}
+	return fmt.Sprintf(s.text.MoreAuthors, strings.Join(authors[:maxListedAuthors], ", "), len(authors)-maxListedAuthors)
 }
 
 func (s *slackRepository) formatArticleMessage(article Item, summary SummarizeResponse) string {
 	timestamp := time.Now().In(time.FixedZone("JST", 9*3600)).Format("2006-01-02 15:04:05")
 
-	return fmt.Sprintf(`📄 *記事要約*
+	return fmt.Sprintf(`%s
 
 *%s*
-📰 ソース: %s
+%s%s
 🔗 URL: %s
 
 %s
 
-⏰ 処理時刻: %s`,
+%s%s`,
+		s.text.ArticleHeader,
 		article.Title,
+		s.text.Source,
 		article.Source,
 		article.Link,
 		summary.Summary,
+		s.text.ProcessedAt,
 		timestamp)
 }
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// Slack action ids of the buttons attached to feed summaries
+const (
+	SlackActionDetail      = "summary_detail"      // 詳細要約: on-demand (longer) summary of the article
+	SlackActionComments    = "summary_comments"    // コメント要約: summary of the article's discussion
+	SlackActionResummarize = "summary_resummarize" // 再要約: regenerate the feed summary
+)
+
+// slackSectionMaxChars is Slack's limit for a section block's text
+const slackSectionMaxChars = 3000
+
+// SlackActionTarget is the button value identifying the summarized article
+type SlackActionTarget struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// ParseSlackActionTarget decodes a button value written by the Slack notifier
+func ParseSlackActionTarget(value string) (SlackActionTarget, error) {
+	var target SlackActionTarget
+	err := json.Unmarshal([]byte(value), &target)
+	return target, err
+}
+
+// ThreadReplier posts replies into an existing Slack thread
+type ThreadReplier interface {
+	ReplyInThread(ctx context.Context, channel, threadTS, text string) error
+}
+
+// NewSlackActionsRepository creates a Slack notifier whose feed summaries carry the
+// 詳細要約/コメント要約/再要約 buttons. The buttons need the interactivity endpoint, so
+// only use it when the Slack app is configured with one.
+func NewSlackActionsRepository(botToken, channel, baseURL string) SlackRepository {
+	slack := NewSlackRepository(botToken, channel, baseURL).(*slackRepository)
+	slack.actions = true
+	return slack
+}
+
+// NewSlackThreadReplier creates a ThreadReplier; replies share the channel's pacing
+func NewSlackThreadReplier(botToken, baseURL string) ThreadReplier {
+	return NewSlackRepository(botToken, "", baseURL).(*slackRepository)
+}
+
+func (s *slackRepository) ReplyInThread(ctx context.Context, channel, threadTS, text string) error {
+	_, err := s.sendThreaded(ctx, text, nil, channel, threadTS)
+	return err
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type     string     `json:"type"`
+	Text     *slackText `json:"text"`
+	ActionID string     `json:"action_id"`
+	Value    string     `json:"value"`
+}
+
+// slackActionBlocks renders message as section blocks followed by the action buttons labeled in text.
+// コメント要約 is only offered for sources whose comments can be fetched.
+func slackActionBlocks(message string, notification Notification, text *slackStrings) []slackBlock {
+	var blocks []slackBlock
+	for _, chunk := range splitSlackSection(message) {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: chunk}})
+	}
+
+	value, _ := json.Marshal(SlackActionTarget{URL: notification.URL, Source: notification.Source})
+	button := func(label, actionID string) slackElement {
+		return slackElement{
+			Type:     "button",
+			Text:     &slackText{Type: "plain_text", Text: label},
+			ActionID: actionID,
+			Value:    string(value),
+		}
+	}
+
+	elements := []slackElement{button(text.ButtonDetail, SlackActionDetail)}
+	if SourceHasComments(notification.Source) {
+		elements = append(elements, button(text.ButtonComments, SlackActionComments))
+	}
+	elements = append(elements, button(text.ButtonResummarize, SlackActionResummarize))
+
+	return append(blocks, slackBlock{Type: "actions", Elements: elements})
+}
+
+// SourceHasComments reports whether comment summaries are available for a feed source
+func SourceHasComments(source string) bool {
+	return source == "hatena" || source == "lobsters"
+}
+
+// splitSlackSection splits text into chunks that fit a section block, preferring line breaks
+func splitSlackSection(text string) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > slackSectionMaxChars {
+		runes := []rune(text)
+		cut := slackSectionMaxChars
+		for i := cut - 1; i > slackSectionMaxChars/2; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		text = string(runes[cut:])
+	}
+	return append(chunks, text)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+)
+
+// Slack UI locales (SLACK_LOCALE); the summaries' own language is SUMMARY_LANGUAGE
+const (
+	SlackLocaleJapanese = "ja"
+	SlackLocaleEnglish  = "en"
+)
+
+// slackStrings are the fixed labels of Slack messages in one locale
+type slackStrings struct {
+	ArticleHeader    string
+	OnDemandHeader   string
+	OnDemandMethod   string
+	Source           string
+	OriginalTitle    string
+	ContentChars     string // Format with the character count
+	ProcessedAt      string
+	Prompt           string
+	Authors          string
+	MoreAuthors      string // Format with the listed authors and the number of others
+	Tags             string
+	Likes            string // Format with the count
+	Votes            string // Format with the count
+	CommentsDelayed  string
+	Glossary         string
+	Difficulty       string
+	DifficultyLevels map[string]string
+	MoreArticles     string // Format with the number of articles collapsed into a digest
+
+	ButtonDetail      string
+	ButtonComments    string
+	ButtonResummarize string
+}
+
+var slackLocales = map[string]*slackStrings{
+	SlackLocaleJapanese: {
+		ArticleHeader:   "📄 *記事要約*",
+		OnDemandHeader:  "🔗 *オンデマンド要約リクエスト完了*",
+		OnDemandMethod:  "📝 要約方法: オンデマンドAPI",
+		Source:          "📰 ソース: ",
+		OriginalTitle:   "📝 元タイトル: ",
+		ContentChars:    "📊 コンテンツ文字数: %d文字",
+		ProcessedAt:     "⏰ 処理時刻: ",
+		Prompt:          "🧪 プロンプト: ",
+		Authors:         "👥 著者: ",
+		MoreAuthors:     "%s 他%d名",
+		Tags:            "🏷️ タグ: ",
+		Likes:           "❤️ いいね: %d",
+		Votes:           "🔼 投票: %d",
+		CommentsDelayed: "💬 議論の要約は遅れて投稿されます",
+		Glossary:        "📖 用語: ",
+		Difficulty:      "🎓 難易度: ",
+		MoreArticles:    "📚 *他%d件の記事*（投稿数の上限のためまとめて掲載）",
+		DifficultyLevels: map[string]string{
+			DifficultyBeginner:     "🟢 初級",
+			DifficultyIntermediate: "🟡 中級",
+			DifficultyExpert:       "🔴 上級",
+		},
+		ButtonDetail:      "詳細要約",
+		ButtonComments:    "コメント要約",
+		ButtonResummarize: "再要約",
+	},
+	SlackLocaleEnglish: {
+		ArticleHeader:   "📄 *Article summary*",
+		OnDemandHeader:  "🔗 *On-demand summary completed*",
+		OnDemandMethod:  "📝 Method: on-demand API",
+		Source:          "📰 Source: ",
+		OriginalTitle:   "📝 Original title: ",
+		ContentChars:    "📊 Content length: %d characters",
+		ProcessedAt:     "⏰ Processed at: ",
+		Prompt:          "🧪 Prompt: ",
+		Authors:         "👥 Authors: ",
+		MoreAuthors:     "%s and %d more",
+		Tags:            "🏷️ Tags: ",
+		Likes:           "❤️ Likes: %d",
+		Votes:           "🔼 Votes: %d",
+		CommentsDelayed: "💬 The discussion summary will follow later",
+		Glossary:        "📖 Glossary: ",
+		Difficulty:      "🎓 Level: ",
+		MoreArticles:    "📚 *%d more articles* (collapsed to stay within the posting limit)",
+		DifficultyLevels: map[string]string{
+			DifficultyBeginner:     "🟢 Beginner",
+			DifficultyIntermediate: "🟡 Intermediate",
+			DifficultyExpert:       "🔴 Expert",
+		},
+		ButtonDetail:      "Detailed summary",
+		ButtonComments:    "Discussion summary",
+		ButtonResummarize: "Re-summarize",
+	},
+}
+
+// ValidateSlackLocale checks SLACK_LOCALE
+func ValidateSlackLocale(locale string) error {
+	if _, ok := slackLocales[locale]; !ok {
+		return fmt.Errorf("unsupported Slack locale %q (expected %s or %s)", locale, SlackLocaleJapanese, SlackLocaleEnglish)
+	}
+	return nil
+}
+
+// slackStringsFromEnv returns the labels of SLACK_LOCALE (validated in Config), Japanese by default
+func slackStringsFromEnv() *slackStrings {
+	if text, ok := slackLocales[os.Getenv("SLACK_LOCALE")]; ok {
+		return text
+	}
+	return slackLocales[SlackLocaleJapanese]
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlackLocales_Complete(t *testing.T) {
+	for locale, text := range slackLocales {
+		for _, level := range difficultyLevels {
+			if text.DifficultyLevels[level] == "" {
+				t.Errorf("Locale %s has no label for level %s", locale, level)
+			}
+		}
+		if text.ButtonDetail == "" || text.ButtonComments == "" || text.ButtonResummarize == "" {
+			t.Errorf("Locale %s is missing button labels", locale)
+		}
+	}
+	if err := ValidateSlackLocale("fr"); err == nil {
+		t.Error("Expected an error for an unsupported locale")
+	}
+}
+
+func TestSlackRepository_EnglishLocale(t *testing.T) {
+	t.Setenv("SLACK_LOCALE", SlackLocaleEnglish)
+	slack := NewSlackActionsRepository("xoxb-test", "#test", "https://slack.example.com").(*slackRepository)
+
+	notification := Notification{
+		Title:           "Test",
+		Source:          "hatena",
+		URL:             "https://example.com",
+		Summary:         "要約",
+		ContentChars:    1200,
+		Difficulty:      DifficultyBeginner,
+		Authors:         []string{"A", "B", "C", "D", "E", "F"},
+		CommentsDelayed: true,
+	}
+	message := slack.formatNotification(notification)
+	for _, want := range []string{"📰 Source: hatena", "📊 Content length: 1200 characters", "🎓 Level: 🟢 Beginner", "A, B, C, D, E and 1 more", "⏰ Processed at: ", "will follow later"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Expected %q in %s", want, message)
+		}
+	}
+	if strings.Contains(message, "ソース") || strings.Contains(message, "文字数") {
+		t.Errorf("Unexpected Japanese label in %s", message)
+	}
+	// The summary itself is not translated
+	if !strings.Contains(message, "要約") {
+		t.Errorf("Expected the summary to be kept, got %s", message)
+	}
+
+	blocks := slackActionBlocks(message, notification, slack.text)
+	buttons := blocks[len(blocks)-1].Elements
+	if buttons[0].Text.Text != "Detailed summary" || buttons[len(buttons)-1].Text.Text != "Re-summarize" {
+		t.Errorf("Unexpected button labels %+v", buttons)
+	}
+
+	onDemand := slack.formatOnDemandMessage(Item{Link: "https://example.com"}, SummarizeResponse{Summary: "要約"}, "#test")
+	if !strings.Contains(onDemand, "On-demand summary completed") || !strings.Contains(onDemand, "Method: on-demand API") {
+		t.Errorf("Unexpected on-demand message %s", onDemand)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+const (
+	// chat.postMessage allows about one message per second per channel
+	defaultSlackSendInterval = time.Second
+	// Used when a 429 response has no usable Retry-After header
+	defaultSlackRetryAfter = time.Second
+	// A rate-limited message is retried this many times before it is reported as failed
+	defaultSlackMaxAttempts = 4
+)
+
+// slackPacer queues sends to one channel: each send books the next free slot, so concurrent
+// article workers post in reservation order at most once per interval
+type slackPacer struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until the caller's slot (or ctx is done)
+func (p *slackPacer) wait(ctx context.Context, interval time.Duration) error {
+	p.mu.Lock()
+	now := time.Now()
+	slot := p.next
+	if slot.Before(now) {
+		slot = now
+	}
+	p.next = slot.Add(interval)
+	p.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// pause holds every queued send for the channel until Slack's Retry-After has passed
+func (p *slackPacer) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(p.next) {
+		p.next = until
+	}
+}
+
+var (
+	slackPacersMu sync.Mutex
+	slackPacers   = make(map[string]*slackPacer)
+)
+
+// channelPacer returns the pacer shared by every Slack notifier posting to the channel
+func channelPacer(channel string) *slackPacer {
+	slackPacersMu.Lock()
+	defer slackPacersMu.Unlock()
+
+	pacer, exists := slackPacers[channel]
+	if !exists {
+		pacer = &slackPacer{}
+		slackPacers[channel] = pacer
+	}
+	return pacer
+}
+
+// slackRetryAfter reads the Retry-After of a 429 response, falling back to defaultSlackRetryAfter
+func slackRetryAfter(header http.Header) time.Duration {
+	if d := httperr.ParseRetryAfter(header, time.Now()); d > 0 {
+		return d
+	}
+	return defaultSlackRetryAfter
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlackRepository_RetriesAfterRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	notifier := NewSlackRepository("xoxb-test", "#rate-limit-retry", server.URL).(*slackRepository)
+	notifier.sendInterval = 0
+
+	if err := notifier.Send(context.Background(), Notification{Title: "Test", URL: "https://example.com"}); err != nil {
+		t.Fatalf("Expected the rate-limited message to be retried, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+}
+
+func TestSlackRepository_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackRepository("xoxb-test", "#rate-limit-exhausted", server.URL).(*slackRepository)
+	notifier.sendInterval = 0
+	notifier.maxAttempts = 2
+
+	if err := notifier.Send(context.Background(), Notification{Title: "Test", URL: "https://example.com"}); err == nil {
+		t.Fatal("Expected an error once attempts are exhausted")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+}
+
+func TestSlackPacer_SpacesSends(t *testing.T) {
+	pacer := &slackPacer{}
+	ctx := context.Background()
+	interval := 20 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := pacer.wait(ctx, interval); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("Expected 3 sends to take at least %s, took %s", 2*interval, elapsed)
+	}
+
+	// Retry-After holds the queue
+	pacer.pause(50 * time.Millisecond)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := pacer.wait(canceled, interval); err == nil {
+		t.Error("Expected a paused pacer to honor context cancellation")
+	}
+}
+
+func TestSlackRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"3", 3 * time.Second},
+		{"", defaultSlackRetryAfter},
+		{"soon", defaultSlackRetryAfter},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("Retry-After", tt.value)
+		if got := slackRetryAfter(header); got != tt.want {
+			t.Errorf("slackRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSlackOpsThread(t *testing.T) {
+	var threadTS []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ThreadTS string `json:"thread_ts"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		threadTS = append(threadTS, req.ThreadTS)
+		w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+	}))
+	defer server.Close()
+
+	thread := NewSlackOpsThread("xoxb-test", "#ops-thread-test", server.URL)
+	thread.(*slackRepository).sendInterval = 0
+	ctx := context.Background()
+
+	id, err := thread.Start(ctx, "run started")
+	if err != nil || id != "1700000000.000100" {
+		t.Fatalf("Start() = %q, %v", id, err)
+	}
+	if err := thread.Reply(ctx, id, "✅ article"); err != nil {
+		t.Fatalf("Reply() error: %v", err)
+	}
+
+	if len(threadTS) != 2 || threadTS[0] != "" || threadTS[1] != id {
+		t.Errorf("Expected a top-level post then a reply in its thread, got thread_ts %v", threadTS)
+	}
+}
+
+func TestSlackActionsRepository_AttachesButtons(t *testing.T) {
+	var requests []struct {
+		Blocks []slackBlock `json:"blocks"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Blocks []slackBlock `json:"blocks"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	slack := NewSlackActionsRepository("xoxb-test", "#actions-test", server.URL)
+	slack.(*slackRepository).sendInterval = 0
+	ctx := context.Background()
+
+	notifications := []Notification{
+		{Title: "hatena", Source: "hatena", URL: "https://example.com/a", Summary: "s"},
+		{Title: "reddit", Source: "reddit", URL: "https://example.com/b", Summary: "s"},
+		{Title: "hatena - コメント", Source: "hatena", URL: "https://example.com/a", Summary: "c", Comment: true},
+	}
+	for _, notification := range notifications {
+		if err := slack.Send(ctx, notification); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+
+	actionIDs := func(blocks []slackBlock) []string {
+		var ids []string
+		for _, block := range blocks {
+			for _, element := range block.Elements {
+				ids = append(ids, element.ActionID)
+			}
+		}
+		return ids
+	}
+	if ids := actionIDs(requests[0].Blocks); len(ids) != 3 {
+		t.Errorf("Expected 3 buttons for hatena, got %v", ids)
+	}
+	if ids := actionIDs(requests[1].Blocks); len(ids) != 2 || ids[1] != SlackActionResummarize {
+		t.Errorf("Expected no comment button for reddit, got %v", ids)
+	}
+	if len(requests[2].Blocks) != 0 {
+		t.Errorf("Expected comment summaries without buttons, got %+v", requests[2].Blocks)
+	}
+
+	target, err := ParseSlackActionTarget(requests[0].Blocks[len(requests[0].Blocks)-1].Elements[0].Value)
+	if err != nil || target.URL != "https://example.com/a" || target.Source != "hatena" {
+		t.Errorf("Unexpected button value %+v, %v", target, err)
+	}
+}
+
+func TestSplitSlackSection(t *testing.T) {
+	line := strings.Repeat("あ", 999) + "\n"
+	chunks := splitSlackSection(strings.Repeat(line, 5))
+
+	if len(chunks) != 2 || chunks[0] != strings.Repeat(line, 3) || chunks[1] != strings.Repeat(line, 2) {
+		t.Errorf("Expected split on line breaks into 3+2 lines, got %d chunks", len(chunks))
+	}
+}
+
+func TestSlackRepository_FormatNotificationCommentsDelayed(t *testing.T) {
+	notifier := NewSlackRepository("xoxb-test", "#format", "https://slack.example.com").(*slackRepository)
+
+	message := notifier.formatNotification(Notification{Title: "Test", URL: "https://example.com", CommentsDelayed: true})
+	if !strings.Contains(message, "議論の要約は遅れて投稿されます") {
+		t.Errorf("Expected the delayed discussion note, got %s", message)
+	}
+
+	message = notifier.formatNotification(Notification{Title: "Test", URL: "https://example.com"})
+	if strings.Contains(message, "議論の要約") {
+		t.Errorf("Unexpected delayed discussion note: %s", message)
+	}
+}
+
+func TestSlackRepository_FormatNotificationTagsAndLikes(t *testing.T) {
+	notifier := NewSlackRepository("xoxb-test", "#format", "https://slack.example.com").(*slackRepository)
+
+	message := notifier.formatNotification(Notification{Title: "Test", URL: "https://qiita.com/a/items/1", Tags: []string{"Go", "AWS"}, Likes: 42})
+	if !strings.Contains(message, "🏷️ タグ: Go, AWS") || !strings.Contains(message, "❤️ いいね: 42") {
+		t.Errorf("Expected tags and likes, got %s", message)
+	}
+
+	message = notifier.formatNotification(Notification{Title: "Test", URL: "https://www.producthunt.com/posts/a", Votes: 512})
+	if !strings.Contains(message, "🔼 投票: 512") || strings.Contains(message, "いいね") {
+		t.Errorf("Expected votes without likes, got %s", message)
+	}
+
+	message = notifier.formatNotification(Notification{Title: "Test", URL: "https://example.com"})
+	if strings.Contains(message, "タグ") || strings.Contains(message, "いいね") || strings.Contains(message, "投票") {
+		t.Errorf("Unexpected tags, likes or votes: %s", message)
+	}
+}
+
+func TestSlackRepository_SendDigest(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	notifier := NewSlackRepository("xoxb-test", "#digest", server.URL).(*slackRepository)
+	notifier.sendInterval = 0
+
+	err := notifier.SendDigest(context.Background(), []Notification{
+		{Title: "First", URL: "https://example.com/1", Source: "hatena"},
+		{Title: "First", URL: "https://example.com/1", Source: "hatena", Comment: true},
+		{Title: "Second", URL: "https://example.com/2", Source: "reddit"},
+	})
+	if err != nil {
+		t.Fatalf("SendDigest failed: %v", err)
+	}
+	text, _ := body["text"].(string)
+	if !strings.Contains(text, "他2件の記事") || !strings.Contains(text, "• <https://example.com/2|Second> (reddit)") {
+		t.Errorf("Unexpected digest message: %q", text)
+	}
+}
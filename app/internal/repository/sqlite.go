@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const defaultSQLitePath = "./data/processed.db"
+
+// sqliteDriverName is the database/sql driver registered by sqlite_driver.go (built with -tags sqlite)
+const sqliteDriverName = "sqlite"
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS processed_articles (
+	key            TEXT PRIMARY KEY,
+	title          TEXT NOT NULL,
+	source         TEXT NOT NULL,
+	pub_date       TEXT NOT NULL,
+	processed_date TEXT NOT NULL,
+	prompt_variant TEXT NOT NULL DEFAULT '',
+	provenance     TEXT NOT NULL DEFAULT ''
+)`
+
+// sqliteProcessedRepository keeps one row per processed article in an embedded SQLite database, so the
+// CLI tracks processed articles across runs without cloud credentials or holding everything in memory
+type sqliteProcessedRepository struct {
+	db *sql.DB
+}
+
+// newSQLiteProcessedRepository opens (and creates) SQLITE_PATH, default ./data/processed.db
+func newSQLiteProcessedRepository() (*sqliteProcessedRepository, error) {
+	path := defaultSQLitePath
+	if env := os.Getenv("SQLITE_PATH"); env != "" {
+		path = env
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating SQLite directory: %w", err)
+	}
+	return openSQLiteProcessedRepository(path)
+}
+
+func openSQLiteProcessedRepository(path string) (*sqliteProcessedRepository, error) {
+	if !slices.Contains(sql.Drivers(), sqliteDriverName) {
+		return nil, fmt.Errorf("CACHE_TYPE=%s requires a binary built with -tags sqlite", CacheTypeSQLite)
+	}
+	db, err := sql.Open(sqliteDriverName, path)
+	if err != nil {
+		return nil, fmt.Errorf("opening SQLite database: %w", err)
+	}
+	// One connection serializes writes from concurrent article workers, so SQLite never reports busy
+	db.SetMaxOpenConns(1)
+	for _, statement := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=5000", sqliteSchema} {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("initializing SQLite database: %w", err)
+		}
+	}
+	return &sqliteProcessedRepository{db: db}, nil
+}
+
+// LoadIndex reads every row
+func (r *sqliteProcessedRepository) LoadIndex(ctx context.Context) (map[string]*IndexEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	rows, err := r.db.QueryContext(ctx, `SELECT key, title, source, pub_date, processed_date, prompt_variant, provenance FROM processed_articles`)
+	if err != nil {
+		logger.Printf("Error reading processed articles: %v", err)
+		return nil, fmt.Errorf("reading processed articles: %w", err)
+	}
+	defer rows.Close()
+
+	index := make(map[string]*IndexEntry)
+	for rows.Next() {
+		var entry IndexEntry
+		var pubDate, processedDate, provenance string
+		if err := rows.Scan(&entry.URL, &entry.Title, &entry.Source, &pubDate, &processedDate, &entry.PromptVariant, &provenance); err != nil {
+			return nil, fmt.Errorf("reading processed article: %w", err)
+		}
+		entry.PubDate, _ = time.Parse(time.RFC3339Nano, pubDate)
+		entry.ProcessedDate, _ = time.Parse(time.RFC3339Nano, processedDate)
+		if provenance != "" {
+			entry.Provenance = &Provenance{}
+			if err := json.Unmarshal([]byte(provenance), entry.Provenance); err != nil {
+				entry.Provenance = nil
+			}
+		}
+		index[entry.URL] = &entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading processed articles: %w", err)
+	}
+	return index, nil
+}
+
+// IsProcessed checks if an article is already processed using the startup index
+func (r *sqliteProcessedRepository) IsProcessed(key string, index map[string]*IndexEntry) bool {
+	_, exists := index[key]
+	return exists
+}
+
+// MarkAsProcessed creates or replaces the article's row
+func (r *sqliteProcessedRepository) MarkAsProcessed(ctx context.Context, article Item) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR REPLACE INTO processed_articles
+		(key, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		r.rowValues(article, Now(ctx))...)
+	if err != nil {
+		return fmt.Errorf("writing processed article: %w", err)
+	}
+	return nil
+}
+
+// MarkManyAsProcessed inserts rows in one transaction, keeping existing ones as they are
+func (r *sqliteProcessedRepository) MarkManyAsProcessed(ctx context.Context, articles []Item) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := Now(ctx)
+	added := 0
+	for _, article := range articles {
+		if r.GenerateKey(article) == "" {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO processed_articles
+			(key, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			r.rowValues(article, now)...)
+		if err != nil {
+			return 0, fmt.Errorf("writing processed article: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing processed articles: %w", err)
+	}
+	return added, nil
+}
+
+// ExportEntries lists the whole index (LoadIndex already returns every entry)
+func (r *sqliteProcessedRepository) ExportEntries(ctx context.Context) ([]*IndexEntry, error) {
+	index, err := r.LoadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexEntries(index), nil
+}
+
+// ImportEntries inserts the entries with their own processed dates, keeping existing rows as they are
+func (r *sqliteProcessedRepository) ImportEntries(ctx context.Context, entries []*IndexEntry) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, entry := range entries {
+		article := entry.item()
+		if r.GenerateKey(article) == "" {
+			continue
+		}
+		result, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO processed_articles
+			(key, title, source, pub_date, processed_date, prompt_variant, provenance) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			r.rowValues(article, entry.ProcessedDate)...)
+		if err != nil {
+			return 0, fmt.Errorf("writing processed article: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing processed articles: %w", err)
+	}
+	return added, nil
+}
+
+// UnmarkProcessed deletes the article's row; reports whether it was present
+func (r *sqliteProcessedRepository) UnmarkProcessed(ctx context.Context, article Item) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM processed_articles WHERE key = ?`, r.GenerateKey(article))
+	if err != nil {
+		return false, fmt.Errorf("deleting processed article: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Prune deletes the rows processed before cutoff (compared as times: RFC 3339 text does not sort
+// by time when fractional seconds vary)
+func (r *sqliteProcessedRepository) Prune(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM processed_articles WHERE julianday(processed_date) < julianday(?)`,
+		cutoff.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, fmt.Errorf("pruning processed articles: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// GenerateKey generates a key for an article
+func (r *sqliteProcessedRepository) GenerateKey(article Item) string {
+	return processedKey(article)
+}
+
+// Flush is a no-op: each mark is written immediately
+func (r *sqliteProcessedRepository) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (r *sqliteProcessedRepository) Close() error {
+	return r.db.Close()
+}
+
+// rowValues are the column values of an article's row
+func (r *sqliteProcessedRepository) rowValues(article Item, processedAt time.Time) []any {
+	var provenance string
+	if article.Provenance != nil {
+		if data, err := json.Marshal(article.Provenance); err == nil {
+			provenance = string(data)
+		}
+	}
+	return []any{
+		r.GenerateKey(article), // Normalized URL
+		article.Title,
+		article.Source,
+		article.ParsedDate.UTC().Format(time.RFC3339Nano),
+		processedAt.UTC().Format(time.RFC3339Nano),
+		article.PromptVariant,
+		provenance,
+	}
+}
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package repository
+
+// The pure Go SQLite driver keeps CGO_ENABLED=0 builds working; it is only linked into
+// binaries built with -tags sqlite (make build-cli-sqlite), e.g. the CLI for local use
+import _ "modernc.org/sqlite"
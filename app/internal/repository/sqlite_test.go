@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSQLiteProcessedRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "processed.db")
+	if !slices.Contains(sql.Drivers(), sqliteDriverName) {
+		if _, err := openSQLiteProcessedRepository(path); err == nil {
+			t.Fatal("Expected an error without the SQLite driver")
+		}
+		t.Skip("SQLite driver not built in (go test -tags sqlite)")
+	}
+
+	repo, err := openSQLiteProcessedRepository(path)
+	if err != nil {
+		t.Fatalf("Opening failed: %v", err)
+	}
+	ctx := context.Background()
+
+	article := Item{
+		Title:      "Go 1.23",
+		Link:       "https://www.example.com/go/?utm_source=feed",
+		Source:     "hatena",
+		ParsedDate: time.Date(2024, 8, 13, 0, 0, 0, 0, time.UTC),
+		Provenance: &Provenance{Provider: "gemini", Model: "gemini-2.5-flash", Prompt: "rss:default@v1", Extraction: "html"},
+	}
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("MarkAsProcessed failed: %v", err)
+	}
+	added, err := repo.MarkManyAsProcessed(ctx, []Item{
+		{Title: "Go 1.23", Link: "https://example.com/go"}, // Already marked
+		{Title: "Rust 1.80", Link: "https://example.com/rust"},
+		{Title: "Rust 1.80", Link: "https://example.com/rust?ref=dup"},
+	})
+	if err != nil {
+		t.Fatalf("MarkManyAsProcessed failed: %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Expected 1 newly marked article, got %d", added)
+	}
+	repo.Close()
+
+	// The index survives reopening, as across CLI runs
+	repo, err = openSQLiteProcessedRepository(path)
+	if err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	defer repo.Close()
+	index, err := repo.LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("LoadIndex failed: %v", err)
+	}
+	if len(index) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(index))
+	}
+	entry := index[repo.GenerateKey(article)]
+	if entry == nil || entry.Title != "Go 1.23" || !entry.PubDate.Equal(article.ParsedDate) {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	if entry.Provenance == nil || entry.Provenance.Model != "gemini-2.5-flash" {
+		t.Errorf("Expected the provenance to round-trip, got %+v", entry.Provenance)
+	}
+
+	removed, err := repo.UnmarkProcessed(ctx, article)
+	if err != nil || !removed {
+		t.Fatalf("Expected the article to be unmarked, got %t, %v", removed, err)
+	}
+	removed, err = repo.UnmarkProcessed(ctx, article)
+	if err != nil || removed {
+		t.Errorf("Expected a second unmark to report absence, got %t, %v", removed, err)
+	}
+
+	// The remaining article was just processed: pruning keeps it until the cutoff passes it
+	if pruned, err := repo.Prune(ctx, time.Now().Add(-time.Hour)); err != nil || pruned != 0 {
+		t.Errorf("Expected nothing to prune, got %d, %v", pruned, err)
+	}
+	if pruned, err := repo.Prune(ctx, time.Now().Add(time.Hour)); err != nil || pruned != 1 {
+		t.Errorf("Expected the remaining article to be pruned, got %d, %v", pruned, err)
+	}
+}
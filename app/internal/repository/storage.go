@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	StorageDriverGCS   = "gcs"
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+
+	defaultCacheBucket = "article-summarizer-processed-articles"
+	defaultStorageDir  = "data"
+)
+
+// Storage is the object store behind the processed index, backlog, audit log, usage log,
+// feed statistics, summary feed and Markdown notes. Object names use "/" separators.
+type Storage interface {
+	// Read returns the object's content; errors.Is(err, os.ErrNotExist) when it does not exist
+	Read(ctx context.Context, name string) ([]byte, error)
+	// Write creates or replaces the object
+	Write(ctx context.Context, name string, data []byte, contentType string) error
+	// Create writes a new object; errors.Is(err, os.ErrExist) when it already exists
+	Create(ctx context.Context, name string, data []byte, contentType string) error
+	// List returns the names of the objects starting with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes the object; deleting an object that does not exist is not an error
+	Delete(ctx context.Context, name string) error
+	Close() error
+}
+
+// ErrVersionConflict is returned by a conditional write when the object changed since it was read
+var ErrVersionConflict = errors.New("object changed since it was read")
+
+// VersionedStorage is implemented by stores with conditional writes (GCS generations), so that
+// read-modify-write updates of a shared object never lose a concurrent writer's changes
+type VersionedStorage interface {
+	// ReadVersioned returns the object's content and generation; errors.Is(err, os.ErrNotExist) when it does not exist
+	ReadVersioned(ctx context.Context, name string) ([]byte, int64, error)
+	// WriteIfGeneration replaces the object only while it still has generation (0: only while it does not
+	// exist); errors.Is(err, ErrVersionConflict) otherwise
+	WriteIfGeneration(ctx context.Context, name string, data []byte, contentType string, generation int64) error
+}
+
+// NewStorage creates the shared store selected by STORAGE_DRIVER: "gcs" (default) uses the
+// CACHE_BUCKET bucket, "s3" the CACHE_BUCKET bucket of an S3-compatible service (see newS3Storage),
+// "local" a directory (STORAGE_DIR, default ./data) for Docker Compose or a VM
+func NewStorage() (Storage, error) {
+	bucketName := defaultCacheBucket
+	if env := os.Getenv("CACHE_BUCKET"); env != "" {
+		bucketName = env
+	}
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", StorageDriverGCS:
+		return newGCSStorage(bucketName, "")
+	case StorageDriverS3:
+		return newS3Storage(bucketName, "")
+	case StorageDriverLocal:
+		dir := defaultStorageDir
+		if env := os.Getenv("STORAGE_DIR"); env != "" {
+			dir = env
+		}
+		return NewLocalStorage(dir)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q (expected %s, %s or %s)", driver, StorageDriverGCS, StorageDriverS3, StorageDriverLocal)
+	}
+}
+
+// NewStorageAt creates a store for a gs://bucket/prefix or s3://bucket/prefix location or a local directory
+func NewStorageAt(location string) (Storage, error) {
+	scheme, bucket, prefix, ok := parseBucketLocation(location)
+	if !ok {
+		return NewLocalStorage(location)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid bucket location %q", location)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return newBucketStorage(scheme, bucket, prefix)
+}
+
+// parseBucketLocation splits a gs:// or s3:// location; ok is false for local paths
+func parseBucketLocation(location string) (scheme, bucket, object string, ok bool) {
+	for _, scheme := range []string{"gs", "s3"} {
+		if rest, found := strings.CutPrefix(location, scheme+"://"); found {
+			bucket, object, _ = strings.Cut(rest, "/")
+			return scheme, bucket, object, true
+		}
+	}
+	return "", "", "", false
+}
+
+func newBucketStorage(scheme, bucket, prefix string) (Storage, error) {
+	if scheme == "s3" {
+		return newS3Storage(bucket, prefix)
+	}
+	return newGCSStorage(bucket, prefix)
+}
+
+// gcsStorage keeps objects in a Cloud Storage bucket, optionally under a name prefix
+type gcsStorage struct {
+	client     *storage.Client
+	bucketName string
+	prefix     string
+}
+
+func newGCSStorage(bucketName, prefix string) (*gcsStorage, error) {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("creating storage client: %w", err)
+	}
+	return &gcsStorage{client: client, bucketName: bucketName, prefix: prefix}, nil
+}
+
+func (g *gcsStorage) Read(ctx context.Context, name string) ([]byte, error) {
+	data, _, err := g.ReadVersioned(ctx, name)
+	return data, err
+}
+
+// ReadVersioned returns the object's generation along with its content
+func (g *gcsStorage) ReadVersioned(ctx context.Context, name string) ([]byte, int64, error) {
+	reader, err := g.client.Bucket(g.bucketName).Object(g.prefix + name).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, 0, fmt.Errorf("gs://%s/%s%s: %w", g.bucketName, g.prefix, name, os.ErrNotExist)
+		}
+		return nil, 0, fmt.Errorf("opening gs://%s/%s%s: %w", g.bucketName, g.prefix, name, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading gs://%s/%s%s: %w", g.bucketName, g.prefix, name, err)
+	}
+	return data, reader.Attrs.Generation, nil
+}
+
+func (g *gcsStorage) Write(ctx context.Context, name string, data []byte, contentType string) error {
+	return g.write(ctx, g.client.Bucket(g.bucketName).Object(g.prefix+name), data, contentType)
+}
+
+// Create relies on the DoesNotExist precondition, so concurrent writers never overwrite each other
+func (g *gcsStorage) Create(ctx context.Context, name string, data []byte, contentType string) error {
+	obj := g.client.Bucket(g.bucketName).Object(g.prefix + name).If(storage.Conditions{DoesNotExist: true})
+	err := g.write(ctx, obj, data, contentType)
+	if preconditionFailed(err) {
+		return fmt.Errorf("gs://%s/%s%s: %w", g.bucketName, g.prefix, name, os.ErrExist)
+	}
+	return err
+}
+
+// WriteIfGeneration relies on the ifGenerationMatch (or DoesNotExist) precondition
+func (g *gcsStorage) WriteIfGeneration(ctx context.Context, name string, data []byte, contentType string, generation int64) error {
+	conditions := storage.Conditions{GenerationMatch: generation}
+	if generation == 0 {
+		conditions = storage.Conditions{DoesNotExist: true}
+	}
+	obj := g.client.Bucket(g.bucketName).Object(g.prefix + name).If(conditions)
+	err := g.write(ctx, obj, data, contentType)
+	if preconditionFailed(err) {
+		return fmt.Errorf("gs://%s/%s%s: %w", g.bucketName, g.prefix, name, ErrVersionConflict)
+	}
+	return err
+}
+
+// preconditionFailed reports whether a GCS write was rejected by its precondition
+func preconditionFailed(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed
+}
+
+func (g *gcsStorage) write(ctx context.Context, obj *storage.ObjectHandle, data []byte, contentType string) error {
+	writer := obj.NewWriter(ctx)
+	writer.ContentType = contentType
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("writing gs://%s/%s: %w", g.bucketName, obj.ObjectName(), err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("closing writer for gs://%s/%s: %w", g.bucketName, obj.ObjectName(), err)
+	}
+	return nil
+}
+
+func (g *gcsStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	it := g.client.Bucket(g.bucketName).Objects(ctx, &storage.Query{Prefix: g.prefix + prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return names, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing gs://%s/%s%s: %w", g.bucketName, g.prefix, prefix, err)
+		}
+		names = append(names, strings.TrimPrefix(attrs.Name, g.prefix))
+	}
+}
+
+func (g *gcsStorage) Delete(ctx context.Context, name string) error {
+	err := g.client.Bucket(g.bucketName).Object(g.prefix + name).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("deleting gs://%s/%s%s: %w", g.bucketName, g.prefix, name, err)
+	}
+	return nil
+}
+
+// Close closes the GCS client
+func (g *gcsStorage) Close() error {
+	if err := g.client.Close(); err != nil {
+		log.Printf("Error closing GCS client: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localStorage keeps objects as files under a directory; "/" in object names maps to subdirectories
+type localStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a store in dir, creating the directory if needed
+func NewLocalStorage(dir string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating storage directory: %w", err)
+	}
+	return &localStorage{dir: dir}, nil
+}
+
+func (l *localStorage) Read(ctx context.Context, name string) ([]byte, error) {
+	filePath, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filePath)
+}
+
+// Write replaces the file atomically (temporary file + rename), so readers never see a partial object
+func (l *localStorage) Write(ctx context.Context, name string, data []byte, contentType string) error {
+	filePath, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file for %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("setting permissions of %s: %w", name, err)
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+// Create uses O_EXCL, so concurrent writers never overwrite each other
+func (l *localStorage) Create(ctx context.Context, name string, data []byte, contentType string) error {
+	filePath, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", name, err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return file.Close()
+}
+
+func (l *localStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	// Only walk the deepest directory the prefix names
+	root := l.dir
+	if dir := path.Dir(prefix + "x"); dir != "." {
+		if !filepath.IsLocal(filepath.FromSlash(dir)) {
+			return nil, fmt.Errorf("invalid object prefix %q", prefix)
+		}
+		root = filepath.Join(l.dir, filepath.FromSlash(dir))
+	}
+
+	var names []string
+	err := filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		// Skip directories and in-flight temporary files of Write
+		if entry.IsDir() || (strings.HasPrefix(entry.Name(), ".") && strings.HasSuffix(entry.Name(), ".tmp")) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.dir, filePath)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", prefix, err)
+	}
+	return names, nil
+}
+
+func (l *localStorage) Delete(ctx context.Context, name string) error {
+	filePath, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deleting %s: %w", name, err)
+	}
+	return nil
+}
+
+func (l *localStorage) Close() error {
+	return nil
+}
+
+// path maps an object name to its file, rejecting names that would escape the directory
+func (l *localStorage) path(name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid object name %q", name)
+	}
+	return filepath.Join(l.dir, local), nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLocalStorage_ReadWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	store, err := NewLocalStorage(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.Read(ctx, "index-v2.json"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for a missing object, got %v", err)
+	}
+
+	if err := store.Write(ctx, "notes/a.md", []byte("first"), "text/markdown"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Write(ctx, "notes/a.md", []byte("second"), "text/markdown"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := store.Read(ctx, "notes/a.md")
+	if err != nil || string(data) != "second" {
+		t.Errorf("Expected the replaced content, got %q (%v)", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(dir, "notes")); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left behind, got %v", entries)
+	}
+
+	for _, name := range []string{"../outside.json", "/etc/passwd", ""} {
+		if err := store.Write(ctx, name, []byte("x"), ""); err == nil {
+			t.Errorf("Expected object name %q to be rejected", name)
+		}
+	}
+}
+
+func TestLocalStorage_CreateAndList(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, name := range []string{"usage/2024-01-01/alice/1", "usage/2024-01-01/bob/1", "usage/2024-01-02/alice/1", "audit/1.json"} {
+		if err := store.Create(ctx, name, nil, ""); err != nil {
+			t.Fatalf("Unexpected error creating %s: %v", name, err)
+		}
+	}
+	if err := store.Create(ctx, "audit/1.json", []byte("{}"), "application/json"); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Expected os.ErrExist for an existing object, got %v", err)
+	}
+
+	names, err := store.List(ctx, "usage/2024-01-01/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sort.Strings(names)
+	expected := []string{"usage/2024-01-01/alice/1", "usage/2024-01-01/bob/1"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	// Prefixes need not end at a directory boundary
+	if names, _ := store.List(ctx, "usage/2024-01-0"); len(names) != 3 {
+		t.Errorf("Expected 3 usage records, got %v", names)
+	}
+	if names, err := store.List(ctx, "missing/"); err != nil || len(names) != 0 {
+		t.Errorf("Expected no objects for a missing prefix, got %v (%v)", names, err)
+	}
+
+	if err := store.Delete(ctx, "audit/1.json"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := store.Read(ctx, "audit/1.json"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the deleted object to be gone, got %v", err)
+	}
+	if err := store.Delete(ctx, "audit/1.json"); err != nil {
+		t.Errorf("Expected deleting a missing object to succeed, got %v", err)
+	}
+}
+
+func TestProcessedIndexRepository_LocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := newProcessedIndexRepository(store, defaultIndexFileName)
+	ctx := context.Background()
+
+	article := Item{Title: "Article", Link: "https://www.example.com/a?utm_source=x", Source: "hatena"}
+	if err := repo.MarkAsProcessed(ctx, article); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	index, err := newProcessedIndexRepository(store, defaultIndexFileName).LoadIndex(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !repo.IsProcessed("https://example.com/a", index) {
+		t.Errorf("Expected the article to persist in the local index, got %v", index)
+	}
+}
+
+func TestNewStorage_UnknownDriver(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "s3")
+	if _, err := NewStorage(); err == nil {
+		t.Error("Expected an error for an unknown storage driver")
+	}
+}
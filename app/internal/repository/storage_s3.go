@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3TimeFormat    = "20060102T150405Z"
+)
+
+// s3Storage keeps objects in an S3-compatible bucket (AWS S3, MinIO, ...), optionally under a
+// name prefix. Requests are signed with AWS Signature Version 4 over plain HTTP, so no SDK is needed.
+type s3Storage struct {
+	httpClient  *http.Client
+	endpoint    *url.URL
+	pathStyle   bool
+	region      string
+	bucketName  string
+	prefix      string
+	credentials *awsCredentialSource
+	now         func() time.Time
+}
+
+// newS3Storage reads the endpoint and credentials from the environment: S3_ENDPOINT (default
+// AWS S3 of S3_REGION; a custom endpoint such as MinIO uses path-style URLs), S3_REGION,
+// S3_ACCESS_KEY_ID / S3_SECRET_ACCESS_KEY (falling back to the AWS_* variables) and AWS_SESSION_TOKEN,
+// or on ECS the task role (see newAWSCredentialSource)
+func newS3Storage(bucketName, prefix string) (*s3Storage, error) {
+	region := firstEnv("S3_REGION", "AWS_REGION")
+	if region == "" {
+		region = defaultS3Region
+	}
+	rawEndpoint := os.Getenv("S3_ENDPOINT")
+	pathStyle := rawEndpoint != "" || os.Getenv("S3_FORCE_PATH_STYLE") == "true"
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", rawEndpoint)
+	}
+
+	credentials := newAWSCredentialSource(
+		firstEnv("S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"),
+		firstEnv("S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"),
+	)
+	if credentials == nil {
+		return nil, fmt.Errorf("S3 credentials are required (S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY)")
+	}
+
+	return &s3Storage{
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+		endpoint:    endpoint,
+		pathStyle:   pathStyle,
+		region:      region,
+		bucketName:  bucketName,
+		prefix:      prefix,
+		credentials: credentials,
+		now:         time.Now,
+	}, nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (s *s3Storage) Read(ctx context.Context, name string) ([]byte, error) {
+	resp, err := s.do(ctx, "GET", s.prefix+name, "", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("reading s3://%s/%s%s: %w", s.bucketName, s.prefix, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("s3://%s/%s%s: %w", s.bucketName, s.prefix, name, os.ErrNotExist)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading s3://%s/%s%s: %w", s.bucketName, s.prefix, name, s3Error(resp))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *s3Storage) Write(ctx context.Context, name string, data []byte, contentType string) error {
+	return s.put(ctx, name, data, contentType, nil)
+}
+
+// Create relies on the If-None-Match conditional write, so concurrent writers never overwrite each other
+func (s *s3Storage) Create(ctx context.Context, name string, data []byte, contentType string) error {
+	err := s.put(ctx, name, data, contentType, map[string]string{"If-None-Match": "*"})
+	var statusErr *s3StatusError
+	if errors.As(err, &statusErr) && statusErr.status == http.StatusPreconditionFailed {
+		return fmt.Errorf("s3://%s/%s%s: %w", s.bucketName, s.prefix, name, os.ErrExist)
+	}
+	return err
+}
+
+func (s *s3Storage) put(ctx context.Context, name string, data []byte, contentType string, headers map[string]string) error {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	if contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+	resp, err := s.do(ctx, "PUT", s.prefix+name, "", data, headers)
+	if err != nil {
+		return fmt.Errorf("writing s3://%s/%s%s: %w", s.bucketName, s.prefix, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("writing s3://%s/%s%s: %w", s.bucketName, s.prefix, name, s3Error(resp))
+	}
+	return nil
+}
+
+// List pages through ListObjectsV2
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	continuation := ""
+	for {
+		query := map[string]string{"list-type": "2", "prefix": s.prefix + prefix}
+		if continuation != "" {
+			query["continuation-token"] = continuation
+		}
+		resp, err := s.do(ctx, "GET", "", s3Query(query), nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing s3://%s/%s%s: %w", s.bucketName, s.prefix, prefix, err)
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = s3Error(resp)
+		} else {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("listing s3://%s/%s%s: %w", s.bucketName, s.prefix, prefix, err)
+		}
+
+		for _, object := range result.Contents {
+			names = append(names, strings.TrimPrefix(object.Key, s.prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return names, nil
+		}
+		continuation = result.NextContinuationToken
+	}
+}
+
+// Delete succeeds for a missing object too (S3 answers 204 either way)
+func (s *s3Storage) Delete(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, "DELETE", s.prefix+name, "", nil, nil)
+	if err != nil {
+		return fmt.Errorf("deleting s3://%s/%s%s: %w", s.bucketName, s.prefix, name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("deleting s3://%s/%s%s: %w", s.bucketName, s.prefix, name, s3Error(resp))
+	}
+	return nil
+}
+
+func (s *s3Storage) Close() error {
+	return nil
+}
+
+// do sends a signed request for key (empty for the bucket itself) with an already canonical query
+func (s *s3Storage) do(ctx context.Context, method, key, rawQuery string, body []byte, headers map[string]string) (*http.Response, error) {
+	target := *s.endpoint
+	objectPath := "/" + key
+	if s.pathStyle {
+		objectPath = "/" + s.bucketName + objectPath
+	} else {
+		target.Host = s.bucketName + "." + target.Host
+	}
+	target.Path = strings.TrimSuffix(target.Path, "/") + objectPath
+	target.RawPath = s3Escape(strings.TrimSuffix(s.endpoint.Path, "/")+objectPath, true)
+	target.RawQuery = rawQuery
+
+	credentials, err := s.credentials.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+	payloadHash := sha256.Sum256(body)
+	signS3Request(req, hex.EncodeToString(payloadHash[:]), credentials.accessKey, credentials.secretKey, s.region, s.now())
+
+	return s.httpClient.Do(req)
+}
+
+// signS3Request adds the Signature Version 4 Authorization header for S3
+func signS3Request(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	signAWSRequest(req, payloadHash, accessKey, secretKey, region, "s3", now)
+}
+
+// signAWSRequest adds the Signature Version 4 Authorization header for an AWS service, signing the
+// host and every header set on req
+func signAWSRequest(req *http.Request, payloadHash, accessKey, secretKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(s3TimeFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalHeaders := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		canonicalHeaders[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(canonicalHeaders))
+	for name := range canonicalHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var headerBlock strings.Builder
+	for _, name := range names {
+		headerBlock.WriteString(name + ":" + canonicalHeaders[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		headerBlock.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3CanonicalQuery(values url.Values) string {
+	params := make(map[string]string, len(values))
+	for key, vals := range values {
+		params[key] = strings.Join(vals, ",")
+	}
+	return s3Query(params)
+}
+
+// s3Query encodes parameters sorted by name, as the signature's canonical query string requires
+func s3Query(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, s3Escape(key, false)+"="+s3Escape(params[key], false))
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape percent-encodes everything but the RFC 3986 unreserved characters (and "/" in paths)
+func s3Escape(value string, path bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (path && c == '/') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// s3StatusError is an unexpected S3 response, carrying the service's error code
+type s3StatusError struct {
+	status int
+	code   string
+}
+
+func (e *s3StatusError) Error() string {
+	if e.code == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.status)
+	}
+	return fmt.Sprintf("unexpected status code: %d (%s)", e.status, e.code)
+}
+
+func s3Error(resp *http.Response) error {
+	var body struct {
+		Code string `xml:"Code"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = xml.Unmarshal(data, &body)
+	return &s3StatusError{status: resp.StatusCode, code: body.Code}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const defaultSummaryArchivePrefix = "summaries/"
+
+// SummaryArchiveEntry is the archived summary of one article
+type SummaryArchiveEntry struct {
+	Title          string    `json:"title"`
+	OriginalTitle  string    `json:"original_title,omitempty"` // Feed title when Title is a rewritten headline
+	URL            string    `json:"url"`                      // Normalized URL (the processed-index key)
+	Link           string    `json:"link"`
+	Source         string    `json:"source"`
+	Summary        string    `json:"summary"`
+	CommentSummary string    `json:"comment_summary,omitempty"`
+	PromptVariant  string    `json:"prompt_variant,omitempty"`
+	Difficulty     string    `json:"difficulty,omitempty"`
+	ArchivedAt     time.Time `json:"archived_at"`
+	// CommentArchivedAt is when the comment summary was added (zero without one)
+	CommentArchivedAt time.Time   `json:"comment_archived_at,omitempty"`
+	Provenance        *Provenance `json:"provenance,omitempty"`
+
+	// RegeneratedAt is when the summary was regenerated with newer prompt templates (zero if never);
+	// the summary it replaced is kept for comparison
+	RegeneratedAt      time.Time   `json:"regenerated_at,omitempty"`
+	PreviousSummary    string      `json:"previous_summary,omitempty"`
+	PreviousProvenance *Provenance `json:"previous_provenance,omitempty"`
+}
+
+// SummaryArchiveRepository keeps every generated summary, one object per article, so summaries
+// outlive the notification. It is a Notifier so it can mirror every feed's notifications.
+type SummaryArchiveRepository interface {
+	Notifier
+	// Get returns the archived summary of an article URL (nil when it was never archived)
+	Get(ctx context.Context, articleURL string) (*SummaryArchiveEntry, error)
+	// List returns the summaries archived since the given time, newest first
+	List(ctx context.Context, since time.Time) ([]*SummaryArchiveEntry, error)
+	// Update replaces an archived entry as is (e.g. with a regenerated summary)
+	Update(ctx context.Context, entry *SummaryArchiveEntry) error
+	Close() error
+}
+
+type summaryArchiveRepository struct {
+	storage Storage
+	prefix  string
+	mu      sync.Mutex // serializes the read-modify-write that attaches comment summaries
+}
+
+// NewSummaryArchiveRepository creates a summary archive in the shared storage, under
+// SUMMARY_ARCHIVE_PREFIX (default summaries/)
+func NewSummaryArchiveRepository() (SummaryArchiveRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := defaultSummaryArchivePrefix
+	if env := os.Getenv("SUMMARY_ARCHIVE_PREFIX"); env != "" {
+		prefix = env
+	}
+
+	return newSummaryArchiveRepository(store, prefix), nil
+}
+
+func newSummaryArchiveRepository(store Storage, prefix string) *summaryArchiveRepository {
+	return &summaryArchiveRepository{
+		storage: store,
+		prefix:  prefix,
+	}
+}
+
+// Send archives an article summary (replacing an older one for the URL), or attaches a comment
+// summary to its article's entry
+func (g *summaryArchiveRepository) Send(ctx context.Context, notification Notification) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := processedKey(Item{Link: notification.URL})
+	now := time.Now()
+
+	if notification.Comment {
+		entry, err := g.load(ctx, key)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			// The article summary was not archived (e.g. archiving was enabled in between)
+			entry = &SummaryArchiveEntry{
+				Title:  notification.Title,
+				URL:    key,
+				Link:   notification.URL,
+				Source: notification.Source,
+			}
+		}
+		entry.CommentSummary = notification.Summary
+		entry.CommentArchivedAt = now
+		return g.save(ctx, entry)
+	}
+
+	return g.save(ctx, &SummaryArchiveEntry{
+		Title:         notification.Title,
+		OriginalTitle: notification.OriginalTitle,
+		URL:           key,
+		Link:          notification.URL,
+		Source:        notification.Source,
+		Summary:       notification.Summary,
+		PromptVariant: notification.PromptVariant,
+		Difficulty:    notification.Difficulty,
+		ArchivedAt:    now,
+		Provenance:    notification.Provenance,
+	})
+}
+
+// SendOnDemandSummary archives an on-demand summary; targetChannel does not apply
+func (g *summaryArchiveRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	title := article.Title
+	if title == "" {
+		title = summary.Title
+	}
+	if title == "" {
+		title = article.Link
+	}
+
+	return g.Send(ctx, Notification{
+		Title:      title,
+		Source:     SourceOnDemand,
+		URL:        article.Link,
+		Summary:    summary.Summary,
+		Provenance: summary.Provenance,
+	})
+}
+
+// Get returns the archived summary of an article URL, looked up by its normalized form
+func (g *summaryArchiveRepository) Get(ctx context.Context, articleURL string) (*SummaryArchiveEntry, error) {
+	return g.load(ctx, processedKey(Item{Link: articleURL}))
+}
+
+// List reads every archived object and keeps those archived since the given time
+func (g *summaryArchiveRepository) List(ctx context.Context, since time.Time) ([]*SummaryArchiveEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	names, err := g.storage.List(ctx, g.prefix)
+	if err != nil {
+		logger.Printf("Error listing archived summaries: %v", err)
+		return nil, fmt.Errorf("listing archived summaries: %w", err)
+	}
+
+	var entries []*SummaryArchiveEntry
+	for _, name := range names {
+		data, err := g.storage.Read(ctx, name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archived summary %s: %w", name, err)
+		}
+		var entry SummaryArchiveEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			// One corrupted object should not hide the rest of the archive
+			logger.Printf("Warning: Skipping unreadable archived summary %s: %v", name, err)
+			continue
+		}
+		if entry.ArchivedAt.Before(since) {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+	})
+	return entries, nil
+}
+
+// Update writes the entry under its URL
+func (g *summaryArchiveRepository) Update(ctx context.Context, entry *SummaryArchiveEntry) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.save(ctx, entry)
+}
+
+// Close closes the storage
+func (g *summaryArchiveRepository) Close() error {
+	return g.storage.Close()
+}
+
+// objectName names an article's object by the SHA-256 of its normalized URL, since URLs contain slashes
+func (g *summaryArchiveRepository) objectName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return g.prefix + hex.EncodeToString(sum[:]) + ".json"
+}
+
+func (g *summaryArchiveRepository) load(ctx context.Context, key string) (*SummaryArchiveEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := g.storage.Read(ctx, g.objectName(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		logger.Printf("Error reading archived summary: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("reading archived summary: %w", err)
+	}
+
+	var entry SummaryArchiveEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		logger.Printf("Error unmarshaling archived summary: %v", err)
+		return nil, fmt.Errorf("unmarshaling archived summary: %w", err)
+	}
+	return &entry, nil
+}
+
+func (g *summaryArchiveRepository) save(ctx context.Context, entry *SummaryArchiveEntry) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling archived summary: %w", err)
+	}
+	name := g.objectName(entry.URL)
+	if err := g.storage.Write(ctx, name, data, "application/json"); err != nil {
+		logger.Printf("Error writing archived summary %s: %v\nStack:\n%s", name, err, debug.Stack())
+		return fmt.Errorf("writing archived summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSummaryArchiveRepository_SendAndGet(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := newSummaryArchiveRepository(store, defaultSummaryArchivePrefix)
+	ctx := context.Background()
+
+	if err := repo.Send(ctx, Notification{
+		Title:         "Rewritten headline",
+		OriginalTitle: "Feed title",
+		Source:        "hatena",
+		URL:           "http://www.example.com/Article/?utm_source=rss",
+		Summary:       "article summary",
+		Provenance:    &Provenance{Provider: "gemini", Model: "gemini-2.5-flash"},
+	}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := repo.Send(ctx, Notification{
+		Title:   "Rewritten headline - コメント",
+		URL:     "https://example.com/article",
+		Summary: "comment summary",
+		Comment: true,
+	}); err != nil {
+		t.Fatalf("Send comment failed: %v", err)
+	}
+
+	// Looked up by any form of the URL that normalizes the same
+	entry, err := repo.Get(ctx, "https://example.com/article/")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if entry == nil {
+		t.Fatal("Expected the archived summary")
+	}
+	if entry.URL != "https://example.com/article" || entry.Link != "http://www.example.com/Article/?utm_source=rss" {
+		t.Errorf("Expected the normalized and original URL, got %q %q", entry.URL, entry.Link)
+	}
+	if entry.Title != "Rewritten headline" || entry.OriginalTitle != "Feed title" || entry.Source != "hatena" {
+		t.Errorf("Unexpected article fields: %+v", entry)
+	}
+	if entry.Summary != "article summary" || entry.CommentSummary != "comment summary" || entry.CommentArchivedAt.IsZero() {
+		t.Errorf("Expected both summaries, got %+v", entry)
+	}
+	if entry.Provenance == nil || entry.Provenance.Model != "gemini-2.5-flash" {
+		t.Errorf("Expected the provenance to be kept, got %+v", entry.Provenance)
+	}
+
+	// One object per article
+	if names, _ := store.List(ctx, defaultSummaryArchivePrefix); len(names) != 1 {
+		t.Errorf("Expected 1 archived object, got %v", names)
+	}
+
+	if entry, err := repo.Get(ctx, "https://example.com/other"); err != nil || entry != nil {
+		t.Errorf("Expected nil for an unarchived URL, got %+v (%v)", entry, err)
+	}
+}
+
+func TestSummaryArchiveRepository_ListAndUpdate(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repo := newSummaryArchiveRepository(store, defaultSummaryArchivePrefix)
+	ctx := context.Background()
+
+	for _, url := range []string{"https://example.com/old", "https://example.com/new"} {
+		if err := repo.Send(ctx, Notification{Title: url, URL: url, Summary: "summary"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	old, _ := repo.Get(ctx, "https://example.com/old")
+	old.ArchivedAt = time.Now().AddDate(0, 0, -30)
+	old.PreviousSummary = "first summary"
+	if err := repo.Update(ctx, old); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	entries, err := repo.List(ctx, time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].URL != "https://example.com/new" {
+		t.Errorf("Expected only the recent entry, got %+v", entries)
+	}
+
+	entries, _ = repo.List(ctx, time.Time{})
+	if len(entries) != 2 || entries[0].URL != "https://example.com/new" || entries[1].PreviousSummary != "first summary" {
+		t.Errorf("Expected both entries newest first, got %+v", entries)
+	}
+}
@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const (
+	defaultSummaryFeedFile   = "summary_feed.json"
+	defaultSummaryFeedExport = "feed.xml"
+	defaultSummaryFeedSize   = 50
+)
+
+// SummaryFeedEntry is one generated summary published in the RSS feed
+type SummaryFeedEntry struct {
+	Title          string    `json:"title"`
+	URL            string    `json:"url"`
+	Source         string    `json:"source"`
+	Summary        string    `json:"summary"`
+	CommentSummary string    `json:"comment_summary,omitempty"`
+	PublishedAt    time.Time `json:"published_at"`
+	// Provenance is not rendered in the RSS export
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// SummaryFeedRepository keeps the last N summaries for the published RSS feed.
+// It is a Notifier so it can mirror every feed's notifications.
+type SummaryFeedRepository interface {
+	Notifier
+	List(ctx context.Context) ([]*SummaryFeedEntry, error)
+	Close() error
+}
+
+type summaryFeedRepository struct {
+	storage    Storage
+	feedFile   string
+	exportFile string // RSS export object; empty disables the export
+	size       int
+
+	mu            sync.Mutex // serializes this instance's feed updates
+	exportPending bool       // entries changed since the last RSS export
+}
+
+// NewSummaryFeedRepository creates a summary feed stored next to the processed index.
+// Each run's updates are also exported as rendered RSS to SUMMARY_FEED_EXPORT for static hosting.
+func NewSummaryFeedRepository() (SummaryFeedRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	feedFile := defaultSummaryFeedFile
+	if env := os.Getenv("SUMMARY_FEED_FILE"); env != "" {
+		feedFile = env
+	}
+
+	exportFile := defaultSummaryFeedExport
+	if env, ok := os.LookupEnv("SUMMARY_FEED_EXPORT"); ok {
+		exportFile = env
+	}
+
+	size := defaultSummaryFeedSize
+	if env := os.Getenv("SUMMARY_FEED_SIZE"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			size = n
+		}
+	}
+
+	return &summaryFeedRepository{
+		storage:    store,
+		feedFile:   feedFile,
+		exportFile: exportFile,
+		size:       size,
+	}, nil
+}
+
+// Send adds an article summary, or attaches a comment summary to its article's entry.
+// The RSS export is rewritten once per run by Flush.
+func (g *summaryFeedRepository) Send(ctx context.Context, notification Notification) error {
+	err := g.update(ctx, func(entries []*SummaryFeedEntry) []*SummaryFeedEntry {
+		return addSummaryFeedEntry(entries, notification, time.Now(), g.size)
+	})
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	g.exportPending = g.exportFile != ""
+	g.mu.Unlock()
+	return nil
+}
+
+// Flush exports the latest entries as RSS when they changed since the last export
+func (g *summaryFeedRepository) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	pending := g.exportPending
+	g.exportPending = false
+	g.mu.Unlock()
+	if !pending {
+		return nil
+	}
+
+	entries, err := g.load(ctx)
+	if err == nil {
+		err = g.export(ctx, entries)
+	}
+	if err != nil {
+		g.mu.Lock()
+		g.exportPending = true // The JSON feed is the source of truth; the next flush re-exports
+		g.mu.Unlock()
+		return fmt.Errorf("exporting summary RSS feed: %w", err)
+	}
+	return nil
+}
+
+// SendOnDemandSummary publishes an on-demand summary; targetChannel does not apply
+func (g *summaryFeedRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	title := article.Title
+	if title == "" {
+		title = summary.Title
+	}
+	if title == "" {
+		title = article.Link
+	}
+
+	err := g.Send(ctx, Notification{
+		Title:      title,
+		Source:     SourceOnDemand,
+		URL:        article.Link,
+		Summary:    summary.Summary,
+		Provenance: summary.Provenance,
+	})
+	if err != nil {
+		return err
+	}
+	// On-demand requests have no run to flush at the end of
+	return g.Flush(ctx)
+}
+
+// List returns the published entries, newest first
+func (g *summaryFeedRepository) List(ctx context.Context) ([]*SummaryFeedEntry, error) {
+	return g.load(ctx)
+}
+
+// Close closes the storage
+func (g *summaryFeedRepository) Close() error {
+	return g.storage.Close()
+}
+
+func (g *summaryFeedRepository) load(ctx context.Context) ([]*SummaryFeedEntry, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	data, err := g.storage.Read(ctx, g.feedFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []*SummaryFeedEntry{}, nil
+		}
+		logger.Printf("Error reading summary feed: %v\nStack:\n%s", err, debug.Stack())
+		return nil, fmt.Errorf("reading summary feed: %w", err)
+	}
+
+	var entries []*SummaryFeedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		logger.Printf("Error unmarshaling summary feed: %v", err)
+		return nil, fmt.Errorf("unmarshaling summary feed: %w", err)
+	}
+	return entries, nil
+}
+
+// update applies mutate to the latest entries and saves them. Like the processed index (see
+// processedIndexRepository.updateIndex), on versioned storage the save is conditional on the
+// generation that was read, and a concurrent invocation's write makes it re-read and re-apply mutate.
+func (g *summaryFeedRepository) update(ctx context.Context, mutate func(entries []*SummaryFeedEntry) []*SummaryFeedEntry) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	versioned, ok := g.storage.(VersionedStorage)
+	if !ok {
+		entries, err := g.load(ctx)
+		if err != nil {
+			return err
+		}
+		return g.save(ctx, mutate(entries))
+	}
+
+	for attempt := 1; ; attempt++ {
+		entries, generation, err := g.loadVersioned(ctx, versioned)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(mutate(entries))
+		if err != nil {
+			return fmt.Errorf("marshaling summary feed: %w", err)
+		}
+
+		err = versioned.WriteIfGeneration(ctx, g.feedFile, data, "application/json", generation)
+		if !errors.Is(err, ErrVersionConflict) {
+			if err != nil {
+				logger.Printf("Error writing object %s: %v\nStack:\n%s", g.feedFile, err, debug.Stack())
+				return fmt.Errorf("writing %s: %w", g.feedFile, err)
+			}
+			return nil
+		}
+		if attempt == maxIndexUpdateAttempts {
+			return fmt.Errorf("writing %s: %w (%d attempts)", g.feedFile, err, attempt)
+		}
+		logger.Printf("Summary feed changed concurrently, retrying update attempt=%d", attempt)
+		// Jittered so invocations that collided do not collide again
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt)*50*time.Millisecond + rand.N(50*time.Millisecond)):
+		}
+	}
+}
+
+// loadVersioned reads the entries with their generation (0 while the feed does not exist)
+func (g *summaryFeedRepository) loadVersioned(ctx context.Context, versioned VersionedStorage) ([]*SummaryFeedEntry, int64, error) {
+	data, generation, err := versioned.ReadVersioned(ctx, g.feedFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return []*SummaryFeedEntry{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading summary feed: %w", err)
+	}
+	var entries []*SummaryFeedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, 0, fmt.Errorf("unmarshaling summary feed: %w", err)
+	}
+	return entries, generation, nil
+}
+
+func (g *summaryFeedRepository) save(ctx context.Context, entries []*SummaryFeedEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshaling summary feed: %w", err)
+	}
+	return g.write(ctx, g.feedFile, "application/json", data)
+}
+
+func (g *summaryFeedRepository) export(ctx context.Context, entries []*SummaryFeedEntry) error {
+	data, err := RenderSummaryFeedRSS(entries, "Article Summarizer", "")
+	if err != nil {
+		return err
+	}
+	return g.write(ctx, g.exportFile, "application/rss+xml; charset=utf-8", data)
+}
+
+func (g *summaryFeedRepository) write(ctx context.Context, name, contentType string, data []byte) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if err := g.storage.Write(ctx, name, data, contentType); err != nil {
+		logger.Printf("Error writing object %s: %v\nStack:\n%s", name, err, debug.Stack())
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// addSummaryFeedEntry prepends an article entry (replacing an older one for the same URL) and caps the feed at size.
+// Comment summaries are attached to the existing entry for their URL and dropped when there is none.
+func addSummaryFeedEntry(entries []*SummaryFeedEntry, notification Notification, now time.Time, size int) []*SummaryFeedEntry {
+	if notification.Comment {
+		for _, entry := range entries {
+			if entry.URL == notification.URL {
+				entry.CommentSummary = notification.Summary
+				break
+			}
+		}
+		return entries
+	}
+
+	updated := []*SummaryFeedEntry{{
+		Title:       notification.Title,
+		URL:         notification.URL,
+		Source:      notification.Source,
+		Summary:     notification.Summary,
+		PublishedAt: now,
+		Provenance:  notification.Provenance,
+	}}
+	for _, entry := range entries {
+		if entry.URL != notification.URL {
+			updated = append(updated, entry)
+		}
+	}
+	if len(updated) > size {
+		updated = updated[:size]
+	}
+	return updated
+}
+
+type summaryRSS struct {
+	XMLName xml.Name          `xml:"rss"`
+	Version string            `xml:"version,attr"`
+	Channel summaryRSSChannel `xml:"channel"`
+}
+
+type summaryRSSChannel struct {
+	Title         string           `xml:"title"`
+	Link          string           `xml:"link,omitempty"`
+	Description   string           `xml:"description"`
+	LastBuildDate string           `xml:"lastBuildDate,omitempty"`
+	Items         []summaryRSSItem `xml:"item"`
+}
+
+type summaryRSSItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Category    string `xml:"category"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// RenderSummaryFeedRSS renders entries as an RSS 2.0 document; link is the channel's home page (optional)
+func RenderSummaryFeedRSS(entries []*SummaryFeedEntry, title, link string) ([]byte, error) {
+	channel := summaryRSSChannel{
+		Title:       title,
+		Link:        link,
+		Description: "記事要約ボットが生成した要約",
+	}
+	if len(entries) > 0 {
+		channel.LastBuildDate = entries[0].PublishedAt.Format(time.RFC1123Z)
+	}
+
+	for _, entry := range entries {
+		description := entry.Summary
+		if entry.CommentSummary != "" {
+			description += "\n\n💬 コメント要約:\n" + entry.CommentSummary
+		}
+		channel.Items = append(channel.Items, summaryRSSItem{
+			Title:       entry.Title,
+			Link:        entry.URL,
+			GUID:        entry.URL,
+			Category:    entry.Source,
+			PubDate:     entry.PublishedAt.Format(time.RFC1123Z),
+			Description: description,
+		})
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&b)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(summaryRSS{Version: "2.0", Channel: channel}); err != nil {
+		return nil, fmt.Errorf("encoding RSS: %w", err)
+	}
+	return b.Bytes(), nil
+}
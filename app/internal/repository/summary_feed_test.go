@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddSummaryFeedEntry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var entries []*SummaryFeedEntry
+
+	entries = addSummaryFeedEntry(entries, Notification{Title: "A", URL: "https://example.com/a", Source: "hatena", Summary: "sa"}, now, 2)
+	entries = addSummaryFeedEntry(entries, Notification{Title: "A - コメント", URL: "https://example.com/a", Summary: "ca", Comment: true}, now, 2)
+	entries = addSummaryFeedEntry(entries, Notification{Title: "B", URL: "https://example.com/b", Summary: "sb"}, now.Add(time.Minute), 2)
+	entries = addSummaryFeedEntry(entries, Notification{Title: "C", URL: "https://example.com/c", Summary: "sc"}, now.Add(2*time.Minute), 2)
+
+	if len(entries) != 2 || entries[0].Title != "C" || entries[1].Title != "B" {
+		t.Fatalf("Expected newest two entries C, B, got %+v", entries)
+	}
+
+	// Comment for an entry that is no longer in the feed is dropped
+	entries = addSummaryFeedEntry(entries, Notification{URL: "https://example.com/a", Summary: "late", Comment: true}, now, 2)
+	if len(entries) != 2 {
+		t.Errorf("Expected comment without entry to be dropped, got %d entries", len(entries))
+	}
+
+	// Re-summarized article replaces its old entry
+	entries = addSummaryFeedEntry(entries, Notification{Title: "B2", URL: "https://example.com/b", Summary: "sb2"}, now.Add(3*time.Minute), 2)
+	if len(entries) != 2 || entries[0].Title != "B2" || entries[1].Title != "C" {
+		t.Errorf("Expected B2, C, got %+v", entries)
+	}
+}
+
+func TestAddSummaryFeedEntry_AttachesComment(t *testing.T) {
+	now := time.Now()
+	entries := addSummaryFeedEntry(nil, Notification{Title: "A", URL: "https://example.com/a", Summary: "sa"}, now, 10)
+	entries = addSummaryFeedEntry(entries, Notification{URL: "https://example.com/a", Summary: "ca", Comment: true}, now, 10)
+
+	if len(entries) != 1 || entries[0].CommentSummary != "ca" {
+		t.Errorf("Expected comment summary attached, got %+v", entries)
+	}
+}
+
+func TestRenderSummaryFeedRSS(t *testing.T) {
+	data, err := RenderSummaryFeedRSS([]*SummaryFeedEntry{{
+		Title:          "Tom & Jerry <3",
+		URL:            "https://example.com/a?x=1&y=2",
+		Source:         "reddit",
+		Summary:        "summary",
+		CommentSummary: "comments",
+		PublishedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}, "Article Summarizer", "https://summarizer.example.com/history")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rss := string(data)
+	for _, want := range []string{
+		`<rss version="2.0">`,
+		"<title>Tom &amp; Jerry &lt;3</title>",
+		"<link>https://example.com/a?x=1&amp;y=2</link>",
+		"<category>reddit</category>",
+		"<pubDate>Thu, 02 Jan 2025 03:04:05 +0000</pubDate>",
+		"コメント要約",
+	} {
+		if !strings.Contains(rss, want) {
+			t.Errorf("Expected RSS to contain %q, got:\n%s", want, rss)
+		}
+	}
+}
+
+func TestSummaryFeedRepository_ExportsOnFlush(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Creating storage failed: %v", err)
+	}
+	repo := &summaryFeedRepository{storage: store, feedFile: defaultSummaryFeedFile, exportFile: defaultSummaryFeedExport, size: defaultSummaryFeedSize}
+	ctx := context.Background()
+
+	for _, link := range []string{"https://example.com/1", "https://example.com/2"} {
+		if err := repo.Send(ctx, Notification{Title: link, URL: link, Source: "hatena", Summary: "summary"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if _, err := store.Read(ctx, defaultSummaryFeedExport); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected no export before the flush, got %v", err)
+	}
+
+	if err := repo.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := store.Read(ctx, defaultSummaryFeedExport)
+	if err != nil {
+		t.Fatalf("Reading export failed: %v", err)
+	}
+	if strings.Count(string(data), "<item>") != 2 {
+		t.Errorf("Expected both entries in the export, got:\n%s", data)
+	}
+}
+
+func TestSummaryFeedRepository_ConcurrentWriteRetries(t *testing.T) {
+	store := &versionedMemoryStorage{}
+	repo := &summaryFeedRepository{storage: store, feedFile: defaultSummaryFeedFile, size: defaultSummaryFeedSize}
+	concurrent := &summaryFeedRepository{storage: store, feedFile: defaultSummaryFeedFile, size: defaultSummaryFeedSize}
+	ctx := context.Background()
+
+	// Another invocation adds a summary between our read and our write, once
+	store.beforeWrite = func(s *versionedMemoryStorage) {
+		s.beforeWrite = nil
+		if err := concurrent.Send(ctx, Notification{Title: "Other", URL: "https://example.com/other"}); err != nil {
+			t.Fatalf("Concurrent send failed: %v", err)
+		}
+	}
+	if err := repo.Send(ctx, Notification{Title: "Mine", URL: "https://example.com/mine"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	entries, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].URL != "https://example.com/mine" || entries[1].URL != "https://example.com/other" {
+		t.Errorf("Expected both entries to survive, got %v", entries)
+	}
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// telegramSummaryLimit keeps messages under Telegram's 4096-character limit (counted after entity parsing)
+const telegramSummaryLimit = 3500
+
+// MarkdownV2 reserved characters; link URLs only need ")" and "\" escaped
+var (
+	telegramMarkdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+		">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	telegramURLEscaper = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+)
+
+type telegramRepository struct {
+	botToken   string
+	chatID     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewTelegramRepository creates a Notifier posting to a Telegram chat through a bot
+func NewTelegramRepository(botToken, chatID, baseURL string) Notifier {
+	return &telegramRepository{
+		botToken: botToken,
+		chatID:   chatID,
+		baseURL:  baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type telegramSendMessageRequest struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description,omitempty"`
+}
+
+func (t *telegramRepository) sendMessage(ctx context.Context, text, chatID string) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	body, err := json.Marshal(telegramSendMessageRequest{
+		ChatID:                chatID,
+		Text:                  text,
+		ParseMode:             "MarkdownV2",
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		logger.Printf("Error marshaling Telegram request: %v", err)
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	// The bot token is part of the URL, so never log the URL itself
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/bot%s/sendMessage", t.baseURL, t.botToken), bytes.NewReader(body))
+	if err != nil {
+		logger.Printf("Error creating Telegram HTTP request: %v", err)
+		return fmt.Errorf("creating request")
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		logger.Printf("Error sending request to Telegram API request_body=%s\nStack:\n%s", string(body), debug.Stack())
+		return httperr.Transport(errors.New("sending request to Telegram API failed"))
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(resp.Body)
+	var telegramResp telegramResponse
+	if err := json.Unmarshal(responseBody, &telegramResp); err != nil && resp.StatusCode == http.StatusOK {
+		logger.Printf("Error decoding Telegram response: %v", err)
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !telegramResp.OK {
+		logger.Printf("Telegram API request failed status_code=%d request_body=%s response_body=%s\nStack:\n%s",
+			resp.StatusCode, string(body), string(responseBody), debug.Stack())
+		err := fmt.Errorf("telegram API error: status %d: %s", resp.StatusCode, telegramResp.Description)
+		if resp.StatusCode == http.StatusOK {
+			// ok=false with 200 is a rejected message (e.g. invalid markup)
+			return httperr.Permanent(err)
+		}
+		return httperr.FromResponse(resp, err)
+	}
+
+	return nil
+}
+
+// Send sends a unified notification (article or comment summary)
+func (t *telegramRepository) Send(ctx context.Context, notification Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Telegram notification started title=%s source=%s", notification.Title, notification.Source)
+
+	if err := t.sendMessage(ctx, t.formatNotification(notification), t.chatID); err != nil {
+		logger.Printf("Error sending notification to Telegram: %v", err)
+		return err
+	}
+
+	duration := time.Since(start)
+	logger.Printf("Telegram notification completed title=%s source=%s duration_ms=%d",
+		notification.Title, notification.Source, duration.Milliseconds())
+	return nil
+}
+
+// SendOnDemandSummary sends an on-demand summary; targetChannel is used as the chat id when set
+func (t *telegramRepository) SendOnDemandSummary(ctx context.Context, article Item, summary SummarizeResponse, targetChannel string) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	chatID := t.chatID
+	if targetChannel != "" {
+		chatID = targetChannel
+	}
+
+	logger.Printf("On-demand Telegram notification started url=%s", article.Link)
+	if err := t.sendMessage(ctx, t.formatOnDemandMessage(article, summary), chatID); err != nil {
+		logger.Printf("Error sending on-demand summary to Telegram: %v", err)
+		return err
+	}
+
+	duration := time.Since(start)
+	logger.Printf("On-demand Telegram notification completed url=%s duration_ms=%d", article.Link, duration.Milliseconds())
+	return nil
+}
+
+func (t *telegramRepository) formatNotification(notification Notification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n", escapeTelegramMarkdown(notification.Title))
+	fmt.Fprintf(&b, "[🔗 記事を開く](%s)\n\n", escapeTelegramURL(notification.URL))
+	b.WriteString(escapeTelegramMarkdown(truncateRunes(notification.Summary, telegramSummaryLimit)))
+	b.WriteString("\n\n")
+
+	footer := fmt.Sprintf("📰 ソース: %s | 📊 コンテンツ文字数: %d文字", notification.Source, notification.ContentChars)
+	if notification.PromptVariant != "" {
+		footer += fmt.Sprintf(" | 🧪 プロンプト: %s", notification.PromptVariant)
+	}
+	fmt.Fprintf(&b, "_%s_", escapeTelegramMarkdown(footer))
+
+	return b.String()
+}
+
+func (t *telegramRepository) formatOnDemandMessage(article Item, summary SummarizeResponse) string {
+	title := article.Title
+	if title == "" {
+		title = article.Link
+	}
+
+	var b strings.Builder
+	b.WriteString("🔗 *オンデマンド要約リクエスト完了*\n\n")
+	fmt.Fprintf(&b, "[%s](%s)\n\n", escapeTelegramMarkdown(title), escapeTelegramURL(article.Link))
+	b.WriteString(escapeTelegramMarkdown(truncateRunes(summary.Summary, telegramSummaryLimit)))
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "_%s_", escapeTelegramMarkdown(fmt.Sprintf("📊 コンテンツ文字数: %d文字", summary.ContentChars)))
+
+	return b.String()
+}
+
+// escapeTelegramMarkdown escapes text for MarkdownV2 outside of entities
+func escapeTelegramMarkdown(s string) string {
+	return telegramMarkdownEscaper.Replace(s)
+}
+
+// escapeTelegramURL escapes the URL part of a MarkdownV2 inline link
+func escapeTelegramURL(s string) string {
+	return telegramURLEscaper.Replace(s)
+}
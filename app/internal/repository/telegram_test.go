@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEscapeTelegramMarkdown(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"Go 1.23 released!", `Go 1\.23 released\!`},
+		{"[draft] a_b *c* (d)", `\[draft\] a\_b \*c\* \(d\)`},
+		{"C# vs F#: x+y=z | {ok} ~`>-", "C\\# vs F\\#: x\\+y\\=z \\| \\{ok\\} \\~\\`\\>\\-"},
+		{`back\slash`, `back\\slash`},
+	}
+
+	for _, test := range tests {
+		if result := escapeTelegramMarkdown(test.input); result != test.expected {
+			t.Errorf("escapeTelegramMarkdown(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+
+	if result := escapeTelegramURL("https://en.wikipedia.org/wiki/Go_(language)"); result != `https://en.wikipedia.org/wiki/Go_(language\)` {
+		t.Errorf("Unexpected escaped URL: %s", result)
+	}
+}
+
+func TestTelegramRepository_Send(t *testing.T) {
+	var received telegramSendMessageRequest
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	notifier := NewTelegramRepository("123:abc", "42", server.URL)
+	err := notifier.Send(context.Background(), Notification{
+		Title:        "Go 1.23 (release)",
+		Source:       "hatena",
+		URL:          "https://example.com/a_(b)",
+		Summary:      "要約です。",
+		ContentChars: 100,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if path != "/bot123:abc/sendMessage" {
+		t.Errorf("Unexpected path: %s", path)
+	}
+	if received.ChatID != "42" || received.ParseMode != "MarkdownV2" {
+		t.Errorf("Unexpected request: %+v", received)
+	}
+	if !strings.Contains(received.Text, `*Go 1\.23 \(release\)*`) {
+		t.Errorf("Expected escaped bold title, got %s", received.Text)
+	}
+	if !strings.Contains(received.Text, `(https://example.com/a_(b\))`) {
+		t.Errorf("Expected escaped link URL, got %s", received.Text)
+	}
+}
+
+func TestTelegramRepository_SendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok": false, "description": "Bad Request: can't parse entities"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewTelegramRepository("123:abc", "42", server.URL)
+	err := notifier.Send(context.Background(), Notification{Title: "t", URL: "https://example.com"})
+	if err == nil || !strings.Contains(err.Error(), "can't parse entities") {
+		t.Errorf("Expected Telegram error description, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// ErrAudioTooLarge is returned for audio above the transcription size limit; such episodes are
+// summarized from their show notes instead
+var ErrAudioTooLarge = errors.New("audio exceeds the transcription size limit")
+
+// Transcriber converts spoken audio into text
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioURL string) (string, error)
+}
+
+// sttRepository transcribes audio through an OpenAI-compatible speech-to-text API
+// (POST <baseURL>/audio/transcriptions), e.g. OpenAI Whisper, Groq or a self-hosted whisper server
+type sttRepository struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	maxBytes   int64
+	httpClient *http.Client
+}
+
+// NewSTTRepository creates a Transcriber; audio larger than maxBytes is not sent to the API
+func NewSTTRepository(baseURL, apiKey, model string, maxBytes int64) Transcriber {
+	return &sttRepository{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   apiKey,
+		model:    model,
+		maxBytes: maxBytes,
+		httpClient: &http.Client{
+			// Downloads and transcription of an hour-long episode take minutes
+			Timeout: 10 * time.Minute,
+		},
+	}
+}
+
+func (s *sttRepository) Transcribe(ctx context.Context, audioURL string) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	audio, err := s.download(ctx, audioURL)
+	if err != nil {
+		return "", err
+	}
+	downloadDuration := time.Since(start)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("model", s.model); err != nil {
+		return "", fmt.Errorf("building transcription request: %w", err)
+	}
+	if err := writer.WriteField("response_format", "text"); err != nil {
+		return "", fmt.Errorf("building transcription request: %w", err)
+	}
+	part, err := writer.CreateFormFile("file", audioFileName(audioURL))
+	if err != nil {
+		return "", fmt.Errorf("building transcription request: %w", err)
+	}
+	if _, err := part.Write(audio); err != nil {
+		return "", fmt.Errorf("building transcription request: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("building transcription request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return "", fmt.Errorf("creating transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("transcription request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("reading transcription response: %w", err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		logger.Printf("Transcription API error status=%d body=%s", resp.StatusCode, string(respBody))
+		return "", httperr.FromResponse(resp, fmt.Errorf("transcription API returned status %d", resp.StatusCode))
+	}
+
+	transcript := strings.TrimSpace(string(respBody))
+	logger.Printf("Audio transcribed url=%s audio_bytes=%d transcript_length=%d download_ms=%d total_ms=%d",
+		audioURL, len(audio), len(transcript), downloadDuration.Milliseconds(), time.Since(start).Milliseconds())
+	return transcript, nil
+}
+
+// download reads the audio, giving up as soon as it exceeds the size limit
+func (s *sttRepository) download(ctx context.Context, audioURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", audioURL, nil)
+	if err != nil {
+		return nil, httperr.Permanent(fmt.Errorf("creating audio request: %w", err))
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Article Summarizer Bot/1.0)")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, httperr.Transport(fmt.Errorf("downloading audio: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httperr.FromResponse(resp, fmt.Errorf("audio download returned status %d", resp.StatusCode))
+	}
+	if s.maxBytes > 0 && resp.ContentLength > s.maxBytes {
+		return nil, httperr.Permanent(fmt.Errorf("%w (%d bytes)", ErrAudioTooLarge, resp.ContentLength))
+	}
+
+	reader := io.Reader(resp.Body)
+	if s.maxBytes > 0 {
+		reader = io.LimitReader(resp.Body, s.maxBytes+1)
+	}
+	audio, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, httperr.Transport(fmt.Errorf("downloading audio: %w", err))
+	}
+	if s.maxBytes > 0 && int64(len(audio)) > s.maxBytes {
+		return nil, httperr.Permanent(fmt.Errorf("%w (more than %d bytes)", ErrAudioTooLarge, s.maxBytes))
+	}
+	return audio, nil
+}
+
+// audioFileName keeps the file extension, which the API uses to detect the audio format
+func audioFileName(audioURL string) string {
+	name := path.Base(strings.SplitN(strings.SplitN(audioURL, "?", 2)[0], "#", 2)[0])
+	if name == "" || name == "." || name == "/" || !strings.Contains(name, ".") {
+		return "episode.mp3"
+	}
+	return name
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func newSTTTestServer(t *testing.T, audio string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/episode.m4a":
+			w.Write([]byte(audio))
+		case "/v1/audio/transcriptions":
+			if r.Header.Get("Authorization") != "Bearer test-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("Expected an audio file: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			if header.Filename != "episode.m4a" || r.FormValue("model") != "whisper-1" {
+				t.Errorf("Unexpected upload filename=%s model=%s", header.Filename, r.FormValue("model"))
+			}
+			w.Write([]byte("  Welcome to the show.\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSTTRepository_Transcribe(t *testing.T) {
+	server := newSTTTestServer(t, "fake audio")
+	stt := NewSTTRepository(server.URL+"/v1/", "test-key", "whisper-1", 1024)
+
+	transcript, err := stt.Transcribe(context.Background(), server.URL+"/episode.m4a?token=abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transcript != "Welcome to the show." {
+		t.Errorf("Unexpected transcript %q", transcript)
+	}
+}
+
+func TestSTTRepository_TranscribeTooLarge(t *testing.T) {
+	server := newSTTTestServer(t, strings.Repeat("a", 2048))
+	stt := NewSTTRepository(server.URL+"/v1", "test-key", "whisper-1", 1024)
+
+	_, err := stt.Transcribe(context.Background(), server.URL+"/episode.m4a")
+	if !errors.Is(err, ErrAudioTooLarge) || !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent ErrAudioTooLarge, got %v", err)
+	}
+}
+
+func TestSTTRepository_TranscribeAPIError(t *testing.T) {
+	server := newSTTTestServer(t, "fake audio")
+	stt := NewSTTRepository(server.URL+"/v1", "wrong-key", "whisper-1", 1024)
+
+	_, err := stt.Transcribe(context.Background(), server.URL+"/episode.m4a")
+	if err == nil || !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent error for a rejected API key, got %v", err)
+	}
+}
+
+func TestAudioFileName(t *testing.T) {
+	tests := map[string]string{
+		"https://cdn.example.com/show/ep1.mp3?x=1": "ep1.mp3",
+		"https://cdn.example.com/show/ep1":         "episode.mp3",
+	}
+	for input, expected := range tests {
+		if got := audioFileName(input); got != expected {
+			t.Errorf("audioFileName(%s) = %s, expected %s", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+)
+
+const defaultUsagePrefix = "usage/"
+
+// UsageRepository counts on-demand requests per user (token ID or Slack user) and UTC day
+type UsageRepository interface {
+	Record(ctx context.Context, user string, t time.Time) error
+	Count(ctx context.Context, user string, day time.Time) (int, error)
+	Daily(ctx context.Context, day time.Time) (map[string]int, error)
+	Close() error
+}
+
+// usageRepository writes one empty object per request under <prefix><day>/<user>/,
+// so concurrent requests never rewrite a shared counter
+type usageRepository struct {
+	storage Storage
+	prefix  string
+}
+
+// NewUsageRepository creates a usage log stored next to the processed index
+func NewUsageRepository() (UsageRepository, error) {
+	store, err := NewStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := defaultUsagePrefix
+	if env := os.Getenv("USAGE_PREFIX"); env != "" {
+		prefix = env
+	}
+
+	return &usageRepository{
+		storage: store,
+		prefix:  prefix,
+	}, nil
+}
+
+// Record counts one request for user at t
+func (g *usageRepository) Record(ctx context.Context, user string, t time.Time) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	name, err := usageObjectName(g.prefix, user, t)
+	if err != nil {
+		return err
+	}
+
+	if err := g.storage.Create(ctx, name, nil, ""); err != nil {
+		logger.Printf("Error writing usage record %s: %v\nStack:\n%s", name, err, debug.Stack())
+		return fmt.Errorf("writing usage record: %w", err)
+	}
+	return nil
+}
+
+// Count returns the user's requests on the given UTC day
+func (g *usageRepository) Count(ctx context.Context, user string, day time.Time) (int, error) {
+	count := 0
+	err := g.list(ctx, usageDayPrefix(g.prefix, day)+url.PathEscape(user)+"/", func(name string) {
+		count++
+	})
+	return count, err
+}
+
+// Daily returns every user's request count on the given UTC day
+func (g *usageRepository) Daily(ctx context.Context, day time.Time) (map[string]int, error) {
+	dayPrefix := usageDayPrefix(g.prefix, day)
+	counts := make(map[string]int)
+	err := g.list(ctx, dayPrefix, func(name string) {
+		escaped, _, _ := strings.Cut(strings.TrimPrefix(name, dayPrefix), "/")
+		if user, err := url.PathUnescape(escaped); err == nil {
+			counts[user]++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (g *usageRepository) list(ctx context.Context, prefix string, visit func(name string)) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	names, err := g.storage.List(ctx, prefix)
+	if err != nil {
+		logger.Printf("Error listing usage records: %v\nStack:\n%s", err, debug.Stack())
+		return fmt.Errorf("listing usage records: %w", err)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return nil
+}
+
+// Close closes the storage
+func (g *usageRepository) Close() error {
+	return g.storage.Close()
+}
+
+func usageDayPrefix(prefix string, day time.Time) string {
+	return prefix + day.UTC().Format("2006-01-02") + "/"
+}
+
+// usageObjectName builds "<prefix><day>/<escaped user>/<timestamp>-<random>"
+func usageObjectName(prefix, user string, t time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating usage record id: %w", err)
+	}
+	timestamp := strings.Replace(t.UTC().Format("150405.000000000"), ".", "", 1)
+	return fmt.Sprintf("%s%s/%s-%s", usageDayPrefix(prefix, t), url.PathEscape(user), timestamp, hex.EncodeToString(suffix)), nil
+}
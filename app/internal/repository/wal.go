@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const walPrefix = "wal/"
+
+// WriteAheadLog durably records entries an instance buffers in memory between flushes (processed
+// marks, queued digest notifications), one object per entry, so that entries of an instance that
+// crashed before its flush are replayed by a later one instead of being lost
+type WriteAheadLog struct {
+	storage  Storage
+	prefix   string // wal/<name>/
+	instance string // Distinguishes this instance's entries from the ones it replays
+
+	mu       sync.Mutex
+	sequence int
+	written  []string // This instance's entries not yet committed
+}
+
+// walEntry is an entry read back from the log
+type walEntry struct {
+	name string
+	data []byte
+}
+
+// NewWALStorage opens the store of write-ahead logs when WAL_ENABLED is set (nil otherwise):
+// WAL_DIR on the local disk when it is set, else the shared storage (see NewStorage)
+func NewWALStorage() (Storage, error) {
+	if os.Getenv("WAL_ENABLED") != "true" {
+		return nil, nil
+	}
+	if dir := os.Getenv("WAL_DIR"); dir != "" {
+		return NewLocalStorage(dir)
+	}
+	return NewStorage()
+}
+
+// NewWriteAheadLog returns the log name in store; a nil store disables it (a nil log records nothing)
+func NewWriteAheadLog(store Storage, name string) *WriteAheadLog {
+	if store == nil {
+		return nil
+	}
+	return &WriteAheadLog{
+		storage:  store,
+		prefix:   walPrefix + name + "/",
+		instance: fmt.Sprintf("%016x", rand.Uint64()),
+	}
+}
+
+// Append records an entry before it is buffered
+func (w *WriteAheadLog) Append(ctx context.Context, entry any) error {
+	if w == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling WAL entry: %w", err)
+	}
+
+	w.mu.Lock()
+	w.sequence++
+	// Named by time first, so entries list in the order they were written
+	name := fmt.Sprintf("%s%020d-%s-%06d.json", w.prefix, time.Now().UnixNano(), w.instance, w.sequence)
+	w.mu.Unlock()
+
+	if err := w.storage.Create(ctx, name, data, "application/json"); err != nil {
+		return fmt.Errorf("writing WAL entry: %w", err)
+	}
+
+	w.mu.Lock()
+	w.written = append(w.written, name)
+	w.mu.Unlock()
+	return nil
+}
+
+// Commit deletes this instance's entries once what they record is flushed
+func (w *WriteAheadLog) Commit(ctx context.Context) error {
+	return w.discard(ctx, w.take())
+}
+
+// take hands over this instance's entries written so far, for a flush of what they record;
+// the flush discards them, or gives them back with restore when it fails
+func (w *WriteAheadLog) take() []string {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	names := w.written
+	w.written = nil
+	return names
+}
+
+func (w *WriteAheadLog) restore(names []string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written = append(names, w.written...)
+}
+
+// replay reads the entries other instances left behind that were written at least minAge ago
+// (older entries of a live instance may still be in its buffer), oldest first
+func (w *WriteAheadLog) replay(ctx context.Context, minAge time.Duration) ([]walEntry, error) {
+	if w == nil {
+		return nil, nil
+	}
+	names, err := w.storage.List(ctx, w.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing WAL entries: %w", err)
+	}
+
+	var entries []walEntry
+	for _, name := range names {
+		writtenAt, instance, ok := parseWALEntryName(strings.TrimPrefix(name, w.prefix))
+		if !ok || instance == w.instance || time.Since(writtenAt) < minAge {
+			continue
+		}
+		data, err := w.storage.Read(ctx, name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // Committed meanwhile
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading WAL entry: %w", err)
+		}
+		entries = append(entries, walEntry{name: name, data: data})
+	}
+	return entries, nil
+}
+
+// discardEntries deletes replayed entries once they are flushed
+func (w *WriteAheadLog) discardEntries(ctx context.Context, entries []walEntry) error {
+	if w == nil {
+		return nil
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.name
+	}
+	return w.discard(ctx, names)
+}
+
+func (w *WriteAheadLog) discard(ctx context.Context, names []string) error {
+	if w == nil {
+		return nil
+	}
+	var errs []error
+	for _, name := range names {
+		if err := w.storage.Delete(ctx, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("deleting WAL entries: %w", err)
+	}
+	return nil
+}
+
+// parseWALEntryName splits "<unix nanos>-<instance>-<sequence>.json"
+func parseWALEntryName(name string) (time.Time, string, bool) {
+	parts := strings.Split(strings.TrimSuffix(path.Base(name), ".json"), "-")
+	if len(parts) != 3 {
+		return time.Time{}, "", false
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return time.Unix(0, nanos), parts[1], true
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteAheadLog_CommitAndReplay(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	crashed := NewWriteAheadLog(store, "test")
+	for _, title := range []string{"first", "second"} {
+		if err := crashed.Append(ctx, Notification{Title: title}); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	// A later instance replays the entries the crashed one left, oldest first, but not its own
+	next := NewWriteAheadLog(store, "test")
+	if err := next.Append(ctx, Notification{Title: "own"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	entries, err := next.replay(ctx, 0)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	var titles []string
+	for _, entry := range entries {
+		var notification Notification
+		if err := json.Unmarshal(entry.data, &notification); err != nil {
+			t.Fatalf("Unexpected entry %s: %v", entry.data, err)
+		}
+		titles = append(titles, notification.Title)
+	}
+	if strings.Join(titles, ",") != "first,second" {
+		t.Errorf("Expected the crashed instance's entries in order, got %v", titles)
+	}
+
+	// Entries younger than minAge may still be buffered by a live instance
+	if young, _ := next.replay(ctx, time.Hour); len(young) != 0 {
+		t.Errorf("Expected recent entries to be skipped, got %d", len(young))
+	}
+
+	if err := next.discardEntries(ctx, entries); err != nil {
+		t.Fatalf("discardEntries failed: %v", err)
+	}
+	if err := next.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if names, _ := store.List(ctx, walPrefix); len(names) != 0 {
+		t.Errorf("Expected an empty log, got %v", names)
+	}
+}
+
+func TestWriteAheadLog_NilIsDisabled(t *testing.T) {
+	wal := NewWriteAheadLog(nil, "test")
+	ctx := context.Background()
+	if err := wal.Append(ctx, Notification{}); err != nil {
+		t.Errorf("Expected a disabled log to accept entries, got %v", err)
+	}
+	if entries, err := wal.replay(ctx, 0); err != nil || entries != nil {
+		t.Errorf("Expected nothing to replay, got %v, %v", entries, err)
+	}
+	if err := wal.Commit(ctx); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
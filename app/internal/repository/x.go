@@ -9,6 +9,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
 )
 
 // PostData represents data from a social media post
@@ -118,12 +120,12 @@ func (x *XClient) callOEmbedAPI(ctx context.Context, postURL string) (*oEmbedRes
 
 	resp, err := x.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, httperr.Transport(fmt.Errorf("HTTP request failed: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("oEmbed API returned status %d", resp.StatusCode)
+		return nil, httperr.FromResponse(resp, fmt.Errorf("oEmbed API returned status %d", resp.StatusCode))
 	}
 
 	var oembedResp oEmbedResponse
@@ -220,7 +222,7 @@ func (x *XClient) expandTcoURL(ctx context.Context, tcoURL string) (string, erro
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to expand t.co URL: %w", err)
+		return "", httperr.Transport(fmt.Errorf("failed to expand t.co URL: %w", err))
 	}
 	defer resp.Body.Close()
 
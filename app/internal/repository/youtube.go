@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// ErrNoTranscript is returned for videos without captions (neither uploaded nor auto-generated)
+var ErrNoTranscript = errors.New("video has no transcript")
+
+// youTubeVideoIDPattern matches the 11-character video IDs
+var youTubeVideoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// youTubeTitlePattern reads the video title from the watch page
+var youTubeTitlePattern = regexp.MustCompile(`<meta (?:property="og:title"|name="title") content="([^"]*)"`)
+
+// YouTubeVideoID returns the video ID of a watch, short link or Shorts URL ("" for other URLs)
+func YouTubeVideoID(pageURL *url.URL) string {
+	var id string
+	switch strings.TrimPrefix(pageURL.Host, "www.") {
+	case "youtube.com", "m.youtube.com":
+		if pageURL.Path == "/watch" {
+			id = pageURL.Query().Get("v")
+		} else if rest, ok := strings.CutPrefix(pageURL.Path, "/shorts/"); ok {
+			id = rest
+		}
+	case "youtu.be":
+		id = strings.TrimPrefix(pageURL.Path, "/")
+	}
+	if !youTubeVideoIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// YouTubeTranscriptFetcher reads a video's captions instead of its watch page, whose HTML
+// carries no readable content. Manually uploaded captions are preferred over auto-generated
+// ones, in the order of the preferred languages.
+type YouTubeTranscriptFetcher struct {
+	// watchBaseURL is the watch page root (overridable for tests)
+	watchBaseURL string
+	languages    []string
+	httpClient   *http.Client
+}
+
+// NewYouTubeTranscriptFetcher creates a fetcher preferring captions in languages (e.g. ja, en)
+func NewYouTubeTranscriptFetcher(languages []string, httpClient *http.Client) *YouTubeTranscriptFetcher {
+	return &YouTubeTranscriptFetcher{
+		watchBaseURL: "https://www.youtube.com",
+		languages:    languages,
+		httpClient:   httpClient,
+	}
+}
+
+func (f *YouTubeTranscriptFetcher) Matches(pageURL *url.URL) bool {
+	return YouTubeVideoID(pageURL) != ""
+}
+
+// captionTrack is an entry of the watch page's captionTracks player data
+type captionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"` // "asr" for auto-generated captions
+}
+
+func (f *YouTubeTranscriptFetcher) Fetch(ctx context.Context, pageURL string) (string, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing YouTube URL: %w", err)
+	}
+	videoID := YouTubeVideoID(parsed)
+	if videoID == "" {
+		return "", httperr.Permanent(fmt.Errorf("no video ID in %s", pageURL))
+	}
+
+	page, err := f.get(ctx, f.watchBaseURL+"/watch?v="+videoID)
+	if err != nil {
+		return "", fmt.Errorf("fetching watch page: %w", err)
+	}
+	tracks, err := parseCaptionTracks(page)
+	if err != nil {
+		return "", err
+	}
+	track := selectCaptionTrack(tracks, f.languages)
+	if track == nil {
+		return "", httperr.Permanent(fmt.Errorf("%s: %w", videoID, ErrNoTranscript))
+	}
+
+	captions, err := f.get(ctx, track.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("fetching captions: %w", err)
+	}
+	transcript, err := parseTranscript(captions)
+	if err != nil {
+		return "", err
+	}
+	if transcript == "" {
+		return "", httperr.Permanent(fmt.Errorf("%s: %w", videoID, ErrNoTranscript))
+	}
+
+	var title string
+	if m := youTubeTitlePattern.FindStringSubmatch(page); m != nil {
+		title = html.UnescapeString(m[1])
+	}
+	return documentHTML(title, "<p>"+html.EscapeString(transcript)+"</p>"), nil
+}
+
+func (f *YouTubeTranscriptFetcher) get(ctx context.Context, target string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Article Summarizer Bot/1.0)")
+	if len(f.languages) > 0 {
+		req.Header.Set("Accept-Language", strings.Join(f.languages, ","))
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", httperr.Transport(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", httperr.FromResponse(resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", httperr.Transport(fmt.Errorf("reading response: %w", err))
+	}
+	return string(body), nil
+}
+
+// parseCaptionTracks decodes the captionTracks array embedded in the watch page's player data
+func parseCaptionTracks(page string) ([]captionTrack, error) {
+	const marker = `"captionTracks":`
+	i := strings.Index(page, marker)
+	if i < 0 {
+		// Videos without captions have no captionTracks at all
+		return nil, nil
+	}
+
+	var tracks []captionTrack
+	if err := json.NewDecoder(strings.NewReader(page[i+len(marker):])).Decode(&tracks); err != nil {
+		return nil, fmt.Errorf("parsing caption tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+// selectCaptionTrack picks uploaded captions before auto-generated ones, each in language order.
+// Without a match in the preferred languages the first track is used.
+func selectCaptionTrack(tracks []captionTrack, languages []string) *captionTrack {
+	if len(tracks) == 0 {
+		return nil
+	}
+	for _, generated := range []bool{false, true} {
+		for _, language := range languages {
+			for i := range tracks {
+				if (tracks[i].Kind == "asr") == generated && strings.EqualFold(tracks[i].LanguageCode, language) {
+					return &tracks[i]
+				}
+			}
+		}
+	}
+	return &tracks[0]
+}
+
+// parseTranscript joins the caption lines of a timedtext document into running text
+func parseTranscript(captions string) (string, error) {
+	var doc struct {
+		Lines []string `xml:"text"`
+	}
+	if err := xml.Unmarshal([]byte(captions), &doc); err != nil {
+		return "", fmt.Errorf("parsing captions: %w", err)
+	}
+
+	lines := make([]string, 0, len(doc.Lines))
+	for _, line := range doc.Lines {
+		// Caption text is HTML-escaped once more inside the XML (e.g. &amp;#39;)
+		if line = strings.Join(strings.Fields(html.UnescapeString(line)), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " "), nil
+}
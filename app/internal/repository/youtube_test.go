@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestYouTubeVideoID(t *testing.T) {
+	tests := map[string]string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42": "dQw4w9WgXcQ",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ":        "dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ":                     "dQw4w9WgXcQ",
+		"https://www.youtube.com/shorts/dQw4w9WgXcQ":       "dQw4w9WgXcQ",
+		"https://www.youtube.com/@channel":                 "",
+		"https://example.com/watch?v=dQw4w9WgXcQ":          "",
+	}
+	for raw, want := range tests {
+		parsed, _ := url.Parse(raw)
+		if got := YouTubeVideoID(parsed); got != want {
+			t.Errorf("%s: expected %q, got %q", raw, want, got)
+		}
+	}
+}
+
+func TestSelectCaptionTrack(t *testing.T) {
+	tracks := []captionTrack{
+		{BaseURL: "en-asr", LanguageCode: "en", Kind: "asr"},
+		{BaseURL: "en", LanguageCode: "en"},
+		{BaseURL: "ja-asr", LanguageCode: "ja", Kind: "asr"},
+	}
+	// Uploaded English captions beat auto-generated Japanese ones
+	if track := selectCaptionTrack(tracks, []string{"ja", "en"}); track.BaseURL != "en" {
+		t.Errorf("Expected uploaded English captions, got %s", track.BaseURL)
+	}
+	if track := selectCaptionTrack(tracks[2:], []string{"fr"}); track.BaseURL != "ja-asr" {
+		t.Errorf("Expected the first track as fallback, got %s", track.BaseURL)
+	}
+	if selectCaptionTrack(nil, []string{"en"}) != nil {
+		t.Error("Expected no track for a video without captions")
+	}
+}
+
+func TestYouTubeTranscriptFetcher_Fetch(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/watch" && r.URL.Query().Get("v") == "dQw4w9WgXcQ":
+			w.Write([]byte(`<html><head><meta property="og:title" content="Go &amp; You"></head><body><script>var ytInitialPlayerResponse = {"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[{"baseUrl":"` +
+				server.URL + `/api/timedtext?v=dQw4w9WgXcQ&lang=en","name":{"runs":[{"text":"English"}]},"languageCode":"en","kind":"asr"}]}}};</script></body></html>`))
+		case r.URL.Path == "/watch":
+			w.Write([]byte(`<html><body>no captions</body></html>`))
+		case r.URL.Path == "/api/timedtext" && r.URL.Query().Get("lang") == "en":
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8" ?><transcript><text start="0" dur="1.5">Hello and</text><text start="1.5" dur="2">welcome, it&amp;#39;s
+Go time</text></transcript>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewYouTubeTranscriptFetcher([]string{"ja", "en"}, server.Client())
+	fetcher.watchBaseURL = server.URL
+
+	html, err := fetcher.Fetch(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(html, "<title>Go &amp; You</title>") || !strings.Contains(html, "Hello and welcome, it&#39;s Go time") {
+		t.Errorf("Unexpected HTML: %s", html)
+	}
+
+	_, err = fetcher.Fetch(context.Background(), "https://www.youtube.com/watch?v=aaaaaaaaaaa")
+	if !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("Expected ErrNoTranscript, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+type ArxivProcessor struct {
+	arxivRepo     rss.FeedRepository
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+}
+
+func NewArxivProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *ArxivProcessor {
+	return &ArxivProcessor{
+		arxivRepo:     rss.NewArxivRepository(rssRepo),
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+	}
+}
+
+func (p *ArxivProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=arxiv")
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=arxiv duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=arxiv")
+	articles, err := p.arxivRepo.FetchArticles(ctx)
+	if err != nil {
+		logger.Printf("Error processing feed arxiv: %v", err)
+		return fmt.Errorf("processing feed arxiv: %w", err)
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "arxiv", len(articles))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed articles
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, "arxiv", articles)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
+
+	logger.Printf("Selected unprocessed articles: %d from arXiv", len(limitedArticles))
+
+	// Process each article
+	run := startOpsRun(ctx, p.notifier, "arxiv", len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
+		if err := p.processArxivArticle(ctx, article); err != nil {
+			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, "arxiv", article, err)
+			return fmt.Errorf("processing article %s: %w", article.Title, err)
+		}
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=arxiv processed_count=%d", len(limitedArticles))
+	return nil
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *ArxivProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processArxivArticle(ctx, article)
+}
+
+// processArxivArticle summarizes a paper and posts it with its authors and PDF link
+func (p *ArxivProcessor) processArxivArticle(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Article processing started title=%s url=%s source=%s",
+		article.Title, article.Link, article.Source)
+
+	// 2. 論文要約
+	summaryStart := time.Now()
+	summary, err := summarizeArxivPaper(ctx, p.geminiRepo, article)
+	if err != nil {
+		logger.Printf("Error summarizing article %s: %v", article.Title, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
+
+	// 3. 通知送信
+	slackStart := time.Now()
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+		Authors:       article.Authors,
+		PDFURL:        article.PDFURL,
+	}); err != nil {
+		logger.Printf("Error sending article notification for %s: %v", article.Title, err)
+		return fmt.Errorf("sending article notification: %w", err)
+	}
+	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
+
+	// 4. インデックス更新
+	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	totalDuration := time.Since(start)
+	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
+		article.Title, totalDuration.Milliseconds(), summaryDuration.Milliseconds(), slackDuration.Milliseconds(), processDuration.Milliseconds())
+
+	return nil
+}
+
+// summarizeArxivPaper summarizes the paper's HTML rendering, which opens with the abstract. Papers
+// without one (older or PDF-only submissions) are summarized from the abstract alone.
+func summarizeArxivPaper(ctx context.Context, gemini repository.GeminiRepository, article repository.Item) (*repository.SummarizeResponse, error) {
+	return summarizeSourceOrText(ctx, gemini, article, rss.ArxivHTMLURL(article.Link), "Abstract")
+}
@@ -0,0 +1,94 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// sourceGemini answers SummarizeURL with a fixed error and records the fallback text prompt
+type sourceGemini struct {
+	mocks.MockGeminiRepo
+	urlErr   error
+	urls     []string
+	textSent string
+}
+
+func (g *sourceGemini) SummarizeURL(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	g.urls = append(g.urls, url)
+	if g.urlErr != nil {
+		return nil, g.urlErr
+	}
+	return &repository.SummarizeResponse{Summary: strings.Repeat("論文の要約です。", 20), ContentChars: 42000}, nil
+}
+
+func (g *sourceGemini) SummarizeText(ctx context.Context, text string) (string, error) {
+	g.textSent = text
+	return "abstract summary", nil
+}
+
+func TestArxivProcessor_Process_SummarizesHTMLVersion(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	gemini := &sourceGemini{}
+	processor := NewArxivProcessor(
+		&mocks.MockArxivRepo{},
+		gemini,
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(gemini.urls) != 1 || gemini.urls[0] != "https://arxiv.org/html/2401.00001v1" {
+		t.Errorf("Expected the HTML version to be summarized, got %v", gemini.urls)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	notification := slackRepo.SentNotifications[0]
+	if notification.URL != "https://arxiv.org/abs/2401.00001v1" || notification.PDFURL != "https://arxiv.org/pdf/2401.00001v1" {
+		t.Errorf("Unexpected links: %s %s", notification.URL, notification.PDFURL)
+	}
+	if len(notification.Authors) != 2 || notification.Authors[0] != "Alice Example" {
+		t.Errorf("Unexpected authors: %v", notification.Authors)
+	}
+}
+
+func TestSummarizeArxivPaper_FallsBackToAbstract(t *testing.T) {
+	article := repository.Item{
+		Title:       "Test arXiv Paper",
+		Link:        "https://arxiv.org/abs/2401.00001v1",
+		Description: "We study a test problem and report results.",
+	}
+
+	// No HTML rendering (404): the abstract is summarized instead
+	gemini := &sourceGemini{urlErr: fmt.Errorf("fetching HTML: %w", httperr.Status(http.StatusNotFound, "unexpected status code: 404"))}
+	summary, err := summarizeArxivPaper(context.Background(), gemini, article)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Summary != "abstract summary" || !strings.Contains(gemini.textSent, "We study a test problem") {
+		t.Errorf("Expected an abstract summary, got %q from %q", summary.Summary, gemini.textSent)
+	}
+
+	// Outages are not downgraded to the abstract; the backlog retries the paper
+	gemini = &sourceGemini{urlErr: fmt.Errorf("fetching HTML: %w", httperr.Status(http.StatusServiceUnavailable, "unexpected status code: 503"))}
+	if _, err := summarizeArxivPaper(context.Background(), gemini, article); !httperr.Retryable(err) {
+		t.Errorf("Expected the temporary error, got %v", err)
+	}
+	if gemini.textSent != "" {
+		t.Error("Abstract should not be summarized on a temporary failure")
+	}
+}
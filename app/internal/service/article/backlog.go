@@ -0,0 +1,148 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// ItemProcessor processes a single article of one feed
+type ItemProcessor interface {
+	ProcessItem(ctx context.Context, article repository.Item) error
+}
+
+// CommentRetrier retries the comment summary of an article that was already posted
+type CommentRetrier interface {
+	RetryComments(ctx context.Context, article repository.Item) error
+}
+
+// DrainResult summarizes one backlog drain run
+type DrainResult struct {
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+	Remaining int `json:"remaining"`
+}
+
+// BacklogDrainProcessor retries the retry-later backlog entries at a low rate so it does not
+// compete with the regular feed runs. Dead-letter entries are kept for inspection and never retried.
+type BacklogDrainProcessor struct {
+	backlogRepo   repository.BacklogRepository
+	processedRepo repository.ProcessedArticleRepository
+	processors    map[string]ItemProcessor
+	limit         int
+	interval      time.Duration
+}
+
+func NewBacklogDrainProcessor(
+	backlogRepo repository.BacklogRepository,
+	processedRepo repository.ProcessedArticleRepository,
+	processors map[string]ItemProcessor,
+	limit int,
+	interval time.Duration,
+) *BacklogDrainProcessor {
+	return &BacklogDrainProcessor{
+		backlogRepo:   backlogRepo,
+		processedRepo: processedRepo,
+		processors:    processors,
+		limit:         limit,
+		interval:      interval,
+	}
+}
+
+// Drain retries up to limit backlog entries, pausing interval between attempts
+func (p *BacklogDrainProcessor) Drain(ctx context.Context) (*DrainResult, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Backlog drain started limit=%d interval_ms=%d", p.limit, p.interval.Milliseconds())
+
+	start := time.Now()
+	defer func() {
+		logger.Printf("Backlog drain completed duration_ms=%d", time.Since(start).Milliseconds())
+	}()
+
+	entries, err := p.backlogRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing backlog: %w", err)
+	}
+
+	index, err := p.processedRepo.LoadIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading index: %w", err)
+	}
+	index = repository.WithLegacyKeys(index)
+
+	result := &DrainResult{}
+	for _, entry := range entries {
+		if !entry.DeadLetter {
+			result.Remaining++
+		}
+	}
+	attempted := 0
+	for _, entry := range entries {
+		// Dead-lettered entries failed too often (or permanently); retrying them would repeat the failure
+		if entry.DeadLetter {
+			continue
+		}
+
+		// Comment tasks belong to articles that are already processed
+		feed, commentTask := entry.CommentTask()
+
+		// A later feed run may already have picked the article up
+		if !commentTask && p.processedRepo.IsProcessed(p.processedRepo.GenerateKey(entry.Item), index) {
+			if err := p.backlogRepo.Remove(ctx, entry.Key()); err != nil {
+				return result, fmt.Errorf("removing processed backlog entry: %w", err)
+			}
+			result.Remaining--
+			continue
+		}
+
+		if attempted >= p.limit {
+			break
+		}
+
+		processor, ok := p.processors[feed]
+		if !ok {
+			logger.Printf("Warning: No processor for backlog entry feed=%s url=%s", entry.Feed, entry.Item.Link)
+			continue
+		}
+		retry := processor.ProcessItem
+		if commentTask {
+			retrier, ok := processor.(CommentRetrier)
+			if !ok {
+				logger.Printf("Warning: Feed does not retry comments feed=%s url=%s", feed, entry.Item.Link)
+				continue
+			}
+			retry = retrier.RetryComments
+		}
+
+		if attempted > 0 && p.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return result, ctx.Err()
+			case <-time.After(p.interval):
+			}
+		}
+		attempted++
+
+		if err := retry(ctx, entry.Item); err != nil {
+			logger.Printf("Backlog entry failed again url=%s feed=%s attempts=%d dead_letter=%t: %v",
+				entry.Item.Link, entry.Feed, entry.Attempts+1, entry.DeadLetter, err)
+			recordBacklog(ctx, p.backlogRepo, entry.Feed, entry.Item, err)
+			result.Failed++
+			continue
+		}
+
+		if err := p.backlogRepo.Remove(ctx, entry.Key()); err != nil {
+			return result, fmt.Errorf("removing drained backlog entry: %w", err)
+		}
+		result.Processed++
+		result.Remaining--
+		logger.Printf("Backlog entry processed url=%s feed=%s", entry.Item.Link, entry.Feed)
+	}
+
+	return result, nil
+}
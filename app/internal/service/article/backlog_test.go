@@ -0,0 +1,101 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+type stubItemProcessor struct {
+	fail      map[string]bool
+	processed []string
+	comments  []string
+}
+
+func (s *stubItemProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	if s.fail[article.Link] {
+		return errors.New("still failing")
+	}
+	s.processed = append(s.processed, article.Link)
+	return nil
+}
+
+func (s *stubItemProcessor) RetryComments(ctx context.Context, article repository.Item) error {
+	s.comments = append(s.comments, article.Link)
+	return nil
+}
+
+func TestBacklogDrainProcessor_Drain(t *testing.T) {
+	backlogRepo := &mocks.MockBacklogRepo{
+		Entries: []*repository.BacklogEntry{
+			{Feed: "hatena", Item: repository.Item{Link: "https://example.com/dead"}, Attempts: 3, DeadLetter: true},
+			{Feed: "hatena", Item: repository.Item{Link: "https://example.com/1"}, Attempts: 1},
+			{Feed: "hatena", Item: repository.Item{Link: "https://example.com/2"}, Attempts: 1},
+			{Feed: "hatena", Item: repository.Item{Link: "https://example.com/3"}, Attempts: 1},
+		},
+	}
+	processor := &stubItemProcessor{fail: map[string]bool{"https://example.com/2": true}}
+
+	drain := NewBacklogDrainProcessor(
+		backlogRepo,
+		&mocks.MockProcessedRepo{},
+		map[string]ItemProcessor{"hatena": processor},
+		2,
+		0,
+	)
+
+	result, err := drain.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Processed != 1 || result.Failed != 1 || result.Remaining != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(backlogRepo.Removed) != 1 || backlogRepo.Removed[0] != "https://example.com/1" {
+		t.Errorf("Expected only the drained entry to be removed, got %v", backlogRepo.Removed)
+	}
+	for _, link := range processor.processed {
+		if link == "https://example.com/dead" {
+			t.Error("Dead-letter entries must not be retried")
+		}
+	}
+	if backlogRepo.Entries[2].Attempts != 2 {
+		t.Errorf("Expected failed entry to be recorded again, got attempts=%d", backlogRepo.Entries[2].Attempts)
+	}
+}
+
+func TestBacklogDrainProcessor_DrainCommentTask(t *testing.T) {
+	article := repository.Item{Title: "Posted", Link: "https://example.com/posted"}
+	processedRepo := repository.NewMemoryProcessedArticleRepository()
+	if err := processedRepo.MarkAsProcessed(context.Background(), article); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	backlogRepo := &mocks.MockBacklogRepo{
+		Entries: []*repository.BacklogEntry{
+			{Feed: repository.CommentTaskFeed("lobsters"), Item: article, Attempts: 1},
+		},
+	}
+	processor := &stubItemProcessor{}
+
+	drain := NewBacklogDrainProcessor(backlogRepo, processedRepo, map[string]ItemProcessor{"lobsters": processor}, 5, 0)
+	result, err := drain.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The article is already processed, but its comment summary is still retried
+	if len(processor.comments) != 1 || len(processor.processed) != 0 {
+		t.Errorf("Expected only the comments to be retried, got comments=%v processed=%v", processor.comments, processor.processed)
+	}
+	if result.Processed != 1 || result.Remaining != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(backlogRepo.Removed) != 1 || backlogRepo.Removed[0] != "https://example.com/posted#comments" {
+		t.Errorf("Expected the comment task to be removed, got %v", backlogRepo.Removed)
+	}
+}
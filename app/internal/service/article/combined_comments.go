@@ -0,0 +1,70 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// combinedComments is the comment half of a summary made with COMMENT_SUMMARY_MODE=combined
+type combinedComments struct {
+	summary  *string       // nil when the article has no comments
+	chars    int           // Length of the comment text
+	duration time.Duration // Spent fetching the comments (their summary shares the article's call)
+	err      error         // Fetching or summarizing failed; the comment summary is retried from the backlog
+}
+
+// summarizeArticleAndComments summarizes the article with summarizeArticle and returns nil comments, leaving
+// the comment summary to the caller. With COMMENT_SUMMARY_MODE=combined the comments are fetched first
+// (fetchComments returns "" when there are none) and summarized in the same Gemini call as the article.
+// Articles without comments, and combined summaries missing the article content, go through summarizeArticle.
+func summarizeArticleAndComments(ctx context.Context, gemini repository.GeminiRepository, article repository.Item, fetchComments func(context.Context, repository.Item) (string, error)) (*repository.SummarizeResponse, *combinedComments, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	combiner, ok := gemini.(repository.CommentCombiner)
+	if !ok || !combiner.CombinesComments() {
+		summary, err := summarizeArticle(ctx, gemini, article)
+		return summary, nil, err
+	}
+
+	comments := &combinedComments{}
+	commentStart := time.Now()
+	text, err := fetchComments(ctx, article)
+	comments.duration = time.Since(commentStart)
+	if err != nil || text == "" {
+		// 記事だけ先に要約し、コメント取得の失敗はバックログから再試行する
+		comments.err = err
+		summary, err := summarizeArticle(ctx, gemini, article)
+		return summary, comments, err
+	}
+	comments.chars = len(text)
+
+	summary, commentSummary, err := combiner.SummarizeURLWithComments(ctx, article.Link, text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if summaryMissingContent(summary.Summary) {
+		// summarizeArticle retries the content through the render fallback or the description
+		logger.Printf("Combined summary missing content, summarizing the article separately url=%s", article.Link)
+		if summary, err = summarizeArticle(ctx, gemini, article); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		countSummary(currentRunStats(ctx), summary)
+	}
+
+	if commentSummary == nil {
+		logger.Printf("Combined summary without comment section, summarizing the comments separately url=%s", article.Link)
+		if commentSummary, err = gemini.SummarizeComments(ctx, text); err != nil {
+			comments.err = fmt.Errorf("failed to summarize comments: %w", err)
+			return summary, comments, nil
+		}
+	}
+	comments.summary = &commentSummary.Summary
+	return summary, comments, nil
+}
@@ -0,0 +1,126 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// combiningGemini summarizes articles together with their comments (COMMENT_SUMMARY_MODE=combined)
+type combiningGemini struct {
+	mocks.MockGeminiRepo
+	combine       bool
+	noCommentPart bool // The model leaves out the comment section
+	combinedCalls int
+	urlCalls      int
+	commentCalls  int
+	combinedText  string
+}
+
+func (g *combiningGemini) CombinesComments() bool {
+	return g.combine
+}
+
+func (g *combiningGemini) SummarizeURLWithComments(ctx context.Context, url, commentsText string) (*repository.SummarizeResponse, *repository.SummarizeResponse, error) {
+	g.combinedCalls++
+	g.combinedText = commentsText
+	article := &repository.SummarizeResponse{Summary: fullSummary, ContentChars: 1500}
+	if g.noCommentPart {
+		return article, nil, nil
+	}
+	return article, &repository.SummarizeResponse{Summary: "combined comment summary", ContentChars: len(commentsText)}, nil
+}
+
+func (g *combiningGemini) SummarizeURL(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	g.urlCalls++
+	return &repository.SummarizeResponse{Summary: fullSummary, ContentChars: 1500}, nil
+}
+
+func (g *combiningGemini) SummarizeComments(ctx context.Context, text string) (*repository.SummarizeResponse, error) {
+	g.commentCalls++
+	return g.MockGeminiRepo.SummarizeComments(ctx, text)
+}
+
+func fetchCommentsText(text string, err error) func(context.Context, repository.Item) (string, error) {
+	return func(context.Context, repository.Item) (string, error) {
+		return text, err
+	}
+}
+
+func TestSummarizeArticleAndComments_Combined(t *testing.T) {
+	gemini := &combiningGemini{combine: true}
+	article := repository.Item{Title: "Story", Link: "https://example.com/story"}
+
+	summary, comments, err := summarizeArticleAndComments(context.Background(), gemini, article, fetchCommentsText("Great story!", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gemini.combinedCalls != 1 || gemini.urlCalls != 0 || gemini.commentCalls != 0 {
+		t.Errorf("Expected a single combined call, got combined=%d url=%d comments=%d", gemini.combinedCalls, gemini.urlCalls, gemini.commentCalls)
+	}
+	if summary.Summary != fullSummary || gemini.combinedText != "Great story!" {
+		t.Errorf("Unexpected article summary %+v (comments %q)", summary, gemini.combinedText)
+	}
+	if comments == nil || comments.summary == nil || *comments.summary != "combined comment summary" || comments.chars != len("Great story!") {
+		t.Errorf("Expected the combined comment summary, got %+v", comments)
+	}
+}
+
+func TestSummarizeArticleAndComments_Fallbacks(t *testing.T) {
+	article := repository.Item{Title: "Story", Link: "https://example.com/story"}
+	ctx := context.Background()
+
+	// Separate mode leaves the comments to the caller
+	gemini := &combiningGemini{}
+	if _, comments, err := summarizeArticleAndComments(ctx, gemini, article, fetchCommentsText("Great story!", nil)); err != nil || comments != nil || gemini.urlCalls != 1 {
+		t.Errorf("Expected the separate article summary, got comments=%+v err=%v url=%d", comments, err, gemini.urlCalls)
+	}
+
+	// Without comments the article is summarized on its own
+	gemini = &combiningGemini{combine: true}
+	if _, comments, err := summarizeArticleAndComments(ctx, gemini, article, fetchCommentsText("", nil)); err != nil || comments == nil || comments.summary != nil || gemini.combinedCalls != 0 {
+		t.Errorf("Expected no comment summary, got comments=%+v err=%v combined=%d", comments, err, gemini.combinedCalls)
+	}
+
+	// A comment fetch failure is reported for the backlog, the article is still summarized
+	gemini = &combiningGemini{combine: true}
+	summary, comments, err := summarizeArticleAndComments(ctx, gemini, article, fetchCommentsText("", errors.New("comment API down")))
+	if err != nil || summary == nil || comments == nil || comments.err == nil {
+		t.Errorf("Expected the article summary and a comment error, got summary=%+v comments=%+v err=%v", summary, comments, err)
+	}
+
+	// A missing comment section is summarized separately
+	gemini = &combiningGemini{combine: true, noCommentPart: true}
+	_, comments, err = summarizeArticleAndComments(ctx, gemini, article, fetchCommentsText("Great story!", nil))
+	if err != nil || comments == nil || comments.summary == nil || *comments.summary != "test comment summary" || gemini.commentCalls != 1 {
+		t.Errorf("Expected a separate comment summary, got comments=%+v err=%v calls=%d", comments, err, gemini.commentCalls)
+	}
+}
+
+func TestHackerNewsProcessor_Process_CombinedComments(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	gemini := &combiningGemini{combine: true}
+	processor := NewHackerNewsProcessor(
+		&mocks.MockHackerNewsRepo{},
+		gemini,
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gemini.combinedCalls != 1 || gemini.urlCalls != 0 || gemini.commentCalls != 0 {
+		t.Errorf("Expected one Gemini call for the story, got combined=%d url=%d comments=%d", gemini.combinedCalls, gemini.urlCalls, gemini.commentCalls)
+	}
+	if len(slackRepo.SentNotifications) != 2 || slackRepo.SentNotifications[1].Summary != "combined comment summary" {
+		t.Errorf("Expected the article and the combined comment notifications, got %+v", slackRepo.SentNotifications)
+	}
+}
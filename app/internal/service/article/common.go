@@ -3,23 +3,44 @@ package article
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
 
 	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
 )
 
-// filterUnprocessedArticles filters out already processed articles
-func filterUnprocessedArticles(ctx context.Context, processedRepo repository.ProcessedArticleRepository, articles []repository.Item) ([]repository.Item, error) {
+// filterUnprocessedArticles filters out the feed's already processed articles, except those the run
+// reprocesses (see WithReprocess) and those whose mark outlived the feed's dedup TTL
+func filterUnprocessedArticles(ctx context.Context, processedRepo repository.ProcessedArticleRepository, feed string, articles []repository.Item) ([]repository.Item, error) {
 	// Load index once at the beginning
 	index, err := processedRepo.LoadIndex(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("loading index: %w", err)
 	}
+	index = repository.WithLegacyKeys(index)
 
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	dedupTTL, _ := processedRepo.(*repository.DedupTTLRepository)
 	var unprocessed []repository.Item
 	for _, article := range articles {
 		key := processedRepo.GenerateKey(article)
 		processed := processedRepo.IsProcessed(key, index)
 
+		if processed && Reprocessing(ctx, article.Link) {
+			// 強制再処理: 要約し直し、インデックスのエントリは MarkAsProcessed で上書きされる
+			logger.Printf("Reprocessing already processed article url=%s", article.Link)
+			processed = false
+		}
+		if processed && dedupTTL != nil && dedupTTL.Expired(ctx, feed, index[key]) {
+			logger.Printf("Processed mark expired, summarizing again feed=%s url=%s processed_date=%s",
+				feed, article.Link, index[key].ProcessedDate.Format(time.RFC3339))
+			processed = false
+		}
 		if !processed {
 			unprocessed = append(unprocessed, article)
 		}
@@ -27,3 +48,158 @@ func filterUnprocessedArticles(ctx context.Context, processedRepo repository.Pro
 
 	return unprocessed, nil
 }
+
+// recordBacklog stores a failed article for the backlog drain; a nil backlog disables recording
+func recordBacklog(ctx context.Context, backlogRepo repository.BacklogRepository, feed string, article repository.Item, cause error) {
+	if backlogRepo == nil {
+		return
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if err := backlogRepo.Record(ctx, feed, article, cause); err != nil {
+		// バックログ記録失敗でも元のエラーを優先して返す
+		logger.Printf("Warning: Failed to record article in backlog %s: %v", article.Link, err)
+	}
+}
+
+// recordCommentTask keeps a failed comment summary for the backlog drain after its article was posted,
+// and notes the delay in the ops thread
+func recordCommentTask(ctx context.Context, backlogRepo repository.BacklogRepository, feed string, article repository.Item, cause error) {
+	if backlogRepo == nil {
+		// Without a backlog the comment summary is simply skipped
+		recordWarning(ctx, "comments: "+cause.Error())
+		return
+	}
+	recordWarning(ctx, "discussion summary delayed: "+cause.Error())
+	recordBacklog(ctx, backlogRepo, repository.CommentTaskFeed(feed), article, cause)
+}
+
+// flushNotifier delivers batched notifications at the end of a run (no-op for immediate notifiers)
+func flushNotifier(ctx context.Context, notifier repository.Notifier) {
+	flusher, ok := notifier.(repository.Flusher)
+	if !ok {
+		return
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if err := flusher.Flush(ctx); err != nil {
+		// 通知のまとめ送信失敗は処理結果に影響させない
+		logger.Printf("Warning: Failed to flush batched notifications: %v", err)
+	}
+}
+
+// flushProcessed writes the run's buffered processed marks. A failure only logs: the articles
+// are summarized again by the next run.
+func flushProcessed(ctx context.Context, processedRepo repository.ProcessedArticleRepository) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if err := processedRepo.Flush(ctx); err != nil {
+		logger.Printf("Warning: Failed to flush processed articles: %v", err)
+	}
+}
+
+// runStats counts a feed run's outcomes; processArticles carries it in the articles' context
+type runStats struct {
+	attempted, failed       int32
+	summaries, summaryChars int32
+	retried, recovered      int32 // Missing-content summary retries (see summarizeArticle)
+}
+
+type runStatsKey struct{}
+
+// currentRunStats returns the run's stats, or nil outside processArticles
+func currentRunStats(ctx context.Context) *runStats {
+	stats, _ := ctx.Value(runStatsKey{}).(*runStats)
+	return stats
+}
+
+// processArticles runs fn over articles with a worker pool sized by the concurrency controller
+// (sequential when nil). After the first failure no new articles are started and that error is returned.
+// When run is non-nil each article's outcome and phase timings are annotated in the ops thread.
+// The returned FeedRun (without Items) feeds the per-feed statistics and the run report (see RunFeed).
+func processArticles(ctx context.Context, concurrency *limiter.ConcurrencyController, run *opsRun, articles []repository.Item, fn func(ctx context.Context, article repository.Item) error) (repository.FeedRun, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	stats := &runStats{}
+	ctx = context.WithValue(ctx, runStatsKey{}, stats)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		inflight int
+		firstErr error
+	)
+	cond := sync.NewCond(&mu)
+
+	limit := func() int {
+		if concurrency == nil {
+			return 1
+		}
+		return concurrency.Limit()
+	}
+
+	for _, article := range articles {
+		mu.Lock()
+		for inflight >= limit() && firstErr == nil {
+			cond.Wait()
+		}
+		if firstErr != nil {
+			mu.Unlock()
+			break
+		}
+		inflight++
+		mu.Unlock()
+
+		wg.Add(1)
+		go func(article repository.Item) {
+			defer wg.Done()
+			articleCtx := ctx
+			var phases *articlePhases
+			if run != nil {
+				phases = &articlePhases{}
+				articleCtx = context.WithValue(ctx, opsPhasesKey{}, phases)
+			}
+
+			start := time.Now()
+			err := fn(articleCtx, article)
+			if concurrency != nil {
+				concurrency.Observe(start, err)
+			}
+			if run != nil {
+				run.annotate(ctx, article, phases, time.Since(start), err)
+			}
+
+			atomic.AddInt32(&stats.attempted, 1)
+			if err != nil {
+				atomic.AddInt32(&stats.failed, 1)
+			}
+
+			mu.Lock()
+			inflight--
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			cond.Broadcast()
+			mu.Unlock()
+		}(article)
+	}
+	wg.Wait()
+
+	if run != nil {
+		run.finish(ctx)
+	}
+
+	logger.Printf("Summary retry stats retried=%d recovered=%d", stats.retried, stats.recovered)
+
+	if concurrency != nil {
+		stats := concurrency.Stats()
+		logger.Printf("Article concurrency stats limit=%d requests=%d rate_limited=%d avg_latency_ms=%d",
+			stats.Limit, stats.Requests, stats.RateLimited, stats.AvgLatency.Milliseconds())
+	}
+
+	feedRun := repository.FeedRun{
+		Attempted:    int(stats.attempted),
+		Failed:       int(stats.failed),
+		Summaries:    int(stats.summaries),
+		SummaryChars: int(stats.summaryChars),
+	}
+	reportFeedRun(ctx, len(articles), feedRun)
+	return feedRun, firstErr
+}
@@ -0,0 +1,71 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+func TestProcessArticles_RespectsConcurrencyLimit(t *testing.T) {
+	articles := make([]repository.Item, 6)
+	for i := range articles {
+		articles[i] = repository.Item{Link: "https://example.com/" + string(rune('a'+i))}
+	}
+
+	controller := limiter.NewConcurrencyController(2, 2, 0)
+	var inflight, maxInflight int32
+	var mu sync.Mutex
+	var seen []string
+
+	_, err := processArticles(context.Background(), controller, nil, articles, func(ctx context.Context, article repository.Item) error {
+		n := atomic.AddInt32(&inflight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inflight, -1)
+
+		mu.Lock()
+		seen = append(seen, article.Link)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(seen) != len(articles) {
+		t.Errorf("Expected %d articles processed, got %d", len(articles), len(seen))
+	}
+	if maxInflight > 2 {
+		t.Errorf("Expected at most 2 concurrent articles, got %d", maxInflight)
+	}
+}
+
+func TestProcessArticles_StopsAfterError(t *testing.T) {
+	articles := []repository.Item{{Link: "1"}, {Link: "2"}, {Link: "3"}}
+
+	var calls int
+	_, err := processArticles(context.Background(), nil, nil, articles, func(ctx context.Context, article repository.Item) error {
+		calls++
+		if article.Link == "2" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if calls != 2 {
+		t.Errorf("Expected processing to stop after the failure (2 calls), got %d", calls)
+	}
+}
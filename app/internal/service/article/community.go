@@ -0,0 +1,247 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+// CommunityProcessor processes a community's trending posts (Dev.to, Qiita, Zenn, Product Hunt).
+// The feeds differ only in how their posts are fetched and summarized; every post is posted
+// with its tags and likes or votes.
+type CommunityProcessor struct {
+	feed          string // Source of the posts
+	feedRepo      rss.FeedRepository
+	summarize     func(ctx context.Context, gemini repository.GeminiRepository, article repository.Item) (*repository.SummarizeResponse, error)
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+}
+
+func NewDevToProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityProcessor {
+	return newCommunityProcessor("devto", rss.NewDevToRepository(rssRepo), geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency)
+}
+
+func NewQiitaProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityProcessor {
+	return newCommunityProcessor("qiita", rss.NewQiitaRepository(rssRepo), geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency)
+}
+
+func NewZennProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityProcessor {
+	return newCommunityProcessor("zenn", rss.NewZennRepository(rssRepo), geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency)
+}
+
+// NewProductHuntProcessor summarizes each product from its website, or from its Product Hunt
+// description when the website has no readable content
+func NewProductHuntProcessor(
+	token string,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityProcessor {
+	p := newCommunityProcessor("producthunt", rss.NewProductHuntRepository(rssRepo, token), geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency)
+	p.summarize = func(ctx context.Context, gemini repository.GeminiRepository, article repository.Item) (*repository.SummarizeResponse, error) {
+		return summarizeSourceOrText(ctx, gemini, article, article.LinkedURL, "Product description")
+	}
+	return p
+}
+
+func newCommunityProcessor(
+	feed string,
+	feedRepo rss.FeedRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityProcessor {
+	return &CommunityProcessor{
+		feed:          feed,
+		feedRepo:      feedRepo,
+		summarize:     summarizeArticle,
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+	}
+}
+
+// Feed returns the source the posts are attributed to (e.g. "devto")
+func (p *CommunityProcessor) Feed() string {
+	return p.feed
+}
+
+func (p *CommunityProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=%s", p.feed)
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=%s duration_ms=%d", p.feed, duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=%s", p.feed)
+	articles, err := p.feedRepo.FetchArticles(ctx)
+	if err != nil {
+		logger.Printf("Error processing feed %s: %v", p.feed, err)
+		return fmt.Errorf("processing feed %s: %w", p.feed, err)
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, p.feed, len(articles))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed articles
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, p.feed, articles)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
+
+	logger.Printf("Selected unprocessed articles: %d from %s", len(limitedArticles), p.feed)
+
+	// Process each article
+	run := startOpsRun(ctx, p.notifier, p.feed, len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
+		if err := p.processCommunityArticle(ctx, article); err != nil {
+			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, p.feed, article, err)
+			return fmt.Errorf("processing article %s: %w", article.Title, err)
+		}
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=%s processed_count=%d", p.feed, len(limitedArticles))
+	return nil
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *CommunityProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processCommunityArticle(ctx, article)
+}
+
+// processCommunityArticle summarizes a post and posts it with its authors, tags and likes or votes
+func (p *CommunityProcessor) processCommunityArticle(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Article processing started title=%s url=%s source=%s",
+		article.Title, article.Link, article.Source)
+
+	// 2. 記事要約
+	summaryStart := time.Now()
+	summary, err := p.summarize(ctx, p.geminiRepo, article)
+	if err != nil {
+		logger.Printf("Error summarizing article %s: %v", article.Title, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
+
+	// 3. 通知送信
+	slackStart := time.Now()
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+		Authors:       article.Authors,
+		Tags:          article.Category,
+		Likes:         article.Likes,
+		Votes:         article.Votes,
+	}); err != nil {
+		logger.Printf("Error sending article notification for %s: %v", article.Title, err)
+		return fmt.Errorf("sending article notification: %w", err)
+	}
+	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
+
+	// 4. インデックス更新
+	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	totalDuration := time.Since(start)
+	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
+		article.Title, totalDuration.Milliseconds(), summaryDuration.Milliseconds(), slackDuration.Milliseconds(), processDuration.Milliseconds())
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package article
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestCommunityProcessors_Process(t *testing.T) {
+	devToSlack, qiitaSlack, zennSlack := &mocks.MockSlackRepo{}, &mocks.MockSlackRepo{}, &mocks.MockSlackRepo{}
+	tests := []struct {
+		feed      string
+		processor *CommunityProcessor
+		slackRepo *mocks.MockSlackRepo
+		tags      int
+	}{
+		{"devto", NewDevToProcessor(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, devToSlack, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil), devToSlack, 2},
+		{"qiita", NewQiitaProcessor(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, qiitaSlack, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil), qiitaSlack, 2},
+		{"zenn", NewZennProcessor(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, zennSlack, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil), zennSlack, 0}, // Zenn's list has no topics
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.feed, func(t *testing.T) {
+			if err := tt.processor.Process(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(tt.slackRepo.SentNotifications) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(tt.slackRepo.SentNotifications))
+			}
+			notification := tt.slackRepo.SentNotifications[0]
+			if notification.Source != tt.feed || notification.Likes != 100 || len(notification.Authors) != 1 {
+				t.Errorf("Unexpected notification: %+v", notification)
+			}
+			if len(notification.Tags) != tt.tags {
+				t.Errorf("Expected %d tags, got %v", tt.tags, notification.Tags)
+			}
+		})
+	}
+}
+
+func TestProductHuntProcessor_Process(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	processedRepo := &mocks.MockProcessedRepo{}
+	processor := NewProductHuntProcessor("", &mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, slackRepo, processedRepo, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	notification := slackRepo.SentNotifications[0]
+	if notification.Source != "producthunt" || notification.Title != "Test Product" || notification.Summary == "" {
+		t.Errorf("Unexpected notification: %+v", notification)
+	}
+}
@@ -0,0 +1,63 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// FeedEscalation is a FeedStatsRepository decorator that knows who owns each feed. Once a feed has
+// failed afterRuns runs in a row (every article of the run failed), its owner is mentioned in one
+// alert in the ops channel; the streak has to end before the feed is escalated again. Statistics
+// pass through.
+type FeedEscalation struct {
+	repository.FeedStatsRepository
+	owners    map[string]string    // Slack user or user group IDs by feed
+	alerts    repository.OpsThread // Posts the alerts of owned feeds
+	afterRuns int                  // 0 disables escalation
+}
+
+func NewFeedEscalation(statsRepo repository.FeedStatsRepository, owners map[string]string, alerts repository.OpsThread, afterRuns int) *FeedEscalation {
+	return &FeedEscalation{
+		FeedStatsRepository: statsRepo,
+		owners:              owners,
+		alerts:              alerts,
+		afterRuns:           afterRuns,
+	}
+}
+
+// Owner returns the feed's owner ("" for an unowned feed)
+func (e *FeedEscalation) Owner(feed string) string {
+	return e.owners[feed]
+}
+
+// Escalated reports whether the feed's failure streak has reached the escalation threshold
+func (e *FeedEscalation) Escalated(stats *repository.FeedStats) bool {
+	return e.afterRuns > 0 && stats.FailedRuns >= e.afterRuns
+}
+
+// escalate alerts when the run just recorded in stats made the failure streak reach the threshold.
+// Unowned feeds are only logged.
+func (e *FeedEscalation) escalate(ctx context.Context, stats *repository.FeedStats) {
+	if e.afterRuns == 0 || stats.FailedRuns != e.afterRuns {
+		return
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	owner := e.owners[stats.Feed]
+	logger.Printf("ALERT: Feed failing persistently feed=%s failed_runs=%d failure_rate=%.2f owner=%s",
+		stats.Feed, stats.FailedRuns, stats.FailureRate, owner)
+	if owner == "" || e.alerts == nil {
+		return
+	}
+
+	text := fmt.Sprintf("🚨 %s %s: %d回連続で全記事の処理に失敗しています（失敗率 %.0f%%）",
+		repository.SlackMention(owner), stats.Feed, stats.FailedRuns, stats.FailureRate*100)
+	if _, err := e.alerts.Start(ctx, text); err != nil {
+		// アラート送信失敗はログのみ（ALERT ログは出ている）
+		logger.Printf("Warning: Failed to alert feed owner feed=%s owner=%s: %v", stats.Feed, owner, err)
+	}
+}
@@ -0,0 +1,42 @@
+package article
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestFeedEscalation_AlertsOwnerOnce(t *testing.T) {
+	alerts := &mocks.MockOpsThread{}
+	statsRepo := NewFeedEscalation(&mocks.MockFeedStatsRepo{}, map[string]string{"hatena": "S0123ABCD"}, alerts, 2)
+	ctx := context.Background()
+
+	failedRun := func(feed string) {
+		stats, _ := checkFeedAnomaly(ctx, statsRepo, feed, 30)
+		recordFeedRun(ctx, statsRepo, stats, repository.FeedRun{Items: 30, Attempted: 2, Failed: 2})
+	}
+	failedRun("hatena")
+	if len(alerts.Parents) != 0 {
+		t.Fatalf("Expected no alert after one failed run, got %v", alerts.Parents)
+	}
+	failedRun("hatena")
+	failedRun("hatena") // The streak goes on without another alert
+	if len(alerts.Parents) != 1 || !strings.Contains(alerts.Parents[0], "<!subteam^S0123ABCD>") || !strings.Contains(alerts.Parents[0], "hatena") {
+		t.Fatalf("Expected one alert mentioning the owner, got %v", alerts.Parents)
+	}
+
+	stats, _ := statsRepo.Load(ctx, "hatena")
+	if stats.FailedRuns != 3 || !statsRepo.Escalated(stats) || statsRepo.Owner("hatena") != "S0123ABCD" {
+		t.Errorf("Expected hatena escalated, got %+v", stats)
+	}
+
+	// Unowned feeds are only logged
+	failedRun("lobsters")
+	failedRun("lobsters")
+	if len(alerts.Parents) != 1 {
+		t.Errorf("Expected no alert for an unowned feed, got %v", alerts.Parents)
+	}
+}
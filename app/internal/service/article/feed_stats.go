@@ -0,0 +1,95 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// ErrFeedAnomaly is returned when a run is skipped because the feed looks corrupted
+var ErrFeedAnomaly = errors.New("feed anomaly detected")
+
+const (
+	// feedStatsWarmupRuns is the number of runs before anomaly detection kicks in
+	feedStatsWarmupRuns = 5
+	// anomalyItemsFactor flags runs whose item count is this many times the rolling average
+	anomalyItemsFactor = 5
+	// anomalyMinItems keeps small feeds (e.g. 2 → 10 items) from being flagged
+	anomalyMinItems = 20
+	// anomalyAcceptAfter accepts a persistent change as the new normal after this many skipped runs
+	anomalyAcceptAfter = 3
+)
+
+// checkFeedAnomaly loads the feed's statistics and rejects the run with ErrFeedAnomaly when the item count
+// is far above normal (a feed format change or a loop), so a corrupted feed is alerted on instead of being
+// summarized. Statistics are best effort: a nil repository or a load failure returns nil stats.
+func checkFeedAnomaly(ctx context.Context, statsRepo repository.FeedStatsRepository, feed string, items int) (*repository.FeedStats, error) {
+	if statsRepo == nil {
+		return nil, nil
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	stats, err := statsRepo.Load(ctx, feed)
+	if err != nil {
+		logger.Printf("Warning: Failed to load feed stats feed=%s: %v", feed, err)
+		return nil, nil
+	}
+
+	if stats.Runs < feedStatsWarmupRuns || items < anomalyMinItems || float64(items) <= anomalyItemsFactor*stats.AvgItems {
+		return stats, nil
+	}
+
+	stats.Anomalies++
+	stats.LastAnomaly = fmt.Sprintf("%s items=%d avg_items=%.1f", repository.Now(ctx).UTC().Format(time.RFC3339), items, stats.AvgItems)
+	if stats.Anomalies > anomalyAcceptAfter {
+		// 何度も続く場合はフィードの仕様変更とみなして受け入れる
+		logger.Printf("ALERT: Feed item count anomaly persisted, accepting as new baseline feed=%s items=%d avg_items=%.1f runs_skipped=%d",
+			feed, items, stats.AvgItems, stats.Anomalies-1)
+		return stats, nil
+	}
+
+	logger.Printf("ALERT: Feed item count anomaly, skipping run feed=%s items=%d avg_items=%.1f consecutive=%d",
+		feed, items, stats.AvgItems, stats.Anomalies)
+	if err := statsRepo.Save(ctx, stats); err != nil {
+		logger.Printf("Warning: Failed to save feed stats feed=%s: %v", feed, err)
+	}
+	return nil, fmt.Errorf("%w: %s has %d items (average %.1f)", ErrFeedAnomaly, feed, items, stats.AvgItems)
+}
+
+// recordFeedRun folds the run into the feed's statistics and flags runs whose failure rate or
+// summary length deviate from normal (logged only; the run has already happened). Persistent
+// failures are escalated to the feed's owner when statsRepo is a FeedEscalation.
+func recordFeedRun(ctx context.Context, statsRepo repository.FeedStatsRepository, stats *repository.FeedStats, run repository.FeedRun) {
+	if statsRepo == nil || stats == nil {
+		return
+	}
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if stats.Runs >= feedStatsWarmupRuns {
+		if run.Attempted >= 3 && float64(run.Failed)/float64(run.Attempted) > 0.5 && stats.FailureRate < 0.1 {
+			logger.Printf("ALERT: Feed failure rate anomaly feed=%s failed=%d attempted=%d failure_rate=%.2f",
+				stats.Feed, run.Failed, run.Attempted, stats.FailureRate)
+		}
+		if run.Summaries > 0 && stats.AvgSummaryChars > 0 && float64(run.SummaryChars)/float64(run.Summaries) < stats.AvgSummaryChars/3 {
+			logger.Printf("ALERT: Feed summary length anomaly feed=%s avg_chars=%d usual_chars=%.0f",
+				stats.Feed, run.SummaryChars/run.Summaries, stats.AvgSummaryChars)
+		}
+	}
+
+	stats.Record(run, repository.Now(ctx))
+	if escalation, ok := statsRepo.(*FeedEscalation); ok {
+		escalation.escalate(ctx, stats)
+	}
+	if err := statsRepo.Save(ctx, stats); err != nil {
+		logger.Printf("Warning: Failed to save feed stats feed=%s: %v", stats.Feed, err)
+		return
+	}
+	logger.Printf("Feed stats updated feed=%s runs=%d avg_items=%.1f avg_summary_chars=%.0f failure_rate=%.2f",
+		stats.Feed, stats.Runs, stats.AvgItems, stats.AvgSummaryChars, stats.FailureRate)
+}
@@ -0,0 +1,61 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestCheckFeedAnomaly(t *testing.T) {
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"lobsters": {Feed: "lobsters", Runs: 10, AvgItems: 25},
+	}}
+	ctx := context.Background()
+
+	if _, err := checkFeedAnomaly(ctx, statsRepo, "lobsters", 30); err != nil {
+		t.Fatalf("Normal run rejected: %v", err)
+	}
+
+	// A 20x spike is skipped until it has persisted past anomalyAcceptAfter runs
+	for i := 1; i <= anomalyAcceptAfter; i++ {
+		if _, err := checkFeedAnomaly(ctx, statsRepo, "lobsters", 500); !errors.Is(err, ErrFeedAnomaly) {
+			t.Fatalf("Run %d: expected ErrFeedAnomaly, got %v", i, err)
+		}
+	}
+	stats, err := checkFeedAnomaly(ctx, statsRepo, "lobsters", 500)
+	if err != nil || stats == nil {
+		t.Fatalf("Expected a persistent change to be accepted, got %v", err)
+	}
+}
+
+func TestCheckFeedAnomaly_WarmupAndSmallFeeds(t *testing.T) {
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"new":   {Feed: "new", Runs: 2, AvgItems: 10},
+		"small": {Feed: "small", Runs: 10, AvgItems: 2},
+	}}
+
+	if _, err := checkFeedAnomaly(context.Background(), statsRepo, "new", 500); err != nil {
+		t.Errorf("Expected no detection during warm-up, got %v", err)
+	}
+	if _, err := checkFeedAnomaly(context.Background(), statsRepo, "small", 15); err != nil {
+		t.Errorf("Expected small feeds below anomalyMinItems to pass, got %v", err)
+	}
+	if stats, err := checkFeedAnomaly(context.Background(), nil, "small", 500); stats != nil || err != nil {
+		t.Errorf("Expected a nil repository to disable stats, got %+v, %v", stats, err)
+	}
+}
+
+func TestRecordFeedRun(t *testing.T) {
+	statsRepo := &mocks.MockFeedStatsRepo{}
+	stats, _ := checkFeedAnomaly(context.Background(), statsRepo, "hatena", 30)
+
+	recordFeedRun(context.Background(), statsRepo, stats, repository.FeedRun{Items: 30, Attempted: 2, Summaries: 2, SummaryChars: 600})
+
+	saved := statsRepo.Stats["hatena"]
+	if saved == nil || saved.Runs != 1 || saved.AvgItems != 30 || saved.AvgSummaryChars != 300 {
+		t.Errorf("Unexpected saved stats %+v", saved)
+	}
+}
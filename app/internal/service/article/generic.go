@@ -0,0 +1,272 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+// GenericFeedProcessor processes one feed described by a GenericFeedSpec (summaries only, no comments)
+type GenericFeedProcessor struct {
+	spec          rss.GenericFeedSpec
+	feedRepo      *rss.GenericFeedRepository
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+	backlogFeed   string // Backlog key that routes retries back to a processor (the spec name unless shared)
+}
+
+func NewGenericFeedProcessor(
+	spec rss.GenericFeedSpec,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *GenericFeedProcessor {
+	return &GenericFeedProcessor{
+		spec:          spec,
+		feedRepo:      rss.NewGenericFeedRepository(rssRepo, spec),
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+		backlogFeed:   spec.Name,
+	}
+}
+
+// Name returns the feed name (the Source of its articles)
+func (p *GenericFeedProcessor) Name() string {
+	return p.spec.Name
+}
+
+// Due reports whether now is inside the feed's active window and its schedule has elapsed since
+// its last recorded run. Feeds without a schedule or without run statistics are due at any
+// time of their window. now is expected in the feed time zone.
+func (p *GenericFeedProcessor) Due(ctx context.Context, now time.Time) bool {
+	return p.spec.Window().Contains(now) && p.scheduleElapsed(ctx, now)
+}
+
+// scheduleElapsed reports whether the feed's schedule has elapsed since its last recorded run
+func (p *GenericFeedProcessor) scheduleElapsed(ctx context.Context, now time.Time) bool {
+	if p.spec.Interval() <= 0 || p.statsRepo == nil {
+		return true
+	}
+	stats, err := p.statsRepo.Load(ctx, p.spec.Name)
+	if err != nil {
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+		logger.Printf("Warning: Failed to load feed stats feed=%s: %v", p.spec.Name, err)
+		return true
+	}
+	return stats.LastRun.IsZero() || now.Sub(stats.LastRun) >= p.spec.Interval()
+}
+
+// Process runs the feed regardless of its schedule
+func (p *GenericFeedProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=%s", p.spec.Name)
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=%s duration_ms=%d", p.spec.Name, duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	return p.processFeed(ctx)
+}
+
+// processFeed runs the feed through the usual fetch → filter → limit → summarize pipeline
+func (p *GenericFeedProcessor) processFeed(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=%s url=%s", p.spec.Name, p.spec.URL)
+	articles, err := p.feedRepo.FetchArticles(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, p.spec.Name, len(articles))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed articles
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, p.spec.Name, articles)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
+
+	logger.Printf("Selected unprocessed articles: %d from %s", len(limitedArticles), p.spec.Name)
+
+	// Process each article
+	run := startOpsRun(ctx, p.notifier, p.spec.Name, len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
+		if err := p.processGenericArticle(ctx, article); err != nil {
+			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, p.backlogFeed, article, err)
+			return fmt.Errorf("processing article %s: %w", article.Title, err)
+		}
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=%s processed_count=%d", p.spec.Name, len(limitedArticles))
+	return nil
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *GenericFeedProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processGenericArticle(ctx, article)
+}
+
+// processGenericArticle summarizes and notifies one article (generic feeds have no comments)
+func (p *GenericFeedProcessor) processGenericArticle(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Article processing started title=%s url=%s source=%s",
+		article.Title, article.Link, article.Source)
+
+	// 2. 記事要約
+	summaryStart := time.Now()
+	summary, err := summarizeArticle(ctx, p.geminiRepo, article)
+	if err != nil {
+		logger.Printf("Error summarizing article %s: %v", article.Title, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
+
+	// 3. 通知送信
+	slackStart := time.Now()
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+	}); err != nil {
+		logger.Printf("Error sending notification for %s: %v", article.Title, err)
+		return fmt.Errorf("sending notification: %w", err)
+	}
+	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
+
+	// 4. インデックス更新
+	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	totalDuration := time.Since(start)
+	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
+		article.Title, totalDuration.Milliseconds(), summaryDuration.Milliseconds(), slackDuration.Milliseconds(), processDuration.Milliseconds())
+
+	return nil
+}
+
+// FeedRegistry holds the generic feeds configured at startup
+type FeedRegistry struct {
+	feeds    map[string]*GenericFeedProcessor
+	location *time.Location    // Time zone of the feeds' active windows
+	fastLane *limiter.FastLane // Runs feeds outside their active windows for breaking news (nil disables)
+}
+
+func NewFeedRegistry(location *time.Location, fastLane *limiter.FastLane, feeds ...*GenericFeedProcessor) *FeedRegistry {
+	registry := &FeedRegistry{feeds: make(map[string]*GenericFeedProcessor, len(feeds)), location: location, fastLane: fastLane}
+	for _, feed := range feeds {
+		registry.feeds[feed.Name()] = feed
+	}
+	return registry
+}
+
+// Get returns the named feed's processor
+func (r *FeedRegistry) Get(name string) (*GenericFeedProcessor, bool) {
+	feed, ok := r.feeds[name]
+	return feed, ok
+}
+
+// Names returns the registered feed names in order
+func (r *FeedRegistry) Names() []string {
+	names := make([]string, 0, len(r.feeds))
+	for name := range r.feeds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProcessDue runs every feed that is due (see Due) and returns the names it ran; outside its active
+// window a feed whose schedule has elapsed may still run for breaking news (see limiter.FastLane).
+// A failing feed does not stop the others; the failures are returned together at the end.
+// Each feed run is added to the context's RunReport, if any.
+func (r *FeedRegistry) ProcessDue(ctx context.Context) ([]string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	now := repository.Now(ctx).In(r.location)
+
+	var ran []string
+	var errs []error
+	for _, name := range r.Names() {
+		feed := r.feeds[name]
+		feedCtx, inWindow := ctx, feed.spec.Window().Contains(now)
+		if !inWindow {
+			feedCtx, inWindow = r.fastLane.OutsideWindow(ctx, name)
+		}
+		if !inWindow || !feed.scheduleElapsed(ctx, now) {
+			logger.Printf("Feed not due, skipping feed=%s schedule=%s active_window=%s", name, feed.spec.Schedule, feed.spec.Window())
+			continue
+		}
+		ran = append(ran, name)
+		if err := RunFeed(feedCtx, name, feed.Process); err != nil {
+			logger.Printf("Error processing feed %s: %v", name, err)
+			errs = append(errs, fmt.Errorf("processing feed %s: %w", name, err))
+		}
+	}
+	return ran, errors.Join(errs...)
+}
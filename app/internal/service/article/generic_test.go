@@ -0,0 +1,160 @@
+package article
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+)
+
+func newTestGenericProcessor(t *testing.T, raw string, fetcher feedFetcher, slackRepo *mocks.MockSlackRepo, statsRepo *mocks.MockFeedStatsRepo) *GenericFeedProcessor {
+	t.Helper()
+	specs, err := rss.ParseGenericFeedSpecs(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return NewGenericFeedProcessor(
+		specs[0],
+		fetcher,
+		&mocks.MockGeminiRepo{},
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		statsRepo,
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+}
+
+func TestGenericFeedProcessor_Process(t *testing.T) {
+	fetcher := feedFetcher{
+		"https://blog.example.com/feed.xml": `<rss version="2.0"><channel>
+<item><title>Go post</title><link>https://blog.example.com/go</link><category>go</category></item>
+<item><title>Ask post</title><link>https://blog.example.com/ask</link><category>ask</category></item>
+</channel></rss>`,
+	}
+	slackRepo := &mocks.MockSlackRepo{}
+	statsRepo := &mocks.MockFeedStatsRepo{}
+	processor := newTestGenericProcessor(t, `[{"name":"blog","url":"https://blog.example.com/feed.xml","exclude_categories":["ask"]}]`,
+		fetcher, slackRepo, statsRepo)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if n := slackRepo.SentNotifications[0]; n.URL != "https://blog.example.com/go" || n.Source != "blog" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+	if stats := statsRepo.Stats["blog"]; stats == nil || stats.LastRun.IsZero() {
+		t.Error("Expected the run to be recorded in feed stats")
+	}
+}
+
+func TestGenericFeedProcessor_Due(t *testing.T) {
+	now := time.Now()
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"recent": {Feed: "recent", LastRun: now.Add(-time.Hour)},
+		"stale":  {Feed: "stale", LastRun: now.Add(-7 * time.Hour)},
+	}}
+
+	tests := []struct {
+		raw string
+		due bool
+	}{
+		{`[{"name":"recent","url":"https://example.com/feed","schedule":"6h"}]`, false},
+		{`[{"name":"stale","url":"https://example.com/feed","schedule":"6h"}]`, true},
+		{`[{"name":"never-run","url":"https://example.com/feed","schedule":"6h"}]`, true},
+		{`[{"name":"recent","url":"https://example.com/feed"}]`, true}, // No schedule: every call
+	}
+
+	for _, tt := range tests {
+		processor := newTestGenericProcessor(t, tt.raw, feedFetcher{}, &mocks.MockSlackRepo{}, statsRepo)
+		if due := processor.Due(context.Background(), now); due != tt.due {
+			t.Errorf("%s: expected due=%v, got %v", tt.raw, tt.due, due)
+		}
+	}
+}
+
+func TestGenericFeedProcessor_Due_ActiveWindow(t *testing.T) {
+	processor := newTestGenericProcessor(t, `[{"name":"daytime","url":"https://example.com/feed","active_window":"07:00-23:00"}]`,
+		feedFetcher{}, &mocks.MockSlackRepo{}, &mocks.MockFeedStatsRepo{})
+
+	jst := time.FixedZone("JST", 9*60*60)
+	if !processor.Due(context.Background(), time.Date(2024, 1, 1, 12, 0, 0, 0, jst)) {
+		t.Error("Expected the feed to be due at noon")
+	}
+	// 16:00 UTC is 01:00 JST: outside the window
+	if processor.Due(context.Background(), time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC).In(jst)) {
+		t.Error("Expected the feed not to be due overnight")
+	}
+}
+
+func TestFeedRegistry_ProcessDue(t *testing.T) {
+	fetcher := feedFetcher{
+		"https://a.example.com/feed.xml": `<rss version="2.0"><channel>
+<item><title>A post</title><link>https://a.example.com/post</link></item>
+</channel></rss>`,
+	}
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"b": {Feed: "b", LastRun: time.Now()},
+	}}
+	slackRepo := &mocks.MockSlackRepo{}
+	registry := NewFeedRegistry(time.UTC, nil,
+		newTestGenericProcessor(t, `[{"name":"a","url":"https://a.example.com/feed.xml","schedule":"1h"}]`, fetcher, slackRepo, statsRepo),
+		newTestGenericProcessor(t, `[{"name":"b","url":"https://b.example.com/feed.xml","schedule":"1h"}]`, fetcher, slackRepo, statsRepo),
+	)
+
+	ran, err := registry.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// b ran just now, so only a is due
+	if len(ran) != 1 || ran[0] != "a" {
+		t.Errorf("Expected only feed a to run, got %v", ran)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Errorf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if _, ok := registry.Get("b"); !ok {
+		t.Error("Expected feed b to be registered")
+	}
+	if _, ok := registry.Get("c"); ok {
+		t.Error("Expected feed c to be unknown")
+	}
+}
+
+func TestFeedRegistry_ProcessDue_AsOf(t *testing.T) {
+	lastRun := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"a": {Feed: "a", LastRun: lastRun},
+	}}
+	registry := NewFeedRegistry(time.UTC, nil,
+		newTestGenericProcessor(t, `[{"name":"a","url":"https://a.example.com/feed.xml","schedule":"1h","active_window":"07:00-23:00"}]`, feedFetcher{}, &mocks.MockSlackRepo{}, statsRepo),
+	)
+
+	tests := []struct {
+		name string
+		asOf time.Time
+		ran  bool
+	}{
+		{"schedule not elapsed", lastRun.Add(30 * time.Minute), false},
+		{"schedule elapsed", lastRun.Add(2 * time.Hour), true},
+		{"outside the active window", time.Date(2024, 6, 4, 23, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := repository.WithClock(context.Background(), repository.FixedClock(tt.asOf))
+			ran, _ := registry.ProcessDue(ctx)
+			if (len(ran) == 1) != tt.ran {
+				t.Errorf("Expected ran=%v as of %s, got %v", tt.ran, tt.asOf, ran)
+			}
+		})
+	}
+}
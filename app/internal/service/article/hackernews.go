@@ -0,0 +1,293 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+type HackerNewsProcessor struct {
+	hackerNewsRepo    rss.FeedRepository
+	hackerNewsAPIRepo *rss.HackerNewsRepository
+	geminiRepo        repository.GeminiRepository
+	notifier          repository.Notifier
+	processedRepo     repository.ProcessedArticleRepository
+	backlogRepo       repository.BacklogRepository
+	statsRepo         repository.FeedStatsRepository
+	limiter           limiter.ArticleLimiter
+	concurrency       *limiter.ConcurrencyController
+}
+
+func NewHackerNewsProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *HackerNewsProcessor {
+	hackerNewsAPIRepo := rss.NewHackerNewsRepository(rssRepo)
+	return &HackerNewsProcessor{
+		hackerNewsRepo:    hackerNewsAPIRepo,
+		hackerNewsAPIRepo: hackerNewsAPIRepo,
+		geminiRepo:        geminiRepo,
+		notifier:          notifier,
+		processedRepo:     processedRepo,
+		backlogRepo:       backlogRepo,
+		statsRepo:         statsRepo,
+		limiter:           limiter,
+		concurrency:       concurrency,
+	}
+}
+
+func (p *HackerNewsProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=hackernews")
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=hackernews duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=hackernews")
+	articles, err := p.hackerNewsRepo.FetchArticles(ctx)
+	if err != nil {
+		logger.Printf("Error processing feed hackernews: %v", err)
+		return fmt.Errorf("processing feed hackernews: %w", err)
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "hackernews", len(articles))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed articles
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, "hackernews", articles)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
+
+	logger.Printf("Selected unprocessed articles: %d from Hacker News", len(limitedArticles))
+
+	// Process each article
+	run := startOpsRun(ctx, p.notifier, "hackernews", len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
+		if err := p.processHackerNewsArticle(ctx, article); err != nil {
+			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, "hackernews", article, err)
+			return fmt.Errorf("processing article %s: %w", article.Title, err)
+		}
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=hackernews processed_count=%d", len(limitedArticles))
+	return nil
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *HackerNewsProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processHackerNewsArticle(ctx, article)
+}
+
+// RetryComments summarizes the comments of an already posted article (used by the backlog drain)
+func (p *HackerNewsProcessor) RetryComments(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+
+	var commentSummary *string
+	var commentDuration time.Duration
+	var commentChars int
+	if err := p.fetchAndProcessHackerNewsComments(ctx, article, &commentSummary, &commentDuration, &commentChars); err != nil {
+		return err
+	}
+	if commentSummary == nil {
+		return nil
+	}
+	return p.notifier.Send(ctx, repository.Notification{
+		Title:        article.Title + " - コメント",
+		Source:       article.Source,
+		URL:          article.Link,
+		Summary:      *commentSummary,
+		ContentChars: commentChars,
+		Comment:      true,
+	})
+}
+
+// processHackerNewsArticle handles Hacker News stories with their discussion comments
+func (p *HackerNewsProcessor) processHackerNewsArticle(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Article processing started title=%s url=%s comment_url=%s source=%s",
+		article.Title, article.Link, article.CommentURL, article.Source)
+
+	// 2. 記事要約
+	summaryStart := time.Now()
+	summary, combined, err := summarizeArticleAndComments(ctx, p.geminiRepo, article, p.fetchHackerNewsComments)
+	if err != nil {
+		logger.Printf("Error summarizing article %s: %v", article.Title, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
+
+	// 3. Hacker Newsコメント取得と要約
+	// Redditと同じくディスカッション(CommentURL)のコメントを要約する。HN APIはIP制限がないため有効
+	// 記事通知とコメント通知を別々に送信する
+	var commentSummary *string
+	var commentDuration time.Duration
+	var commentChars int
+	var commentErr error
+	if combined != nil {
+		// COMMENT_SUMMARY_MODE=combined: コメントは記事と同じ呼び出しで要約済み
+		commentSummary, commentDuration, commentChars, commentErr = combined.summary, combined.duration, combined.chars, combined.err
+	} else {
+		commentErr = p.fetchAndProcessHackerNewsComments(ctx, article, &commentSummary, &commentDuration, &commentChars)
+	}
+	if commentErr != nil {
+		logger.Printf("Warning: Failed to fetch Hacker News comments for %s: %v", article.Title, commentErr)
+		// Hacker Newsコメント取得失敗でも記事は投稿し、コメント要約は後でバックログから再試行
+	}
+	recordPhase(ctx, "comments", commentDuration)
+
+	// 5. 通知送信
+	slackStart := time.Now()
+	// 記事通知
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+		// The comment summary follows once the backlog drain retries it
+		CommentsDelayed: commentErr != nil && p.backlogRepo != nil,
+	}); err != nil {
+		logger.Printf("Error sending article notification for %s: %v", article.Title, err)
+		return fmt.Errorf("sending article notification: %w", err)
+	}
+
+	// コメント通知（コメントがある場合）
+	if commentSummary != nil {
+		if err := p.notifier.Send(ctx, repository.Notification{
+			Title:        article.Title + " - コメント",
+			Source:       article.Source,
+			URL:          article.Link,
+			Summary:      *commentSummary,
+			ContentChars: commentChars, // コメントの元文字数を表示
+			Comment:      true,
+			Difficulty:   summary.Difficulty, // Follows the article through channel level filters
+		}); err != nil {
+			logger.Printf("Error sending comment notification for %s: %v", article.Title, err)
+			return fmt.Errorf("sending comment notification: %w", err)
+		}
+	}
+	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
+
+	// 6. インデックス更新
+	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	if commentErr != nil {
+		recordCommentTask(ctx, p.backlogRepo, "hackernews", article, commentErr)
+	}
+
+	totalDuration := time.Since(start)
+	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
+		article.Title, totalDuration.Milliseconds(), summaryDuration.Milliseconds(), slackDuration.Milliseconds(), processDuration.Milliseconds())
+
+	return nil
+}
+
+// fetchHackerNewsComments fetches the discussion of the story ("" when there is none)
+func (p *HackerNewsProcessor) fetchHackerNewsComments(ctx context.Context, article repository.Item) (string, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if article.CommentURL == "" {
+		return "", nil
+	}
+	logger.Printf("Comments fetching started url=%s", article.CommentURL)
+
+	// Fetch Hacker News comments (Algolia API)
+	comments, err := p.hackerNewsAPIRepo.FetchComments(ctx, article.CommentURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Hacker News comments: %w", err)
+	}
+	if comments.Text == "" {
+		logger.Printf("No Hacker News comments found for %s", article.Title)
+	}
+	return comments.Text, nil
+}
+
+// fetchAndProcessHackerNewsComments handles Hacker News comment retrieval and summarization
+func (p *HackerNewsProcessor) fetchAndProcessHackerNewsComments(ctx context.Context, article repository.Item, commentSummary **string, commentDuration *time.Duration, commentChars *int) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	commentStart := time.Now()
+	text, err := p.fetchHackerNewsComments(ctx, article)
+	if err != nil {
+		return err
+	}
+
+	if text == "" {
+		*commentDuration = time.Since(commentStart)
+		*commentChars = 0
+		return nil
+	}
+
+	logger.Printf("Comments summarization started text_length=%d", len(text))
+
+	// Summarize comments
+	summaryResponse, err := p.geminiRepo.SummarizeComments(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to summarize Hacker News comments: %w", err)
+	}
+
+	*commentSummary = &summaryResponse.Summary
+	*commentDuration = time.Since(commentStart)
+	*commentChars = len(text)
+
+	logger.Printf("Comments summarization completed summary_length=%d duration_ms=%d",
+		len(summaryResponse.Summary), commentDuration.Milliseconds())
+
+	return nil
+}
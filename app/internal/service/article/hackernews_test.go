@@ -0,0 +1,99 @@
+package article
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestHackerNewsProcessor_Process_NoArticles(t *testing.T) {
+	processor := NewHackerNewsProcessor(
+		&mocks.MockRSSRepo{}, // not a JSON story list
+		&mocks.MockGeminiRepo{},
+		&mocks.MockSlackRepo{},
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err == nil {
+		t.Error("Expected error for a non-JSON story list")
+	}
+}
+
+func TestHackerNewsProcessor_Process_SendsArticleAndComments(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	processor := NewHackerNewsProcessor(
+		&mocks.MockHackerNewsRepo{},
+		&mocks.MockGeminiRepo{},
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The low-score story is dropped; the remaining one gets an article and a comment notification
+	if len(slackRepo.SentNotifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(slackRepo.SentNotifications))
+	}
+	if slackRepo.SentNotifications[0].URL != "https://example.com/article" {
+		t.Errorf("Expected story URL, got '%s'", slackRepo.SentNotifications[0].URL)
+	}
+	if !slackRepo.SentNotifications[1].Comment || !strings.HasSuffix(slackRepo.SentNotifications[1].Title, " - コメント") {
+		t.Errorf("Expected comment notification, got %+v", slackRepo.SentNotifications[1])
+	}
+}
+
+func TestHackerNewsProcessor_Process_CommentFailureContinues(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	backlogRepo := &mocks.MockBacklogRepo{}
+	hackerNewsRepo := &mocks.MockHackerNewsRepo{ShouldFailComments: true}
+	processor := NewHackerNewsProcessor(
+		hackerNewsRepo,
+		&mocks.MockGeminiRepo{},
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		backlogRepo,
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected only the article notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if !slackRepo.SentNotifications[0].CommentsDelayed {
+		t.Error("Expected the article notification to note the delayed discussion summary")
+	}
+
+	// The comment summary is kept as a separate task for the backlog drain
+	if len(backlogRepo.Entries) != 1 {
+		t.Fatalf("Expected 1 comment task, got %d", len(backlogRepo.Entries))
+	}
+	task := backlogRepo.Entries[0]
+	if feed, ok := task.CommentTask(); !ok || feed != "hackernews" {
+		t.Errorf("Expected a hackernews comment task, got feed=%s", task.Feed)
+	}
+
+	// Once the comment API recovers, the retry posts only the comment summary
+	hackerNewsRepo.ShouldFailComments = false
+	if err := processor.RetryComments(context.Background(), task.Item); err != nil {
+		t.Fatalf("Unexpected retry error: %v", err)
+	}
+	if len(slackRepo.SentNotifications) != 2 || !slackRepo.SentNotifications[1].Comment {
+		t.Errorf("Expected a comment notification after the retry, got %+v", slackRepo.SentNotifications)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
@@ -18,26 +19,35 @@ type HatenaProcessor struct {
 	hatenaRepo    rss.FeedRepository
 	hatenaRSSRepo *rss.HatenaRSSRepository
 	geminiRepo    repository.GeminiRepository
-	slackRepo     repository.SlackRepository
+	notifier      repository.Notifier
 	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
 	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
 }
 
 func NewHatenaProcessor(
 	rssRepo repository.RSSRepository,
 	geminiRepo repository.GeminiRepository,
-	slackRepo repository.SlackRepository,
+	notifier repository.Notifier,
 	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
 	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
 ) *HatenaProcessor {
 	hatenaRSSRepo := rss.NewHatenaRSSRepository(rssRepo)
 	return &HatenaProcessor{
 		hatenaRepo:    hatenaRSSRepo,
 		hatenaRSSRepo: hatenaRSSRepo,
 		geminiRepo:    geminiRepo,
-		slackRepo:     slackRepo,
+		notifier:      notifier,
 		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
 		limiter:       limiter,
+		concurrency:   concurrency,
 	}
 }
 
@@ -50,6 +60,8 @@ func (p *HatenaProcessor) Process(ctx context.Context) error {
 		duration := time.Since(start)
 		logger.Printf("Process request completed feed=hatena duration_ms=%d", duration.Milliseconds())
 	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
 
 	// 1. データ取得
 	logger.Printf("Feed processing started feed=hatena")
@@ -59,30 +71,75 @@ func (p *HatenaProcessor) Process(ctx context.Context) error {
 		return fmt.Errorf("processing feed hatena: %w", err)
 	}
 
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "hatena", len(articles))
+	if err != nil {
+		return err
+	}
+
 	// Filter unprocessed articles
-	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, articles)
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, "hatena", articles)
 	if err != nil {
 		return fmt.Errorf("filtering unprocessed articles: %w", err)
 	}
 
 	// Apply article limiting
-	limitedArticles := p.limiter.Limit(unprocessedArticles)
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
 
 	logger.Printf("Selected unprocessed articles: %d from はてブ テクノロジー", len(limitedArticles))
 
 	// Process each article
-	for i, article := range limitedArticles {
+	run := startOpsRun(ctx, p.notifier, "hatena", len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
 		if err := p.processHatenaArticle(ctx, article); err != nil {
 			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, "hatena", article, err)
 			return fmt.Errorf("processing article %s: %w", article.Title, err)
 		}
-		logger.Printf("Article processed %d/%d title=%s", i+1, len(limitedArticles), article.Title)
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
 	}
 
 	logger.Printf("Feed processing completed feed=hatena processed_count=%d", len(limitedArticles))
 	return nil
 }
 
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *HatenaProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processHatenaArticle(ctx, article)
+}
+
+// RetryComments summarizes the comments of an already posted article (used by the backlog drain)
+func (p *HatenaProcessor) RetryComments(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+
+	var commentSummary *string
+	var commentDuration time.Duration
+	var commentChars int
+	if err := p.fetchAndProcessHatenaComments(ctx, article, &commentSummary, &commentDuration, &commentChars); err != nil {
+		return err
+	}
+	if commentSummary == nil {
+		return nil
+	}
+	return p.notifier.Send(ctx, repository.Notification{
+		Title:        article.Title + " - コメント",
+		Source:       article.Source,
+		URL:          article.Link,
+		Summary:      *commentSummary,
+		ContentChars: commentChars,
+		Comment:      true,
+	})
+}
+
 // processHatenaArticle handles articles with Hatena bookmark comments
 func (p *HatenaProcessor) processHatenaArticle(ctx context.Context, article repository.Item) error {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
@@ -93,12 +150,13 @@ func (p *HatenaProcessor) processHatenaArticle(ctx context.Context, article repo
 
 	// 2. 記事要約
 	summaryStart := time.Now()
-	summary, err := p.geminiRepo.SummarizeURL(ctx, article.Link)
+	summary, combined, err := summarizeArticleAndComments(ctx, p.geminiRepo, article, p.fetchHatenaComments)
 	if err != nil {
 		logger.Printf("Error summarizing article %s: %v", article.Title, err)
 		return fmt.Errorf("summarizing article: %w", err)
 	}
 	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
 
 	// 3. Hatenaコメント取得と要約
 	// 注意: Redditとは異なり、HatenaとLobstersはコメント機能が有効
@@ -106,20 +164,35 @@ func (p *HatenaProcessor) processHatenaArticle(ctx context.Context, article repo
 	var commentSummary *string
 	var commentDuration time.Duration
 	var commentChars int
-	if err := p.fetchAndProcessHatenaComments(ctx, article, &commentSummary, &commentDuration, &commentChars); err != nil {
-		logger.Printf("Warning: Failed to fetch Hatena comments for %s: %v", article.Title, err)
-		// Hatenaコメント取得失敗でも処理は続行
+	var commentErr error
+	if combined != nil {
+		// COMMENT_SUMMARY_MODE=combined: コメントは記事と同じ呼び出しで要約済み
+		commentSummary, commentDuration, commentChars, commentErr = combined.summary, combined.duration, combined.chars, combined.err
+	} else {
+		commentErr = p.fetchAndProcessHatenaComments(ctx, article, &commentSummary, &commentDuration, &commentChars)
 	}
+	if commentErr != nil {
+		logger.Printf("Warning: Failed to fetch Hatena comments for %s: %v", article.Title, commentErr)
+		// Hatenaコメント取得失敗でも記事は投稿し、コメント要約は後でバックログから再試行
+	}
+	recordPhase(ctx, "comments", commentDuration)
 
 	// 5. 通知送信
 	slackStart := time.Now()
 	// 記事通知
-	if err := p.slackRepo.Send(ctx, repository.Notification{
-		Title:        article.Title,
-		Source:       article.Source,
-		URL:          article.Link,
-		Summary:      summary.Summary,
-		ContentChars: summary.ContentChars,
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+		// The comment summary follows once the backlog drain retries it
+		CommentsDelayed: commentErr != nil && p.backlogRepo != nil,
 	}); err != nil {
 		logger.Printf("Error sending article notification for %s: %v", article.Title, err)
 		return fmt.Errorf("sending article notification: %w", err)
@@ -127,26 +200,36 @@ func (p *HatenaProcessor) processHatenaArticle(ctx context.Context, article repo
 
 	// コメント通知（コメントがある場合）
 	if commentSummary != nil {
-		if err := p.slackRepo.Send(ctx, repository.Notification{
+		if err := p.notifier.Send(ctx, repository.Notification{
 			Title:        article.Title + " - コメント",
 			Source:       article.Source,
 			URL:          article.Link,
 			Summary:      *commentSummary,
 			ContentChars: commentChars, // コメントの元文字数を表示
+			Comment:      true,
+			Difficulty:   summary.Difficulty, // Follows the article through channel level filters
 		}); err != nil {
 			logger.Printf("Error sending comment notification for %s: %v", article.Title, err)
 			return fmt.Errorf("sending comment notification: %w", err)
 		}
 	}
 	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
 
 	// 6. インデックス更新
 	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
 	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
 		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
 		return fmt.Errorf("marking as processed: %w", err)
 	}
 	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	if commentErr != nil {
+		recordCommentTask(ctx, p.backlogRepo, "hatena", article, commentErr)
+	}
 
 	totalDuration := time.Since(start)
 	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
@@ -155,37 +238,50 @@ func (p *HatenaProcessor) processHatenaArticle(ctx context.Context, article repo
 	return nil
 }
 
-// fetchAndProcessHatenaComments handles Hatena bookmark comment retrieval and summarization
-func (p *HatenaProcessor) fetchAndProcessHatenaComments(ctx context.Context, article repository.Item, commentSummary **string, commentDuration *time.Duration, commentChars *int) error {
+// fetchHatenaComments fetches the Hatena bookmark comments of the article ("" when there are none)
+func (p *HatenaProcessor) fetchHatenaComments(ctx context.Context, article repository.Item) (string, error) {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 
 	logger.Printf("Comments fetching started url=%s", article.Link)
-	commentStart := time.Now()
 
 	// Fetch Hatena bookmark comments
 	comments, err := p.hatenaRSSRepo.FetchComments(ctx, article.Link)
 	if err != nil {
-		return fmt.Errorf("failed to fetch Hatena comments: %w", err)
+		return "", fmt.Errorf("failed to fetch Hatena comments: %w", err)
 	}
-
 	if comments.Text == "" {
 		logger.Printf("No Hatena comments found for %s", article.Title)
+	}
+	return comments.Text, nil
+}
+
+// fetchAndProcessHatenaComments handles Hatena bookmark comment retrieval and summarization
+func (p *HatenaProcessor) fetchAndProcessHatenaComments(ctx context.Context, article repository.Item, commentSummary **string, commentDuration *time.Duration, commentChars *int) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	commentStart := time.Now()
+	text, err := p.fetchHatenaComments(ctx, article)
+	if err != nil {
+		return err
+	}
+
+	if text == "" {
 		*commentDuration = time.Since(commentStart)
 		*commentChars = 0
 		return nil
 	}
 
-	logger.Printf("Comments summarization started text_length=%d", len(comments.Text))
+	logger.Printf("Comments summarization started text_length=%d", len(text))
 
 	// Summarize comments
-	summaryResponse, err := p.geminiRepo.SummarizeComments(ctx, comments.Text)
+	summaryResponse, err := p.geminiRepo.SummarizeComments(ctx, text)
 	if err != nil {
 		return fmt.Errorf("failed to summarize Hatena comments: %w", err)
 	}
 
 	*commentSummary = &summaryResponse.Summary
 	*commentDuration = time.Since(commentStart)
-	*commentChars = len(comments.Text)
+	*commentChars = len(text)
 
 	logger.Printf("Comments summarization completed summary_length=%d duration_ms=%d",
 		len(summaryResponse.Summary), commentDuration.Milliseconds())
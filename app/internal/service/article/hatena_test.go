@@ -15,7 +15,10 @@ func TestHatenaProcessor_New(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	if processor == nil {
@@ -32,7 +35,10 @@ func TestHatenaProcessor_CommentsIntegration(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	// Test that comment fetching works conceptually
@@ -79,7 +85,7 @@ func TestFilterUnprocessedArticles(t *testing.T) {
 		{Title: "Test Article 2", Link: "https://example.com/2"},
 	}
 
-	unprocessed, err := filterUnprocessedArticles(ctx, processedRepo, articles)
+	unprocessed, err := filterUnprocessedArticles(ctx, processedRepo, "hatena", articles)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
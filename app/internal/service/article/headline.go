@@ -0,0 +1,82 @@
+package article
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// RewrittenHeadlinePrefix marks a title produced by the LLM instead of the feed
+const RewrittenHeadlinePrefix = "✏️ "
+
+// HeadlineRewriter is a Notifier decorator that replaces clickbait titles with neutral,
+// descriptive headlines before forwarding. The original title travels in OriginalTitle.
+type HeadlineRewriter struct {
+	notifier   repository.Notifier
+	geminiRepo repository.GeminiRepository
+
+	mu        sync.Mutex
+	rewritten map[string]string // article URL -> rewritten title, reused for the comment notification
+}
+
+func NewHeadlineRewriter(notifier repository.Notifier, geminiRepo repository.GeminiRepository) *HeadlineRewriter {
+	return &HeadlineRewriter{
+		notifier:   notifier,
+		geminiRepo: geminiRepo,
+		rewritten:  make(map[string]string),
+	}
+}
+
+// Send rewrites the article title; a failed rewrite keeps the original title rather than failing the article
+func (h *HeadlineRewriter) Send(ctx context.Context, notification repository.Notification) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	if notification.Comment {
+		h.mu.Lock()
+		title, ok := h.rewritten[notification.URL]
+		delete(h.rewritten, notification.URL)
+		h.mu.Unlock()
+		if ok {
+			notification.OriginalTitle = notification.Title
+			notification.Title = title + " - コメント"
+		}
+		return h.notifier.Send(ctx, notification)
+	}
+
+	headline, err := h.geminiRepo.RewriteHeadline(ctx, notification.Title, notification.Summary)
+	if err != nil {
+		logger.Printf("Warning: Failed to rewrite headline for %s: %v", notification.Title, err)
+		return h.notifier.Send(ctx, notification)
+	}
+
+	if strings.EqualFold(headline, notification.Title) {
+		return h.notifier.Send(ctx, notification)
+	}
+
+	title := RewrittenHeadlinePrefix + headline
+	h.mu.Lock()
+	h.rewritten[notification.URL] = title
+	h.mu.Unlock()
+
+	notification.OriginalTitle = notification.Title
+	notification.Title = title
+	return h.notifier.Send(ctx, notification)
+}
+
+// SendOnDemandSummary forwards unchanged; on-demand requests are explicit, not clickbait feed items
+func (h *HeadlineRewriter) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	return h.notifier.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush forwards to the wrapped notifier when it batches
+func (h *HeadlineRewriter) Flush(ctx context.Context) error {
+	if flusher, ok := h.notifier.(repository.Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+type failingHeadlineGemini struct {
+	mocks.MockGeminiRepo
+}
+
+func (f *failingHeadlineGemini) RewriteHeadline(ctx context.Context, title, summary string) (string, error) {
+	return "", errors.New("quota exceeded")
+}
+
+func TestHeadlineRewriter_Send(t *testing.T) {
+	notifier := &mocks.MockSlackRepo{}
+	rewriter := NewHeadlineRewriter(notifier, &mocks.MockGeminiRepo{})
+	ctx := context.Background()
+
+	if err := rewriter.Send(ctx, repository.Notification{Title: "You won't believe this", URL: "https://example.com/a", Summary: "s"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := rewriter.Send(ctx, repository.Notification{Title: "You won't believe this - コメント", URL: "https://example.com/a", Summary: "c", Comment: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(notifier.SentNotifications) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(notifier.SentNotifications))
+	}
+	article := notifier.SentNotifications[0]
+	if article.Title != RewrittenHeadlinePrefix+"test headline" || article.OriginalTitle != "You won't believe this" {
+		t.Errorf("Unexpected article title=%q original=%q", article.Title, article.OriginalTitle)
+	}
+	comment := notifier.SentNotifications[1]
+	if comment.Title != RewrittenHeadlinePrefix+"test headline - コメント" {
+		t.Errorf("Expected comment to reuse the rewritten title, got %q", comment.Title)
+	}
+}
+
+func TestHeadlineRewriter_KeepsOriginalOnError(t *testing.T) {
+	notifier := &mocks.MockSlackRepo{}
+	rewriter := NewHeadlineRewriter(notifier, &failingHeadlineGemini{})
+
+	if err := rewriter.Send(context.Background(), repository.Notification{Title: "Original", URL: "https://example.com/a"}); err != nil {
+		t.Fatalf("Rewrite failure should not fail the notification, got %v", err)
+	}
+	if len(notifier.SentNotifications) != 1 || notifier.SentNotifications[0].Title != "Original" || notifier.SentNotifications[0].OriginalTitle != "" {
+		t.Errorf("Expected original title, got %+v", notifier.SentNotifications)
+	}
+}
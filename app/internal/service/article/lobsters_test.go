@@ -14,7 +14,10 @@ func TestLobstersProcessor_New(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	if processor == nil {
@@ -28,7 +31,10 @@ func TestLobstersProcessor_Process_NoArticles(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	ctx := context.Background()
@@ -48,7 +54,10 @@ func TestLobstersProcessor_CommentsIntegration(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	// Test that comment fetching works conceptually
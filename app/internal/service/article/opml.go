@@ -0,0 +1,102 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+// OPMLProcessor processes every feed subscribed in an OPML file as a generic feed (summaries only, no comments)
+type OPMLProcessor struct {
+	opmlSource    repository.OPMLSource
+	rssRepo       repository.RSSRepository
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+}
+
+func NewOPMLProcessor(
+	opmlSource repository.OPMLSource,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *OPMLProcessor {
+	return &OPMLProcessor{
+		opmlSource:    opmlSource,
+		rssRepo:       rssRepo,
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+	}
+}
+
+// Process loads the OPML file and processes each feed in turn. A failing feed does not stop the others;
+// the failures are returned together at the end.
+func (p *OPMLProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=opml")
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=opml duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	content, err := p.opmlSource.Load(ctx)
+	if err != nil {
+		logger.Printf("Error loading OPML: %v", err)
+		return fmt.Errorf("loading OPML: %w", err)
+	}
+	feeds, err := rss.ParseOPML(content)
+	if err != nil {
+		return fmt.Errorf("parsing OPML: %w", err)
+	}
+	logger.Printf("OPML loaded feeds=%d", len(feeds))
+
+	var errs []error
+	for _, feed := range feeds {
+		if err := p.feedProcessor(feed).processFeed(ctx); err != nil {
+			logger.Printf("Error processing feed %s: %v", feed.Source, err)
+			errs = append(errs, fmt.Errorf("processing feed %s: %w", feed.Source, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// feedProcessor adapts a subscription to the generic feed pipeline; its failures are backlogged under "opml"
+func (p *OPMLProcessor) feedProcessor(feed rss.OPMLFeed) *GenericFeedProcessor {
+	processor := NewGenericFeedProcessor(rss.GenericFeedSpec{Name: feed.Source, URL: feed.URL},
+		p.rssRepo, p.geminiRepo, p.notifier, p.processedRepo, p.backlogRepo, p.statsRepo, p.limiter, p.concurrency)
+	processor.backlogFeed = "opml"
+	return processor
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *OPMLProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.feedProcessor(rss.OPMLFeed{Source: article.Source}).processGenericArticle(ctx, article)
+}
@@ -0,0 +1,83 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// feedFetcher serves fixed feed documents by URL
+type feedFetcher map[string]string
+
+func (f feedFetcher) FetchFeedXML(ctx context.Context, url string, headers map[string]string) (string, error) {
+	doc, ok := f[url]
+	if !ok {
+		return "", fmt.Errorf("unexpected status code: 404")
+	}
+	return doc, nil
+}
+
+func (f feedFetcher) GetUniqueItems(items []repository.Item) []repository.Item {
+	return items
+}
+
+const testOPML = `<?xml version="1.0"?>
+<opml version="2.0"><body>
+	<outline type="rss" text="Example Blog" xmlUrl="https://blog.example.com/feed.xml"/>
+	<outline type="rss" text="Gone Blog" xmlUrl="https://gone.example.com/feed.xml"/>
+</body></opml>`
+
+func TestOPMLProcessor_Process(t *testing.T) {
+	fetcher := feedFetcher{
+		"https://blog.example.com/feed.xml": `<rss version="2.0"><channel>
+<item><title>First post</title><link>https://blog.example.com/first</link></item>
+</channel></rss>`,
+	}
+	slackRepo := &mocks.MockSlackRepo{}
+	processor := NewOPMLProcessor(
+		&mocks.MockOPMLSource{Content: testOPML},
+		fetcher,
+		&mocks.MockGeminiRepo{},
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	err := processor.Process(context.Background())
+
+	// The unreachable feed is reported without stopping the other one
+	if err == nil || !strings.Contains(err.Error(), "opml:gone-blog") {
+		t.Errorf("Expected error for the unreachable feed, got %v", err)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if n := slackRepo.SentNotifications[0]; n.URL != "https://blog.example.com/first" || n.Source != "opml:example-blog" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+}
+
+func TestOPMLProcessor_Process_LoadError(t *testing.T) {
+	processor := NewOPMLProcessor(
+		&mocks.MockOPMLSource{ShouldFail: true},
+		feedFetcher{},
+		&mocks.MockGeminiRepo{},
+		&mocks.MockSlackRepo{},
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err == nil {
+		t.Error("Expected error when the OPML file cannot be loaded")
+	}
+}
@@ -0,0 +1,173 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// OpsNotifier is a Notifier decorator that gives a feed an ops thread: each run opens a thread
+// and every processed article adds a ✅/⚠️/❌ line with its phase durations. Notifications pass through.
+type OpsNotifier struct {
+	notifier repository.Notifier
+	thread   repository.OpsThread
+}
+
+func NewOpsNotifier(notifier repository.Notifier, thread repository.OpsThread) *OpsNotifier {
+	return &OpsNotifier{
+		notifier: notifier,
+		thread:   thread,
+	}
+}
+
+func (o *OpsNotifier) Send(ctx context.Context, notification repository.Notification) error {
+	return o.notifier.Send(ctx, notification)
+}
+
+func (o *OpsNotifier) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	return o.notifier.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush forwards to the wrapped notifier when it batches
+func (o *OpsNotifier) Flush(ctx context.Context) error {
+	if flusher, ok := o.notifier.(repository.Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// opsRun collects one feed run's article outcomes into its ops thread
+type opsRun struct {
+	thread   repository.OpsThread
+	threadID string
+	feed     string
+	start    time.Time
+
+	mu                 sync.Mutex
+	ok, warned, failed int
+}
+
+// startOpsRun opens the run's ops thread when the feed notifier has one; nil disables annotations.
+// Runs without articles stay quiet.
+func startOpsRun(ctx context.Context, notifier repository.Notifier, feed string, articles int) *opsRun {
+	ops, ok := notifier.(*OpsNotifier)
+	if !ok || articles == 0 {
+		return nil
+	}
+
+	threadID, err := ops.thread.Start(ctx, fmt.Sprintf("🛠 %s: %d件の処理を開始", feed, articles))
+	if err != nil {
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+		// ops スレッドは補助情報なので処理は続行
+		logger.Printf("Warning: Failed to start ops thread feed=%s: %v", feed, err)
+		return nil
+	}
+	return &opsRun{
+		thread:   ops.thread,
+		threadID: threadID,
+		feed:     feed,
+		start:    time.Now(),
+	}
+}
+
+// annotate posts the article's outcome line
+func (r *opsRun) annotate(ctx context.Context, article repository.Item, phases *articlePhases, total time.Duration, err error) {
+	icon := "✅"
+	r.mu.Lock()
+	switch {
+	case err != nil:
+		icon = "❌"
+		r.failed++
+	case len(phases.warnings) > 0:
+		icon = "⚠️"
+		r.warned++
+	default:
+		r.ok++
+	}
+	r.mu.Unlock()
+
+	r.reply(ctx, fmt.Sprintf("%s %s — %s", icon, article.Title, phases.format(total, err)))
+}
+
+// finish posts the run's totals
+func (r *opsRun) finish(ctx context.Context) {
+	r.mu.Lock()
+	line := fmt.Sprintf("🏁 完了 ✅%d ⚠️%d ❌%d (%s)", r.ok, r.warned, r.failed, formatOpsDuration(time.Since(r.start)))
+	r.mu.Unlock()
+	r.reply(ctx, line)
+}
+
+func (r *opsRun) reply(ctx context.Context, text string) {
+	if err := r.thread.Reply(ctx, r.threadID, text); err != nil {
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+		logger.Printf("Warning: Failed to annotate ops thread feed=%s: %v", r.feed, err)
+	}
+}
+
+type opsPhasesKey struct{}
+
+type opsPhase struct {
+	name     string
+	duration time.Duration
+}
+
+// articlePhases collects one article's phase timings and warnings through its context
+type articlePhases struct {
+	mu       sync.Mutex
+	phases   []opsPhase
+	warnings []string
+}
+
+// recordPhase notes a phase duration for the article's ops line (no-op without an ops run)
+func recordPhase(ctx context.Context, name string, duration time.Duration) {
+	phases, ok := ctx.Value(opsPhasesKey{}).(*articlePhases)
+	if !ok || duration <= 0 {
+		return
+	}
+	phases.mu.Lock()
+	phases.phases = append(phases.phases, opsPhase{name: name, duration: duration})
+	phases.mu.Unlock()
+}
+
+// recordWarning marks the article ⚠️ in the ops thread (no-op without an ops run)
+func recordWarning(ctx context.Context, warning string) {
+	phases, ok := ctx.Value(opsPhasesKey{}).(*articlePhases)
+	if !ok {
+		return
+	}
+	phases.mu.Lock()
+	phases.warnings = append(phases.warnings, warning)
+	phases.mu.Unlock()
+}
+
+// format renders "summary 12.3s · notify 0.4s · total 13.0s", followed by the error or warnings
+func (p *articlePhases) format(total time.Duration, err error) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var parts []string
+	for _, phase := range p.phases {
+		parts = append(parts, phase.name+" "+formatOpsDuration(phase.duration))
+	}
+	parts = append(parts, "total "+formatOpsDuration(total))
+	line := strings.Join(parts, " · ")
+
+	if err != nil {
+		return line + "\n> " + err.Error()
+	}
+	if len(p.warnings) > 0 {
+		return line + "\n> " + strings.Join(p.warnings, "; ")
+	}
+	return line
+}
+
+func formatOpsDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -0,0 +1,69 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestOpsRun_AnnotatesArticles(t *testing.T) {
+	thread := &mocks.MockOpsThread{}
+	notifier := NewOpsNotifier(&mocks.MockSlackRepo{}, thread)
+	ctx := context.Background()
+
+	articles := []repository.Item{{Title: "ok"}, {Title: "warned"}, {Title: "failed"}}
+	run := startOpsRun(ctx, notifier, "lobsters", len(articles))
+	if run == nil {
+		t.Fatal("Expected an ops run for an OpsNotifier")
+	}
+
+	_, err := processArticles(ctx, nil, run, articles, func(ctx context.Context, article repository.Item) error {
+		recordPhase(ctx, "summary", 1500*time.Millisecond)
+		switch article.Title {
+		case "warned":
+			recordWarning(ctx, "comments: timeout")
+		case "failed":
+			return errors.New("gemini 500")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected the failing article's error")
+	}
+
+	if len(thread.Parents) != 1 || !strings.Contains(thread.Parents[0], "lobsters") {
+		t.Fatalf("Expected one parent message for lobsters, got %v", thread.Parents)
+	}
+	want := []string{"✅ ok — summary 1.5s", "⚠️ warned — summary 1.5s", "❌ failed —", "🏁 完了 ✅1 ⚠️1 ❌1"}
+	if len(thread.Replies) != len(want) {
+		t.Fatalf("Expected %d replies, got %v", len(want), thread.Replies)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(thread.Replies[i], prefix) {
+			t.Errorf("reply[%d] = %q, want prefix %q", i, thread.Replies[i], prefix)
+		}
+	}
+	if !strings.Contains(thread.Replies[1], "comments: timeout") || !strings.Contains(thread.Replies[2], "gemini 500") {
+		t.Errorf("Expected warning and error details, got %v", thread.Replies)
+	}
+}
+
+func TestOpsRun_QuietWithoutOpsNotifier(t *testing.T) {
+	if run := startOpsRun(context.Background(), &mocks.MockSlackRepo{}, "hatena", 3); run != nil {
+		t.Error("Expected no ops run for a plain notifier")
+	}
+
+	thread := &mocks.MockOpsThread{}
+	if run := startOpsRun(context.Background(), NewOpsNotifier(&mocks.MockSlackRepo{}, thread), "hatena", 0); run != nil || len(thread.Parents) != 0 {
+		t.Error("Expected runs without articles to stay quiet")
+	}
+
+	// Phases recorded outside an ops run are ignored
+	recordPhase(context.Background(), "summary", time.Second)
+	recordWarning(context.Background(), "ignored")
+}
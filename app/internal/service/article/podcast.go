@@ -0,0 +1,210 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+type PodcastProcessor struct {
+	podcastRepo   rss.FeedRepository
+	transcriber   repository.Transcriber
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+}
+
+func NewPodcastProcessor(
+	feedURLs []string,
+	maxEpisodes int,
+	transcriber repository.Transcriber,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *PodcastProcessor {
+	return &PodcastProcessor{
+		podcastRepo:   rss.NewPodcastRepository(rssRepo, feedURLs, maxEpisodes),
+		transcriber:   transcriber,
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+	}
+}
+
+func (p *PodcastProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=podcast")
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=podcast duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=podcast")
+	articles, err := p.podcastRepo.FetchArticles(ctx)
+	if err != nil {
+		logger.Printf("Error processing feed podcast: %v", err)
+		return fmt.Errorf("processing feed podcast: %w", err)
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "podcast", len(articles))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed articles
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, "podcast", articles)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
+
+	logger.Printf("Selected unprocessed articles: %d from podcasts", len(limitedArticles))
+
+	// Process each article
+	run := startOpsRun(ctx, p.notifier, "podcast", len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
+		if err := p.processPodcastEpisode(ctx, article); err != nil {
+			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, "podcast", article, err)
+			return fmt.Errorf("processing article %s: %w", article.Title, err)
+		}
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=podcast processed_count=%d", len(limitedArticles))
+	return nil
+}
+
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *PodcastProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processPodcastEpisode(ctx, article)
+}
+
+// processPodcastEpisode summarizes an episode and posts it with its show
+func (p *PodcastProcessor) processPodcastEpisode(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+
+	logger.Printf("Article processing started title=%s url=%s source=%s",
+		article.Title, article.Link, article.Source)
+
+	// 2. エピソード要約（文字起こし）
+	summaryStart := time.Now()
+	summary, err := p.summarizePodcastEpisode(ctx, article)
+	if err != nil {
+		logger.Printf("Error summarizing article %s: %v", article.Title, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
+
+	// 3. 通知送信
+	slackStart := time.Now()
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
+		Authors:       article.Authors,
+	}); err != nil {
+		logger.Printf("Error sending article notification for %s: %v", article.Title, err)
+		return fmt.Errorf("sending article notification: %w", err)
+	}
+	slackDuration := time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
+
+	// 4. インデックス更新
+	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
+
+	totalDuration := time.Since(start)
+	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d",
+		article.Title, totalDuration.Milliseconds(), summaryDuration.Milliseconds(), slackDuration.Milliseconds(), processDuration.Milliseconds())
+
+	return nil
+}
+
+// summarizePodcastEpisode transcribes the episode audio and summarizes the transcript. Episodes whose
+// audio cannot be transcribed for good (too large, gone) are summarized from their show notes; outages
+// and rate limits are returned so that the backlog retries the episode.
+func (p *PodcastProcessor) summarizePodcastEpisode(ctx context.Context, article repository.Item) (*repository.SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	transcribeStart := time.Now()
+	transcript, err := p.transcriber.Transcribe(ctx, article.AudioURL)
+	recordPhase(ctx, "transcribe", time.Since(transcribeStart))
+	if err != nil && !httperr.IsPermanent(err) {
+		return nil, fmt.Errorf("transcribing audio: %w", err)
+	}
+	if err != nil || strings.TrimSpace(transcript) == "" {
+		logger.Printf("Transcript unavailable, summarizing the show notes url=%s error=%v", article.AudioURL, err)
+		return summarizeSourceOrText(ctx, p.geminiRepo, article, "", "Show notes")
+	}
+
+	summaryText, err := p.geminiRepo.SummarizeText(ctx, "Title: "+article.Title+"\n\nTranscript:\n"+transcript)
+	if err != nil {
+		return nil, err
+	}
+	summary := &repository.SummarizeResponse{
+		Summary:      summaryText,
+		ProcessedAt:  time.Now(),
+		ContentChars: len(transcript),
+		Title:        article.Title,
+	}
+	countSummary(currentRunStats(ctx), summary)
+	return summary, nil
+}
@@ -0,0 +1,86 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func newTestPodcastProcessor(transcriber repository.Transcriber, gemini repository.GeminiRepository, slackRepo *mocks.MockSlackRepo, backlogRepo *mocks.MockBacklogRepo) *PodcastProcessor {
+	return NewPodcastProcessor(
+		[]string{"https://podcast.example.com/podcast.xml"},
+		3,
+		transcriber,
+		&mocks.MockPodcastRepo{},
+		gemini,
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		backlogRepo,
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+}
+
+func TestPodcastProcessor_Process_SummarizesTranscript(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	gemini := &sourceGemini{}
+	transcriber := &mocks.MockTranscriber{Transcript: "Welcome to the show. Today we talk about testing."}
+	processor := newTestPodcastProcessor(transcriber, gemini, slackRepo, &mocks.MockBacklogRepo{})
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(transcriber.AudioURLs) != 1 || transcriber.AudioURLs[0] != "https://cdn.example.com/episode-1.mp3" {
+		t.Errorf("Expected the enclosure to be transcribed, got %v", transcriber.AudioURLs)
+	}
+	if !strings.Contains(gemini.textSent, "Transcript:\nWelcome to the show.") {
+		t.Errorf("Expected the transcript to be summarized, got %q", gemini.textSent)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if n := slackRepo.SentNotifications[0]; n.Source != "podcast" || n.URL != "https://podcast.example.com/episodes/1" || len(n.Authors) != 1 || n.Authors[0] != "Test Show" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+}
+
+func TestPodcastProcessor_Process_FallsBackToShowNotes(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	gemini := &sourceGemini{}
+	transcriber := &mocks.MockTranscriber{Err: httperr.Permanent(repository.ErrAudioTooLarge)}
+	processor := newTestPodcastProcessor(transcriber, gemini, slackRepo, &mocks.MockBacklogRepo{})
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(gemini.textSent, "Show notes:\nShow notes of the test episode") {
+		t.Errorf("Expected the show notes to be summarized, got %q", gemini.textSent)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Errorf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+}
+
+func TestPodcastProcessor_Process_TransientTranscriptionFailure(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	backlogRepo := &mocks.MockBacklogRepo{}
+	transcriber := &mocks.MockTranscriber{Err: httperr.Transport(fmt.Errorf("connection reset"))}
+	processor := newTestPodcastProcessor(transcriber, &sourceGemini{}, slackRepo, backlogRepo)
+
+	if err := processor.Process(context.Background()); err == nil {
+		t.Fatal("Expected an error for a transient transcription failure")
+	}
+	if len(slackRepo.SentNotifications) != 0 {
+		t.Errorf("Expected no notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if len(backlogRepo.Entries) != 1 || backlogRepo.Entries[0].Item.AudioURL == "" {
+		t.Errorf("Expected the episode with its audio to be recorded in the backlog, got %+v", backlogRepo.Entries)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
@@ -17,24 +18,33 @@ import (
 type RedditProcessor struct {
 	redditRepo    rss.FeedRepository
 	geminiRepo    repository.GeminiRepository
-	slackRepo     repository.SlackRepository
+	notifier      repository.Notifier
 	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
 	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
 }
 
 func NewRedditProcessor(
 	rssRepo repository.RSSRepository,
 	geminiRepo repository.GeminiRepository,
-	slackRepo repository.SlackRepository,
+	notifier repository.Notifier,
 	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
 	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
 ) *RedditProcessor {
 	return &RedditProcessor{
 		redditRepo:    rss.NewRedditRSSRepository(rssRepo),
 		geminiRepo:    geminiRepo,
-		slackRepo:     slackRepo,
+		notifier:      notifier,
 		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
 		limiter:       limiter,
+		concurrency:   concurrency,
 	}
 }
 
@@ -47,6 +57,8 @@ func (p *RedditProcessor) Process(ctx context.Context) error {
 		duration := time.Since(start)
 		logger.Printf("Process request completed feed=reddit duration_ms=%d", duration.Milliseconds())
 	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
 
 	// 1. データ取得
 	logger.Printf("Feed processing started feed=reddit")
@@ -56,30 +68,52 @@ func (p *RedditProcessor) Process(ctx context.Context) error {
 		return fmt.Errorf("processing feed reddit: %w", err)
 	}
 
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "reddit", len(articles))
+	if err != nil {
+		return err
+	}
+
 	// Filter unprocessed articles
-	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, articles)
+	unprocessedArticles, err := filterUnprocessedArticles(ctx, p.processedRepo, "reddit", articles)
 	if err != nil {
 		return fmt.Errorf("filtering unprocessed articles: %w", err)
 	}
 
 	// Apply article limiting
-	limitedArticles := p.limiter.Limit(unprocessedArticles)
+	limitedArticles := p.limiter.Limit(ctx, unprocessedArticles)
 
 	logger.Printf("Selected unprocessed articles: %d from Reddit r/programming", len(limitedArticles))
 
 	// Process each article
-	for i, article := range limitedArticles {
+	run := startOpsRun(ctx, p.notifier, "reddit", len(limitedArticles))
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, run, limitedArticles, func(ctx context.Context, article repository.Item) error {
 		if err := p.processRedditArticle(ctx, article); err != nil {
 			logger.Printf("Error processing article %s: %v", article.Title, err)
+			recordBacklog(ctx, p.backlogRepo, "reddit", article, err)
 			return fmt.Errorf("processing article %s: %w", article.Title, err)
 		}
-		logger.Printf("Article processed %d/%d title=%s", i+1, len(limitedArticles), article.Title)
+		logger.Printf("Article processed %d/%d title=%s", atomic.AddInt32(&processedCount, 1), len(limitedArticles), article.Title)
+		return nil
+	})
+	feedRun.Items = len(articles)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
 	}
 
 	logger.Printf("Feed processing completed feed=reddit processed_count=%d", len(limitedArticles))
 	return nil
 }
 
+// ProcessItem processes a single article outside of the feed run (used by the backlog drain)
+func (p *RedditProcessor) ProcessItem(ctx context.Context, article repository.Item) error {
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processRedditArticle(ctx, article)
+}
+
 // processRedditArticle handles Reddit articles with comments
 func (p *RedditProcessor) processRedditArticle(ctx context.Context, article repository.Item) error {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
@@ -90,12 +124,13 @@ func (p *RedditProcessor) processRedditArticle(ctx context.Context, article repo
 
 	// 2. 記事要約
 	summaryStart := time.Now()
-	summary, err := p.geminiRepo.SummarizeURL(ctx, article.Link)
+	summary, err := summarizeArticle(ctx, p.geminiRepo, article)
 	if err != nil {
 		logger.Printf("Error summarizing article %s: %v", article.Title, err)
 		return fmt.Errorf("summarizing article: %w", err)
 	}
 	summaryDuration := time.Since(summaryStart)
+	recordPhase(ctx, "summary", summaryDuration)
 
 	var slackDuration time.Duration
 
@@ -105,25 +140,34 @@ func (p *RedditProcessor) processRedditArticle(ctx context.Context, article repo
 
 	// 3. 通知送信（記事のみ）
 	slackStart := time.Now()
-	if err := p.slackRepo.Send(ctx, repository.Notification{
-		Title:        article.Title,
-		Source:       article.Source,
-		URL:          article.Link,
-		Summary:      summary.Summary,
-		ContentChars: summary.ContentChars,
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:         article.Title,
+		Source:        article.Source,
+		URL:           article.Link,
+		Summary:       summary.Summary,
+		ContentChars:  summary.ContentChars,
+		PromptVariant: summary.PromptVariant,
+		Provenance:    summary.Provenance,
+		Glossary:      summary.Glossary,
+		Difficulty:    summary.Difficulty,
+		Breaking:      article.Breaking,
 	}); err != nil {
 		logger.Printf("Error sending notification for %s: %v", article.Title, err)
 		return fmt.Errorf("sending notification: %w", err)
 	}
 	slackDuration = time.Since(slackStart)
+	recordPhase(ctx, "notify", slackDuration)
 
 	// 4. インデックス更新
 	processStart := time.Now()
+	article.PromptVariant = summary.PromptVariant
+	article.Provenance = summary.Provenance
 	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
 		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Title, err, debug.Stack())
 		return fmt.Errorf("marking as processed: %w", err)
 	}
 	processDuration := time.Since(processStart)
+	recordPhase(ctx, "index", processDuration)
 
 	totalDuration := time.Since(start)
 	logger.Printf("Article processing completed title=%s total_duration_ms=%d summary_duration_ms=%d slack_duration_ms=%d process_duration_ms=%d (comment processing disabled)",
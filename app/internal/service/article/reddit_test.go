@@ -13,7 +13,10 @@ func TestRedditProcessor_New(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	if processor == nil {
@@ -27,7 +30,10 @@ func TestRedditProcessor_Process_NoArticles(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	ctx := context.Background()
@@ -0,0 +1,133 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// Run statuses of a feed, and of a request over its feeds
+const (
+	RunStatusOK      = "ok"
+	RunStatusPartial = "partial" // Some articles (or feeds) failed
+	RunStatusFailed  = "failed"  // Nothing succeeded
+)
+
+// FeedReport is the machine-readable outcome of one feed run
+type FeedReport struct {
+	Feed       string `json:"feed"`
+	Status     string `json:"status"`
+	Selected   int    `json:"selected"` // Unprocessed articles picked for the run
+	Attempted  int    `json:"attempted"`
+	Succeeded  int    `json:"succeeded"`
+	Failed     int    `json:"failed"`
+	Summaries  int    `json:"summaries"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RunReport collects the feed reports of one request (an HTTP call or a CLI run)
+type RunReport struct {
+	mu    sync.Mutex
+	feeds []FeedReport
+}
+
+// runReportJSON is the serialized RunReport
+type runReportJSON struct {
+	Status string       `json:"status"`
+	Feeds  []FeedReport `json:"feeds"`
+}
+
+type runReportKey struct{}
+
+type feedReportKey struct{}
+
+// WithRunReport returns a context whose feed runs (see RunFeed) are reported to the returned RunReport
+func WithRunReport(ctx context.Context) (context.Context, *RunReport) {
+	report := &RunReport{}
+	return context.WithValue(ctx, runReportKey{}, report), report
+}
+
+// RunFeed runs one feed and adds its outcome to the context's RunReport (just runs it without one)
+func RunFeed(ctx context.Context, feed string, process func(ctx context.Context) error) error {
+	report, ok := ctx.Value(runReportKey{}).(*RunReport)
+	if !ok {
+		return process(ctx)
+	}
+
+	feedReport := &FeedReport{Feed: feed}
+	start := time.Now()
+	err := process(context.WithValue(ctx, feedReportKey{}, feedReport))
+	feedReport.DurationMS = time.Since(start).Milliseconds()
+	feedReport.Succeeded = feedReport.Attempted - feedReport.Failed
+	switch {
+	case err == nil:
+		feedReport.Status = RunStatusOK
+	case feedReport.Succeeded > 0:
+		feedReport.Status = RunStatusPartial
+	default:
+		feedReport.Status = RunStatusFailed
+	}
+	if err != nil {
+		feedReport.Error = err.Error()
+	}
+
+	report.mu.Lock()
+	report.feeds = append(report.feeds, *feedReport)
+	report.mu.Unlock()
+	return err
+}
+
+// reportFeedRun adds a batch of articles to the feed's report (no-op outside RunFeed). Feeds that
+// run several batches, like OPML subscriptions, add each one.
+func reportFeedRun(ctx context.Context, selected int, run repository.FeedRun) {
+	feedReport, ok := ctx.Value(feedReportKey{}).(*FeedReport)
+	if !ok {
+		return
+	}
+	feedReport.Selected += selected
+	feedReport.Attempted += run.Attempted
+	feedReport.Failed += run.Failed
+	feedReport.Summaries += run.Summaries
+}
+
+// Feeds returns the reports of the feeds run so far
+func (r *RunReport) Feeds() []FeedReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FeedReport(nil), r.feeds...)
+}
+
+// Status is ok when every feed succeeded (or none ran), failed when every feed failed, and partial otherwise
+func (r *RunReport) Status() string {
+	feeds := r.Feeds()
+	failed, ok := 0, 0
+	for _, feed := range feeds {
+		switch feed.Status {
+		case RunStatusOK:
+			ok++
+		case RunStatusFailed:
+			failed++
+		}
+	}
+	switch {
+	case ok == len(feeds):
+		return RunStatusOK
+	case failed == len(feeds):
+		return RunStatusFailed
+	default:
+		return RunStatusPartial
+	}
+}
+
+// MarshalJSON renders {"status": ..., "feeds": [...]}
+func (r *RunReport) MarshalJSON() ([]byte, error) {
+	feeds := r.Feeds()
+	if feeds == nil {
+		feeds = []FeedReport{}
+	}
+	return json.Marshal(runReportJSON{Status: r.Status(), Feeds: feeds})
+}
@@ -0,0 +1,94 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestRunFeed_ReportsProcessArticlesCounts(t *testing.T) {
+	ctx, report := WithRunReport(context.Background())
+	articles := []repository.Item{{Link: "https://example.com/a"}, {Link: "https://example.com/b"}}
+
+	err := RunFeed(ctx, "hatena", func(ctx context.Context) error {
+		_, err := processArticles(ctx, nil, nil, articles, func(ctx context.Context, article repository.Item) error {
+			if article.Link == "https://example.com/b" {
+				return errors.New("summarize failed")
+			}
+			return nil
+		})
+		return err
+	})
+	if err == nil {
+		t.Fatal("Expected the article error to be returned")
+	}
+
+	feeds := report.Feeds()
+	if len(feeds) != 1 {
+		t.Fatalf("Expected 1 feed report, got %d", len(feeds))
+	}
+	feed := feeds[0]
+	if feed.Feed != "hatena" || feed.Status != RunStatusPartial {
+		t.Errorf("Expected hatena partial, got %s %s", feed.Feed, feed.Status)
+	}
+	if feed.Selected != 2 || feed.Attempted != 2 || feed.Succeeded != 1 || feed.Failed != 1 {
+		t.Errorf("Unexpected counts: %+v", feed)
+	}
+	if feed.Error != "summarize failed" {
+		t.Errorf("Expected the error in the report, got %q", feed.Error)
+	}
+}
+
+func TestRunFeed_WithoutReport(t *testing.T) {
+	called := false
+	err := RunFeed(context.Background(), "hatena", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if err != nil || !called {
+		t.Errorf("Expected the feed to run without a report, called=%v err=%v", called, err)
+	}
+}
+
+func TestRunReport_Status(t *testing.T) {
+	fail := func(ctx context.Context) error { return errors.New("fetch failed") }
+	ok := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name  string
+		feeds []func(ctx context.Context) error
+		want  string
+	}{
+		{name: "no feeds", want: RunStatusOK},
+		{name: "all ok", feeds: []func(ctx context.Context) error{ok, ok}, want: RunStatusOK},
+		{name: "some failed", feeds: []func(ctx context.Context) error{ok, fail}, want: RunStatusPartial},
+		{name: "all failed", feeds: []func(ctx context.Context) error{fail, fail}, want: RunStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, report := WithRunReport(context.Background())
+			for _, feed := range tt.feeds {
+				RunFeed(ctx, "feed", feed)
+			}
+			if got := report.Status(); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReport_MarshalJSON(t *testing.T) {
+	_, report := WithRunReport(context.Background())
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"status":"ok","feeds":[]}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,26 @@
+package article
+
+import "context"
+
+type reprocessKey struct{}
+
+// WithReprocess returns a context whose runs ignore the processed index, so that articles are
+// summarized and posted again and their index entries overwritten (after a prompt fix or a bad
+// summary). Without links every article of the run is reprocessed; otherwise only those links,
+// which still have to be in the feed's current items.
+func WithReprocess(ctx context.Context, links ...string) context.Context {
+	set := make(map[string]bool, len(links))
+	for _, link := range links {
+		set[link] = true
+	}
+	return context.WithValue(ctx, reprocessKey{}, set)
+}
+
+// Reprocessing reports whether the context's run reprocesses the article link
+func Reprocessing(ctx context.Context, link string) bool {
+	links, ok := ctx.Value(reprocessKey{}).(map[string]bool)
+	if !ok {
+		return false
+	}
+	return len(links) == 0 || links[link]
+}
@@ -0,0 +1,83 @@
+package article
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestFilterUnprocessedArticles_Reprocess(t *testing.T) {
+	ctx := context.Background()
+	processedRepo := repository.NewMemoryProcessedArticleRepository()
+	articles := []repository.Item{
+		{Title: "A", Link: "https://example.com/a"},
+		{Title: "B", Link: "https://example.com/b"},
+		{Title: "C", Link: "https://example.com/c"},
+	}
+	for _, item := range articles[:2] {
+		if err := processedRepo.MarkAsProcessed(ctx, item); err != nil {
+			t.Fatalf("MarkAsProcessed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected []string
+	}{
+		{"dedup", ctx, []string{"https://example.com/c"}},
+		{"whole run", WithReprocess(ctx), []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}},
+		{"one link", WithReprocess(ctx, "https://example.com/b"), []string{"https://example.com/b", "https://example.com/c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unprocessed, err := filterUnprocessedArticles(tt.ctx, processedRepo, "hatena", articles)
+			if err != nil {
+				t.Fatalf("filterUnprocessedArticles: %v", err)
+			}
+			if len(unprocessed) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %+v", tt.expected, unprocessed)
+			}
+			for i, link := range tt.expected {
+				if unprocessed[i].Link != link {
+					t.Errorf("Expected %s at %d, got %s", link, i, unprocessed[i].Link)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterUnprocessedArticles_DedupTTL(t *testing.T) {
+	processedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	base := repository.NewMemoryProcessedArticleRepository()
+	item := repository.Item{Title: "A", Link: "https://example.com/a"}
+	if err := base.MarkAsProcessed(repository.WithClock(context.Background(), repository.FixedClock(processedAt)), item); err != nil {
+		t.Fatalf("MarkAsProcessed: %v", err)
+	}
+	processedRepo := repository.NewDedupTTLRepository(base, map[string]time.Duration{"hatena": 30 * 24 * time.Hour})
+
+	tests := []struct {
+		name     string
+		feed     string
+		now      time.Time
+		expected int
+	}{
+		{"within ttl", "hatena", processedAt.AddDate(0, 0, 10), 0},
+		{"resurfaced after ttl", "hatena", processedAt.AddDate(0, 3, 0), 1},
+		{"feed without ttl", "lobsters", processedAt.AddDate(1, 0, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := repository.WithClock(context.Background(), repository.FixedClock(tt.now))
+			unprocessed, err := filterUnprocessedArticles(ctx, processedRepo, tt.feed, []repository.Item{item})
+			if err != nil {
+				t.Fatalf("filterUnprocessedArticles: %v", err)
+			}
+			if len(unprocessed) != tt.expected {
+				t.Errorf("Expected %d unprocessed, got %+v", tt.expected, unprocessed)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+)
+
+// SitemapOptions selects which sitemap URLs are processed in a one-off batch
+type SitemapOptions struct {
+	SitemapURL string
+	Pattern    *regexp.Regexp // nil matches every URL
+	Since      time.Time      // zero disables the age filter; otherwise URLs without lastmod are skipped
+	Limit      int            // 0 means no limit
+}
+
+// SitemapProcessor onboards a site without a feed by summarizing URLs enumerated from its sitemap
+type SitemapProcessor struct {
+	sitemapRepo   *rss.SitemapRepository
+	geminiRepo    repository.GeminiRepository
+	notifier      repository.Notifier
+	processedRepo repository.ProcessedArticleRepository
+}
+
+func NewSitemapProcessor(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+) *SitemapProcessor {
+	return &SitemapProcessor{
+		sitemapRepo:   rss.NewSitemapRepository(rssRepo),
+		geminiRepo:    geminiRepo,
+		notifier:      notifier,
+		processedRepo: processedRepo,
+	}
+}
+
+// Process summarizes the selected sitemap URLs and returns how many were processed
+func (p *SitemapProcessor) Process(ctx context.Context, opts SitemapOptions) (int, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=sitemap url=%s", opts.SitemapURL)
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=sitemap duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushNotifier(ctx, p.notifier)
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	entries, err := p.sitemapRepo.FetchEntries(ctx, opts.SitemapURL)
+	if err != nil {
+		logger.Printf("Error processing sitemap %s: %v", opts.SitemapURL, err)
+		return 0, fmt.Errorf("processing sitemap: %w", err)
+	}
+
+	selected := selectSitemapEntries(entries, opts)
+
+	// Filter unprocessed articles
+	unprocessed, err := filterUnprocessedArticles(ctx, p.processedRepo, "sitemap", selected)
+	if err != nil {
+		return 0, fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	if opts.Limit > 0 && len(unprocessed) > opts.Limit {
+		unprocessed = unprocessed[:opts.Limit]
+	}
+
+	logger.Printf("Selected unprocessed sitemap URLs: %d (matched %d of %d)", len(unprocessed), len(selected), len(entries))
+
+	for i, article := range unprocessed {
+		if err := p.processSitemapArticle(ctx, article); err != nil {
+			logger.Printf("Error processing sitemap URL %s: %v", article.Link, err)
+			reportFeedRun(ctx, len(unprocessed), repository.FeedRun{Attempted: i + 1, Failed: 1, Summaries: i})
+			return i, fmt.Errorf("processing sitemap URL %s: %w", article.Link, err)
+		}
+		logger.Printf("Article processed %d/%d url=%s", i+1, len(unprocessed), article.Link)
+	}
+
+	reportFeedRun(ctx, len(unprocessed), repository.FeedRun{Attempted: len(unprocessed), Summaries: len(unprocessed)})
+	return len(unprocessed), nil
+}
+
+// selectSitemapEntries applies the pattern and age filters and orders the result newest first
+func selectSitemapEntries(entries []repository.Item, opts SitemapOptions) []repository.Item {
+	var selected []repository.Item
+	for _, entry := range entries {
+		if opts.Pattern != nil && !opts.Pattern.MatchString(entry.Link) {
+			continue
+		}
+		if !opts.Since.IsZero() && (entry.ParsedDate.IsZero() || entry.ParsedDate.Before(opts.Since)) {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].ParsedDate.After(selected[j].ParsedDate)
+	})
+
+	return selected
+}
+
+// processSitemapArticle summarizes one URL; sitemaps carry no titles, so the title comes from the page
+func (p *SitemapProcessor) processSitemapArticle(ctx context.Context, article repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	summary, err := p.geminiRepo.SummarizeURLForOnDemand(ctx, article.Link)
+	if err != nil {
+		logger.Printf("Error summarizing sitemap URL %s: %v", article.Link, err)
+		return fmt.Errorf("summarizing article: %w", err)
+	}
+
+	article.Title = summary.Title
+	if article.Title == "" {
+		article.Title = article.Link
+	}
+
+	if err := p.notifier.Send(ctx, repository.Notification{
+		Title:        article.Title,
+		Source:       article.Source,
+		URL:          article.Link,
+		Summary:      summary.Summary,
+		ContentChars: summary.ContentChars,
+	}); err != nil {
+		logger.Printf("Error sending notification for %s: %v", article.Link, err)
+		return fmt.Errorf("sending notification: %w", err)
+	}
+
+	if err := p.processedRepo.MarkAsProcessed(ctx, article); err != nil {
+		logger.Printf("Error marking article as processed %s: %v\nStack:\n%s", article.Link, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package article
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestSelectSitemapEntries(t *testing.T) {
+	now := time.Now()
+	entries := []repository.Item{
+		{Link: "https://example.com/blog/old", ParsedDate: now.Add(-90 * 24 * time.Hour)},
+		{Link: "https://example.com/blog/new", ParsedDate: now.Add(-1 * time.Hour)},
+		{Link: "https://example.com/blog/mid", ParsedDate: now.Add(-48 * time.Hour)},
+		{Link: "https://example.com/blog/undated"},
+		{Link: "https://example.com/about", ParsedDate: now},
+	}
+
+	selected := selectSitemapEntries(entries, SitemapOptions{
+		Pattern: regexp.MustCompile(`/blog/`),
+		Since:   now.Add(-30 * 24 * time.Hour),
+	})
+
+	if len(selected) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(selected))
+	}
+	if selected[0].Link != "https://example.com/blog/new" || selected[1].Link != "https://example.com/blog/mid" {
+		t.Errorf("Expected newest first, got %s, %s", selected[0].Link, selected[1].Link)
+	}
+
+	all := selectSitemapEntries(entries, SitemapOptions{})
+	if len(all) != len(entries) {
+		t.Errorf("Expected all %d entries without filters, got %d", len(entries), len(all))
+	}
+}
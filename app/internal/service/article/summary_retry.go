@@ -0,0 +1,191 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// missingContentMarkers are phrases Gemini (and the empty-page fallback) use when the page had no readable content
+var missingContentMarkers = []string{
+	"内容が取得できません",
+	"内容を取得できません",
+	"中身が取得できません",
+	"本文が取得できません",
+	"コンテンツが取得できません",
+}
+
+const (
+	// minSummaryChars is the summary length below which the article content is assumed to be missing
+	minSummaryChars = 60
+	// minDescriptionChars is the RSS description length worth summarizing instead of the page
+	minDescriptionChars = 100
+)
+
+// summaryMissingContent reports whether a summary looks like the article content could not be read
+func summaryMissingContent(summary string) bool {
+	if utf8.RuneCountInString(strings.TrimSpace(summary)) < minSummaryChars {
+		return true
+	}
+	for _, marker := range missingContentMarkers {
+		if strings.Contains(summary, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeArticle summarizes the article page. When the summary indicates missing content it retries once,
+// through the render fallback when configured or else from the RSS description, and keeps the original
+// summary if the retry does not do better.
+func summarizeArticle(ctx context.Context, gemini repository.GeminiRepository, article repository.Item) (*repository.SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	stats := currentRunStats(ctx)
+	summary, err := gemini.SummarizeURL(ctx, article.Link)
+	if errors.Is(err, repository.ErrDisallowedByRobots) {
+		return summarizeDisallowed(ctx, gemini, article, err)
+	}
+	if err != nil || !summaryMissingContent(summary.Summary) {
+		if err == nil {
+			countSummary(stats, summary)
+		}
+		return summary, err
+	}
+
+	if stats != nil {
+		atomic.AddInt32(&stats.retried, 1)
+	}
+
+	// A page already rendered for having too little text is not rendered again
+	method := "render"
+	var retried *repository.SummarizeResponse
+	err = repository.ErrRenderFallbackDisabled
+	if !summaryRendered(summary) {
+		retried, err = gemini.SummarizeRendered(ctx, article.Link)
+	}
+	if errors.Is(err, repository.ErrRenderFallbackDisabled) {
+		method = "description"
+		retried, err = summarizeDescription(ctx, gemini, article, summary)
+	}
+	if err != nil || retried == nil || summaryMissingContent(retried.Summary) {
+		// 再試行でも改善しなければ元の要約をそのまま使う
+		logger.Printf("Summary retry did not recover content url=%s method=%s error=%v", article.Link, method, err)
+		recordWarning(ctx, "content missing (retry via "+method+" failed)")
+		countSummary(stats, summary)
+		return summary, nil
+	}
+
+	if stats != nil {
+		atomic.AddInt32(&stats.recovered, 1)
+	}
+	logger.Printf("Summary retry recovered content url=%s method=%s", article.Link, method)
+	recordWarning(ctx, "content recovered via "+method)
+	countSummary(stats, retried)
+	return retried, nil
+}
+
+// summarizeDisallowed summarizes the RSS description of an article whose page robots.txt does not
+// allow fetching; without a usable description the robots.txt error is returned
+func summarizeDisallowed(ctx context.Context, gemini repository.GeminiRepository, article repository.Item, disallowed error) (*repository.SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	summary, err := summarizeDescription(ctx, gemini, article, &repository.SummarizeResponse{ProcessedAt: time.Now(), Title: article.Title})
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		return nil, disallowed
+	}
+	logger.Printf("Page disallowed by robots.txt, summarized the description url=%s", article.Link)
+	recordWarning(ctx, "page disallowed by robots.txt (description summarized)")
+	countSummary(currentRunStats(ctx), summary)
+	return summary, nil
+}
+
+// summaryRendered reports whether a summary was made from the rendered page
+func summaryRendered(summary *repository.SummarizeResponse) bool {
+	return summary.Provenance != nil && strings.HasPrefix(summary.Provenance.Extraction, repository.ExtractionRendered)
+}
+
+// countSummary adds a generated summary to the run's statistics
+func countSummary(stats *runStats, summary *repository.SummarizeResponse) {
+	if stats == nil {
+		return
+	}
+	atomic.AddInt32(&stats.summaries, 1)
+	atomic.AddInt32(&stats.summaryChars, int32(utf8.RuneCountInString(summary.Summary)))
+}
+
+// summarizeDescription summarizes the RSS description in place of the unreadable page
+func summarizeDescription(ctx context.Context, gemini repository.GeminiRepository, article repository.Item, original *repository.SummarizeResponse) (*repository.SummarizeResponse, error) {
+	description := strings.TrimSpace(article.Description)
+	if utf8.RuneCountInString(description) < minDescriptionChars {
+		return nil, nil
+	}
+
+	text, err := gemini.SummarizeText(ctx, description)
+	if err != nil {
+		return nil, err
+	}
+	summary := &repository.SummarizeResponse{
+		Summary:       text,
+		ProcessedAt:   original.ProcessedAt,
+		ContentChars:  len(description),
+		Title:         original.Title,
+		PromptVariant: original.PromptVariant,
+	}
+	if original.Provenance != nil {
+		summary.Provenance = original.Provenance.ForText(repository.ExtractionDescription)
+	}
+	return summary, nil
+}
+
+// summarizeSourceOrText summarizes sourceURL, a more readable rendering of the article than its page
+// (an arXiv HTML version, a video transcript). When the source does not exist or has no readable
+// content, the article description (labelled in the prompt, e.g. "Abstract") is summarized instead.
+// Outages and rate limits are returned so that the backlog retries the article rather than
+// settling for the description.
+func summarizeSourceOrText(ctx context.Context, gemini repository.GeminiRepository, article repository.Item, sourceURL, label string) (*repository.SummarizeResponse, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	stats := currentRunStats(ctx)
+
+	if sourceURL != "" {
+		summary, err := gemini.SummarizeURL(ctx, sourceURL)
+		if err == nil && !summaryMissingContent(summary.Summary) {
+			countSummary(stats, summary)
+			return summary, nil
+		}
+		if err != nil && !httperr.IsPermanent(err) {
+			return nil, err
+		}
+		logger.Printf("Source unavailable, summarizing the %s url=%s error=%v", strings.ToLower(label), sourceURL, err)
+	}
+
+	text := strings.TrimSpace(article.Description)
+	if text == "" {
+		return nil, httperr.Permanent(fmt.Errorf("no %s for %s", strings.ToLower(label), article.Link))
+	}
+	summaryText, err := gemini.SummarizeText(ctx, "Title: "+article.Title+"\n\n"+label+":\n"+text)
+	if err != nil {
+		return nil, err
+	}
+	summary := &repository.SummarizeResponse{
+		Summary:      summaryText,
+		ProcessedAt:  time.Now(),
+		ContentChars: len(text),
+		Title:        article.Title,
+	}
+	countSummary(stats, summary)
+	return summary, nil
+}
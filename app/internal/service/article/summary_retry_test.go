@@ -0,0 +1,147 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+var fullSummary = strings.Repeat("記事の要点をまとめた十分な長さの要約です。", 5)
+
+type missingContentGemini struct {
+	mocks.MockGeminiRepo
+	rendered  *repository.SummarizeResponse // nil keeps the render fallback disabled
+	textCalls int
+	// provenance of the page summary, e.g. marking a page already rendered for having little text
+	provenance *repository.Provenance
+}
+
+func (g *missingContentGemini) SummarizeURL(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	return &repository.SummarizeResponse{Summary: "記事の内容が取得できませんでした。", PromptVariant: "v1", Provenance: g.provenance}, nil
+}
+
+func (g *missingContentGemini) SummarizeRendered(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	if g.rendered == nil {
+		return nil, repository.ErrRenderFallbackDisabled
+	}
+	return g.rendered, nil
+}
+
+func (g *missingContentGemini) SummarizeText(ctx context.Context, text string) (string, error) {
+	g.textCalls++
+	return fullSummary, nil
+}
+
+func TestSummaryMissingContent(t *testing.T) {
+	tests := []struct {
+		summary string
+		want    bool
+	}{
+		{fullSummary, false},
+		{"短い要約", true},
+		{fullSummary + "ただし本文が取得できませんでした。", true},
+	}
+	for _, tt := range tests {
+		if got := summaryMissingContent(tt.summary); got != tt.want {
+			t.Errorf("summaryMissingContent(%q) = %v, want %v", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeArticle_RetriesFromDescription(t *testing.T) {
+	gemini := &missingContentGemini{}
+	stats := &runStats{}
+	ctx := context.WithValue(context.Background(), runStatsKey{}, stats)
+	article := repository.Item{Link: "https://example.com/spa", Description: strings.Repeat("RSS の説明文。", 20)}
+
+	summary, err := summarizeArticle(ctx, gemini, article)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Summary != fullSummary || summary.PromptVariant != "v1" {
+		t.Errorf("Expected the description summary with the original variant, got %+v", summary)
+	}
+	if stats.retried != 1 || stats.recovered != 1 {
+		t.Errorf("Expected 1 retry recovered, got %+v", stats)
+	}
+}
+
+func TestSummarizeArticle_PrefersRenderFallback(t *testing.T) {
+	gemini := &missingContentGemini{rendered: &repository.SummarizeResponse{Summary: fullSummary}}
+
+	summary, err := summarizeArticle(context.Background(), gemini, repository.Item{Link: "https://example.com/spa", Description: strings.Repeat("説明", 100)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary != gemini.rendered || gemini.textCalls != 0 {
+		t.Errorf("Expected the rendered summary without a description retry, got %+v (text calls %d)", summary, gemini.textCalls)
+	}
+}
+
+func TestSummarizeArticle_KeepsOriginalWhenRetryCannotHelp(t *testing.T) {
+	gemini := &missingContentGemini{}
+	stats := &runStats{}
+	ctx := context.WithValue(context.Background(), runStatsKey{}, stats)
+
+	summary, err := summarizeArticle(ctx, gemini, repository.Item{Link: "https://example.com/spa", Description: "短い"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(summary.Summary, "取得できません") || gemini.textCalls != 0 {
+		t.Errorf("Expected the original summary without summarizing a short description, got %+v", summary)
+	}
+	if stats.retried != 1 || stats.recovered != 0 {
+		t.Errorf("Expected 1 unrecovered retry, got %+v", stats)
+	}
+}
+
+func TestSummarizeArticle_SkipsRenderForRenderedPage(t *testing.T) {
+	gemini := &missingContentGemini{
+		rendered:   &repository.SummarizeResponse{Summary: fullSummary},
+		provenance: &repository.Provenance{Provider: "gemini", Model: "m", Extraction: "rendered+readability"},
+	}
+
+	summary, err := summarizeArticle(context.Background(), gemini, repository.Item{Link: "https://example.com/spa", Description: strings.Repeat("説明", 100)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary == gemini.rendered || gemini.textCalls != 1 || summary.Provenance.Extraction != repository.ExtractionDescription {
+		t.Errorf("Expected the description summary instead of a second render, got %+v (text calls %d)", summary, gemini.textCalls)
+	}
+}
+
+type disallowedGemini struct {
+	mocks.MockGeminiRepo
+}
+
+func (g *disallowedGemini) SummarizeURL(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	return nil, fmt.Errorf("fetching HTML: %w", repository.ErrDisallowedByRobots)
+}
+
+func (g *disallowedGemini) SummarizeText(ctx context.Context, text string) (string, error) {
+	return fullSummary, nil
+}
+
+func TestSummarizeArticle_RobotsDisallowedUsesDescription(t *testing.T) {
+	gemini := &disallowedGemini{}
+	article := repository.Item{Title: "記事", Link: "https://example.com/members/post", Description: strings.Repeat("RSS の説明文。", 20)}
+
+	summary, err := summarizeArticle(context.Background(), gemini, article)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Summary != fullSummary || summary.Title != "記事" {
+		t.Errorf("Expected the description summary, got %+v", summary)
+	}
+
+	// Without a usable description the robots.txt error stands
+	article.Description = "短い"
+	if _, err := summarizeArticle(context.Background(), gemini, article); !errors.Is(err, repository.ErrDisallowedByRobots) {
+		t.Errorf("Expected ErrDisallowedByRobots, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package article
+
+import (
+	"context"
+	"log"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// translationLabels head the translated summary appended to the original
+var translationLabels = map[string]string{
+	repository.SummaryLanguageJapanese: "🌐 **日本語訳:**",
+	repository.SummaryLanguageEnglish:  "🌐 **English:**",
+}
+
+type summaryTranslationKey struct{}
+
+// WithSummaryTranslation asks for the summaries sent under ctx to be delivered with a translation
+// into language as well, overriding the feed's SUMMARY_TRANSLATION_<FEED> (used by on-demand requests)
+func WithSummaryTranslation(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, summaryTranslationKey{}, language)
+}
+
+// SummaryTranslator is a Notifier decorator that appends a translation of the summary before
+// forwarding, so that e.g. an English article reaches the channel in both Japanese and English
+type SummaryTranslator struct {
+	notifier   repository.Notifier
+	geminiRepo repository.GeminiRepository
+	language   string // Target language; empty translates only when asked through WithSummaryTranslation
+}
+
+func NewSummaryTranslator(notifier repository.Notifier, geminiRepo repository.GeminiRepository, language string) *SummaryTranslator {
+	return &SummaryTranslator{
+		notifier:   notifier,
+		geminiRepo: geminiRepo,
+		language:   language,
+	}
+}
+
+// Send appends the translation; a failed translation sends the original summary rather than failing the article
+func (s *SummaryTranslator) Send(ctx context.Context, notification repository.Notification) error {
+	notification.Summary = s.translate(ctx, notification.Summary)
+	return s.notifier.Send(ctx, notification)
+}
+
+func (s *SummaryTranslator) SendOnDemandSummary(ctx context.Context, article repository.Item, summary repository.SummarizeResponse, targetChannel string) error {
+	summary.Summary = s.translate(ctx, summary.Summary)
+	return s.notifier.SendOnDemandSummary(ctx, article, summary, targetChannel)
+}
+
+// Flush forwards to the wrapped notifier when it batches
+func (s *SummaryTranslator) Flush(ctx context.Context) error {
+	if flusher, ok := s.notifier.(repository.Flusher); ok {
+		return flusher.Flush(ctx)
+	}
+	return nil
+}
+
+// translate returns summary followed by its translation into the target language; summary alone
+// when no translation is asked for, the summary is already in that language or translating fails
+func (s *SummaryTranslator) translate(ctx context.Context, summary string) string {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	language := s.language
+	if requested, ok := ctx.Value(summaryTranslationKey{}).(string); ok {
+		language = requested
+	}
+	label, ok := translationLabels[language]
+	if !ok || summary == "" {
+		return summary
+	}
+
+	translation, err := s.geminiRepo.TranslateSummary(ctx, summary, language)
+	if err != nil {
+		logger.Printf("Warning: Failed to translate summary language=%s: %v", language, err)
+		return summary
+	}
+	if translation == summary {
+		return summary
+	}
+	return summary + "\n\n" + label + "\n" + translation
+}
@@ -0,0 +1,87 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+type failingTranslationGemini struct {
+	mocks.MockGeminiRepo
+}
+
+func (f *failingTranslationGemini) TranslateSummary(ctx context.Context, summary, language string) (string, error) {
+	return "", errors.New("quota exceeded")
+}
+
+// sameLanguageGemini returns summaries unchanged, as when they are already in the target language
+type sameLanguageGemini struct {
+	mocks.MockGeminiRepo
+}
+
+func (s *sameLanguageGemini) TranslateSummary(ctx context.Context, summary, language string) (string, error) {
+	return summary, nil
+}
+
+func TestSummaryTranslator_Send(t *testing.T) {
+	notifier := &mocks.MockSlackRepo{}
+	translator := NewSummaryTranslator(notifier, &mocks.MockGeminiRepo{}, repository.SummaryLanguageEnglish)
+
+	if err := translator.Send(context.Background(), repository.Notification{Title: "記事", Summary: "要約"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "要約\n\n🌐 **English:**\ntest translation"
+	if len(notifier.SentNotifications) != 1 || notifier.SentNotifications[0].Summary != want {
+		t.Errorf("Expected summary with translation %q, got %+v", want, notifier.SentNotifications)
+	}
+}
+
+func TestSummaryTranslator_RequestedLanguage(t *testing.T) {
+	notifier := &mocks.MockSlackRepo{}
+	// On-demand notifiers have no configured language and translate only when asked
+	translator := NewSummaryTranslator(notifier, &mocks.MockGeminiRepo{}, "")
+	article := repository.Item{Title: "Article", Link: "https://example.com/a", Source: repository.SourceOnDemand}
+
+	if err := translator.SendOnDemandSummary(context.Background(), article, repository.SummarizeResponse{Summary: "summary"}, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := WithSummaryTranslation(context.Background(), repository.SummaryLanguageJapanese)
+	if err := translator.SendOnDemandSummary(ctx, article, repository.SummarizeResponse{Summary: "summary"}, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sent := notifier.SentOnDemandSummaries
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(sent))
+	}
+	if sent[0].Summary != "summary" {
+		t.Errorf("Expected no translation without a request, got %q", sent[0].Summary)
+	}
+	if want := "summary\n\n🌐 **日本語訳:**\ntest translation"; sent[1].Summary != want {
+		t.Errorf("Expected %q, got %q", want, sent[1].Summary)
+	}
+}
+
+func TestSummaryTranslator_KeepsOriginal(t *testing.T) {
+	tests := map[string]repository.GeminiRepository{
+		"translation failure": &failingTranslationGemini{},
+		"same language":       &sameLanguageGemini{},
+	}
+	for name, gemini := range tests {
+		t.Run(name, func(t *testing.T) {
+			notifier := &mocks.MockSlackRepo{}
+			translator := NewSummaryTranslator(notifier, gemini, repository.SummaryLanguageEnglish)
+
+			if err := translator.Send(context.Background(), repository.Notification{Title: "Article", Summary: "summary"}); err != nil {
+				t.Fatalf("Translation problems should not fail the notification, got %v", err)
+			}
+			if len(notifier.SentNotifications) != 1 || notifier.SentNotifications[0].Summary != "summary" {
+				t.Errorf("Expected the original summary, got %+v", notifier.SentNotifications)
+			}
+		})
+	}
+}
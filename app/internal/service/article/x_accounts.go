@@ -0,0 +1,140 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+// URLProcessor summarizes and posts a URL through the on-demand (webhook) pipeline
+type URLProcessor interface {
+	Process(ctx context.Context, url string) error
+}
+
+// XAccountsProcessor turns the posts of followed X accounts into a feed: every new post linking
+// to an external page sends that page through the webhook pipeline. Posts are deduplicated by
+// their permalink (one per post ID), not by the page they link to.
+type XAccountsProcessor struct {
+	feedRepo      rss.FeedRepository
+	urlProcessor  URLProcessor
+	processedRepo repository.ProcessedArticleRepository
+	backlogRepo   repository.BacklogRepository
+	statsRepo     repository.FeedStatsRepository
+	limiter       limiter.ArticleLimiter
+	concurrency   *limiter.ConcurrencyController
+}
+
+func NewXAccountsProcessor(
+	accounts []string,
+	bearerToken string,
+	bridgeURL string,
+	rssRepo repository.RSSRepository,
+	urlProcessor URLProcessor,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *XAccountsProcessor {
+	return &XAccountsProcessor{
+		feedRepo:      rss.NewXAccountsRepository(rssRepo, accounts, bearerToken, bridgeURL),
+		urlProcessor:  urlProcessor,
+		processedRepo: processedRepo,
+		backlogRepo:   backlogRepo,
+		statsRepo:     statsRepo,
+		limiter:       limiter,
+		concurrency:   concurrency,
+	}
+}
+
+func (p *XAccountsProcessor) Process(ctx context.Context) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	logger.Printf("Process request started feed=x")
+
+	start := time.Now()
+	defer func() {
+		duration := time.Since(start)
+		logger.Printf("Process request completed feed=x duration_ms=%d", duration.Milliseconds())
+	}()
+	defer flushProcessed(ctx, p.processedRepo)
+
+	// 1. データ取得
+	logger.Printf("Feed processing started feed=x")
+	posts, err := p.feedRepo.FetchArticles(ctx)
+	if err != nil {
+		logger.Printf("Error processing feed x: %v", err)
+		return fmt.Errorf("processing feed x: %w", err)
+	}
+
+	// Skip (and alert on) runs whose feed looks corrupted
+	feedStats, err := checkFeedAnomaly(ctx, p.statsRepo, "x", len(posts))
+	if err != nil {
+		return err
+	}
+
+	// Filter unprocessed posts
+	unprocessedPosts, err := filterUnprocessedArticles(ctx, p.processedRepo, "x", posts)
+	if err != nil {
+		return fmt.Errorf("filtering unprocessed articles: %w", err)
+	}
+
+	// Apply article limiting
+	limitedPosts := p.limiter.Limit(ctx, unprocessedPosts)
+
+	logger.Printf("Selected unprocessed posts: %d from x", len(limitedPosts))
+
+	// Process each post
+	var processedCount int32
+	feedRun, err := processArticles(ctx, p.concurrency, nil, limitedPosts, func(ctx context.Context, post repository.Item) error {
+		if err := p.processPost(ctx, post); err != nil {
+			logger.Printf("Error processing post %s: %v", post.Link, err)
+			recordBacklog(ctx, p.backlogRepo, "x", post, err)
+			return fmt.Errorf("processing post %s: %w", post.Link, err)
+		}
+		logger.Printf("Post processed %d/%d post=%s", atomic.AddInt32(&processedCount, 1), len(limitedPosts), post.Link)
+		return nil
+	})
+	feedRun.Items = len(posts)
+	recordFeedRun(ctx, p.statsRepo, feedStats, feedRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Printf("Feed processing completed feed=x processed_count=%d", len(limitedPosts))
+	return nil
+}
+
+// ProcessItem processes a single post outside of the feed run (used by the backlog drain)
+func (p *XAccountsProcessor) ProcessItem(ctx context.Context, post repository.Item) error {
+	defer flushProcessed(ctx, p.processedRepo)
+	return p.processPost(ctx, post)
+}
+
+// processPost sends the linked page through the webhook pipeline, then marks the post as processed
+func (p *XAccountsProcessor) processPost(ctx context.Context, post repository.Item) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	if post.LinkedURL == "" {
+		return fmt.Errorf("post %s has no linked URL", post.Link)
+	}
+
+	logger.Printf("Post processing started post=%s url=%s", post.Link, post.LinkedURL)
+	if err := p.urlProcessor.Process(ctx, post.LinkedURL); err != nil {
+		return fmt.Errorf("processing linked URL %s: %w", post.LinkedURL, err)
+	}
+
+	// インデックス更新
+	if err := p.processedRepo.MarkAsProcessed(ctx, post); err != nil {
+		logger.Printf("Error marking post as processed %s: %v\nStack:\n%s", post.Link, err, debug.Stack())
+		return fmt.Errorf("marking as processed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestXAccountsProcessor_Process(t *testing.T) {
+	urlProcessor := &mocks.MockURLProcessor{}
+	processor := NewXAccountsProcessor([]string{"golang"}, "token", "", &mocks.MockXAccountsRepo{}, urlProcessor, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(urlProcessor.URLs) != 1 || urlProcessor.URLs[0] != "https://example.com/article" {
+		t.Errorf("Expected only the linked article to be processed, got %v", urlProcessor.URLs)
+	}
+}
+
+func TestXAccountsProcessor_ProcessFailureRecordsBacklog(t *testing.T) {
+	urlProcessor := &mocks.MockURLProcessor{Err: errors.New("summarization failed")}
+	backlogRepo := &mocks.MockBacklogRepo{}
+	processor := NewXAccountsProcessor([]string{"golang"}, "token", "", &mocks.MockXAccountsRepo{}, urlProcessor, &mocks.MockProcessedRepo{}, backlogRepo, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil)
+
+	if err := processor.Process(context.Background()); err == nil {
+		t.Fatal("Expected an error when the linked URL fails")
+	}
+	if len(backlogRepo.Entries) != 1 || backlogRepo.Entries[0].Feed != "x" || backlogRepo.Entries[0].Item.LinkedURL != "https://example.com/article" {
+		t.Errorf("Expected the post to be recorded in the backlog, got %+v", backlogRepo.Entries)
+	}
+}
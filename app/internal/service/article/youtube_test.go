@@ -0,0 +1,66 @@
+package article
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestYouTubeProcessor_Process_SummarizesTranscript(t *testing.T) {
+	slackRepo := &mocks.MockSlackRepo{}
+	gemini := &sourceGemini{}
+	processor := NewYouTubeProcessor(
+		[]string{"UCabcdefghijklmnopqrstuv"},
+		&mocks.MockYouTubeRepo{},
+		gemini,
+		slackRepo,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	if err := processor.Process(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The watch URL is read through the transcript fetcher
+	if len(gemini.urls) != 1 || gemini.urls[0] != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
+		t.Errorf("Expected the watch URL to be summarized, got %v", gemini.urls)
+	}
+	if len(slackRepo.SentNotifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(slackRepo.SentNotifications))
+	}
+	if n := slackRepo.SentNotifications[0]; n.Source != "youtube" || len(n.Authors) != 1 || n.Authors[0] != "Test Channel" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+}
+
+func TestSummarizeYouTubeVideo_FallsBackToDescription(t *testing.T) {
+	video := repository.Item{
+		Title:       "Test Video",
+		Link:        "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		Description: "A test video about testing.",
+	}
+
+	gemini := &sourceGemini{urlErr: httperr.Permanent(fmt.Errorf("dQw4w9WgXcQ: %w", repository.ErrNoTranscript))}
+	summary, err := summarizeYouTubeVideo(context.Background(), gemini, video)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Summary != "abstract summary" || !strings.Contains(gemini.textSent, "Video description:\nA test video") {
+		t.Errorf("Expected a description summary, got %q from %q", summary.Summary, gemini.textSent)
+	}
+
+	// Without captions or description there is nothing to summarize
+	video.Description = ""
+	if _, err := summarizeYouTubeVideo(context.Background(), gemini, video); !httperr.IsPermanent(err) {
+		t.Errorf("Expected a permanent error, got %v", err)
+	}
+}
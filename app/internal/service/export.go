@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultExportLimit = 1000
+	maxExportLimit     = 10000
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued by Export
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ExportQuery selects archived summaries published in [From, To)
+type ExportQuery struct {
+	From   time.Time // Zero means from the oldest summary
+	To     time.Time // Zero means up to now
+	Cursor string    // NextCursor of the previous page; empty starts from the beginning
+	Limit  int       // Defaults to 1000, capped at 10000
+}
+
+// ExportPage is one page of an export, oldest first
+type ExportPage struct {
+	Entries []*repository.SummaryFeedEntry
+	// NextCursor continues the export after this page (empty on the last page)
+	NextCursor string
+}
+
+// Export pages through the summary archive for offline analysis and backup
+type Export struct {
+	feedRepo repository.SummaryFeedRepository
+}
+
+func NewExport(feedRepo repository.SummaryFeedRepository) *Export {
+	return &Export{
+		feedRepo: feedRepo,
+	}
+}
+
+// Page returns the summaries of query's range after its cursor. Summaries are ordered by
+// publication time and URL, so a cursor stays valid while newer summaries are added.
+func (e *Export) Page(ctx context.Context, query ExportQuery) (*ExportPage, error) {
+	var after *exportPosition
+	if query.Cursor != "" {
+		position, err := decodeExportCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &position
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultExportLimit
+	}
+	if limit > maxExportLimit {
+		limit = maxExportLimit
+	}
+
+	archived, err := e.feedRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading summaries: %w", err)
+	}
+
+	entries := make([]*repository.SummaryFeedEntry, 0)
+	for _, entry := range archived {
+		if !query.From.IsZero() && entry.PublishedAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && !entry.PublishedAt.Before(query.To) {
+			continue
+		}
+		if after != nil && !after.before(entry) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return positionOf(entries[i]).before(entries[j])
+	})
+
+	page := &ExportPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = positionOf(entries[limit-1]).encode()
+	}
+	return page, nil
+}
+
+// exportPosition is where an export page ended
+type exportPosition struct {
+	publishedAt time.Time
+	url         string
+}
+
+func positionOf(entry *repository.SummaryFeedEntry) exportPosition {
+	return exportPosition{publishedAt: entry.PublishedAt, url: entry.URL}
+}
+
+// before reports whether entry comes after the position
+func (p exportPosition) before(entry *repository.SummaryFeedEntry) bool {
+	if !p.publishedAt.Equal(entry.PublishedAt) {
+		return p.publishedAt.Before(entry.PublishedAt)
+	}
+	return p.url < entry.URL
+}
+
+func (p exportPosition) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(p.publishedAt.UTC().Format(time.RFC3339Nano) + "\n" + p.url))
+}
+
+func decodeExportCursor(cursor string) (exportPosition, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return exportPosition{}, ErrInvalidCursor
+	}
+	rawTime, url, found := strings.Cut(string(data), "\n")
+	publishedAt, err := time.Parse(time.RFC3339Nano, rawTime)
+	if !found || err != nil {
+		return exportPosition{}, ErrInvalidCursor
+	}
+	return exportPosition{publishedAt: publishedAt, url: url}, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestExport_Page(t *testing.T) {
+	base := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	feedRepo := &mocks.MockSummaryFeedRepo{Entries: []*repository.SummaryFeedEntry{
+		// Newest first, as the feed stores them
+		{Title: "D", URL: "https://example.com/d", PublishedAt: base.Add(72 * time.Hour)},
+		{Title: "C", URL: "https://example.com/c", PublishedAt: base.Add(48 * time.Hour)},
+		{Title: "B2", URL: "https://example.com/b2", PublishedAt: base.Add(24 * time.Hour)},
+		{Title: "B1", URL: "https://example.com/b1", PublishedAt: base.Add(24 * time.Hour)},
+		{Title: "A", URL: "https://example.com/a", PublishedAt: base},
+	}}
+	export := NewExport(feedRepo)
+	ctx := context.Background()
+
+	query := ExportQuery{From: base.Add(time.Hour), To: base.Add(72 * time.Hour), Limit: 2}
+	var titles []string
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatal("Expected the export to end")
+		}
+		page, err := export.Page(ctx, query)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, entry := range page.Entries {
+			titles = append(titles, entry.Title)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	// A is before from, D is at the exclusive to; entries at the same time are ordered by URL
+	if got := len(titles); got != 3 || titles[0] != "B1" || titles[1] != "B2" || titles[2] != "C" {
+		t.Errorf("Unexpected export order %v", titles)
+	}
+
+	if _, err := export.Page(ctx, ExportQuery{Cursor: "not a cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
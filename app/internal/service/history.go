@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// HistoryQuery filters the processed-article history
+type HistoryQuery struct {
+	Source string // Exact feed source (e.g. "hatena"); empty matches all
+	Query  string // Case-insensitive substring of title or URL; empty matches all
+	Limit  int    // Defaults to 50, capped at 500
+}
+
+// History serves read-only lookups over the processed-article index
+type History struct {
+	processedRepo repository.ProcessedArticleRepository
+}
+
+func NewHistory(processedRepo repository.ProcessedArticleRepository) *History {
+	return &History{
+		processedRepo: processedRepo,
+	}
+}
+
+// Search returns matching processed articles, most recently processed first
+func (h *History) Search(ctx context.Context, query HistoryQuery) ([]*repository.IndexEntry, error) {
+	index, err := h.processedRepo.LoadIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading index: %w", err)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+	needle := strings.ToLower(query.Query)
+
+	entries := make([]*repository.IndexEntry, 0)
+	for _, entry := range index {
+		if query.Source != "" && historySource(entry.Source) != historySource(query.Source) {
+			continue
+		}
+		if needle != "" && !strings.Contains(strings.ToLower(entry.Title), needle) && !strings.Contains(strings.ToLower(entry.URL), needle) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ProcessedDate.After(entries[j].ProcessedDate)
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// legacySourceOnDemand is the source on-demand articles were recorded under before SourceOnDemand
+const legacySourceOnDemand = "on-demand"
+
+// historySource maps the legacy on-demand source to repository.SourceOnDemand so older entries still match
+func historySource(source string) string {
+	if source == legacySourceOnDemand {
+		return repository.SourceOnDemand
+	}
+	return source
+}
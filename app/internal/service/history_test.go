@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+type indexProcessedRepo struct {
+	mocks.MockProcessedRepo
+	index map[string]*repository.IndexEntry
+}
+
+func (r *indexProcessedRepo) LoadIndex(ctx context.Context) (map[string]*repository.IndexEntry, error) {
+	return r.index, nil
+}
+
+func TestHistory_Search(t *testing.T) {
+	now := time.Now()
+	repo := &indexProcessedRepo{index: map[string]*repository.IndexEntry{
+		"a": {Title: "Go 1.23 released", URL: "https://go.dev/blog", Source: "hatena", ProcessedDate: now.Add(-2 * time.Hour)},
+		"b": {Title: "Rust news", URL: "https://rust-lang.org", Source: "reddit", ProcessedDate: now.Add(-time.Hour)},
+		"c": {Title: "Generics in GO", URL: "https://example.com/go", Source: "hatena", ProcessedDate: now},
+		"d": {Title: "Requested", URL: "https://example.com/d", Source: repository.SourceOnDemand, ProcessedDate: now.Add(-3 * time.Hour)},
+		"e": {Title: "Requested earlier", URL: "https://example.com/e", Source: "on-demand", ProcessedDate: now.Add(-4 * time.Hour)},
+	}}
+	history := NewHistory(repo)
+
+	entries, err := history.Search(context.Background(), HistoryQuery{Source: "hatena", Query: "go"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Title != "Generics in GO" {
+		t.Errorf("Expected 2 hatena entries newest first, got %+v", entries)
+	}
+
+	// Entries recorded under the legacy "on-demand" source match the on-demand filter
+	entries, _ = history.Search(context.Background(), HistoryQuery{Source: repository.SourceOnDemand})
+	if len(entries) != 2 || entries[1].Title != "Requested earlier" {
+		t.Errorf("Expected both on-demand entries, got %+v", entries)
+	}
+
+	entries, _ = history.Search(context.Background(), HistoryQuery{Limit: 1})
+	if len(entries) != 1 || entries[0].Title != "Generics in GO" {
+		t.Errorf("Expected newest entry only, got %+v", entries)
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// LeaderElector decides which of several replicas runs scheduled feeds. Time is cut into slots of
+// the lease TTL, each claimed by exactly one replica; the leader claims the following slot ahead of
+// time, so it keeps the lead while it is alive and another replica takes over within two slots
+// after it stops.
+type LeaderElector struct {
+	repo   repository.LeaseRepository
+	holder string
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	owned map[int64]bool // Slot starts (Unix seconds) held by this replica
+}
+
+func NewLeaderElector(repo repository.LeaseRepository, holder string, ttl time.Duration) *LeaderElector {
+	return &LeaderElector{
+		repo:   repo,
+		holder: holder,
+		ttl:    ttl,
+		now:    time.Now,
+		owned:  make(map[int64]bool),
+	}
+}
+
+// Run renews the lease three times per slot until ctx is canceled
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		e.Renew(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Renew claims the current slot if it is free and, while leading, the next one
+func (e *LeaderElector) Renew(ctx context.Context) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	current := e.now().Truncate(e.ttl)
+	wasLeader := e.IsLeader()
+
+	leading := e.claim(ctx, logger, current)
+	if leading {
+		e.claim(ctx, logger, current.Add(e.ttl))
+	}
+	if leading != wasLeader {
+		logger.Printf("Leadership changed holder=%s leader=%t", e.holder, leading)
+	}
+
+	// Forget slots that have ended
+	e.mu.Lock()
+	for start := range e.owned {
+		if start < current.Unix() {
+			delete(e.owned, start)
+		}
+	}
+	e.mu.Unlock()
+}
+
+// claim reports whether this replica holds the slot starting at start
+func (e *LeaderElector) claim(ctx context.Context, logger *log.Logger, start time.Time) bool {
+	e.mu.Lock()
+	owned := e.owned[start.Unix()]
+	e.mu.Unlock()
+	if owned {
+		return true
+	}
+
+	holder, err := e.repo.Claim(ctx, start, e.holder)
+	if err != nil {
+		logger.Printf("Error claiming leader lease slot=%s: %v", start.UTC().Format(time.RFC3339), err)
+		return false
+	}
+	if holder != e.holder {
+		return false
+	}
+	e.mu.Lock()
+	e.owned[start.Unix()] = true
+	e.mu.Unlock()
+	return true
+}
+
+// IsLeader reports whether this replica holds the current slot
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.owned[e.now().Truncate(e.ttl).Unix()]
+}
+
+// Holder returns this replica's ID in the lease
+func (e *LeaderElector) Holder() string {
+	return e.holder
+}
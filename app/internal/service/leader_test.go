@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestLeaderElector_Failover(t *testing.T) {
+	repo := &mocks.MockLeaseRepo{}
+	now := time.Date(2024, 1, 1, 12, 0, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+	a := NewLeaderElector(repo, "replica-a", 30*time.Second)
+	b := NewLeaderElector(repo, "replica-b", 30*time.Second)
+	a.now, b.now = clock, clock
+	ctx := context.Background()
+
+	a.Renew(ctx)
+	b.Renew(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("Expected replica-a to lead, got a=%t b=%t", a.IsLeader(), b.IsLeader())
+	}
+
+	// The leader claimed the next slot ahead of time, so it keeps the lead
+	now = now.Add(30 * time.Second)
+	b.Renew(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Errorf("Expected replica-a to keep the lead, got a=%t b=%t", a.IsLeader(), b.IsLeader())
+	}
+	a.Renew(ctx)
+
+	// replica-a stops renewing: its claimed slots run out and replica-b takes over
+	now = now.Add(60 * time.Second)
+	b.Renew(ctx)
+	if a.IsLeader() || !b.IsLeader() {
+		t.Errorf("Expected replica-b to take over, got a=%t b=%t", a.IsLeader(), b.IsLeader())
+	}
+}
+
+func TestLeaderElector_StorageErrorFollows(t *testing.T) {
+	repo := &mocks.MockLeaseRepo{Err: errors.New("storage unavailable")}
+	elector := NewLeaderElector(repo, "replica-a", 30*time.Second)
+
+	elector.Renew(context.Background())
+	if elector.IsLeader() {
+		t.Error("Expected no leadership without a lease")
+	}
+}
@@ -0,0 +1,25 @@
+package limiter
+
+import (
+	"context"
+	"log"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// CappedArticleLimiter keeps at most max articles per run (simulation mode)
+type CappedArticleLimiter struct {
+	max int
+}
+
+func NewCappedArticleLimiter(max int) *CappedArticleLimiter {
+	return &CappedArticleLimiter{max: max}
+}
+
+func (l *CappedArticleLimiter) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
+	if len(articles) > l.max {
+		log.Printf("シミュレーション用制限により %d件に制限 (元: %d件)", l.max, len(articles))
+		return articles[:l.max]
+	}
+	return articles
+}
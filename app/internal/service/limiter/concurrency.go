@@ -0,0 +1,121 @@
+package limiter
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+// ConcurrencyController sizes the article worker pool AIMD-style from Gemini feedback:
+// the limit grows by one after a full round of fast successes and halves on a 429 or a slow call.
+type ConcurrencyController struct {
+	mu            sync.Mutex
+	min           int
+	max           int
+	limit         int
+	latencyTarget time.Duration // 0 disables latency-based decrease
+	successes     int           // successes since the last adjustment
+	lastDecrease  time.Time
+
+	// Metrics
+	requests     int
+	rateLimited  int
+	totalLatency time.Duration
+}
+
+// ConcurrencyStats is a snapshot of the controller's metrics
+type ConcurrencyStats struct {
+	Limit       int
+	Requests    int
+	RateLimited int
+	AvgLatency  time.Duration
+}
+
+func NewConcurrencyController(min, max int, latencyTarget time.Duration) *ConcurrencyController {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &ConcurrencyController{
+		min:           min,
+		max:           max,
+		limit:         min,
+		latencyTarget: latencyTarget,
+	}
+}
+
+// Limit returns the current number of articles that may be processed concurrently
+func (c *ConcurrencyController) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// Observe feeds the outcome of one article started at start back into the controller
+func (c *ConcurrencyController) Observe(start time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	latency := time.Since(start)
+	c.requests++
+	c.totalLatency += latency
+
+	rateLimited := errors.Is(err, httperr.ErrRateLimited)
+	if rateLimited {
+		c.rateLimited++
+	}
+
+	switch {
+	case rateLimited || (c.latencyTarget > 0 && latency > c.latencyTarget):
+		// Calls started before the last decrease already reflect the old limit; don't halve twice
+		if start.Before(c.lastDecrease) {
+			return
+		}
+		c.adjust(c.limit/2, rateLimited, latency)
+		c.lastDecrease = time.Now()
+	case err == nil:
+		c.successes++
+		if c.successes >= c.limit {
+			c.adjust(c.limit+1, false, latency)
+		}
+	}
+}
+
+// Stats returns the metrics collected so far
+func (c *ConcurrencyController) Stats() ConcurrencyStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := ConcurrencyStats{
+		Limit:       c.limit,
+		Requests:    c.requests,
+		RateLimited: c.rateLimited,
+	}
+	if c.requests > 0 {
+		stats.AvgLatency = c.totalLatency / time.Duration(c.requests)
+	}
+	return stats
+}
+
+// adjust clamps the new limit to the configured bounds; caller must hold mu
+func (c *ConcurrencyController) adjust(limit int, rateLimited bool, latency time.Duration) {
+	if limit < c.min {
+		limit = c.min
+	}
+	if limit > c.max {
+		limit = c.max
+	}
+	c.successes = 0
+	if limit == c.limit {
+		return
+	}
+
+	log.Printf("Article concurrency adjusted from=%d to=%d rate_limited=%t latency_ms=%d",
+		c.limit, limit, rateLimited, latency.Milliseconds())
+	c.limit = limit
+}
@@ -0,0 +1,79 @@
+package limiter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository/httperr"
+)
+
+func TestConcurrencyController_AdditiveIncrease(t *testing.T) {
+	c := NewConcurrencyController(1, 3, time.Minute)
+
+	// One success at limit 1 grows to 2, two more at limit 2 grow to 3
+	for i := 0; i < 3; i++ {
+		c.Observe(time.Now(), nil)
+	}
+	if c.Limit() != 3 {
+		t.Errorf("Expected limit 3, got %d", c.Limit())
+	}
+
+	// Capped at max
+	for i := 0; i < 10; i++ {
+		c.Observe(time.Now(), nil)
+	}
+	if c.Limit() != 3 {
+		t.Errorf("Expected limit to stay at max 3, got %d", c.Limit())
+	}
+}
+
+func TestConcurrencyController_MultiplicativeDecrease(t *testing.T) {
+	c := NewConcurrencyController(1, 8, 0)
+	for i := 0; i < 100 && c.Limit() < 8; i++ {
+		c.Observe(time.Now(), nil)
+	}
+	if c.Limit() != 8 {
+		t.Fatalf("Expected limit 8, got %d", c.Limit())
+	}
+
+	start := time.Now()
+	rateLimitErr := fmt.Errorf("summarizing article: %w", httperr.ErrRateLimited)
+	c.Observe(start, rateLimitErr)
+	if c.Limit() != 4 {
+		t.Errorf("Expected limit halved to 4, got %d", c.Limit())
+	}
+
+	// Concurrent calls started before the decrease must not halve again
+	c.Observe(start, rateLimitErr)
+	if c.Limit() != 4 {
+		t.Errorf("Expected limit to stay at 4, got %d", c.Limit())
+	}
+
+	stats := c.Stats()
+	if stats.RateLimited != 2 || stats.Requests < 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestConcurrencyController_LatencyAndErrors(t *testing.T) {
+	c := NewConcurrencyController(2, 4, 10*time.Millisecond)
+	c.Observe(time.Now(), nil)
+	c.Observe(time.Now(), nil)
+	if c.Limit() != 3 {
+		t.Fatalf("Expected limit 3, got %d", c.Limit())
+	}
+
+	// Slow call halves, clamped to min
+	c.Observe(time.Now().Add(-time.Second), nil)
+	if c.Limit() != 2 {
+		t.Errorf("Expected limit clamped to min 2, got %d", c.Limit())
+	}
+
+	// Non rate-limit errors are neutral
+	c.Observe(time.Now(), errors.New("parse error"))
+	if c.Limit() != 2 {
+		t.Errorf("Expected limit unchanged, got %d", c.Limit())
+	}
+}
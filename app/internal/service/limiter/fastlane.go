@@ -0,0 +1,106 @@
+package limiter
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// FastLane lets breaking news jump the queue: items of high-priority feeds, or whose titles contain
+// a keyword, are marked Breaking and run first, past the wrapped limiter's cap. Only items published
+// within window qualify, so a feed's old items are never posted as breaking news.
+type FastLane struct {
+	next     ArticleLimiter
+	feeds    []string
+	keywords []string // Lower-cased
+	window   time.Duration
+}
+
+func NewFastLane(next ArticleLimiter, feeds, keywords []string, window time.Duration) *FastLane {
+	lowered := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		lowered[i] = strings.ToLower(keyword)
+	}
+	return &FastLane{
+		next:     next,
+		feeds:    feeds,
+		keywords: lowered,
+		window:   window,
+	}
+}
+
+// Matches reports whether an item is breaking news as of the context's clock; items without a
+// publication date count as fresh
+func (f *FastLane) Matches(ctx context.Context, article repository.Item) bool {
+	if !article.ParsedDate.IsZero() && repository.Now(ctx).Sub(article.ParsedDate) > f.window {
+		return false
+	}
+	for _, feed := range f.feeds {
+		// OPML feeds are sourced "opml:<slug>"
+		if article.Source == feed || strings.HasPrefix(article.Source, feed+":") {
+			return true
+		}
+	}
+	title := strings.ToLower(article.Title)
+	for _, keyword := range f.keywords {
+		if strings.Contains(title, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit puts breaking items first and passes the rest to the wrapped limiter; in a breaking-only
+// run (outside the feed's active window) the rest is left for the next regular run
+func (f *FastLane) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
+	var breaking, rest []repository.Item
+	for _, article := range articles {
+		if f.Matches(ctx, article) {
+			article.Breaking = true
+			breaking = append(breaking, article)
+		} else {
+			rest = append(rest, article)
+		}
+	}
+	if len(breaking) > 0 {
+		log.Printf("速報レーン: %d件を優先処理", len(breaking))
+	}
+
+	if BreakingOnly(ctx) {
+		return breaking
+	}
+	return append(breaking, f.next.Limit(ctx, rest)...)
+}
+
+// OutsideWindow decides whether a feed runs outside its active window: a high-priority feed runs
+// as usual, and with keyword rules the run is limited to breaking items (see WithBreakingOnly).
+// ok is false when the run should be skipped; a nil FastLane always skips.
+func (f *FastLane) OutsideWindow(ctx context.Context, feed string) (context.Context, bool) {
+	switch {
+	case f == nil:
+		return ctx, false
+	case slices.Contains(f.feeds, feed):
+		return ctx, true
+	case len(f.keywords) > 0:
+		return WithBreakingOnly(ctx), true
+	default:
+		return ctx, false
+	}
+}
+
+type breakingOnlyKey struct{}
+
+// WithBreakingOnly marks a run that may only process breaking items
+func WithBreakingOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, breakingOnlyKey{}, true)
+}
+
+// BreakingOnly reports whether the run may only process breaking items
+func BreakingOnly(ctx context.Context) bool {
+	only, _ := ctx.Value(breakingOnlyKey{}).(bool)
+	return only
+}
@@ -0,0 +1,65 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestFastLane_Limit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lane := NewFastLane(NewTestArticleLimiter(), []string{"opml"}, []string{"CVE-"}, 2*time.Hour)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(now))
+
+	articles := []repository.Item{
+		{Title: "Weekly roundup", Source: "hatena", ParsedDate: now.Add(-time.Hour)},
+		{Title: "Second roundup", Source: "hatena", ParsedDate: now.Add(-time.Hour)},
+		{Title: "Patch for cve-2024-0001 released", Source: "hatena", ParsedDate: now.Add(-time.Hour)},
+		{Title: "Old CVE-2023-9999 writeup", Source: "hatena", ParsedDate: now.Add(-3 * time.Hour)},
+		{Title: "Release notes", Source: "opml:status-page"},
+	}
+
+	limited := lane.Limit(ctx, articles)
+	// Both breaking items come first and are exempt from the 1-article cap of the wrapped limiter
+	if len(limited) != 3 {
+		t.Fatalf("Expected 2 breaking items and 1 regular item, got %d", len(limited))
+	}
+	if limited[0].Title != "Patch for cve-2024-0001 released" || limited[1].Source != "opml:status-page" {
+		t.Errorf("Expected breaking items first, got %q and %q", limited[0].Title, limited[1].Title)
+	}
+	if !limited[0].Breaking || !limited[1].Breaking || limited[2].Breaking {
+		t.Errorf("Expected only the breaking items to be marked, got %+v", limited)
+	}
+	if limited[2].Title != "Weekly roundup" {
+		t.Errorf("Expected the regular item from the wrapped limiter, got %q", limited[2].Title)
+	}
+
+	limited = lane.Limit(WithBreakingOnly(ctx), articles)
+	if len(limited) != 2 {
+		t.Errorf("Expected only breaking items in a breaking-only run, got %d", len(limited))
+	}
+}
+
+func TestFastLane_OutsideWindow(t *testing.T) {
+	ctx := context.Background()
+
+	var disabled *FastLane
+	if _, ok := disabled.OutsideWindow(ctx, "hatena"); ok {
+		t.Error("Expected a nil fast lane to skip runs outside the window")
+	}
+
+	lane := NewFastLane(NewProductionArticleLimiter(), []string{"reddit"}, nil, time.Hour)
+	if runCtx, ok := lane.OutsideWindow(ctx, "reddit"); !ok || BreakingOnly(runCtx) {
+		t.Error("Expected a high-priority feed to run in full")
+	}
+	if _, ok := lane.OutsideWindow(ctx, "hatena"); ok {
+		t.Error("Expected other feeds to skip without keyword rules")
+	}
+
+	lane = NewFastLane(NewProductionArticleLimiter(), nil, []string{"outage"}, time.Hour)
+	if runCtx, ok := lane.OutsideWindow(ctx, "hatena"); !ok || !BreakingOnly(runCtx) {
+		t.Error("Expected a breaking-only run with keyword rules")
+	}
+}
@@ -1,8 +1,12 @@
 package limiter
 
-import "github.com/pep299/article-summarizer-v3/internal/repository"
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
 
 // ArticleLimiter defines the interface for limiting articles before processing
 type ArticleLimiter interface {
-	Limit(articles []repository.Item) []repository.Item
+	Limit(ctx context.Context, articles []repository.Item) []repository.Item
 }
@@ -1,6 +1,10 @@
 package limiter
 
-import "github.com/pep299/article-summarizer-v3/internal/repository"
+import (
+	"context"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
 
 // ProductionArticleLimiter returns all articles without any limiting
 type ProductionArticleLimiter struct{}
@@ -9,6 +13,6 @@ func NewProductionArticleLimiter() *ProductionArticleLimiter {
 	return &ProductionArticleLimiter{}
 }
 
-func (l *ProductionArticleLimiter) Limit(articles []repository.Item) []repository.Item {
+func (l *ProductionArticleLimiter) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
 	return articles
 }
@@ -0,0 +1,59 @@
+package limiter
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// ShardScheduler runs a feed again later
+type ShardScheduler interface {
+	Schedule(ctx context.Context, feed string, delay time.Duration) error
+}
+
+// Sharder splits a burst of new items (e.g. after a feed's outage) into shards: a run processes the
+// newest size items and schedules a follow-up run of the feed after interval, which picks up the
+// next shard since processed items are skipped
+type Sharder struct {
+	next      ArticleLimiter
+	size      int
+	interval  time.Duration
+	scheduler ShardScheduler
+}
+
+func NewSharder(next ArticleLimiter, size int, interval time.Duration, scheduler ShardScheduler) *Sharder {
+	return &Sharder{
+		next:      next,
+		size:      size,
+		interval:  interval,
+		scheduler: scheduler,
+	}
+}
+
+func (s *Sharder) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
+	if len(articles) <= s.size {
+		return s.next.Limit(ctx, articles)
+	}
+
+	newest := slices.Clone(articles)
+	slices.SortStableFunc(newest, func(a, b repository.Item) int {
+		return b.ParsedDate.Compare(a.ParsedDate)
+	})
+	feed := shardFeed(newest[0].Source)
+	log.Printf("シャード分割: feed=%s %d件中%d件を処理し、残りは%s後に処理", feed, len(articles), s.size, s.interval)
+	if err := s.scheduler.Schedule(ctx, feed, s.interval); err != nil {
+		// The next regular run picks up the rest
+		log.Printf("Error scheduling follow-up shard feed=%s: %v", feed, err)
+	}
+	return s.next.Limit(ctx, newest[:s.size])
+}
+
+// shardFeed is the feed that produced an item of source; OPML feeds are sourced "opml:<slug>"
+func shardFeed(source string) string {
+	feed, _, _ := strings.Cut(source, ":")
+	return feed
+}
@@ -0,0 +1,48 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+type recordingScheduler struct {
+	feeds []string
+	delay time.Duration
+}
+
+func (s *recordingScheduler) Schedule(ctx context.Context, feed string, delay time.Duration) error {
+	s.feeds = append(s.feeds, feed)
+	s.delay = delay
+	return nil
+}
+
+func TestSharder_Limit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := &recordingScheduler{}
+	sharder := NewSharder(NewProductionArticleLimiter(), 2, 10*time.Minute, scheduler)
+
+	small := []repository.Item{{Title: "a", Source: "hatena"}, {Title: "b", Source: "hatena"}}
+	if limited := sharder.Limit(context.Background(), small); len(limited) != 2 || len(scheduler.feeds) != 0 {
+		t.Fatalf("Expected runs within the shard size to pass through unscheduled, got %d items and %v", len(limited), scheduler.feeds)
+	}
+
+	burst := []repository.Item{
+		{Title: "oldest", Source: "opml:status-page", ParsedDate: now.Add(-3 * time.Hour)},
+		{Title: "newest", Source: "opml:status-page", ParsedDate: now},
+		{Title: "older", Source: "opml:blog", ParsedDate: now.Add(-2 * time.Hour)},
+		{Title: "newer", Source: "opml:blog", ParsedDate: now.Add(-time.Hour)},
+	}
+	limited := sharder.Limit(context.Background(), burst)
+	if len(limited) != 2 || limited[0].Title != "newest" || limited[1].Title != "newer" {
+		t.Errorf("Expected the newest shard, got %+v", limited)
+	}
+	if len(scheduler.feeds) != 1 || scheduler.feeds[0] != "opml" || scheduler.delay != 10*time.Minute {
+		t.Errorf("Expected one follow-up of opml after 10m, got %v after %s", scheduler.feeds, scheduler.delay)
+	}
+	if burst[0].Title != "oldest" {
+		t.Error("Expected the input order to be left alone")
+	}
+}
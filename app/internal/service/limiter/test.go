@@ -1,6 +1,7 @@
 package limiter
 
 import (
+	"context"
 	"log"
 
 	"github.com/pep299/article-summarizer-v3/internal/repository"
@@ -13,7 +14,7 @@ func NewTestArticleLimiter() *TestArticleLimiter {
 	return &TestArticleLimiter{}
 }
 
-func (l *TestArticleLimiter) Limit(articles []repository.Item) []repository.Item {
+func (l *TestArticleLimiter) Limit(ctx context.Context, articles []repository.Item) []repository.Item {
 	if len(articles) == 0 {
 		return articles
 	}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// On-demand queue stages besides the processing stages reported by the repository layer
+const (
+	StageQueued = "queued"
+	StageDone   = "done"
+	StageError  = "error"
+)
+
+// OnDemandEvent is a progress update for a queued on-demand request
+type OnDemandEvent struct {
+	Stage    string `json:"stage"`              // queued, fetching, extracting, summarizing, posting, done or error
+	Position int    `json:"position,omitempty"` // Requests ahead in the queue, including this one (queued only)
+	Message  string `json:"message,omitempty"`  // Error message (error only)
+}
+
+// OnDemandQueue runs on-demand requests on a bounded number of workers and reports each
+// request's progress, so long-running summaries can show live status
+type OnDemandQueue struct {
+	urlService *URL
+	slots      chan struct{}
+
+	mu      sync.Mutex
+	waiting int
+}
+
+func NewOnDemandQueue(urlService *URL, workers int) *OnDemandQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &OnDemandQueue{
+		urlService: urlService,
+		slots:      make(chan struct{}, workers),
+	}
+}
+
+// Submit queues url and returns its progress events. The channel is closed after the final
+// done or error event; canceling ctx (e.g. the client disconnecting) abandons the request.
+func (q *OnDemandQueue) Submit(ctx context.Context, url string) <-chan OnDemandEvent {
+	events := make(chan OnDemandEvent, 8)
+	send := func(event OnDemandEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	q.mu.Lock()
+	q.waiting++
+	position := q.waiting
+	q.mu.Unlock()
+
+	go func() {
+		defer close(events)
+		logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+		send(OnDemandEvent{Stage: StageQueued, Position: position})
+		select {
+		case q.slots <- struct{}{}:
+			q.dequeue()
+		case <-ctx.Done():
+			q.dequeue()
+			logger.Printf("Queued on-demand request abandoned url=%s: %v", url, ctx.Err())
+			return
+		}
+		defer func() { <-q.slots }()
+
+		progressCtx := repository.WithProgress(ctx, func(stage string) {
+			send(OnDemandEvent{Stage: stage})
+		})
+		if err := q.urlService.Process(progressCtx, url); err != nil {
+			send(OnDemandEvent{Stage: StageError, Message: err.Error()})
+			return
+		}
+		send(OnDemandEvent{Stage: StageDone})
+	}()
+	return events
+}
+
+func (q *OnDemandQueue) dequeue() {
+	q.mu.Lock()
+	q.waiting--
+	q.mu.Unlock()
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// stagedGemini reports the repository's on-demand stages, optionally signaling started and waiting for release first
+type stagedGemini struct {
+	mocks.MockGeminiRepo
+	started chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (g *stagedGemini) SummarizeURLForOnDemand(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	if g.release != nil {
+		g.started <- struct{}{}
+		<-g.release
+	}
+	repository.ReportProgress(ctx, repository.ProgressFetching)
+	repository.ReportProgress(ctx, repository.ProgressExtracting)
+	repository.ReportProgress(ctx, repository.ProgressSummarizing)
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &repository.SummarizeResponse{Summary: "test summary", Title: "Title"}, nil
+}
+
+func collectStages(events <-chan OnDemandEvent) []string {
+	var stages []string
+	for event := range events {
+		stages = append(stages, event.Stage)
+	}
+	return stages
+}
+
+func TestOnDemandQueue_Submit(t *testing.T) {
+	queue := NewOnDemandQueue(NewURL(&stagedGemini{}, &mocks.MockSlackRepo{}), 1)
+
+	stages := collectStages(queue.Submit(context.Background(), "https://example.com/article"))
+
+	if got := strings.Join(stages, ","); got != "queued,fetching,extracting,summarizing,posting,done" {
+		t.Errorf("Unexpected stages: %s", got)
+	}
+}
+
+func TestOnDemandQueue_SubmitError(t *testing.T) {
+	queue := NewOnDemandQueue(NewURL(&stagedGemini{err: errors.New("fetching HTML: unexpected status code: 403")}, &mocks.MockSlackRepo{}), 1)
+
+	var last OnDemandEvent
+	for event := range queue.Submit(context.Background(), "https://example.com/article") {
+		last = event
+	}
+	if last.Stage != StageError || !strings.Contains(last.Message, "403") {
+		t.Errorf("Expected final error event, got %+v", last)
+	}
+}
+
+func TestOnDemandQueue_WaitsForWorker(t *testing.T) {
+	gemini := &stagedGemini{started: make(chan struct{}, 2), release: make(chan struct{})}
+	queue := NewOnDemandQueue(NewURL(gemini, &mocks.MockSlackRepo{}), 1)
+
+	first := queue.Submit(context.Background(), "https://example.com/first")
+	if event := <-first; event.Stage != StageQueued || event.Position != 1 {
+		t.Fatalf("Unexpected first event: %+v", event)
+	}
+	<-gemini.started // The first request holds the only worker until released
+
+	second := queue.Submit(context.Background(), "https://example.com/second")
+	if event := <-second; event.Stage != StageQueued || event.Position != 1 {
+		t.Fatalf("Unexpected second event: %+v", event)
+	}
+	select {
+	case event := <-second:
+		t.Fatalf("Expected the second request to wait for the worker, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gemini.release)
+	collectStages(first)
+	if stages := collectStages(second); stages[len(stages)-1] != StageDone {
+		t.Errorf("Expected the queued request to finish, got %v", stages)
+	}
+}
+
+func TestOnDemandQueue_CanceledWhileQueued(t *testing.T) {
+	gemini := &stagedGemini{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(gemini.release)
+	queue := NewOnDemandQueue(NewURL(gemini, &mocks.MockSlackRepo{}), 1)
+	queue.Submit(context.Background(), "https://example.com/first")
+	<-gemini.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	second := queue.Submit(ctx, "https://example.com/second")
+	<-second // queued
+	cancel()
+
+	if stages := collectStages(second); len(stages) != 0 {
+		t.Errorf("Expected no further events after cancellation, got %v", stages)
+	}
+}
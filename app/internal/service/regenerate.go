@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// RegenerateOptions selects the archived summaries to regenerate
+type RegenerateOptions struct {
+	Days   int  // Summaries archived within this many days
+	All    bool // Also regenerate summaries already made with the current prompt templates
+	Limit  int  // Maximum number of summaries to regenerate (0 means no limit)
+	Post   bool // Post the regenerated summaries through their feed's notifier
+	DryRun bool // Only count the summaries that would be regenerated
+}
+
+// RegenerateResult counts the archived summaries of a regeneration run
+type RegenerateResult struct {
+	Checked     int // Archived within the window
+	Outdated    int // Selected for regeneration
+	Regenerated int
+	Posted      int
+	Failed      int
+}
+
+// SummaryRegenerator re-summarizes recent archived articles after a prompt template upgrade, so the
+// new prompts can be compared with the old ones on real content. The archive keeps the replaced
+// summary next to the new one.
+type SummaryRegenerator struct {
+	archiveRepo repository.SummaryArchiveRepository
+	geminiRepo  repository.GeminiRepository
+	notifiers   map[string]repository.Notifier // By feed (entry source); used with RegenerateOptions.Post
+}
+
+func NewSummaryRegenerator(archiveRepo repository.SummaryArchiveRepository, geminiRepo repository.GeminiRepository, notifiers map[string]repository.Notifier) *SummaryRegenerator {
+	return &SummaryRegenerator{
+		archiveRepo: archiveRepo,
+		geminiRepo:  geminiRepo,
+		notifiers:   notifiers,
+	}
+}
+
+// Run regenerates the selected summaries one by one. A failed article is logged and counted, and
+// the run goes on; the returned error joins the failures.
+func (r *SummaryRegenerator) Run(ctx context.Context, opts RegenerateOptions) (RegenerateResult, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	var result RegenerateResult
+
+	since := repository.Now(ctx).AddDate(0, 0, -opts.Days)
+	entries, err := r.archiveRepo.List(ctx, since)
+	if err != nil {
+		return result, err
+	}
+	result.Checked = len(entries)
+
+	var errs []error
+	for _, entry := range entries {
+		if !opts.All && !repository.PromptOutdated(entry.Provenance) {
+			continue
+		}
+		if opts.Limit > 0 && result.Outdated >= opts.Limit {
+			break
+		}
+		result.Outdated++
+		if opts.DryRun {
+			logger.Printf("Would regenerate summary url=%s source=%s prompt=%s", entry.Link, entry.Source, promptOf(entry.Provenance))
+			continue
+		}
+
+		posted, err := r.regenerate(ctx, entry, opts.Post)
+		if err != nil {
+			logger.Printf("Error regenerating summary url=%s: %v", entry.Link, err)
+			result.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Link, err))
+			continue
+		}
+		result.Regenerated++
+		if posted {
+			result.Posted++
+		}
+	}
+
+	if opts.Post {
+		// Batched notifiers (email digests, channel outboxes) deliver at the end of a run
+		for feed, notifier := range r.notifiers {
+			if flusher, ok := notifier.(repository.Flusher); ok {
+				if err := flusher.Flush(ctx); err != nil {
+					logger.Printf("Warning: Failed to flush notifications feed=%s: %v", feed, err)
+				}
+			}
+		}
+	}
+
+	logger.Printf("Summary regeneration completed checked=%d outdated=%d regenerated=%d posted=%d failed=%d dry_run=%t",
+		result.Checked, result.Outdated, result.Regenerated, result.Posted, result.Failed, opts.DryRun)
+	return result, errors.Join(errs...)
+}
+
+// regenerate summarizes the entry's article again and archives the new summary in place of the old one
+func (r *SummaryRegenerator) regenerate(ctx context.Context, entry *repository.SummaryArchiveEntry, post bool) (bool, error) {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+
+	// On-demand summaries were made with the detailed prompt
+	summarize := r.geminiRepo.SummarizeURL
+	if entry.Source == repository.SourceOnDemand {
+		summarize = r.geminiRepo.SummarizeOnDemand
+	}
+	summary, err := summarize(ctx, entry.Link)
+	if err != nil {
+		return false, fmt.Errorf("summarizing: %w", err)
+	}
+	logger.Printf("Summary regenerated url=%s previous_prompt=%s prompt=%s", entry.Link, promptOf(entry.Provenance), promptOf(summary.Provenance))
+
+	posted := false
+	if notifier := r.notifiers[entry.Source]; post && notifier != nil {
+		// Posted first: the archive mirror of the notifier replaces the entry, which is then updated below
+		if err := notifier.Send(ctx, repository.Notification{
+			Title:         entry.Title,
+			OriginalTitle: entry.OriginalTitle,
+			Source:        entry.Source,
+			URL:           entry.Link,
+			Summary:       summary.Summary,
+			ContentChars:  summary.ContentChars,
+			PromptVariant: summary.PromptVariant,
+			Provenance:    summary.Provenance,
+			Glossary:      summary.Glossary,
+			Difficulty:    summary.Difficulty,
+		}); err != nil {
+			return false, fmt.Errorf("posting: %w", err)
+		}
+		posted = true
+	} else if post {
+		logger.Printf("No notifier for source=%s, not posting url=%s", entry.Source, entry.Link)
+	}
+
+	regenerated := *entry
+	regenerated.PreviousSummary = entry.Summary
+	regenerated.PreviousProvenance = entry.Provenance
+	regenerated.Summary = summary.Summary
+	regenerated.Provenance = summary.Provenance
+	regenerated.PromptVariant = summary.PromptVariant
+	if summary.Difficulty != "" {
+		regenerated.Difficulty = summary.Difficulty
+	}
+	regenerated.RegeneratedAt = repository.Now(ctx)
+	if err := r.archiveRepo.Update(ctx, &regenerated); err != nil {
+		return posted, fmt.Errorf("archiving: %w", err)
+	}
+	return posted, nil
+}
+
+// promptOf formats the prompt of a provenance for logs ("unknown" for summaries without one)
+func promptOf(p *repository.Provenance) string {
+	if p == nil {
+		return "unknown"
+	}
+	return p.Prompt
+}
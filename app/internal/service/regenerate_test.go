@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// versionedGemini answers with the current prompt template versions
+type versionedGemini struct {
+	mocks.MockGeminiRepo
+	urlCalls      int
+	onDemandCalls int
+}
+
+func (g *versionedGemini) SummarizeURL(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	g.urlCalls++
+	return &repository.SummarizeResponse{Summary: "new summary", Provenance: &repository.Provenance{Prompt: "rss:default@v1"}}, nil
+}
+
+func (g *versionedGemini) SummarizeOnDemand(ctx context.Context, url string) (*repository.SummarizeResponse, error) {
+	g.onDemandCalls++
+	return &repository.SummarizeResponse{Summary: "new detailed summary", Provenance: &repository.Provenance{Prompt: "ondemand@v1"}}, nil
+}
+
+func regenerateArchive(now time.Time) *mocks.MockSummaryArchiveRepo {
+	return &mocks.MockSummaryArchiveRepo{Entries: map[string]*repository.SummaryArchiveEntry{
+		"https://example.com/legacy": {Link: "https://example.com/legacy", Source: "hatena", Summary: "legacy summary", ArchivedAt: now.Add(-time.Hour)},
+		"https://example.com/old":    {Link: "https://example.com/old", Source: "ondemand", Summary: "old summary", Provenance: &repository.Provenance{Prompt: "ondemand@v0"}, ArchivedAt: now.Add(-2 * time.Hour)},
+		"https://example.com/fresh":  {Link: "https://example.com/fresh", Source: "hatena", Summary: "fresh summary", Provenance: &repository.Provenance{Prompt: "rss:default@v1"}, ArchivedAt: now.Add(-3 * time.Hour)},
+		"https://example.com/stale":  {Link: "https://example.com/stale", Source: "hatena", Summary: "stale summary", ArchivedAt: now.AddDate(0, 0, -30)},
+	}}
+}
+
+func TestSummaryRegenerator_Run(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(now))
+	archive := regenerateArchive(now)
+	gemini := &versionedGemini{}
+	slack := &mocks.MockSlackRepo{}
+	regenerator := NewSummaryRegenerator(archive, gemini, map[string]repository.Notifier{"hatena": slack})
+
+	result, err := regenerator.Run(ctx, RegenerateOptions{Days: 7})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Checked != 3 || result.Outdated != 2 || result.Regenerated != 2 || result.Posted != 0 {
+		t.Errorf("Expected the 2 outdated summaries within the window to be regenerated, got %+v", result)
+	}
+	if gemini.urlCalls != 1 || gemini.onDemandCalls != 1 {
+		t.Errorf("Expected one feed and one on-demand summary, got url=%d ondemand=%d", gemini.urlCalls, gemini.onDemandCalls)
+	}
+	if len(slack.SentNotifications) != 0 {
+		t.Errorf("Expected nothing posted without -post, got %+v", slack.SentNotifications)
+	}
+
+	entry := archive.Entries["https://example.com/old"]
+	if entry.Summary != "new detailed summary" || entry.PreviousSummary != "old summary" || entry.PreviousProvenance.Prompt != "ondemand@v0" {
+		t.Errorf("Expected the previous summary to be kept, got %+v", entry)
+	}
+	if !entry.RegeneratedAt.Equal(now) || !entry.ArchivedAt.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("Unexpected timestamps %+v", entry)
+	}
+	if archive.Entries["https://example.com/fresh"].PreviousSummary != "" {
+		t.Error("Expected the up to date summary to be left alone")
+	}
+}
+
+func TestSummaryRegenerator_RunOptions(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ctx := repository.WithClock(context.Background(), repository.FixedClock(now))
+
+	// A dry run only counts
+	archive := regenerateArchive(now)
+	gemini := &versionedGemini{}
+	result, err := NewSummaryRegenerator(archive, gemini, nil).Run(ctx, RegenerateOptions{Days: 7, DryRun: true})
+	if err != nil || result.Outdated != 2 || result.Regenerated != 0 || gemini.urlCalls+gemini.onDemandCalls != 0 || len(archive.Updated) != 0 {
+		t.Errorf("Expected a dry run without changes, got %+v calls=%d err=%v", result, gemini.urlCalls+gemini.onDemandCalls, err)
+	}
+
+	// -all with a limit, posted through the feed's notifier
+	archive = regenerateArchive(now)
+	slack := &mocks.MockSlackRepo{}
+	result, err = NewSummaryRegenerator(archive, &versionedGemini{}, map[string]repository.Notifier{"hatena": slack}).Run(ctx, RegenerateOptions{Days: 7, All: true, Limit: 2, Post: true})
+	if err != nil || result.Outdated != 2 || result.Regenerated != 2 || result.Posted != 1 {
+		t.Errorf("Expected the 2 newest summaries regenerated and the feed one posted, got %+v err=%v", result, err)
+	}
+	if len(slack.SentNotifications) != 1 || slack.SentNotifications[0].URL != "https://example.com/legacy" || slack.SentNotifications[0].Summary != "new summary" {
+		t.Errorf("Unexpected posts %+v", slack.SentNotifications)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// FeedRunner runs one feed (the Process method of the feed processors)
+type FeedRunner interface {
+	Process(ctx context.Context) error
+}
+
+// InternalShardScheduler runs follow-up shards of a feed in this process with timers. A feed has at
+// most one pending follow-up; pending runs are lost if the instance stops, so the rest of the burst
+// then waits for the feed's next scheduled run.
+type InternalShardScheduler struct {
+	mu      sync.Mutex
+	runners map[string]FeedRunner
+	pending map[string]*time.Timer
+	closed  bool
+}
+
+func NewInternalShardScheduler() *InternalShardScheduler {
+	return &InternalShardScheduler{
+		runners: make(map[string]FeedRunner),
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+// Register sets the runner of a feed's follow-up shards
+func (s *InternalShardScheduler) Register(feed string, runner FeedRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runners[feed] = runner
+}
+
+// Schedule runs feed after delay, unless a follow-up is already pending
+func (s *InternalShardScheduler) Schedule(ctx context.Context, feed string, delay time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runner, ok := s.runners[feed]
+	if !ok {
+		return fmt.Errorf("no runner registered for feed %s", feed)
+	}
+	if s.closed || s.pending[feed] != nil {
+		return nil
+	}
+	s.pending[feed] = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		delete(s.pending, feed)
+		s.mu.Unlock()
+
+		log.Printf("Follow-up shard started feed=%s", feed)
+		if err := runner.Process(context.Background()); err != nil {
+			log.Printf("Error processing follow-up shard feed=%s: %v", feed, err)
+		}
+	})
+	return nil
+}
+
+// Close cancels pending follow-ups
+func (s *InternalShardScheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for feed, timer := range s.pending {
+		timer.Stop()
+		delete(s.pending, feed)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingRunner struct {
+	runs atomic.Int32
+	done chan struct{}
+}
+
+func (r *countingRunner) Process(ctx context.Context) error {
+	r.runs.Add(1)
+	r.done <- struct{}{}
+	return nil
+}
+
+func TestInternalShardScheduler_Schedule(t *testing.T) {
+	scheduler := NewInternalShardScheduler()
+	defer scheduler.Close()
+	runner := &countingRunner{done: make(chan struct{}, 2)}
+	scheduler.Register("hatena", runner)
+
+	ctx := context.Background()
+	if err := scheduler.Schedule(ctx, "reddit", time.Millisecond); err == nil {
+		t.Error("Expected an error for a feed without a runner")
+	}
+	// A second request while one is pending is merged into it
+	for i := 0; i < 2; i++ {
+		if err := scheduler.Schedule(ctx, "hatena", 20*time.Millisecond); err != nil {
+			t.Fatalf("Schedule failed: %v", err)
+		}
+	}
+
+	select {
+	case <-runner.done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the follow-up to run")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if runs := runner.runs.Load(); runs != 1 {
+		t.Errorf("Expected one follow-up run, got %d", runs)
+	}
+
+	// Once run, the feed can be scheduled again
+	if err := scheduler.Schedule(ctx, "hatena", time.Millisecond); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	select {
+	case <-runner.done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a second follow-up to run")
+	}
+}
+
+func TestInternalShardScheduler_Close(t *testing.T) {
+	scheduler := NewInternalShardScheduler()
+	runner := &countingRunner{done: make(chan struct{}, 1)}
+	scheduler.Register("hatena", runner)
+
+	if err := scheduler.Schedule(context.Background(), "hatena", 20*time.Millisecond); err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	scheduler.Close()
+	time.Sleep(50 * time.Millisecond)
+	if runs := runner.runs.Load(); runs != 0 {
+		t.Errorf("Expected Close to cancel the pending follow-up, got %d runs", runs)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+)
+
+// ErrUnknownSlackAction is returned for button callbacks this service does not handle
+var ErrUnknownSlackAction = errors.New("unknown slack action")
+
+// SlackActions handles the 詳細要約/コメント要約/再要約 buttons on feed summaries:
+// the requested summary is generated on demand and replied in the summary's thread.
+type SlackActions struct {
+	gemini   repository.GeminiRepository
+	comments map[string]rss.FeedRepository // Comment fetchers keyed by feed source
+	replier  repository.ThreadReplier
+	usage    *Usage
+}
+
+func NewSlackActions(
+	gemini repository.GeminiRepository,
+	comments map[string]rss.FeedRepository,
+	replier repository.ThreadReplier,
+	usage *Usage,
+) *SlackActions {
+	return &SlackActions{
+		gemini:   gemini,
+		comments: comments,
+		replier:  replier,
+		usage:    usage,
+	}
+}
+
+// Handle runs action for target on behalf of user and replies in channel's threadTS.
+// Failures are also replied so the user is not left waiting.
+func (s *SlackActions) Handle(ctx context.Context, action string, target repository.SlackActionTarget, user, channel, threadTS string) error {
+	logger := log.New(funcframework.LogWriter(ctx), "", 0)
+	start := time.Now()
+	logger.Printf("Slack action started action=%s url=%s user=%s", action, target.URL, user)
+
+	// ボタン経由もオンデマンド要約と同じ日次上限を消費する
+	if err := s.usage.Consume(ctx, user); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return s.reply(ctx, channel, threadTS, "⚠️ 本日のオンデマンド要約の上限に達しました")
+		}
+		return fmt.Errorf("consuming usage: %w", err)
+	}
+
+	text, err := s.summarize(ctx, action, target)
+	if err != nil {
+		logger.Printf("Error handling Slack action action=%s url=%s: %v", action, target.URL, err)
+		if replyErr := s.reply(ctx, channel, threadTS, "⚠️ 要約に失敗しました: "+err.Error()); replyErr != nil {
+			logger.Printf("Warning: Failed to reply Slack action error: %v", replyErr)
+		}
+		return err
+	}
+	if err := s.reply(ctx, channel, threadTS, text); err != nil {
+		return err
+	}
+
+	logger.Printf("Slack action completed action=%s url=%s duration_ms=%d", action, target.URL, time.Since(start).Milliseconds())
+	return nil
+}
+
+func (s *SlackActions) summarize(ctx context.Context, action string, target repository.SlackActionTarget) (string, error) {
+	switch action {
+	case repository.SlackActionDetail:
+		summary, err := s.gemini.SummarizeURLForOnDemand(ctx, target.URL)
+		if err != nil {
+			return "", fmt.Errorf("summarizing article: %w", err)
+		}
+		return "🔍 *詳細要約*\n\n" + summary.Summary, nil
+
+	case repository.SlackActionComments:
+		fetcher, ok := s.comments[target.Source]
+		if !ok {
+			return fmt.Sprintf("💬 %s のコメント要約には対応していません", target.Source), nil
+		}
+		comments, err := fetcher.FetchComments(ctx, target.URL)
+		if err != nil {
+			return "", fmt.Errorf("fetching comments: %w", err)
+		}
+		if comments.Text == "" {
+			return "💬 コメントはまだありません", nil
+		}
+		summary, err := s.gemini.SummarizeComments(ctx, comments.Text)
+		if err != nil {
+			return "", fmt.Errorf("summarizing comments: %w", err)
+		}
+		return "💬 *コメント要約*\n\n" + summary.Summary, nil
+
+	case repository.SlackActionResummarize:
+		summary, err := s.gemini.SummarizeURL(ctx, target.URL)
+		if err != nil {
+			return "", fmt.Errorf("summarizing article: %w", err)
+		}
+		return "🔄 *再要約*\n\n" + summary.Summary, nil
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownSlackAction, action)
+	}
+}
+
+func (s *SlackActions) reply(ctx context.Context, channel, threadTS, text string) error {
+	if err := s.replier.ReplyInThread(ctx, channel, threadTS, text); err != nil {
+		return fmt.Errorf("replying in thread: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+)
+
+func TestSlackActions_Handle(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		source string
+		want   string
+	}{
+		{"detail", repository.SlackActionDetail, "reddit", "🔍 *詳細要約*\n\ntest summary"},
+		{"comments", repository.SlackActionComments, "hatena", "💬 *コメント要約*\n\ntest comment summary"},
+		{"comments unsupported", repository.SlackActionComments, "reddit", "💬 reddit のコメント要約には対応していません"},
+		{"resummarize", repository.SlackActionResummarize, "lobsters", "🔄 *再要約*\n\ntest summary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replier := &mocks.MockThreadReplier{}
+			actions := NewSlackActions(&mocks.MockGeminiRepo{}, map[string]rss.FeedRepository{
+				"hatena": &mocks.MockHatenaRSSRepo{},
+			}, replier, NewUsage(&mocks.MockUsageRepo{}, 0))
+
+			target := repository.SlackActionTarget{URL: "https://example.com/a", Source: tt.source}
+			if err := actions.Handle(context.Background(), tt.action, target, "slack:U1", "C1", "1700000000.000100"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(replier.Replies) != 1 {
+				t.Fatalf("Expected 1 reply, got %+v", replier.Replies)
+			}
+			reply := replier.Replies[0]
+			if reply.Channel != "C1" || reply.ThreadTS != "1700000000.000100" || reply.Text != tt.want {
+				t.Errorf("Unexpected reply %+v, want text %q", reply, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlackActions_QuotaAndUnknownAction(t *testing.T) {
+	replier := &mocks.MockThreadReplier{}
+	usageRepo := &mocks.MockUsageRepo{Counts: map[string]int{"slack:U1": 1}}
+	actions := NewSlackActions(&mocks.MockGeminiRepo{}, nil, replier, NewUsage(usageRepo, 1))
+	target := repository.SlackActionTarget{URL: "https://example.com/a", Source: "hatena"}
+
+	if err := actions.Handle(context.Background(), repository.SlackActionDetail, target, "slack:U1", "C1", "1"); err != nil {
+		t.Fatalf("Quota exhaustion should be replied, not returned: %v", err)
+	}
+	if len(replier.Replies) != 1 || !strings.Contains(replier.Replies[0].Text, "上限") {
+		t.Errorf("Expected a quota reply, got %+v", replier.Replies)
+	}
+
+	err := actions.Handle(context.Background(), "unknown", target, "slack:U2", "C1", "1")
+	if !errors.Is(err, ErrUnknownSlackAction) {
+		t.Errorf("Expected ErrUnknownSlackAction, got %v", err)
+	}
+	if len(replier.Replies) != 2 || !strings.HasPrefix(replier.Replies[1].Text, "⚠️ 要約に失敗しました") {
+		t.Errorf("Expected a failure reply, got %+v", replier.Replies)
+	}
+}
@@ -11,17 +11,17 @@ import (
 )
 
 type URL struct {
-	gemini repository.GeminiRepository
-	slack  repository.SlackRepository
+	gemini   repository.GeminiRepository
+	notifier repository.Notifier
 }
 
 func NewURL(
 	gemini repository.GeminiRepository,
-	slack repository.SlackRepository,
+	notifier repository.Notifier,
 ) *URL {
 	return &URL{
-		gemini: gemini,
-		slack:  slack,
+		gemini:   gemini,
+		notifier: notifier,
 	}
 }
 
@@ -29,6 +29,12 @@ func (u *URL) Process(ctx context.Context, url string) error {
 	logger := log.New(funcframework.LogWriter(ctx), "", 0)
 	startTime := time.Now()
 
+	// Rewrite AMP/mobile variants to the canonical desktop URL before fetching
+	if canonical := repository.CanonicalizeURL(url); canonical != url {
+		logger.Printf("Canonicalized on-demand URL from=%s to=%s", url, canonical)
+		url = canonical
+	}
+
 	logger.Printf("On-demand URL processing started url=%s", url)
 
 	// Summarization phase
@@ -43,15 +49,16 @@ func (u *URL) Process(ctx context.Context, url string) error {
 	article := repository.Item{
 		Title:  summary.Title,
 		Link:   url,
-		Source: "on-demand",
+		Source: repository.SourceOnDemand,
 	}
 
-	// Slack notification phase
+	// Notification phase
+	repository.ReportProgress(ctx, repository.ProgressPosting)
 	slackStart := time.Now()
-	// Use the on-demand specific method for Slack notification
+	// Use the on-demand specific method for notification
 	// Note: The targetChannel should be passed from the application layer
-	// For now, using the default channel of the slack repository
-	if err := u.slack.SendOnDemandSummary(ctx, article, *summary, ""); err != nil {
+	// For now, using the default channel of the notifier
+	if err := u.notifier.SendOnDemandSummary(ctx, article, *summary, ""); err != nil {
 		logger.Printf("Error sending on-demand Slack summary for URL %s: %v", url, err)
 		return err
 	}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+// ErrQuotaExceeded is returned when a user has used up their daily on-demand quota
+var ErrQuotaExceeded = errors.New("daily on-demand quota exceeded")
+
+// UserUsage is one user's on-demand consumption for a day
+type UserUsage struct {
+	User  string `json:"user"`
+	Count int    `json:"count"`
+	Quota int    `json:"quota"` // 0 means unlimited
+}
+
+// Usage tracks on-demand requests per user and enforces the per-user daily quota
+type Usage struct {
+	usageRepo  repository.UsageRepository
+	dailyQuota int // 0 disables enforcement (usage is still recorded)
+	now        func() time.Time
+}
+
+func NewUsage(usageRepo repository.UsageRepository, dailyQuota int) *Usage {
+	return &Usage{
+		usageRepo:  usageRepo,
+		dailyQuota: dailyQuota,
+		now:        time.Now,
+	}
+}
+
+// Consume records one request for each of users (e.g. the API token and the Slack user it was made
+// for), or returns ErrQuotaExceeded without recording it when any of them used up the quota.
+// Requests are counted before summarizing, since a failed summary still spends Gemini quota.
+func (u *Usage) Consume(ctx context.Context, users ...string) error {
+	now := u.now()
+	if u.dailyQuota > 0 {
+		for _, user := range users {
+			count, err := u.usageRepo.Count(ctx, user, now)
+			if err != nil {
+				return fmt.Errorf("counting usage: %w", err)
+			}
+			if count >= u.dailyQuota {
+				return ErrQuotaExceeded
+			}
+		}
+	}
+
+	for _, user := range users {
+		if err := u.usageRepo.Record(ctx, user, now); err != nil {
+			return fmt.Errorf("recording usage: %w", err)
+		}
+	}
+	return nil
+}
+
+// Today returns the user's consumption for the current UTC day
+func (u *Usage) Today(ctx context.Context, user string) (UserUsage, error) {
+	count, err := u.usageRepo.Count(ctx, user, u.now())
+	if err != nil {
+		return UserUsage{}, fmt.Errorf("counting usage: %w", err)
+	}
+	return UserUsage{User: user, Count: count, Quota: u.dailyQuota}, nil
+}
+
+// Report returns every user's consumption on the given UTC day, heaviest first
+func (u *Usage) Report(ctx context.Context, day time.Time) ([]UserUsage, error) {
+	counts, err := u.usageRepo.Daily(ctx, day)
+	if err != nil {
+		return nil, fmt.Errorf("loading daily usage: %w", err)
+	}
+
+	report := make([]UserUsage, 0, len(counts))
+	for user, count := range counts {
+		report = append(report, UserUsage{User: user, Count: count, Quota: u.dailyQuota})
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Count != report[j].Count {
+			return report[i].Count > report[j].Count
+		}
+		return report[i].User < report[j].User
+	})
+	return report, nil
+}
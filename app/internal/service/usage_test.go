@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestUsage_Consume(t *testing.T) {
+	usageRepo := &mocks.MockUsageRepo{}
+	usage := NewUsage(usageRepo, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := usage.Consume(ctx, "slack:U1"); err != nil {
+			t.Fatalf("Request %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if err := usage.Consume(ctx, "slack:U1"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if usageRepo.Counts["slack:U1"] != 2 {
+		t.Errorf("Rejected requests should not be recorded, got %d", usageRepo.Counts["slack:U1"])
+	}
+
+	// Other users keep their own quota
+	if err := usage.Consume(ctx, "tok_abc"); err != nil {
+		t.Errorf("Unexpected error for another user: %v", err)
+	}
+}
+
+func TestUsage_ConsumeMultipleKeys(t *testing.T) {
+	usageRepo := &mocks.MockUsageRepo{Counts: map[string]int{"tok_abc": 1}}
+	usage := NewUsage(usageRepo, 2)
+	ctx := context.Background()
+
+	if err := usage.Consume(ctx, "tok_abc", "slack:U1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if usageRepo.Counts["tok_abc"] != 2 || usageRepo.Counts["slack:U1"] != 1 {
+		t.Errorf("Expected the request to be charged to both keys, got %v", usageRepo.Counts)
+	}
+
+	// Either key being out of quota rejects the request
+	if err := usage.Consume(ctx, "tok_abc", "slack:U2"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if usageRepo.Counts["slack:U2"] != 0 {
+		t.Errorf("Rejected requests should not be recorded, got %v", usageRepo.Counts)
+	}
+}
+
+func TestUsage_ConsumeUnlimited(t *testing.T) {
+	usageRepo := &mocks.MockUsageRepo{Counts: map[string]int{"tok_abc": 100}}
+	usage := NewUsage(usageRepo, 0)
+
+	if err := usage.Consume(context.Background(), "tok_abc"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if usageRepo.Counts["tok_abc"] != 101 {
+		t.Errorf("Usage should still be recorded without a quota, got %d", usageRepo.Counts["tok_abc"])
+	}
+}
+
+func TestUsage_Report(t *testing.T) {
+	usage := NewUsage(&mocks.MockUsageRepo{Counts: map[string]int{"tok_abc": 1, "slack:U1": 5, "slack:U2": 1}}, 10)
+
+	report, err := usage.Report(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{"slack:U1", "slack:U2", "tok_abc"}
+	if len(report) != len(want) {
+		t.Fatalf("Expected %d users, got %+v", len(want), report)
+	}
+	for i, user := range want {
+		if report[i].User != user || report[i].Quota != 10 {
+			t.Errorf("report[%d] = %+v, want user %s with quota 10", i, report[i], user)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// ActiveWindow skips scheduled runs of a feed outside its active hours, so that nothing is posted
+// into empty channels overnight. ?force=true runs the feed anyway (manual runs), and the breaking
+// news fast lane (nil disables) may still run it for breaking items.
+type ActiveWindow struct {
+	feed     string
+	window   *rss.ActiveWindow
+	location *time.Location
+	fastLane *limiter.FastLane
+	next     http.Handler
+}
+
+func NewActiveWindow(feed string, window *rss.ActiveWindow, location *time.Location, fastLane *limiter.FastLane, next http.Handler) *ActiveWindow {
+	return &ActiveWindow{feed: feed, window: window, location: location, fastLane: fastLane, next: next}
+}
+
+func (h *ActiveWindow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.window.Contains(repository.Now(r.Context()).In(h.location)) || r.URL.Query().Get("force") == "true" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+	if ctx, ok := h.fastLane.OutsideWindow(r.Context(), h.feed); ok {
+		logger.Printf("Feed outside its active window, running the breaking news fast lane feed=%s breaking_only=%t", h.feed, limiter.BreakingOnly(ctx))
+		h.next.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+	logger.Printf("Feed outside its active window, skipping feed=%s active_window=%s", h.feed, h.window)
+	response.WriteSuccess(w, "Feed "+h.feed+" skipped outside its active window", map[string]interface{}{
+		"skipped":       true,
+		"active_window": h.window.String(),
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+)
+
+func TestActiveWindow_ServeHTTP(t *testing.T) {
+	window, err := rss.ParseActiveWindow("07:00-23:00")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jst := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		path string
+		ran  bool
+	}{
+		{"inside window", time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), "/process/reddit", true}, // 12:00 JST
+		{"overnight", time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC), "/process/reddit", false},   // 01:00 JST
+		{"forced overnight", time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC), "/process/reddit?force=true", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			handler := NewActiveWindow("reddit", window, jst, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ran = true
+			}))
+			req := httptest.NewRequest("POST", tt.path, nil)
+			req = req.WithContext(repository.WithClock(req.Context(), repository.FixedClock(tt.now)))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if ran != tt.ran {
+				t.Errorf("Expected ran=%v, got %v", tt.ran, ran)
+			}
+			if !tt.ran && (w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"skipped":true`)) {
+				t.Errorf("Expected a successful skip response, got %d %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestActiveWindow_FastLane(t *testing.T) {
+	window, err := rss.ParseActiveWindow("07:00-23:00")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fastLane := limiter.NewFastLane(limiter.NewProductionArticleLimiter(), nil, []string{"outage"}, time.Hour)
+
+	breakingOnly := false
+	handler := NewActiveWindow("reddit", window, time.UTC, fastLane, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		breakingOnly = limiter.BreakingOnly(r.Context())
+	}))
+	req := httptest.NewRequest("POST", "/process/reddit", nil)
+	req = req.WithContext(repository.WithClock(req.Context(), repository.FixedClock(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC))))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !breakingOnly {
+		t.Error("Expected an overnight run limited to breaking items")
+	}
+}
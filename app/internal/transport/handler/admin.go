@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/transport/middleware"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// Audit action names
+const (
+	AuditActionProcessedDelete = "processed.delete"
+	AuditActionProcessedImport = "processed.import"
+	AuditActionProcessedPrune  = "processed.prune"
+)
+
+// recordAdminAction writes the audit entry before an admin action runs, so no action goes unrecorded
+func recordAdminAction(r *http.Request, auditRepo repository.AuditRepository, action string, params map[string]string) error {
+	if err := auditRepo.Record(r.Context(), repository.AuditEntry{
+		Time:         time.Now(),
+		Action:       action,
+		ActorTokenID: middleware.TokenIDFromContext(r.Context()),
+		Params:       params,
+	}); err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+	return nil
+}
+
+// AdminProcessed removes an article from the processed index so it is summarized again
+type AdminProcessed struct {
+	processedRepo repository.ProcessedArticleRepository
+	auditRepo     repository.AuditRepository
+}
+
+func NewAdminProcessed(processedRepo repository.ProcessedArticleRepository, auditRepo repository.AuditRepository) *AdminProcessed {
+	return &AdminProcessed{
+		processedRepo: processedRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+type adminProcessedRequest struct {
+	URL string `json:"url"`
+}
+
+func (h *AdminProcessed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	var req adminProcessedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Printf("Invalid JSON in admin request: %v", err)
+		response.WriteBadRequest(w, "Invalid JSON")
+		return
+	}
+	if req.URL == "" {
+		response.WriteBadRequest(w, "URL is required")
+		return
+	}
+
+	if err := recordAdminAction(r, h.auditRepo, AuditActionProcessedDelete, map[string]string{"url": req.URL}); err != nil {
+		logger.Printf("Error auditing processed index removal url=%s: %v", req.URL, err)
+		response.WriteInternalError(w, "Failed to record audit log")
+		return
+	}
+
+	removed, err := h.processedRepo.UnmarkProcessed(r.Context(), repository.Item{Link: req.URL})
+	if err != nil {
+		logger.Printf("Error unmarking processed article %s: %v", req.URL, err)
+		response.WriteInternalError(w, "Failed to update processed index")
+		return
+	}
+
+	logger.Printf("Processed index entry removal url=%s removed=%t", req.URL, removed)
+	response.WriteSuccess(w, "Processed index updated", map[string]bool{"removed": removed})
+}
+
+// maxImportURLs bounds one import request; larger histories are sent in chunks
+const maxImportURLs = 5000
+
+// DefaultImportSource labels imported entries when the request names no source
+const DefaultImportSource = "import"
+
+// AdminProcessedImport bulk-marks URLs as processed, e.g. the history of a previous deployment,
+// so migrating does not replay already-shared articles
+type AdminProcessedImport struct {
+	processedRepo repository.ProcessedArticleRepository
+	auditRepo     repository.AuditRepository
+}
+
+func NewAdminProcessedImport(processedRepo repository.ProcessedArticleRepository, auditRepo repository.AuditRepository) *AdminProcessedImport {
+	return &AdminProcessedImport{
+		processedRepo: processedRepo,
+		auditRepo:     auditRepo,
+	}
+}
+
+type adminProcessedImportRequest struct {
+	URLs   []string `json:"urls"`
+	Source string   `json:"source"` // Recorded as the entries' source; defaults to "import"
+}
+
+func (h *AdminProcessedImport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	var req adminProcessedImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Printf("Invalid JSON in admin import request: %v", err)
+		response.WriteBadRequest(w, "Invalid JSON")
+		return
+	}
+	if len(req.URLs) == 0 {
+		response.WriteBadRequest(w, "urls is required")
+		return
+	}
+	if len(req.URLs) > maxImportURLs {
+		response.WriteBadRequest(w, fmt.Sprintf("at most %d urls per request", maxImportURLs))
+		return
+	}
+	if req.Source == "" {
+		req.Source = DefaultImportSource
+	}
+
+	params := map[string]string{"source": req.Source, "count": strconv.Itoa(len(req.URLs))}
+	if err := recordAdminAction(r, h.auditRepo, AuditActionProcessedImport, params); err != nil {
+		logger.Printf("Error auditing processed index import count=%d: %v", len(req.URLs), err)
+		response.WriteInternalError(w, "Failed to record audit log")
+		return
+	}
+
+	items := make([]repository.Item, 0, len(req.URLs))
+	for _, url := range req.URLs {
+		items = append(items, repository.Item{Link: url, Source: req.Source})
+	}
+	added, err := h.processedRepo.MarkManyAsProcessed(r.Context(), items)
+	if err != nil {
+		logger.Printf("Error importing processed articles count=%d: %v", len(items), err)
+		response.WriteInternalError(w, "Failed to update processed index")
+		return
+	}
+
+	logger.Printf("Processed index import source=%s requested=%d added=%d", req.Source, len(items), added)
+	response.WriteSuccess(w, "Processed index updated", map[string]int{"requested": len(items), "added": added})
+}
+
+// AdminProcessedPrune removes processed index entries older than a retention window
+type AdminProcessedPrune struct {
+	processedRepo repository.ProcessedArticleRepository
+	auditRepo     repository.AuditRepository
+	retentionDays int // Used when the request names no window (0: the request must name one)
+}
+
+func NewAdminProcessedPrune(processedRepo repository.ProcessedArticleRepository, auditRepo repository.AuditRepository, retentionDays int) *AdminProcessedPrune {
+	return &AdminProcessedPrune{
+		processedRepo: processedRepo,
+		auditRepo:     auditRepo,
+		retentionDays: retentionDays,
+	}
+}
+
+type adminProcessedPruneRequest struct {
+	OlderThanDays int `json:"older_than_days"` // Defaults to PROCESSED_RETENTION_DAYS
+}
+
+func (h *AdminProcessedPrune) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	var req adminProcessedPruneRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Printf("Invalid JSON in admin prune request: %v", err)
+			response.WriteBadRequest(w, "Invalid JSON")
+			return
+		}
+	}
+	days := req.OlderThanDays
+	if days == 0 {
+		days = h.retentionDays
+	}
+	if days <= 0 {
+		response.WriteBadRequest(w, "older_than_days must be a positive number of days (no PROCESSED_RETENTION_DAYS default)")
+		return
+	}
+
+	if err := recordAdminAction(r, h.auditRepo, AuditActionProcessedPrune, map[string]string{"older_than_days": strconv.Itoa(days)}); err != nil {
+		logger.Printf("Error auditing processed index pruning days=%d: %v", days, err)
+		response.WriteInternalError(w, "Failed to record audit log")
+		return
+	}
+
+	cutoff := repository.Now(r.Context()).AddDate(0, 0, -days)
+	removed, err := h.processedRepo.Prune(r.Context(), cutoff)
+	if err != nil {
+		logger.Printf("Error pruning processed index days=%d: %v", days, err)
+		response.WriteInternalError(w, "Failed to prune processed index")
+		return
+	}
+
+	logger.Printf("Processed index pruning older_than_days=%d removed=%d", days, removed)
+	response.WriteSuccess(w, "Processed index pruned", map[string]any{"removed": removed, "cutoff": cutoff.UTC().Format(time.RFC3339)})
+}
+
+// AdminAudit lists recorded admin actions, newest first
+type AdminAudit struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAdminAudit(auditRepo repository.AuditRepository) *AdminAudit {
+	return &AdminAudit{
+		auditRepo: auditRepo,
+	}
+}
+
+func (h *AdminAudit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			response.WriteBadRequest(w, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	entries, err := h.auditRepo.List(r.Context(), limit)
+	if err != nil {
+		logger.Printf("Error listing audit log: %v", err)
+		response.WriteInternalError(w, "Failed to list audit log")
+		return
+	}
+
+	response.WriteSuccess(w, "Audit log retrieved successfully", entries)
+}
+
+// AdminUsage reports per-user on-demand consumption for a day (UTC, default today)
+type AdminUsage struct {
+	usage *service.Usage
+}
+
+func NewAdminUsage(usage *service.Usage) *AdminUsage {
+	return &AdminUsage{
+		usage: usage,
+	}
+}
+
+func (h *AdminUsage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	day := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			response.WriteBadRequest(w, "date must be YYYY-MM-DD")
+			return
+		}
+		day = parsed
+	}
+
+	report, err := h.usage.Report(r.Context(), day)
+	if err != nil {
+		logger.Printf("Error loading usage report: %v", err)
+		response.WriteInternalError(w, "Failed to load usage")
+		return
+	}
+
+	response.WriteSuccess(w, "Usage retrieved successfully", report)
+}
+
+// AdminProcessedStats reports the processed index's lookups and writes since the process started
+// (storage-backed index only; CACHE_TYPE=storage)
+type AdminProcessedStats struct {
+	stats func() repository.StorageIndexStats
+}
+
+func NewAdminProcessedStats(stats func() repository.StorageIndexStats) *AdminProcessedStats {
+	return &AdminProcessedStats{
+		stats: stats,
+	}
+}
+
+func (h *AdminProcessedStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	response.WriteSuccess(w, "Processed index stats retrieved successfully", h.stats())
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func TestAdminProcessed_ServeHTTP(t *testing.T) {
+	auditRepo := &mocks.MockAuditRepo{}
+	handler := NewAdminProcessed(&mocks.MockProcessedRepo{}, auditRepo)
+
+	req := httptest.NewRequest("DELETE", "/admin/processed", strings.NewReader(`{"url": "https://example.com/a"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if len(auditRepo.Entries) != 1 || auditRepo.Entries[0].Action != AuditActionProcessedDelete || auditRepo.Entries[0].Params["url"] != "https://example.com/a" {
+		t.Errorf("Expected audit entry for the removal, got %+v", auditRepo.Entries)
+	}
+}
+
+func TestAdminProcessed_ServeHTTP_AuditFailure(t *testing.T) {
+	handler := NewAdminProcessed(&mocks.MockProcessedRepo{}, &mocks.MockAuditRepo{Err: errors.New("gcs down")})
+
+	req := httptest.NewRequest("DELETE", "/admin/processed", strings.NewReader(`{"url": "https://example.com/a"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 when the audit log cannot be written, got %d", w.Code)
+	}
+}
+
+func TestAdminAudit_ServeHTTP(t *testing.T) {
+	handler := NewAdminAudit(&mocks.MockAuditRepo{})
+
+	req := httptest.NewRequest("GET", "/admin/audit?limit=10", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestAdminProcessed_ServeHTTP_MissingURL(t *testing.T) {
+	handler := NewAdminProcessed(&mocks.MockProcessedRepo{}, &mocks.MockAuditRepo{})
+
+	req := httptest.NewRequest("DELETE", "/admin/processed", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestAdminUsage_ServeHTTP(t *testing.T) {
+	handler := NewAdminUsage(service.NewUsage(&mocks.MockUsageRepo{Counts: map[string]int{"slack:U1": 2}}, 5))
+
+	req := httptest.NewRequest("GET", "/admin/usage?date=2026-01-02", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"user":"slack:U1","count":2,"quota":5`) {
+		t.Errorf("Expected usage of slack:U1 in response, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/admin/usage?date=yesterday", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", w.Code)
+	}
+}
+
+func TestAdminProcessedStats_ServeHTTP(t *testing.T) {
+	handler := NewAdminProcessedStats(func() repository.StorageIndexStats {
+		return repository.StorageIndexStats{Hits: 3, Misses: 1, Writes: 2}
+	})
+
+	req := httptest.NewRequest("GET", "/admin/processed/stats", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"hits":3,"misses":1,"writes":2`) {
+		t.Errorf("Expected the index stats in response, got %s", w.Body.String())
+	}
+}
+
+func TestAdminProcessedImport_ServeHTTP(t *testing.T) {
+	auditRepo := &mocks.MockAuditRepo{}
+	handler := NewAdminProcessedImport(&mocks.MockProcessedRepo{}, auditRepo)
+
+	req := httptest.NewRequest("POST", "/admin/processed", strings.NewReader(`{"urls": ["https://example.com/a", "https://example.com/b"], "source": "v2"}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"added":2`) {
+		t.Errorf("Expected 2 added entries, got %s", w.Body.String())
+	}
+	if len(auditRepo.Entries) != 1 || auditRepo.Entries[0].Action != AuditActionProcessedImport || auditRepo.Entries[0].Params["source"] != "v2" || auditRepo.Entries[0].Params["count"] != "2" {
+		t.Errorf("Expected audit entry for the import, got %+v", auditRepo.Entries)
+	}
+}
+
+func TestAdminProcessedImport_ServeHTTP_EmptyURLs(t *testing.T) {
+	handler := NewAdminProcessedImport(&mocks.MockProcessedRepo{}, &mocks.MockAuditRepo{})
+
+	req := httptest.NewRequest("POST", "/admin/processed", strings.NewReader(`{"urls": []}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestAdminProcessedPrune_ServeHTTP(t *testing.T) {
+	auditRepo := &mocks.MockAuditRepo{}
+	handler := NewAdminProcessedPrune(&mocks.MockProcessedRepo{}, auditRepo, 90)
+
+	// Without a body the configured retention applies
+	req := httptest.NewRequest("POST", "/admin/processed/prune", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if len(auditRepo.Entries) != 1 || auditRepo.Entries[0].Action != AuditActionProcessedPrune || auditRepo.Entries[0].Params["older_than_days"] != "90" {
+		t.Errorf("Expected audit entry for the pruning, got %+v", auditRepo.Entries)
+	}
+
+	req = httptest.NewRequest("POST", "/admin/processed/prune", strings.NewReader(`{"older_than_days": 30}`))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || auditRepo.Entries[1].Params["older_than_days"] != "30" {
+		t.Errorf("Expected the requested window, got status %d and %+v", w.Code, auditRepo.Entries)
+	}
+}
+
+func TestAdminProcessedPrune_ServeHTTP_NoWindow(t *testing.T) {
+	handler := NewAdminProcessedPrune(&mocks.MockProcessedRepo{}, &mocks.MockAuditRepo{}, 0)
+
+	req := httptest.NewRequest("POST", "/admin/processed/prune", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a retention window, got %d", w.Code)
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type ArxivHandler struct {
+	processor *article.ArxivProcessor
+}
+
+func NewArxivHandler(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *ArxivHandler {
+	return &ArxivHandler{
+		processor: article.NewArxivProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *ArxivHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("arXiv feed processing request started")
+
+	// Process arXiv feed
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "arxiv", h.processor.Process); err != nil {
+		logger.Printf("Error processing arXiv feed: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process arXiv feed", report)
+		return
+	}
+
+	logger.Printf("arXiv feed processing completed successfully")
+	response.WriteSuccess(w, "arXiv feed processed successfully", report)
+}
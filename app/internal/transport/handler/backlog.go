@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type BacklogHandler struct {
+	processor *article.BacklogDrainProcessor
+}
+
+func NewBacklogHandler(processor *article.BacklogDrainProcessor) *BacklogHandler {
+	return &BacklogHandler{
+		processor: processor,
+	}
+}
+
+func (h *BacklogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("Backlog drain request started")
+
+	result, err := h.processor.Drain(r.Context())
+	if err != nil {
+		logger.Printf("Error draining backlog: %v", err)
+		response.WriteInternalError(w, "Failed to drain backlog")
+		return
+	}
+
+	logger.Printf("Backlog drain request completed processed=%d failed=%d remaining=%d", result.Processed, result.Failed, result.Remaining)
+	response.WriteSuccess(w, "Backlog drained successfully", result)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// CommunityHandler runs one community feed (Dev.to, Qiita, Zenn or Product Hunt)
+type CommunityHandler struct {
+	name      string // Display name for logs and responses
+	processor *article.CommunityProcessor
+}
+
+func NewDevToHandler(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityHandler {
+	return &CommunityHandler{
+		name:      "Dev.to",
+		processor: article.NewDevToProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func NewQiitaHandler(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityHandler {
+	return &CommunityHandler{
+		name:      "Qiita",
+		processor: article.NewQiitaProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func NewZennHandler(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityHandler {
+	return &CommunityHandler{
+		name:      "Zenn",
+		processor: article.NewZennProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func NewProductHuntHandler(
+	token string,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *CommunityHandler {
+	return &CommunityHandler{
+		name:      "Product Hunt",
+		processor: article.NewProductHuntProcessor(token, rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *CommunityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("%s feed processing request started", h.name)
+
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, h.processor.Feed(), h.processor.Process); err != nil {
+		logger.Printf("Error processing %s feed: %v", h.name, err)
+		response.WriteInternalErrorWithData(w, "Failed to process "+h.name+" feed", report)
+		return
+	}
+
+	logger.Printf("%s feed processing completed successfully", h.name)
+	response.WriteSuccess(w, h.name+" feed processed successfully", report)
+}
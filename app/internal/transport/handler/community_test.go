@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestCommunityHandlers_ServeHTTP_PostMethod(t *testing.T) {
+	handlers := map[string]*CommunityHandler{
+		"/process/devto":       NewDevToHandler(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil),
+		"/process/qiita":       NewQiitaHandler(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil),
+		"/process/zenn":        NewZennHandler(&mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil),
+		"/process/producthunt": NewProductHuntHandler("", &mocks.MockCommunityRepo{}, &mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}, &mocks.MockProcessedRepo{}, &mocks.MockBacklogRepo{}, &mocks.MockFeedStatsRepo{}, &mocks.MockLimiter{}, nil),
+	}
+
+	for path, handler := range handlers {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// exportCSVHeader names the CSV columns
+var exportCSVHeader = []string{"published_at", "source", "title", "url", "summary", "comment_summary", "provider", "model", "prompt", "extraction"}
+
+// SummaryExport streams archived summaries as NDJSON or CSV
+type SummaryExport struct {
+	exportService *service.Export
+}
+
+func NewSummaryExport(exportService *service.Export) *SummaryExport {
+	return &SummaryExport{
+		exportService: exportService,
+	}
+}
+
+func (h *SummaryExport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	params := r.URL.Query()
+	format := params.Get("format")
+	switch format {
+	case "":
+		format = "ndjson"
+	case "ndjson", "csv":
+	default:
+		response.WriteBadRequest(w, "format must be ndjson or csv")
+		return
+	}
+
+	query := service.ExportQuery{Cursor: params.Get("cursor")}
+	var err error
+	if query.From, err = parseExportTime(params.Get("from")); err != nil {
+		response.WriteBadRequest(w, "from must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if query.To, err = parseExportTime(params.Get("to")); err != nil {
+		response.WriteBadRequest(w, "to must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.WriteBadRequest(w, "limit must be a positive integer")
+			return
+		}
+		query.Limit = n
+	}
+
+	page, err := h.exportService.Page(r.Context(), query)
+	if errors.Is(err, service.ErrInvalidCursor) {
+		response.WriteBadRequest(w, "cursor is invalid")
+		return
+	}
+	if err != nil {
+		logger.Printf("Error exporting summaries from=%s to=%s: %v", params.Get("from"), params.Get("to"), err)
+		response.WriteInternalError(w, "Failed to export summaries")
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	if page.NextCursor != "" {
+		next := *r.URL
+		nextParams := next.Query()
+		nextParams.Set("cursor", page.NextCursor)
+		next.RawQuery = nextParams.Encode()
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+		w.Header().Set("Link", "<"+next.RequestURI()+">; rel=\"next\"")
+	}
+
+	var out io.Writer = w
+	w.Header().Add("Vary", "Accept-Encoding")
+	if acceptsGzip(r) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	}
+
+	if format == "csv" {
+		err = writeExportCSV(out, page.Entries)
+	} else {
+		err = writeExportNDJSON(out, page.Entries)
+	}
+	if err != nil {
+		// Headers are sent; the client sees a truncated body
+		logger.Printf("Error writing summary export: %v", err)
+	}
+}
+
+// parseExportTime accepts an RFC 3339 time or a date (midnight UTC); empty is the zero time
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// acceptsGzip reports whether the client accepts a gzip-encoded body
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) == "gzip" && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeExportNDJSON(out io.Writer, entries []*repository.SummaryFeedEntry) error {
+	encoder := json.NewEncoder(out)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeExportCSV(out io.Writer, entries []*repository.SummaryFeedEntry) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var provenance repository.Provenance
+		if entry.Provenance != nil {
+			provenance = *entry.Provenance
+		}
+		if err := writer.Write([]string{
+			entry.PublishedAt.UTC().Format(time.RFC3339),
+			entry.Source,
+			entry.Title,
+			entry.URL,
+			entry.Summary,
+			entry.CommentSummary,
+			provenance.Provider,
+			provenance.Model,
+			provenance.Prompt,
+			provenance.Extraction,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
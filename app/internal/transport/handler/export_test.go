@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func newTestSummaryExport() *SummaryExport {
+	base := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	return NewSummaryExport(service.NewExport(&mocks.MockSummaryFeedRepo{Entries: []*repository.SummaryFeedEntry{
+		{Title: "Newer", URL: "https://example.com/2", Source: "hatena", Summary: "s2, with comma", PublishedAt: base.Add(time.Hour),
+			Provenance: &repository.Provenance{Provider: "gemini", Model: "gemini-2.5-flash"}},
+		{Title: "Older", URL: "https://example.com/1", Source: "reddit", Summary: "s1", PublishedAt: base},
+	}}))
+}
+
+func TestSummaryExport_NDJSONPages(t *testing.T) {
+	handler := newTestSummaryExport()
+
+	req := httptest.NewRequest("GET", "/api/v1/summaries/export?from=2024-08-01&limit=1", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var entry repository.SummaryFeedEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entry); err != nil || entry.Title != "Older" {
+		t.Fatalf("Expected the oldest summary first, got %q (%v)", w.Body.String(), err)
+	}
+	cursor := w.Header().Get("X-Next-Cursor")
+	if cursor == "" || w.Header().Get("Link") == "" {
+		t.Fatalf("Expected a next page, got headers %v", w.Header())
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/summaries/export?from=2024-08-01&limit=1&cursor="+cursor, nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &entry); err != nil || entry.Title != "Newer" {
+		t.Errorf("Expected the second page, got %q (%v)", w.Body.String(), err)
+	}
+	if w.Header().Get("X-Next-Cursor") != "" {
+		t.Errorf("Expected the last page")
+	}
+}
+
+func TestSummaryExport_GzipCSV(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/summaries/export?format=csv", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	newTestSummaryExport().ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected a gzip body, got headers %v", w.Header())
+	}
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Invalid gzip body: %v", err)
+	}
+	records, err := csv.NewReader(bufio.NewReader(gz)).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 3 || records[0][0] != "published_at" || records[2][4] != "s2, with comma" || records[2][7] != "gemini-2.5-flash" {
+		t.Errorf("Unexpected CSV %v", records)
+	}
+}
+
+func TestSummaryExport_BadRequests(t *testing.T) {
+	for _, query := range []string{"format=xml", "from=yesterday", "limit=0", "cursor=%21%21"} {
+		req := httptest.NewRequest("GET", "/api/v1/summaries/export?"+query, nil)
+		w := httptest.NewRecorder()
+		newTestSummaryExport().ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
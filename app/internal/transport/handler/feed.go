@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// SummaryFeed publishes the most recent generated summaries as RSS
+type SummaryFeed struct {
+	feedRepo repository.SummaryFeedRepository
+}
+
+func NewSummaryFeed(feedRepo repository.SummaryFeedRepository) *SummaryFeed {
+	return &SummaryFeed{
+		feedRepo: feedRepo,
+	}
+}
+
+func (h *SummaryFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	entries, err := h.feedRepo.List(r.Context())
+	if err != nil {
+		logger.Printf("Error loading summary feed: %v", err)
+		response.WriteInternalError(w, "Failed to load summary feed")
+		return
+	}
+
+	scheme := "https"
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	} else if r.TLS == nil {
+		scheme = "http"
+	}
+	data, err := repository.RenderSummaryFeedRSS(entries, "Article Summarizer", scheme+"://"+r.Host+"/history")
+	if err != nil {
+		logger.Printf("Error rendering summary feed: %v", err)
+		response.WriteInternalError(w, "Failed to render summary feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write(data)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// Feed health states
+const (
+	FeedHealthOK        = "ok"
+	FeedHealthFailing   = "failing"   // Every article of the last run failed
+	FeedHealthEscalated = "escalated" // Failing long enough for the owner to be alerted
+)
+
+// FeedHealthStatus is a feed's run health and ownership
+type FeedHealthStatus struct {
+	Feed        string    `json:"feed"`
+	Owner       string    `json:"owner,omitempty"` // Slack user or user group ID
+	Status      string    `json:"status"`
+	Runs        int       `json:"runs"`
+	FailureRate float64   `json:"failure_rate"`
+	FailedRuns  int       `json:"failed_runs"` // Consecutive runs in which every article failed
+	Anomalies   int       `json:"anomalies"`   // Consecutive runs skipped as anomalous
+	LastRun     time.Time `json:"last_run"`
+}
+
+// FeedHealth reports the configured feeds' run statistics and owners, so that a shared
+// deployment shows whom to ask about a failing feed
+type FeedHealth struct {
+	escalation *article.FeedEscalation
+	feeds      []string
+}
+
+func NewFeedHealth(escalation *article.FeedEscalation, feeds []string) *FeedHealth {
+	return &FeedHealth{
+		escalation: escalation,
+		feeds:      feeds,
+	}
+}
+
+func (h *FeedHealth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	statuses := make([]FeedHealthStatus, 0, len(h.feeds))
+	for _, feed := range h.feeds {
+		stats, err := h.escalation.Load(r.Context(), feed)
+		if err != nil {
+			logger.Printf("Error loading feed stats feed=%s: %v", feed, err)
+			response.WriteInternalError(w, "Failed to load feed health")
+			return
+		}
+		status := FeedHealthOK
+		switch {
+		case h.escalation.Escalated(stats):
+			status = FeedHealthEscalated
+		case stats.FailedRuns > 0:
+			status = FeedHealthFailing
+		}
+		statuses = append(statuses, FeedHealthStatus{
+			Feed:        feed,
+			Owner:       h.escalation.Owner(feed),
+			Status:      status,
+			Runs:        stats.Runs,
+			FailureRate: stats.FailureRate,
+			FailedRuns:  stats.FailedRuns,
+			Anomalies:   stats.Anomalies,
+			LastRun:     stats.LastRun,
+		})
+	}
+
+	response.WriteSuccess(w, "Feed health retrieved successfully", statuses)
+}
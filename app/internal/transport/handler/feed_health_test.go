@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+func TestFeedHealth_ServeHTTP(t *testing.T) {
+	statsRepo := &mocks.MockFeedStatsRepo{Stats: map[string]*repository.FeedStats{
+		"hatena":   {Feed: "hatena", Runs: 10, FailureRate: 0.8, FailedRuns: 3},
+		"lobsters": {Feed: "lobsters", Runs: 10, FailedRuns: 1},
+	}}
+	escalation := article.NewFeedEscalation(statsRepo, map[string]string{"hatena": "S0123ABCD"}, nil, 3)
+	handler := NewFeedHealth(escalation, []string{"hatena", "lobsters", "zenn"})
+
+	req := httptest.NewRequest("GET", "/api/v1/feeds", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var body struct {
+		Data []FeedHealthStatus `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if len(body.Data) != 3 {
+		t.Fatalf("Expected 3 feeds, got %+v", body.Data)
+	}
+	if hatena := body.Data[0]; hatena.Status != FeedHealthEscalated || hatena.Owner != "S0123ABCD" || hatena.FailedRuns != 3 {
+		t.Errorf("Unexpected hatena health %+v", hatena)
+	}
+	if lobsters := body.Data[1]; lobsters.Status != FeedHealthFailing || lobsters.Owner != "" {
+		t.Errorf("Unexpected lobsters health %+v", lobsters)
+	}
+	if zenn := body.Data[2]; zenn.Status != FeedHealthOK || zenn.Runs != 0 {
+		t.Errorf("Unexpected zenn health %+v", zenn)
+	}
+}
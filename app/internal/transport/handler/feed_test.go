@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestSummaryFeed_ServeHTTP(t *testing.T) {
+	feedRepo := &mocks.MockSummaryFeedRepo{Entries: []*repository.SummaryFeedEntry{
+		{Title: "Newest", URL: "https://example.com/2", Source: "hatena", Summary: "s2", CommentSummary: "c2", PublishedAt: time.Now()},
+		{Title: "Older", URL: "https://example.com/1", Source: "reddit", Summary: "s1", PublishedAt: time.Now().Add(-time.Hour)},
+	}}
+	handler := NewSummaryFeed(feedRepo)
+
+	req := httptest.NewRequest("GET", "/feed.xml", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+
+	var feed struct {
+		Channel struct {
+			Items []repository.Item `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("Failed to parse feed: %v", err)
+	}
+	if len(feed.Channel.Items) != 2 || feed.Channel.Items[0].Title != "Newest" || feed.Channel.Items[0].Link != "https://example.com/2" {
+		t.Errorf("Unexpected items: %+v", feed.Channel.Items)
+	}
+}
+
+func TestSummaryFeed_ServeHTTP_Error(t *testing.T) {
+	handler := NewSummaryFeed(&mocks.MockSummaryFeedRepo{Err: errors.New("gcs down")})
+
+	req := httptest.NewRequest("GET", "/feed.xml", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+}
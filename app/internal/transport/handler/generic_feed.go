@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// FeedsHandler runs the generic feeds configured in GENERIC_FEEDS
+type FeedsHandler struct {
+	registry *article.FeedRegistry
+}
+
+func NewFeedsHandler(registry *article.FeedRegistry) *FeedsHandler {
+	return &FeedsHandler{registry: registry}
+}
+
+// ServeHTTP runs every feed whose schedule has elapsed (one scheduler job covers all generic feeds)
+func (h *FeedsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("Generic feeds processing request started")
+
+	ctx, report := article.WithRunReport(r.Context())
+	ran, err := h.registry.ProcessDue(ctx)
+	data := map[string]interface{}{
+		"feeds":  ran,
+		"report": report,
+	}
+	if err != nil {
+		logger.Printf("Error processing generic feeds: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process generic feeds", data)
+		return
+	}
+
+	logger.Printf("Generic feeds processing completed successfully feeds=%v", ran)
+	response.WriteSuccess(w, "Generic feeds processed successfully", data)
+}
+
+// ServeFeed runs the feed named in the path now, regardless of its schedule
+func (h *FeedsHandler) ServeFeed(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	name := r.PathValue("name")
+	feed, ok := h.registry.Get(name)
+	if !ok {
+		response.WriteError(w, http.StatusNotFound, "Unknown feed: "+name)
+		return
+	}
+
+	logger.Printf("Generic feed processing request started feed=%s", name)
+
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, name, feed.Process); err != nil {
+		logger.Printf("Error processing feed %s: %v", name, err)
+		response.WriteInternalErrorWithData(w, "Failed to process feed "+name, report)
+		return
+	}
+
+	logger.Printf("Generic feed processing completed successfully feed=%s", name)
+	response.WriteSuccess(w, "Feed "+name+" processed successfully", report)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository/rss"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+func newTestFeedsHandler(t *testing.T) *FeedsHandler {
+	t.Helper()
+	specs, err := rss.ParseGenericFeedSpecs(`[{"name":"blog","url":"https://blog.example.com/feed.xml"}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	processor := article.NewGenericFeedProcessor(
+		specs[0],
+		&mocks.MockRSSRepo{},
+		&mocks.MockGeminiRepo{},
+		&mocks.MockSlackRepo{},
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+	return NewFeedsHandler(article.NewFeedRegistry(time.UTC, nil, processor))
+}
+
+func TestFeedsHandler_ServeFeed(t *testing.T) {
+	handler := newTestFeedsHandler(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /process/feeds/{name}", handler.ServeFeed)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/process/feeds/blog", http.StatusOK},
+		{"/process/feeds/unknown", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.code, w.Code)
+		}
+	}
+}
+
+func TestFeedsHandler_ServeHTTP(t *testing.T) {
+	handler := newTestFeedsHandler(t)
+
+	req := httptest.NewRequest("POST", "/process/feeds", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
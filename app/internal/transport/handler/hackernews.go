@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type HackerNewsHandler struct {
+	processor *article.HackerNewsProcessor
+}
+
+func NewHackerNewsHandler(
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *HackerNewsHandler {
+	return &HackerNewsHandler{
+		processor: article.NewHackerNewsProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *HackerNewsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("Hacker News feed processing request started")
+
+	// Process Hacker News feed
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "hackernews", h.processor.Process); err != nil {
+		logger.Printf("Error processing Hacker News feed: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process Hacker News feed", report)
+		return
+	}
+
+	logger.Printf("Hacker News feed processing completed successfully")
+	response.WriteSuccess(w, "Hacker News feed processed successfully", report)
+}
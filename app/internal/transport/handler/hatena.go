@@ -19,12 +19,15 @@ type HatenaHandler struct {
 func NewHatenaHandler(
 	rssRepo repository.RSSRepository,
 	geminiRepo repository.GeminiRepository,
-	slackRepo repository.SlackRepository,
+	notifier repository.Notifier,
 	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
 	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
 ) *HatenaHandler {
 	return &HatenaHandler{
-		processor: article.NewHatenaProcessor(rssRepo, geminiRepo, slackRepo, processedRepo, limiter),
+		processor: article.NewHatenaProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
 	}
 }
 
@@ -34,12 +37,13 @@ func (h *HatenaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger.Printf("Hatena feed processing request started")
 
 	// Process Hatena feed
-	if err := h.processor.Process(r.Context()); err != nil {
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "hatena", h.processor.Process); err != nil {
 		logger.Printf("Error processing Hatena feed: %v", err)
-		response.WriteInternalError(w, "Failed to process Hatena feed")
+		response.WriteInternalErrorWithData(w, "Failed to process Hatena feed", report)
 		return
 	}
 
 	logger.Printf("Hatena feed processing completed successfully")
-	response.WriteSuccess(w, "Hatena feed processed successfully", nil)
+	response.WriteSuccess(w, "Hatena feed processed successfully", report)
 }
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
 )
 
 func TestHatenaHandler_ServeHTTP_GetMethod(t *testing.T) {
@@ -25,7 +27,10 @@ func TestHatenaHandler_ServeHTTP_GetMethod(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	req := httptest.NewRequest("GET", "/process/hatena", nil)
@@ -57,7 +62,10 @@ func TestHatenaHandler_ServeHTTP_ValidRequest(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	req := httptest.NewRequest("POST", "/process/hatena", nil)
@@ -69,3 +77,49 @@ func TestHatenaHandler_ServeHTTP_ValidRequest(t *testing.T) {
 		t.Errorf("Expected status 200, got %d", w.Code)
 	}
 }
+
+func TestHatenaHandler_ServeHTTP_RunReport(t *testing.T) {
+	handler := NewHatenaHandler(
+		&mocks.MockHatenaRSSRepo{},
+		&mocks.MockGeminiRepo{},
+		&mocks.MockSlackRepo{},
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	req := httptest.NewRequest("POST", "/process/hatena", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var result struct {
+		Data struct {
+			Status string               `json:"status"`
+			Feeds  []article.FeedReport `json:"feeds"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if result.Data.Status != article.RunStatusOK {
+		t.Errorf("Expected run status ok, got %q", result.Data.Status)
+	}
+	if len(result.Data.Feeds) != 1 {
+		t.Fatalf("Expected 1 feed report, got %d", len(result.Data.Feeds))
+	}
+	feed := result.Data.Feeds[0]
+	if feed.Feed != "hatena" || feed.Status != article.RunStatusOK {
+		t.Errorf("Expected hatena ok, got %s %s", feed.Feed, feed.Status)
+	}
+	if feed.Failed != 0 || feed.Succeeded != feed.Attempted {
+		t.Errorf("Unexpected counts: %+v", feed)
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type History struct {
+	historyService *service.History
+}
+
+func NewHistory(historyService *service.History) *History {
+	return &History{
+		historyService: historyService,
+	}
+}
+
+func (h *History) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	query := service.HistoryQuery{
+		Source: r.URL.Query().Get("source"),
+		Query:  r.URL.Query().Get("q"),
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.WriteBadRequest(w, "limit must be a positive integer")
+			return
+		}
+		query.Limit = n
+	}
+
+	entries, err := h.historyService.Search(r.Context(), query)
+	if err != nil {
+		logger.Printf("Error searching history source=%s q=%s: %v", query.Source, query.Query, err)
+		response.WriteInternalError(w, "Failed to search history")
+		return
+	}
+
+	response.WriteSuccess(w, "History retrieved successfully", entries)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func TestHistory_ServeHTTP(t *testing.T) {
+	handler := NewHistory(service.NewHistory(&mocks.MockProcessedRepo{}))
+
+	req := httptest.NewRequest("GET", "/history?source=hatena&q=go&limit=10", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestHistory_ServeHTTP_InvalidLimit(t *testing.T) {
+	handler := NewHistory(service.NewHistory(&mocks.MockProcessedRepo{}))
+
+	req := httptest.NewRequest("GET", "/history?limit=abc", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// LeaderChecker reports whether this replica currently leads (service.LeaderElector)
+type LeaderChecker interface {
+	IsLeader() bool
+	Holder() string
+}
+
+// LeaderOnly skips scheduled runs on replicas that are not the leader, so that replicas sharing a
+// schedule do not post twice. ?force=true runs the feed anyway (manual runs).
+type LeaderOnly struct {
+	feed   string
+	leader LeaderChecker
+	next   http.Handler
+}
+
+func NewLeaderOnly(feed string, leader LeaderChecker, next http.Handler) *LeaderOnly {
+	return &LeaderOnly{feed: feed, leader: leader, next: next}
+}
+
+func (h *LeaderOnly) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.leader.IsLeader() || r.URL.Query().Get("force") == "true" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+	logger.Printf("Replica is not the leader, skipping feed=%s holder=%s", h.feed, h.leader.Holder())
+	response.WriteSuccess(w, "Feed "+h.feed+" skipped on a follower replica", map[string]interface{}{
+		"skipped": true,
+		"leader":  false,
+	})
+}
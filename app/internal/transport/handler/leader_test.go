@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type stubLeader bool
+
+func (s stubLeader) IsLeader() bool { return bool(s) }
+func (s stubLeader) Holder() string { return "replica-a" }
+
+func TestLeaderOnly_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		leader bool
+		path   string
+		ran    bool
+	}{
+		{"leader", true, "/process/reddit", true},
+		{"follower", false, "/process/reddit", false},
+		{"forced on follower", false, "/process/reddit?force=true", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			handler := NewLeaderOnly("reddit", stubLeader(tt.leader), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ran = true
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("POST", tt.path, nil))
+
+			if ran != tt.ran {
+				t.Errorf("Expected ran=%v, got %v", tt.ran, ran)
+			}
+			if !tt.ran && (w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"skipped":true`)) {
+				t.Errorf("Expected a successful skip response, got %d %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -19,12 +19,15 @@ type LobstersHandler struct {
 func NewLobstersHandler(
 	rssRepo repository.RSSRepository,
 	geminiRepo repository.GeminiRepository,
-	slackRepo repository.SlackRepository,
+	notifier repository.Notifier,
 	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
 	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
 ) *LobstersHandler {
 	return &LobstersHandler{
-		processor: article.NewLobstersProcessor(rssRepo, geminiRepo, slackRepo, processedRepo, limiter),
+		processor: article.NewLobstersProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
 	}
 }
 
@@ -34,12 +37,13 @@ func (h *LobstersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger.Printf("Lobsters feed processing request started")
 
 	// Process Lobsters feed
-	if err := h.processor.Process(r.Context()); err != nil {
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "lobsters", h.processor.Process); err != nil {
 		logger.Printf("Error processing Lobsters feed: %v", err)
-		response.WriteInternalError(w, "Failed to process Lobsters feed")
+		response.WriteInternalErrorWithData(w, "Failed to process Lobsters feed", report)
 		return
 	}
 
 	logger.Printf("Lobsters feed processing completed successfully")
-	response.WriteSuccess(w, "Lobsters feed processed successfully", nil)
+	response.WriteSuccess(w, "Lobsters feed processed successfully", report)
 }
@@ -25,7 +25,10 @@ func TestLobstersHandler_ServeHTTP_PostMethod(t *testing.T) {
 		&mocks.MockGeminiRepo{},
 		&mocks.MockSlackRepo{},
 		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
 		&mocks.MockLimiter{},
+		nil, // sequential processing
 	)
 
 	req := httptest.NewRequest("POST", "/process/lobsters", nil)
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type OPMLHandler struct {
+	processor *article.OPMLProcessor
+}
+
+func NewOPMLHandler(
+	opmlSource repository.OPMLSource,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *OPMLHandler {
+	return &OPMLHandler{
+		processor: article.NewOPMLProcessor(opmlSource, rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *OPMLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("OPML feeds processing request started")
+
+	// Process every feed listed in the OPML file
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "opml", h.processor.Process); err != nil {
+		logger.Printf("Error processing OPML feeds: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process OPML feeds", report)
+		return
+	}
+
+	logger.Printf("OPML feeds processing completed successfully")
+	response.WriteSuccess(w, "OPML feeds processed successfully", report)
+}
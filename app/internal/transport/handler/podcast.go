@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type PodcastHandler struct {
+	processor *article.PodcastProcessor
+}
+
+func NewPodcastHandler(
+	feedURLs []string,
+	maxEpisodes int,
+	transcriber repository.Transcriber,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *PodcastHandler {
+	return &PodcastHandler{
+		processor: article.NewPodcastProcessor(feedURLs, maxEpisodes, transcriber, rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *PodcastHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("Podcast feeds processing request started")
+
+	// Process podcast feeds
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "podcast", h.processor.Process); err != nil {
+		logger.Printf("Error processing podcast feeds: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process podcast feeds", report)
+		return
+	}
+
+	logger.Printf("Podcast feeds processing completed successfully")
+	response.WriteSuccess(w, "Podcast feeds processed successfully", report)
+}
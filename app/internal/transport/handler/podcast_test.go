@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestPodcastHandler_ServeHTTP_PostMethod(t *testing.T) {
+	transcriber := &mocks.MockTranscriber{Transcript: "Welcome to the show."}
+	handler := NewPodcastHandler(
+		[]string{"https://podcast.example.com/podcast.xml"},
+		3,
+		transcriber,
+		&mocks.MockPodcastRepo{},
+		&mocks.MockGeminiRepo{},
+		&mocks.MockSlackRepo{},
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	req := httptest.NewRequest("POST", "/process/podcast", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if len(transcriber.AudioURLs) != 1 {
+		t.Errorf("Expected the episode to be transcribed, got %v", transcriber.AudioURLs)
+	}
+}
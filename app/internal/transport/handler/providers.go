@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// ProvidersStatus is the summarizer health shown by the web UI and status pages
+type ProvidersStatus struct {
+	// Status is the best state among the providers: summarization works while any provider does
+	Status    string                      `json:"status"`
+	Providers []repository.ProviderStatus `json:"providers"`
+}
+
+// Providers reports per-provider summarizer health (error rate, circuit state, last success)
+type Providers struct {
+	statuses func() []repository.ProviderStatus
+}
+
+func NewProviders(statuses func() []repository.ProviderStatus) *Providers {
+	return &Providers{
+		statuses: statuses,
+	}
+}
+
+func (h *Providers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	providers := h.statuses()
+	status := repository.ProviderStateDown
+	for _, provider := range providers {
+		switch {
+		case provider.State == repository.ProviderStateOK:
+			status = repository.ProviderStateOK
+		case provider.State == repository.ProviderStateDegraded && status == repository.ProviderStateDown:
+			status = repository.ProviderStateDegraded
+		}
+	}
+	response.WriteSuccess(w, "Provider status retrieved successfully", ProvidersStatus{Status: status, Providers: providers})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestProviders_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		states   []string
+		expected string
+	}{
+		{name: "one region down, one ok", states: []string{repository.ProviderStateDown, repository.ProviderStateOK}, expected: repository.ProviderStateOK},
+		{name: "degraded", states: []string{repository.ProviderStateDown, repository.ProviderStateDegraded}, expected: repository.ProviderStateDegraded},
+		{name: "all down", states: []string{repository.ProviderStateDown}, expected: repository.ProviderStateDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewProviders(func() []repository.ProviderStatus {
+				var statuses []repository.ProviderStatus
+				for _, state := range tt.states {
+					statuses = append(statuses, repository.ProviderStatus{Name: "vertex:" + state, State: state})
+				}
+				return statuses
+			})
+
+			req := httptest.NewRequest("GET", "/api/v1/providers", nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			var body struct {
+				Data ProvidersStatus `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid response: %v", err)
+			}
+			if body.Data.Status != tt.expected || len(body.Data.Providers) != len(tt.states) {
+				t.Errorf("Expected status %s with %d providers, got %+v", tt.expected, len(tt.states), body.Data)
+			}
+		})
+	}
+}
@@ -19,12 +19,15 @@ type RedditHandler struct {
 func NewRedditHandler(
 	rssRepo repository.RSSRepository,
 	geminiRepo repository.GeminiRepository,
-	slackRepo repository.SlackRepository,
+	notifier repository.Notifier,
 	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
 	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
 ) *RedditHandler {
 	return &RedditHandler{
-		processor: article.NewRedditProcessor(rssRepo, geminiRepo, slackRepo, processedRepo, limiter),
+		processor: article.NewRedditProcessor(rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
 	}
 }
 
@@ -34,12 +37,13 @@ func (h *RedditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger.Printf("Reddit feed processing request started")
 
 	// Process Reddit feed
-	if err := h.processor.Process(r.Context()); err != nil {
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "reddit", h.processor.Process); err != nil {
 		logger.Printf("Error processing Reddit feed: %v", err)
-		response.WriteInternalError(w, "Failed to process Reddit feed")
+		response.WriteInternalErrorWithData(w, "Failed to process Reddit feed", report)
 		return
 	}
 
 	logger.Printf("Reddit feed processing completed successfully")
-	response.WriteSuccess(w, "Reddit feed processed successfully", nil)
+	response.WriteSuccess(w, "Reddit feed processed successfully", report)
 }
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// Reprocess lets a manual run ignore the processed index: ?reprocess=true summarizes and posts
+// every article of the feed's current items again, ?reprocess=<article URL> (repeatable) only
+// those articles. Their index entries are overwritten. This is separate from ?force=true, which
+// only overrides the schedule checks.
+type Reprocess struct {
+	next http.Handler
+}
+
+func NewReprocess(next http.Handler) *Reprocess {
+	return &Reprocess{next: next}
+}
+
+func (h *Reprocess) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()["reprocess"]
+	if len(values) == 0 {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	var links []string
+	for _, value := range values {
+		if value == "true" {
+			links = nil
+			break
+		}
+		if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			response.WriteBadRequest(w, "reprocess must be true or an article URL: "+value)
+			return
+		}
+		links = append(links, value)
+	}
+
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+	logger.Printf("Reprocessing requested path=%s urls=%v", r.URL.Path, links)
+	h.next.ServeHTTP(w, r.WithContext(article.WithReprocess(r.Context(), links...)))
+}
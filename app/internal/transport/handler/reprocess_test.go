@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+func TestReprocess_ServeHTTP(t *testing.T) {
+	const a, b = "https://example.com/a", "https://example.com/b"
+	tests := []struct {
+		name        string
+		query       string
+		code        int
+		reprocessed []string // Links the run reprocesses
+		kept        []string // Links the run still dedups
+	}{
+		{"none", "", http.StatusOK, nil, []string{a, b}},
+		{"whole feed", "reprocess=true", http.StatusOK, []string{a, b}, nil},
+		{"one url", "reprocess=" + url.QueryEscape(a), http.StatusOK, []string{a}, []string{b}},
+		{"invalid url", "reprocess=yes", http.StatusBadRequest, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			handler := NewReprocess(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ran = true
+				for _, link := range tt.reprocessed {
+					if !article.Reprocessing(r.Context(), link) {
+						t.Errorf("Expected %s reprocessed", link)
+					}
+				}
+				for _, link := range tt.kept {
+					if article.Reprocessing(r.Context(), link) {
+						t.Errorf("Expected %s deduplicated", link)
+					}
+				}
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("POST", "/process/hatena?"+tt.query, nil))
+
+			if w.Code != tt.code {
+				t.Errorf("Expected status %d, got %d", tt.code, w.Code)
+			}
+			if ran != (tt.code == http.StatusOK) {
+				t.Errorf("Expected ran=%v, got %v", tt.code == http.StatusOK, ran)
+			}
+		})
+	}
+}
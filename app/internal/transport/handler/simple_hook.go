@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+// SimpleHook is the on-demand endpoint for no-code tools (Zapier, IFTTT) that can neither send
+// Bearer headers nor JSON bodies: the URL (and the key checked by middleware.RequireScopeKey)
+// come as query or form parameters, and the answer is a single line of plain text
+type SimpleHook struct {
+	urlService *service.URL
+	usage      *service.Usage
+}
+
+func NewSimpleHook(urlService *service.URL, usage *service.Usage) *SimpleHook {
+	return &SimpleHook{
+		urlService: urlService,
+		usage:      usage,
+	}
+}
+
+func (h *SimpleHook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	target := strings.TrimSpace(r.FormValue("url"))
+	if target == "" {
+		writePlainText(w, http.StatusBadRequest, "error: url is required")
+		return
+	}
+
+	user := usageUser(r)
+	if err := h.usage.Consume(r.Context(), user); err != nil {
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			logger.Printf("On-demand quota exceeded user=%s url=%s", user, target)
+			writePlainText(w, http.StatusTooManyRequests, "error: daily on-demand quota exceeded")
+			return
+		}
+		logger.Printf("Error checking on-demand usage user=%s: %v", user, err)
+		writePlainText(w, http.StatusInternalServerError, "error: failed to check usage")
+		return
+	}
+
+	// The query string carries the key, so only the URL is logged
+	logger.Printf("Simple hook request started url=%s user=%s", target, user)
+
+	if err := h.urlService.Process(r.Context(), target); err != nil {
+		logger.Printf("Error processing URL %s: %v", target, err)
+		writePlainText(w, http.StatusInternalServerError, "error: failed to summarize "+target)
+		return
+	}
+
+	logger.Printf("Simple hook request completed url=%s", target)
+	writePlainText(w, http.StatusOK, "ok: summarized "+target)
+}
+
+func writePlainText(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintln(w, message)
+}
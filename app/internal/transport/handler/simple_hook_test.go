@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func newTestSimpleHook(quota int, counts map[string]int) *SimpleHook {
+	urlService := service.NewURL(&mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{})
+	return NewSimpleHook(urlService, service.NewUsage(&mocks.MockUsageRepo{Counts: counts}, quota))
+}
+
+func TestSimpleHook_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      func() *http.Request
+		quota    int
+		counts   map[string]int
+		expected int
+		body     string
+	}{
+		{
+			name: "GET with url",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "/hooks/simple?url=https://example.com/a", nil)
+			},
+			expected: http.StatusOK,
+			body:     "ok: summarized https://example.com/a\n",
+		},
+		{
+			name: "form POST with url",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "/hooks/simple", strings.NewReader("url=https%3A%2F%2Fexample.com%2Fa"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			expected: http.StatusOK,
+			body:     "ok: summarized https://example.com/a\n",
+		},
+		{
+			name:     "missing url",
+			req:      func() *http.Request { return httptest.NewRequest("GET", "/hooks/simple", nil) },
+			expected: http.StatusBadRequest,
+			body:     "error: url is required\n",
+		},
+		{
+			name: "quota exceeded",
+			req: func() *http.Request {
+				return httptest.NewRequest("GET", "/hooks/simple?url=https://example.com/a", nil)
+			},
+			quota:    1,
+			counts:   map[string]int{"anonymous": 1},
+			expected: http.StatusTooManyRequests,
+			body:     "error: daily on-demand quota exceeded\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newTestSimpleHook(tt.quota, tt.counts).ServeHTTP(w, tt.req())
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+				t.Errorf("Expected plain text, got %s", ct)
+			}
+		})
+	}
+}
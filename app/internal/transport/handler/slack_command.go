@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+// slackRequestMaxAge rejects replayed slash command requests
+const slackRequestMaxAge = 5 * time.Minute
+
+// SlackCommand serves the /summaries slash command. Requests are authenticated
+// with the Slack signing secret instead of a bearer token.
+type SlackCommand struct {
+	signingSecret string
+	usage         *service.Usage
+	now           func() time.Time
+}
+
+func NewSlackCommand(signingSecret string, usage *service.Usage) *SlackCommand {
+	return &SlackCommand{
+		signingSecret: signingSecret,
+		usage:         usage,
+		now:           time.Now,
+	}
+}
+
+type slackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func (h *SlackCommand) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Printf("Error reading slash command body: %v", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if !verifySlackSignature(h.signingSecret, h.now(), r.Header, body) {
+		logger.Printf("Slash command signature verification failed")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	userID := form.Get("user_id")
+
+	var text string
+	switch strings.TrimSpace(form.Get("text")) {
+	case "usage", "":
+		usage, err := h.usage.Today(r.Context(), "slack:"+userID)
+		if err != nil {
+			logger.Printf("Error loading usage for slash command user=%s: %v", userID, err)
+			text = "利用状況を取得できませんでした"
+			break
+		}
+		text = formatSlackUsage(usage)
+	default:
+		text = fmt.Sprintf("使い方: `%s usage` で本日のオンデマンド要約の利用状況を表示します", form.Get("command"))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slackCommandResponse{ResponseType: "ephemeral", Text: text})
+}
+
+// verifySlackSignature checks Slack's v0 request signature over "v0:<timestamp>:<body>"
+func verifySlackSignature(signingSecret string, now time.Time, header http.Header, body []byte) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), bytes.TrimSpace([]byte(header.Get("X-Slack-Signature"))))
+}
+
+func formatSlackUsage(usage service.UserUsage) string {
+	if usage.Quota == 0 {
+		return fmt.Sprintf("📊 本日のオンデマンド要約: %d 件 (上限なし)", usage.Count)
+	}
+	remaining := usage.Quota - usage.Count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("📊 本日のオンデマンド要約: %d / %d 件 (残り %d 件、UTC 0時にリセット)", usage.Count, usage.Quota, remaining)
+}
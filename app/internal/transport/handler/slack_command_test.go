@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func signedSlackCommand(secret, body string, ts time.Time) *http.Request {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+
+	req := httptest.NewRequest("POST", "/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlackCommand_Usage(t *testing.T) {
+	usage := service.NewUsage(&mocks.MockUsageRepo{Counts: map[string]int{"slack:U1": 3}}, 10)
+	handler := NewSlackCommand("secret", usage)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedSlackCommand("secret", "command=%2Fsummaries&text=usage&user_id=U1", time.Now()))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var resp slackCommandResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.ResponseType != "ephemeral" || !strings.Contains(resp.Text, "3 / 10") {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestSlackCommand_RejectsBadSignature(t *testing.T) {
+	handler := NewSlackCommand("secret", service.NewUsage(&mocks.MockUsageRepo{}, 10))
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"wrong secret", signedSlackCommand("other", "text=usage&user_id=U1", time.Now())},
+		{"stale timestamp", signedSlackCommand("secret", "text=usage&user_id=U1", time.Now().Add(-10*time.Minute))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected status 401, got %d", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+// SlackInteraction receives Slack interactivity callbacks for the summary buttons.
+// Slack expects an acknowledgement within 3 seconds, so the summary is generated after responding.
+type SlackInteraction struct {
+	signingSecret string
+	actions       *service.SlackActions
+	now           func() time.Time
+	dispatch      func(func()) // Runs the action after the acknowledgement
+}
+
+func NewSlackInteraction(signingSecret string, actions *service.SlackActions) *SlackInteraction {
+	return &SlackInteraction{
+		signingSecret: signingSecret,
+		actions:       actions,
+		now:           time.Now,
+		dispatch:      func(f func()) { go f() },
+	}
+}
+
+// slackInteractionPayload is the subset of a block_actions payload used here
+type slackInteractionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+func (h *SlackInteraction) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Printf("Error reading interaction body: %v", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if !verifySlackSignature(h.signingSecret, h.now(), r.Header, body) {
+		logger.Printf("Interaction signature verification failed")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+	var payload slackInteractionPayload
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		logger.Printf("Error decoding interaction payload: %v", err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Other interaction types (shortcuts, modals) are acknowledged and ignored
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	action := payload.Actions[0]
+	target, err := repository.ParseSlackActionTarget(action.Value)
+	if err != nil || target.URL == "" {
+		logger.Printf("Invalid interaction action value action=%s: %v", action.ActionID, err)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	// Replies go to the summary's thread (or the thread the summary is in)
+	threadTS := payload.Message.ThreadTS
+	if threadTS == "" {
+		threadTS = payload.Message.TS
+	}
+
+	// The request context ends with the acknowledgement
+	ctx := context.WithoutCancel(r.Context())
+	h.dispatch(func() {
+		if err := h.actions.Handle(ctx, action.ActionID, target, "slack:"+payload.User.ID, payload.Channel.ID, threadTS); err != nil {
+			logger.Printf("Error handling interaction action=%s url=%s: %v", action.ActionID, target.URL, err)
+		}
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func TestSlackInteraction_RepliesInThread(t *testing.T) {
+	replier := &mocks.MockThreadReplier{}
+	actions := service.NewSlackActions(&mocks.MockGeminiRepo{}, nil, replier, service.NewUsage(&mocks.MockUsageRepo{}, 0))
+	handler := NewSlackInteraction("secret", actions)
+	handler.dispatch = func(f func()) { f() }
+
+	payload := `{"type":"block_actions","user":{"id":"U1"},"channel":{"id":"C1"},"message":{"ts":"1700000000.000100"},` +
+		`"actions":[{"action_id":"summary_detail","value":"{\"url\":\"https://example.com/a\",\"source\":\"reddit\"}"}]}`
+	body := url.Values{"payload": {payload}}.Encode()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedSlackCommand("secret", body, time.Now()))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if len(replier.Replies) != 1 || replier.Replies[0].Channel != "C1" || replier.Replies[0].ThreadTS != "1700000000.000100" {
+		t.Errorf("Expected a reply in the summary's thread, got %+v", replier.Replies)
+	}
+}
+
+func TestSlackInteraction_RejectsBadSignature(t *testing.T) {
+	handler := NewSlackInteraction("secret", nil)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedSlackCommand("other", "payload=%7B%7D", time.Now()))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// SummaryArchive re-serves the archived summary of an article (?url=, matched after normalization)
+type SummaryArchive struct {
+	archiveRepo repository.SummaryArchiveRepository
+}
+
+func NewSummaryArchive(archiveRepo repository.SummaryArchiveRepository) *SummaryArchive {
+	return &SummaryArchive{
+		archiveRepo: archiveRepo,
+	}
+}
+
+func (h *SummaryArchive) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	articleURL := r.URL.Query().Get("url")
+	if articleURL == "" {
+		response.WriteBadRequest(w, "url is required")
+		return
+	}
+
+	entry, err := h.archiveRepo.Get(r.Context(), articleURL)
+	if err != nil {
+		logger.Printf("Error loading archived summary url=%s: %v", articleURL, err)
+		response.WriteInternalError(w, "Failed to load archived summary")
+		return
+	}
+	if entry == nil {
+		response.WriteError(w, http.StatusNotFound, "No archived summary for "+articleURL)
+		return
+	}
+
+	response.WriteSuccess(w, "Archived summary retrieved successfully", entry)
+}
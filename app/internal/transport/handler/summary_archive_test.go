@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+)
+
+func TestSummaryArchive_ServeHTTP(t *testing.T) {
+	handler := NewSummaryArchive(&mocks.MockSummaryArchiveRepo{Entries: map[string]*repository.SummaryArchiveEntry{
+		"https://example.com/a": {Title: "A", URL: "https://example.com/a", Summary: "sa", CommentSummary: "ca"},
+	}})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "archived", query: "?url=" + url.QueryEscape("https://example.com/a"), wantStatus: http.StatusOK},
+		{name: "not archived", query: "?url=" + url.QueryEscape("https://example.com/b"), wantStatus: http.StatusNotFound},
+		{name: "missing url", query: "", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/summaries/archive"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result struct {
+				Data repository.SummaryArchiveEntry `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if result.Data.Summary != "sa" || result.Data.CommentSummary != "ca" {
+				t.Errorf("Expected the archived summaries, got %+v", result.Data)
+			}
+		})
+	}
+}
+
+func TestSummaryArchive_ServeHTTP_Error(t *testing.T) {
+	handler := NewSummaryArchive(&mocks.MockSummaryArchiveRepo{Err: errors.New("storage down")})
+
+	req := httptest.NewRequest("GET", "/api/v1/summaries/archive?url=https://example.com/a", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+}
@@ -2,28 +2,127 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
 
+	"github.com/pep299/article-summarizer-v3/internal/repository"
 	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/transport/middleware"
 	"github.com/pep299/article-summarizer-v3/internal/transport/response"
 )
 
 type Webhook struct {
 	urlService *service.URL
+	usage      *service.Usage
+	fields     WebhookFields
 }
 
-func NewWebhook(urlService *service.URL) *Webhook {
+// WebhookFields names the payload fields holding the URL and the requester. Slack Workflow Builder
+// sends flat JSON (or form-encoded) payloads keyed by the variable names chosen in the workflow, so
+// they are configurable; "url" and "user" are always accepted as well.
+type WebhookFields struct {
+	URL  string
+	User string
+}
+
+func NewWebhook(urlService *service.URL, usage *service.Usage, fields WebhookFields) *Webhook {
 	return &Webhook{
 		urlService: urlService,
+		usage:      usage,
+		fields:     fields,
 	}
 }
 
 type webhookRequest struct {
-	URL string `json:"url"`
+	URL  string `json:"url"`
+	User string `json:"user,omitempty"` // Slack user ID of the requester, when relayed from Slack
+	// Translate asks for the summary to be delivered with a translation into ja or en as well
+	Translate string `json:"translate,omitempty"`
+}
+
+// parseWebhookRequest reads a JSON or form-encoded payload. Only flat string values are read;
+// Slack's formatting of links (<https://...|label>) and user mentions (<@U123>) is stripped.
+func parseWebhookRequest(contentType string, body []byte, fields WebhookFields) (webhookRequest, error) {
+	values := make(map[string]string)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return webhookRequest{}, fmt.Errorf("invalid form payload: %w", err)
+		}
+		for key := range form {
+			values[key] = form.Get(key)
+		}
+	} else {
+		var payload map[string]any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return webhookRequest{}, fmt.Errorf("invalid JSON payload: %w", err)
+		}
+		for key, value := range payload {
+			if str, ok := value.(string); ok {
+				values[key] = str
+			}
+		}
+	}
+
+	lookup := func(field, fallback string) string {
+		if field != "" {
+			if value := strings.TrimSpace(values[field]); value != "" {
+				return value
+			}
+		}
+		return strings.TrimSpace(values[fallback])
+	}
+	return webhookRequest{
+		URL:       unwrapSlackLink(lookup(fields.URL, "url")),
+		User:      unwrapSlackUser(lookup(fields.User, "user")),
+		Translate: strings.ToLower(strings.TrimSpace(values["translate"])),
+	}, nil
+}
+
+// unwrapSlackLink turns Slack's <https://example.com|example.com> link format into the URL
+func unwrapSlackLink(value string) string {
+	if !strings.HasPrefix(value, "<") || !strings.HasSuffix(value, ">") {
+		return value
+	}
+	link, _, _ := strings.Cut(value[1:len(value)-1], "|")
+	return link
+}
+
+// unwrapSlackUser turns a <@U123> (or <@U123|name>) mention into the user ID
+func unwrapSlackUser(value string) string {
+	if !strings.HasPrefix(value, "<@") || !strings.HasSuffix(value, ">") {
+		return value
+	}
+	id, _, _ := strings.Cut(value[2:len(value)-1], "|")
+	return id
+}
+
+// usageUser identifies the API token a request counts against. The quota is always keyed on it,
+// since the payload's user field is chosen by the client.
+func usageUser(r *http.Request) string {
+	if tokenID := middleware.TokenIDFromContext(r.Context()); tokenID != "" {
+		return tokenID
+	}
+	return "anonymous"
+}
+
+// usageUsers are the keys a request is charged to: its token and, when the payload names one, the
+// Slack user it was made for (so that /summaries usage and the usage report show them)
+func usageUsers(r *http.Request, slackUser string) []string {
+	if slackUser != "" {
+		return []string{usageUser(r), "slack:" + slackUser}
+	}
+	return []string{usageUser(r)}
 }
 
 func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -37,10 +136,10 @@ func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req webhookRequest
-	if err := json.Unmarshal(bodyBytes, &req); err != nil {
-		logger.Printf("Invalid JSON in webhook request: %v, body: %s", err, string(bodyBytes))
-		response.WriteBadRequest(w, "Invalid JSON")
+	req, err := parseWebhookRequest(r.Header.Get("Content-Type"), bodyBytes, h.fields)
+	if err != nil {
+		logger.Printf("Invalid webhook request: %v, body: %s", err, string(bodyBytes))
+		response.WriteBadRequest(w, "Invalid payload")
 		return
 	}
 
@@ -50,9 +149,34 @@ func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logger.Printf("Webhook request started url=%s", req.URL)
+	if req.Translate != "" {
+		if err := repository.ValidateTranslationLanguage(req.Translate); err != nil {
+			logger.Printf("Invalid translation language in webhook request: %v", err)
+			response.WriteBadRequest(w, "translate must be ja or en")
+			return
+		}
+	}
+
+	users := usageUsers(r, req.User)
+	user := strings.Join(users, ",")
+	if err := h.usage.Consume(r.Context(), users...); err != nil {
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			logger.Printf("On-demand quota exceeded user=%s url=%s", user, req.URL)
+			response.WriteError(w, http.StatusTooManyRequests, "Daily on-demand quota exceeded")
+			return
+		}
+		logger.Printf("Error checking on-demand usage user=%s: %v", user, err)
+		response.WriteInternalError(w, "Failed to check usage")
+		return
+	}
+
+	logger.Printf("Webhook request started url=%s user=%s translate=%s", req.URL, user, req.Translate)
 
-	if err := h.urlService.Process(r.Context(), req.URL); err != nil {
+	ctx := r.Context()
+	if req.Translate != "" {
+		ctx = article.WithSummaryTranslation(ctx, req.Translate)
+	}
+	if err := h.urlService.Process(ctx, req.URL); err != nil {
 		logger.Printf("Error processing URL %s: %v", req.URL, err)
 		response.WriteInternalError(w, err.Error())
 		return
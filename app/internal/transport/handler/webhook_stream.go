@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+// WebhookStream is the on-demand endpoint for the web UI and CLI: the request is queued and its
+// progress (queued → fetching → extracting → summarizing → posting → done/error) is streamed as
+// Server-Sent Events instead of answering once at the end
+type WebhookStream struct {
+	queue *service.OnDemandQueue
+	usage *service.Usage
+}
+
+func NewWebhookStream(queue *service.OnDemandQueue, usage *service.Usage) *WebhookStream {
+	return &WebhookStream{
+		queue: queue,
+		usage: usage,
+	}
+}
+
+func (h *WebhookStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.WriteInternalError(w, "Streaming not supported")
+		return
+	}
+
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Printf("Invalid JSON in webhook stream request: %v", err)
+		response.WriteBadRequest(w, "Invalid JSON")
+		return
+	}
+	if req.URL == "" {
+		response.WriteBadRequest(w, "URL is required")
+		return
+	}
+
+	// Quota errors are still plain JSON responses: nothing has been streamed yet
+	users := usageUsers(r, req.User)
+	user := strings.Join(users, ",")
+	if err := h.usage.Consume(r.Context(), users...); err != nil {
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			logger.Printf("On-demand quota exceeded user=%s url=%s", user, req.URL)
+			response.WriteError(w, http.StatusTooManyRequests, "Daily on-demand quota exceeded")
+			return
+		}
+		logger.Printf("Error checking on-demand usage user=%s: %v", user, err)
+		response.WriteInternalError(w, "Failed to check usage")
+		return
+	}
+
+	logger.Printf("Webhook stream request started url=%s user=%s", req.URL, user)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Keep proxies from buffering the stream
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for event := range h.queue.Submit(r.Context(), req.URL) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			logger.Printf("Error marshaling progress event: %v", err)
+			continue
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Stage, data)
+		flusher.Flush()
+
+		switch event.Stage {
+		case service.StageDone:
+			logger.Printf("Webhook stream request completed url=%s", req.URL)
+		case service.StageError:
+			logger.Printf("Error processing URL %s: %s", req.URL, event.Message)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+)
+
+func newTestWebhookStream(quota int, counts map[string]int) *WebhookStream {
+	urlService := service.NewURL(&mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{})
+	usage := service.NewUsage(&mocks.MockUsageRepo{Counts: counts}, quota)
+	return NewWebhookStream(service.NewOnDemandQueue(urlService, 1), usage)
+}
+
+func TestWebhookStream_ServeHTTP(t *testing.T) {
+	handler := newTestWebhookStream(0, nil)
+
+	req := httptest.NewRequest("POST", "/webhook/stream", strings.NewReader(`{"url":"https://example.com/article"}`))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", ct)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		"event: queued\ndata: {\"stage\":\"queued\",\"position\":1}\n\n",
+		"event: posting\ndata: {\"stage\":\"posting\"}\n\n",
+		"event: done\ndata: {\"stage\":\"done\"}\n\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in stream, got %s", want, body)
+		}
+	}
+}
+
+func TestWebhookStream_RejectsBeforeStreaming(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"invalid JSON", `{`, http.StatusBadRequest},
+		{"missing URL", `{}`, http.StatusBadRequest},
+		{"quota exceeded", `{"url":"https://example.com/article","user":"U1"}`, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestWebhookStream(1, map[string]int{"slack:U1": 1})
+			req := httptest.NewRequest("POST", "/webhook/stream", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("Expected status %d, got %d", tt.code, w.Code)
+			}
+			if w.Header().Get("Content-Type") == "text/event-stream" {
+				t.Error("Expected a plain JSON error response")
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+	"github.com/pep299/article-summarizer-v3/internal/service"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+)
+
+func TestParseWebhookRequest(t *testing.T) {
+	workflowFields := WebhookFields{URL: "link", User: "submitter"}
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		fields      WebhookFields
+		expected    webhookRequest
+	}{
+		{
+			name:        "default JSON",
+			contentType: "application/json",
+			body:        `{"url":"https://example.com/a","user":"U123"}`,
+			fields:      WebhookFields{URL: "url", User: "user"},
+			expected:    webhookRequest{URL: "https://example.com/a", User: "U123"},
+		},
+		{
+			name:        "Workflow Builder JSON with Slack formatting",
+			contentType: "application/json",
+			body:        `{"link":"<https://example.com/a|example.com/a>","submitter":"<@U123>","note":"read this"}`,
+			fields:      workflowFields,
+			expected:    webhookRequest{URL: "https://example.com/a", User: "U123"},
+		},
+		{
+			name:        "form-encoded",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			body:        "link=https%3A%2F%2Fexample.com%2Fa&submitter=U123",
+			fields:      workflowFields,
+			expected:    webhookRequest{URL: "https://example.com/a", User: "U123"},
+		},
+		{
+			name:        "url and user remain accepted",
+			contentType: "application/json",
+			body:        `{"url":"https://example.com/a"}`,
+			fields:      workflowFields,
+			expected:    webhookRequest{URL: "https://example.com/a"},
+		},
+		{
+			name:        "translation request",
+			contentType: "application/json",
+			body:        `{"url":"https://example.com/a","translate":" EN "}`,
+			fields:      workflowFields,
+			expected:    webhookRequest{URL: "https://example.com/a", Translate: "en"},
+		},
+		{
+			name:        "non-string values are ignored",
+			contentType: "application/json",
+			body:        `{"link":{"href":"https://example.com/a"}}`,
+			fields:      workflowFields,
+			expected:    webhookRequest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWebhookRequest(tt.contentType, []byte(tt.body), tt.fields)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := parseWebhookRequest("application/json", []byte(`not json`), workflowFields); err == nil {
+		t.Error("Expected an error for an invalid JSON payload")
+	}
+}
+
+func TestWebhook_ServeHTTP_WorkflowBuilderPayload(t *testing.T) {
+	usageRepo := &mocks.MockUsageRepo{}
+	handler := NewWebhook(
+		service.NewURL(&mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}),
+		service.NewUsage(usageRepo, 0),
+		WebhookFields{URL: "link", User: "submitter"},
+	)
+
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"link":"https://example.com/a","submitter":"<@U123>"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Data["url"] != "https://example.com/a" {
+		t.Errorf("Expected the mapped URL in the response, got %s", w.Body.String())
+	}
+	if usageRepo.Counts["slack:U123"] != 1 || usageRepo.Counts["anonymous"] != 1 {
+		t.Errorf("Expected the usage to count against the token and the Slack user, got %v", usageRepo.Counts)
+	}
+}
+
+func TestWebhook_ServeHTTP_QuotaKeyedOnToken(t *testing.T) {
+	handler := NewWebhook(
+		service.NewURL(&mocks.MockGeminiRepo{}, &mocks.MockSlackRepo{}),
+		service.NewUsage(&mocks.MockUsageRepo{}, 1),
+		WebhookFields{},
+	)
+
+	// A different user field on each request must not reset the token's quota
+	codes := []int{}
+	for _, user := range []string{"U1", "U2"} {
+		req := httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"url":"https://example.com/a","user":"`+user+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected 200 then 429, got %v", codes)
+	}
+}
+
+func TestWebhook_ServeHTTP_Translate(t *testing.T) {
+	notifier := &mocks.MockSlackRepo{}
+	gemini := &mocks.MockGeminiRepo{}
+	handler := NewWebhook(
+		service.NewURL(gemini, article.NewSummaryTranslator(notifier, gemini, "")),
+		service.NewUsage(&mocks.MockUsageRepo{}, 0),
+		WebhookFields{},
+	)
+
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"url":"https://example.com/a","translate":"en"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(notifier.SentOnDemandSummaries) != 1 || !strings.HasSuffix(notifier.SentOnDemandSummaries[0].Summary, "\ntest translation") {
+		t.Errorf("Expected the summary with its translation, got %+v", notifier.SentOnDemandSummaries)
+	}
+
+	req = httptest.NewRequest("POST", "/webhook", strings.NewReader(`{"url":"https://example.com/a","translate":"fr"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest || len(notifier.SentOnDemandSummaries) != 1 {
+		t.Errorf("Expected an unsupported language to be rejected, got %d", w.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type XAccountsHandler struct {
+	processor *article.XAccountsProcessor
+}
+
+func NewXAccountsHandler(
+	accounts []string,
+	bearerToken string,
+	bridgeURL string,
+	rssRepo repository.RSSRepository,
+	urlProcessor article.URLProcessor,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *XAccountsHandler {
+	return &XAccountsHandler{
+		processor: article.NewXAccountsProcessor(accounts, bearerToken, bridgeURL, rssRepo, urlProcessor, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *XAccountsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("X accounts processing request started")
+
+	// Process posts of the followed accounts
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "x", h.processor.Process); err != nil {
+		logger.Printf("Error processing X accounts: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process X accounts", report)
+		return
+	}
+
+	logger.Printf("X accounts processing completed successfully")
+	response.WriteSuccess(w, "X accounts processed successfully", report)
+}
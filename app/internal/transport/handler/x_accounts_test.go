@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/mocks"
+)
+
+func TestXAccountsHandler_ServeHTTP_PostMethod(t *testing.T) {
+	urlProcessor := &mocks.MockURLProcessor{}
+	handler := NewXAccountsHandler(
+		[]string{"golang"},
+		"test-token",
+		"",
+		&mocks.MockXAccountsRepo{},
+		urlProcessor,
+		&mocks.MockProcessedRepo{},
+		&mocks.MockBacklogRepo{},
+		&mocks.MockFeedStatsRepo{},
+		&mocks.MockLimiter{},
+		nil, // sequential processing
+	)
+
+	req := httptest.NewRequest("POST", "/process/x", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if len(urlProcessor.URLs) != 1 {
+		t.Errorf("Expected the linked article to be processed, got %v", urlProcessor.URLs)
+	}
+}
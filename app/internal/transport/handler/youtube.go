@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+
+	"github.com/pep299/article-summarizer-v3/internal/repository"
+	"github.com/pep299/article-summarizer-v3/internal/service/article"
+	"github.com/pep299/article-summarizer-v3/internal/service/limiter"
+	"github.com/pep299/article-summarizer-v3/internal/transport/response"
+)
+
+type YouTubeHandler struct {
+	processor *article.YouTubeProcessor
+}
+
+func NewYouTubeHandler(
+	channelIDs []string,
+	rssRepo repository.RSSRepository,
+	geminiRepo repository.GeminiRepository,
+	notifier repository.Notifier,
+	processedRepo repository.ProcessedArticleRepository,
+	backlogRepo repository.BacklogRepository,
+	statsRepo repository.FeedStatsRepository,
+	limiter limiter.ArticleLimiter,
+	concurrency *limiter.ConcurrencyController,
+) *YouTubeHandler {
+	return &YouTubeHandler{
+		processor: article.NewYouTubeProcessor(channelIDs, rssRepo, geminiRepo, notifier, processedRepo, backlogRepo, statsRepo, limiter, concurrency),
+	}
+}
+
+func (h *YouTubeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+
+	logger.Printf("YouTube feed processing request started")
+
+	// Process YouTube feed
+	ctx, report := article.WithRunReport(r.Context())
+	if err := article.RunFeed(ctx, "youtube", h.processor.Process); err != nil {
+		logger.Printf("Error processing YouTube feed: %v", err)
+		response.WriteInternalErrorWithData(w, "Failed to process YouTube feed", report)
+		return
+	}
+
+	logger.Printf("YouTube feed processing completed successfully")
+	response.WriteSuccess(w, "YouTube feed processed successfully", report)
+}
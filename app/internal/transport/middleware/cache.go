@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache keeps successful GET responses in memory for a TTL, keyed by URL and Accept header,
+// and tags them with an ETag so that clients polling with If-None-Match get 304 without a body
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time // For testing
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// NewResponseCache keeps up to maxEntries responses for ttl each
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*cachedResponse),
+	}
+}
+
+// CacheResponses creates a middleware serving GET requests from the cache (next itself for a nil
+// cache). Wrap it inside the auth middleware: the cache does not tell callers apart.
+func CacheResponses(cache *ResponseCache) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cache == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := r.URL.RequestURI() + "\n" + r.Header.Get("Accept")
+			response := cache.get(key)
+			if response == nil {
+				recorder := &responseRecorder{header: make(http.Header), status: http.StatusOK}
+				next.ServeHTTP(recorder, r)
+				response = &cachedResponse{
+					status: recorder.status,
+					header: recorder.header,
+					body:   recorder.body.Bytes(),
+				}
+				if response.status == http.StatusOK {
+					sum := sha256.Sum256(response.body)
+					response.etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+					cache.put(key, response)
+				}
+				w.Header().Set("X-Cache", "MISS")
+			} else {
+				w.Header().Set("X-Cache", "HIT")
+			}
+			response.write(w, r, cache.ttl)
+		})
+	}
+}
+
+func (c *ResponseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	response, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(response.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return response
+}
+
+func (c *ResponseCache) put(key string, response *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	response.expires = now.Add(c.ttl)
+	if len(c.entries) >= c.maxEntries {
+		// Drop the expired responses, then the one expiring first
+		var oldestKey string
+		var oldest time.Time
+		for k, cached := range c.entries {
+			if !now.Before(cached.expires) {
+				delete(c.entries, k)
+				continue
+			}
+			if oldestKey == "" || cached.expires.Before(oldest) {
+				oldestKey, oldest = k, cached.expires
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, oldestKey)
+		}
+	}
+	c.entries[key] = response
+}
+
+// write sends the response, or 304 when the client already has it
+func (r *cachedResponse) write(w http.ResponseWriter, req *http.Request, ttl time.Duration) {
+	for name, values := range r.header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	if r.etag != "" {
+		w.Header().Set("ETag", r.etag)
+		if w.Header().Get("Cache-Control") == "" {
+			w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(ttl.Seconds())))
+		}
+		if etagMatches(req.Header.Get("If-None-Match"), r.etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(r.body)))
+	w.WriteHeader(r.status)
+	w.Write(r.body)
+}
+
+// etagMatches checks an If-None-Match header ("*" or a list of possibly weak ETags)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// responseRecorder buffers a handler's response
+type responseRecorder struct {
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.body.Write(data)
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingHandler answers with the number of requests it served
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"calls":%d}`, *calls)
+	})
+}
+
+func TestCacheResponses(t *testing.T) {
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	cache := NewResponseCache(10*time.Second, 10)
+	cache.now = func() time.Time { return now }
+	calls := 0
+	handler := CacheResponses(cache)(countingHandler(&calls, http.StatusOK))
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/history?q=go", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("Expected a tagged response, got %d etag=%q cache=%q", first.Code, etag, first.Header().Get("X-Cache"))
+	}
+	if first.Header().Get("Cache-Control") != "max-age=10" {
+		t.Errorf("Expected max-age=10, got %q", first.Header().Get("Cache-Control"))
+	}
+
+	// Served from the cache within the TTL
+	second := get("/history?q=go", "")
+	if calls != 1 || second.Body.String() != `{"calls":1}` || second.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("Expected the cached response, got calls=%d body=%s", calls, second.Body.String())
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the cached headers, got %v", second.Header())
+	}
+
+	// A client that has the response gets 304
+	if notModified := get("/history?q=go", `W/"other", `+etag); notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body, got %d %q", notModified.Code, notModified.Body.String())
+	}
+
+	// Other queries are cached separately
+	get("/history?q=rust", "")
+	if calls != 2 {
+		t.Errorf("Expected another query to reach the handler, got calls=%d", calls)
+	}
+
+	// Expired responses are fetched again
+	now = now.Add(11 * time.Second)
+	if expired := get("/history?q=go", etag); expired.Code != http.StatusOK || calls != 3 {
+		t.Errorf("Expected a fresh response after the TTL, got %d calls=%d", expired.Code, calls)
+	}
+}
+
+func TestCacheResponses_ErrorsNotCached(t *testing.T) {
+	calls := 0
+	handler := CacheResponses(NewResponseCache(time.Minute, 10))(countingHandler(&calls, http.StatusInternalServerError))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/providers", nil))
+		if w.Code != http.StatusInternalServerError || w.Header().Get("ETag") != "" {
+			t.Errorf("Expected the error as is, got %d etag=%q", w.Code, w.Header().Get("ETag"))
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected errors not to be cached, got calls=%d", calls)
+	}
+}
+
+func TestResponseCache_Eviction(t *testing.T) {
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	cache := NewResponseCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "c"} {
+		cache.put(key, &cachedResponse{status: http.StatusOK})
+		now = now.Add(time.Second)
+	}
+	if len(cache.entries) != 2 || cache.get("a") != nil || cache.get("c") == nil {
+		t.Errorf("Expected the oldest response to be evicted, got %v", cache.entries)
+	}
+}
+
+func TestCacheResponses_NilCache(t *testing.T) {
+	next := http.HandlerFunc(mockHandler)
+	handler := CacheResponses(nil)(next)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/feed.xml", nil))
+	if w.Header().Get("X-Cache") != "" || w.Body.String() != "success" {
+		t.Errorf("Expected a nil cache to pass requests through, got %v", w.Header())
+	}
+}
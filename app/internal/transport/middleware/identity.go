@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/funcframework"
+	"google.golang.org/api/idtoken"
+)
+
+const (
+	// IAPAssertionHeader carries the signed JWT Identity-Aware Proxy adds to every proxied request
+	IAPAssertionHeader = "X-Goog-IAP-JWT-Assertion"
+	iapIssuer          = "https://cloud.google.com/iap"
+)
+
+// IdentityVerifier authenticates a request by a proxy- or transport-level identity instead of a bearer token
+type IdentityVerifier interface {
+	// Verify returns the caller's principal (e.g. "iap:alice@example.com")
+	Verify(r *http.Request) (string, error)
+}
+
+// RequireIdentity creates a middleware rejecting requests the verifier cannot authenticate (401).
+// The principal takes the place of the token ID in the context, so audit logs and usage records name the caller.
+func RequireIdentity(verifier IdentityVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := verifier.Verify(r)
+			if err != nil {
+				logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+				logger.Printf("Identity verification failed path=%s: %v", r.URL.Path, err)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), tokenIDKey{}, principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// IdentityDefaultScopes are granted to verified callers that IDENTITY_SCOPES does not list; admin
+// must be granted to a principal explicitly
+var IdentityDefaultScopes = []Scope{ScopeProcess, ScopeWebhook, ScopeRead}
+
+// RequireIdentityScope is RequireScope for identity auth: the principal RequireIdentity authenticated
+// must be granted the scope in identities ("iap:alice@example.com:admin+read", see ParseTokenScopes),
+// or IdentityDefaultScopes when it is not listed. Requests without a principal get 401, principals
+// without the scope get 403.
+func RequireIdentityScope(identities TokenScopes, scope Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := TokenIDFromContext(r.Context())
+			if principal == "" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			scopes, listed := identities[principal]
+			if !listed {
+				scopes = IdentityDefaultScopes
+			}
+			if !hasScope(scopes, scope) {
+				logger := log.New(funcframework.LogWriter(r.Context()), "", 0)
+				logger.Printf("Identity lacks scope principal=%s scope=%s path=%s", principal, scope, r.URL.Path)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IAPVerifier validates Identity-Aware Proxy assertions (ES256, Google's IAP keys) for one audience
+type IAPVerifier struct {
+	audience string
+	validate func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+}
+
+// NewIAPVerifier creates a verifier for the backend's audience
+// ("/projects/NUMBER/global/backendServices/ID" or "/projects/NUMBER/apps/PROJECT_ID")
+func NewIAPVerifier(audience string) *IAPVerifier {
+	return &IAPVerifier{
+		audience: audience,
+		validate: idtoken.Validate,
+	}
+}
+
+func (v *IAPVerifier) Verify(r *http.Request) (string, error) {
+	assertion := r.Header.Get(IAPAssertionHeader)
+	if assertion == "" {
+		return "", errors.New("missing IAP assertion header")
+	}
+
+	payload, err := v.validate(r.Context(), assertion, v.audience)
+	if err != nil {
+		return "", fmt.Errorf("validating IAP assertion: %w", err)
+	}
+	// idtoken also accepts Google service account tokens; only IAP may vouch for the caller
+	if payload.Issuer != iapIssuer {
+		return "", fmt.Errorf("unexpected IAP assertion issuer %q", payload.Issuer)
+	}
+
+	if email, _ := payload.Claims["email"].(string); email != "" {
+		return "iap:" + email, nil
+	}
+	return "iap:" + payload.Subject, nil
+}
+
+// ClientCertVerifier accepts requests whose TLS client certificate was verified by the server
+// (self-hosted deployments terminating TLS with TLS_CLIENT_CA_FILE)
+type ClientCertVerifier struct{}
+
+func (ClientCertVerifier) Verify(r *http.Request) (string, error) {
+	if r.TLS == nil {
+		return "", errors.New("request was not received over TLS")
+	}
+	if len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return "", errors.New("no verified client certificate")
+	}
+	return "mtls:" + r.TLS.VerifiedChains[0][0].Subject.CommonName, nil
+}
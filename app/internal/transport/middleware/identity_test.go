@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/api/idtoken"
+)
+
+func stubIAPVerifier(payload *idtoken.Payload, err error) *IAPVerifier {
+	verifier := NewIAPVerifier("/projects/123/apps/test-project")
+	verifier.validate = func(ctx context.Context, token, audience string) (*idtoken.Payload, error) {
+		if token != "signed-assertion" || audience != "/projects/123/apps/test-project" {
+			return nil, errors.New("idtoken: invalid token")
+		}
+		return payload, err
+	}
+	return verifier
+}
+
+func TestRequireIdentity_IAP(t *testing.T) {
+	iapPayload := &idtoken.Payload{
+		Issuer:  "https://cloud.google.com/iap",
+		Subject: "accounts.google.com:1234",
+		Claims:  map[string]interface{}{"email": "alice@example.com"},
+	}
+
+	tests := []struct {
+		name      string
+		assertion string
+		verifier  *IAPVerifier
+		expected  int
+		principal string
+	}{
+		{"valid assertion", "signed-assertion", stubIAPVerifier(iapPayload, nil), http.StatusOK, "iap:alice@example.com"},
+		{"missing header", "", stubIAPVerifier(iapPayload, nil), http.StatusUnauthorized, ""},
+		{"invalid assertion", "forged", stubIAPVerifier(iapPayload, nil), http.StatusUnauthorized, ""},
+		{"service account token", "signed-assertion", stubIAPVerifier(&idtoken.Payload{Issuer: "https://accounts.google.com"}, nil), http.StatusUnauthorized, ""},
+		{"without email", "signed-assertion", stubIAPVerifier(&idtoken.Payload{Issuer: "https://cloud.google.com/iap", Subject: "accounts.google.com:1234"}, nil), http.StatusOK, "iap:accounts.google.com:1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal string
+			handler := RequireIdentity(tt.verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal = TokenIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("POST", "/process/hatena", nil)
+			if tt.assertion != "" {
+				req.Header.Set(IAPAssertionHeader, tt.assertion)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+			if principal != tt.principal {
+				t.Errorf("Expected principal %q, got %q", tt.principal, principal)
+			}
+		})
+	}
+}
+
+// principalVerifier authenticates every request as the principal in its X-Principal header
+type principalVerifier struct{}
+
+func (principalVerifier) Verify(r *http.Request) (string, error) {
+	if principal := r.Header.Get("X-Principal"); principal != "" {
+		return principal, nil
+	}
+	return "", errors.New("no principal")
+}
+
+func TestRequireIdentityScope(t *testing.T) {
+	identities, err := ParseTokenScopes("iap:admin@example.com:admin+read,mtls:ci-runner:process")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		principal string
+		scope     Scope
+		expected  int
+	}{
+		{"listed principal with scope", "iap:admin@example.com", ScopeAdmin, http.StatusOK},
+		{"listed principal without scope", "mtls:ci-runner", ScopeWebhook, http.StatusForbidden},
+		{"unlisted principal default scope", "iap:bob@example.com", ScopeWebhook, http.StatusOK},
+		{"unlisted principal admin", "iap:bob@example.com", ScopeAdmin, http.StatusForbidden},
+		{"no identity", "", ScopeRead, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireIdentity(principalVerifier{})(RequireIdentityScope(identities, tt.scope)(http.HandlerFunc(mockHandler)))
+
+			req := httptest.NewRequest("DELETE", "/admin/processed", nil)
+			if tt.principal != "" {
+				req.Header.Set("X-Principal", tt.principal)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestClientCertVerifier(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "ops-runner"}}
+
+	req := httptest.NewRequest("POST", "/process/hatena", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	principal, err := ClientCertVerifier{}.Verify(req)
+	if err != nil || principal != "mtls:ops-runner" {
+		t.Errorf("Expected mtls:ops-runner, got %q (%v)", principal, err)
+	}
+
+	// Presented but unverified certificates are not enough
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if _, err := (ClientCertVerifier{}).Verify(req); err == nil {
+		t.Error("Expected error without a verified chain")
+	}
+
+	if _, err := (ClientCertVerifier{}).Verify(httptest.NewRequest("POST", "/process/hatena", nil)); err == nil {
+		t.Error("Expected error for a plain HTTP request")
+	}
+}
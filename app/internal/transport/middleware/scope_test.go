@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// mockHandler is a simple handler for testing
+func mockHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("success"))
+}
+
+func TestParseTokenScopes(t *testing.T) {
+	tokens, err := ParseTokenScopes("slack-token:webhook, ops-token:process+admin,abc/def=:read")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if scopes, ok := tokens.lookup("slack-token"); !ok || len(scopes) != 1 || scopes[0] != ScopeWebhook {
+		t.Errorf("Unexpected scopes for slack-token: %v", scopes)
+	}
+	if scopes, ok := tokens.lookup("ops-token"); !ok || !hasScope(scopes, ScopeAdmin) || !hasScope(scopes, ScopeProcess) {
+		t.Errorf("Unexpected scopes for ops-token: %v", scopes)
+	}
+	if _, ok := tokens.lookup("abc/def="); !ok {
+		t.Error("Expected base64-style token to be parsed")
+	}
+
+	for _, spec := range []string{"token", "token:", "token:delete"} {
+		if _, err := ParseTokenScopes(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestRequireScope_TokenIDInContext(t *testing.T) {
+	tokens := TokenScopes{}
+	tokens.Grant("admin-token", ScopeAdmin)
+
+	var tokenID string
+	handler := RequireScope(tokens, ScopeAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenID = TokenIDFromContext(r.Context())
+	}))
+	req := httptest.NewRequest("DELETE", "/admin/processed", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if tokenID != TokenID("admin-token") || tokenID == "admin-token" {
+		t.Errorf("Expected hashed token ID in context, got %q", tokenID)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tokens := TokenScopes{}
+	tokens.Grant("slack-token", ScopeWebhook)
+	tokens.Grant("admin-token", AllScopes...)
+
+	tests := []struct {
+		name     string
+		header   string
+		scope    Scope
+		expected int
+	}{
+		{"scope granted", "Bearer slack-token", ScopeWebhook, http.StatusOK},
+		{"scope not granted", "Bearer slack-token", ScopeAdmin, http.StatusForbidden},
+		{"admin token", "Bearer admin-token", ScopeAdmin, http.StatusOK},
+		{"unknown token", "Bearer other", ScopeWebhook, http.StatusUnauthorized},
+		{"token prefix is not enough", "Bearer slack-token-extra", ScopeWebhook, http.StatusUnauthorized},
+		{"missing header", "", ScopeRead, http.StatusUnauthorized},
+		{"empty bearer", "Bearer ", ScopeRead, http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := RequireScope(tokens, test.scope)(http.HandlerFunc(mockHandler))
+			req := httptest.NewRequest("POST", "/test", nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != test.expected {
+				t.Errorf("Expected status %d, got %d", test.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireScopeKey(t *testing.T) {
+	tokens := TokenScopes{}
+	tokens.Grant("zapier-token", ScopeWebhook)
+	tokens.Grant("read-token", ScopeRead)
+
+	var tokenID string
+	handler := RequireScopeKey(tokens, ScopeWebhook, "key")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenID = TokenIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		expected int
+	}{
+		{"query key", httptest.NewRequest("GET", "/hooks/simple?url=x&key=zapier-token", nil), http.StatusOK},
+		{"missing key", httptest.NewRequest("GET", "/hooks/simple?url=x", nil), http.StatusUnauthorized},
+		{"unknown key", httptest.NewRequest("GET", "/hooks/simple?key=other", nil), http.StatusUnauthorized},
+		{"scope missing", httptest.NewRequest("GET", "/hooks/simple?key=read-token", nil), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.req)
+			if w.Code != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, w.Code)
+			}
+		})
+	}
+
+	form := httptest.NewRequest("POST", "/hooks/simple", strings.NewReader("url=x&key=zapier-token"))
+	form.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, form)
+	if w.Code != http.StatusOK || tokenID != TokenID("zapier-token") {
+		t.Errorf("Expected the form key to authenticate, got status %d token ID %q", w.Code, tokenID)
+	}
+}
@@ -47,6 +47,15 @@ func WriteInternalError(w http.ResponseWriter, message string) error {
 	return WriteError(w, http.StatusInternalServerError, message)
 }
 
+// WriteInternalErrorWithData writes a 500 Internal Server Error that still carries data (e.g. the run report of a failed run)
+func WriteInternalErrorWithData(w http.ResponseWriter, message string, data interface{}) error {
+	return WriteJSON(w, http.StatusInternalServerError, Response{
+		Status: "error",
+		Error:  message,
+		Data:   data,
+	})
+}
+
 // WriteMethodNotAllowed writes a 405 Method Not Allowed error
 func WriteMethodNotAllowed(w http.ResponseWriter, message string) error {
 	return WriteError(w, http.StatusMethodNotAllowed, message)
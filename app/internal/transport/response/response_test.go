@@ -154,3 +154,33 @@ func TestWriteInternalError(t *testing.T) {
 		t.Errorf("Expected error 'internal server error', got '%s'", result.Error)
 	}
 }
+
+func TestWriteInternalErrorWithData(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := WriteInternalErrorWithData(w, "run failed", map[string]string{"status": "partial"})
+	if err != nil {
+		t.Fatalf("WriteInternalErrorWithData failed: %v", err)
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	var result struct {
+		Status string            `json:"status"`
+		Error  string            `json:"error"`
+		Data   map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if result.Status != "error" || result.Error != "run failed" {
+		t.Errorf("Expected error 'run failed', got %s '%s'", result.Status, result.Error)
+	}
+
+	if result.Data["status"] != "partial" {
+		t.Errorf("Expected data to be kept, got %v", result.Data)
+	}
+}
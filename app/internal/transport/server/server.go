@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/pep299/article-summarizer-v3/internal/application"
+	"github.com/pep299/article-summarizer-v3/internal/transport/handler"
 	"github.com/pep299/article-summarizer-v3/internal/transport/middleware"
 )
 
@@ -18,26 +21,180 @@ func CreateHandler() (http.Handler, func(), error) {
 		return nil, nil, err
 	}
 
-	// Create auth middleware
-	authMiddleware := middleware.Auth(app.Config.WebhookAuthToken)
+	if err := app.Config.ValidateServerAuth(); err != nil {
+		log.Printf("Error creating application: %v", err)
+		app.Close()
+		return nil, nil, err
+	}
+
+	// Create scoped auth middleware (validated in config); the legacy token keeps every scope
+	tokens, err := middleware.ParseTokenScopes(app.Config.AuthTokens)
+	if err != nil {
+		app.Close()
+		return nil, nil, err
+	}
+	tokens.Grant(app.Config.WebhookAuthToken, middleware.AllScopes...)
+	requireScope := func(scope middleware.Scope) func(http.Handler) http.Handler {
+		return middleware.RequireScope(tokens, scope)
+	}
+	// No-code tools (Zapier, IFTTT) pass the token as a "key" parameter instead of a header
+	requireKey := func(scope middleware.Scope) func(http.Handler) http.Handler {
+		return middleware.RequireScopeKey(tokens, scope, "key")
+	}
+	// Identity auth (IAP / mTLS) replaces bearer tokens: verified callers get the scopes IDENTITY_SCOPES
+	// grants their principal, or every scope but admin when it does not list them
+	verifier := identityVerifier(app.Config)
+	if verifier != nil {
+		identities, err := middleware.ParseTokenScopes(app.Config.IdentityScopes)
+		if err != nil {
+			app.Close()
+			return nil, nil, err
+		}
+		requireScope = func(scope middleware.Scope) func(http.Handler) http.Handler {
+			return middleware.RequireIdentityScope(identities, scope)
+		}
+		requireKey = requireScope
+	}
+
+	// Read API responses are cached per endpoint for polling clients (READ_CACHE_TTL_SECONDS)
+	cached := func(pattern string, next http.Handler) http.Handler {
+		if app.Config.ReadCacheTTL <= 0 {
+			return next
+		}
+		return middleware.CacheResponses(readCache(pattern, app.Config.ReadCacheTTL, app.Config.ReadCacheEntries))(next)
+	}
 
 	// Setup routes (pure HTTP routing)
-	mux := http.NewServeMux()
-	// Feed-specific endpoints
-	mux.Handle("POST /process/hatena", authMiddleware(app.HatenaHandler))
-	mux.Handle("POST /process/reddit", authMiddleware(app.RedditHandler))
-	mux.Handle("POST /process/lobsters", authMiddleware(app.LobstersHandler))
-	mux.Handle("POST /webhook", authMiddleware(app.WebhookHandler))
-	mux.Handle("GET /x", authMiddleware(app.XHandler))                       // X fetch endpoint (auth required)
-	mux.Handle("GET /x/quote-chain", authMiddleware(app.XQuoteChainHandler)) // X quote chain endpoint (auth required)
-	mux.HandleFunc("GET /hc", healthCheck)                                   // Health check endpoint
+	mux, public := newRouter(verifier)
+	// Read APIs (available in every mode)
+	mux.HandleFunc("GET /hc", healthCheck) // Health check endpoint
+	// History lists on-demand and private-document titles, so it needs the read scope like the other read APIs
+	mux.Handle("GET /history", requireScope(middleware.ScopeRead)(cached("GET /history", app.HistoryHandler))) // Processed article history search
+	if app.ProvidersHandler != nil {
+		mux.Handle("GET /api/v1/providers", cached("GET /api/v1/providers", app.ProvidersHandler)) // Summarizer provider health for status pages
+	}
+	if app.FeedHealthHandler != nil {
+		mux.Handle("GET /api/v1/feeds", requireScope(middleware.ScopeRead)(cached("GET /api/v1/feeds", app.FeedHealthHandler))) // Feed run health and owners
+	}
+	if app.SummaryFeedHandler != nil {
+		mux.Handle("GET /feed.xml", cached("GET /feed.xml", app.SummaryFeedHandler)) // RSS feed of the latest summaries
+	}
+	if app.SummaryExport != nil {
+		mux.Handle("GET /api/v1/summaries/export", requireScope(middleware.ScopeRead)(app.SummaryExport)) // NDJSON/CSV export of archived summaries
+	}
+	if app.SummaryArchive != nil {
+		mux.Handle("GET /api/v1/summaries/archive", requireScope(middleware.ScopeRead)(cached("GET /api/v1/summaries/archive", app.SummaryArchive))) // Full summary of one article (?url=)
+	}
+
+	// Processing endpoints are not registered on read-only instances
+	if !app.ReadOnly() {
+		// Feed-specific endpoints (scheduled runs outside ACTIVE_WINDOW_<FEED> are skipped, ?reprocess bypasses the processed index)
+		mux.Handle("POST /process/hatena", requireScope(middleware.ScopeProcess)(app.Scheduled("hatena", app.HatenaHandler)))
+		mux.Handle("POST /process/reddit", requireScope(middleware.ScopeProcess)(app.Scheduled("reddit", app.RedditHandler)))
+		mux.Handle("POST /process/lobsters", requireScope(middleware.ScopeProcess)(app.Scheduled("lobsters", app.LobstersHandler)))
+	}
+
+	// Everything else reaches external systems, so simulation instances only run the feeds
+	if !app.ReadOnly() && !app.Simulation() {
+		// Hacker News has no fixture feed: it always reaches the HN API
+		mux.Handle("POST /process/hackernews", requireScope(middleware.ScopeProcess)(app.Scheduled("hackernews", app.HackerNewsHandler)))
+		// arXiv papers come from the arXiv API, which has no fixture either
+		mux.Handle("POST /process/arxiv", requireScope(middleware.ScopeProcess)(app.Scheduled("arxiv", app.ArxivHandler)))
+		// Developer communities are read from their JSON APIs
+		mux.Handle("POST /process/devto", requireScope(middleware.ScopeProcess)(app.Scheduled("devto", app.DevToHandler)))
+		mux.Handle("POST /process/qiita", requireScope(middleware.ScopeProcess)(app.Scheduled("qiita", app.QiitaHandler)))
+		mux.Handle("POST /process/zenn", requireScope(middleware.ScopeProcess)(app.Scheduled("zenn", app.ZennHandler)))
+		mux.Handle("POST /process/producthunt", requireScope(middleware.ScopeProcess)(app.Scheduled("producthunt", app.ProductHuntHandler)))
+		if app.YouTubeHandler != nil {
+			mux.Handle("POST /process/youtube", requireScope(middleware.ScopeProcess)(app.Scheduled("youtube", app.YouTubeHandler))) // Channels in YOUTUBE_CHANNEL_IDS
+		}
+		if app.XAccountsHandler != nil {
+			mux.Handle("POST /process/x", requireScope(middleware.ScopeProcess)(app.Scheduled("x", app.XAccountsHandler))) // Accounts in X_ACCOUNTS
+		}
+		if app.PodcastHandler != nil {
+			mux.Handle("POST /process/podcast", requireScope(middleware.ScopeProcess)(app.Scheduled("podcast", app.PodcastHandler))) // Feeds in PODCAST_FEEDS
+		}
+		if app.OPMLHandler != nil {
+			mux.Handle("POST /process/opml", requireScope(middleware.ScopeProcess)(app.Scheduled("opml", app.OPMLHandler))) // Feeds listed in OPML_SOURCE
+		}
+		if app.FeedsHandler != nil {
+			// Feeds defined in GENERIC_FEEDS: all due feeds, or one feed on demand
+			mux.Handle("POST /process/feeds", requireScope(middleware.ScopeProcess)(handler.NewReprocess(app.LeaderOnly("feeds", app.FeedsHandler))))
+			mux.Handle("POST /process/feeds/{name}", requireScope(middleware.ScopeProcess)(handler.NewReprocess(http.HandlerFunc(app.FeedsHandler.ServeFeed))))
+		}
+		mux.Handle("POST /process/backlog", requireScope(middleware.ScopeProcess)(app.LeaderOnly("backlog", app.BacklogHandler))) // Off-peak backlog drain
+		mux.Handle("POST /webhook", requireScope(middleware.ScopeWebhook)(app.WebhookHandler))
+		mux.Handle("POST /webhook/stream", requireScope(middleware.ScopeWebhook)(app.WebhookStream)) // On-demand with SSE progress
+		mux.Handle("GET /hooks/simple", requireKey(middleware.ScopeWebhook)(app.SimpleHook))         // On-demand for no-code tools (plain text)
+		mux.Handle("POST /hooks/simple", requireKey(middleware.ScopeWebhook)(app.SimpleHook))
+		mux.Handle("GET /x", requireScope(middleware.ScopeRead)(app.XHandler))                       // X fetch endpoint (auth required)
+		mux.Handle("GET /x/quote-chain", requireScope(middleware.ScopeRead)(app.XQuoteChainHandler)) // X quote chain endpoint (auth required)
+		// Admin endpoints
+		mux.Handle("DELETE /admin/processed", requireScope(middleware.ScopeAdmin)(app.AdminProcessed)) // Re-enable summarization of an article
+		mux.Handle("POST /admin/processed", requireScope(middleware.ScopeAdmin)(app.AdminImport))      // Bulk-mark URLs as processed (migrations)
+		mux.Handle("POST /admin/processed/prune", requireScope(middleware.ScopeAdmin)(app.AdminPrune)) // Drop entries older than the retention window
+		mux.Handle("GET /admin/audit", requireScope(middleware.ScopeAdmin)(app.AdminAudit))            // Audit log of admin actions
+		mux.Handle("GET /admin/usage", requireScope(middleware.ScopeAdmin)(app.AdminUsage))            // Per-user on-demand consumption
+		mux.Handle("GET /admin/processed/stats", requireScope(middleware.ScopeAdmin)(app.AdminStats))  // Processed index usage since startup
+		// Slack slash commands authenticate with the signing secret, not a bearer token or identity
+		if app.SlackCommand != nil {
+			public.Handle("POST /slack/commands", app.SlackCommand)
+		}
+		if app.SlackInteraction != nil {
+			public.Handle("POST /slack/interactions", app.SlackInteraction) // Summary button callbacks
+		}
+	}
 
 	// Return handler and cleanup function
 	cleanup := func() {
 		app.Close()
 	}
 
-	return mux, cleanup, nil
+	return public, cleanup, nil
+}
+
+// newRouter returns the mux for authenticated routes and the one serving it, on which routes that
+// authenticate on their own (health check, Slack callbacks) are registered. With identity auth the
+// former sits behind RequireIdentity and the latter stays outside it; otherwise they are the same mux.
+func newRouter(verifier middleware.IdentityVerifier) (mux, public *http.ServeMux) {
+	mux = http.NewServeMux()
+	if verifier == nil {
+		return mux, mux
+	}
+	public = http.NewServeMux()
+	public.HandleFunc("GET /hc", healthCheck) // Load balancer health checks carry no identity
+	public.Handle("/", middleware.RequireIdentity(verifier)(mux))
+	return mux, public
+}
+
+var (
+	readCachesMu sync.Mutex
+	readCaches   = make(map[string]*middleware.ResponseCache)
+)
+
+// readCache returns the response cache of a read endpoint, shared by every request of the process:
+// HandleRequest builds the handler for each request, so a cache created with it would always be empty
+func readCache(pattern string, ttl time.Duration, maxEntries int) *middleware.ResponseCache {
+	readCachesMu.Lock()
+	defer readCachesMu.Unlock()
+
+	cache, exists := readCaches[pattern]
+	if !exists {
+		cache = middleware.NewResponseCache(ttl, maxEntries)
+		readCaches[pattern] = cache
+	}
+	return cache
+}
+
+// identityVerifier returns the verifier for the configured auth mode (nil for bearer tokens)
+func identityVerifier(cfg *application.Config) middleware.IdentityVerifier {
+	switch cfg.AuthMode {
+	case application.AuthModeIAP:
+		return middleware.NewIAPVerifier(cfg.IAPAudience)
+	case application.AuthModeMTLS:
+		return middleware.ClientCertVerifier{}
+	}
+	return nil
 }
 
 // HandleRequest handles a single HTTP request (for Cloud Functions)
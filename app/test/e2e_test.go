@@ -93,10 +93,10 @@ func createTestApplication() (*application.Application, *handler.HatenaHandler,
 	// Create services with test limiter
 	testLimiter := limiter.NewTestArticleLimiter()
 
-	// Create handlers
-	hatenaHandler := handler.NewHatenaHandler(rssRepo, geminiRepo, slackRepo, processedRepo, testLimiter)
-	redditHandler := handler.NewRedditHandler(rssRepo, geminiRepo, slackRepo, processedRepo, testLimiter)
-	lobstersHandler := handler.NewLobstersHandler(rssRepo, geminiRepo, slackRepo, processedRepo, testLimiter)
+	// Create handlers (no backlog, sequential processing in E2E)
+	hatenaHandler := handler.NewHatenaHandler(rssRepo, geminiRepo, slackRepo, processedRepo, nil, nil, testLimiter, nil)
+	redditHandler := handler.NewRedditHandler(rssRepo, geminiRepo, slackRepo, processedRepo, nil, nil, testLimiter, nil)
+	lobstersHandler := handler.NewLobstersHandler(rssRepo, geminiRepo, slackRepo, processedRepo, nil, nil, testLimiter, nil)
 
 	// Create mock application for cleanup
 	app := &application.Application{
@@ -0,0 +1,57 @@
+package golden
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pep299/article-summarizer-v3/internal/application"
+)
+
+// newGoldenApp wires the application as deployed, with fixture feeds, local storage and one
+// article at a time so the payloads come out in a stable order
+func newGoldenApp(t *testing.T) *application.Application {
+	t.Helper()
+	t.Setenv("GEMINI_API_KEY", "golden-key")
+	t.Setenv("SLACK_BOT_TOKEN", "xoxb-golden")
+	t.Setenv("SLACK_CHANNEL", "#golden")
+	t.Setenv("STORAGE_DRIVER", "local")
+	t.Setenv("STORAGE_DIR", t.TempDir())
+	t.Setenv("ARTICLE_CONCURRENCY_MIN", "1")
+	t.Setenv("ARTICLE_CONCURRENCY_MAX", "1")
+	t.Setenv("LOBSTERS_RSS_URL", "fixture://lobsters.rss")
+	t.Setenv("REDDIT_RSS_URL", "fixture://reddit.atom")
+
+	app, err := application.New()
+	if err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+	t.Cleanup(func() { app.Close() })
+	return app
+}
+
+func TestGolden_Feeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(app *application.Application) http.Handler
+	}{
+		{name: "lobsters", handler: func(app *application.Application) http.Handler { return app.LobstersHandler }},
+		{name: "reddit", handler: func(app *application.Application) http.Handler { return app.RedditHandler }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := installFakeProviders(t)
+			app := newGoldenApp(t)
+
+			req := httptest.NewRequest("POST", "/process/"+tt.name, nil)
+			w := httptest.NewRecorder()
+			tt.handler(app).ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			assertGolden(t, tt.name, fake.calls)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package golden
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// go test ./test/golden -update rewrites the golden files from the current output
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// slackCall is one Slack Web API request as snapshotted
+type slackCall struct {
+	Method  string         `json:"method"`
+	Payload map[string]any `json:"payload"`
+}
+
+// fakeProviders answers every outbound HTTP request in process: Slack records the payloads,
+// Gemini returns a summary derived from the prompt (so a changed prompt changes the snapshot),
+// Lobsters story JSON returns fixed comments, and any other URL is served an article page
+type fakeProviders struct {
+	mu    sync.Mutex
+	calls []slackCall
+}
+
+func (f *fakeProviders) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+
+	switch {
+	case req.URL.Host == "slack.com":
+		return f.slack(req, body)
+	case req.URL.Host == "generativelanguage.googleapis.com":
+		return f.gemini(body)
+	case strings.HasSuffix(req.URL.Path, ".json"):
+		return jsonResponse(map[string]any{
+			"title": "story",
+			"comments": []map[string]any{
+				{"comment": "This release fixes the loop variable gotcha for good.", "user": "alice", "score": 12,
+					"replies": []map[string]any{{"comment": "Range over func is the bigger change for library authors.", "user": "bob", "score": 7}}},
+				{"comment": "The telemetry opt-in discussion was handled well.", "user": "carol", "score": 5},
+			},
+		}), nil
+	default:
+		return articlePage(req.URL.String()), nil
+	}
+}
+
+func (f *fakeProviders) slack(req *http.Request, body []byte) (*http.Response, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("decoding Slack payload: %w", err)
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, slackCall{Method: strings.TrimPrefix(req.URL.Path, "/api/"), Payload: payload})
+	ts := fmt.Sprintf("1700000000.%06d", len(f.calls))
+	f.mu.Unlock()
+
+	return jsonResponse(map[string]any{"ok": true, "channel": "C0GOLDEN", "ts": ts}), nil
+}
+
+func (f *fakeProviders) gemini(body []byte) (*http.Response, error) {
+	var request struct {
+		Contents []struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil || len(request.Contents) == 0 || len(request.Contents[0].Parts) == 0 {
+		return nil, fmt.Errorf("decoding Gemini request: %v", err)
+	}
+	prompt := request.Contents[0].Parts[0].Text
+	sum := sha256.Sum256([]byte(prompt))
+
+	return jsonResponse(map[string]any{
+		"candidates": []map[string]any{{
+			"content": map[string]any{
+				"role":  "model",
+				"parts": []map[string]any{{"text": fmt.Sprintf("・これはゴールデンテスト用の偽の要約で、実際の記事の内容は要約していません\n・プロンプトの指紋 %s（%d文字）から生成しています", hex.EncodeToString(sum[:6]), len([]rune(prompt)))}},
+			},
+		}},
+	}), nil
+}
+
+func jsonResponse(v any) *http.Response {
+	data, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}
+}
+
+func articlePage(url string) *http.Response {
+	paragraph := "This article explains the change in detail, with examples and the reasoning behind it. "
+	html := fmt.Sprintf("<html><head><title>Article at %s</title></head><body><article><h1>Article at %s</h1><p>%s</p><p>%s</p></article></body></html>",
+		url, url, strings.Repeat(paragraph, 8), strings.Repeat(paragraph, 8))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(html)),
+	}
+}
+
+// installFakeProviders routes the default transport (used by every repository's client) to the
+// fakes for the rest of the test
+func installFakeProviders(t *testing.T) *fakeProviders {
+	t.Helper()
+	fake := &fakeProviders{}
+	original := http.DefaultTransport
+	http.DefaultTransport = fake
+	t.Cleanup(func() { http.DefaultTransport = original })
+	return fake
+}
+
+// timestampPattern matches the processing times messages carry, which differ on every run
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}:\d{2}`)
+
+// assertGolden compares the recorded Slack calls (timestamps masked) with testdata/<name>.golden.json
+func assertGolden(t *testing.T, name string, calls []slackCall) {
+	t.Helper()
+	got, err := json.MarshalIndent(calls, "", "  ")
+	if err != nil {
+		t.Fatalf("Marshaling Slack calls: %v", err)
+	}
+	got = append(timestampPattern.ReplaceAll(got, []byte("<timestamp>")), '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("Writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Reading golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Slack payloads differ from %s (run make test-golden-update to accept):\n%s", path, got)
+	}
+}